@@ -147,7 +147,7 @@ func testVersionUpgrade(ctx context.Context, t *testing.T, cfg cluster.TestConfi
 		}
 	}()
 
-	bumps := []string{"1.0", "1.0-1", "1.0-3"}
+	bumps := []string{"1.0", "1.0-1", "1.0-3", "1.0-4", "1.0-5", "1.0-6"}
 
 	for i, bump := range bumps {
 		func() {
@@ -457,6 +457,10 @@ func convertRecord(
 	if err != nil {
 		return errors.Wrap(err, "process default columns")
 	}
+	computedCols, err := sqlbase.MakeComputedColumns(tableDesc, cols)
+	if err != nil {
+		return errors.Wrap(err, "process computed columns")
+	}
 
 	datums := make([]parser.Datum, len(visibleCols))
 	kvBatch := make([]roachpb.KeyValue, 0, kvBatchSize+padding)
@@ -479,7 +483,7 @@ func convertRecord(
 				}
 			}
 
-			row, err := sql.GenerateInsertRow(defaultExprs, ri.InsertColIDtoRowIndex, cols, evalCtx, tableDesc, datums)
+			row, err := sql.GenerateInsertRow(defaultExprs, computedCols, ri.InsertColIDtoRowIndex, cols, evalCtx, tableDesc, datums)
 			if err != nil {
 				return errors.Wrapf(err, "generate insert row: %s: row %d", batch.file, rowNum)
 			}
@@ -96,6 +96,7 @@ func Load(
 	scanner := bufio.NewReader(r)
 	var ri sqlbase.RowInserter
 	var defaultExprs []parser.TypedExpr
+	var computedCols *sqlbase.ComputedColumns
 	var cols []sqlbase.ColumnDescriptor
 	var tableDesc *sqlbase.TableDescriptor
 	var tableName string
@@ -171,6 +172,10 @@ func Load(
 			if err != nil {
 				return BackupDescriptor{}, errors.Wrap(err, "process default columns")
 			}
+			computedCols, err = sqlbase.MakeComputedColumns(tableDesc, cols)
+			if err != nil {
+				return BackupDescriptor{}, errors.Wrap(err, "process computed columns")
+			}
 
 		case *parser.Insert:
 			name := parser.AsString(s.Table)
@@ -181,7 +186,7 @@ func Load(
 				return BackupDescriptor{}, errors.Errorf("unexpected INSERT for table %s after CREATE TABLE %s", name, tableName)
 			}
 			outOfOrder := false
-			err := insertStmtToKVs(ctx, tableDesc, defaultExprs, cols, evalCtx, ri, s, func(kv roachpb.KeyValue) {
+			err := insertStmtToKVs(ctx, tableDesc, defaultExprs, computedCols, cols, evalCtx, ri, s, func(kv roachpb.KeyValue) {
 				if outOfOrder || prevKey.Compare(kv.Key) >= 0 {
 					outOfOrder = true
 					return
@@ -234,6 +239,7 @@ func insertStmtToKVs(
 	ctx context.Context,
 	tableDesc *sqlbase.TableDescriptor,
 	defaultExprs []parser.TypedExpr,
+	computedCols *sqlbase.ComputedColumns,
 	cols []sqlbase.ColumnDescriptor,
 	evalCtx parser.EvalContext,
 	ri sqlbase.RowInserter,
@@ -286,7 +292,7 @@ func insertStmtToKVs(
 			}
 		}
 		row, err := sql.GenerateInsertRow(
-			defaultExprs, ri.InsertColIDtoRowIndex, cols, evalCtx, tableDesc, row,
+			defaultExprs, computedCols, ri.InsertColIDtoRowIndex, cols, evalCtx, tableDesc, row,
 		)
 		if err != nil {
 			return errors.Wrapf(err, "process insert %q", row)
@@ -539,6 +539,7 @@ func Example_zone() {
 	//   ttlseconds: 90000
 	// num_replicas: 1
 	// constraints: [us-east-1a, ssd]
+	// lease_preferences: [[+us-east-1a]]
 	// zone ls
 	// .default
 	// system
@@ -550,6 +551,7 @@ func Example_zone() {
 	//   ttlseconds: 90000
 	// num_replicas: 1
 	// constraints: []
+	// lease_preferences: []
 	// zone get system.nonexistent
 	// system.nonexistent not found
 	// zone get system.lease
@@ -560,6 +562,7 @@ func Example_zone() {
 	//   ttlseconds: 90000
 	// num_replicas: 1
 	// constraints: [us-east-1a, ssd]
+	// lease_preferences: [[+us-east-1a]]
 	// zone set system.lease --file=./testdata/zone_attrs.yaml
 	// setting zone configs for individual system tables is not supported; try setting your config on the entire "system" database instead
 	// zone set system.namespace --file=./testdata/zone_attrs.yaml
@@ -573,6 +576,7 @@ func Example_zone() {
 	//   ttlseconds: 90000
 	// num_replicas: 3
 	// constraints: [us-east-1a, ssd]
+	// lease_preferences: [[+us-east-1a]]
 	// zone get system
 	// system
 	// range_min_bytes: 1048576
@@ -581,6 +585,7 @@ func Example_zone() {
 	//   ttlseconds: 90000
 	// num_replicas: 3
 	// constraints: [us-east-1a, ssd]
+	// lease_preferences: [[+us-east-1a]]
 	// zone rm system
 	// DELETE 1
 	// zone ls
@@ -594,6 +599,7 @@ func Example_zone() {
 	//   ttlseconds: 90000
 	// num_replicas: 3
 	// constraints: []
+	// lease_preferences: []
 	// zone set .system --file=./testdata/zone_range_max_bytes.yaml
 	// range_min_bytes: 1048576
 	// range_max_bytes: 134217728
@@ -601,6 +607,7 @@ func Example_zone() {
 	//   ttlseconds: 90000
 	// num_replicas: 3
 	// constraints: []
+	// lease_preferences: []
 	// zone set .timeseries --file=./testdata/zone_range_max_bytes.yaml
 	// range_min_bytes: 1048576
 	// range_max_bytes: 134217728
@@ -608,6 +615,7 @@ func Example_zone() {
 	//   ttlseconds: 90000
 	// num_replicas: 3
 	// constraints: []
+	// lease_preferences: []
 	// zone get .system
 	// .system
 	// range_min_bytes: 1048576
@@ -616,6 +624,7 @@ func Example_zone() {
 	//   ttlseconds: 90000
 	// num_replicas: 3
 	// constraints: []
+	// lease_preferences: []
 	// zone ls
 	// .default
 	// .meta
@@ -628,6 +637,7 @@ func Example_zone() {
 	//   ttlseconds: 90000
 	// num_replicas: 3
 	// constraints: []
+	// lease_preferences: []
 	// zone get system
 	// .default
 	// range_min_bytes: 1048576
@@ -636,6 +646,7 @@ func Example_zone() {
 	//   ttlseconds: 90000
 	// num_replicas: 3
 	// constraints: []
+	// lease_preferences: []
 	// zone set .default --disable-replication
 	// range_min_bytes: 1048576
 	// range_max_bytes: 134217728
@@ -643,6 +654,7 @@ func Example_zone() {
 	//   ttlseconds: 90000
 	// num_replicas: 1
 	// constraints: []
+	// lease_preferences: []
 	// zone get system
 	// .default
 	// range_min_bytes: 1048576
@@ -651,6 +663,7 @@ func Example_zone() {
 	//   ttlseconds: 90000
 	// num_replicas: 1
 	// constraints: []
+	// lease_preferences: []
 	// zone rm .meta
 	// DELETE 1
 	// zone rm .system
@@ -1818,6 +1831,8 @@ writing ` + os.DevNull + `
   debug/nodes/1/ranges/14
   debug/nodes/1/ranges/15
   debug/nodes/1/ranges/16
+  debug/nodes/1/ranges/17
+  debug/nodes/1/ranges/18
   debug/schema/system@details
   debug/schema/system/descriptor
   debug/schema/system/eventlog
@@ -1825,7 +1840,9 @@ writing ` + os.DevNull + `
   debug/schema/system/lease
   debug/schema/system/namespace
   debug/schema/system/rangelog
+  debug/schema/system/role_members
   debug/schema/system/settings
+  debug/schema/system/table_statistics
   debug/schema/system/ui
   debug/schema/system/users
   debug/schema/system/web_sessions
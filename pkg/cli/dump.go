@@ -430,6 +430,11 @@ func dumpTableData(w io.Writer, conn *sqlConn, clusterTS string, md tableMetadat
 						if err != nil {
 							return err
 						}
+					case "JSONB":
+						d, err = parser.ParseDJSON(string(t))
+						if err != nil {
+							return err
+						}
 					default:
 						// STRING and DECIMAL types can have optional length
 						// suffixes, so only examine the prefix of the type.
@@ -422,6 +422,7 @@ The zone config format has the following YAML schema:
 
   num_replicas: <num>
   constraints: [comma-separated attribute list]
+  lease_preferences: [[comma-separated attribute list], ...]
   range_min_bytes: <size-in-bytes>
   range_max_bytes: <size-in-bytes>
   gc:
@@ -433,6 +434,13 @@ num_replicas: 3
 constraints: [ssd, -mem]
 EOF
 
+Lease preferences are ordered from most to least preferred. Each one lists
+required (+) or prohibited (-) attributes for the store holding the range
+lease. For example, to prefer leases in us-east, falling back to us-west:
+$ cockroach zone set db.t -f - << EOF
+lease_preferences: [[+region=us-east], [+region=us-west]]
+EOF
+
 Note that the specified zone config is merged with the existing zone config for
 the database or table.
 `,
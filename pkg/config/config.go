@@ -136,6 +136,34 @@ func (c *Constraints) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+var _ yaml.Marshaler = LeasePreference{}
+var _ yaml.Unmarshaler = &LeasePreference{}
+
+// MarshalYAML implements yaml.Marshaler.
+func (l LeasePreference) MarshalYAML() (interface{}, error) {
+	short := make([]string, len(l.Constraints))
+	for i, c := range l.Constraints {
+		short[i] = c.String()
+	}
+	return short, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (l *LeasePreference) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var shortConstraints []string
+	if err := unmarshal(&shortConstraints); err != nil {
+		return err
+	}
+	constraints := make([]Constraint, len(shortConstraints))
+	for i, short := range shortConstraints {
+		if err := constraints[i].FromString(short); err != nil {
+			return err
+		}
+	}
+	l.Constraints = constraints
+	return nil
+}
+
 // DefaultZoneConfig is the default zone configuration used when no custom
 // config has been specified.
 func DefaultZoneConfig() ZoneConfig {
@@ -176,6 +204,17 @@ func (z ZoneConfig) Validate() error {
 		return fmt.Errorf("RangeMinBytes %d is greater than or equal to RangeMaxBytes %d",
 			z.RangeMinBytes, z.RangeMaxBytes)
 	}
+	for _, leasePref := range z.LeasePreferences {
+		if len(leasePref.Constraints) == 0 {
+			return fmt.Errorf("every lease preference must include at least one constraint")
+		}
+		for _, constraint := range leasePref.Constraints {
+			if constraint.Type == Constraint_POSITIVE {
+				return fmt.Errorf("lease preference constraints must either be required " +
+					"(e.g. '+foo') or prohibited (e.g. '-foo')")
+			}
+		}
+	}
 	return nil
 }
 
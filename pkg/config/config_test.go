@@ -465,6 +465,35 @@ func TestZoneConfigValidate(t *testing.T) {
 			},
 			"is greater than or equal to RangeMaxBytes",
 		},
+		{
+			config.ZoneConfig{
+				NumReplicas:      1,
+				RangeMaxBytes:    config.DefaultZoneConfig().RangeMaxBytes,
+				LeasePreferences: []config.LeasePreference{{}},
+			},
+			"every lease preference must include at least one constraint",
+		},
+		{
+			config.ZoneConfig{
+				NumReplicas:   1,
+				RangeMaxBytes: config.DefaultZoneConfig().RangeMaxBytes,
+				LeasePreferences: []config.LeasePreference{
+					{Constraints: []config.Constraint{{Type: config.Constraint_POSITIVE, Value: "a"}}},
+				},
+			},
+			"lease preference constraints must either be required",
+		},
+		{
+			config.ZoneConfig{
+				NumReplicas:   1,
+				RangeMaxBytes: config.DefaultZoneConfig().RangeMaxBytes,
+				LeasePreferences: []config.LeasePreference{
+					{Constraints: []config.Constraint{{Type: config.Constraint_REQUIRED, Value: "a"}}},
+					{Constraints: []config.Constraint{{Type: config.Constraint_PROHIBITED, Value: "b"}}},
+				},
+			},
+			"",
+		},
 	}
 	for i, c := range testCases {
 		err := c.cfg.Validate()
@@ -504,6 +533,30 @@ func TestZoneConfigMarshalYAML(t *testing.T) {
 				},
 			},
 		},
+		LeasePreferences: []config.LeasePreference{
+			{
+				Constraints: []config.Constraint{
+					{
+						Type:  config.Constraint_REQUIRED,
+						Key:   "region",
+						Value: "us-east",
+					},
+				},
+			},
+			{
+				Constraints: []config.Constraint{
+					{
+						Type:  config.Constraint_REQUIRED,
+						Key:   "region",
+						Value: "us-west",
+					},
+					{
+						Type:  config.Constraint_PROHIBITED,
+						Value: "hdd",
+					},
+				},
+			},
+		},
 	}
 
 	expected := `range_min_bytes: 1
@@ -512,6 +565,7 @@ gc:
   ttlseconds: 1
 num_replicas: 1
 constraints: [foo, +duck=foo, -duck=foo]
+lease_preferences: [[+region=us-east], [+region=us-west, -hdd]]
 `
 
 	body, err := yaml.Marshal(original)
@@ -330,6 +330,30 @@ func (txn *Txn) IsSerializableRestart() bool {
 	return txn.Proto().Isolation == enginepb.SERIALIZABLE && isTxnPushed
 }
 
+// CreateSavepoint returns a token for the current point in the transaction,
+// which can be passed to RollbackToSavepoint to discard the writes made after
+// it. Savepoints are only valid in the epoch in which they were created.
+func (txn *Txn) CreateSavepoint() int32 {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.Proto.Sequence
+}
+
+// RollbackToSavepoint discards the writes made by the transaction after the
+// savepoint was created. The intents of these writes remain, but they are
+// invisible to the transaction and are removed when it commits.
+func (txn *Txn) RollbackToSavepoint(savepoint int32) {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	if txn.mu.Proto.Sequence <= savepoint {
+		return
+	}
+	txn.mu.Proto.IgnoredSeqNums = append(
+		append([]enginepb.IgnoredSeqNumRange(nil), txn.mu.Proto.IgnoredSeqNums...),
+		enginepb.IgnoredSeqNumRange{Start: savepoint + 1, End: txn.mu.Proto.Sequence},
+	)
+}
+
 // NewBatch creates and returns a new empty batch object for use with the Txn.
 func (txn *Txn) NewBatch() *Batch {
 	return &Batch{txn: txn}
@@ -227,6 +227,9 @@ func (r *RSG) GenerateRandomArg(typ parser.Type) string {
 	case parser.TypeTimestamp, parser.TypeTimestampTZ:
 		t := time.Unix(0, r.Int63())
 		v = fmt.Sprintf(`'%s'`, t.Format(time.RFC3339Nano))
+	case parser.TypeTime, parser.TypeTimeTZ:
+		t := time.Unix(0, r.Int63())
+		v = fmt.Sprintf(`'%s'`, t.UTC().Format("15:04:05.999999"))
 	case parser.TypeBool:
 		v = boolArgs[r.Intn(2)]
 	case parser.TypeDate:
@@ -245,6 +248,8 @@ func (r *RSG) GenerateRandomArg(typ parser.Type) string {
 		ipAddr := ipaddr.RandIPAddr(r.src)
 		r.lock.Unlock()
 		v = fmt.Sprintf(`'%s'`, ipAddr)
+	case parser.TypeJSON:
+		v = jsonArgs[r.Intn(len(jsonArgs))]
 	case parser.TypeOid,
 		parser.TypeRegClass,
 		parser.TypeRegNamespace,
@@ -280,3 +285,11 @@ var boolArgs = map[int]string{
 	0: "false",
 	1: "true",
 }
+
+var jsonArgs = map[int]string{
+	0: `'null'`,
+	1: `'1'`,
+	2: `'"a"'`,
+	3: `'[1, "b", [true, null]]'`,
+	4: `'{"a": {"b": [1, 2]}, "c": "d"}'`,
+}
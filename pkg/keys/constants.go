@@ -283,6 +283,10 @@ const (
 	// RootNamespaceID is the ID of the root namespace.
 	RootNamespaceID = 0
 
+	// SequenceIndexID is the ID of the single index of a sequence, under
+	// which the sequence's value is stored. See MakeSequenceKey.
+	SequenceIndexID = 1
+
 	// SystemDatabaseID and following are the database/table IDs for objects
 	// in the system span.
 	// NOTE: IDs must be <= MaxSystemConfigDescID.
@@ -297,13 +301,15 @@ const (
 	// to "Ranges" instead of a Table - these IDs are needed to store custom
 	// configuration for non-table ranges (e.g. Zone Configs).
 	// NOTE: IDs must be <= MaxReservedDescID.
-	LeaseTableID       = 11
-	EventLogTableID    = 12
-	RangeEventTableID  = 13
-	UITableID          = 14
-	JobsTableID        = 15
-	MetaRangesID       = 16
-	SystemRangesID     = 17
-	TimeseriesRangesID = 18
-	WebSessionsTableID = 19
+	LeaseTableID           = 11
+	EventLogTableID        = 12
+	RangeEventTableID      = 13
+	UITableID              = 14
+	JobsTableID            = 15
+	MetaRangesID           = 16
+	SystemRangesID         = 17
+	TimeseriesRangesID     = 18
+	WebSessionsTableID     = 19
+	TableStatisticsTableID = 20
+	RoleMembersTableID     = 21
 )
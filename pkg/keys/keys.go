@@ -590,6 +590,15 @@ func MakeTablePrefix(tableID uint32) []byte {
 	return encoding.EncodeUvarintAscending(nil, uint64(tableID))
 }
 
+// MakeSequenceKey returns the key used to store the value of a sequence. The
+// key lies within the span of the sequence's table prefix, so that it is
+// removed along with the rest of the sequence's data when it is dropped.
+func MakeSequenceKey(tableID uint32) []byte {
+	key := MakeTablePrefix(tableID)
+	key = encoding.EncodeUvarintAscending(key, SequenceIndexID)
+	return MakeFamilyKey(key, 0)
+}
+
 // DecodeTablePrefix validates that the given key has a table prefix, returning
 // the remainder of the key (with the prefix removed) and the decoded descriptor
 // ID of the table.
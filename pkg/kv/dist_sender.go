@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
 	"github.com/cockroachdb/cockroach/pkg/util/grpcutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -45,6 +46,13 @@ const (
 	defaultRangeLookupMaxRanges = 8
 	// The default limit for asynchronous senders.
 	defaultSenderConcurrency = 500
+	// followerReadLagFactor is the multiple of the closed timestamp target
+	// duration by which a read must trail the present to be sent to the
+	// nearest replica rather than to the leaseholder. Leaseholders close
+	// timestamps in increments, and followers learn about them when they
+	// apply the commands proposed by the leaseholder, so the timestamp closed
+	// on a follower usually trails the present by more than the target.
+	followerReadLagFactor = 2
 )
 
 var (
@@ -434,8 +442,9 @@ func (ds *DistSender) sendSingleRange(
 	replicas.OptimizeReplicaOrder(ds.getNodeDescriptor())
 
 	// If this request needs to go to a lease holder and we know who that is, move
-	// it to the front.
-	if !(ba.IsReadOnly() && ba.ReadConsistency == roachpb.INCONSISTENT) {
+	// it to the front. Reads old enough to be served by any replica are sent to
+	// the nearest one instead.
+	if !(ba.IsReadOnly() && ba.ReadConsistency == roachpb.INCONSISTENT) && !ds.canUseFollowerRead(ba) {
 		if storeID, ok := ds.leaseHolderCache.Lookup(ctx, desc.RangeID); ok {
 			if i := replicas.FindReplica(storeID); i >= 0 {
 				replicas.MoveToFront(i)
@@ -462,6 +471,32 @@ func (ds *DistSender) sendSingleRange(
 	return br, pErr
 }
 
+// canUseFollowerRead returns whether the batch is a consistent read at a
+// timestamp which is likely to have been closed by the leaseholder of the
+// range, in which case any replica can serve it. If the replica the batch is
+// sent to can't serve it after all, it redirects the batch to the leaseholder
+// with a NotLeaseHolderError.
+func (ds *DistSender) canUseFollowerRead(ba roachpb.BatchRequest) bool {
+	if !ba.IsReadOnly() || ba.ReadConsistency != roachpb.CONSISTENT {
+		return false
+	}
+	target := storagebase.ClosedTimestampTarget(ds.st)
+	if target == 0 {
+		return false
+	}
+	ts := ba.Timestamp
+	if ba.Txn != nil {
+		ts.Forward(ba.Txn.Timestamp)
+		ts.Forward(ba.Txn.MaxTimestamp)
+	}
+	if ts == (hlc.Timestamp{}) {
+		return false
+	}
+	threshold := ds.clock.Now()
+	threshold.WallTime -= followerReadLagFactor * target.Nanoseconds()
+	return ts.Less(threshold)
+}
+
 // initAndVerifyBatch initializes timestamp-related information and
 // verifies batch constraints before splitting.
 func (ds *DistSender) initAndVerifyBatch(
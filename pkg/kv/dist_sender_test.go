@@ -33,7 +33,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -200,6 +202,9 @@ func TestSendRPCOrder(t *testing.T) {
 		// Likely a test setup here will never have a read lease, but good
 		// to keep in mind.
 		consistent bool
+		// historical is set for reads far enough in the past to be served by
+		// any replica.
+		historical bool
 	}{
 		// Inconsistent Scan without matching attributes.
 		{
@@ -259,6 +264,26 @@ func TestSendRPCOrder(t *testing.T) {
 			expReplica:  []roachpb.NodeID{1, 2, 3, 4, 5},
 			leaseHolder: 2,
 		},
+		// Consistent Get with matching attributes that finds the lease holder.
+		// Should address the lease holder first.
+		{
+			args:        &roachpb.GetRequest{},
+			attrs:       nodeAttrs[5],
+			expReplica:  []roachpb.NodeID{2, 5, 4, 0, 0},
+			leaseHolder: 2,
+			consistent:  true,
+		},
+		// Consistent historical Get with matching attributes that finds the lease
+		// holder. The read can be served by a follower, so should address the
+		// two nodes matching the attributes first.
+		{
+			args:        &roachpb.GetRequest{},
+			attrs:       nodeAttrs[5],
+			expReplica:  []roachpb.NodeID{5, 4, 0, 0, 0},
+			leaseHolder: 2,
+			consistent:  true,
+			historical:  true,
+		},
 	}
 
 	descriptor := roachpb.RangeDescriptor{
@@ -284,8 +309,14 @@ func TestSendRPCOrder(t *testing.T) {
 		return args.CreateReply(), nil
 	}
 
+	// Enable closed timestamps, so that historical reads can be served by
+	// followers.
+	st := cluster.MakeTestingClusterSettings()
+	storagebase.ClosedTimestampTargetDuration.Override(&st.SV, 30*time.Second)
+
 	cfg := DistSenderConfig{
 		AmbientCtx: log.AmbientContext{Tracer: tracing.NewTracer()},
+		Settings:   st,
 		Clock:      clock,
 		TestingKnobs: DistSenderTestingKnobs{
 			TransportFactory: adaptLegacyTransport(testFn),
@@ -356,11 +387,17 @@ func TestSendRPCOrder(t *testing.T) {
 		if !tc.consistent {
 			consistency = roachpb.INCONSISTENT
 		}
+		var ts hlc.Timestamp
+		if tc.historical {
+			ts = clock.Now()
+			ts.WallTime -= (10 * time.Minute).Nanoseconds()
+		}
 		// Kill the cached NodeDescriptor, enforcing a lookup from Gossip.
 		ds.nodeDescriptor = nil
 		if _, err := client.SendWrappedWith(context.Background(), ds, roachpb.Header{
 			RangeID:         rangeID, // Not used in this test, but why not.
 			ReadConsistency: consistency,
+			Timestamp:       ts,
 		}, args); err != nil {
 			t.Errorf("%d: %s", n, err)
 		}
@@ -740,6 +740,7 @@ func (t Transaction) Clone() Transaction {
 	// Note that we're not cloning the span keys under the assumption that the
 	// keys themselves are not mutable.
 	t.Intents = append([]Span(nil), t.Intents...)
+	t.IgnoredSeqNums = append([]enginepb.IgnoredSeqNumRange(nil), t.IgnoredSeqNums...)
 	return t
 }
 
@@ -859,6 +860,8 @@ func (t *Transaction) Restart(
 	t.WriteTooOld = false
 	t.RetryOnPush = false
 	t.Sequence = 0
+	// The writes of the previous epochs are all ignored.
+	t.IgnoredSeqNums = nil
 }
 
 // BumpEpoch increments the transaction's epoch, allowing for an in-place
@@ -888,6 +891,11 @@ func (t *Transaction) Update(o *Transaction) {
 	}
 	if t.Epoch < o.Epoch {
 		t.Epoch = o.Epoch
+		t.IgnoredSeqNums = o.IgnoredSeqNums
+	} else if t.Epoch == o.Epoch && len(t.IgnoredSeqNums) < len(o.IgnoredSeqNums) {
+		// Ranges of ignored sequence numbers are only ever added during an
+		// epoch, so the longer list is the most recent one.
+		t.IgnoredSeqNums = o.IgnoredSeqNums
 	}
 	t.Timestamp.Forward(o.Timestamp)
 	t.LastHeartbeat.Forward(o.LastHeartbeat)
@@ -24,7 +24,7 @@ var (
 	BinaryMinimumSupportedVersion = VersionBase
 
 	// BinaryServerVersion is the version of this binary.
-	BinaryServerVersion = VersionStatsBasedRebalancing
+	BinaryServerVersion = VersionClosedTimestamps
 )
 
 // List all historical versions here in reverse chronological order, with
@@ -33,6 +33,20 @@ var (
 // NB: when adding a version, don't forget to bump ServerVersion above (and
 // perhaps MinimumSupportedVersion, if necessary).
 var (
+	// VersionClosedTimestamps allows leaseholders to close timestamps and
+	// followers to serve reads below them. Older nodes don't keep the promise
+	// not to accept writes below a closed timestamp.
+	VersionClosedTimestamps = roachpb.Version{Major: 1, Minor: 0, Unstable: 6}
+
+	// VersionSavepoints adds the ignored sequence numbers of a transaction and
+	// the intent history of a key, which savepoints other than the restart
+	// savepoint rely on. Older nodes drop them when resolving intents.
+	VersionSavepoints = roachpb.Version{Major: 1, Minor: 0, Unstable: 5}
+
+	// VersionQPSBasedRebalancing gossips the QPS of each store in its
+	// StoreCapacity, which QPS-based rebalancing relies on.
+	VersionQPSBasedRebalancing = roachpb.Version{Major: 1, Minor: 0, Unstable: 4}
+
 	// VersionStatsBasedRebalancing is https://github.com/cockroachdb/cockroach/pull/16878.
 	VersionStatsBasedRebalancing = roachpb.Version{Major: 1, Minor: 0, Unstable: 3}
 
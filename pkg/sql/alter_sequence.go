@@ -0,0 +1,87 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+type alterSequenceNode struct {
+	n       *parser.AlterSequence
+	seqDesc *sqlbase.TableDescriptor
+}
+
+// AlterSequence changes the options of a sequence.
+// Privileges: CREATE on sequence.
+//   notes: postgres requires ownership of the sequence.
+func (p *planner) AlterSequence(ctx context.Context, n *parser.AlterSequence) (planNode, error) {
+	tn, err := n.Name.NormalizeWithDatabaseName(p.session.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	seqDesc, err := getSequenceDesc(ctx, p.txn, p.getVirtualTabler(), tn)
+	if err != nil {
+		return nil, err
+	}
+	if seqDesc == nil {
+		if n.IfExists {
+			return &zeroNode{}, nil
+		}
+		return nil, sqlbase.NewUndefinedRelationError(tn)
+	}
+
+	if err := p.CheckPrivilege(seqDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &alterSequenceNode{n: n, seqDesc: seqDesc}, nil
+}
+
+func (n *alterSequenceNode) Start(params runParams) error {
+	// The options that are not specified keep their current value.
+	if err := assignSequenceOptions(
+		n.seqDesc.SequenceOpts, n.n.Options, false /* setDefaults */); err != nil {
+		return err
+	}
+
+	if err := params.p.saveNonmutationAndNotify(params.ctx, n.seqDesc); err != nil {
+		return err
+	}
+
+	// Record this sequence alteration in the event log. This is an auditable log
+	// event and is recorded in the same transaction as the table descriptor
+	// update.
+	return MakeEventLogger(params.p.LeaseMgr()).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogAlterSequence,
+		int32(n.seqDesc.ID),
+		int32(params.p.evalCtx.NodeID),
+		struct {
+			SequenceName string
+			Statement    string
+			User         string
+		}{n.seqDesc.Name, n.n.String(), params.p.session.User},
+	)
+}
+
+func (*alterSequenceNode) Next(runParams) (bool, error) { return false, nil }
+func (*alterSequenceNode) Values() parser.Datums        { return parser.Datums{} }
+func (*alterSequenceNode) Close(context.Context)        {}
@@ -83,9 +83,16 @@ func (n *alterTableNode) Start(params runParams) error {
 			if err != nil {
 				return err
 			}
+			if col.IsComputed() {
+				if err := sqlbase.ValidateComputedColumn(
+					n.tableDesc, col, params.p.session.SearchPath,
+				); err != nil {
+					return err
+				}
+			}
 			// We're checking to see if a user is trying add a non-nullable column without a default to a
 			// non empty table by scanning the primary index span with a limit of 1 to see if any key exists.
-			if !col.Nullable && col.DefaultExpr == nil {
+			if !col.Nullable && col.DefaultExpr == nil && !col.IsComputed() {
 				kvs, err := params.p.txn.Scan(params.ctx, n.tableDesc.PrimaryIndexSpan().Key, n.tableDesc.PrimaryIndexSpan().EndKey, 1)
 				if err != nil {
 					return err
@@ -138,7 +145,13 @@ func (n *alterTableNode) Start(params runParams) error {
 					Unique:           true,
 					StoreColumnNames: d.Storing.ToStrings(),
 				}
-				if err := idx.FillColumns(d.Columns); err != nil {
+				elems, exprCols, err := sqlbase.ResolveIndexExprs(
+					n.tableDesc, d.Columns, params.p.session.SearchPath,
+				)
+				if err != nil {
+					return err
+				}
+				if err := idx.FillColumns(elems); err != nil {
 					return err
 				}
 				_, dropped, err := n.tableDesc.FindIndexByName(string(d.Name))
@@ -147,6 +160,9 @@ func (n *alterTableNode) Start(params runParams) error {
 						return fmt.Errorf("index %q being dropped, try again later", d.Name)
 					}
 				}
+				for _, col := range exprCols {
+					n.tableDesc.AddColumnMutation(col, sqlbase.DescriptorMutation_ADD)
+				}
 				if err := n.tableDesc.AddIndexMutation(idx, sqlbase.DescriptorMutation_ADD); err != nil {
 					return err
 				}
@@ -253,8 +269,14 @@ func (n *alterTableNode) Start(params runParams) error {
 				containsOnlyThisColumn := true
 
 				// Analyze the index.
-				for _, id := range idx.ColumnIDs {
-					if id == col.ID {
+				for i, id := range idx.ColumnIDs {
+					// The expressions of an expression index are stored in
+					// hidden columns, which are dropped along with the index.
+					refers, err := sqlbase.IndexExprRefersTo(n.tableDesc, &idx, i, col)
+					if err != nil {
+						return err
+					}
+					if id == col.ID || refers {
 						containsThisColumn = true
 					} else {
 						containsOnlyThisColumn = false
@@ -281,6 +303,13 @@ func (n *alterTableNode) Start(params runParams) error {
 						containsThisColumn = true
 					}
 				}
+				// The predicate of a partial index is stored in a hidden
+				// column as well.
+				if refers, err := sqlbase.IndexPredicateRefersTo(n.tableDesc, &idx, col); err != nil {
+					return err
+				} else if refers {
+					containsThisColumn = true
+				}
 
 				// Perform the DROP.
 				if containsThisColumn {
@@ -296,6 +325,16 @@ func (n *alterTableNode) Start(params runParams) error {
 					}
 				}
 			}
+			if computed, err := sqlbase.ComputedColumnReferencing(n.tableDesc, col); err != nil {
+				return err
+			} else if computed != nil {
+				return fmt.Errorf("column %q is referenced by computed column %q", col.Name, computed.Name)
+			}
+			if policy, err := sqlbase.PolicyReferencing(n.tableDesc, col); err != nil {
+				return err
+			} else if policy != nil {
+				return fmt.Errorf("column %q is referenced by policy %q", col.Name, policy.Name)
+			}
 			found := false
 			for i := range n.tableDesc.Columns {
 				if n.tableDesc.Columns[i].ID == col.ID {
@@ -412,6 +451,22 @@ func (n *alterTableNode) Start(params runParams) error {
 				return errors.Errorf("validating %s constraint %q unsupported", constraint.Kind, t.Constraint)
 			}
 
+		case *parser.AlterTableAlterColumnType:
+			col, dropped, err := n.tableDesc.FindColumnByName(t.Column)
+			if err != nil {
+				return err
+			}
+			if dropped {
+				return fmt.Errorf("column %q in the middle of being dropped", t.Column)
+			}
+			changed, err := alterColumnType(
+				n.tableDesc, col, t.ToType, params.p.session.SearchPath, &params.p.evalCtx,
+			)
+			if err != nil {
+				return err
+			}
+			descriptorChanged = descriptorChanged || changed
+
 		case parser.ColumnMutationCmd:
 			// Column mutations
 			col, dropped, err := n.tableDesc.FindColumnByName(t.GetColumn())
@@ -429,6 +484,12 @@ func (n *alterTableNode) Start(params runParams) error {
 			n.tableDesc.UpdateColumnDescriptor(col)
 			descriptorChanged = true
 
+		case *parser.AlterTableRowLevelSecurity:
+			if n.tableDesc.RowLevelSecurity != t.Enable {
+				n.tableDesc.RowLevelSecurity = t.Enable
+				descriptorChanged = true
+			}
+
 		default:
 			return fmt.Errorf("unsupported alter cmd: %T", cmd)
 		}
@@ -499,6 +560,10 @@ func applyColumnMutation(
 ) error {
 	switch t := mut.(type) {
 	case *parser.AlterTableSetDefault:
+		if col.IsComputed() {
+			return pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
+				"computed column %q cannot have a default value", col.Name)
+		}
 		if t.Default == nil {
 			col.DefaultExpr = nil
 		} else {
@@ -518,6 +583,133 @@ func applyColumnMutation(
 	return nil
 }
 
+// alterColumnType changes the type of col to toType. If the stored values of
+// col are valid values of the new type, only the column descriptor is changed
+// and true is returned. Otherwise a mutation is added for a new column of the
+// new type, which is backfilled with the converted values of col and then
+// replaces it.
+func alterColumnType(
+	tableDesc *sqlbase.TableDescriptor,
+	col sqlbase.ColumnDescriptor,
+	toType parser.ColumnType,
+	searchPath parser.SearchPath,
+	evalCtx *parser.EvalContext,
+) (bool, error) {
+	if _, err := tableDesc.FindActiveColumnByName(col.Name); err != nil {
+		return false, fmt.Errorf("column %q in the middle of being added, try again later", col.Name)
+	}
+	for _, m := range tableDesc.Mutations {
+		if m.ConvertFromColumnID == col.ID {
+			return false, fmt.Errorf("column %q in the middle of a type change, try again later", col.Name)
+		}
+	}
+	for _, ref := range tableDesc.DependedOnBy {
+		for _, colID := range ref.ColumnIDs {
+			if colID == col.ID {
+				return false, fmt.Errorf("cannot alter type of column %q because a view depends on it", col.Name)
+			}
+		}
+	}
+	if col.IsComputed() {
+		return false, fmt.Errorf("cannot alter type of computed column %q", col.Name)
+	}
+	if computed, err := sqlbase.ComputedColumnReferencing(tableDesc, col); err != nil {
+		return false, err
+	} else if computed != nil {
+		return false, fmt.Errorf("cannot alter type of column %q because computed column %q depends on it",
+			col.Name, computed.Name)
+	}
+	if policy, err := sqlbase.PolicyReferencing(tableDesc, col); err != nil {
+		return false, err
+	} else if policy != nil {
+		return false, fmt.Errorf("cannot alter type of column %q because policy %q depends on it",
+			col.Name, policy.Name)
+	}
+
+	// Describe the column with its new type the way CREATE TABLE would, which
+	// also checks that the default expression is valid for the new type.
+	d := &parser.ColumnTableDef{Name: parser.Name(col.Name), Type: toType}
+	d.Nullable.Nullability = parser.Null
+	if !col.Nullable {
+		d.Nullable.Nullability = parser.NotNull
+	}
+	if col.DefaultExpr != nil {
+		expr, err := parser.ParseExpr(*col.DefaultExpr)
+		if err != nil {
+			return false, err
+		}
+		d.DefaultExpr.Expr = expr
+	}
+	newCol, _, err := sqlbase.MakeColumnDefDescs(d, searchPath, evalCtx)
+	if err != nil {
+		return false, err
+	}
+
+	if !sqlbase.ColumnTypeConversionRequiresBackfill(col.Type, newCol.Type) {
+		col.Type = newCol.Type
+		tableDesc.UpdateColumnDescriptor(col)
+		return true, nil
+	}
+
+	// The column is rewritten, which is only supported for columns whose old
+	// values aren't also stored elsewhere or interpreted by constraints: the
+	// backfill rewrites the column in the primary index only, and doesn't
+	// rebuild the indexes or re-validate the CHECK constraints referencing it.
+	for _, idx := range tableDesc.AllNonDropIndexes() {
+		if idx.ContainsColumnID(col.ID) {
+			return false, pgerror.Unimplemented("alter column type indexed", fmt.Sprintf(
+				"changing the type of column %q from %s to %s requires a rewrite, which is "+
+					"not supported for columns referenced by an index (%q)",
+				col.Name, col.Type.SQLString(), newCol.Type.SQLString(), idx.Name),
+			).SetHintf("drop index %q, change the type of the column and recreate the index", idx.Name)
+		}
+	}
+	referencedByCheck := false
+	for _, check := range tableDesc.Checks {
+		expr, err := parser.ParseExpr(check.Expr)
+		if err != nil {
+			return false, err
+		}
+		if _, err := parser.SimpleVisit(expr, func(expr parser.Expr) (error, bool, parser.Expr) {
+			if vBase, ok := expr.(parser.VarName); ok {
+				v, err := vBase.NormalizeVarName()
+				if err != nil {
+					return err, false, nil
+				}
+				if c, ok := v.(*parser.ColumnItem); ok && string(c.ColumnName) == col.Name {
+					referencedByCheck = true
+				}
+				return nil, false, expr
+			}
+			return nil, true, expr
+		}); err != nil {
+			return false, err
+		}
+	}
+	if referencedByCheck {
+		return false, pgerror.Unimplemented("alter column type check", fmt.Sprintf(
+			"changing the type of column %q from %s to %s requires a rewrite, which is "+
+				"not supported for columns referenced by a CHECK constraint",
+			col.Name, col.Type.SQLString(), newCol.Type.SQLString()),
+		).SetHintf("drop the constraint, change the type of the column and recreate the constraint")
+	}
+
+	// Check that the values can be converted before starting the schema change.
+	if _, err := sqlbase.MakeColumnConverter(col, *newCol); err != nil {
+		return false, err
+	}
+
+	// The new column has a temporary name until it replaces col.
+	newCol.Name = col.Name + "_conv"
+	for i := 1; ; i++ {
+		if _, _, err := tableDesc.FindColumnByName(parser.Name(newCol.Name)); err != nil {
+			break
+		}
+		newCol.Name = fmt.Sprintf("%s_conv%d", col.Name, i)
+	}
+	return false, tableDesc.AddColumnConversionMutation(*newCol, col)
+}
+
 func labeledRowValues(cols []sqlbase.ColumnDescriptor, values parser.Datums) string {
 	var s bytes.Buffer
 	for i := range cols {
@@ -17,7 +17,10 @@ package sql
 import (
 	"fmt"
 
+	"golang.org/x/net/context"
+
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 )
@@ -50,16 +53,95 @@ func CheckPrivilege(
 		user, privilege, descriptor.TypeName(), descriptor.GetName())
 }
 
-// CheckPrivilege implements the AuthorizationAccessor interface.
+// CheckPrivilege implements the AuthorizationAccessor interface. The
+// privileges of the roles the session user is a member of are inherited.
 func (p *planner) CheckPrivilege(
 	descriptor sqlbase.DescriptorProto, privilege privilege.Kind,
 ) error {
-	return CheckPrivilege(p.session.User, descriptor, privilege)
+	if ok, err := p.hasPrivilege(descriptor.GetPrivileges(), privilege); err != nil || ok {
+		return err
+	}
+	return fmt.Errorf("user %s does not have %s privilege on %s %s",
+		p.session.User, privilege, descriptor.TypeName(), descriptor.GetName())
+}
+
+// hasPrivilege returns whether privs grants priv to the session user, or to
+// one of the roles it is a member of.
+func (p *planner) hasPrivilege(
+	privs *sqlbase.PrivilegeDescriptor, priv privilege.Kind,
+) (bool, error) {
+	if privs.CheckPrivilege(p.session.User, priv) {
+		return true, nil
+	}
+	roles, err := p.sessionUserMemberOf(p.session.Ctx())
+	if err != nil {
+		return false, err
+	}
+	for role := range roles {
+		if privs.CheckPrivilege(role, priv) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// anyColumnPrivilege returns whether the session user has priv on at least
+// one column of table alone. Statements needing priv on the table can then
+// use it, as long as they verify that they only use the columns the user has
+// priv on.
+func (p *planner) anyColumnPrivilege(
+	table *sqlbase.TableDescriptor, priv privilege.Kind,
+) (bool, error) {
+	for i := range table.Columns {
+		if table.Columns[i].Privileges == nil {
+			continue
+		}
+		if ok, err := p.hasPrivilege(table.Columns[i].Privileges, priv); err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// hasColumnPrivilege returns whether the session user has priv on the column
+// col of table, either on the whole table or on the column alone.
+func (p *planner) hasColumnPrivilege(
+	table *sqlbase.TableDescriptor, col *sqlbase.ColumnDescriptor, priv privilege.Kind,
+) (bool, error) {
+	if ok, err := p.hasPrivilege(table.Privileges, priv); err != nil || ok {
+		return ok, err
+	}
+	if col.Privileges == nil {
+		return false, nil
+	}
+	return p.hasPrivilege(col.Privileges, priv)
+}
+
+// checkColumnPrivileges verifies that the session user has priv on each of
+// cols, columns of table, either on the whole table or on the columns alone.
+func (p *planner) checkColumnPrivileges(
+	table *sqlbase.TableDescriptor, cols []sqlbase.ColumnDescriptor, priv privilege.Kind,
+) error {
+	for i := range cols {
+		ok, err := p.hasColumnPrivilege(table, &cols[i], priv)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("user %s does not have %s privilege on column %s of %s %s",
+				p.session.User, priv, cols[i].Name, table.TypeName(), table.GetName())
+		}
+	}
+	return nil
 }
 
 // anyPrivilege implements the AuthorizationAccessor interface.
 func (p *planner) anyPrivilege(descriptor sqlbase.DescriptorProto) error {
-	if userCanSeeDescriptor(descriptor, p.session.User) {
+	roles, err := p.sessionUserMemberOf(p.session.Ctx())
+	if err != nil {
+		return err
+	}
+	if userCanSeeDescriptor(descriptor, p.session.User, roles) {
 		return nil
 	}
 	return fmt.Errorf("user %s has no privileges on %s %s",
@@ -74,6 +156,84 @@ func (p *planner) RequireSuperUser(action string) error {
 	return nil
 }
 
-func userCanSeeDescriptor(descriptor sqlbase.DescriptorProto, user string) bool {
-	return descriptor.GetPrivileges().AnyPrivilege(user) || isVirtualDescriptor(descriptor)
+// userCanSeeDescriptor returns whether user, or one of roles, the roles it is
+// a member of, has any privilege on descriptor, or on one of its columns if
+// it is a table.
+func userCanSeeDescriptor(
+	descriptor sqlbase.DescriptorProto, user string, roles map[string]bool,
+) bool {
+	if isVirtualDescriptor(descriptor) {
+		return true
+	}
+	if grantsAnyPrivilege(descriptor.GetPrivileges(), user, roles) {
+		return true
+	}
+	if table, ok := descriptor.(*sqlbase.TableDescriptor); ok {
+		for i := range table.Columns {
+			if privs := table.Columns[i].Privileges; privs != nil && grantsAnyPrivilege(privs, user, roles) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// grantsAnyPrivilege returns whether privs grants any privilege to user or
+// to one of roles.
+func grantsAnyPrivilege(privs *sqlbase.PrivilegeDescriptor, user string, roles map[string]bool) bool {
+	if privs.AnyPrivilege(user) {
+		return true
+	}
+	for role := range roles {
+		if privs.AnyPrivilege(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// sessionUserMemberOf returns the roles the session user is a member of, with
+// whether it has the admin option on them; see memberOf. The result is cached
+// until the end of the statement. Superusers are not members of any role.
+func (p *planner) sessionUserMemberOf(ctx context.Context) (map[string]bool, error) {
+	user := p.session.User
+	if user == security.RootUser || user == security.NodeUser || p.txn == nil {
+		return nil, nil
+	}
+	if p.sessionUserRoles == nil {
+		roles, err := p.memberOf(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		p.sessionUserRoles = roles
+	}
+	return p.sessionUserRoles, nil
+}
+
+// memberOf returns the roles member is a member of, either directly or
+// through the roles it is itself a member of. Each role is mapped to whether
+// member, or one of the roles it inherits from, was granted it with the admin
+// option.
+func (p *planner) memberOf(ctx context.Context, member string) (map[string]bool, error) {
+	const getRoles = `SELECT "role", "isAdmin" FROM system.role_members WHERE "member" = $1`
+	internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+	roles := make(map[string]bool)
+	visited := map[string]struct{}{member: {}}
+	for toVisit := []string{member}; len(toVisit) > 0; {
+		m := toVisit[0]
+		toVisit = toVisit[1:]
+		rows, err := internalExecutor.QueryRowsInTransaction(ctx, "member-of", p.txn, getRoles, m)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			role := string(parser.MustBeDString(row[0]))
+			roles[role] = roles[role] || bool(*row[1].(*parser.DBool))
+			if _, ok := visited[role]; !ok {
+				visited[role] = struct{}{}
+				toVisit = append(toVisit, role)
+			}
+		}
+	}
+	return roles, nil
 }
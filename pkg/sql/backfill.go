@@ -142,7 +142,8 @@ func (sc *SchemaChanger) runBackfill(
 			switch t := m.Descriptor_.(type) {
 			case *sqlbase.DescriptorMutation_Column:
 				desc := m.GetColumn()
-				if desc.DefaultExpr != nil || !desc.Nullable {
+				if desc.DefaultExpr != nil || !desc.Nullable || m.ConvertFromColumnID != 0 ||
+					desc.IsComputed() {
 					needColumnBackfill = true
 				}
 			case *sqlbase.DescriptorMutation_Index:
@@ -302,7 +303,7 @@ func (sc *SchemaChanger) truncateIndexes(
 					return err
 				}
 
-				rd, err := sqlbase.MakeRowDeleter(txn, tableDesc, nil, nil, false, alloc)
+				rd, err := sqlbase.MakeRowDeleter(txn, tableDesc, nil, nil, false, nil, alloc)
 				if err != nil {
 					return err
 				}
@@ -28,23 +28,34 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
 
-// checkHelper validates check constraints on rows, on INSERT and UPDATE.
+// checkHelper validates check constraints on rows, on INSERT and UPDATE,
+// as well as the row-level security policies of the table.
 type checkHelper struct {
 	exprs        []parser.TypedExpr
 	cols         []sqlbase.ColumnDescriptor
 	sourceInfo   *dataSourceInfo
 	ivars        []parser.IndexedVar
 	curSourceRow parser.Datums
+
+	// policyExpr, if set, is the predicate the rows written by the session
+	// user must satisfy; see policyPredicate.
+	policyExpr parser.TypedExpr
+	tableName  string
 }
 
 func (c *checkHelper) init(
 	ctx context.Context, p *planner, tn *parser.TableName, tableDesc *sqlbase.TableDescriptor,
 ) error {
-	if len(tableDesc.Checks) == 0 {
+	policyPred, err := p.policyPredicate(ctx, tableDesc, true /* forWrite */)
+	if err != nil {
+		return err
+	}
+	if len(tableDesc.Checks) == 0 && policyPred == nil {
 		return nil
 	}
 
 	c.cols = tableDesc.Columns
+	c.tableName = tableDesc.Name
 	c.sourceInfo = newSourceInfoForSingleTable(
 		*tn, sqlbase.ResultColumnsFromColDescs(tableDesc.Columns),
 	)
@@ -68,6 +79,13 @@ func (c *checkHelper) init(
 		}
 		c.exprs[i] = typedExpr
 	}
+	if policyPred != nil {
+		c.policyExpr, err = p.analyzeExpr(ctx, policyPred, multiSourceInfo{c.sourceInfo}, ivarHelper,
+			parser.TypeBool, true, "row-level security policy")
+		if err != nil {
+			return err
+		}
+	}
 	c.ivars = ivarHelper.GetIndexedVars()
 	c.curSourceRow = make(parser.Datums, len(c.cols))
 	return nil
@@ -130,6 +148,17 @@ func (c *checkHelper) check(ctx *parser.EvalContext) error {
 				"failed to satisfy CHECK constraint (%s)", expr)
 		}
 	}
+	if c.policyExpr != nil {
+		// Unlike CHECK constraints, policies reject the rows for which
+		// their predicate is NULL.
+		if d, err := c.policyExpr.Eval(ctx); err != nil {
+			return err
+		} else if res, err := parser.GetBool(d); err != nil {
+			return err
+		} else if !res {
+			return newPolicyViolationError(c.tableName)
+		}
+	}
 	return nil
 }
 
@@ -144,7 +173,7 @@ func (p *planner) validateCheckExpr(
 		return err
 	}
 	sel := &parser.SelectClause{
-		Exprs: sqlbase.ColumnsSelectors(tableDesc.Columns),
+		Exprs: sqlbase.ColumnsSelectors(tableDesc.Columns, false),
 		From:  &parser.From{Tables: parser.TableExprs{tableName}},
 		Where: &parser.Where{Expr: &parser.NotExpr{Expr: expr}},
 	}
@@ -77,6 +77,9 @@ func (p *planner) CopyFrom(ctx context.Context, n *parser.CopyFrom) (planNode, e
 	if err != nil {
 		return nil, err
 	}
+	if err := p.checkColumnPrivileges(en.tableDesc, cols, privilege.INSERT); err != nil {
+		return nil, err
+	}
 	cn.resultColumns = make(sqlbase.ResultColumns, len(cols))
 	for i, c := range cols {
 		cn.resultColumns[i] = sqlbase.ResultColumn{Typ: c.Type.ToDatumType()}
@@ -186,7 +189,10 @@ func (n *copyNode) addRow(ctx context.Context, line []byte) error {
 			parser.TypeDate,
 			parser.TypeInterval,
 			parser.TypeINet,
+			parser.TypeJSON,
 			parser.TypeString,
+			parser.TypeTime,
+			parser.TypeTimeTZ,
 			parser.TypeTimestamp,
 			parser.TypeTimestampTZ,
 			parser.TypeUUID:
@@ -178,6 +178,10 @@ CREATE TABLE crdb_internal.tables (
 		if err != nil {
 			return err
 		}
+		roles, err := p.sessionUserMemberOf(ctx)
+		if err != nil {
+			return err
+		}
 		dbNames := make(map[sqlbase.ID]string)
 		// Record database descriptors for name lookups.
 		for _, desc := range descs {
@@ -190,7 +194,7 @@ CREATE TABLE crdb_internal.tables (
 		// include added and dropped descriptors.
 		for _, desc := range descs {
 			table, ok := desc.(*sqlbase.TableDescriptor)
-			if !ok || !userCanSeeDescriptor(table, p.session.User) {
+			if !ok || !userCanSeeDescriptor(table, p.session.User, roles) {
 				continue
 			}
 			dbName := dbNames[table.GetParentID()]
@@ -247,11 +251,15 @@ CREATE TABLE crdb_internal.schema_changes (
 		if err != nil {
 			return err
 		}
+		roles, err := p.sessionUserMemberOf(ctx)
+		if err != nil {
+			return err
+		}
 		// Note: we do not use forEachTableDesc() here because we want to
 		// include added and dropped descriptors.
 		for _, desc := range descs {
 			table, ok := desc.(*sqlbase.TableDescriptor)
-			if !ok || !userCanSeeDescriptor(table, p.session.User) {
+			if !ok || !userCanSeeDescriptor(table, p.session.User, roles) {
 				continue
 			}
 			tableID := parser.NewDInt(parser.DInt(int64(table.ID)))
@@ -300,7 +308,12 @@ CREATE TABLE crdb_internal.leases (
   deleted     BOOL NOT NULL
 );
 `,
-	populate: func(_ context.Context, p *planner, _ string, addRow func(...parser.Datum) error) error {
+	populate: func(ctx context.Context, p *planner, _ string, addRow func(...parser.Datum) error) error {
+		// The roles are looked up before the lease manager is locked.
+		roles, err := p.sessionUserMemberOf(ctx)
+		if err != nil {
+			return err
+		}
 		leaseMgr := p.LeaseMgr()
 		nodeID := parser.NewDInt(parser.DInt(int64(leaseMgr.nodeID.Get())))
 
@@ -317,7 +330,7 @@ CREATE TABLE crdb_internal.leases (
 				dropped := parser.MakeDBool(parser.DBool(ts.mu.dropped))
 
 				for _, state := range ts.mu.active.data {
-					if !userCanSeeDescriptor(&state.TableDescriptor, p.session.User) {
+					if !userCanSeeDescriptor(&state.TableDescriptor, p.session.User, roles) {
 						continue
 					}
 
@@ -620,6 +633,19 @@ CREATE TABLE crdb_internal.session_variables (
 				return err
 			}
 		}
+		customNames := make([]string, 0, len(p.session.CustomVars))
+		for vName := range p.session.CustomVars {
+			customNames = append(customNames, vName)
+		}
+		sort.Strings(customNames)
+		for _, vName := range customNames {
+			if err := addRow(
+				parser.NewDString(vName),
+				parser.NewDString(p.session.CustomVars[vName]),
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
 }
@@ -878,9 +904,13 @@ CREATE TABLE crdb_internal.create_statements (
 				var err error
 				var typeView = parser.DString("view")
 				var typeTable = parser.DString("table")
+				var typeSequence = parser.DString("sequence")
 				if table.IsView() {
 					descType = &typeView
 					stmt, err = p.showCreateView(ctx, parser.Name(table.Name), table)
+				} else if table.IsSequence() {
+					descType = &typeSequence
+					stmt, err = p.showCreateSequence(ctx, parser.Name(table.Name), table)
 				} else {
 					descType = &typeTable
 					stmt, err = p.showCreateTable(ctx, parser.Name(table.Name), prefix, table)
@@ -162,9 +162,43 @@ func (n *createIndexNode) Start(params runParams) error {
 		Unique:           n.n.Unique,
 		StoreColumnNames: n.n.Storing.ToStrings(),
 	}
-	if err := indexDesc.FillColumns(n.n.Columns); err != nil {
+	if n.n.Inverted {
+		indexDesc.Type = sqlbase.IndexDescriptor_INVERTED
+	}
+	// The values of the expressions of the index are stored in hidden
+	// computed columns added along with the index; the column backfill runs
+	// before the index backfill.
+	elems, exprCols, err := sqlbase.ResolveIndexExprs(
+		n.tableDesc, n.n.Columns, params.p.session.SearchPath,
+	)
+	if err != nil {
+		return err
+	}
+	for _, col := range exprCols {
+		n.tableDesc.AddColumnMutation(col, sqlbase.DescriptorMutation_ADD)
+	}
+	if err := indexDesc.FillColumns(elems); err != nil {
 		return err
 	}
+	if n.n.Predicate != nil {
+		// So is the value of the predicate of a partial index, whose column
+		// needs an ID before the index can refer to it.
+		predCol, pred, err := sqlbase.MakeIndexPredicateColumn(
+			n.tableDesc, n.n.Predicate, exprCols, params.p.session.SearchPath,
+		)
+		if err != nil {
+			return err
+		}
+		n.tableDesc.AddColumnMutation(predCol, sqlbase.DescriptorMutation_ADD)
+		if err := n.tableDesc.AllocateIDs(); err != nil {
+			return err
+		}
+		if predCol, _, err = n.tableDesc.FindColumnByName(parser.Name(predCol.Name)); err != nil {
+			return err
+		}
+		indexDesc.Predicate = pred
+		indexDesc.PredicateColumnID = predCol.ID
+	}
 
 	mutationIdx := len(n.tableDesc.Mutations)
 	if err := n.tableDesc.AddIndexMutation(indexDesc, sqlbase.DescriptorMutation_ADD); err != nil {
@@ -222,8 +256,10 @@ func (*createIndexNode) Close(context.Context)        {}
 func (*createIndexNode) Values() parser.Datums        { return parser.Datums{} }
 
 type createUserNode struct {
-	n        *parser.CreateUser
+	name     parser.Name
 	password string
+	// isRole is set for CREATE ROLE. Roles cannot log in.
+	isRole bool
 }
 
 // CreateUser creates a user.
@@ -235,12 +271,7 @@ func (p *planner) CreateUser(ctx context.Context, n *parser.CreateUser) (planNod
 		return nil, errors.New("no username specified")
 	}
 
-	tDesc, err := getTableDesc(ctx, p.txn, p.getVirtualTabler(), &parser.TableName{DatabaseName: "system", TableName: "users"})
-	if err != nil {
-		return nil, err
-	}
-
-	if err := p.CheckPrivilege(tDesc, privilege.INSERT); err != nil {
+	if err := p.checkCanCreateUser(ctx); err != nil {
 		return nil, err
 	}
 
@@ -252,7 +283,29 @@ func (p *planner) CreateUser(ctx context.Context, n *parser.CreateUser) (planNod
 		}
 	}
 
-	return &createUserNode{n: n, password: resolvedPassword}, nil
+	return &createUserNode{name: n.Name, password: resolvedPassword}, nil
+}
+
+// CreateRole creates a role.
+// Privileges: INSERT on system.users.
+func (p *planner) CreateRole(ctx context.Context, n *parser.CreateRole) (planNode, error) {
+	if n.Name == "" {
+		return nil, errors.New("no role name specified")
+	}
+
+	if err := p.checkCanCreateUser(ctx); err != nil {
+		return nil, err
+	}
+
+	return &createUserNode{name: n.Name, isRole: true}, nil
+}
+
+func (p *planner) checkCanCreateUser(ctx context.Context) error {
+	tDesc, err := getTableDesc(ctx, p.txn, p.getVirtualTabler(), &parser.TableName{DatabaseName: "system", TableName: "users"})
+	if err != nil {
+		return err
+	}
+	return p.CheckPrivilege(tDesc, privilege.INSERT)
 }
 
 const usernameHelp = "usernames are case insensitive, must start with a letter " +
@@ -277,6 +330,14 @@ func NormalizeAndValidateUsername(username string) (string, error) {
 	return username, nil
 }
 
+// userOrRole returns the name of the kind of principal, for messages.
+func userOrRole(isRole bool) string {
+	if isRole {
+		return "role"
+	}
+	return "user"
+}
+
 func (n *createUserNode) Start(params runParams) error {
 	var hashedPassword []byte
 	if n.password != "" {
@@ -287,7 +348,7 @@ func (n *createUserNode) Start(params runParams) error {
 		}
 	}
 
-	normalizedUsername, err := NormalizeAndValidateUsername(string(n.n.Name))
+	normalizedUsername, err := NormalizeAndValidateUsername(string(n.name))
 	if err != nil {
 		return err
 	}
@@ -297,18 +358,20 @@ func (n *createUserNode) Start(params runParams) error {
 		params.ctx,
 		"create-user",
 		params.p.txn,
-		"INSERT INTO system.users VALUES ($1, $2);",
+		"INSERT INTO system.users VALUES ($1, $2, $3);",
 		normalizedUsername,
 		hashedPassword,
+		n.isRole,
 	)
 	if err != nil {
 		if sqlbase.IsUniquenessConstraintViolationError(err) {
-			err = errors.Errorf("user %s already exists", normalizedUsername)
+			err = errors.Errorf("%s %s already exists", userOrRole(n.isRole), normalizedUsername)
 		}
 		return err
 	} else if rowsAffected != 1 {
 		return errors.Errorf(
-			"%d rows affected by user creation; expected exactly one row affected", rowsAffected,
+			"%d rows affected by %s creation; expected exactly one row affected",
+			rowsAffected, userOrRole(n.isRole),
 		)
 	}
 
@@ -586,6 +649,7 @@ func HoistConstraints(n *parser.CreateTable) {
 					FromCols: parser.NameList{col.Name},
 					ToCols:   targetCol,
 					Name:     col.References.ConstraintName,
+					Actions:  col.References.Actions,
 				})
 				col.References.Table = parser.NormalizableTableName{}
 			}
@@ -732,6 +796,120 @@ func (n *createTableNode) Close(ctx context.Context) {
 func (*createTableNode) Next(runParams) (bool, error) { return false, nil }
 func (*createTableNode) Values() parser.Datums        { return parser.Datums{} }
 
+type createSequenceNode struct {
+	n      *parser.CreateSequence
+	dbDesc *sqlbase.DatabaseDescriptor
+}
+
+// CreateSequence creates a sequence.
+// Privileges: CREATE on database.
+//   Notes: postgres requires CREATE on database.
+func (p *planner) CreateSequence(ctx context.Context, n *parser.CreateSequence) (planNode, error) {
+	name, err := n.Name.NormalizeWithDatabaseName(p.session.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	dbDesc, err := MustGetDatabaseDesc(ctx, p.txn, p.getVirtualTabler(), name.Database())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &createSequenceNode{n: n, dbDesc: dbDesc}, nil
+}
+
+func (n *createSequenceNode) Start(params runParams) error {
+	seqName := n.n.Name.TableName().Table()
+	tKey := tableKey{parentID: n.dbDesc.ID, name: seqName}
+	key := tKey.Key()
+	if exists, err := descExists(params.ctx, params.p.txn, key); err == nil && exists {
+		if n.n.IfNotExists {
+			return nil
+		}
+		return sqlbase.NewRelationAlreadyExistsError(tKey.Name())
+	} else if err != nil {
+		return err
+	}
+
+	id, err := GenerateUniqueDescID(params.ctx, params.p.session.execCfg.DB)
+	if err != nil {
+		return err
+	}
+
+	// Inherit permissions from the database descriptor.
+	privs := n.dbDesc.GetPrivileges()
+
+	desc, err := makeSequenceTableDesc(
+		seqName, n.n.Options, n.dbDesc.ID, id, params.p.txn.OrigTimestamp(), privs,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = desc.ValidateTable(); err != nil {
+		return err
+	}
+
+	if err = params.p.createDescriptorWithID(params.ctx, key, id, &desc); err != nil {
+		return err
+	}
+
+	// Initialize the sequence's value so that the first call to nextval()
+	// returns the START value.
+	seqValueKey := keys.MakeSequenceKey(uint32(id))
+	if err := params.p.txn.Put(
+		params.ctx, seqValueKey, desc.SequenceOpts.Start-desc.SequenceOpts.Increment,
+	); err != nil {
+		return err
+	}
+	params.p.session.tables.addUncommittedSequence(id)
+
+	if err := desc.Validate(params.ctx, params.p.txn); err != nil {
+		return err
+	}
+
+	// Log Create Sequence event. This is an auditable log event and is
+	// recorded in the same transaction as the table descriptor update.
+	return MakeEventLogger(params.p.LeaseMgr()).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogCreateSequence,
+		int32(desc.ID),
+		int32(params.p.evalCtx.NodeID),
+		struct {
+			SequenceName string
+			Statement    string
+			User         string
+		}{n.n.Name.String(), n.n.String(), params.p.session.User},
+	)
+}
+
+func (*createSequenceNode) Next(runParams) (bool, error) { return false, nil }
+func (*createSequenceNode) Values() parser.Datums        { return parser.Datums{} }
+func (*createSequenceNode) Close(context.Context)        {}
+
+// makeSequenceTableDesc returns the table descriptor for a new sequence.
+// Like views, sequences are created directly in the PUBLIC state.
+func makeSequenceTableDesc(
+	sequenceName string,
+	sequenceOptions parser.SequenceOptions,
+	parentID sqlbase.ID,
+	id sqlbase.ID,
+	creationTime hlc.Timestamp,
+	privileges *sqlbase.PrivilegeDescriptor,
+) (sqlbase.TableDescriptor, error) {
+	desc := initTableDescriptor(id, parentID, sequenceName, creationTime, privileges)
+	desc.SequenceOpts = &sqlbase.TableDescriptor_SequenceOpts{}
+	if err := assignSequenceOptions(desc.SequenceOpts, sequenceOptions, true /* setDefaults */); err != nil {
+		return desc, err
+	}
+	return desc, desc.AllocateIDs()
+}
+
 type indexMatch bool
 
 const (
@@ -748,6 +926,10 @@ func matchesIndex(
 	if len(cols) > len(idx.ColumnIDs) || (exact && len(cols) != len(idx.ColumnIDs)) {
 		return false
 	}
+	// A partial index doesn't contain all the rows of the table.
+	if idx.IsPartial() {
+		return false
+	}
 
 	for i := range cols {
 		if cols[i].ID != idx.ColumnIDs[i] {
@@ -882,11 +1064,35 @@ func resolveFK(
 		}
 	}
 
+	// Referencing columns can only be set to NULL or to their default value if
+	// the result satisfies their NOT NULL constraint.
+	for _, action := range []parser.ReferenceAction{d.Actions.Delete, d.Actions.Update} {
+		for _, col := range srcCols {
+			if col.Nullable {
+				continue
+			}
+			switch action {
+			case parser.SetNull:
+				return pgerror.NewErrorf(pgerror.CodeInvalidForeignKeyError,
+					"cannot add a SET NULL cascading action on column %q which has a NOT NULL constraint",
+					col.Name)
+			case parser.SetDefault:
+				if col.DefaultExpr == nil {
+					return pgerror.NewErrorf(pgerror.CodeInvalidForeignKeyError,
+						"cannot add a SET DEFAULT cascading action on column %q which has a NOT "+
+							"NULL constraint and a NULL default expression", col.Name)
+				}
+			}
+		}
+	}
+
 	ref := sqlbase.ForeignKeyReference{
 		Table:           target.ID,
 		Index:           targetIdx.ID,
 		Name:            constraintName,
 		SharedPrefixLen: int32(len(srcCols)),
+		OnDelete:        sqlbase.ForeignKeyReferenceActionValue[d.Actions.Delete],
+		OnUpdate:        sqlbase.ForeignKeyReferenceActionValue[d.Actions.Update],
 	}
 	if mode == sqlbase.ConstraintValidity_Unvalidated {
 		ref.Validity = sqlbase.ConstraintValidity_Unvalidated
@@ -1260,6 +1466,16 @@ func MakeTableDesc(
 		}
 	}
 
+	// Computed columns can refer to any other column of the table, so their
+	// expressions are only checked once all the columns are known.
+	for i := range desc.Columns {
+		if col := &desc.Columns[i]; col.IsComputed() {
+			if err := sqlbase.ValidateComputedColumn(&desc, col, searchPath); err != nil {
+				return desc, err
+			}
+		}
+	}
+
 	var primaryIndexColumnSet map[string]struct{}
 	for _, def := range n.Defs {
 		switch d := def.(type) {
@@ -1271,7 +1487,17 @@ func MakeTableDesc(
 				Name:             string(d.Name),
 				StoreColumnNames: d.Storing.ToStrings(),
 			}
-			if err := idx.FillColumns(d.Columns); err != nil {
+			if d.Inverted {
+				idx.Type = sqlbase.IndexDescriptor_INVERTED
+			}
+			elems, exprCols, err := sqlbase.ResolveIndexExprs(&desc, d.Columns, searchPath)
+			if err != nil {
+				return desc, err
+			}
+			for _, col := range exprCols {
+				desc.AddColumn(col)
+			}
+			if err := idx.FillColumns(elems); err != nil {
 				return desc, err
 			}
 			if err := desc.AddIndex(idx, false); err != nil {
@@ -1286,7 +1512,18 @@ func MakeTableDesc(
 				Unique:           true,
 				StoreColumnNames: d.Storing.ToStrings(),
 			}
-			if err := idx.FillColumns(d.Columns); err != nil {
+			elems, exprCols, err := sqlbase.ResolveIndexExprs(&desc, d.Columns, searchPath)
+			if err != nil {
+				return desc, err
+			}
+			if d.PrimaryKey && len(exprCols) > 0 {
+				return desc, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+					"primary key cannot contain expressions")
+			}
+			for _, col := range exprCols {
+				desc.AddColumn(col)
+			}
+			if err := idx.FillColumns(elems); err != nil {
 				return desc, err
 			}
 			if err := desc.AddIndex(idx, d.PrimaryKey); err != nil {
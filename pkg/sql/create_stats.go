@@ -0,0 +1,146 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+type createStatsNode struct {
+	n         *parser.CreateStats
+	tableDesc *sqlbase.TableDescriptor
+	columnIDs []sqlbase.ColumnID
+}
+
+// CreateStatistics computes a statistic on a set of columns of a table and
+// stores it in system.table_statistics.
+// Privileges: SELECT on table.
+func (p *planner) CreateStatistics(ctx context.Context, n *parser.CreateStats) (planNode, error) {
+	tn, err := n.Table.NormalizeWithDatabaseName(p.session.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	tableDesc, err := MustGetTableDesc(ctx, p.txn, p.getVirtualTabler(), tn, false /*allowAdding*/)
+	if err != nil {
+		return nil, err
+	}
+	if !tableDesc.IsTable() || tableDesc.IsVirtualTable() {
+		return nil, sqlbase.NewWrongObjectTypeError(tn, "table")
+	}
+
+	if err := p.CheckPrivilege(tableDesc, privilege.SELECT); err != nil {
+		return nil, err
+	}
+
+	if len(n.ColumnNames) == 0 {
+		return nil, errors.New("no columns given for statistics")
+	}
+	columnIDs := make([]sqlbase.ColumnID, len(n.ColumnNames))
+	seen := make(map[sqlbase.ColumnID]struct{}, len(n.ColumnNames))
+	for i, colName := range n.ColumnNames {
+		col, err := tableDesc.FindActiveColumnByName(string(colName))
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[col.ID]; ok {
+			return nil, errors.Errorf("column %q appears twice in statistics", col.Name)
+		}
+		seen[col.ID] = struct{}{}
+		columnIDs[i] = col.ID
+	}
+
+	return &createStatsNode{n: n, tableDesc: tableDesc, columnIDs: columnIDs}, nil
+}
+
+func (n *createStatsNode) Start(params runParams) error {
+	p := params.p
+	dsp := p.session.distSQLPlanner
+	planCtx := dsp.NewPlanningCtx(params.ctx, p.txn)
+	plan, err := dsp.createPlanForCreateStats(&planCtx, p, n)
+	if err != nil {
+		return err
+	}
+	dsp.FinalizePlan(&planCtx, &plan)
+
+	rows := sqlbase.NewRowContainer(
+		p.session.TxnState.makeBoundAccount(),
+		sqlbase.ColTypeInfoFromColTypes(plan.ResultTypes),
+		1, /* rowCapacity */
+	)
+	defer rows.Close(params.ctx)
+
+	cfg := p.ExecCfg()
+	recv, err := makeDistSQLReceiver(
+		params.ctx,
+		NewRowResultWriter(parser.Rows, rows),
+		cfg.RangeDescriptorCache,
+		cfg.LeaseHolderCache,
+		p.txn,
+		func(ts hlc.Timestamp) {
+			_ = cfg.Clock.Update(ts)
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if err := dsp.Run(&planCtx, p.txn, &plan, &recv, p.evalCtx); err != nil {
+		return err
+	}
+	if recv.err != nil {
+		return recv.err
+	}
+	if rows.Len() != 1 {
+		return errors.Errorf("expected 1 row of statistics, got %d", rows.Len())
+	}
+	// The row has the row count, distinct count, NULL count and histogram of
+	// the statistic.
+	row := rows.At(0)
+
+	columnIDs := parser.NewDArray(parser.TypeInt)
+	for _, colID := range n.columnIDs {
+		if err := columnIDs.Append(parser.NewDInt(parser.DInt(colID))); err != nil {
+			return err
+		}
+	}
+
+	internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+	_, err = internalExecutor.ExecuteStatementInTransaction(
+		params.ctx,
+		"insert-statistic",
+		p.txn,
+		`INSERT INTO system.table_statistics (
+			"tableID", name, "columnIDs", "rowCount", "distinctCount", "nullCount", histogram
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		n.tableDesc.ID,
+		string(n.n.Name),
+		columnIDs,
+		row[0],
+		row[1],
+		row[2],
+		row[3],
+	)
+	return err
+}
+
+func (*createStatsNode) Next(runParams) (bool, error) { return false, nil }
+func (*createStatsNode) Values() parser.Datums        { return parser.Datums{} }
+func (*createStatsNode) Close(context.Context)        {}
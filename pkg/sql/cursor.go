@@ -0,0 +1,339 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// sqlCursor is a cursor declared with DECLARE, or the cursor running the
+// query of a portal executed with a row limit. It holds a started plan, the
+// rows of which are retrieved on demand by FETCH and MOVE. Cursors are scoped
+// to the transaction they are declared in.
+type sqlCursor struct {
+	name string
+	// seq is the number of cursors declared in the transaction before this
+	// one. Rolling back to a savepoint closes the cursors declared after it.
+	seq int
+	// p is the planner the plan was made with. The cursor needs its own
+	// planner because the session's planner is reset for every statement.
+	p    *planner
+	plan planNode
+	// rowAcc tracks the memory used by the current row of the plan.
+	rowAcc mon.BoundAccount
+	// open is set once the plan is started and the cursor is registered in
+	// the transaction.
+	open bool
+	// done is set once the plan has no more rows.
+	done   bool
+	closed bool
+}
+
+// newCursor plans stmt for a new cursor. The plan is started when the cursor
+// is opened with txnState.openCursor.
+func (p *planner) newCursor(
+	ctx context.Context, name string, stmt parser.Statement,
+) (*sqlCursor, error) {
+	cp := p.session.newPlanner(nil /* e */, p.txn)
+	cp.evalCtx.ClusterID = p.evalCtx.ClusterID
+	cp.evalCtx.NodeID = p.evalCtx.NodeID
+	cp.evalCtx.ReCache = p.evalCtx.ReCache
+	cp.evalCtx.SetTxnTimestamp(p.evalCtx.GetTxnTimestampRaw())
+	cp.evalCtx.SetStmtTimestamp(p.evalCtx.GetStmtTimestamp())
+	cp.semaCtx.Placeholders.Assign(&p.semaCtx.Placeholders)
+	cp.avoidCachedDescriptors = p.avoidCachedDescriptors
+
+	c := &sqlCursor{name: name, p: cp, rowAcc: cp.evalCtx.Mon.MakeBoundAccount()}
+	cp.evalCtx.ActiveMemAcc = &c.rowAcc
+
+	plan, err := cp.newPlan(ctx, stmt, nil)
+	if err != nil {
+		c.rowAcc.Close(ctx)
+		return nil, err
+	}
+	plan, err = cp.optimizePlan(ctx, plan, allColumns(plan))
+	if err != nil {
+		plan.Close(ctx)
+		c.rowAcc.Close(ctx)
+		return nil, err
+	}
+	c.plan = plan
+	return c, nil
+}
+
+// next advances the cursor to its next row, if any.
+func (c *sqlCursor) next(ctx context.Context) (bool, error) {
+	if c.done {
+		return false, nil
+	}
+	c.rowAcc.Clear(ctx)
+	next, err := c.plan.Next(runParams{ctx: ctx, p: c.p})
+	if err != nil {
+		return false, err
+	}
+	c.done = !next
+	return next, nil
+}
+
+func (c *sqlCursor) close(ctx context.Context) {
+	if c.closed {
+		return
+	}
+	c.plan.Close(ctx)
+	c.rowAcc.Close(ctx)
+	c.closed = true
+}
+
+// openCursor starts the plan of cursor c and registers the cursor in the
+// transaction.
+func (ts *txnState) openCursor(ctx context.Context, c *sqlCursor) error {
+	if err := c.p.startPlan(ctx, c.plan); err != nil {
+		return err
+	}
+	if ts.cursors == nil {
+		ts.cursors = make(map[string]*sqlCursor)
+	}
+	c.seq = ts.numCursors
+	ts.numCursors++
+	ts.cursors[c.name] = c
+	c.open = true
+	return nil
+}
+
+// closeCursor closes cursor c and removes it from the transaction.
+func (ts *txnState) closeCursor(ctx context.Context, c *sqlCursor) {
+	c.close(ctx)
+	if ts.cursors[c.name] == c {
+		delete(ts.cursors, c.name)
+	}
+}
+
+// closeCursors closes the cursors of the transaction, except for the first n
+// declared.
+func (ts *txnState) closeCursors(ctx context.Context, n int) {
+	for name, c := range ts.cursors {
+		if c.seq >= n {
+			c.close(ctx)
+			delete(ts.cursors, name)
+		}
+	}
+}
+
+func newUndefinedCursorError(name string) error {
+	return pgerror.NewErrorf(pgerror.CodeInvalidCursorNameError, "cursor %q does not exist", name)
+}
+
+// getCursor returns the cursor with the given name.
+func (p *planner) getCursor(name parser.Name) (*sqlCursor, error) {
+	c, ok := p.session.TxnState.cursors[string(name)]
+	if !ok {
+		return nil, newUndefinedCursorError(string(name))
+	}
+	return c, nil
+}
+
+type declareCursorNode struct {
+	cursor *sqlCursor
+}
+
+// DeclareCursor declares a cursor for a query.
+// Privileges: None.
+//   notes: the privileges needed by the query are checked when it is planned.
+func (p *planner) DeclareCursor(ctx context.Context, n *parser.DeclareCursor) (planNode, error) {
+	if p.session.TxnState.implicitTxn {
+		return nil, pgerror.NewError(pgerror.CodeNoActiveSQLTransactionError,
+			"DECLARE CURSOR can only be used in transaction blocks")
+	}
+	name := string(n.Name)
+	if _, ok := p.session.TxnState.cursors[name]; ok {
+		return nil, pgerror.NewErrorf(pgerror.CodeDuplicateCursorError,
+			"cursor %q already exists", name)
+	}
+	c, err := p.newCursor(ctx, name, n.Select)
+	if err != nil {
+		return nil, err
+	}
+	return &declareCursorNode{cursor: c}, nil
+}
+
+func (n *declareCursorNode) Start(params runParams) error {
+	return params.p.session.TxnState.openCursor(params.ctx, n.cursor)
+}
+
+func (*declareCursorNode) Next(runParams) (bool, error) { return false, nil }
+func (*declareCursorNode) Values() parser.Datums        { return parser.Datums{} }
+
+func (n *declareCursorNode) Close(ctx context.Context) {
+	// Once opened, the cursor is owned by the transaction.
+	if !n.cursor.open {
+		n.cursor.close(ctx)
+	}
+}
+
+// fetchNode retrieves rows from a cursor, for FETCH and MOVE.
+type fetchNode struct {
+	cursor *sqlCursor
+	// count is the maximum number of rows retrieved, unless all is set.
+	count int64
+	all   bool
+	// columns is empty for MOVE, which doesn't return the rows.
+	columns sqlbase.ResultColumns
+	// open is set when the node opens the cursor, for portals.
+	open bool
+
+	fetched int64
+}
+
+// FetchCursor retrieves rows from a cursor.
+// Privileges: None.
+func (p *planner) FetchCursor(ctx context.Context, n *parser.FetchCursor) (planNode, error) {
+	node, err := p.newFetchNode(&n.CursorStmt)
+	if err != nil {
+		return nil, err
+	}
+	node.columns = planColumns(node.cursor.plan)
+	return node, nil
+}
+
+// MoveCursor skips rows of a cursor.
+// Privileges: None.
+func (p *planner) MoveCursor(ctx context.Context, n *parser.MoveCursor) (planNode, error) {
+	return p.newFetchNode(&n.CursorStmt)
+}
+
+func (p *planner) newFetchNode(n *parser.CursorStmt) (*fetchNode, error) {
+	c, err := p.getCursor(n.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !n.All && n.Count < 0 {
+		return nil, pgerror.NewError(pgerror.CodeObjectNotInPrerequisiteStateError,
+			"cursor can only scan forward")
+	}
+	return &fetchNode{cursor: c, count: n.Count, all: n.All}, nil
+}
+
+func (n *fetchNode) Start(params runParams) error {
+	if n.open {
+		return params.p.session.TxnState.openCursor(params.ctx, n.cursor)
+	}
+	return nil
+}
+
+func (n *fetchNode) Next(params runParams) (bool, error) {
+	if !n.all && n.fetched >= n.count {
+		return false, nil
+	}
+	next, err := n.cursor.next(params.ctx)
+	if next {
+		n.fetched++
+	}
+	return next, err
+}
+
+func (n *fetchNode) Values() parser.Datums { return n.cursor.plan.Values() }
+
+func (n *fetchNode) Close(ctx context.Context) {
+	if n.open && !n.cursor.open {
+		n.cursor.close(ctx)
+	}
+}
+
+// CloseCursor closes a cursor, or all of them.
+// Privileges: None.
+func (p *planner) CloseCursor(ctx context.Context, n *parser.CloseCursor) (planNode, error) {
+	ts := &p.session.TxnState
+	if n.Name == "" {
+		ts.closeCursors(ctx, 0)
+		return &zeroNode{}, nil
+	}
+	c, err := p.getCursor(n.Name)
+	if err != nil {
+		return nil, err
+	}
+	ts.closeCursor(ctx, c)
+	return &zeroNode{}, nil
+}
+
+// portalFetch is the statement executing a portal with a row limit. The query
+// of the portal is run by a cursor, which keeps its position across the
+// executions of the portal.
+type portalFetch struct {
+	portal *PreparedPortal
+	name   string
+	limit  int
+}
+
+var _ parser.Statement = &portalFetch{}
+
+// Format implements the NodeFormatter interface.
+func (n *portalFetch) Format(buf *bytes.Buffer, f parser.FmtFlags) {
+	parser.FormatNode(buf, f, n.portal.Stmt.Statement)
+}
+
+// StatementType implements the Statement interface.
+func (*portalFetch) StatementType() parser.StatementType { return parser.Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (n *portalFetch) StatementTag() string { return n.portal.Stmt.Statement.StatementTag() }
+func (n *portalFetch) String() string       { return parser.AsString(n) }
+
+// portalCursorName returns the name of the cursor of the portal with the given
+// name. It starts with a NUL byte so that it cannot be used by a SQL
+// statement.
+func portalCursorName(portalName string) string {
+	return "\x00portal " + portalName
+}
+
+func (p *planner) portalFetch(ctx context.Context, n *portalFetch) (planNode, error) {
+	c := n.portal.cursor
+	open := c == nil
+	if open {
+		var err error
+		c, err = p.newCursor(ctx, portalCursorName(n.name), n.portal.Stmt.Statement)
+		if err != nil {
+			return nil, err
+		}
+		n.portal.cursor = c
+	} else if c.closed {
+		// The transaction the portal was first executed in is over.
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidCursorNameError,
+			"portal %q does not exist", n.name)
+	}
+	return &fetchNode{
+		cursor:  c,
+		count:   int64(n.limit),
+		columns: planColumns(c.plan),
+		open:    open,
+	}, nil
+}
+
+// CanSuspendPortal returns whether the execution of a portal with a row limit
+// can be suspended once the limit is reached, to be resumed by the next
+// execution of the portal. This is the case for the queries executed in a
+// transaction block: an implicit transaction doesn't outlive the execution.
+func (s *Session) CanSuspendPortal(portal *PreparedPortal) bool {
+	if _, ok := portal.Stmt.Statement.(*parser.Select); !ok {
+		return false
+	}
+	return portal.cursor != nil || s.TxnState.State() == Open
+}
@@ -334,6 +334,15 @@ func (p *planner) getDataSource(
 ) (planDataSource, error) {
 	switch t := src.(type) {
 	case *parser.NormalizableTableName:
+		// Is this perhaps the name of a common table expression?
+		ds, foundCTE, err := p.getCTEDataSource(ctx, t)
+		if err != nil {
+			return planDataSource{}, err
+		}
+		if foundCTE {
+			return ds, nil
+		}
+
 		// Usual case: a table.
 		tn, err := p.QualifyWithDatabase(ctx, t)
 		if err != nil {
@@ -562,6 +571,10 @@ func (p *planner) getPlanForDesc(
 				errors.Errorf("cannot specify an explicit column list when accessing a view by reference")
 		}
 		return p.getViewPlan(ctx, tn, desc)
+	} else if desc.IsSequence() {
+		return planDataSource{}, pgerror.NewErrorf(pgerror.CodeWrongObjectTypeError,
+			"cannot read from sequence %q; use nextval(), currval() or lastval() instead",
+			parser.ErrString(tn))
 	} else if !desc.IsTable() {
 		return planDataSource{}, errors.Errorf(
 			"unexpected table descriptor of type %s for %q", desc.TypeName(), parser.ErrString(tn))
@@ -572,6 +585,9 @@ func (p *planner) getPlanForDesc(
 	if err := scan.initTable(p, desc, hints, scanVisibility, wantedColumns); err != nil {
 		return planDataSource{}, err
 	}
+	if err := p.restrictScanToPolicies(ctx, scan, tn); err != nil {
+		return planDataSource{}, err
+	}
 
 	return planDataSource{
 		info: newSourceInfoForSingleTable(*tn, planColumns(scan)),
@@ -624,6 +640,10 @@ func (p *planner) getViewPlan(
 		p.planDeps = nil
 	}
 
+	// The view's query must not see the CTEs of the query that uses the view.
+	defer func(prev cteNameEnvironment) { p.ctes = prev }(p.ctes)
+	p.ctes = nil
+
 	// TODO(a-robinson): Support ORDER BY and LIMIT in views. Is it as simple as
 	// just passing the entire select here or will inserting an ORDER BY in the
 	// middle of a query plan break things?
@@ -671,9 +691,14 @@ func (src *dataSourceInfo) expandStar(
 			"cannot use %q without a FROM clause", parser.ErrString(v))
 	}
 
+	// Stars can only be expanded if all the columns are readable.
+	var unreadable string
 	colSel := func(idx int) {
 		col := src.sourceColumns[idx]
 		if !col.Hidden {
+			if col.Unreadable && unreadable == "" {
+				unreadable = col.Name
+			}
 			ivar := ivarHelper.IndexedVar(idx)
 			columns = append(columns, sqlbase.ResultColumn{Name: col.Name, Typ: ivar.ResolvedType()})
 			exprs = append(exprs, ivar)
@@ -702,12 +727,22 @@ func (src *dataSourceInfo) expandStar(
 			colSel(i)
 		}
 	}
+	if unreadable != "" {
+		return nil, nil, newUnreadableColumnError(unreadable)
+	}
 
 	return columns, exprs, nil
 }
 
 type multiSourceInfo []*dataSourceInfo
 
+// newUnreadableColumnError creates the error returned when a query refers to
+// a column the session user doesn't have the SELECT privilege on.
+func newUnreadableColumnError(name string) error {
+	return pgerror.NewErrorf(pgerror.CodeInsufficientPrivilegeError,
+		"no SELECT privilege on column %q", name)
+}
+
 func newUnknownSourceError(tn *parser.TableName) error {
 	return pgerror.NewErrorf(pgerror.CodeUndefinedTableError,
 		"source name %q not found in FROM clause", parser.ErrString(tn))
@@ -864,6 +899,9 @@ func (sources multiSourceInfo) findColumn(
 			pgerror.NewErrorf(pgerror.CodeUndefinedColumnError,
 				"column name %q not found", parser.ErrString(c))
 	}
+	if sources[srcIdx].sourceColumns[colIdx].Unreadable && !c.ForUpdateOrDelete {
+		return invalidSrcIdx, invalidColIdx, newUnreadableColumnError(colName)
+	}
 
 	return srcIdx, colIdx, nil
 }
@@ -58,10 +58,19 @@ func (p *planner) Delete(
 		return nil, pgerror.NewDangerousStatementErrorf("DELETE without WHERE clause")
 	}
 
+	resetWith, err := p.initWith(ctx, n.With)
+	if err != nil {
+		return nil, err
+	}
+	defer resetWith()
+
 	tn, err := p.getAliasedTableName(n.Table)
 	if err != nil {
 		return nil, err
 	}
+	if err := p.checkEditTargetNotShadowed(tn); err != nil {
+		return nil, err
+	}
 
 	en, err := p.makeEditNode(ctx, tn, privilege.DELETE)
 	if err != nil {
@@ -75,12 +84,12 @@ func (p *planner) Delete(
 		requestedCols = en.tableDesc.Columns
 	}
 
-	fkTables := sqlbase.TablesNeededForFKs(*en.tableDesc, sqlbase.CheckDeletes)
-	if err := p.fillFKTableMap(ctx, fkTables); err != nil {
+	fkTables, err := p.lookupFKTables(ctx, en.tableDesc, sqlbase.CheckDeletes)
+	if err != nil {
 		return nil, err
 	}
 	rd, err := sqlbase.MakeRowDeleter(p.txn, en.tableDesc, fkTables, requestedCols,
-		sqlbase.CheckFKs, &p.alloc)
+		sqlbase.CheckFKs, &p.evalCtx, &p.alloc)
 	if err != nil {
 		return nil, err
 	}
@@ -92,7 +101,7 @@ func (p *planner) Delete(
 	// performs index selection. We cannot perform index selection
 	// properly until the placeholder values are known.
 	rows, err := p.SelectClause(ctx, &parser.SelectClause{
-		Exprs: sqlbase.ColumnsSelectors(rd.FetchCols),
+		Exprs: sqlbase.ColumnsSelectors(rd.FetchCols, true),
 		From:  &parser.From{Tables: []parser.TableExpr{n.Table}},
 		Where: n.Where,
 	}, nil, n.Limit, nil, publicAndNonPublicColumns)
@@ -42,6 +42,11 @@ func (p *planner) Discard(ctx context.Context, s *parser.Discard) (planNode, err
 
 		// DEALLOCATE ALL
 		p.session.PreparedStatements.DeleteAll(ctx)
+
+		// DISCARD SEQUENCES
+		p.session.sequenceState.reset()
+	case parser.DiscardModeSequences:
+		p.session.sequenceState.reset()
 	default:
 		return nil, pgerror.NewErrorf(pgerror.CodeInternalError,
 			"unknown mode for DISCARD: %d", s.Mode)
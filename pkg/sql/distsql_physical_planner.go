@@ -15,6 +15,7 @@
 package sql
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"sort"
@@ -338,6 +339,24 @@ func (dsp *distSQLPlanner) checkSupportForNode(node planNode) (distRecommendatio
 		// Distribute aggregations if possible.
 		return rec.compose(shouldDistribute), nil
 
+	case *windowNode:
+		for _, f := range n.funcs {
+			if _, err := windowerFunc(f); err != nil {
+				return 0, err
+			}
+		}
+		for _, render := range n.windowRender {
+			if err := dsp.checkExpr(render); err != nil {
+				return 0, err
+			}
+		}
+		rec, err := dsp.checkSupportForNode(n.plan)
+		if err != nil {
+			return 0, err
+		}
+		// Distribute window functions if possible.
+		return rec.compose(shouldDistribute), nil
+
 	case *limitNode:
 		if err := dsp.checkExpr(n.countExpr); err != nil {
 			return 0, err
@@ -1622,6 +1641,315 @@ func (dsp *distSQLPlanner) addAggregators(
 	return nil
 }
 
+// windowerFunc returns the function computed by a windower for a window
+// function application, or an error if it isn't supported by DistSQL.
+func windowerFunc(f *windowFuncHolder) (distsqlrun.WindowerSpec_Func, error) {
+	funcStr := strings.ToUpper(f.expr.Func.FunctionReference.String())
+	if f.expr.GetAggregateConstructor() != nil {
+		if f.expr.Type == parser.DistinctFuncType {
+			return distsqlrun.WindowerSpec_Func{}, newQueryNotSupportedError(
+				"DISTINCT window aggregations not supported yet")
+		}
+		if funcStr == "ARRAY_AGG" {
+			return distsqlrun.WindowerSpec_Func{}, newQueryNotSupportedError(
+				"ARRAY_AGG aggregation not supported yet")
+		}
+		funcIdx, ok := distsqlrun.AggregatorSpec_Func_value[funcStr]
+		if !ok {
+			return distsqlrun.WindowerSpec_Func{}, newQueryNotSupportedErrorf(
+				"unsupported window aggregation %s", funcStr)
+		}
+		aggFunc := distsqlrun.AggregatorSpec_Func(funcIdx)
+		return distsqlrun.WindowerSpec_Func{AggregateFunc: &aggFunc}, nil
+	}
+	funcIdx, ok := distsqlrun.WindowerSpec_WindowFunc_value[funcStr]
+	if !ok {
+		return distsqlrun.WindowerSpec_Func{}, newQueryNotSupportedErrorf(
+			"unsupported window function %s", funcStr)
+	}
+	windowFunc := distsqlrun.WindowerSpec_WindowFunc(funcIdx)
+	return distsqlrun.WindowerSpec_Func{WindowFunc: &windowFunc}, nil
+}
+
+// windowerFrame converts the frame of a window function application to its
+// windower specification, evaluating the offsets of its bounds.
+func windowerFrame(
+	evalCtx *parser.EvalContext, f *windowFuncHolder,
+) (*distsqlrun.WindowerSpec_Frame, error) {
+	frame := f.windowDef.Frame
+	if frame == nil {
+		return nil, nil
+	}
+	startOffset, endOffset, err := f.evalFrameOffsets(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	spec := &distsqlrun.WindowerSpec_Frame{Mode: distsqlrun.WindowerSpec_Frame_RANGE}
+	if frame.Mode == parser.RowsFrame {
+		spec.Mode = distsqlrun.WindowerSpec_Frame_ROWS
+	}
+	spec.Start = windowerFrameBound(frame.Bounds.StartBound, startOffset)
+	if frame.Bounds.EndBound != nil {
+		end := windowerFrameBound(frame.Bounds.EndBound, endOffset)
+		spec.End = &end
+	}
+	return spec, nil
+}
+
+func windowerFrameBound(
+	bound *parser.WindowFrameBound, offset parser.Datum,
+) distsqlrun.WindowerSpec_Frame_Bound {
+	var spec distsqlrun.WindowerSpec_Frame_Bound
+	switch bound.BoundType {
+	case parser.UnboundedPreceding:
+		spec.BoundType = distsqlrun.WindowerSpec_Frame_UNBOUNDED_PRECEDING
+	case parser.OffsetPreceding:
+		spec.BoundType = distsqlrun.WindowerSpec_Frame_OFFSET_PRECEDING
+	case parser.CurrentRow:
+		spec.BoundType = distsqlrun.WindowerSpec_Frame_CURRENT_ROW
+	case parser.OffsetFollowing:
+		spec.BoundType = distsqlrun.WindowerSpec_Frame_OFFSET_FOLLOWING
+	case parser.UnboundedFollowing:
+		spec.BoundType = distsqlrun.WindowerSpec_Frame_UNBOUNDED_FOLLOWING
+	default:
+		panic(fmt.Sprintf("unknown window frame bound type %d", bound.BoundType))
+	}
+	if offset != nil {
+		spec.Offset = distsqlplan.MakeExpression(offset, nil)
+	}
+	return spec
+}
+
+// addWindowers adds windowers computing the window functions of a windowNode
+// and updates the plan to reflect the windowNode.
+//
+// The window functions with the same PARTITION BY clause are computed by the
+// same stage of windowers. Each windower outputs its input columns followed by
+// the results of its window functions, so the results of all the window
+// functions accumulate in the streams. If the rows are partitioned and the
+// previous stage has multiple streams, they are hashed by the PARTITION BY
+// columns to a windower on each of the nodes of the previous stage; otherwise,
+// a single windower computes the window functions. The renders of the
+// windowNode are evaluated by the last stage.
+func (dsp *distSQLPlanner) addWindowers(p *physicalPlan, n *windowNode) error {
+	evalCtx := &n.planner.evalCtx
+
+	// funcCols[i] is the stream column of the result of n.funcs[i].
+	funcCols := make([]int, len(n.funcs))
+	planned := make([]bool, len(n.funcs))
+	for i := range n.funcs {
+		if planned[i] {
+			continue
+		}
+		partitionIdxs := n.funcs[i].partitionIdxs
+		spec := distsqlrun.WindowerSpec{PartitionBy: make([]uint32, len(partitionIdxs))}
+		for j, idx := range partitionIdxs {
+			spec.PartitionBy[j] = uint32(p.planToStreamColMap[idx])
+		}
+
+		outTypes := append([]sqlbase.ColumnType(nil), p.ResultTypes...)
+		for j := i; j < len(n.funcs); j++ {
+			f := n.funcs[j]
+			if planned[j] || !sameIntSlice(f.partitionIdxs, partitionIdxs) {
+				continue
+			}
+			fn, err := windowerFunc(f)
+			if err != nil {
+				return err
+			}
+			frame, err := windowerFrame(evalCtx, f)
+			if err != nil {
+				return err
+			}
+			fnSpec := distsqlrun.WindowerSpec_WindowFn{
+				Func:    fn,
+				ArgIdxs: make([]uint32, f.argCount),
+				Frame:   frame,
+			}
+			fnSpec.Ordering.Columns = make([]distsqlrun.Ordering_Column, len(f.columnOrdering))
+			for k, o := range f.columnOrdering {
+				fnSpec.Ordering.Columns[k].ColIdx = uint32(p.planToStreamColMap[o.ColIdx])
+				fnSpec.Ordering.Columns[k].Direction = distsqlrun.Ordering_Column_ASC
+				if o.Direction == encoding.Descending {
+					fnSpec.Ordering.Columns[k].Direction = distsqlrun.Ordering_Column_DESC
+				}
+			}
+			argTypes := make([]sqlbase.ColumnType, f.argCount)
+			for k := range fnSpec.ArgIdxs {
+				streamCol := p.planToStreamColMap[f.argIdxStart+k]
+				fnSpec.ArgIdxs[k] = uint32(streamCol)
+				argTypes[k] = p.ResultTypes[streamCol]
+			}
+			_, retType, err := distsqlrun.GetWindowFuncInfo(fn, argTypes...)
+			if err != nil {
+				return err
+			}
+
+			funcCols[j] = len(outTypes)
+			outTypes = append(outTypes, retType)
+			spec.WindowFns = append(spec.WindowFns, fnSpec)
+			planned[j] = true
+		}
+
+		dsp.addWindowerStage(p, spec, outTypes)
+	}
+
+	// Evaluate the renders of the windowNode.
+	renderExprs := make([]distsqlrun.Expression, len(n.windowRender))
+	curColIdx := 0
+	curFnIdx := 0
+	for i, render := range n.windowRender {
+		if render == nil {
+			// The column is propagated from the wrapped node.
+			renderExprs[i] = distsqlrun.Expression{
+				Expr: fmt.Sprintf("@%d", p.planToStreamColMap[curColIdx]+1),
+			}
+			curColIdx++
+			continue
+		}
+		// Skip the arguments of the window functions of this render; see
+		// windowNode.populateValues.
+		for ; curFnIdx < len(n.funcs) && n.funcs[curFnIdx].argIdxStart == curColIdx; curFnIdx++ {
+			curColIdx += n.funcs[curFnIdx].argCount
+		}
+		expr, err := windowRenderExpr(render, p.planToStreamColMap, funcCols)
+		if err != nil {
+			return err
+		}
+		renderExprs[i] = expr
+	}
+	p.SetLastStagePost(
+		distsqlrun.PostProcessSpec{RenderExprs: renderExprs}, getTypesForPlanResult(n, nil),
+	)
+	p.planToStreamColMap = identityMap(p.planToStreamColMap, len(n.windowRender))
+	return nil
+}
+
+// addWindowerStage adds a stage of windowers with the given spec.
+func (dsp *distSQLPlanner) addWindowerStage(
+	p *physicalPlan, spec distsqlrun.WindowerSpec, outTypes []sqlbase.ColumnType,
+) {
+	core := distsqlrun.ProcessorCoreUnion{Windower: &spec}
+
+	// Check if the previous stage is all on one node.
+	prevStageNode := p.Processors[p.ResultRouters[0]].Node
+	for i := 1; i < len(p.ResultRouters); i++ {
+		if n := p.Processors[p.ResultRouters[i]].Node; n != prevStageNode {
+			prevStageNode = 0
+			break
+		}
+	}
+
+	if len(spec.PartitionBy) == 0 || len(p.ResultRouters) == 1 {
+		// All the rows are in a single partition, or we have a single stream.
+		// Use a single windower. If the previous stage was all on a single node,
+		// put the windower there. Otherwise, bring the results back on this
+		// node.
+		node := dsp.nodeDesc.NodeID
+		if prevStageNode != 0 {
+			node = prevStageNode
+		}
+		p.AddSingleGroupStage(node, core, distsqlrun.PostProcessSpec{}, outTypes)
+		return
+	}
+
+	// We distribute (by the PARTITION BY columns) to multiple processors.
+	for _, resultProc := range p.ResultRouters {
+		p.Processors[resultProc].Spec.Output[0] = distsqlrun.OutputRouterSpec{
+			Type:        distsqlrun.OutputRouterSpec_BY_HASH,
+			HashColumns: spec.PartitionBy,
+		}
+	}
+
+	stageID := p.NewStageID()
+
+	// We have one windower for each result router, on the same node.
+	pIdxStart := distsqlplan.ProcessorIdx(len(p.Processors))
+	for _, resultProc := range p.ResultRouters {
+		proc := distsqlplan.Processor{
+			Node: p.Processors[resultProc].Node,
+			Spec: distsqlrun.ProcessorSpec{
+				Input: []distsqlrun.InputSyncSpec{{
+					// The other fields will be filled in by mergeResultStreams.
+					ColumnTypes: p.ResultTypes,
+				}},
+				Core: core,
+				Output: []distsqlrun.OutputRouterSpec{{
+					Type: distsqlrun.OutputRouterSpec_PASS_THROUGH,
+				}},
+				StageID: stageID,
+			},
+		}
+		p.AddProcessor(proc)
+	}
+
+	// Connect the streams.
+	for bucket := 0; bucket < len(p.ResultRouters); bucket++ {
+		pIdx := pIdxStart + distsqlplan.ProcessorIdx(bucket)
+		p.MergeResultStreams(p.ResultRouters, bucket, distsqlrun.Ordering{}, pIdx, 0)
+	}
+
+	// Set the new result routers.
+	for i := 0; i < len(p.ResultRouters); i++ {
+		p.ResultRouters[i] = pIdxStart + distsqlplan.ProcessorIdx(i)
+	}
+	p.ResultTypes = outTypes
+	p.SetMergeOrdering(orderingTerminated)
+}
+
+// windowRenderExpr converts a render of a windowNode to an expression over the
+// output of the windowers. The render refers to the results of the window
+// functions through windowFuncHolders, and to the columns of the wrapped node
+// through IndexedVars bound to the windowNode's containers.
+func windowRenderExpr(
+	render parser.TypedExpr, planToStreamColMap []int, funcCols []int,
+) (distsqlrun.Expression, error) {
+	expr, err := parser.SimpleVisit(render, func(expr parser.Expr) (error, bool, parser.Expr) {
+		if f, ok := expr.(*windowFuncHolder); ok {
+			return nil, false, parser.NewOrdinalReference(funcCols[f.funcIdx])
+		}
+		return nil, true, expr
+	})
+	if err != nil {
+		return distsqlrun.Expression{}, err
+	}
+
+	fmtFlags := parser.FmtIndexedVarFormat(
+		parser.FmtStarDatumFormat(
+			parser.FmtParsable,
+			func(buf *bytes.Buffer, _ parser.FmtFlags) {
+				fmt.Fprintf(buf, "0")
+			},
+		),
+		func(buf *bytes.Buffer, _ parser.FmtFlags, container parser.IndexedVarContainer, idx int) {
+			switch c := container.(type) {
+			case *windowNodeColContainer:
+				idx = planToStreamColMap[c.idxMap[idx]]
+			case *windowNodeAggContainer:
+				idx = planToStreamColMap[c.idxMap[idx]]
+			}
+			// Ordinal references to the results of the window functions are
+			// already stream columns.
+			fmt.Fprintf(buf, "@%d", idx+1)
+		},
+	)
+	var buf bytes.Buffer
+	parser.FormatNode(&buf, fmtFlags, expr)
+	return distsqlrun.Expression{Expr: buf.String()}, nil
+}
+
+func sameIntSlice(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func (dsp *distSQLPlanner) createPlanForIndexJoin(
 	planCtx *planningCtx, n *indexJoinNode,
 ) (physicalPlan, error) {
@@ -1688,6 +2016,95 @@ ColLoop:
 	return plan, nil
 }
 
+// createPlanForLookupJoin creates a plan for a join whose right side is a scan
+// of a whole table, using JoinReaders which look up the rows of the table by
+// primary key for each row of the left side. It returns false if the join
+// isn't eligible, in which case a regular join should be planned.
+func (dsp *distSQLPlanner) createPlanForLookupJoin(
+	planCtx *planningCtx, n *joinNode,
+) (physicalPlan, bool, error) {
+	scan, ok := n.right.plan.(*scanNode)
+	if !ok || n.joinType != joinTypeInner || n.pred.numMergedEqualityColumns != 0 ||
+		scan.filter != nil || scan.hardLimit != 0 || scan.index.ID != scan.desc.PrimaryIndex.ID ||
+		len(scan.cols) != len(scan.desc.Columns) {
+		return physicalPlan{}, false, nil
+	}
+	// The JoinReader outputs all the columns of the table, so the columns of
+	// the scan must be the columns of the table.
+	for i := range scan.cols {
+		if scan.cols[i].ID != scan.desc.Columns[i].ID {
+			return physicalPlan{}, false, nil
+		}
+	}
+	// Each column of the primary key must be constrained by exactly one
+	// equality.
+	keyCols := scan.desc.PrimaryIndex.ColumnIDs
+	if len(n.pred.rightEqualityIndices) != len(keyCols) {
+		return physicalPlan{}, false, nil
+	}
+	leftColumns, rightColumns := planColumns(n.left.plan), planColumns(scan)
+	lookupCols := make([]int, len(keyCols))
+	for i, colID := range keyCols {
+		found := false
+		for j, rightCol := range n.pred.rightEqualityIndices {
+			leftCol := n.pred.leftEqualityIndices[j]
+			if rightCol == scan.colIdxMap[colID] &&
+				leftColumns[leftCol].Typ.Equivalent(rightColumns[rightCol].Typ) {
+				lookupCols[i] = leftCol
+				found = true
+			}
+		}
+		if !found {
+			return physicalPlan{}, false, nil
+		}
+	}
+
+	plan, err := dsp.createPlanForNode(planCtx, n.left.plan)
+	if err != nil {
+		return physicalPlan{}, false, err
+	}
+
+	joinReaderSpec := distsqlrun.JoinReaderSpec{
+		Table:         *scan.desc,
+		IndexIdx:      0,
+		LookupColumns: make([]uint32, len(lookupCols)),
+	}
+	for i, col := range lookupCols {
+		joinReaderSpec.LookupColumns[i] = uint32(plan.planToStreamColMap[col])
+	}
+
+	// The JoinReader outputs the columns of the left side followed by the
+	// columns of the table.
+	numLeftStreamCols := len(plan.ResultTypes)
+	joinColMap := make([]int, 0, len(n.columns))
+	for i := 0; i < n.pred.numLeftCols; i++ {
+		joinColMap = append(joinColMap, plan.planToStreamColMap[i])
+	}
+	for i := 0; i < n.pred.numRightCols; i++ {
+		joinColMap = append(joinColMap, numLeftStreamCols+i)
+	}
+	post := distsqlrun.PostProcessSpec{
+		Filter:     distsqlplan.MakeExpression(n.pred.onCond, joinColMap),
+		Projection: true,
+	}
+	joinToStreamColMap := makePlanToStreamColMap(len(n.columns))
+	for i, col := range n.columns {
+		if !col.Omitted && joinColMap[i] != -1 {
+			joinToStreamColMap[i] = len(post.OutputColumns)
+			post.OutputColumns = append(post.OutputColumns, uint32(joinColMap[i]))
+		}
+	}
+
+	plan.AddNoGroupingStage(
+		distsqlrun.ProcessorCoreUnion{JoinReader: &joinReaderSpec},
+		post,
+		getTypesForPlanResult(n, joinToStreamColMap),
+		distsqlrun.Ordering{},
+	)
+	plan.planToStreamColMap = joinToStreamColMap
+	return plan, true, nil
+}
+
 // getTypesForPlanResult returns the types of the elements in the result streams
 // of a plan that corresponds to a given planNode. If planToSreamColMap is nil,
 // a 1-1 mapping is assumed.
@@ -1749,6 +2166,12 @@ func (dsp *distSQLPlanner) createPlanForJoin(
 	//
 	//  - The routers of the joiner processors are the result routers of the plan.
 
+	if n.lookupJoin {
+		if plan, ok, err := dsp.createPlanForLookupJoin(planCtx, n); err != nil || ok {
+			return plan, err
+		}
+	}
+
 	leftPlan, err := dsp.createPlanForNode(planCtx, n.left.plan)
 	if err != nil {
 		return physicalPlan{}, err
@@ -2079,6 +2502,18 @@ func (dsp *distSQLPlanner) createPlanForNode(
 
 		return plan, nil
 
+	case *windowNode:
+		plan, err := dsp.createPlanForNode(planCtx, n.plan)
+		if err != nil {
+			return physicalPlan{}, err
+		}
+
+		if err := dsp.addWindowers(&plan, n); err != nil {
+			return physicalPlan{}, err
+		}
+
+		return plan, nil
+
 	case *sortNode:
 		plan, err := dsp.createPlanForNode(planCtx, n.plan)
 		if err != nil {
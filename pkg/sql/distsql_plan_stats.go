@@ -0,0 +1,120 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+const (
+	// statsSampleSize is the number of rows sampled to build the histogram of
+	// a statistic.
+	statsSampleSize = 10000
+	// statsHistogramMaxBuckets is the maximum number of buckets in the
+	// histogram of a statistic.
+	statsHistogramMaxBuckets = 200
+)
+
+var (
+	statsIntType   = sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}
+	statsBytesType = sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_BYTES}
+)
+
+// createPlanForCreateStats creates the physical plan which computes the
+// statistic described by n. The plan has table readers for the table's
+// primary index, each followed by a sampler on the same node, and a single
+// sample aggregator on this node which produces one row with the row count,
+// distinct count, NULL count and histogram of the statistic.
+func (dsp *distSQLPlanner) createPlanForCreateStats(
+	planCtx *planningCtx, p *planner, n *createStatsNode,
+) (physicalPlan, error) {
+	scan := p.Scan()
+	defer scan.Close(planCtx.ctx)
+	if err := scan.initTable(
+		p, n.tableDesc, nil /* indexHints */, publicColumns, nil, /* wantedColumns */
+	); err != nil {
+		return physicalPlan{}, err
+	}
+	// Only read the columns of the statistic.
+	for i := range scan.valNeededForCol {
+		scan.valNeededForCol[i] = false
+	}
+	for _, colID := range n.columnIDs {
+		idx, ok := scan.colIdxMap[colID]
+		if !ok {
+			return physicalPlan{}, errors.Errorf("unknown column ID %d", colID)
+		}
+		scan.valNeededForCol[idx] = true
+	}
+	var err error
+	scan.spans, err = makeSpans(nil /* constraints */, n.tableDesc, scan.index)
+	if err != nil {
+		return physicalPlan{}, err
+	}
+
+	plan, err := dsp.createTableReaders(planCtx, scan, nil /* overrideResultColumns */)
+	if err != nil {
+		return physicalPlan{}, err
+	}
+
+	sketch := distsqlrun.SketchSpec{
+		SketchType: distsqlrun.SketchSpec_HLL_V1,
+		Columns:    make([]uint32, len(n.columnIDs)),
+	}
+	for i, colID := range n.columnIDs {
+		sketch.Columns[i] = uint32(plan.planToStreamColMap[scan.colIdxMap[colID]])
+	}
+	// Histograms are only generated for single-column statistics.
+	if len(n.columnIDs) == 1 {
+		sketch.GenerateHistogram = true
+		sketch.HistogramMaxBuckets = statsHistogramMaxBuckets
+	}
+	sketches := []distsqlrun.SketchSpec{sketch}
+
+	// The samplers output the sampled rows followed by the rank, sketch index,
+	// row count, NULL count and sketch data columns; see SamplerSpec.
+	samplerOutTypes := make([]sqlbase.ColumnType, 0, len(plan.ResultTypes)+5)
+	samplerOutTypes = append(samplerOutTypes, plan.ResultTypes...)
+	samplerOutTypes = append(samplerOutTypes,
+		statsIntType, statsIntType, statsIntType, statsIntType, statsBytesType,
+	)
+	plan.AddNoGroupingStage(
+		distsqlrun.ProcessorCoreUnion{Sampler: &distsqlrun.SamplerSpec{
+			Sketches:   sketches,
+			SampleSize: statsSampleSize,
+		}},
+		distsqlrun.PostProcessSpec{},
+		samplerOutTypes,
+		distsqlrun.Ordering{},
+	)
+
+	// The sample aggregator outputs the row count, distinct count, NULL count
+	// and histogram of each sketch; see SampleAggregatorSpec.
+	aggOutTypes := []sqlbase.ColumnType{statsIntType, statsIntType, statsIntType, statsBytesType}
+	plan.AddSingleGroupStage(
+		dsp.nodeDesc.NodeID,
+		distsqlrun.ProcessorCoreUnion{SampleAggregator: &distsqlrun.SampleAggregatorSpec{
+			Sketches:   sketches,
+			SampleSize: statsSampleSize,
+		}},
+		distsqlrun.PostProcessSpec{},
+		aggOutTypes,
+	)
+	plan.planToStreamColMap = []int{0, 1, 2, 3}
+	return plan, nil
+}
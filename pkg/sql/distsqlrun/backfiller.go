@@ -315,7 +315,9 @@ func ConvertBackfillError(
 			// of mutations if they have the mutation ID we're looking for.
 			break
 		}
-		desc.MakeMutationComplete(mutation)
+		if err := desc.MakeMutationComplete(mutation); err != nil {
+			return err
+		}
 	}
 	return sqlbase.ConvertBatchError(ctx, desc, b)
 }
@@ -34,6 +34,18 @@ type columnBackfiller struct {
 	// updateCols is a slice of all column descriptors that are being modified.
 	updateCols  []sqlbase.ColumnDescriptor
 	updateExprs []parser.TypedExpr
+	// converters holds, for each added column being converted from another
+	// column by ALTER COLUMN TYPE, the converter computing its values and the
+	// index of the source column in the fetched row. The values of the other
+	// added columns are computed by updateExprs.
+	converters  []*sqlbase.ColumnConverter
+	sourceIdxes []int
+	// computedCols computes the values of the added computed columns from the
+	// fetched row; computedIdxes holds, for each added column, the index of
+	// the column in computedCols.Cols, or -1 if it isn't computed.
+	computedCols  *sqlbase.ComputedColumns
+	computedIdxes []int
+	colIdxMap     map[sqlbase.ColumnID]int
 }
 
 var _ Processor = &columnBackfiller{}
@@ -72,6 +84,12 @@ func (cb *columnBackfiller) init() error {
 	// colIdxMap maps ColumnIDs to indices into desc.Columns and desc.Mutations.
 	var colIdxMap map[sqlbase.ColumnID]int
 
+	colIdxMap = make(map[sqlbase.ColumnID]int, len(desc.Columns))
+	for i, c := range desc.Columns {
+		colIdxMap[c.ID] = i
+	}
+
+	var convertFrom []sqlbase.ColumnID
 	if len(desc.Mutations) > 0 {
 		for _, m := range desc.Mutations {
 			if ColumnMutationFilter(m) {
@@ -79,6 +97,7 @@ func (cb *columnBackfiller) init() error {
 				case sqlbase.DescriptorMutation_ADD:
 					desc := *m.GetColumn()
 					cb.added = append(cb.added, desc)
+					convertFrom = append(convertFrom, m.ConvertFromColumnID)
 				case sqlbase.DescriptorMutation_DROP:
 					cb.dropped = append(cb.dropped, *m.GetColumn())
 				}
@@ -90,8 +109,43 @@ func (cb *columnBackfiller) init() error {
 		return err
 	}
 
+	haveConversions := false
+	cb.converters = make([]*sqlbase.ColumnConverter, len(cb.added))
+	cb.sourceIdxes = make([]int, len(cb.added))
+	for j, sourceID := range convertFrom {
+		if sourceID == 0 {
+			continue
+		}
+		idx, ok := colIdxMap[sourceID]
+		if !ok {
+			return errors.Errorf("column %d converted to column %q does not exist",
+				sourceID, cb.added[j].Name)
+		}
+		if cb.converters[j], err = sqlbase.MakeColumnConverter(desc.Columns[idx], cb.added[j]); err != nil {
+			return err
+		}
+		cb.sourceIdxes[j] = idx
+		haveConversions = true
+	}
+
+	if cb.computedCols, err = sqlbase.MakeComputedColumns(&desc, cb.added); err != nil {
+		return err
+	}
+	if cb.computedCols != nil {
+		cb.computedIdxes = make([]int, len(cb.added))
+		k := 0
+		for j := range cb.added {
+			cb.computedIdxes[j] = -1
+			if cb.added[j].IsComputed() {
+				cb.computedIdxes[j] = k
+				k++
+			}
+		}
+	}
+	cb.colIdxMap = colIdxMap
+
 	cb.updateCols = append(cb.added, cb.dropped...)
-	if len(cb.dropped) > 0 || len(defaultExprs) > 0 {
+	if len(cb.dropped) > 0 || len(defaultExprs) > 0 || haveConversions || cb.computedCols != nil {
 		// Populate default values.
 		cb.updateExprs = make([]parser.TypedExpr, len(cb.updateCols))
 		for j := range cb.added {
@@ -112,10 +166,6 @@ func (cb *columnBackfiller) init() error {
 		valNeededForCol[i] = true
 	}
 
-	colIdxMap = make(map[sqlbase.ColumnID]int, len(desc.Columns))
-	for i, c := range desc.Columns {
-		colIdxMap[c.ID] = i
-	}
 	return cb.fetcher.Init(
 		&desc, colIdxMap, &desc.PrimaryIndex, false, false, desc.Columns,
 		valNeededForCol, false, &cb.alloc,
@@ -164,7 +214,7 @@ func (cb *columnBackfiller) runChunk(
 		requestedCols = append(requestedCols, cb.added...)
 		ru, err := sqlbase.MakeRowUpdater(
 			txn, &tableDesc, fkTables, cb.updateCols, requestedCols,
-			sqlbase.RowUpdaterOnlyColumns, &cb.alloc,
+			sqlbase.RowUpdaterOnlyColumns, &cb.flowCtx.EvalCtx, &cb.alloc,
 		)
 		if err != nil {
 			return err
@@ -204,10 +254,21 @@ func (cb *columnBackfiller) runChunk(
 			if row == nil {
 				break
 			}
+			if cb.computedCols != nil {
+				cb.computedCols.LoadRow(cb.colIdxMap, row, false /* merge */)
+			}
 			// Evaluate the new values. This must be done separately for
 			// each row so as to handle impure functions correctly.
 			for j, e := range cb.updateExprs {
-				val, err := e.Eval(&cb.flowCtx.EvalCtx)
+				var val parser.Datum
+				var err error
+				if j < len(cb.added) && cb.converters[j] != nil {
+					val, err = cb.converters[j].Convert(row[cb.sourceIdxes[j]])
+				} else if j < len(cb.added) && cb.computedCols != nil && cb.computedIdxes[j] >= 0 {
+					val, err = cb.computedCols.Compute(&cb.flowCtx.EvalCtx, cb.computedIdxes[j])
+				} else {
+					val, err = e.Eval(&cb.flowCtx.EvalCtx)
+				}
 				if err != nil {
 					return sqlbase.NewInvalidSchemaDefinitionError(err)
 				}
@@ -0,0 +1,170 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// keySetValue is the value stored for every key of a DiskBackedKeySet that
+// was moved to disk. It is not empty so that present keys can be told apart
+// from missing ones, for which SortedDiskMap.Get returns a nil value.
+var keySetValue = []byte{1}
+
+// DiskBackedKeySet is a set of keys, typically encoded rows, that keeps its
+// keys in memory until its memory budget is exhausted, at which point it
+// moves all of its keys to a SortedDiskMap and keeps adding keys there. It is
+// the counterpart of DiskBackedRowContainer for users that need to look up
+// rows, like the deduplication of rows for UNION.
+type DiskBackedKeySet struct {
+	// mem holds the keys while the set is in memory. It is nil once the keys
+	// were moved to diskMap.
+	mem    map[string]struct{}
+	memAcc mon.BoundAccount
+
+	// memMonitor limits the memory used by mem. It is only started if the set
+	// was created with a memory limit.
+	memMonitor    mon.BytesMonitor
+	memMonitorSet bool
+
+	diskMap     engine.SortedDiskMap
+	diskAcc     mon.BoundAccount
+	diskMonitor *mon.BytesMonitor
+	engine      engine.Engine
+}
+
+// MakeDiskBackedKeySet creates a DiskBackedKeySet. Memory usage is accounted
+// for in evalCtx.Mon. If memLimit is positive, the set moves its keys to
+// disk once its memory usage would exceed memLimit bytes. A nil engine
+// disables the fallback to disk, in which case the set only uses memory.
+func MakeDiskBackedKeySet(
+	ctx context.Context,
+	evalCtx *parser.EvalContext,
+	memLimit int64,
+	diskMonitor *mon.BytesMonitor,
+	e engine.Engine,
+) *DiskBackedKeySet {
+	s := &DiskBackedKeySet{
+		mem:         make(map[string]struct{}),
+		diskMonitor: diskMonitor,
+		engine:      e,
+	}
+	memMonitor := evalCtx.Mon
+	if e != nil && memLimit > 0 {
+		s.memMonitor = mon.MakeMonitorInheritWithLimit("keyset-limited", memLimit, evalCtx.Mon)
+		s.memMonitor.Start(ctx, evalCtx.Mon, mon.BoundAccount{})
+		s.memMonitorSet = true
+		memMonitor = &s.memMonitor
+	}
+	s.memAcc = memMonitor.MakeBoundAccount()
+	return s
+}
+
+// NewDiskBackedKeySet creates a DiskBackedKeySet that falls back to the
+// server's temporary storage once the per-processor memory limit
+// (sql.distsql.temp_storage.workmem) is reached.
+func (ds *ServerImpl) NewDiskBackedKeySet(
+	ctx context.Context, evalCtx *parser.EvalContext,
+) *DiskBackedKeySet {
+	return MakeDiskBackedKeySet(
+		ctx, evalCtx, settingWorkMemBytes.Get(&ds.Settings.SV), &ds.diskMonitor, ds.tempStorage,
+	)
+}
+
+// UsingDisk returns whether the set has moved its keys to disk.
+func (s *DiskBackedKeySet) UsingDisk() bool {
+	return s.diskMap != nil
+}
+
+// Add adds the key to the set, moving all the keys to disk first if the key
+// doesn't fit in the memory budget. It returns false if the key was already
+// in the set.
+func (s *DiskBackedKeySet) Add(ctx context.Context, key []byte) (bool, error) {
+	if s.diskMap != nil {
+		return s.addToDisk(ctx, key)
+	}
+	if _, ok := s.mem[string(key)]; ok {
+		return false, nil
+	}
+	if err := s.memAcc.Grow(ctx, int64(len(key))); err != nil {
+		pgErr, ok := pgerror.GetPGCause(err)
+		if !ok || pgErr.Code != pgerror.CodeOutOfMemoryError || s.engine == nil {
+			return false, err
+		}
+		if err := s.spillToDisk(ctx); err != nil {
+			return false, err
+		}
+		return s.addToDisk(ctx, key)
+	}
+	s.mem[string(key)] = struct{}{}
+	return true, nil
+}
+
+// addToDisk adds the key to diskMap, if it isn't there already.
+func (s *DiskBackedKeySet) addToDisk(ctx context.Context, key []byte) (bool, error) {
+	if v, err := s.diskMap.Get(key); err != nil || v != nil {
+		return false, err
+	}
+	if err := s.diskAcc.Grow(ctx, int64(len(key)+len(keySetValue))); err != nil {
+		return false, err
+	}
+	return true, s.diskMap.Put(key, keySetValue)
+}
+
+// spillToDisk moves all the keys currently in memory to a SortedDiskMap,
+// which subsequent keys are then added to.
+func (s *DiskBackedKeySet) spillToDisk(ctx context.Context) error {
+	log.VEventf(ctx, 2, "falling back to disk")
+	s.diskMap = engine.NewRocksDBMap(s.engine)
+	s.diskAcc = s.diskMonitor.MakeBoundAccount()
+
+	b := s.diskMap.NewBatchWriter()
+	for k := range s.mem {
+		if err := s.diskAcc.Grow(ctx, int64(len(k)+len(keySetValue))); err != nil {
+			_ = b.Close(ctx)
+			return err
+		}
+		if err := b.Put([]byte(k), keySetValue); err != nil {
+			_ = b.Close(ctx)
+			return err
+		}
+	}
+	if err := b.Close(ctx); err != nil {
+		return err
+	}
+	s.mem = nil
+	s.memAcc.Clear(ctx)
+	return nil
+}
+
+// Close frees up the resources held by the set.
+func (s *DiskBackedKeySet) Close(ctx context.Context) {
+	if s.diskMap != nil {
+		s.diskMap.Close(ctx)
+		s.diskAcc.Close(ctx)
+	}
+	s.mem = nil
+	s.memAcc.Close(ctx)
+	if s.memMonitorSet {
+		s.memMonitor.Stop(ctx)
+		s.memMonitorSet = false
+	}
+}
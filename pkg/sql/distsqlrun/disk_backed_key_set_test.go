@@ -0,0 +1,89 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestDiskBackedKeySet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	tempEngine, err := engine.NewTempEngine(ctx, base.DefaultTestStoreSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tempEngine.Close()
+
+	evalCtx := parser.MakeTestingEvalContext()
+	diskMonitor := mon.MakeMonitor(
+		"test-disk",
+		mon.DiskResource,
+		nil, /* curCount */
+		nil, /* maxHist */
+		-1,  /* increment: use default block size */
+		math.MaxInt64,
+	)
+	diskMonitor.Start(ctx, nil /* pool */, mon.MakeStandaloneBudget(math.MaxInt64))
+	defer diskMonitor.Stop(ctx)
+
+	const numKeys = 1000
+
+	testCases := []struct {
+		name     string
+		memLimit int64
+		engine   engine.Engine
+		useDisk  bool
+	}{
+		{name: "Memory", memLimit: math.MaxInt64, engine: tempEngine, useDisk: false},
+		{name: "NoEngine", memLimit: 1, engine: nil, useDisk: false},
+		{name: "Disk", memLimit: 1, engine: tempEngine, useDisk: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := MakeDiskBackedKeySet(ctx, &evalCtx, tc.memLimit, &diskMonitor, tc.engine)
+			defer s.Close(ctx)
+
+			// Every key is added twice, and must only be reported as new the
+			// first time.
+			for _, pass := range []bool{true, false} {
+				for i := 0; i < numKeys; i++ {
+					added, err := s.Add(ctx, []byte(fmt.Sprintf("key%04d", i)))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if added != pass {
+						t.Fatalf("key %d: expected added to be %t", i, pass)
+					}
+				}
+			}
+			if s.UsingDisk() != tc.useDisk {
+				t.Fatalf("expected UsingDisk() to be %t", tc.useDisk)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// DiskBackedRowContainer is a row container that keeps its rows in memory
+// until its memory budget is exhausted, at which point it moves all of its
+// rows to a diskRowContainer and keeps adding rows there. Rows are iterated
+// over in the order they were added, unless the container was created with an
+// ordering and sorted.
+//
+// Unlike the other row containers in this package, DiskBackedRowContainer is
+// meant to be usable outside of DistSQL flows, for example by local planNodes
+// that need to buffer an unbounded number of rows.
+type DiskBackedRowContainer struct {
+	// src is the container rows are currently added to. It is either mrc or
+	// drc.
+	src sortableRowContainer
+
+	mrc memRowContainer
+	drc *diskRowContainer
+
+	// memMonitor limits the memory used by mrc. It is only started if the
+	// container was created with a memory limit.
+	memMonitor    mon.BytesMonitor
+	memMonitorSet bool
+
+	diskMonitor *mon.BytesMonitor
+	engine      engine.Engine
+	types       []sqlbase.ColumnType
+	ordering    sqlbase.ColumnOrdering
+
+	numRows int
+}
+
+// MakeDiskBackedRowContainer creates a DiskBackedRowContainer for rows of the
+// given types. Memory usage is accounted for in evalCtx.Mon. If memLimit is
+// positive, the container moves its rows to disk once its memory usage would
+// exceed memLimit bytes. A nil engine disables the fallback to disk, in which
+// case the container behaves like a memory-only container.
+func MakeDiskBackedRowContainer(
+	ctx context.Context,
+	types []sqlbase.ColumnType,
+	evalCtx *parser.EvalContext,
+	memLimit int64,
+	diskMonitor *mon.BytesMonitor,
+	e engine.Engine,
+) *DiskBackedRowContainer {
+	return makeOrderedDiskBackedRowContainer(
+		ctx, nil /* ordering */, types, evalCtx, memLimit, diskMonitor, e,
+	)
+}
+
+// makeOrderedDiskBackedRowContainer is like MakeDiskBackedRowContainer, but
+// the container can be sorted according to the given ordering.
+func makeOrderedDiskBackedRowContainer(
+	ctx context.Context,
+	ordering sqlbase.ColumnOrdering,
+	types []sqlbase.ColumnType,
+	evalCtx *parser.EvalContext,
+	memLimit int64,
+	diskMonitor *mon.BytesMonitor,
+	e engine.Engine,
+) *DiskBackedRowContainer {
+	c := &DiskBackedRowContainer{
+		diskMonitor: diskMonitor,
+		engine:      e,
+		types:       types,
+		ordering:    ordering,
+	}
+	memMonitor := evalCtx.Mon
+	if e != nil && memLimit > 0 {
+		c.memMonitor = mon.MakeMonitorInheritWithLimit("rowcontainer-limited", memLimit, evalCtx.Mon)
+		c.memMonitor.Start(ctx, evalCtx.Mon, mon.BoundAccount{})
+		c.memMonitorSet = true
+		memMonitor = &c.memMonitor
+	}
+	c.mrc.initWithMon(ordering, types, evalCtx, memMonitor)
+	c.src = &c.mrc
+	return c
+}
+
+// NewDiskBackedRowContainer creates a DiskBackedRowContainer that falls back
+// to the server's temporary storage once the per-processor memory limit
+// (sql.distsql.temp_storage.workmem) is reached.
+func (ds *ServerImpl) NewDiskBackedRowContainer(
+	ctx context.Context, types []sqlbase.ColumnType, evalCtx *parser.EvalContext,
+) *DiskBackedRowContainer {
+	return MakeDiskBackedRowContainer(
+		ctx, types, evalCtx, settingWorkMemBytes.Get(&ds.Settings.SV), &ds.diskMonitor, ds.tempStorage,
+	)
+}
+
+// Len returns the number of rows in the container.
+func (c *DiskBackedRowContainer) Len() int {
+	return c.numRows
+}
+
+// UsingDisk returns whether the container has moved its rows to disk.
+func (c *DiskBackedRowContainer) UsingDisk() bool {
+	return c.drc != nil
+}
+
+// AddRow adds a row to the container, moving all the rows to disk first if
+// the row doesn't fit in the memory budget.
+func (c *DiskBackedRowContainer) AddRow(ctx context.Context, row sqlbase.EncDatumRow) error {
+	err := c.src.AddRow(ctx, row)
+	if err != nil {
+		pgErr, ok := pgerror.GetPGCause(err)
+		if !ok || pgErr.Code != pgerror.CodeOutOfMemoryError || c.drc != nil || c.engine == nil {
+			return err
+		}
+		if err := c.spillToDisk(ctx); err != nil {
+			return err
+		}
+		err = c.src.AddRow(ctx, row)
+	}
+	if err == nil {
+		c.numRows++
+	}
+	return err
+}
+
+// spillToDisk moves all the rows currently in memory to a diskRowContainer,
+// which subsequent rows are then added to.
+func (c *DiskBackedRowContainer) spillToDisk(ctx context.Context) error {
+	log.VEventf(ctx, 2, "falling back to disk")
+	drc := makeDiskRowContainer(ctx, c.diskMonitor, c.types, c.ordering, c.engine)
+	c.drc = &drc
+
+	// Transfer the rows from memory to disk. Note that this frees up the
+	// memory taken up by c.mrc.
+	i := c.mrc.NewIterator(ctx)
+	defer i.Close()
+	for i.Rewind(); ; i.Next() {
+		if ok, err := i.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		memRow, err := i.Row()
+		if err != nil {
+			return err
+		}
+		if err := c.drc.AddRow(ctx, memRow); err != nil {
+			return err
+		}
+	}
+	c.mrc.Close(ctx)
+	c.src = c.drc
+	return nil
+}
+
+// Sort sorts the rows according to the ordering the container was created
+// with. Rows on disk are always kept in sorted order.
+func (c *DiskBackedRowContainer) Sort(ctx context.Context) {
+	c.src.Sort(ctx)
+}
+
+// DiskBackedRowIterator iterates over the rows of a DiskBackedRowContainer.
+// See rowIterator for its usage. Rows read from memory are removed from the
+// container as they are iterated over, so a container should only be iterated
+// over once.
+type DiskBackedRowIterator struct {
+	rowIterator
+}
+
+// NewIterator returns an iterator over the rows of the container, in sorted
+// order if the container was sorted and in the order in which they were added
+// otherwise.
+func (c *DiskBackedRowContainer) NewIterator(ctx context.Context) DiskBackedRowIterator {
+	return DiskBackedRowIterator{rowIterator: c.src.NewIterator(ctx)}
+}
+
+// Close frees up the resources held by the container.
+func (c *DiskBackedRowContainer) Close(ctx context.Context) {
+	if c.drc != nil {
+		c.drc.Close(ctx)
+	} else {
+		c.mrc.Close(ctx)
+	}
+	if c.memMonitorSet {
+		c.memMonitor.Stop(ctx)
+		c.memMonitorSet = false
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestDiskBackedRowContainer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	tempEngine, err := engine.NewTempEngine(ctx, base.DefaultTestStoreSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tempEngine.Close()
+
+	evalCtx := parser.MakeTestingEvalContext()
+	diskMonitor := mon.MakeMonitor(
+		"test-disk",
+		mon.DiskResource,
+		nil, /* curCount */
+		nil, /* maxHist */
+		-1,  /* increment: use default block size */
+		math.MaxInt64,
+	)
+	diskMonitor.Start(ctx, nil /* pool */, mon.MakeStandaloneBudget(math.MaxInt64))
+	defer diskMonitor.Stop(ctx)
+
+	columnTypeInt := sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}
+	types := []sqlbase.ColumnType{columnTypeInt}
+	const numRows = 1000
+
+	testCases := []struct {
+		name     string
+		memLimit int64
+		engine   engine.Engine
+		useDisk  bool
+	}{
+		{name: "Memory", memLimit: math.MaxInt64, engine: tempEngine, useDisk: false},
+		{name: "NoEngine", memLimit: 1, engine: nil, useDisk: false},
+		{name: "Disk", memLimit: 1, engine: tempEngine, useDisk: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := MakeDiskBackedRowContainer(ctx, types, &evalCtx, tc.memLimit, &diskMonitor, tc.engine)
+			defer c.Close(ctx)
+
+			for i := 0; i < numRows; i++ {
+				row := sqlbase.EncDatumRow{
+					sqlbase.DatumToEncDatum(columnTypeInt, parser.NewDInt(parser.DInt(i))),
+				}
+				if err := c.AddRow(ctx, row); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if c.Len() != numRows {
+				t.Fatalf("expected %d rows, got %d", numRows, c.Len())
+			}
+			if c.UsingDisk() != tc.useDisk {
+				t.Fatalf("expected UsingDisk() to be %t", tc.useDisk)
+			}
+
+			// The rows must be returned in the order they were added.
+			var alloc sqlbase.DatumAlloc
+			i := c.NewIterator(ctx)
+			defer i.Close()
+			n := 0
+			for i.Rewind(); ; i.Next() {
+				if ok, err := i.Valid(); err != nil {
+					t.Fatal(err)
+				} else if !ok {
+					break
+				}
+				row, err := i.Row()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := row[0].EnsureDecoded(&alloc); err != nil {
+					t.Fatal(err)
+				}
+				if v := int(*row[0].Datum.(*parser.DInt)); v != n {
+					t.Fatalf("expected row %d, got %d", n, v)
+				}
+				n++
+			}
+			if n != numRows {
+				t.Fatalf("expected to read %d rows, got %d", numRows, n)
+			}
+		})
+	}
+}
@@ -106,6 +106,53 @@ func (a *AggregatorSpec) summary() (string, []string) {
 	return "Aggregator", details
 }
 
+func (w *WindowerSpec) summary() (string, []string) {
+	details := make([]string, 0, len(w.WindowFns)+1)
+	if len(w.PartitionBy) > 0 {
+		details = append(details, colListStr(w.PartitionBy))
+	}
+	for _, fn := range w.WindowFns {
+		var buf bytes.Buffer
+		if fn.Func.AggregateFunc != nil {
+			buf.WriteString(fn.Func.AggregateFunc.String())
+		} else if fn.Func.WindowFunc != nil {
+			buf.WriteString(fn.Func.WindowFunc.String())
+		}
+		fmt.Fprintf(&buf, "(%s)", colListStr(fn.ArgIdxs))
+		if len(fn.Ordering.Columns) > 0 {
+			fmt.Fprintf(&buf, " ORDER BY %s", fn.Ordering.diagramString())
+		}
+		if fn.Frame != nil {
+			fmt.Fprintf(&buf, " %s", fn.Frame.Mode)
+		}
+		details = append(details, buf.String())
+	}
+	return "Windower", details
+}
+
+func sketchesSummary(sketches []SketchSpec) []string {
+	details := make([]string, len(sketches))
+	for i, sk := range sketches {
+		details[i] = fmt.Sprintf("%s(%s)", sk.SketchType, colListStr(sk.Columns))
+		if sk.GenerateHistogram {
+			details[i] += fmt.Sprintf(" histogram(%d)", sk.HistogramMaxBuckets)
+		}
+	}
+	return details
+}
+
+func (s *SamplerSpec) summary() (string, []string) {
+	details := append([]string{fmt.Sprintf("SampleSize: %d", s.SampleSize)},
+		sketchesSummary(s.Sketches)...)
+	return "Sampler", details
+}
+
+func (s *SampleAggregatorSpec) summary() (string, []string) {
+	details := append([]string{fmt.Sprintf("SampleSize: %d", s.SampleSize)},
+		sketchesSummary(s.Sketches)...)
+	return "SampleAggregator", details
+}
+
 func (tr *TableReaderSpec) summary() (string, []string) {
 	index := "primary"
 	if tr.IndexIdx > 0 {
@@ -96,7 +96,8 @@ func (ib *indexBackfiller) init() error {
 		if IndexMutationFilter(m) {
 			idx := m.GetIndex()
 			for i, col := range cols {
-				valNeededForCol[i] = valNeededForCol[i] || idx.ContainsColumnID(col.ID)
+				valNeededForCol[i] = valNeededForCol[i] || idx.ContainsColumnID(col.ID) ||
+					(idx.IsPartial() && idx.PredicateColumnID == col.ID)
 			}
 		}
 	}
@@ -130,7 +131,7 @@ func (ib *indexBackfiller) runChunk(
 	for i, m := range mutations {
 		added[i] = *m.GetIndex()
 	}
-	secondaryIndexEntries := make([]sqlbase.IndexEntry, len(mutations))
+	var secondaryIndexEntries []sqlbase.IndexEntry
 
 	buildIndexEntries := func(ctx context.Context, txn *client.Txn) ([]sqlbase.IndexEntry, error) {
 		entries := make([]sqlbase.IndexEntry, 0, chunkSize*int64(len(added)))
@@ -164,9 +165,10 @@ func (ib *indexBackfiller) runChunk(
 			if err := sqlbase.EncDatumRowToDatums(ib.rowVals, encRow, &ib.da); err != nil {
 				return nil, err
 			}
-			if err := sqlbase.EncodeSecondaryIndexes(
+			secondaryIndexEntries, err = sqlbase.EncodeSecondaryIndexes(
 				&ib.spec.Table, added, ib.colIdxMap,
-				ib.rowVals, secondaryIndexEntries); err != nil {
+				ib.rowVals, secondaryIndexEntries[:0])
+			if err != nil {
 				return nil, err
 			}
 			entries = append(entries, secondaryIndexEntries...)
@@ -43,6 +43,19 @@ type joinReader struct {
 	alloc   sqlbase.DatumAlloc
 
 	input RowSource
+
+	// lookupCols are the input columns which form the primary key of the
+	// looked up rows, if we are performing a lookup join; see
+	// JoinReaderSpec.LookupColumns. If empty, the first input columns form the
+	// primary key and only the table rows are output.
+	lookupCols columns
+	// keyCols are the indices of the primary key columns in the rows returned
+	// by the fetcher. Only used for lookup joins.
+	keyCols []int
+	// numInputCols is the number of input columns; the table columns follow
+	// them in the output of a lookup join.
+	numInputCols int
+	rowAlloc     sqlbase.EncDatumRowAlloc
 }
 
 var _ Processor = &joinReader{}
@@ -60,24 +73,61 @@ func newJoinReader(
 	}
 
 	jr := &joinReader{
-		flowCtx: flowCtx,
-		desc:    spec.Table,
-		input:   input,
+		flowCtx:    flowCtx,
+		desc:       spec.Table,
+		input:      input,
+		lookupCols: spec.LookupColumns,
 	}
 
-	types := make([]sqlbase.ColumnType, len(spec.Table.Columns))
-	for i := range types {
-		types[i] = spec.Table.Columns[i].Type
+	var types []sqlbase.ColumnType
+	if jr.isLookupJoin() {
+		inputTypes := input.Types()
+		if len(jr.lookupCols) != len(jr.desc.PrimaryIndex.ColumnIDs) {
+			return nil, errors.Errorf("joinReader has %d lookup columns, expected %d",
+				len(jr.lookupCols), len(jr.desc.PrimaryIndex.ColumnIDs))
+		}
+		for _, col := range jr.lookupCols {
+			if col >= uint32(len(inputTypes)) {
+				return nil, errors.Errorf("invalid lookup column %d", col)
+			}
+		}
+		jr.numInputCols = len(inputTypes)
+		types = append(types, inputTypes...)
+	}
+	for i := range spec.Table.Columns {
+		types = append(types, spec.Table.Columns[i].Type)
 	}
 
 	if err := jr.out.Init(post, types, &flowCtx.EvalCtx, output); err != nil {
 		return nil, err
 	}
 
+	// The table columns needed by the post-processing stage. For lookup joins,
+	// the primary key columns are also needed to match the fetched rows with
+	// the input rows.
+	neededCols := jr.out.neededColumns()[jr.numInputCols:]
+	if jr.isLookupJoin() {
+		jr.keyCols = make([]int, len(jr.desc.PrimaryIndex.ColumnIDs))
+		for i, colID := range jr.desc.PrimaryIndex.ColumnIDs {
+			idx := -1
+			for j := range jr.desc.Columns {
+				if jr.desc.Columns[j].ID == colID {
+					idx = j
+					break
+				}
+			}
+			if idx == -1 {
+				return nil, errors.Errorf("unknown primary key column %d", colID)
+			}
+			jr.keyCols[i] = idx
+			neededCols[idx] = true
+		}
+	}
+
 	var err error
 	jr.index, _, err = initRowFetcher(
 		&jr.fetcher, &jr.desc, int(spec.IndexIdx), false, /* reverse */
-		jr.out.neededColumns(), &jr.alloc,
+		neededCols, &jr.alloc,
 	)
 	if err != nil {
 		return nil, err
@@ -86,6 +136,12 @@ func newJoinReader(
 	return jr, nil
 }
 
+// isLookupJoin returns true if the join reader joins its input rows with the
+// looked up rows, as opposed to only outputting the looked up rows.
+func (jr *joinReader) isLookupJoin() bool {
+	return len(jr.lookupCols) > 0
+}
+
 func (jr *joinReader) generateKey(
 	row sqlbase.EncDatumRow, alloc *sqlbase.DatumAlloc, primaryKeyPrefix []byte,
 ) (roachpb.Key, error) {
@@ -99,6 +155,22 @@ func (jr *joinReader) generateKey(
 	return sqlbase.MakeKeyFromEncDatums(row, &jr.desc, index, primaryKeyPrefix, alloc)
 }
 
+// generateLookupKey generates the primary key of the row looked up for an
+// input row of a lookup join. The returned bool is false if one of the lookup
+// values is NULL, in which case the input row can't have a match.
+func (jr *joinReader) generateLookupKey(
+	row, keyRow sqlbase.EncDatumRow, alloc *sqlbase.DatumAlloc, primaryKeyPrefix []byte,
+) (roachpb.Key, bool, error) {
+	for i, col := range jr.lookupCols {
+		if row[col].IsNull() {
+			return nil, false, nil
+		}
+		keyRow[i] = row[col]
+	}
+	key, err := sqlbase.MakeKeyFromEncDatums(keyRow, &jr.desc, jr.index, primaryKeyPrefix, alloc)
+	return key, err == nil, err
+}
+
 // mainLoop runs the mainLoop and returns any error.
 //
 // If no error is returned, the input has been drained and the output has been
@@ -121,11 +193,26 @@ func (jr *joinReader) mainLoop(ctx context.Context) error {
 		defer log.Infof(ctx, "exiting")
 	}
 
-	for {
+	// For lookup joins, keyRow is used to generate the primary keys, and
+	// lookupRows maps each primary key in the current batch to the input rows
+	// which look it up.
+	var keyRow, outRow sqlbase.EncDatumRow
+	var lookupRows map[string][]sqlbase.EncDatumRow
+	if jr.isLookupJoin() {
+		keyRow = make(sqlbase.EncDatumRow, len(jr.keyCols))
+		outRow = make(sqlbase.EncDatumRow, jr.numInputCols+len(jr.desc.Columns))
+		lookupRows = make(map[string][]sqlbase.EncDatumRow)
+	}
+
+	for inputDone := false; !inputDone; {
 		// TODO(radu): figure out how to send smaller batches if the source has
 		// a soft limit (perhaps send the batch out if we don't get a result
 		// within a certain amount of time).
-		for spans = spans[:0]; len(spans) < joinReaderBatchSize; {
+		spans = spans[:0]
+		for k := range lookupRows {
+			delete(lookupRows, k)
+		}
+		for len(spans) < joinReaderBatchSize {
 			row, meta := jr.input.Next()
 			if !meta.Empty() {
 				if meta.Err != nil {
@@ -137,15 +224,32 @@ func (jr *joinReader) mainLoop(ctx context.Context) error {
 				continue
 			}
 			if row == nil {
-				if len(spans) == 0 {
-					// No fetching needed since we have collected no spans and
-					// the input has signalled that no more records are coming.
-					jr.out.Close()
-					return nil
-				}
+				inputDone = true
 				break
 			}
 
+			if jr.isLookupJoin() {
+				key, ok, err := jr.generateLookupKey(row, keyRow, &alloc, primaryKeyPrefix)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					// A NULL lookup value never matches.
+					continue
+				}
+				rows, seen := lookupRows[string(key)]
+				lookupRows[string(key)] = append(rows, jr.rowAlloc.CopyRow(row))
+				if seen {
+					// The row is already looked up for a previous input row.
+					continue
+				}
+				spans = append(spans, roachpb.Span{
+					Key:    key,
+					EndKey: key.PrefixEnd(),
+				})
+				continue
+			}
+
 			key, err := jr.generateKey(row, &alloc, primaryKeyPrefix)
 			if err != nil {
 				return err
@@ -156,6 +260,10 @@ func (jr *joinReader) mainLoop(ctx context.Context) error {
 				EndKey: key.PrefixEnd(),
 			})
 		}
+		if len(spans) == 0 {
+			// No fetching needed since we have collected no spans.
+			continue
+		}
 
 		err := jr.fetcher.StartScan(ctx, txn, spans, false /* no batch limits */, 0, false /* traceKV */)
 		if err != nil {
@@ -177,19 +285,39 @@ func (jr *joinReader) mainLoop(ctx context.Context) error {
 				break
 			}
 
-			// Emit the row; stop if no more rows are needed.
-			if !emitHelper(ctx, &jr.out, fetcherRow, ProducerMetadata{}, jr.input) {
-				return nil
+			if !jr.isLookupJoin() {
+				// Emit the row; stop if no more rows are needed.
+				if !emitHelper(ctx, &jr.out, fetcherRow, ProducerMetadata{}, jr.input) {
+					return nil
+				}
+				continue
 			}
-		}
 
-		if len(spans) != joinReaderBatchSize {
-			// This was the last batch.
-			sendTraceData(ctx, jr.out.output)
-			jr.out.Close()
-			return nil
+			// Find the input rows which looked up this row by regenerating its
+			// primary key.
+			for i, col := range jr.keyCols {
+				keyRow[i] = fetcherRow[col]
+			}
+			key, err := sqlbase.MakeKeyFromEncDatums(
+				keyRow, &jr.desc, jr.index, primaryKeyPrefix, &alloc,
+			)
+			if err != nil {
+				return err
+			}
+			copy(outRow[jr.numInputCols:], fetcherRow)
+			for _, inputRow := range lookupRows[string(key)] {
+				copy(outRow, inputRow)
+				// Emit the row; stop if no more rows are needed.
+				if !emitHelper(ctx, &jr.out, outRow, ProducerMetadata{}, jr.input) {
+					return nil
+				}
+			}
 		}
 	}
+
+	sendTraceData(ctx, jr.out.output)
+	jr.out.Close()
+	return nil
 }
 
 // Run is part of the processor interface.
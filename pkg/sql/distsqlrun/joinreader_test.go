@@ -152,6 +152,102 @@ func TestJoinReader(t *testing.T) {
 	}
 }
 
+// TestJoinReaderLookupJoin tests a joinReader which joins its input rows with
+// the looked up rows.
+func TestJoinReaderLookupJoin(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.TODO())
+
+	aFn := func(row int) parser.Datum {
+		return parser.NewDInt(parser.DInt(row / 10))
+	}
+	bFn := func(row int) parser.Datum {
+		return parser.NewDInt(parser.DInt(row % 10))
+	}
+	sumFn := func(row int) parser.Datum {
+		return parser.NewDInt(parser.DInt(row/10 + row%10))
+	}
+
+	sqlutils.CreateTable(t, sqlDB, "t",
+		"a INT, b INT, sum INT, s STRING, PRIMARY KEY (a,b)",
+		99,
+		sqlutils.ToRowFn(aFn, bFn, sumFn, sqlutils.RowEnglishFn))
+
+	td := sqlbase.GetTableDescriptor(kvDB, "test", "t")
+
+	evalCtx := parser.MakeTestingEvalContext()
+	defer evalCtx.Stop(context.Background())
+	flowCtx := FlowCtx{
+		EvalCtx:  evalCtx,
+		Settings: cluster.MakeTestingClusterSettings(),
+		// Pass a DB without a TxnCoordSender.
+		txn: client.NewTxn(client.NewDB(s.DistSender(), s.Clock())),
+	}
+
+	intType := sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}
+	types := []sqlbase.ColumnType{intType, intType, intType}
+	// Each input row is (id, b, a); the table rows are looked up on (a, b).
+	input := [][]parser.Datum{
+		{parser.NewDInt(1), bFn(12), aFn(12)},
+		// There is no row with a = 0 and b = 0.
+		{parser.NewDInt(2), parser.NewDInt(0), parser.NewDInt(0)},
+		// A NULL lookup value doesn't match anything.
+		{parser.NewDInt(3), bFn(13), parser.DNull},
+		// This row looks up the same table row as the first one.
+		{parser.NewDInt(4), bFn(12), aFn(12)},
+		{parser.NewDInt(5), bFn(99), aFn(99)},
+	}
+	var rows sqlbase.EncDatumRows
+	for _, row := range input {
+		encRow := make(sqlbase.EncDatumRow, len(row))
+		for i, d := range row {
+			encRow[i] = sqlbase.DatumToEncDatum(intType, d)
+		}
+		rows = append(rows, encRow)
+	}
+	in := NewRowBuffer(types, rows, RowBufferArgs{})
+
+	// The internal columns are the input columns followed by the table columns.
+	post := PostProcessSpec{
+		Projection:    true,
+		OutputColumns: []uint32{0, 5, 6},
+	}
+	spec := JoinReaderSpec{Table: *td, LookupColumns: []uint32{2, 1}}
+	out := &RowBuffer{}
+	jr, err := newJoinReader(&flowCtx, &spec, in, &post, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jr.Run(context.Background(), nil)
+
+	if !in.Done {
+		t.Fatal("joinReader didn't consume all the rows")
+	}
+	if !out.ProducerClosed {
+		t.Fatalf("output RowReceiver not closed")
+	}
+
+	var res sqlbase.EncDatumRows
+	for {
+		row, meta := out.Next()
+		if !meta.Empty() {
+			t.Fatalf("unexpected metadata: %v", meta)
+		}
+		if row == nil {
+			break
+		}
+		res = append(res, row)
+	}
+
+	expected := "[[1 3 'one-two'] [4 3 'one-two'] [5 18 'nine-nine']]"
+	if result := res.String(); result != expected {
+		t.Errorf("invalid results: %s, expected %s'", result, expected)
+	}
+}
+
 // TestJoinReaderDrain tests various scenarios in which a joinReader's consumer
 // is closed.
 func TestJoinReaderDrain(t *testing.T) {
@@ -0,0 +1,299 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+// sampleAggregator combines the samples and sketches produced by multiple
+// samplers into statistics. See SampleAggregatorSpec for the layout of its
+// output rows.
+type sampleAggregator struct {
+	processorBase
+
+	flowCtx *FlowCtx
+	// input is a row source without metadata; the metadata is directed straight
+	// to out.output.
+	input NoMetadataRowSource
+	// rawInput is the true input, not wrapped in a NoMetadataRowSource.
+	rawInput RowSource
+	sr       sampleReservoir
+	sketches []sketchInfo
+
+	// sampledTypes are the types of the sampled row columns of the input.
+	sampledTypes []sqlbase.ColumnType
+}
+
+var _ Processor = &sampleAggregator{}
+
+// sampleAggregatorOutputTypes are the types of the output columns of a
+// sampleAggregator; see SampleAggregatorSpec.
+var sampleAggregatorOutputTypes = []sqlbase.ColumnType{
+	{SemanticType: sqlbase.ColumnType_INT},
+	{SemanticType: sqlbase.ColumnType_INT},
+	{SemanticType: sqlbase.ColumnType_INT},
+	{SemanticType: sqlbase.ColumnType_BYTES},
+}
+
+func newSampleAggregator(
+	flowCtx *FlowCtx,
+	spec *SampleAggregatorSpec,
+	input RowSource,
+	post *PostProcessSpec,
+	output RowReceiver,
+) (*sampleAggregator, error) {
+	inputTypes := input.Types()
+	if len(inputTypes) < numSamplerExtraCols {
+		return nil, errors.Errorf("invalid sample aggregator input with %d columns", len(inputTypes))
+	}
+	numSampledCols := len(inputTypes) - numSamplerExtraCols
+	for _, sk := range spec.Sketches {
+		if sk.SketchType != SketchSpec_HLL_V1 {
+			return nil, errors.Errorf("unsupported sketch type %s", sk.SketchType)
+		}
+		if len(sk.Columns) == 0 {
+			return nil, errors.Errorf("no columns for sketch")
+		}
+		for _, col := range sk.Columns {
+			if col >= uint32(numSampledCols) {
+				return nil, errors.Errorf("invalid sketch column %d", col)
+			}
+		}
+	}
+
+	s := &sampleAggregator{
+		flowCtx:      flowCtx,
+		input:        MakeNoMetadataRowSource(input, output),
+		rawInput:     input,
+		sketches:     make([]sketchInfo, len(spec.Sketches)),
+		sampledTypes: inputTypes[:numSampledCols],
+	}
+	s.sr.init(int(spec.SampleSize))
+	for i := range spec.Sketches {
+		s.sketches[i].spec = spec.Sketches[i]
+	}
+
+	if err := s.out.Init(post, sampleAggregatorOutputTypes, &flowCtx.EvalCtx, output); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Run is part of the processor interface.
+func (s *sampleAggregator) Run(ctx context.Context, wg *sync.WaitGroup) {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	ctx = log.WithLogTag(ctx, "SampleAggregator", nil)
+	ctx, span := processorSpan(ctx, "sample aggregator")
+	defer tracing.FinishSpan(span)
+
+	if log.V(2) {
+		log.Infof(ctx, "starting sample aggregator run")
+		defer log.Infof(ctx, "exiting sample aggregator run")
+	}
+
+	err := s.mainLoop(ctx)
+	if err != nil {
+		log.Errorf(ctx, "error aggregating samples: %s", err)
+	}
+	DrainAndClose(ctx, s.out.output, err, s.rawInput)
+}
+
+func (s *sampleAggregator) mainLoop(ctx context.Context) error {
+	rankCol := len(s.sampledTypes)
+	var da sqlbase.DatumAlloc
+	var tmpSketch hllSketch
+	for {
+		row, err := s.input.NextRow()
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			break
+		}
+
+		// The sketch index column is NULL for sampled rows.
+		sketchIdxDatum, err := decodeSamplerCol(&da, row, rankCol+1)
+		if err != nil {
+			return err
+		}
+		if sketchIdxDatum == parser.DNull {
+			rank, err := decodeSamplerCol(&da, row, rankCol)
+			if err != nil {
+				return err
+			}
+			if err := s.sr.sampleRow(row, s.sampledTypes, int64(*rank.(*parser.DInt))); err != nil {
+				return err
+			}
+			continue
+		}
+
+		sketchIdx := int(*sketchIdxDatum.(*parser.DInt))
+		if sketchIdx < 0 || sketchIdx >= len(s.sketches) {
+			return errors.Errorf("invalid sketch index %d", sketchIdx)
+		}
+		sk := &s.sketches[sketchIdx]
+		numRows, err := decodeSamplerCol(&da, row, rankCol+2)
+		if err != nil {
+			return err
+		}
+		sk.numRows += int64(*numRows.(*parser.DInt))
+		numNulls, err := decodeSamplerCol(&da, row, rankCol+3)
+		if err != nil {
+			return err
+		}
+		sk.numNulls += int64(*numNulls.(*parser.DInt))
+		data, err := decodeSamplerCol(&da, row, rankCol+4)
+		if err != nil {
+			return err
+		}
+		if err := tmpSketch.unmarshalBinary([]byte(*data.(*parser.DBytes))); err != nil {
+			return err
+		}
+		sk.sketch.merge(&tmpSketch)
+	}
+
+	outRow := make(sqlbase.EncDatumRow, len(sampleAggregatorOutputTypes))
+	for i := range s.sketches {
+		sk := &s.sketches[i]
+		histogram := parser.Datum(parser.DNull)
+		if sk.spec.GenerateHistogram {
+			h, err := s.generateHistogram(
+				sk.spec.Columns[0], sk.numRows-sk.numNulls, int(sk.spec.HistogramMaxBuckets),
+			)
+			if err != nil {
+				return err
+			}
+			encoded, err := protoutil.Marshal(&h)
+			if err != nil {
+				return err
+			}
+			histogram = parser.NewDBytes(parser.DBytes(encoded))
+		}
+		datums := parser.Datums{
+			parser.NewDInt(parser.DInt(sk.numRows)),
+			parser.NewDInt(parser.DInt(sk.sketch.estimate())),
+			parser.NewDInt(parser.DInt(sk.numNulls)),
+			histogram,
+		}
+		for j, d := range datums {
+			outRow[j] = sqlbase.DatumToEncDatum(sampleAggregatorOutputTypes[j], d)
+		}
+		if consumerStatus, err := s.out.EmitRow(ctx, outRow); err != nil || consumerStatus != NeedMoreRows {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeSamplerCol returns the decoded value of a column of a sampler output
+// row.
+func decodeSamplerCol(
+	da *sqlbase.DatumAlloc, row sqlbase.EncDatumRow, col int,
+) (parser.Datum, error) {
+	if err := row[col].EnsureDecoded(da); err != nil {
+		return nil, err
+	}
+	return row[col].Datum, nil
+}
+
+// generateHistogram returns a histogram of the values of the given column in
+// the sample, with at most maxBuckets buckets. The counts of the buckets are
+// scaled so that they add up to numRows, the number of non-NULL values in the
+// whole input.
+func (s *sampleAggregator) generateHistogram(
+	col uint32, numRows int64, maxBuckets int,
+) (HistogramData, error) {
+	evalCtx := &s.flowCtx.EvalCtx
+	var values parser.Datums
+	for _, sample := range s.sr.samples {
+		if d := sample.row[col].Datum; d != parser.DNull {
+			values = append(values, d)
+		}
+	}
+	return makeHistogram(evalCtx, values, numRows, maxBuckets)
+}
+
+// makeHistogram returns an equi-depth histogram of the given sampled values
+// (which must not contain NULLs), with at most maxBuckets buckets. The values
+// are sorted in place.
+//
+// Each bucket contains roughly the same number of sampled values; all the
+// values equal to the upper bound of a bucket are part of that bucket. The
+// counts are scaled from the number of sampled values to numRows.
+func makeHistogram(
+	evalCtx *parser.EvalContext, values parser.Datums, numRows int64, maxBuckets int,
+) (HistogramData, error) {
+	var h HistogramData
+	if len(values) == 0 || maxBuckets < 1 {
+		return h, nil
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Compare(evalCtx, values[j]) < 0
+	})
+	if maxBuckets > len(values) {
+		maxBuckets = len(values)
+	}
+	scale := float64(numRows) / float64(len(values))
+
+	for i := 0; i < len(values); {
+		num := (len(values) - i) / (maxBuckets - len(h.Buckets))
+		if num < 1 {
+			num = 1
+		}
+		upper := values[i+num-1]
+		// Find the number of values in the bucket that are less than the upper
+		// bound, and extend the bucket with all the values equal to it.
+		numLess := 0
+		for ; numLess < num-1; numLess++ {
+			if values[i+numLess].Compare(evalCtx, upper) == 0 {
+				break
+			}
+		}
+		end := i + num
+		for ; end < len(values); end++ {
+			if values[end].Compare(evalCtx, upper) != 0 {
+				break
+			}
+		}
+		numEq := end - i - numLess
+
+		encoded, err := sqlbase.EncodeTableKey(nil, upper, encoding.Ascending)
+		if err != nil {
+			return HistogramData{}, err
+		}
+		h.Buckets = append(h.Buckets, HistogramData_Bucket{
+			NumEq:      int64(float64(numEq)*scale + 0.5),
+			NumRange:   int64(float64(numLess)*scale + 0.5),
+			UpperBound: encoded,
+		})
+		i = end
+	}
+	return h, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestSampleAggregator(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	evalCtx := parser.MakeTestingEvalContext()
+	defer evalCtx.Stop(context.Background())
+	flowCtx := FlowCtx{
+		EvalCtx:  evalCtx,
+		Settings: cluster.MakeTestingClusterSettings(),
+	}
+
+	// The input has an INT column with values 0 to 9, each appearing 10 times,
+	// and an INT column which is NULL for half the rows.
+	const numRows = 100
+	columnTypeInt := sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}
+	types := []sqlbase.ColumnType{columnTypeInt, columnTypeInt}
+	rows := make(sqlbase.EncDatumRows, numRows)
+	for i := range rows {
+		b := parser.Datum(parser.DNull)
+		if i%2 == 0 {
+			b = parser.NewDInt(parser.DInt(i))
+		}
+		rows[i] = sqlbase.EncDatumRow{
+			sqlbase.DatumToEncDatum(columnTypeInt, parser.NewDInt(parser.DInt(i%10))),
+			sqlbase.DatumToEncDatum(columnTypeInt, b),
+		}
+	}
+
+	// The sample is large enough to contain all the rows, so the histogram is
+	// exact.
+	sketches := []SketchSpec{
+		{
+			SketchType:          SketchSpec_HLL_V1,
+			Columns:             []uint32{0},
+			GenerateHistogram:   true,
+			HistogramMaxBuckets: 4,
+		},
+		{
+			SketchType: SketchSpec_HLL_V1,
+			Columns:    []uint32{1},
+		},
+	}
+	samplerSpec := SamplerSpec{SampleSize: 1000, Sketches: sketches}
+
+	// Run two samplers, each on half of the rows.
+	var samplerOut sqlbase.EncDatumRows
+	samplerOut = append(samplerOut, runSampler(t, &flowCtx, samplerSpec, types, rows[:numRows/2])...)
+	samplerOut = append(samplerOut, runSampler(t, &flowCtx, samplerSpec, types, rows[numRows/2:])...)
+
+	samplerOutTypes := append(append([]sqlbase.ColumnType(nil), types...),
+		samplerRankType, samplerSketchIdxType, samplerNumRowsType, samplerNumNullsType,
+		samplerSketchDataType,
+	)
+	in := NewRowBuffer(samplerOutTypes, samplerOut, RowBufferArgs{})
+	out := &RowBuffer{}
+	spec := SampleAggregatorSpec{SampleSize: 1000, Sketches: sketches}
+	agg, err := newSampleAggregator(&flowCtx, &spec, in, &PostProcessSpec{}, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agg.Run(context.Background(), nil)
+	if !out.ProducerClosed {
+		t.Fatalf("output RowReceiver not closed")
+	}
+
+	var res []parser.Datums
+	var alloc sqlbase.DatumAlloc
+	for {
+		row, meta := out.Next()
+		if !meta.Empty() {
+			t.Fatalf("unexpected metadata: %v", meta)
+		}
+		if row == nil {
+			break
+		}
+		datums := make(parser.Datums, len(row))
+		for i := range row {
+			if err := row[i].EnsureDecoded(&alloc); err != nil {
+				t.Fatal(err)
+			}
+			datums[i] = row[i].Datum
+		}
+		res = append(res, datums)
+	}
+
+	if len(res) != len(sketches) {
+		t.Fatalf("expected %d rows, got %d", len(sketches), len(res))
+	}
+	expected := []struct{ numRows, distinct, numNulls int64 }{
+		{numRows, 10, 0},
+		{numRows, numRows / 2, numRows / 2},
+	}
+	for i, row := range res {
+		rowCount := int64(*row[0].(*parser.DInt))
+		distinct := int64(*row[1].(*parser.DInt))
+		nullCount := int64(*row[2].(*parser.DInt))
+		if rowCount != expected[i].numRows || nullCount != expected[i].numNulls {
+			t.Errorf("sketch %d: expected %d rows and %d NULLs, got %d and %d",
+				i, expected[i].numRows, expected[i].numNulls, rowCount, nullCount)
+		}
+		if distinct < expected[i].distinct-2 || distinct > expected[i].distinct+2 {
+			t.Errorf("sketch %d: expected ~%d distinct values, got %d", i, expected[i].distinct, distinct)
+		}
+	}
+
+	if res[1][3] != parser.DNull {
+		t.Errorf("expected no histogram, got %s", res[1][3])
+	}
+	var h HistogramData
+	if err := h.Unmarshal([]byte(*res[0][3].(*parser.DBytes))); err != nil {
+		t.Fatal(err)
+	}
+	type bucket struct {
+		numEq, numRange int64
+		upper           int64
+	}
+	expectedBuckets := []bucket{{10, 20, 2}, {10, 20, 5}, {10, 10, 7}, {10, 10, 9}}
+	buckets := make([]bucket, len(h.Buckets))
+	for i, b := range h.Buckets {
+		_, upper, err := encoding.DecodeVarintAscending(b.UpperBound)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buckets[i] = bucket{numEq: b.NumEq, numRange: b.NumRange, upper: upper}
+	}
+	if !reflect.DeepEqual(buckets, expectedBuckets) {
+		t.Errorf("expected buckets %v, got %v", expectedBuckets, buckets)
+	}
+}
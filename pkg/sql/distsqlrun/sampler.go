@@ -0,0 +1,297 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"container/heap"
+	"math/rand"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+// sampledRow is a row of a sample, along with its rank.
+type sampledRow struct {
+	row  sqlbase.EncDatumRow
+	rank int64
+}
+
+// sampleReservoir keeps the rows with the smallest ranks out of all the rows
+// it is given. It implements heap.Interface as a max-heap on the rank, so that
+// the row with the largest rank can be replaced efficiently.
+type sampleReservoir struct {
+	size    int
+	samples []sampledRow
+	alloc   sqlbase.DatumAlloc
+}
+
+var _ heap.Interface = &sampleReservoir{}
+
+func (sr *sampleReservoir) init(size int) {
+	sr.size = size
+	sr.samples = make([]sampledRow, 0, size)
+}
+
+// Len is part of heap.Interface.
+func (sr *sampleReservoir) Len() int { return len(sr.samples) }
+
+// Less is part of heap.Interface.
+func (sr *sampleReservoir) Less(i, j int) bool { return sr.samples[i].rank > sr.samples[j].rank }
+
+// Swap is part of heap.Interface.
+func (sr *sampleReservoir) Swap(i, j int) {
+	sr.samples[i], sr.samples[j] = sr.samples[j], sr.samples[i]
+}
+
+// Push is part of heap.Interface; it should not be called directly.
+func (sr *sampleReservoir) Push(x interface{}) { panic("unimplemented") }
+
+// Pop is part of heap.Interface; it should not be called directly.
+func (sr *sampleReservoir) Pop() interface{} { panic("unimplemented") }
+
+// sampleRow adds a row to the sample if its rank is small enough. The row is
+// copied; it can be reused by the caller.
+func (sr *sampleReservoir) sampleRow(
+	row sqlbase.EncDatumRow, types []sqlbase.ColumnType, rank int64,
+) error {
+	if len(sr.samples) < sr.size {
+		rowCopy, err := sr.copyRow(row, types)
+		if err != nil {
+			return err
+		}
+		sr.samples = append(sr.samples, sampledRow{row: rowCopy, rank: rank})
+		if len(sr.samples) == sr.size {
+			heap.Init(sr)
+		}
+		return nil
+	}
+	if sr.size > 0 && rank < sr.samples[0].rank {
+		rowCopy, err := sr.copyRow(row, types)
+		if err != nil {
+			return err
+		}
+		sr.samples[0] = sampledRow{row: rowCopy, rank: rank}
+		heap.Fix(sr, 0)
+	}
+	return nil
+}
+
+// copyRow makes a copy of a row which doesn't reference the memory of the
+// original row's encoded values.
+func (sr *sampleReservoir) copyRow(
+	row sqlbase.EncDatumRow, types []sqlbase.ColumnType,
+) (sqlbase.EncDatumRow, error) {
+	rowCopy := make(sqlbase.EncDatumRow, len(types))
+	for i := range rowCopy {
+		if err := row[i].EnsureDecoded(&sr.alloc); err != nil {
+			return nil, err
+		}
+		rowCopy[i] = sqlbase.DatumToEncDatum(types[i], row[i].Datum)
+	}
+	return rowCopy, nil
+}
+
+// sketchInfo contains the state of a sketch computed by a sampler or a sample
+// aggregator.
+type sketchInfo struct {
+	spec     SketchSpec
+	sketch   hllSketch
+	numNulls int64
+	numRows  int64
+}
+
+// sampler returns a sample of its input rows and computes sketches on sets of
+// its input columns. See SamplerSpec for the layout of its output rows.
+type sampler struct {
+	processorBase
+
+	flowCtx *FlowCtx
+	// input is a row source without metadata; the metadata is directed straight
+	// to out.output.
+	input NoMetadataRowSource
+	// rawInput is the true input, not wrapped in a NoMetadataRowSource.
+	rawInput RowSource
+	sr       sampleReservoir
+	sketches []sketchInfo
+	rng      *rand.Rand
+
+	// types are the types of the output columns, before post-processing.
+	types []sqlbase.ColumnType
+}
+
+var _ Processor = &sampler{}
+
+// The types of the columns that follow the input columns in the output of a
+// sampler; see SamplerSpec.
+var (
+	samplerRankType       = sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}
+	samplerSketchIdxType  = sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}
+	samplerNumRowsType    = sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}
+	samplerNumNullsType   = sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}
+	samplerSketchDataType = sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_BYTES}
+)
+
+// numSamplerExtraCols is the number of columns that follow the input columns
+// in the output of a sampler.
+const numSamplerExtraCols = 5
+
+func newSampler(
+	flowCtx *FlowCtx, spec *SamplerSpec, input RowSource, post *PostProcessSpec, output RowReceiver,
+) (*sampler, error) {
+	inputTypes := input.Types()
+	for _, sk := range spec.Sketches {
+		if sk.SketchType != SketchSpec_HLL_V1 {
+			return nil, errors.Errorf("unsupported sketch type %s", sk.SketchType)
+		}
+		if len(sk.Columns) == 0 {
+			return nil, errors.Errorf("no columns for sketch")
+		}
+		for _, col := range sk.Columns {
+			if col >= uint32(len(inputTypes)) {
+				return nil, errors.Errorf("invalid sketch column %d", col)
+			}
+		}
+	}
+
+	s := &sampler{
+		flowCtx:  flowCtx,
+		input:    MakeNoMetadataRowSource(input, output),
+		rawInput: input,
+		sketches: make([]sketchInfo, len(spec.Sketches)),
+		rng:      rand.New(rand.NewSource(rand.Int63())),
+	}
+	s.sr.init(int(spec.SampleSize))
+	for i := range spec.Sketches {
+		s.sketches[i].spec = spec.Sketches[i]
+	}
+
+	s.types = make([]sqlbase.ColumnType, len(inputTypes), len(inputTypes)+numSamplerExtraCols)
+	copy(s.types, inputTypes)
+	s.types = append(s.types,
+		samplerRankType, samplerSketchIdxType, samplerNumRowsType, samplerNumNullsType,
+		samplerSketchDataType,
+	)
+
+	if err := s.out.Init(post, s.types, &flowCtx.EvalCtx, output); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Run is part of the processor interface.
+func (s *sampler) Run(ctx context.Context, wg *sync.WaitGroup) {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	ctx = log.WithLogTag(ctx, "Sampler", nil)
+	ctx, span := processorSpan(ctx, "sampler")
+	defer tracing.FinishSpan(span)
+
+	if log.V(2) {
+		log.Infof(ctx, "starting sampler run")
+		defer log.Infof(ctx, "exiting sampler run")
+	}
+
+	err := s.mainLoop(ctx)
+	if err != nil {
+		log.Errorf(ctx, "error sampling rows: %s", err)
+	}
+	DrainAndClose(ctx, s.out.output, err, s.rawInput)
+}
+
+// mainLoop consumes all the input rows, and then emits the sampled rows
+// followed by one row for each sketch.
+func (s *sampler) mainLoop(ctx context.Context) error {
+	inputTypes := s.rawInput.Types()
+	var alloc sqlbase.DatumAlloc
+	var buf []byte
+	for {
+		row, err := s.input.NextRow()
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			break
+		}
+
+		for i := range s.sketches {
+			sk := &s.sketches[i]
+			sk.numRows++
+			if row[sk.spec.Columns[0]].IsNull() {
+				// NULL values are counted but not added to the sketch.
+				sk.numNulls++
+				continue
+			}
+			buf = buf[:0]
+			for _, col := range sk.spec.Columns {
+				buf, err = row[col].Encode(&alloc, sqlbase.DatumEncoding_ASCENDING_KEY, buf)
+				if err != nil {
+					return err
+				}
+			}
+			sk.sketch.insert(buf)
+		}
+
+		// Use Int63 so the rank fits in an INT column.
+		if err := s.sr.sampleRow(row, inputTypes, s.rng.Int63()); err != nil {
+			return err
+		}
+	}
+
+	outRow := make(sqlbase.EncDatumRow, len(s.types))
+	numInputCols := len(inputTypes)
+	for i := range outRow {
+		outRow[i] = sqlbase.DatumToEncDatum(s.types[i], parser.DNull)
+	}
+	for _, sample := range s.sr.samples {
+		copy(outRow, sample.row)
+		outRow[numInputCols] = sqlbase.DatumToEncDatum(
+			samplerRankType, parser.NewDInt(parser.DInt(sample.rank)),
+		)
+		if consumerStatus, err := s.out.EmitRow(ctx, outRow); err != nil || consumerStatus != NeedMoreRows {
+			return err
+		}
+	}
+
+	for i := 0; i <= numInputCols; i++ {
+		outRow[i] = sqlbase.DatumToEncDatum(s.types[i], parser.DNull)
+	}
+	for i := range s.sketches {
+		sk := &s.sketches[i]
+		outRow[numInputCols+1] = sqlbase.DatumToEncDatum(
+			samplerSketchIdxType, parser.NewDInt(parser.DInt(i)),
+		)
+		outRow[numInputCols+2] = sqlbase.DatumToEncDatum(
+			samplerNumRowsType, parser.NewDInt(parser.DInt(sk.numRows)),
+		)
+		outRow[numInputCols+3] = sqlbase.DatumToEncDatum(
+			samplerNumNullsType, parser.NewDInt(parser.DInt(sk.numNulls)),
+		)
+		outRow[numInputCols+4] = sqlbase.DatumToEncDatum(
+			samplerSketchDataType, parser.NewDBytes(parser.DBytes(sk.sketch.marshalBinary())),
+		)
+		if consumerStatus, err := s.out.EmitRow(ctx, outRow); err != nil || consumerStatus != NeedMoreRows {
+			return err
+		}
+	}
+	return nil
+}
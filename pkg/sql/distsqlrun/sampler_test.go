@@ -0,0 +1,170 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// runSampler runs a sampler with the given spec on the given rows, and returns
+// its output rows.
+func runSampler(
+	t *testing.T, flowCtx *FlowCtx, spec SamplerSpec, types []sqlbase.ColumnType, rows sqlbase.EncDatumRows,
+) sqlbase.EncDatumRows {
+	in := NewRowBuffer(types, rows, RowBufferArgs{})
+	out := &RowBuffer{}
+
+	s, err := newSampler(flowCtx, &spec, in, &PostProcessSpec{}, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run(context.Background(), nil)
+	if !out.ProducerClosed {
+		t.Fatalf("output RowReceiver not closed")
+	}
+
+	var res sqlbase.EncDatumRows
+	for {
+		row, meta := out.Next()
+		if !meta.Empty() {
+			t.Fatalf("unexpected metadata: %v", meta)
+		}
+		if row == nil {
+			break
+		}
+		res = append(res, row)
+	}
+	return res
+}
+
+func TestSampler(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	evalCtx := parser.MakeTestingEvalContext()
+	defer evalCtx.Stop(context.Background())
+	flowCtx := FlowCtx{
+		EvalCtx:  evalCtx,
+		Settings: cluster.MakeTestingClusterSettings(),
+	}
+
+	// The input has an INT column with values 0 to numRows-1 and an INT column
+	// which is NULL for every fourth row.
+	const numRows = 100
+	columnTypeInt := sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}
+	types := []sqlbase.ColumnType{columnTypeInt, columnTypeInt}
+	rows := make(sqlbase.EncDatumRows, numRows)
+	for i := range rows {
+		b := parser.Datum(parser.DNull)
+		if i%4 != 0 {
+			b = parser.NewDInt(parser.DInt(i / 2))
+		}
+		rows[i] = sqlbase.EncDatumRow{
+			sqlbase.DatumToEncDatum(columnTypeInt, parser.NewDInt(parser.DInt(i))),
+			sqlbase.DatumToEncDatum(columnTypeInt, b),
+		}
+	}
+
+	for _, sampleSize := range []uint32{0, 10, numRows, 2 * numRows} {
+		t.Run(fmt.Sprintf("SampleSize=%d", sampleSize), func(t *testing.T) {
+			spec := SamplerSpec{
+				SampleSize: sampleSize,
+				Sketches: []SketchSpec{
+					{SketchType: SketchSpec_HLL_V1, Columns: []uint32{0}},
+					{SketchType: SketchSpec_HLL_V1, Columns: []uint32{1}},
+					{SketchType: SketchSpec_HLL_V1, Columns: []uint32{1, 0}},
+				},
+			}
+			out := runSampler(t, &flowCtx, spec, types, rows)
+
+			expSamples := int(sampleSize)
+			if expSamples > numRows {
+				expSamples = numRows
+			}
+			if len(out) != expSamples+len(spec.Sketches) {
+				t.Fatalf("expected %d rows, got %d", expSamples+len(spec.Sketches), len(out))
+			}
+
+			// Verify that the sampled rows are distinct input rows.
+			var alloc sqlbase.DatumAlloc
+			seen := make(map[int64]bool)
+			for _, row := range out[:expSamples] {
+				if len(row) != len(types)+numSamplerExtraCols {
+					t.Fatalf("invalid row %s", row.String())
+				}
+				if err := row[0].EnsureDecoded(&alloc); err != nil {
+					t.Fatal(err)
+				}
+				v := int64(*row[0].Datum.(*parser.DInt))
+				if seen[v] {
+					t.Errorf("row %d sampled twice", v)
+				}
+				seen[v] = true
+				if row[len(types)].IsNull() {
+					t.Errorf("no rank for sampled row %s", row.String())
+				}
+				for col := len(types) + 1; col < len(row); col++ {
+					if !row[col].IsNull() {
+						t.Errorf("invalid sampled row %s", row.String())
+					}
+				}
+			}
+
+			// Verify the sketch rows.
+			expected := []struct{ numRows, numNulls, distinct int64 }{
+				{numRows, 0, numRows},
+				{numRows, numRows / 4, numRows / 2},
+				{numRows, numRows / 4, numRows * 3 / 4},
+			}
+			for i, row := range out[expSamples:] {
+				for col := 0; col <= len(types); col++ {
+					if !row[col].IsNull() {
+						t.Fatalf("invalid sketch row %s", row.String())
+					}
+				}
+				datums := make(parser.Datums, numSamplerExtraCols-1)
+				for j := range datums {
+					if err := row[len(types)+1+j].EnsureDecoded(&alloc); err != nil {
+						t.Fatal(err)
+					}
+					datums[j] = row[len(types)+1+j].Datum
+				}
+				if idx := int(*datums[0].(*parser.DInt)); idx != i {
+					t.Errorf("expected sketch %d, got %d", i, idx)
+				}
+				if n := int64(*datums[1].(*parser.DInt)); n != expected[i].numRows {
+					t.Errorf("sketch %d: expected %d rows, got %d", i, expected[i].numRows, n)
+				}
+				if n := int64(*datums[2].(*parser.DInt)); n != expected[i].numNulls {
+					t.Errorf("sketch %d: expected %d NULLs, got %d", i, expected[i].numNulls, n)
+				}
+				var sk hllSketch
+				if err := sk.unmarshalBinary([]byte(*datums[3].(*parser.DBytes))); err != nil {
+					t.Fatal(err)
+				}
+				if est := sk.estimate(); est < expected[i].distinct-2 || est > expected[i].distinct+2 {
+					t.Errorf("sketch %d: expected ~%d distinct values, got %d", i, expected[i].distinct, est)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"hash/fnv"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// hllPrecision is the number of bits of the hash used to select a register.
+	hllPrecision = 14
+	hllRegisters = 1 << hllPrecision
+
+	// hllVersion is the first byte of the binary format of a sketch, which is
+	// followed by the value of each register.
+	hllVersion = 1
+)
+
+// hllSketch is a HyperLogLog sketch, which estimates the number of distinct
+// values in a multiset using a fixed amount of memory. The estimates have a
+// standard error of about 1%. Two sketches can be merged into a sketch of the
+// union of their multisets, which allows the sketches to be computed in a
+// distributed fashion.
+//
+// See "HyperLogLog: the analysis of a near-optimal cardinality estimation
+// algorithm" by Flajolet et al.
+type hllSketch struct {
+	// Each register stores the maximum "rank" (the position of the leftmost
+	// 1-bit) of the hashes which were assigned to the register.
+	registers [hllRegisters]uint8
+}
+
+// insert adds a value, in encoded form, to the sketch.
+func (s *hllSketch) insert(b []byte) {
+	h := fnv.New64a()
+	_, _ = h.Write(b)
+	s.insertHash(mixHash(h.Sum64()))
+}
+
+func (s *hllSketch) insertHash(x uint64) {
+	idx := x >> (64 - hllPrecision)
+	// Set a sentinel bit so that the rank is at most 64 - hllPrecision + 1.
+	w := x<<hllPrecision | 1<<(hllPrecision-1)
+	if rank := uint8(leadingZeros64(w) + 1); rank > s.registers[idx] {
+		s.registers[idx] = rank
+	}
+}
+
+// merge folds the values of another sketch into s.
+func (s *hllSketch) merge(other *hllSketch) {
+	for i, r := range other.registers {
+		if r > s.registers[i] {
+			s.registers[i] = r
+		}
+	}
+}
+
+// estimate returns the estimated number of distinct values inserted into the
+// sketch.
+func (s *hllSketch) estimate() int64 {
+	const m = float64(hllRegisters)
+	alpha := 0.7213 / (1 + 1.079/m)
+
+	var sum float64
+	zeros := 0
+	for _, r := range s.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+	est := alpha * m * m / sum
+	if est <= 2.5*m && zeros > 0 {
+		// Use linear counting for small cardinalities, where the raw estimate
+		// is biased.
+		est = m * math.Log(m/float64(zeros))
+	}
+	return int64(est + 0.5)
+}
+
+// marshalBinary returns the binary encoding of the sketch.
+func (s *hllSketch) marshalBinary() []byte {
+	b := make([]byte, 1+hllRegisters)
+	b[0] = hllVersion
+	copy(b[1:], s.registers[:])
+	return b
+}
+
+// unmarshalBinary initializes the sketch from its binary encoding.
+func (s *hllSketch) unmarshalBinary(b []byte) error {
+	if len(b) != 1+hllRegisters || b[0] != hllVersion {
+		return errors.Errorf("invalid HyperLogLog sketch encoding (%d bytes)", len(b))
+	}
+	copy(s.registers[:], b[1:])
+	return nil
+}
+
+// mixHash scrambles the bits of a hash value (using the finalizer of
+// MurmurHash3), so that the high bits used by the sketch are well
+// distributed.
+func mixHash(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// leadingZeros64 returns the number of leading zero bits in x.
+//
+// TODO: use math/bits.LeadingZeros64 when we switch to go1.9.
+func leadingZeros64(x uint64) int {
+	if x == 0 {
+		return 64
+	}
+	n := 0
+	for x&(1<<63) == 0 {
+		x <<= 1
+		n++
+	}
+	return n
+}
@@ -0,0 +1,63 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestHLLSketch(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, n := range []int{0, 1, 10, 1000, 100000} {
+		t.Run(fmt.Sprintf("n=%d", n), func(t *testing.T) {
+			// Insert the values into two sketches, with some overlap, and
+			// insert every value multiple times.
+			var a, b hllSketch
+			for i := 0; i < n; i++ {
+				v := encoding.EncodeVarintAscending(nil, int64(i))
+				if i < n*2/3 {
+					a.insert(v)
+					a.insert(v)
+				}
+				if i >= n/3 {
+					b.insert(v)
+				}
+			}
+
+			var merged hllSketch
+			if err := merged.unmarshalBinary(a.marshalBinary()); err != nil {
+				t.Fatal(err)
+			}
+			merged.merge(&b)
+
+			est := merged.estimate()
+			if diff := math.Abs(float64(est - int64(n))); diff > 0.05*float64(n)+1 {
+				t.Errorf("estimate %d too far from %d", est, n)
+			}
+		})
+	}
+
+	var s hllSketch
+	if err := s.unmarshalBinary([]byte{hllVersion}); !testutils.IsError(err, "invalid HyperLogLog sketch") {
+		t.Errorf("expected error, got %v", err)
+	}
+}
@@ -0,0 +1,600 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+// GetWindowFuncInfo returns the window function constructor and the return
+// type for the given window function when applied on the given types.
+func GetWindowFuncInfo(
+	fn WindowerSpec_Func, inputTypes ...sqlbase.ColumnType,
+) (
+	windowConstructor func(*parser.EvalContext) parser.WindowFunc,
+	returnType sqlbase.ColumnType,
+	err error,
+) {
+	var name string
+	var builtins []parser.Builtin
+	switch {
+	case fn.AggregateFunc != nil && *fn.AggregateFunc != AggregatorSpec_IDENT:
+		name = strings.ToLower(fn.AggregateFunc.String())
+		builtins = parser.Aggregates[name]
+	case fn.WindowFunc != nil:
+		name = strings.ToLower(fn.WindowFunc.String())
+		builtins = parser.Builtins[name]
+	default:
+		return nil, sqlbase.ColumnType{}, errors.Errorf("invalid window function %s", fn.String())
+	}
+
+	datumTypes := make([]parser.Type, len(inputTypes))
+	for i := range inputTypes {
+		datumTypes[i] = inputTypes[i].ToDatumType()
+	}
+
+	for _, b := range builtins {
+		types := b.Types.Types()
+		if len(types) != len(inputTypes) {
+			continue
+		}
+		match := true
+		for i, t := range types {
+			if !datumTypes[i].Equivalent(t) {
+				match = false
+				break
+			}
+		}
+		if match {
+			// Found!
+			constructWindow := func(evalCtx *parser.EvalContext) parser.WindowFunc {
+				return b.WindowFunc(datumTypes, evalCtx)
+			}
+
+			colTyp, err := sqlbase.DatumTypeToColumnType(b.FixedReturnType())
+			if err != nil {
+				return nil, sqlbase.ColumnType{}, err
+			}
+			return constructWindow, colTyp, nil
+		}
+	}
+	return nil, sqlbase.ColumnType{}, errors.Errorf(
+		"no builtin window function for %s on %v", name, inputTypes,
+	)
+}
+
+// windowFunc is a window function computed by a windower.
+type windowFunc struct {
+	create   func(*parser.EvalContext) parser.WindowFunc
+	argIdxs  []uint32
+	ordering sqlbase.ColumnOrdering
+
+	// frame is the frame of the window definition, or nil if it has none.
+	// startOffset and endOffset are the values of the offsets of its bounds,
+	// if any. plusOp and minusOp are only set for RANGE frames with offsets.
+	frame                  *parser.WindowFrame
+	startOffset, endOffset parser.Datum
+	plusOp, minusOp        parser.BinOp
+}
+
+// windower computes window functions over partitions of its input rows. All
+// the rows of a partition must be sent to the same windower. Its output rows
+// are its input rows, each followed by the results of the window functions for
+// that row.
+//
+// The windower first sorts all its input rows by the PARTITION BY columns,
+// falling back to disk if they don't fit in memory, and then buffers the rows
+// of one partition at a time to compute the window functions over them. The
+// rows of a partition are buffered on disk too if they don't fit in memory;
+// only the values the window functions are computed over are kept in memory.
+type windower struct {
+	processorBase
+
+	flowCtx *FlowCtx
+	// input is a row source without metadata; the metadata is directed straight
+	// to out.output.
+	input NoMetadataRowSource
+	// rawInput is the true input, not wrapped in a NoMetadataRowSource.
+	rawInput    RowSource
+	partitionBy []uint32
+	funcs       []windowFunc
+	// types are the types of the input columns followed by the result types of
+	// the window functions.
+	types []sqlbase.ColumnType
+	// neededCols are the input columns that are decoded and kept in memory
+	// while a partition is buffered: the PARTITION BY columns and the arguments
+	// and ORDER BY columns of the window functions.
+	neededCols []int
+
+	// testingKnobMemLimit is used in testing to set a limit on the memory that
+	// should be used to sort the input rows and to buffer the rows of a
+	// partition. Minimum value to enable is 1.
+	testingKnobMemLimit int64
+
+	// resultsAcc accounts for the values decoded from the rows of the current
+	// partition and for the results of the window functions over it.
+	resultsAcc mon.BoundAccount
+}
+
+var _ Processor = &windower{}
+
+func newWindower(
+	flowCtx *FlowCtx, spec *WindowerSpec, input RowSource, post *PostProcessSpec, output RowReceiver,
+) (*windower, error) {
+	w := &windower{
+		flowCtx:     flowCtx,
+		input:       MakeNoMetadataRowSource(input, output),
+		rawInput:    input,
+		partitionBy: spec.PartitionBy,
+		funcs:       make([]windowFunc, len(spec.WindowFns)),
+		resultsAcc:  flowCtx.EvalCtx.Mon.MakeBoundAccount(),
+	}
+
+	inputTypes := input.Types()
+	types := make([]sqlbase.ColumnType, len(inputTypes), len(inputTypes)+len(spec.WindowFns))
+	copy(types, inputTypes)
+	for i, fnSpec := range spec.WindowFns {
+		argTypes := make([]sqlbase.ColumnType, len(fnSpec.ArgIdxs))
+		for j, idx := range fnSpec.ArgIdxs {
+			if idx >= uint32(len(inputTypes)) {
+				return nil, errors.Errorf("invalid window function argument column %d", idx)
+			}
+			argTypes[j] = inputTypes[idx]
+		}
+		create, retType, err := GetWindowFuncInfo(fnSpec.Func, argTypes...)
+		if err != nil {
+			return nil, err
+		}
+		fn := windowFunc{
+			create:   create,
+			argIdxs:  fnSpec.ArgIdxs,
+			ordering: convertToColumnOrdering(fnSpec.Ordering),
+		}
+		if fnSpec.Frame != nil {
+			if err := fn.initFrame(&flowCtx.EvalCtx, fnSpec.Frame, inputTypes); err != nil {
+				return nil, err
+			}
+		}
+		w.funcs[i] = fn
+		types = append(types, retType)
+	}
+
+	needed := make([]bool, len(inputTypes))
+	for _, col := range w.partitionBy {
+		needed[col] = true
+	}
+	for _, fn := range w.funcs {
+		for _, idx := range fn.argIdxs {
+			needed[idx] = true
+		}
+		for _, o := range fn.ordering {
+			needed[o.ColIdx] = true
+		}
+	}
+	for col := range needed {
+		if needed[col] {
+			w.neededCols = append(w.neededCols, col)
+		}
+	}
+
+	w.types = types
+	if err := w.out.Init(post, types, &flowCtx.EvalCtx, output); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// initFrame initializes the window frame of the window function from its
+// specification, evaluating the offsets of its bounds.
+func (fn *windowFunc) initFrame(
+	evalCtx *parser.EvalContext, spec *WindowerSpec_Frame, inputTypes []sqlbase.ColumnType,
+) error {
+	fn.frame = &parser.WindowFrame{}
+	switch spec.Mode {
+	case WindowerSpec_Frame_RANGE:
+		fn.frame.Mode = parser.RangeFrame
+	case WindowerSpec_Frame_ROWS:
+		fn.frame.Mode = parser.RowsFrame
+	default:
+		return errors.Errorf("invalid window frame mode %s", spec.Mode)
+	}
+
+	var err error
+	fn.frame.Bounds.StartBound, fn.startOffset, err = convertWindowFrameBound(evalCtx, spec.Start)
+	if err != nil {
+		return err
+	}
+	if spec.End != nil {
+		fn.frame.Bounds.EndBound, fn.endOffset, err = convertWindowFrameBound(evalCtx, *spec.End)
+		if err != nil {
+			return err
+		}
+	}
+
+	if fn.frame.Mode == parser.RangeFrame && (fn.startOffset != nil || fn.endOffset != nil) {
+		// RANGE frames with offsets are computed from the values of the single
+		// ORDER BY column.
+		if len(fn.ordering) != 1 {
+			return errors.Errorf("RANGE with offset PRECEDING/FOLLOWING requires exactly one ORDER BY column")
+		}
+		offset := fn.startOffset
+		if offset == nil {
+			offset = fn.endOffset
+		}
+		ordType := inputTypes[fn.ordering[0].ColIdx].ToDatumType()
+		var ok bool
+		fn.plusOp, fn.minusOp, ok = parser.WindowFrameRangeOps(ordType, offset.ResolvedType())
+		if !ok {
+			return errors.Errorf(
+				"RANGE with offset PRECEDING/FOLLOWING is not supported for column type %s and offset type %s",
+				ordType, offset.ResolvedType(),
+			)
+		}
+	}
+	return nil
+}
+
+// convertWindowFrameBound converts a window frame bound specification to a
+// parser.WindowFrameBound and evaluates its offset, if any.
+func convertWindowFrameBound(
+	evalCtx *parser.EvalContext, spec WindowerSpec_Frame_Bound,
+) (*parser.WindowFrameBound, parser.Datum, error) {
+	bound := &parser.WindowFrameBound{}
+	switch spec.BoundType {
+	case WindowerSpec_Frame_UNBOUNDED_PRECEDING:
+		bound.BoundType = parser.UnboundedPreceding
+	case WindowerSpec_Frame_OFFSET_PRECEDING:
+		bound.BoundType = parser.OffsetPreceding
+	case WindowerSpec_Frame_CURRENT_ROW:
+		bound.BoundType = parser.CurrentRow
+	case WindowerSpec_Frame_OFFSET_FOLLOWING:
+		bound.BoundType = parser.OffsetFollowing
+	case WindowerSpec_Frame_UNBOUNDED_FOLLOWING:
+		bound.BoundType = parser.UnboundedFollowing
+	default:
+		return nil, nil, errors.Errorf("invalid window frame bound type %s", spec.BoundType)
+	}
+	if bound.BoundType != parser.OffsetPreceding && bound.BoundType != parser.OffsetFollowing {
+		return bound, nil, nil
+	}
+
+	h := parser.MakeIndexedVarHelper(nil, 0)
+	expr, err := processExpression(spec.Offset, &h)
+	if err != nil {
+		return nil, nil, err
+	}
+	if expr == nil {
+		return nil, nil, errors.Errorf("missing window frame offset")
+	}
+	offset, err := expr.Eval(evalCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+	bound.OffsetExpr = expr
+	return bound, offset, nil
+}
+
+// Run is part of the processor interface.
+func (w *windower) Run(ctx context.Context, wg *sync.WaitGroup) {
+	if wg != nil {
+		defer wg.Done()
+	}
+	defer w.resultsAcc.Close(ctx)
+
+	ctx = log.WithLogTag(ctx, "Windower", nil)
+	ctx, span := processorSpan(ctx, "windower")
+	defer tracing.FinishSpan(span)
+
+	if log.V(2) {
+		log.Infof(ctx, "starting windower run")
+		defer log.Infof(ctx, "exiting windower run")
+	}
+
+	err := w.mainLoop(ctx)
+	if err != nil {
+		log.Errorf(ctx, "error computing window functions: %s", err)
+	}
+	DrainAndClose(ctx, w.out.output, err, w.rawInput)
+}
+
+// mainLoop sorts the input rows by the PARTITION BY columns and computes the
+// window functions over each partition.
+//
+// It returns once either all the input has been exhausted or the consumer
+// indicated that no more rows are needed.
+func (w *windower) mainLoop(ctx context.Context) error {
+	st := w.flowCtx.Settings
+	types := w.rawInput.Types()
+	ordering := make(sqlbase.ColumnOrdering, len(w.partitionBy))
+	for i, col := range w.partitionBy {
+		ordering[i] = sqlbase.ColumnOrderInfo{ColIdx: int(col), Direction: encoding.Ascending}
+	}
+
+	// Enable fall back to disk if the cluster setting is set or a memory limit
+	// has been set through testing.
+	useTempStorage := settingUseTempStorageSorts.Get(&st.SV) || w.testingKnobMemLimit > 0
+	limit := w.testingKnobMemLimit
+	if limit <= 0 {
+		limit = settingWorkMemBytes.Get(&st.SV)
+	}
+	tempStorage := w.flowCtx.TempStorage
+	if !useTempStorage {
+		tempStorage = nil
+	}
+	rows := makeOrderedDiskBackedRowContainer(
+		ctx, ordering, types, &w.flowCtx.EvalCtx, limit, w.flowCtx.diskMonitor, tempStorage,
+	)
+	defer rows.Close(ctx)
+
+	for {
+		row, err := w.input.NextRow()
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			break
+		}
+		if err := rows.AddRow(ctx, row); err != nil {
+			return err
+		}
+	}
+	rows.Sort(ctx)
+
+	// The rows of a partition are buffered in a container that falls back to
+	// disk while the values needed to compute the window functions over them
+	// are decoded and kept in memory, in vals. A new container is used for
+	// each partition, since a container can only be iterated over once.
+	var partition *DiskBackedRowContainer
+	defer func() {
+		if partition != nil {
+			partition.Close(ctx)
+		}
+	}()
+	var vals []parser.Datums
+	var datumAlloc sqlbase.DatumAlloc
+
+	i := rows.NewIterator(ctx)
+	defer i.Close()
+	for i.Rewind(); ; i.Next() {
+		if ok, err := i.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		row, err := i.Row()
+		if err != nil {
+			return err
+		}
+		if len(vals) > 0 {
+			cmp, err := row.CompareToDatums(&datumAlloc, ordering, &w.flowCtx.EvalCtx, vals[0])
+			if err != nil {
+				return err
+			}
+			if cmp != 0 {
+				// The row starts a new partition.
+				if done, err := w.processPartition(ctx, partition, vals); err != nil || done {
+					return err
+				}
+				partition.Close(ctx)
+				partition = nil
+				vals = nil
+			}
+		}
+		if partition == nil {
+			partition = MakeDiskBackedRowContainer(
+				ctx, types, &w.flowCtx.EvalCtx, limit, w.flowCtx.diskMonitor, tempStorage,
+			)
+		}
+		if err := partition.AddRow(ctx, row); err != nil {
+			return err
+		}
+		rowVals, err := w.decodeNeededCols(ctx, row, &datumAlloc)
+		if err != nil {
+			return err
+		}
+		vals = append(vals, rowVals)
+	}
+	if len(vals) > 0 {
+		if _, err := w.processPartition(ctx, partition, vals); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeNeededCols decodes the values of the row's columns that are needed to
+// compute the window functions over its partition. The values of the other
+// columns are left nil.
+func (w *windower) decodeNeededCols(
+	ctx context.Context, row sqlbase.EncDatumRow, datumAlloc *sqlbase.DatumAlloc,
+) (parser.Datums, error) {
+	vals := make(parser.Datums, len(row))
+	sz := uintptr(len(row)) * unsafe.Sizeof(parser.Datum(nil))
+	for _, col := range w.neededCols {
+		if err := row[col].EnsureDecoded(datumAlloc); err != nil {
+			return nil, err
+		}
+		vals[col] = row[col].Datum
+		sz += vals[col].Size()
+	}
+	if err := w.resultsAcc.Grow(ctx, int64(sz)); err != nil {
+		return nil, err
+	}
+	return vals, nil
+}
+
+// processPartition computes the window functions over the rows of a
+// partition, given the values decoded from them, and emits the rows with their
+// results. It returns true if the consumer doesn't need more rows.
+func (w *windower) processPartition(
+	ctx context.Context, partition *DiskBackedRowContainer, vals []parser.Datums,
+) (bool, error) {
+	defer w.resultsAcc.Clear(ctx)
+
+	evalCtx := &w.flowCtx.EvalCtx
+	rowCount := len(vals)
+	resultsSz := uintptr(rowCount*len(w.funcs)) * unsafe.Sizeof(parser.Datum(nil))
+	if err := w.resultsAcc.Grow(ctx, int64(resultsSz)); err != nil {
+		return false, err
+	}
+	results := make([]parser.Datums, len(w.funcs))
+	for fnIdx := range w.funcs {
+		fn := &w.funcs[fnIdx]
+		results[fnIdx] = make(parser.Datums, rowCount)
+		if err := fn.compute(ctx, evalCtx, vals, results[fnIdx], &w.resultsAcc); err != nil {
+			return false, err
+		}
+	}
+
+	// The rows are iterated over in the order in which they were added to the
+	// partition, which is the order of vals.
+	inputCols := len(w.types) - len(w.funcs)
+	outRow := make(sqlbase.EncDatumRow, inputCols+len(w.funcs))
+	i := partition.NewIterator(ctx)
+	defer i.Close()
+	rowIdx := 0
+	for i.Rewind(); ; i.Next() {
+		if ok, err := i.Valid(); err != nil {
+			return false, err
+		} else if !ok {
+			break
+		}
+		row, err := i.Row()
+		if err != nil {
+			return false, err
+		}
+		copy(outRow, row)
+		for fnIdx, fn := range results {
+			outRow[inputCols+fnIdx] = sqlbase.DatumToEncDatum(
+				w.types[inputCols+fnIdx], fn[rowIdx],
+			)
+		}
+		consumerStatus, err := w.out.EmitRow(ctx, outRow)
+		if err != nil || consumerStatus != NeedMoreRows {
+			return true, err
+		}
+		rowIdx++
+	}
+	return false, nil
+}
+
+// compute computes the window function over the rows of a partition, given
+// the values decoded from them, storing the result for each row in results.
+func (fn *windowFunc) compute(
+	ctx context.Context,
+	evalCtx *parser.EvalContext,
+	vals []parser.Datums,
+	results parser.Datums,
+	acc *mon.BoundAccount,
+) error {
+	rowCount := len(vals)
+	argCount := len(fn.argIdxs)
+	sz := uintptr(rowCount)*unsafe.Sizeof(parser.IndexedRow{}) +
+		uintptr(rowCount*argCount)*unsafe.Sizeof(parser.Datum(nil))
+	if err := acc.Grow(ctx, int64(sz)); err != nil {
+		return err
+	}
+
+	// The rows passed to the window function only contain its arguments.
+	rows := make([]parser.IndexedRow, rowCount)
+	argsAlloc := make(parser.Datums, rowCount*argCount)
+	for i := range rows {
+		row := vals[i]
+		args := argsAlloc[i*argCount : (i+1)*argCount]
+		for j, idx := range fn.argIdxs {
+			args[j] = row[idx]
+		}
+		rows[i] = parser.IndexedRow{Idx: i, Row: args}
+	}
+
+	// Order the rows by the ORDER BY clause. The sort is stable so that window
+	// functions with the same ORDER BY clause see the rows in the same order,
+	// even if the ORDER BY clause doesn't determine a unique ordering.
+	compare := func(i, j int) int {
+		return sqlbase.CompareDatums(
+			fn.ordering, evalCtx, vals[rows[i].Idx], vals[rows[j].Idx],
+		)
+	}
+	if len(fn.ordering) > 0 {
+		sort.SliceStable(rows, func(i, j int) bool { return compare(i, j) < 0 })
+	}
+
+	frameRun := parser.WindowFrameRun{
+		Rows:        rows,
+		ArgIdxStart: 0,
+		ArgCount:    argCount,
+		Frame:       fn.frame,
+		PlusOp:      fn.plusOp,
+		MinusOp:     fn.minusOp,
+		RowIdx:      0,
+	}
+	frameRun.StartBoundOffset, frameRun.EndBoundOffset = fn.startOffset, fn.endOffset
+	if fn.plusOp.ReturnType != nil {
+		// RANGE frames with offsets need the values of the ORDER BY column to
+		// find the bounds of each row's frame.
+		if err := acc.Grow(ctx, int64(uintptr(rowCount)*unsafe.Sizeof(parser.Datum(nil)))); err != nil {
+			return err
+		}
+		ordCol := fn.ordering[0]
+		frameRun.OrdVals = make(parser.Datums, rowCount)
+		for i := range rows {
+			frameRun.OrdVals[i] = vals[rows[i].Idx][ordCol.ColIdx]
+		}
+		frameRun.OrdDescending = ordCol.Direction == encoding.Descending
+	}
+
+	builtin := fn.create(evalCtx)
+	defer builtin.Close(ctx, evalCtx)
+	for frameRun.RowIdx < rowCount {
+		// Compute the size of the current peer group. Without an ORDER BY
+		// clause, all rows of the partition are peers.
+		frameRun.FirstPeerIdx = frameRun.RowIdx
+		frameRun.PeerRowCount = 1
+		for ; frameRun.FirstPeerIdx+frameRun.PeerRowCount < rowCount; frameRun.PeerRowCount++ {
+			cur := frameRun.FirstPeerIdx + frameRun.PeerRowCount
+			if len(fn.ordering) > 0 && compare(cur, cur-1) != 0 {
+				break
+			}
+		}
+
+		// Perform calculations on each row in the current peer group.
+		for ; frameRun.RowIdx < frameRun.FirstPeerIdx+frameRun.PeerRowCount; frameRun.RowIdx++ {
+			res, err := builtin.Compute(ctx, evalCtx, frameRun)
+			if err != nil {
+				return err
+			}
+			// This may overestimate, because WindowFuncs may perform internal
+			// caching.
+			if err := acc.Grow(ctx, int64(res.Size())); err != nil {
+				return err
+			}
+			results[rows[frameRun.RowIdx].Idx] = res
+		}
+	}
+	return nil
+}
@@ -0,0 +1,330 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+
+	"golang.org/x/net/context"
+)
+
+func TestWindower(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	columnTypeInt := sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}
+	v := [7]sqlbase.EncDatum{}
+	for i := range v {
+		v[i] = sqlbase.DatumToEncDatum(columnTypeInt, parser.NewDInt(parser.DInt(i)))
+	}
+
+	rowNumber := WindowerSpec_ROW_NUMBER
+	countRows := AggregatorSpec_COUNT_ROWS
+	maxFn := AggregatorSpec_MAX
+	sumInt := AggregatorSpec_SUM_INT
+	asc := Ordering{Columns: []Ordering_Column{{ColIdx: 1, Direction: Ordering_Column_ASC}}}
+	desc := Ordering{Columns: []Ordering_Column{{ColIdx: 1, Direction: Ordering_Column_DESC}}}
+
+	testCases := []struct {
+		name     string
+		spec     WindowerSpec
+		input    sqlbase.EncDatumRows
+		expected sqlbase.EncDatumRows
+	}{
+		{
+			// SELECT a, b, row_number() OVER (PARTITION BY a ORDER BY b DESC),
+			//        count(*) OVER (PARTITION BY a)
+			name: "Partitioned",
+			spec: WindowerSpec{
+				PartitionBy: []uint32{0},
+				WindowFns: []WindowerSpec_WindowFn{
+					{Func: WindowerSpec_Func{WindowFunc: &rowNumber}, Ordering: desc},
+					{Func: WindowerSpec_Func{AggregateFunc: &countRows}},
+				},
+			},
+			input: sqlbase.EncDatumRows{
+				{v[1], v[3]},
+				{v[0], v[2]},
+				{v[1], v[1]},
+				{v[0], v[4]},
+				{v[1], v[2]},
+			},
+			expected: sqlbase.EncDatumRows{
+				{v[0], v[2], v[2], v[2]},
+				{v[0], v[4], v[1], v[2]},
+				{v[1], v[1], v[3], v[3]},
+				{v[1], v[2], v[2], v[3]},
+				{v[1], v[3], v[1], v[3]},
+			},
+		},
+		{
+			// SELECT a, b, max(b) OVER (ORDER BY b ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING)
+			name: "RowsFrame",
+			spec: WindowerSpec{
+				WindowFns: []WindowerSpec_WindowFn{{
+					Func:     WindowerSpec_Func{AggregateFunc: &maxFn},
+					ArgIdxs:  []uint32{1},
+					Ordering: asc,
+					Frame: &WindowerSpec_Frame{
+						Mode: WindowerSpec_Frame_ROWS,
+						Start: WindowerSpec_Frame_Bound{
+							BoundType: WindowerSpec_Frame_OFFSET_PRECEDING,
+							Offset:    Expression{Expr: "1"},
+						},
+						End: &WindowerSpec_Frame_Bound{
+							BoundType: WindowerSpec_Frame_OFFSET_FOLLOWING,
+							Offset:    Expression{Expr: "1"},
+						},
+					},
+				}},
+			},
+			input: sqlbase.EncDatumRows{
+				{v[0], v[3]},
+				{v[1], v[1]},
+				{v[0], v[5]},
+				{v[1], v[2]},
+				{v[0], v[4]},
+			},
+			expected: sqlbase.EncDatumRows{
+				{v[0], v[3], v[4]},
+				{v[1], v[1], v[2]},
+				{v[0], v[5], v[5]},
+				{v[1], v[2], v[3]},
+				{v[0], v[4], v[5]},
+			},
+		},
+		{
+			// SELECT a, b, sum_int(b) OVER (ORDER BY b RANGE 1 PRECEDING)
+			name: "RangeFrame",
+			spec: WindowerSpec{
+				WindowFns: []WindowerSpec_WindowFn{{
+					Func:     WindowerSpec_Func{AggregateFunc: &sumInt},
+					ArgIdxs:  []uint32{1},
+					Ordering: asc,
+					Frame: &WindowerSpec_Frame{
+						Mode: WindowerSpec_Frame_RANGE,
+						Start: WindowerSpec_Frame_Bound{
+							BoundType: WindowerSpec_Frame_OFFSET_PRECEDING,
+							Offset:    Expression{Expr: "1"},
+						},
+					},
+				}},
+			},
+			input: sqlbase.EncDatumRows{
+				{v[0], v[1]},
+				{v[0], v[2]},
+				{v[1], v[2]},
+				{v[1], v[4]},
+			},
+			expected: sqlbase.EncDatumRows{
+				{v[0], v[1], v[1]},
+				{v[0], v[2], v[5]},
+				{v[1], v[2], v[5]},
+				{v[1], v[4], v[4]},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	tempEngine, err := engine.NewTempEngine(ctx, base.DefaultTestStoreSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tempEngine.Close()
+
+	evalCtx := parser.MakeTestingEvalContext()
+	defer evalCtx.Stop(ctx)
+	diskMonitor := mon.MakeMonitor(
+		"test-disk",
+		mon.DiskResource,
+		nil, /* curCount */
+		nil, /* maxHist */
+		-1,  /* increment: use default block size */
+		math.MaxInt64,
+	)
+	diskMonitor.Start(ctx, nil /* pool */, mon.MakeStandaloneBudget(math.MaxInt64))
+	defer diskMonitor.Stop(ctx)
+	flowCtx := FlowCtx{
+		EvalCtx:     evalCtx,
+		Settings:    cluster.MakeTestingClusterSettings(),
+		TempStorage: tempEngine,
+		diskMonitor: &diskMonitor,
+	}
+
+	// rowsString returns a string representation of rows which doesn't depend
+	// on their order, since the order of the rows within a partition is not
+	// defined.
+	rowsString := func(rows sqlbase.EncDatumRows) string {
+		strs := make([]string, len(rows))
+		for i, row := range rows {
+			strs[i] = row.String()
+		}
+		sort.Strings(strs)
+		return strings.Join(strs, " ")
+	}
+
+	for _, c := range testCases {
+		// Test with several memory limits:
+		// 0: Use the default limit.
+		// 1: Immediately switch to disk.
+		for _, memLimit := range []int64{0, 1} {
+			t.Run(fmt.Sprintf("%sMemLimit=%d", c.name, memLimit), func(t *testing.T) {
+				types := make([]sqlbase.ColumnType, len(c.input[0]))
+				for i := range types {
+					types[i] = c.input[0][i].Type
+				}
+				in := NewRowBuffer(types, c.input, RowBufferArgs{})
+				out := &RowBuffer{}
+
+				w, err := newWindower(&flowCtx, &c.spec, in, &PostProcessSpec{}, out)
+				if err != nil {
+					t.Fatal(err)
+				}
+				w.testingKnobMemLimit = memLimit
+				w.Run(ctx, nil)
+				if !out.ProducerClosed {
+					t.Fatalf("output RowReceiver not closed")
+				}
+
+				var retRows sqlbase.EncDatumRows
+				for {
+					row, meta := out.Next()
+					if !meta.Empty() {
+						t.Fatalf("unexpected metadata: %v", meta)
+					}
+					if row == nil {
+						break
+					}
+					retRows = append(retRows, row)
+				}
+
+				if expStr, retStr := rowsString(c.expected), rowsString(retRows); expStr != retStr {
+					t.Errorf("invalid results; expected:\n   %s\ngot:\n   %s", expStr, retStr)
+				}
+			})
+		}
+	}
+}
+
+// TestWindowerPartitionOnDisk verifies that the rows of a partition that
+// doesn't fit in the memory budget of the flow are buffered on disk.
+func TestWindowerPartitionOnDisk(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numRows = 200
+	columnTypeInt := sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}
+	columnTypeString := sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_STRING}
+	types := []sqlbase.ColumnType{columnTypeInt, columnTypeInt, columnTypeString}
+	padding := parser.NewDString(strings.Repeat("a", 1024))
+	input := make(sqlbase.EncDatumRows, numRows)
+	for i := range input {
+		input[i] = sqlbase.EncDatumRow{
+			sqlbase.DatumToEncDatum(columnTypeInt, parser.NewDInt(0)),
+			sqlbase.DatumToEncDatum(columnTypeInt, parser.NewDInt(parser.DInt(numRows-i))),
+			sqlbase.DatumToEncDatum(columnTypeString, padding),
+		}
+	}
+
+	ctx := context.Background()
+	tempEngine, err := engine.NewTempEngine(ctx, base.DefaultTestStoreSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tempEngine.Close()
+
+	evalCtx := parser.MakeTestingEvalContext()
+	defer evalCtx.Stop(ctx)
+	diskMonitor := mon.MakeMonitor(
+		"test-disk",
+		mon.DiskResource,
+		nil, /* curCount */
+		nil, /* maxHist */
+		-1,  /* increment: use default block size */
+		math.MaxInt64,
+	)
+	diskMonitor.Start(ctx, nil /* pool */, mon.MakeStandaloneBudget(math.MaxInt64))
+	defer diskMonitor.Stop(ctx)
+	// The memory budget of the flow is a fraction of the size of the
+	// partition's rows, but is enough for the values the window function is
+	// computed over.
+	memMonitor := mon.MakeMonitorInheritWithLimit("test-limited", 64<<10, evalCtx.Mon)
+	memMonitor.Start(ctx, evalCtx.Mon, mon.BoundAccount{})
+	defer memMonitor.Stop(ctx)
+	flowCtx := FlowCtx{
+		EvalCtx:     evalCtx,
+		Settings:    cluster.MakeTestingClusterSettings(),
+		TempStorage: tempEngine,
+		diskMonitor: &diskMonitor,
+	}
+	flowCtx.EvalCtx.Mon = &memMonitor
+
+	// SELECT a, b, c, row_number() OVER (PARTITION BY a ORDER BY b)
+	rowNumber := WindowerSpec_ROW_NUMBER
+	spec := WindowerSpec{
+		PartitionBy: []uint32{0},
+		WindowFns: []WindowerSpec_WindowFn{{
+			Func: WindowerSpec_Func{WindowFunc: &rowNumber},
+			Ordering: Ordering{
+				Columns: []Ordering_Column{{ColIdx: 1, Direction: Ordering_Column_ASC}},
+			},
+		}},
+	}
+	in := NewRowBuffer(types, input, RowBufferArgs{})
+	out := &RowBuffer{}
+	w, err := newWindower(&flowCtx, &spec, in, &PostProcessSpec{}, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.testingKnobMemLimit = 1
+	w.Run(ctx, nil)
+	if !out.ProducerClosed {
+		t.Fatalf("output RowReceiver not closed")
+	}
+
+	var datumAlloc sqlbase.DatumAlloc
+	count := 0
+	for {
+		row, meta := out.Next()
+		if !meta.Empty() {
+			t.Fatalf("unexpected metadata: %v", meta)
+		}
+		if row == nil {
+			break
+		}
+		count++
+		for _, idx := range []int{1, 3} {
+			if err := row[idx].EnsureDecoded(&datumAlloc); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if b, rn := *row[1].Datum.(*parser.DInt), *row[3].Datum.(*parser.DInt); b != rn {
+			t.Errorf("expected row_number %d for row %s, got %d", b, row, rn)
+		}
+	}
+	if count != numRows {
+		t.Errorf("expected %d rows, got %d", numRows, count)
+	}
+}
@@ -369,6 +369,26 @@ func (p *planner) dropIndexByName(
 	if !found {
 		return fmt.Errorf("index %q in the middle of being added, try again later", idxName)
 	}
+	// Drop the hidden columns storing the values of the index expressions
+	// and of the predicate of a partial index.
+	hiddenColIDs := make([]sqlbase.ColumnID, 0, len(idx.ColumnIDs)+1)
+	for i, colID := range idx.ColumnIDs {
+		if idx.IsExprColumn(i) {
+			hiddenColIDs = append(hiddenColIDs, colID)
+		}
+	}
+	if idx.IsPartial() {
+		hiddenColIDs = append(hiddenColIDs, idx.PredicateColumnID)
+	}
+	for _, colID := range hiddenColIDs {
+		for j := range tableDesc.Columns {
+			if tableDesc.Columns[j].ID == colID {
+				tableDesc.AddColumnMutation(tableDesc.Columns[j], sqlbase.DescriptorMutation_DROP)
+				tableDesc.Columns = append(tableDesc.Columns[:j], tableDesc.Columns[j+1:]...)
+				break
+			}
+		}
+	}
 
 	if err := tableDesc.Validate(ctx, p.txn); err != nil {
 		return err
@@ -749,6 +769,83 @@ func (*dropTableNode) Next(runParams) (bool, error) { return false, nil }
 func (*dropTableNode) Close(context.Context)        {}
 func (*dropTableNode) Values() parser.Datums        { return parser.Datums{} }
 
+type dropSequenceNode struct {
+	n  *parser.DropSequence
+	td []*sqlbase.TableDescriptor
+}
+
+// DropSequence drops a sequence.
+// Privileges: DROP on sequence.
+//   Notes: postgres allows only the sequence owner to DROP a sequence.
+func (p *planner) DropSequence(ctx context.Context, n *parser.DropSequence) (planNode, error) {
+	td := make([]*sqlbase.TableDescriptor, 0, len(n.Names))
+	for _, name := range n.Names {
+		tn, err := name.NormalizeTableName()
+		if err != nil {
+			return nil, err
+		}
+		if err := tn.QualifyWithDatabase(p.session.Database); err != nil {
+			return nil, err
+		}
+
+		droppedDesc, err := p.dropTableOrViewPrepare(ctx, tn)
+		if err != nil {
+			return nil, err
+		}
+		if droppedDesc == nil {
+			if n.IfExists {
+				continue
+			}
+			// Sequence does not exist, but we want it to: error out.
+			return nil, sqlbase.NewUndefinedRelationError(tn)
+		}
+		if !droppedDesc.IsSequence() {
+			return nil, sqlbase.NewWrongObjectTypeError(tn, "sequence")
+		}
+		td = append(td, droppedDesc)
+	}
+
+	if len(td) == 0 {
+		return &zeroNode{}, nil
+	}
+	return &dropSequenceNode{n: n, td: td}, nil
+}
+
+func (n *dropSequenceNode) Start(params runParams) error {
+	ctx := params.ctx
+	for _, droppedDesc := range n.td {
+		if err := params.p.initiateDropTable(ctx, droppedDesc); err != nil {
+			return err
+		}
+		descID := droppedDesc.ID
+		params.p.session.setTestingVerifyMetadata(func(systemConfig config.SystemConfig) error {
+			return verifyDropTableMetadata(systemConfig, descID, "sequence")
+		})
+		// Log a Drop Sequence event for this sequence. This is an auditable log
+		// event and is recorded in the same transaction as the table descriptor
+		// update.
+		if err := MakeEventLogger(params.p.LeaseMgr()).InsertEventRecord(
+			ctx,
+			params.p.txn,
+			EventLogDropSequence,
+			int32(droppedDesc.ID),
+			int32(params.p.evalCtx.NodeID),
+			struct {
+				SequenceName string
+				Statement    string
+				User         string
+			}{droppedDesc.Name, n.n.String(), params.p.session.User},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropSequenceNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropSequenceNode) Close(context.Context)        {}
+func (*dropSequenceNode) Values() parser.Datums        { return parser.Datums{} }
+
 // dropTableOrViewPrepare/dropTableImpl is used to drop a single table by
 // name, which can result from either a DROP TABLE or DROP DATABASE
 // statement. This method returns the dropped table descriptor, to be
@@ -1036,14 +1133,17 @@ func (p *planner) getViewDescForCascade(
 }
 
 type dropUserNode struct {
-	n *parser.DropUser
+	names    parser.NameList
+	ifExists bool
+	// isRole is set for DROP ROLE.
+	isRole bool
 	// The number of users deleted.
 	numDeleted int
 }
 
 func (n *dropUserNode) Start(params runParams) error {
 	numDeleted := 0
-	for _, name := range n.n.Names {
+	for _, name := range n.names {
 		normalizedUsername, err := NormalizeAndValidateUsername(string(name))
 		if err != nil {
 			return err
@@ -1060,15 +1160,27 @@ func (n *dropUserNode) Start(params runParams) error {
 			params.ctx,
 			"drop-user",
 			params.p.txn,
-			"DELETE FROM system.users WHERE username=$1",
+			`DELETE FROM system.users WHERE username=$1 AND "isRole"=$2`,
 			normalizedUsername,
+			n.isRole,
 		)
 		if err != nil {
 			return err
 		}
 
-		if rowsAffected == 0 && !n.n.IfExists {
-			return errors.Errorf("user %s does not exist", normalizedUsername)
+		if rowsAffected == 0 && !n.ifExists {
+			return errors.Errorf("%s %s does not exist", userOrRole(n.isRole), normalizedUsername)
+		}
+
+		// The memberships of the user or role, and in the role, go with it.
+		if _, err := internalExecutor.ExecuteStatementInTransaction(
+			params.ctx,
+			"drop-role-members",
+			params.p.txn,
+			`DELETE FROM system.role_members WHERE "role"=$1 OR "member"=$1`,
+			normalizedUsername,
+		); err != nil {
+			return err
 		}
 
 		numDeleted += rowsAffected
@@ -1087,14 +1199,27 @@ func (n *dropUserNode) FastPathResults() (int, bool) { return n.numDeleted, true
 // DropUser drops a list of users.
 // Privileges: DELETE on system.users.
 func (p *planner) DropUser(ctx context.Context, n *parser.DropUser) (planNode, error) {
-	tDesc, err := getTableDesc(ctx, p.txn, p.getVirtualTabler(), &parser.TableName{DatabaseName: "system", TableName: "users"})
-	if err != nil {
+	if err := p.checkCanDropUser(ctx); err != nil {
 		return nil, err
 	}
 
-	if err := p.CheckPrivilege(tDesc, privilege.DELETE); err != nil {
+	return &dropUserNode{names: n.Names, ifExists: n.IfExists}, nil
+}
+
+// DropRole drops a list of roles.
+// Privileges: DELETE on system.users.
+func (p *planner) DropRole(ctx context.Context, n *parser.DropRole) (planNode, error) {
+	if err := p.checkCanDropUser(ctx); err != nil {
 		return nil, err
 	}
 
-	return &dropUserNode{n: n}, nil
+	return &dropUserNode{names: n.Names, ifExists: n.IfExists, isRole: true}, nil
+}
+
+func (p *planner) checkCanDropUser(ctx context.Context) error {
+	tDesc, err := getTableDesc(ctx, p.txn, p.getVirtualTabler(), &parser.TableName{DatabaseName: "system", TableName: "users"})
+	if err != nil {
+		return err
+	}
+	return p.CheckPrivilege(tDesc, privilege.DELETE)
 }
@@ -670,6 +670,40 @@ func (e *Executor) ExecutePreparedStatement(
 	return e.execPrepared(session, stmt, pinfo)
 }
 
+// ExecutePortal executes the statement bound to a portal, returning at most
+// limit rows. The query of the portal is run by a cursor, so that the next
+// execution of the portal returns the next rows. The portal must be
+// suspendable; see Session.CanSuspendPortal.
+func (e *Executor) ExecutePortal(
+	session *Session,
+	portal *PreparedPortal,
+	portalName string,
+	pinfo *parser.PlaceholderInfo,
+	limit int,
+) error {
+	defer session.maybeRecover("executing", portal.Stmt.Str)
+
+	// Block system config updates. For more details, see the comment in
+	// ExecuteStatements.
+	if e.cfg.TestingKnobs.WaitForGossipUpdate {
+		e.systemConfigCond.L.Lock()
+		defer e.systemConfigCond.L.Unlock()
+	}
+
+	{
+		// See ExecutePreparedStatement.
+		now := timeutil.Now()
+		session.phaseTimes[sessionStartParse] = now
+		session.phaseTimes[sessionEndParse] = now
+	}
+
+	stmts := StatementList{{
+		AST:           &portalFetch{portal: portal, name: portalName, limit: limit},
+		ExpectedTypes: portal.Stmt.Columns,
+	}}
+	return e.execParsed(session, stmts, pinfo, copyMsgNone)
+}
+
 // execPrepared executes a prepared statement. It returns an error if there
 // is more than 1 result or the returned types differ from the prepared
 // return types.
@@ -854,8 +888,10 @@ func (e *Executor) execParsed(
 		}
 
 		// Sanity check about not leaving KV txns open on errors (other than
-		// retriable errors).
-		if err != nil && txnState.mu.txn != nil && !txnState.mu.txn.IsFinalized() {
+		// retriable errors and errors after which the txn can be rolled back to
+		// a savepoint).
+		if err != nil && txnState.mu.txn != nil && !txnState.mu.txn.IsFinalized() &&
+			!txnState.kvTxnKeptAfterErr() {
 			if _, retryable := err.(*roachpb.HandledRetryableTxnError); !retryable {
 				log.Fatalf(session.Ctx(), "got a non-retryable error but the KV "+
 					"transaction is not finalized. TxnState: %s, err: %s\n"+
@@ -1121,6 +1157,10 @@ func runWithAutoRetry(
 			break
 		}
 		txnState.mu.txn.PrepareForRetry(session.Ctx(), err)
+		// The savepoints and cursors will be created again when the statements
+		// are retried.
+		txnState.savepoints = nil
+		txnState.closeCursors(session.Ctx(), 0)
 		automaticRetryCount++
 	}
 	return remainingStmts, transitionToOpen, err
@@ -1389,6 +1429,9 @@ func runShowTransactionState(session *Session, res StatementResult) error {
 // - COMMIT / ROLLBACK: aborts the current transaction.
 // - ROLLBACK TO SAVEPOINT / SAVEPOINT: reopens the current transaction,
 //   allowing it to be retried.
+// - ROLLBACK TO SAVEPOINT for a savepoint other than the restart savepoint,
+//   if the KV txn was kept open after the error: discards the writes made
+//   after the savepoint and reopens the current transaction.
 func (e *Executor) execStmtInAbortedTxn(
 	session *Session, stmt Statement, res StatementResult,
 ) error {
@@ -1399,7 +1442,7 @@ func (e *Executor) execStmtInAbortedTxn(
 	// TODO(andrei/cuongdo): Figure out what statements to count here.
 	switch s := stmt.AST.(type) {
 	case *parser.CommitTransaction, *parser.RollbackTransaction:
-		if txnState.State() == RestartWait {
+		if txnState.State() == RestartWait || txnState.kvTxnKeptAfterErr() {
 			return rollbackSQLTransaction(txnState, res)
 		}
 		// Reset the state to allow new transactions to start.
@@ -1421,7 +1464,18 @@ func (e *Executor) execStmtInAbortedTxn(
 		default:
 			panic("unreachable")
 		}
-		if err := parser.ValidateRestartCheckpoint(spName); err != nil {
+		if !parser.IsRestartSavepoint(spName) {
+			if _, ok := s.(*parser.RollbackToSavepoint); ok && txnState.kvTxnKeptAfterErr() {
+				if err := rollbackToSQLSavepoint(txnState, spName, res); err != nil {
+					return err
+				}
+				txnState.SetState(Open)
+				return nil
+			}
+			// The KV transaction has already been rolled back, so the other
+			// savepoints can't be used anymore.
+			var err error = pgerror.NewErrorf(pgerror.CodeInFailedSQLTransactionError,
+				"cannot use savepoint %s after an error, only %s", spName, parser.RestartSavepointName)
 			if txnState.State() == RestartWait {
 				err = txnState.updateStateAndCleanupOnErr(err, e)
 			}
@@ -1556,7 +1610,14 @@ func (e *Executor) execStmtInOpenTxn(
 			if !txnState.TxnIsOpen() {
 				panic(fmt.Sprintf("unexpected txnState when cleaning up: %v", txnState.State()))
 			}
-			err = txnState.updateStateAndCleanupOnErr(err, e)
+			if txnState.canRollbackToSavepointAfterErr(err) {
+				// The KV txn is kept open until the client rolls back to a
+				// savepoint, which discards the writes of the failed statement, or
+				// ends the transaction.
+				txnState.SetState(Aborted)
+			} else {
+				err = txnState.updateStateAndCleanupOnErr(err, e)
+			}
 
 			if firstInTxn && isBegin(stmt) {
 				// A failed BEGIN statement that was starting a txn doesn't leave the
@@ -1631,8 +1692,8 @@ func (e *Executor) execStmtInOpenTxn(
 		return commitSQLTransaction(txnState, commit, res)
 
 	case *parser.ReleaseSavepoint:
-		if err := parser.ValidateRestartCheckpoint(s.Savepoint); err != nil {
-			return err
+		if !parser.IsRestartSavepoint(s.Savepoint) {
+			return releaseSQLSavepoint(txnState, s.Savepoint, res)
 		}
 		// ReleaseSavepoint is executed fully here; there's no planNode for it
 		// and a planner is not involved at all.
@@ -1647,8 +1708,13 @@ func (e *Executor) execStmtInOpenTxn(
 		return rollbackSQLTransaction(txnState, res)
 
 	case *parser.Savepoint:
-		if err := parser.ValidateRestartCheckpoint(s.Name); err != nil {
-			return err
+		if !parser.IsRestartSavepoint(s.Name) {
+			if !e.cfg.Settings.Version.IsActive(cluster.VersionSavepoints) {
+				return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+					"SAVEPOINT %s requires the cluster version to be at least %s",
+					s.Name, cluster.VersionSavepoints)
+			}
+			return createSQLSavepoint(txnState, s.Name, res)
 		}
 		// We want to disallow SAVEPOINTs to be issued after a transaction has
 		// started running. The client txn's statement count indicates how many
@@ -1664,8 +1730,8 @@ func (e *Executor) execStmtInOpenTxn(
 		return res.CloseResult()
 
 	case *parser.RollbackToSavepoint:
-		if err := parser.ValidateRestartCheckpoint(s.Savepoint); err != nil {
-			return err
+		if !parser.IsRestartSavepoint(s.Savepoint) {
+			return rollbackToSQLSavepoint(txnState, s.Savepoint, res)
 		}
 		if !txnState.retryIntent {
 			err := fmt.Errorf("SAVEPOINT %s has not been used", parser.RestartSavepointName)
@@ -1679,6 +1745,10 @@ func (e *Executor) execStmtInOpenTxn(
 
 		// Move the state to AutoRetry; we're morally beginning a new transaction.
 		txnState.SetState(AutoRetry)
+		// The savepoints and cursors created after the restart savepoint are
+		// gone.
+		txnState.savepoints = nil
+		txnState.closeCursors(session.Ctx(), 0)
 		// If commands have already been sent through the transaction,
 		// restart the client txn's proto to increment the epoch.
 		if txnState.mu.txn.CommandCount() > 0 {
@@ -1793,7 +1863,7 @@ func stmtAllowedInImplicitTxn(stmt Statement) bool {
 // rollbackSQLTransaction executes a ROLLBACK statement. The transaction is
 // rolled-back results are written to res. All errors are swallowed.
 func rollbackSQLTransaction(txnState *txnState, res StatementResult) error {
-	if !txnState.TxnIsOpen() && txnState.State() != RestartWait {
+	if !txnState.TxnIsOpen() && txnState.State() != RestartWait && !txnState.kvTxnKeptAfterErr() {
 		panic(fmt.Sprintf("rollbackSQLTransaction called on txn in wrong state: %s (txn: %s)",
 			txnState.State(), txnState.mu.txn.Proto()))
 	}
@@ -1850,6 +1920,59 @@ func commitSQLTransaction(txnState *txnState, commitType commitType, res Stateme
 	return res.CloseResult()
 }
 
+// errSavepointNotFound is returned when a savepoint statement refers to a
+// savepoint which doesn't exist.
+func errSavepointNotFound(name string) error {
+	return pgerror.NewErrorf(pgerror.CodeInvalidSavepointSpecificationError,
+		"savepoint %s does not exist", name)
+}
+
+// createSQLSavepoint executes a SAVEPOINT statement for a savepoint other than
+// the restart savepoint. A savepoint can be created several times with the
+// same name, in which case the most recent one is used until it is released.
+func createSQLSavepoint(txnState *txnState, name string, res StatementResult) error {
+	txnState.savepoints = append(txnState.savepoints, sqlSavepoint{
+		name:         name,
+		token:        txnState.mu.txn.CreateSavepoint(),
+		numLocalVars: len(txnState.localVars),
+		numCursors:   txnState.numCursors,
+	})
+	res.BeginResult((*parser.Savepoint)(nil))
+	return res.CloseResult()
+}
+
+// releaseSQLSavepoint executes a RELEASE SAVEPOINT statement for a savepoint
+// other than the restart savepoint. The savepoint and all the savepoints
+// created after it are destroyed; their writes are kept.
+func releaseSQLSavepoint(txnState *txnState, name string, res StatementResult) error {
+	i := txnState.findSavepoint(name)
+	if i < 0 {
+		return errSavepointNotFound(name)
+	}
+	txnState.savepoints = txnState.savepoints[:i]
+	res.BeginResult((*parser.ReleaseSavepoint)(nil))
+	return res.CloseResult()
+}
+
+// rollbackToSQLSavepoint executes a ROLLBACK TO SAVEPOINT statement for a
+// savepoint other than the restart savepoint. The writes made after the
+// savepoint was created are discarded and the savepoints created after it are
+// destroyed, and so are the changes made by SET LOCAL to the session
+// variables and the cursors declared. The savepoint itself remains and can be
+// rolled back to again.
+func rollbackToSQLSavepoint(txnState *txnState, name string, res StatementResult) error {
+	i := txnState.findSavepoint(name)
+	if i < 0 {
+		return errSavepointNotFound(name)
+	}
+	txnState.savepoints = txnState.savepoints[:i+1]
+	txnState.mu.txn.RollbackToSavepoint(txnState.savepoints[i].token)
+	txnState.unwindLocalVars(txnState.savepoints[i].numLocalVars)
+	txnState.closeCursors(txnState.Ctx, txnState.savepoints[i].numCursors)
+	res.BeginResult((*parser.RollbackToSavepoint)(nil))
+	return res.CloseResult()
+}
+
 // exectDistSQL converts a classic plan to a distributed SQL physical plan and
 // runs it.
 func (e *Executor) execDistSQL(
@@ -2256,9 +2379,12 @@ func checkResultType(typ parser.Type) error {
 	case parser.TypeDate:
 	case parser.TypeTimestamp:
 	case parser.TypeTimestampTZ:
+	case parser.TypeTime:
+	case parser.TypeTimeTZ:
 	case parser.TypeInterval:
 	case parser.TypeUUID:
 	case parser.TypeINet:
+	case parser.TypeJSON:
 	case parser.TypeNameArray:
 	case parser.TypeOid:
 	case parser.TypeRegClass:
@@ -2398,10 +2524,11 @@ func isAsOf(session *Session, stmt parser.Statement, max hlc.Timestamp) (*hlc.Ti
 	return &ts, err
 }
 
-// isSavepoint returns true if stmt is a SAVEPOINT statement.
-func isSavepoint(stmt Statement) bool {
-	_, isSavepoint := stmt.AST.(*parser.Savepoint)
-	return isSavepoint
+// isRestartSavepoint returns true if stmt is a "SAVEPOINT cockroach_restart"
+// statement.
+func isRestartSavepoint(stmt Statement) bool {
+	s, isSavepoint := stmt.AST.(*parser.Savepoint)
+	return isSavepoint && parser.IsRestartSavepoint(s.Name)
 }
 
 // isBegin returns true if stmt is a BEGIN statement.
@@ -2416,11 +2543,11 @@ func isSetTransaction(stmt Statement) bool {
 	return isSet
 }
 
-// isRollbackToSavepoint returns true if stmt is a "ROLLBACK TO SAVEPOINT"
-// statement.
-func isRollbackToSavepoint(stmt Statement) bool {
-	_, isSet := stmt.AST.(*parser.RollbackToSavepoint)
-	return isSet
+// isRollbackToRestartSavepoint returns true if stmt is a "ROLLBACK TO
+// SAVEPOINT cockroach_restart" statement.
+func isRollbackToRestartSavepoint(stmt Statement) bool {
+	s, isSet := stmt.AST.(*parser.RollbackToSavepoint)
+	return isSet && parser.IsRestartSavepoint(s.Savepoint)
 }
 
 // canStayInAutoRetryState returns true if the statement, by itself, should not
@@ -2439,11 +2566,11 @@ func isRollbackToSavepoint(stmt Statement) bool {
 // statements ran in the transaction.
 func canStayInAutoRetryState(stmt Statement) bool {
 	return isBegin(stmt) ||
-		isSavepoint(stmt) ||
+		isRestartSavepoint(stmt) ||
 		isSetTransaction(stmt) ||
 		// ROLLBACK TO SAVEPOINT does its own state transitions; if it leaves the
 		// transaction in the AutoRetriable state, don't mess with it.
-		isRollbackToSavepoint(stmt)
+		isRollbackToRestartSavepoint(stmt)
 }
 
 // convertToErrWithPGCode recognizes errs that should have SQL error codes to be
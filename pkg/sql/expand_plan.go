@@ -112,6 +112,20 @@ func doExpandPlan(
 		n.source.plan, err = doExpandPlan(ctx, p, params, n.source.plan)
 
 	case *joinNode:
+		if !n.reordered {
+			// Choose the order of the joins before expanding the data sources,
+			// since the index selection for each data source doesn't depend on
+			// the join order.
+			var newPlan planNode
+			newPlan, err = p.reorderJoins(ctx, n)
+			if err != nil {
+				return plan, err
+			}
+			if newPlan != plan {
+				return doExpandPlan(ctx, p, params, newPlan)
+			}
+		}
+
 		n.left.plan, err = doExpandPlan(ctx, p, noParams, n.left.plan)
 		if err != nil {
 			return plan, err
@@ -121,12 +135,16 @@ func doExpandPlan(
 			return plan, err
 		}
 
-		n.mergeJoinOrdering = computeMergeJoinOrdering(
-			planOrdering(n.left.plan),
-			planOrdering(n.right.plan),
-			n.pred.leftEqualityIndices,
-			n.pred.rightEqualityIndices,
-		)
+		if !n.lookupJoin {
+			// A lookup join isn't executed as a merge join, and doesn't
+			// preserve the ordering of either side.
+			n.mergeJoinOrdering = computeMergeJoinOrdering(
+				planOrdering(n.left.plan),
+				planOrdering(n.right.plan),
+				n.pred.leftEqualityIndices,
+				n.pred.rightEqualityIndices,
+			)
+		}
 		n.ordering = n.joinOrdering()
 
 	case *ordinalityNode:
@@ -234,24 +252,36 @@ func doExpandPlan(
 	case *testingRelocateNode:
 		n.rows, err = doExpandPlan(ctx, p, noParams, n.rows)
 
+	case *recursiveCTENode:
+		n.initial, err = doExpandPlan(ctx, p, noParams, n.initial)
+
 	case *valuesNode:
+	case *alterSequenceNode:
 	case *alterTableNode:
 	case *cancelQueryNode:
 	case *controlJobNode:
 	case *copyNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createPolicyNode:
+	case *createSequenceNode:
+	case *createStatsNode:
 	case *createUserNode:
 	case *createViewNode:
+	case *declareCursorNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
+	case *dropPolicyNode:
+	case *dropSequenceNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropUserNode:
+	case *fetchNode:
 	case *zeroNode:
 	case *unaryNode:
 	case *hookFnNode:
 	case *valueGenerator:
+	case *workTableScanNode:
 	case *setNode:
 	case *setClusterSettingNode:
 	case *showRangesNode:
@@ -554,24 +584,36 @@ func simplifyOrderings(plan planNode, usefulOrdering sqlbase.ColumnOrdering) pla
 	case *testingRelocateNode:
 		n.rows = simplifyOrderings(n.rows, nil)
 
+	case *recursiveCTENode:
+		n.initial = simplifyOrderings(n.initial, nil)
+
 	case *valuesNode:
+	case *alterSequenceNode:
 	case *alterTableNode:
 	case *cancelQueryNode:
 	case *controlJobNode:
 	case *copyNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createPolicyNode:
+	case *createSequenceNode:
+	case *createStatsNode:
 	case *createUserNode:
 	case *createViewNode:
+	case *declareCursorNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
+	case *dropPolicyNode:
+	case *dropSequenceNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropUserNode:
+	case *fetchNode:
 	case *zeroNode:
 	case *unaryNode:
 	case *hookFnNode:
 	case *valueGenerator:
+	case *workTableScanNode:
 	case *setNode:
 	case *setClusterSettingNode:
 	case *showRangesNode:
@@ -316,27 +316,41 @@ func (p *planner) propagateFilters(
 			return plan, extraFilter, err
 		}
 
+	case *recursiveCTENode:
+		if n.initial, err = p.triggerFilterPropagation(ctx, n.initial); err != nil {
+			return plan, extraFilter, err
+		}
+
 	case *testingRelocateNode:
 		if n.rows, err = p.triggerFilterPropagation(ctx, n.rows); err != nil {
 			return plan, extraFilter, err
 		}
 
+	case *alterSequenceNode:
 	case *alterTableNode:
 	case *cancelQueryNode:
 	case *controlJobNode:
 	case *copyNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createPolicyNode:
+	case *createSequenceNode:
+	case *createStatsNode:
 	case *createUserNode:
 	case *createViewNode:
+	case *declareCursorNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
+	case *dropPolicyNode:
+	case *dropSequenceNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropUserNode:
+	case *fetchNode:
 	case *hookFnNode:
 	case *valueGenerator:
 	case *valuesNode:
+	case *workTableScanNode:
 	case *setNode:
 	case *setClusterSettingNode:
 	case *showRangesNode:
@@ -15,16 +15,22 @@
 package sql
 
 import (
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 
+	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 )
 
+// changePrivileges applies changePrivilege to the privileges of grantees on
+// targets, or, if columns is not empty, on these columns of the targets,
+// which must then be tables.
 func (p *planner) changePrivileges(
 	ctx context.Context,
 	targets parser.TargetList,
+	columns parser.NameList,
 	grantees parser.NameList,
 	changePrivilege func(*sqlbase.PrivilegeDescriptor, string),
 ) (planNode, error) {
@@ -37,9 +43,15 @@ func (p *planner) changePrivileges(
 		if err := p.CheckPrivilege(descriptor, privilege.GRANT); err != nil {
 			return nil, err
 		}
-		privileges := descriptor.GetPrivileges()
-		for _, grantee := range grantees {
-			changePrivilege(privileges, string(grantee))
+		if len(columns) > 0 {
+			if err := changeColumnPrivileges(descriptor, columns, grantees, changePrivilege); err != nil {
+				return nil, err
+			}
+		} else {
+			privileges := descriptor.GetPrivileges()
+			for _, grantee := range grantees {
+				changePrivilege(privileges, string(grantee))
+			}
 		}
 
 		switch d := descriptor.(type) {
@@ -70,9 +82,61 @@ func (p *planner) changePrivileges(
 	return &zeroNode{}, nil
 }
 
+// changeColumnPrivileges applies changePrivilege to the privileges of
+// grantees on the named columns of descriptor. The privileges of a column
+// are only stored while some are granted.
+func changeColumnPrivileges(
+	descriptor sqlbase.DescriptorProto,
+	columns parser.NameList,
+	grantees parser.NameList,
+	changePrivilege func(*sqlbase.PrivilegeDescriptor, string),
+) error {
+	tableDesc, ok := descriptor.(*sqlbase.TableDescriptor)
+	if !ok || !tableDesc.IsTable() {
+		return errors.Errorf("privileges can only be granted on columns of tables, not on %s %s",
+			descriptor.TypeName(), descriptor.GetName())
+	}
+	for _, colName := range columns {
+		found, err := tableDesc.FindActiveColumnByName(string(colName))
+		if err != nil {
+			return err
+		}
+		col, err := tableDesc.FindColumnByID(found.ID)
+		if err != nil {
+			return err
+		}
+		if col.Privileges == nil {
+			col.Privileges = &sqlbase.PrivilegeDescriptor{}
+		}
+		for _, grantee := range grantees {
+			changePrivilege(col.Privileges, string(grantee))
+		}
+		if len(col.Privileges.Users) == 0 {
+			col.Privileges = nil
+		}
+	}
+	return nil
+}
+
+// columnPrivileges returns the privileges of privs that can be granted on
+// columns, with ALL standing for all of them, or an error if privs contains
+// others.
+func columnPrivileges(privs privilege.List) (privilege.List, error) {
+	for _, priv := range privs {
+		if priv == privilege.ALL {
+			return privilege.ColumnData, nil
+		}
+		if priv.Mask()&privilege.ColumnData.ToBitField() == 0 {
+			return nil, errors.Errorf("invalid privilege type %s for column", priv)
+		}
+	}
+	return privs, nil
+}
+
 // Grant adds privileges to users.
 // Current status:
 // - Target: single database, table, or view.
+// - Columns: SELECT, INSERT and UPDATE on columns of tables.
 // TODO(marc): open questions:
 // - should we have root always allowed and not present in the permissions list?
 // - should we make users case-insensitive?
@@ -80,14 +144,22 @@ func (p *planner) changePrivileges(
 //   Notes: postgres requires the object owner.
 //          mysql requires the "grant option" and the same privileges, and sometimes superuser.
 func (p *planner) Grant(ctx context.Context, n *parser.Grant) (planNode, error) {
-	return p.changePrivileges(ctx, n.Targets, n.Grantees, func(privDesc *sqlbase.PrivilegeDescriptor, grantee string) {
-		privDesc.Grant(grantee, n.Privileges)
+	privs := n.Privileges
+	if len(n.Columns) > 0 {
+		var err error
+		if privs, err = columnPrivileges(privs); err != nil {
+			return nil, err
+		}
+	}
+	return p.changePrivileges(ctx, n.Targets, n.Columns, n.Grantees, func(privDesc *sqlbase.PrivilegeDescriptor, grantee string) {
+		privDesc.Grant(grantee, privs)
 	})
 }
 
 // Revoke removes privileges from users.
 // Current status:
 // - Target: single database, table, or view.
+// - Columns: SELECT, INSERT and UPDATE on columns of tables.
 // TODO(marc): open questions:
 // - should we have root always allowed and not present in the permissions list?
 // - should we make users case-insensitive?
@@ -95,7 +167,127 @@ func (p *planner) Grant(ctx context.Context, n *parser.Grant) (planNode, error)
 //   Notes: postgres requires the object owner.
 //          mysql requires the "grant option" and the same privileges, and sometimes superuser.
 func (p *planner) Revoke(ctx context.Context, n *parser.Revoke) (planNode, error) {
-	return p.changePrivileges(ctx, n.Targets, n.Grantees, func(privDesc *sqlbase.PrivilegeDescriptor, grantee string) {
-		privDesc.Revoke(grantee, n.Privileges)
+	privs := n.Privileges
+	if len(n.Columns) > 0 {
+		var err error
+		if privs, err = columnPrivileges(privs); err != nil {
+			return nil, err
+		}
+	}
+	return p.changePrivileges(ctx, n.Targets, n.Columns, n.Grantees, func(privDesc *sqlbase.PrivilegeDescriptor, grantee string) {
+		privDesc.Revoke(grantee, privs)
 	})
 }
+
+// GrantRole adds users and roles to roles.
+// Privileges: the admin option on the roles, or superuser.
+//   Notes: postgres also allows users with the CREATEROLE attribute.
+func (p *planner) GrantRole(ctx context.Context, n *parser.GrantRole) (planNode, error) {
+	roles, members, err := p.checkCanChangeRoleMembers(ctx, n.Roles, n.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := `INSERT INTO system.role_members VALUES ($1, $2, $3) ON CONFLICT ("role", "member") DO NOTHING`
+	if n.AdminOption {
+		stmt = `UPSERT INTO system.role_members VALUES ($1, $2, $3)`
+	}
+	internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+	for _, role := range roles {
+		for _, member := range members {
+			if member == role {
+				return nil, errors.Errorf("%s cannot be a member of itself", role)
+			}
+			// The memberships of role include those granted so far by this
+			// statement, so this also detects cycles among its own grants.
+			memberOf, err := p.memberOf(ctx, role)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := memberOf[member]; ok {
+				return nil, errors.Errorf("making %s a member of %s would create a cycle", member, role)
+			}
+			if _, err := internalExecutor.ExecuteStatementInTransaction(
+				ctx, "grant-role", p.txn, stmt, role, member, n.AdminOption,
+			); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &zeroNode{}, nil
+}
+
+// RevokeRole removes users and roles from roles, or only revokes their admin
+// option on them.
+// Privileges: the admin option on the roles, or superuser.
+//   Notes: postgres also allows users with the CREATEROLE attribute.
+func (p *planner) RevokeRole(ctx context.Context, n *parser.RevokeRole) (planNode, error) {
+	roles, members, err := p.checkCanChangeRoleMembers(ctx, n.Roles, n.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := `DELETE FROM system.role_members WHERE "role" = $1 AND "member" = $2`
+	if n.AdminOption {
+		stmt = `UPDATE system.role_members SET "isAdmin" = false WHERE "role" = $1 AND "member" = $2`
+	}
+	internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+	for _, role := range roles {
+		for _, member := range members {
+			if _, err := internalExecutor.ExecuteStatementInTransaction(
+				ctx, "revoke-role", p.txn, stmt, role, member,
+			); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &zeroNode{}, nil
+}
+
+// checkCanChangeRoleMembers verifies that roles are existing roles the
+// session user can administer, and that members are existing users or roles.
+// It returns the normalized names of the roles and members.
+func (p *planner) checkCanChangeRoleMembers(
+	ctx context.Context, roles, members parser.NameList,
+) ([]string, []string, error) {
+	const getIsRole = `SELECT "isRole" FROM system.users WHERE username = $1`
+	internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+	isSuperUser := p.session.User == security.RootUser || p.session.User == security.NodeUser
+	adminOf, err := p.sessionUserMemberOf(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roleNames := make([]string, len(roles))
+	for i, role := range roles {
+		roleNames[i] = role.Normalize()
+		row, err := internalExecutor.QueryRowInTransaction(ctx, "check-role", p.txn, getIsRole, roleNames[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		if row == nil || !bool(*row[0].(*parser.DBool)) {
+			return nil, nil, errors.Errorf("role %s does not exist", roleNames[i])
+		}
+		if !isSuperUser && !adminOf[roleNames[i]] {
+			return nil, nil, errors.Errorf("user %s must have the admin option on role %s",
+				p.session.User, roleNames[i])
+		}
+	}
+
+	memberNames := make([]string, len(members))
+	for i, member := range members {
+		memberNames[i] = member.Normalize()
+		// The root user is not in system.users.
+		if memberNames[i] == security.RootUser {
+			continue
+		}
+		row, err := internalExecutor.QueryRowInTransaction(ctx, "check-role", p.txn, getIsRole, memberNames[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		if row == nil {
+			return nil, nil, errors.Errorf("user or role %s does not exist", memberNames[i])
+		}
+	}
+	return roleNames, memberNames, nil
+}
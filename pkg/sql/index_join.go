@@ -157,6 +157,10 @@ func (p *planner) makeIndexJoin(
 	// refers to any additional column, we also need to prepare the
 	// mapping for these columns in colIDtoRowIndex.
 	for _, colID := range indexScan.index.ColumnIDs {
+		if indexScan.index.Type == sqlbase.IndexDescriptor_INVERTED {
+			// The column of an inverted index can't be fetched from it.
+			break
+		}
 		idx, ok := indexScan.colIdxMap[colID]
 		if !ok {
 			panic(fmt.Sprintf("Unknown column %d in index!", colID))
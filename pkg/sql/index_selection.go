@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/pkg/errors"
 )
@@ -120,6 +121,35 @@ func (p *planner) selectIndex(
 		}
 	}
 
+	// A partial index only contains the rows its predicate holds for, so it
+	// can only be used when the filter implies the predicate.
+	for i := 0; i < len(candidates); {
+		if candidates[i].index.IsPartial() {
+			implied, err := p.filterImpliesPredicate(ctx, s, candidates[i].index)
+			if err != nil {
+				return nil, err
+			}
+			if !implied {
+				candidates[i] = candidates[len(candidates)-1]
+				candidates = candidates[:len(candidates)-1]
+				continue
+			}
+		}
+		i++
+	}
+	if len(candidates) == 0 {
+		// The primary index is never partial. So the only way this can happen
+		// is if we had a specified index.
+		return nil, fmt.Errorf("index \"%s\" is partial and can't be used for this query",
+			s.specifiedIndex.Name)
+	}
+
+	if s.filter != nil {
+		if err := p.replaceIndexExprs(ctx, s); err != nil {
+			return nil, err
+		}
+	}
+
 	for _, c := range candidates {
 		c.init(s)
 	}
@@ -163,9 +193,32 @@ func (p *planner) selectIndex(
 		// use.
 
 		for _, c := range candidates {
-			c.analyzeExprs(exprs)
+			if c.index.Type == sqlbase.IndexDescriptor_INVERTED {
+				c.analyzeInvertedFilter(&p.evalCtx, s.filter)
+			} else {
+				c.analyzeExprs(exprs)
+			}
+		}
+	}
+
+	// An inverted index can only be scanned for the rows matching a
+	// containment constraint; scanning it fully would return rows once per
+	// path in the indexed document and skip rows where it is NULL.
+	for i := 0; i < len(candidates); {
+		if candidates[i].index.Type == sqlbase.IndexDescriptor_INVERTED &&
+			candidates[i].invertedSpans == nil {
+			candidates[i] = candidates[len(candidates)-1]
+			candidates = candidates[:len(candidates)-1]
+		} else {
+			i++
 		}
 	}
+	if len(candidates) == 0 {
+		// The primary index is never inverted. So the only way this can happen
+		// is if we had a specified index.
+		return nil, fmt.Errorf("index \"%s\" is inverted and can't be used for this query",
+			s.specifiedIndex.Name)
+	}
 
 	if s.noIndexJoin {
 		// Eliminate non-covering indexes. We do this after the check above for
@@ -211,7 +264,11 @@ func (p *planner) selectIndex(
 	s.specifiedIndex = nil
 	s.isSecondaryIndex = (c.index != &s.desc.PrimaryIndex)
 	var err error
-	s.spans, err = makeSpans(c.constraints, c.desc, c.index)
+	if c.invertedSpans != nil {
+		s.spans = c.invertedSpans
+	} else {
+		s.spans, err = makeSpans(c.constraints, c.desc, c.index)
+	}
 	if err != nil {
 		return nil, errors.Wrapf(err, "constraints = %v, table ID = %d, index ID = %d",
 			c.constraints, s.desc.ID, s.index.ID)
@@ -262,6 +319,111 @@ func (p *planner) selectIndex(
 	return plan, nil
 }
 
+// replaceIndexExprs replaces the sub-expressions of the filter of s that
+// match an expression of an expression index of the table with the hidden
+// column storing the value of the expression, so that the filter can
+// constrain the index. Expressions are compared after normalization.
+func (p *planner) replaceIndexExprs(ctx context.Context, s *scanNode) error {
+	var v indexExprReplacer
+	for i := range s.desc.Indexes {
+		index := &s.desc.Indexes[i]
+		for j, colID := range index.ColumnIDs {
+			if !index.IsExprColumn(j) {
+				continue
+			}
+			colIdx, ok := s.colIdxMap[colID]
+			if !ok {
+				continue
+			}
+			raw, err := parser.ParseExpr(index.ColumnExprs[j])
+			if err != nil {
+				return err
+			}
+			// The index expressions are resolved with their own helper so
+			// that the columns they use are not marked as needed by the
+			// filter.
+			ivarHelper := parser.MakeIndexedVarHelper(s, len(s.cols))
+			tn := parser.TableName{TableName: parser.Name(s.desc.Name)}
+			expr, err := p.analyzeExpr(ctx, raw,
+				multiSourceInfo{newSourceInfoForSingleTable(tn, s.resultColumns)},
+				ivarHelper, parser.TypeAny, false /* requireType */, "")
+			if err != nil {
+				return err
+			}
+			if v.exprs == nil {
+				v.exprs = make(map[string]int)
+			}
+			v.exprs[parser.AsString(expr)] = colIdx
+		}
+	}
+	if v.exprs == nil {
+		return nil
+	}
+	v.ivarHelper = &s.filterVars
+	filter, _ := parser.WalkExpr(&v, s.filter)
+	s.filter = filter.(parser.TypedExpr)
+	return nil
+}
+
+// filterImpliesPredicate returns whether the filter of the scanNode implies
+// the predicate of the partial index. The implication is only recognized when
+// each conjunct of the predicate is also a conjunct of the filter.
+func (p *planner) filterImpliesPredicate(
+	ctx context.Context, s *scanNode, index *sqlbase.IndexDescriptor,
+) (bool, error) {
+	if s.filter == nil {
+		return false, nil
+	}
+	raw, err := parser.ParseExpr(index.Predicate)
+	if err != nil {
+		return false, err
+	}
+	// Like the index expressions, the predicate is resolved with its own
+	// helper so that the columns it uses are not marked as needed.
+	ivarHelper := parser.MakeIndexedVarHelper(s, len(s.cols))
+	tn := parser.TableName{TableName: parser.Name(s.desc.Name)}
+	pred, err := p.analyzeExpr(ctx, raw,
+		multiSourceInfo{newSourceInfoForSingleTable(tn, s.resultColumns)},
+		ivarHelper, parser.TypeBool, true /* requireType */, "index predicate")
+	if err != nil {
+		return false, err
+	}
+	filterExprs := make(map[string]struct{})
+	for _, e := range splitAndExpr(&p.evalCtx, s.filter, nil) {
+		filterExprs[parser.AsString(e)] = struct{}{}
+	}
+	for _, e := range splitAndExpr(&p.evalCtx, pred, nil) {
+		if _, ok := filterExprs[parser.AsString(e)]; !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// indexExprReplacer replaces the expressions of expression indexes with the
+// columns storing their values.
+type indexExprReplacer struct {
+	// exprs maps the formatted expressions to the indexes of the columns in
+	// the scanNode.
+	exprs      map[string]int
+	ivarHelper *parser.IndexedVarHelper
+}
+
+var _ parser.Visitor = &indexExprReplacer{}
+
+func (v *indexExprReplacer) VisitPre(expr parser.Expr) (recurse bool, newExpr parser.Expr) {
+	switch expr.(type) {
+	case *parser.IndexedVar, parser.Datum:
+		return false, expr
+	}
+	if colIdx, ok := v.exprs[parser.AsString(expr)]; ok {
+		return false, v.ivarHelper.IndexedVar(colIdx)
+	}
+	return true, expr
+}
+
+func (*indexExprReplacer) VisitPost(expr parser.Expr) parser.Expr { return expr }
+
 type indexConstraint struct {
 	start *parser.ComparisonExpr
 	end   *parser.ComparisonExpr
@@ -341,6 +503,9 @@ type indexInfo struct {
 	covering    bool // Does the index cover the required IndexedVars?
 	reverse     bool
 	exactPrefix int
+	// invertedSpans are the spans to scan in an inverted index, which are not
+	// derived from constraints.
+	invertedSpans roachpb.Spans
 }
 
 func (v *indexInfo) init(s *scanNode) {
@@ -399,6 +564,38 @@ func (v *indexInfo) analyzeExprs(exprs []parser.TypedExprs) {
 	}
 }
 
+// analyzeInvertedFilter looks for a conjunct of the filter of the form
+// "<col> @> <json>", where <col> is the column of the inverted index, that
+// allows restricting the scan to the index entries of a single path. Rows
+// found that way are only candidates, so the whole filter is still applied
+// to the rows fetched from the table.
+func (v *indexInfo) analyzeInvertedFilter(evalCtx *parser.EvalContext, filter parser.TypedExpr) {
+	for _, e := range splitAndExpr(evalCtx, filter, nil) {
+		c, ok := e.(*parser.ComparisonExpr)
+		if !ok || c.Operator != parser.Contains {
+			continue
+		}
+		ok, colIdx := getColVarIdx(c.Left)
+		if !ok || v.desc.Columns[colIdx].ID != v.index.ColumnIDs[0] {
+			continue
+		}
+		d, ok := c.Right.(*parser.DJSON)
+		if !ok {
+			continue
+		}
+		key, ok := json.EncodeContainedInvertedIndexKey(
+			sqlbase.MakeIndexKeyPrefix(v.desc, v.index.ID), d.JSON)
+		if !ok {
+			continue
+		}
+		v.invertedSpans = roachpb.Spans{{
+			Key:    roachpb.Key(key),
+			EndKey: roachpb.Key(key).PrefixEnd(),
+		}}
+		return
+	}
+}
+
 // analyzeOrdering analyzes the ordering provided by the index and determines
 // if it matches the ordering requested by the query. Non-matching orderings
 // increase the cost of using the index.
@@ -801,6 +998,10 @@ func (v *indexInfo) isCoveringIndex(scan *scanNode) bool {
 			if !v.index.ContainsColumnID(colID) {
 				return false
 			}
+			if v.index.Type == sqlbase.IndexDescriptor_INVERTED && colID == v.index.ColumnIDs[0] {
+				// An inverted index only holds paths into the indexed document.
+				return false
+			}
 		}
 	}
 	return true
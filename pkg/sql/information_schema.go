@@ -519,6 +519,9 @@ CREATE TABLE information_schema.tables (
 );`,
 	populate: func(ctx context.Context, p *planner, prefix string, addRow func(...parser.Datum) error) error {
 		return forEachTableDesc(ctx, p, prefix, func(db *sqlbase.DatabaseDescriptor, table *sqlbase.TableDescriptor) error {
+			if table.IsSequence() {
+				return nil
+			}
 			tableType := tableTypeBaseTable
 			if isVirtualDescriptor(table) {
 				tableType = tableTypeSystemView
@@ -604,9 +607,14 @@ func forEachDatabaseDesc(
 		dbDescs = append(dbDescs, schema.desc)
 	}
 
+	roles, err := p.sessionUserMemberOf(ctx)
+	if err != nil {
+		return err
+	}
+
 	sort.Sort(sortedDBDescs(dbDescs))
 	for _, db := range dbDescs {
-		if userCanSeeDatabase(db, p.session.User) {
+		if userCanSeeDatabase(db, p.session.User, roles) {
 			if err := fn(db); err != nil {
 				return err
 			}
@@ -724,6 +732,10 @@ func forEachTableDescWithTableLookupInternal(
 	if err != nil {
 		return err
 	}
+	roles, err := p.sessionUserMemberOf(ctx)
+	if err != nil {
+		return err
+	}
 	dbIDsToName := make(map[sqlbase.ID]string)
 	// First, iterate through all database descriptors, constructing dbDescTables
 	// objects and populating a mapping from sqlbase.ID to database name.
@@ -801,7 +813,7 @@ func forEachTableDescWithTableLookupInternal(
 		sort.Strings(dbTableNames)
 		for _, tableName := range dbTableNames {
 			tableDesc := db.tables[tableName]
-			if userCanSeeTable(tableDesc, p.session.User, allowAdding) {
+			if userCanSeeTable(tableDesc, p.session.User, roles, allowAdding) {
 				if err := fn(db.desc, tableDesc, tableLookup); err != nil {
 					return err
 				}
@@ -898,14 +910,18 @@ func forEachUser(ctx context.Context, p *planner, fn func(username string) error
 	return nil
 }
 
-func userCanSeeDatabase(db *sqlbase.DatabaseDescriptor, user string) bool {
-	return userCanSeeDescriptor(db, user)
+func userCanSeeDatabase(
+	db *sqlbase.DatabaseDescriptor, user string, roles map[string]bool,
+) bool {
+	return userCanSeeDescriptor(db, user, roles)
 }
 
-func userCanSeeTable(table *sqlbase.TableDescriptor, user string, allowAdding bool) bool {
+func userCanSeeTable(
+	table *sqlbase.TableDescriptor, user string, roles map[string]bool, allowAdding bool,
+) bool {
 	if !(table.State == sqlbase.TableDescriptor_PUBLIC ||
 		(allowAdding && table.State == sqlbase.TableDescriptor_ADD)) {
 		return false
 	}
-	return userCanSeeDescriptor(table, user)
+	return userCanSeeDescriptor(table, user, roles)
 }
@@ -49,6 +49,7 @@ type insertNode struct {
 	// The following fields are populated during makePlan.
 	editNodeBase
 	defaultExprs []parser.TypedExpr
+	computedCols *sqlbase.ComputedColumns
 	n            *parser.Insert
 	checkHelper  checkHelper
 
@@ -77,6 +78,12 @@ type insertNode struct {
 func (p *planner) Insert(
 	ctx context.Context, n *parser.Insert, desiredTypes []parser.Type,
 ) (planNode, error) {
+	resetWith, err := p.initWith(ctx, n.With)
+	if err != nil {
+		return nil, err
+	}
+	defer resetWith()
+
 	tn, err := p.getAliasedTableName(n.Table)
 	if err != nil {
 		return nil, err
@@ -89,8 +96,14 @@ func (p *planner) Insert(
 	isUpsertReturning := false
 	if n.OnConflict != nil {
 		if !n.OnConflict.DoNothing {
+			// The UPDATE privilege on the updated columns is checked below
+			// if it is only granted on columns.
 			if err := p.CheckPrivilege(en.tableDesc, privilege.UPDATE); err != nil {
-				return nil, err
+				if ok, colErr := p.anyColumnPrivilege(en.tableDesc, privilege.UPDATE); colErr != nil {
+					return nil, colErr
+				} else if !ok {
+					return nil, err
+				}
 			}
 		}
 		if _, ok := n.Returning.(*parser.ReturningExprs); ok {
@@ -101,12 +114,16 @@ func (p *planner) Insert(
 	var cols []sqlbase.ColumnDescriptor
 	// Determine which columns we're inserting into.
 	if n.DefaultValues() {
-		cols = en.tableDesc.Columns
+		cols = nonComputedColumns(en.tableDesc.Columns)
 	} else {
 		var err error
 		if cols, err = p.processColumns(en.tableDesc, n.Columns); err != nil {
 			return nil, err
 		}
+		// The columns receiving a default value need no privilege.
+		if err := p.checkColumnPrivileges(en.tableDesc, cols, privilege.INSERT); err != nil {
+			return nil, err
+		}
 	}
 	// Number of columns expecting an input. This doesn't include the
 	// columns receiving a default value.
@@ -117,6 +134,10 @@ func (p *planner) Insert(
 	if err != nil {
 		return nil, err
 	}
+	computedCols, err := sqlbase.MakeComputedColumns(en.tableDesc, cols)
+	if err != nil {
+		return nil, err
+	}
 
 	var insertRows parser.SelectStatement
 	if n.DefaultValues() {
@@ -219,8 +240,14 @@ func (p *planner) Insert(
 				if err != nil {
 					return nil, err
 				}
+				if col.IsComputed() {
+					return nil, sqlbase.NewComputedColumnWriteError(col.Name)
+				}
 				updateCols[i] = col
 			}
+			if err := p.checkColumnPrivileges(en.tableDesc, updateCols, privilege.UPDATE); err != nil {
+				return nil, err
+			}
 
 			helper, err := p.makeUpsertHelper(
 				ctx, tn, en.tableDesc, ri.InsertCols, updateCols, updateExprs, conflictIndex, n.OnConflict.Where,
@@ -229,8 +256,20 @@ func (p *planner) Insert(
 				return nil, err
 			}
 
-			fkTables := sqlbase.TablesNeededForFKs(*en.tableDesc, sqlbase.CheckUpdates)
-			if err := p.fillFKTableMap(ctx, fkTables); err != nil {
+			// The computed columns depending on the updated columns are updated
+			// too. Their values follow the ones of the SET expressions.
+			computedUpdateCols, err := sqlbase.ComputedColumnsToUpdate(en.tableDesc, updateCols)
+			if err != nil {
+				return nil, err
+			}
+			updateComputedCols, err := sqlbase.MakeComputedColumns(en.tableDesc, computedUpdateCols)
+			if err != nil {
+				return nil, err
+			}
+			updateCols = append(updateCols, computedUpdateCols...)
+
+			fkTables, err := p.lookupFKTables(ctx, en.tableDesc, sqlbase.CheckUpdates)
+			if err != nil {
 				return nil, err
 			}
 			tu := tableUpserterPool.Get().(*tableUpserter)
@@ -240,8 +279,10 @@ func (p *planner) Insert(
 				alloc:         &p.alloc,
 				mon:           &p.session.TxnState.mon,
 				collectRows:   isUpsertReturning,
+				evalCtx:       &p.evalCtx,
 				fkTables:      fkTables,
 				updateCols:    updateCols,
+				computedCols:  updateComputedCols,
 				conflictIndex: *conflictIndex,
 				evaler:        helper,
 				isUpsertAlias: n.OnConflict.IsUpsertAlias(),
@@ -255,6 +296,7 @@ func (p *planner) Insert(
 		n:                     n,
 		editNodeBase:          en,
 		defaultExprs:          defaultExprs,
+		computedCols:          computedCols,
 		insertCols:            ri.InsertCols,
 		insertColIDtoRowIndex: ri.InsertColIDtoRowIndex,
 		isUpsertReturning:     isUpsertReturning,
@@ -381,7 +423,10 @@ func (n *insertNode) internalNext(params runParams) (bool, error) {
 		return false, err
 	}
 
-	rowVals, err := GenerateInsertRow(n.defaultExprs, n.insertColIDtoRowIndex, n.insertCols, params.p.evalCtx, n.tableDesc, n.run.rows.Values())
+	rowVals, err := GenerateInsertRow(
+		n.defaultExprs, n.computedCols, n.insertColIDtoRowIndex, n.insertCols, params.p.evalCtx,
+		n.tableDesc, n.run.rows.Values(),
+	)
 	if err != nil {
 		return false, err
 	}
@@ -417,9 +462,11 @@ func (n *insertNode) internalNext(params runParams) (bool, error) {
 }
 
 // GenerateInsertRow prepares a row tuple for insertion. It fills in default
-// expressions, verifies non-nullable columns, and checks column widths.
+// expressions, computes the values of computed columns, verifies
+// non-nullable columns, and checks column widths.
 func GenerateInsertRow(
 	defaultExprs []parser.TypedExpr,
+	computedCols *sqlbase.ComputedColumns,
 	insertColIDtoRowIndex map[sqlbase.ColumnID]int,
 	insertCols []sqlbase.ColumnDescriptor,
 	evalCtx parser.EvalContext,
@@ -448,6 +495,16 @@ func GenerateInsertRow(
 			}
 			rowVals[i] = d
 		}
+	} else if computedCols != nil {
+		// The computed values are written to a copy of the row, as above.
+		rowVals = append(parser.Datums(nil), rowVals...)
+	}
+
+	if computedCols != nil {
+		computedCols.LoadRow(insertColIDtoRowIndex, rowVals, false)
+		if err := computedCols.ComputeInto(&evalCtx, insertColIDtoRowIndex, rowVals); err != nil {
+			return nil, err
+		}
 	}
 
 	// Check to see if NULL is being inserted into any non-nullable column.
@@ -475,8 +532,8 @@ func (p *planner) processColumns(
 		// VisibleColumns is used here to prevent INSERT INTO <table> VALUES (...)
 		// (as opposed to INSERT INTO <table> (...) VALUES (...)) from writing
 		// hidden columns. At present, the only hidden column is the implicit rowid
-		// primary key column.
-		return tableDesc.VisibleColumns(), nil
+		// primary key column. Computed columns are skipped as well.
+		return nonComputedColumns(tableDesc.VisibleColumns()), nil
 	}
 
 	cols := make([]sqlbase.ColumnDescriptor, len(node))
@@ -496,6 +553,10 @@ func (p *planner) processColumns(
 			return nil, err
 		}
 
+		if col.IsComputed() {
+			return nil, sqlbase.NewComputedColumnWriteError(col.Name)
+		}
+
 		if _, ok := colIDSet[col.ID]; ok {
 			return nil, fmt.Errorf("multiple assignments to the same column %q", n)
 		}
@@ -506,6 +567,17 @@ func (p *planner) processColumns(
 	return cols, nil
 }
 
+// nonComputedColumns returns the columns of cols that are not computed.
+func nonComputedColumns(cols []sqlbase.ColumnDescriptor) []sqlbase.ColumnDescriptor {
+	res := cols[:0:0]
+	for _, col := range cols {
+		if !col.IsComputed() {
+			res = append(res, col)
+		}
+	}
+	return res
+}
+
 // extractInsertSource removes the parentheses around the data source of an INSERT statement.
 // If the data source is a VALUES clause not further qualified with LIMIT/OFFSET and ORDER BY,
 // the 2nd return value is a pre-casted pointer to the VALUES clause.
@@ -142,6 +142,17 @@ type joinNode struct {
 	// trimmed.
 	ordering orderingInfo
 
+	// reordered is set once the join has been considered for join reordering
+	// during expandPlan, either as part of a larger cluster of joins or by
+	// itself. See reorderJoins.
+	reordered bool
+
+	// lookupJoin is set by reorderJoins if the right side is a scan of the
+	// primary index of a table and the left side is expected to produce few
+	// rows. In that case distsql planning looks up the matching right rows by
+	// primary key instead of reading the whole table.
+	lookupJoin bool
+
 	// columns contains the metadata for the results of this node.
 	columns sqlbase.ResultColumns
 
@@ -255,6 +266,18 @@ func (p *planner) makeJoin(
 		return planDataSource{}, err
 	}
 
+	return planDataSource{
+		info: info,
+		plan: p.newJoinNode(typ, left, right, pred, info),
+	}, nil
+}
+
+// newJoinNode creates a joinNode for the given data sources and predicate.
+// info must describe the columns of the join, as returned along with the
+// predicate by makeCrossPredicate and friends.
+func (p *planner) newJoinNode(
+	typ joinType, left, right planDataSource, pred *joinPredicate, info *dataSourceInfo,
+) *joinNode {
 	n := &joinNode{
 		planner:  p,
 		left:     left,
@@ -279,11 +302,7 @@ func (p *planner) makeJoin(
 			0,
 		),
 	}
-
-	return planDataSource{
-		info: info,
-		plan: n,
-	}, nil
+	return n
 }
 
 // Start implements the planNode interface.
@@ -539,13 +558,19 @@ func (n *joinNode) Values() parser.Datums {
 
 // Close implements the planNode interface.
 func (n *joinNode) Close(ctx context.Context) {
+	n.closeBuffers(ctx)
+
+	n.right.plan.Close(ctx)
+	n.left.plan.Close(ctx)
+}
+
+// closeBuffers releases the memory held by the join itself, without closing
+// its sources.
+func (n *joinNode) closeBuffers(ctx context.Context) {
 	n.buffer.Close(ctx)
 	n.buffer = nil
 	n.buckets.Close(ctx)
 	n.bucketsMemAcc.Wtxn(n.planner.session).Close(ctx)
-
-	n.right.plan.Close(ctx)
-	n.left.plan.Close(ctx)
 }
 
 // equalityColIdxInSchema takes a column index from joinPred.leftEqualityIndices
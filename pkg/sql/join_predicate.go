@@ -478,5 +478,8 @@ func pickUsingColumn(
 	if idx == invalidColIdx {
 		return idx, nil, fmt.Errorf("column \"%s\" specified in USING clause does not exist in %s table", colName, context)
 	}
+	if cols[idx].Unreadable {
+		return idx, nil, newUnreadableColumnError(colName)
+	}
 	return idx, cols[idx].Typ, nil
 }
@@ -0,0 +1,713 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"math"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// Join reordering
+//
+// The joins of a query are planned in the order in which they appear in the
+// FROM clause. Before expanding a tree of inner joins, reorderJoins uses the
+// table statistics collected by CREATE STATISTICS to estimate the number of
+// rows produced by every join, and rebuilds the tree if a cheaper order is
+// found. For example, in:
+//
+//   SELECT * FROM a, b, c WHERE a.x = c.x AND b.y = c.y AND c.z = 1
+//
+// the first join is a cross join between a and b. If c.z = 1 selects few
+// rows, it is much cheaper to join c with a and then join the result with b.
+//
+// The join order is chosen greedily: we start with the cheapest join between
+// two data sources, and then repeatedly join the result with the data source
+// which makes the next join cheapest. Data sources which are connected to the
+// joined ones by an equality are preferred, to avoid cross joins.
+//
+// For each join we also choose how it is executed: a hash join, for which the
+// smaller side is put on the right since the right side is loaded in memory,
+// or a lookup join, for which the right side must be a table whose primary
+// key is entirely constrained by equalities with the left side. Merge joins
+// are still chosen during distsql planning, when the orderings of both sides
+// match.
+//
+// Join reordering only happens if statistics are available for all the tables
+// involved; otherwise the order of the query is kept.
+
+const (
+	// joinReorderFilterSelectivity is the estimated fraction of rows which pass
+	// a filter, or a join condition which isn't an equality.
+	joinReorderFilterSelectivity = 1.0 / 3
+
+	// lookupJoinRowCost is the estimated cost of looking up a row by primary
+	// key, relative to the cost of reading or joining a row.
+	lookupJoinRowCost = 10
+
+	// maxJoinReorderLeaves is the maximum number of data sources in a tree of
+	// joins which is reordered.
+	maxJoinReorderLeaves = 64
+)
+
+// joinCluster is a tree of inner joins whose order can be freely changed.
+// Its leaves are the data sources which aren't such joins. The columns of the
+// leaves are numbered globally, in the order in which they appear in the
+// result of the root of the tree.
+type joinCluster struct {
+	// columns are the result columns of the root of the tree.
+	columns sqlbase.ResultColumns
+	// joins are the joinNodes of the tree.
+	joins []*joinNode
+	// leaves are the data sources which are joined.
+	leaves []joinLeaf
+	// colLeaf maps each global column index to the index of its leaf.
+	colLeaf []int
+	// equalities are the pairs of columns compared for equality by the joins.
+	equalities []joinEquality
+	// conds are the other join conditions.
+	conds []joinCond
+	// tree is the original order of the joins.
+	tree *joinTree
+}
+
+// joinLeaf is a data source of a joinCluster.
+type joinLeaf struct {
+	source planDataSource
+	// firstCol is the global index of the first column of the data source.
+	firstCol int
+	// rows is the estimated number of rows of the data source.
+	rows float64
+	// distinct maps the global indices of some of the columns to the estimated
+	// number of distinct values in the column.
+	distinct map[int]float64
+	// keyCols are the global indices of the primary key columns if the data
+	// source is a scan of a whole table, which can be the right side of a
+	// lookup join.
+	keyCols []int
+}
+
+// joinEquality is an equality between two columns of different leaves.
+type joinEquality struct {
+	left, right int
+}
+
+// joinCond is a join condition which isn't an equality between two columns.
+type joinCond struct {
+	// expr is the condition; its IndexedVars are relative to firstCol.
+	expr     parser.TypedExpr
+	firstCol int
+	// leaves is the set of leaves the condition refers to.
+	leaves leafSet
+}
+
+// leafSet is a set of leaves of a joinCluster.
+type leafSet uint64
+
+func (s leafSet) contains(leaf int) bool { return s&(1<<uint(leaf)) != 0 }
+
+// joinTree is a tree of joins between the leaves of a joinCluster, along with
+// estimates for its result.
+type joinTree struct {
+	// leaf is the index of the leaf for leaf nodes, or -1 for joins.
+	leaf        int
+	left, right *joinTree
+	// lookup is set if the join looks up the rows of its right side, which is
+	// a leaf, by primary key.
+	lookup bool
+
+	leaves leafSet
+	// rows is the estimated number of rows in the result.
+	rows float64
+	// cost is the estimated cost of computing the result, in rows processed.
+	cost float64
+}
+
+// isReorderableJoin returns true if a join can be part of a joinCluster.
+func isReorderableJoin(n *joinNode) bool {
+	return !n.reordered && n.joinType == joinTypeInner && n.pred.numMergedEqualityColumns == 0
+}
+
+// reorderJoins chooses the order of the inner joins in the tree rooted at n,
+// and the way each of them is executed. It returns either n, or a plan which
+// produces the same columns as n using a different join order. All the joins
+// in the resulting tree are marked so they aren't considered again.
+func (p *planner) reorderJoins(ctx context.Context, n *joinNode) (planNode, error) {
+	if !isReorderableJoin(n) {
+		n.reordered = true
+		return n, nil
+	}
+
+	c := &joinCluster{columns: n.columns}
+	c.tree = c.addSource(planDataSource{plan: n}, 0 /* firstCol */)
+	defer func() {
+		for _, j := range c.joins {
+			j.reordered = true
+		}
+	}()
+	if len(c.leaves) > maxJoinReorderLeaves || p.txn == nil {
+		return n, nil
+	}
+	for i := range c.leaves {
+		if !isEstimableJoinLeaf(c.leaves[i].source.plan) {
+			return n, nil
+		}
+	}
+
+	// Estimate the size of the leaves.
+	stats := make(map[sqlbase.ID]*tableStats)
+	for i := range c.leaves {
+		ok, err := p.estimateJoinLeaf(ctx, &c.leaves[i], stats)
+		if err != nil {
+			return n, err
+		}
+		if !ok {
+			return n, nil
+		}
+	}
+	for i := range c.conds {
+		c.conds[i].leaves = c.condLeaves(c.conds[i])
+	}
+
+	c.estimateTree(c.tree)
+	newTree := c.greedyOrder()
+	if newTree.cost >= c.tree.cost {
+		// The original order is at least as good.
+		return n, nil
+	}
+
+	for _, j := range c.joins {
+		j.closeBuffers(ctx)
+	}
+	return p.buildJoinTree(ctx, c, newTree)
+}
+
+// addSource adds a data source to the cluster, either as a leaf or, for
+// reorderable joins, as a subtree. It returns the original tree for the
+// source.
+func (c *joinCluster) addSource(src planDataSource, firstCol int) *joinTree {
+	j, ok := src.plan.(*joinNode)
+	if !ok || !isReorderableJoin(j) {
+		c.leaves = append(c.leaves, joinLeaf{source: src, firstCol: firstCol})
+		for range planColumns(src.plan) {
+			c.colLeaf = append(c.colLeaf, len(c.leaves)-1)
+		}
+		return &joinTree{leaf: len(c.leaves) - 1}
+	}
+
+	c.joins = append(c.joins, j)
+	rightFirstCol := firstCol + j.pred.numLeftCols
+	t := &joinTree{
+		leaf:  -1,
+		left:  c.addSource(j.left, firstCol),
+		right: c.addSource(j.right, rightFirstCol),
+	}
+	for i := range j.pred.leftEqualityIndices {
+		c.equalities = append(c.equalities, joinEquality{
+			left:  firstCol + j.pred.leftEqualityIndices[i],
+			right: rightFirstCol + j.pred.rightEqualityIndices[i],
+		})
+	}
+	if !isFilterTrue(j.pred.onCond) {
+		c.conds = append(c.conds, joinCond{expr: j.pred.onCond, firstCol: firstCol})
+	}
+	return t
+}
+
+// condLeaves returns the set of leaves a join condition refers to.
+func (c *joinCluster) condLeaves(cond joinCond) leafSet {
+	var leaves leafSet
+	exprCheckVars(cond.expr, func(expr parser.VariableExpr) (bool, parser.Expr) {
+		if iv, ok := expr.(*parser.IndexedVar); ok {
+			leaves |= 1 << uint(c.colLeaf[cond.firstCol+iv.Idx])
+		}
+		return true, expr
+	})
+	return leaves
+}
+
+// isEstimableJoinLeaf returns true if the number of rows of a plan can be
+// estimated by estimateJoinLeaf, provided statistics are available.
+func isEstimableJoinLeaf(plan planNode) bool {
+	switch n := plan.(type) {
+	case *scanNode:
+		// The system tables are skipped, since they don't have statistics
+		// and are used by internal queries.
+		return n.desc.IsTable() && !n.desc.IsVirtualTable() &&
+			n.desc.ParentID != keys.SystemDatabaseID
+	case *renderNode:
+		return isEstimableJoinLeaf(n.source.plan)
+	case *filterNode:
+		return isEstimableJoinLeaf(n.source.plan)
+	case *valuesNode:
+		return n.n != nil
+	}
+	return false
+}
+
+// estimateJoinLeaf estimates the number of rows of a leaf and the number of
+// distinct values of its columns. It returns false if there are no statistics
+// for one of the tables it reads.
+func (p *planner) estimateJoinLeaf(
+	ctx context.Context, leaf *joinLeaf, stats map[sqlbase.ID]*tableStats,
+) (bool, error) {
+	rows, distinct, ok, err := p.estimatePlanRows(ctx, leaf.source.plan, stats)
+	if err != nil || !ok {
+		return false, err
+	}
+	leaf.rows = rows
+	leaf.distinct = make(map[int]float64, len(distinct))
+	for col, d := range distinct {
+		leaf.distinct[leaf.firstCol+col] = d
+	}
+
+	// Check whether the leaf can be the right side of a lookup join: it must
+	// read all the rows of the table and its columns must be the columns of
+	// the table, which is what a JoinReader produces.
+	if scan, ok := leaf.source.plan.(*scanNode); ok && isFilterTrue(scan.filter) &&
+		scan.specifiedIndex == nil && len(scan.cols) == len(scan.desc.Columns) {
+		leaf.keyCols = make([]int, len(scan.desc.PrimaryIndex.ColumnIDs))
+		for i, colID := range scan.desc.PrimaryIndex.ColumnIDs {
+			idx, ok := scan.colIdxMap[colID]
+			if !ok || scan.cols[idx].ID != scan.desc.Columns[idx].ID {
+				leaf.keyCols = nil
+				break
+			}
+			leaf.keyCols[i] = leaf.firstCol + idx
+		}
+	}
+	return true, nil
+}
+
+// estimatePlanRows estimates the number of rows of a plan and the number of
+// distinct values of some of its columns; see estimateJoinLeaf.
+func (p *planner) estimatePlanRows(
+	ctx context.Context, plan planNode, stats map[sqlbase.ID]*tableStats,
+) (rows float64, distinct map[int]float64, ok bool, err error) {
+	switch n := plan.(type) {
+	case *scanNode:
+		s, found := stats[n.desc.ID]
+		if !found {
+			s, err = p.getTableStats(ctx, n.desc)
+			if err != nil {
+				return 0, nil, false, err
+			}
+			stats[n.desc.ID] = s
+		}
+		if s == nil {
+			return 0, nil, false, nil
+		}
+		rows = s.rowCount
+		distinct = make(map[int]float64)
+		for i := range n.cols {
+			if d, ok := s.distinctCount[n.cols[i].ID]; ok {
+				distinct[i] = d
+			}
+		}
+		if !isFilterTrue(n.filter) {
+			rows, distinct = applySelectivity(rows, distinct, joinReorderFilterSelectivity)
+		}
+		return rows, distinct, true, nil
+
+	case *renderNode:
+		rows, srcDistinct, ok, err := p.estimatePlanRows(ctx, n.source.plan, stats)
+		if err != nil || !ok {
+			return 0, nil, false, err
+		}
+		distinct = make(map[int]float64)
+		for i, e := range n.render {
+			if iv, ok := e.(*parser.IndexedVar); ok {
+				if d, ok := srcDistinct[iv.Idx]; ok {
+					distinct[i] = d
+				}
+			}
+		}
+		return rows, distinct, true, nil
+
+	case *filterNode:
+		rows, distinct, ok, err := p.estimatePlanRows(ctx, n.source.plan, stats)
+		if err != nil || !ok {
+			return 0, nil, false, err
+		}
+		rows, distinct = applySelectivity(rows, distinct, joinReorderFilterSelectivity)
+		return rows, distinct, true, nil
+
+	case *valuesNode:
+		return float64(len(n.tuples)), nil, true, nil
+	}
+	return 0, nil, false, nil
+}
+
+// applySelectivity scales an estimated number of rows, making sure the
+// number of distinct values of each column doesn't exceed it.
+func applySelectivity(
+	rows float64, distinct map[int]float64, selectivity float64,
+) (float64, map[int]float64) {
+	rows *= selectivity
+	for col, d := range distinct {
+		distinct[col] = math.Min(d, rows)
+	}
+	return rows, distinct
+}
+
+// tableStats contains the statistics of a table used to order joins.
+type tableStats struct {
+	rowCount float64
+	// distinctCount maps column IDs to the number of distinct values in the
+	// column, for the columns which have single-column statistics.
+	distinctCount map[sqlbase.ColumnID]float64
+}
+
+// getTableStats returns the latest statistics of a table, or nil if there are
+// none.
+func (p *planner) getTableStats(
+	ctx context.Context, desc *sqlbase.TableDescriptor,
+) (*tableStats, error) {
+	// The statistics are read as root, like for SHOW STATISTICS.
+	internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+	rows, err := internalExecutor.QueryRowsInTransaction(
+		ctx,
+		"get-table-statistics",
+		p.txn,
+		`SELECT "columnIDs", "rowCount", "distinctCount"
+		FROM system.table_statistics
+		WHERE "tableID" = $1
+		ORDER BY "createdAt" DESC, "statisticID" DESC`,
+		desc.ID,
+	)
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+
+	s := &tableStats{
+		rowCount:      float64(*rows[0][1].(*parser.DInt)),
+		distinctCount: make(map[sqlbase.ColumnID]float64),
+	}
+	for _, r := range rows {
+		columnIDs := r[0].(*parser.DArray).Array
+		if len(columnIDs) != 1 {
+			continue
+		}
+		colID := sqlbase.ColumnID(*columnIDs[0].(*parser.DInt))
+		if _, ok := s.distinctCount[colID]; !ok {
+			// The rows are ordered from the newest to the oldest statistic.
+			s.distinctCount[colID] = float64(*r[2].(*parser.DInt))
+		}
+	}
+	return s, nil
+}
+
+// distinct returns the estimated number of distinct values of a column in the
+// result of a tree.
+func (c *joinCluster) distinct(col int, t *joinTree) float64 {
+	leaf := &c.leaves[c.colLeaf[col]]
+	d, ok := leaf.distinct[col]
+	if !ok {
+		// Without statistics, assume the column is a key.
+		d = leaf.rows
+	}
+	return math.Max(math.Min(d, t.rows), 1)
+}
+
+// connected returns true if there is an equality between the columns of two
+// trees.
+func (c *joinCluster) connected(a, b *joinTree) bool {
+	for _, eq := range c.equalities {
+		l, r := c.colLeaf[eq.left], c.colLeaf[eq.right]
+		if (a.leaves.contains(l) && b.leaves.contains(r)) ||
+			(a.leaves.contains(r) && b.leaves.contains(l)) {
+			return true
+		}
+	}
+	return false
+}
+
+// estimateJoinRows estimates the number of rows of the join of two trees.
+func (c *joinCluster) estimateJoinRows(a, b *joinTree) float64 {
+	// The equality columns are assumed to be correlated, so only the most
+	// selective equality is taken into account.
+	selectivity := 1.0
+	for _, eq := range c.equalities {
+		l, r := eq.left, eq.right
+		if a.leaves.contains(c.colLeaf[r]) && b.leaves.contains(c.colLeaf[l]) {
+			l, r = r, l
+		} else if !a.leaves.contains(c.colLeaf[l]) || !b.leaves.contains(c.colLeaf[r]) {
+			continue
+		}
+		s := 1 / math.Max(c.distinct(l, a), c.distinct(r, b))
+		selectivity = math.Min(selectivity, s)
+	}
+	leaves := a.leaves | b.leaves
+	for _, cond := range c.conds {
+		if cond.leaves&leaves == cond.leaves &&
+			cond.leaves&a.leaves != cond.leaves && cond.leaves&b.leaves != cond.leaves {
+			selectivity *= joinReorderFilterSelectivity
+		}
+	}
+	return a.rows * b.rows * selectivity
+}
+
+// lookupCols returns the global indices of the columns of a tree which can be
+// used to look up the rows of a leaf by primary key, in the order of the
+// primary key columns. It returns nil if a lookup join isn't possible, which
+// is the case unless the primary key columns are each constrained by exactly
+// one equality with the tree.
+func (c *joinCluster) lookupCols(t *joinTree, leaf int) []int {
+	keyCols := c.leaves[leaf].keyCols
+	if keyCols == nil {
+		return nil
+	}
+	cols := make([]int, len(keyCols))
+	numEqualities := 0
+	for _, eq := range c.equalities {
+		l, r := eq.left, eq.right
+		if c.colLeaf[l] == leaf {
+			l, r = r, l
+		}
+		if c.colLeaf[r] != leaf || !t.leaves.contains(c.colLeaf[l]) {
+			continue
+		}
+		numEqualities++
+		found := false
+		for i, keyCol := range keyCols {
+			if keyCol == r && c.columns[l].Typ.Equivalent(c.columns[r].Typ) {
+				cols[i] = l
+				found = true
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	if numEqualities != len(keyCols) {
+		return nil
+	}
+	return cols
+}
+
+// join returns the cheapest way to join two trees.
+func (c *joinCluster) join(a, b *joinTree) *joinTree {
+	rows := c.estimateJoinRows(a, b)
+
+	// A hash join processes the rows of both sides. The right side is loaded in
+	// memory, so we put the smaller side there.
+	t := &joinTree{
+		leaf:   -1,
+		left:   a,
+		right:  b,
+		leaves: a.leaves | b.leaves,
+		rows:   rows,
+		cost:   a.cost + b.cost + a.rows + b.rows + rows,
+	}
+	if a.rows < b.rows {
+		t.left, t.right = b, a
+	}
+
+	// A lookup join only processes the rows of the left side, but looking up a
+	// row is more expensive than reading it.
+	for _, sides := range [][2]*joinTree{{a, b}, {b, a}} {
+		left, right := sides[0], sides[1]
+		if right.leaf < 0 || c.lookupCols(left, right.leaf) == nil {
+			continue
+		}
+		if cost := left.cost + left.rows*lookupJoinRowCost + rows; cost < t.cost {
+			t = &joinTree{
+				leaf:   -1,
+				left:   left,
+				right:  right,
+				lookup: true,
+				leaves: t.leaves,
+				rows:   rows,
+				cost:   cost,
+			}
+		}
+	}
+	return t
+}
+
+// estimateTree fills in the estimates of a tree, assuming it is executed with
+// hash joins.
+func (c *joinCluster) estimateTree(t *joinTree) {
+	if t.leaf >= 0 {
+		t.leaves = 1 << uint(t.leaf)
+		t.rows = c.leaves[t.leaf].rows
+		return
+	}
+	c.estimateTree(t.left)
+	c.estimateTree(t.right)
+	t.leaves = t.left.leaves | t.right.leaves
+	t.rows = c.estimateJoinRows(t.left, t.right)
+	t.cost = t.left.cost + t.right.cost + t.left.rows + t.right.rows + t.rows
+}
+
+// greedyOrder returns a tree which joins all the leaves, built greedily by
+// choosing the cheapest join at each step.
+func (c *joinCluster) greedyOrder() *joinTree {
+	remaining := make([]*joinTree, len(c.leaves))
+	for i := range remaining {
+		remaining[i] = &joinTree{leaf: i}
+		c.estimateTree(remaining[i])
+	}
+	// candidates returns the indices in remaining of the trees which can be
+	// joined with t. We avoid cross joins unless there is no other choice.
+	candidates := func(t *joinTree) []int {
+		var connected, all []int
+		for i, r := range remaining {
+			if r == t {
+				continue
+			}
+			all = append(all, i)
+			if c.connected(t, r) {
+				connected = append(connected, i)
+			}
+		}
+		if len(connected) > 0 {
+			return connected
+		}
+		return all
+	}
+	remove := func(t *joinTree) {
+		for i, r := range remaining {
+			if r == t {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				return
+			}
+		}
+	}
+
+	// Start with the cheapest join between two leaves.
+	var best *joinTree
+	for _, a := range remaining {
+		for _, i := range candidates(a) {
+			if t := c.join(a, remaining[i]); best == nil || t.cost < best.cost {
+				best = t
+			}
+		}
+	}
+	remove(best.left)
+	remove(best.right)
+
+	for len(remaining) > 0 {
+		var next *joinTree
+		for _, i := range candidates(best) {
+			if t := c.join(best, remaining[i]); next == nil || t.cost < next.cost {
+				next = t
+			}
+		}
+		if next.left == best {
+			remove(next.right)
+		} else {
+			remove(next.left)
+		}
+		best = next
+	}
+	return best
+}
+
+// buildJoinTree creates the plan for a tree of joins. The join conditions of
+// the cluster are added to the root of the tree and propagated down to the
+// joins which can evaluate them.
+func (p *planner) buildJoinTree(
+	ctx context.Context, c *joinCluster, t *joinTree,
+) (planNode, error) {
+	src, cols, err := p.buildJoinSubtree(c, t)
+	if err != nil {
+		return nil, err
+	}
+	root := src.plan.(*joinNode)
+
+	// newCols maps the global column indices to the columns of the new root.
+	newCols := make([]int, len(cols))
+	identity := true
+	for i, col := range cols {
+		newCols[col] = i
+		identity = identity && i == col
+	}
+
+	ivar := func(col int) parser.TypedExpr {
+		return root.pred.iVarHelper.IndexedVar(newCols[col])
+	}
+	var filter parser.TypedExpr
+	for _, eq := range c.equalities {
+		filter = mergeConj(filter, parser.NewTypedComparisonExpr(parser.EQ, ivar(eq.left), ivar(eq.right)))
+	}
+	for _, cond := range c.conds {
+		firstCol := cond.firstCol
+		filter = mergeConj(filter, exprConvertVars(cond.expr,
+			func(expr parser.VariableExpr) (bool, parser.Expr) {
+				if iv, ok := expr.(*parser.IndexedVar); ok {
+					return true, ivar(firstCol + iv.Idx)
+				}
+				return true, expr
+			}))
+	}
+	plan, _, err := p.addJoinFilter(ctx, root, filter)
+	if err != nil {
+		return nil, err
+	}
+	if identity {
+		return plan, nil
+	}
+
+	// Restore the original order of the columns.
+	r := &renderNode{
+		planner: p,
+		source:  src,
+	}
+	r.sourceInfo = multiSourceInfo{src.info}
+	r.ivarHelper = parser.MakeIndexedVarHelper(r, len(cols))
+	for i, col := range c.columns {
+		iv := r.ivarHelper.IndexedVar(newCols[i])
+		r.addRenderColumn(iv, symbolicExprStr(iv), col)
+	}
+	r.numOriginalCols = len(r.columns)
+	return r, nil
+}
+
+// buildJoinSubtree creates the plan for a subtree of joins, without the join
+// conditions. It also returns the global indices of the columns of the plan.
+func (p *planner) buildJoinSubtree(
+	c *joinCluster, t *joinTree,
+) (planDataSource, []int, error) {
+	if t.leaf >= 0 {
+		leaf := &c.leaves[t.leaf]
+		cols := make([]int, len(planColumns(leaf.source.plan)))
+		for i := range cols {
+			cols[i] = leaf.firstCol + i
+		}
+		return leaf.source, cols, nil
+	}
+
+	left, leftCols, err := p.buildJoinSubtree(c, t.left)
+	if err != nil {
+		return planDataSource{}, nil, err
+	}
+	right, rightCols, err := p.buildJoinSubtree(c, t.right)
+	if err != nil {
+		return planDataSource{}, nil, err
+	}
+	pred, info, err := makeCrossPredicate(left.info, right.info)
+	if err != nil {
+		return planDataSource{}, nil, err
+	}
+	n := p.newJoinNode(joinTypeInner, left, right, pred, info)
+	n.reordered = true
+	n.lookupJoin = t.lookup
+	return planDataSource{info: info, plan: n}, append(leftCols, rightCols...), nil
+}
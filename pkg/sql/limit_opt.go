@@ -159,27 +159,39 @@ func applyLimit(plan planNode, numRows int64, soft bool) {
 	case *splitNode:
 		setUnlimited(n.rows)
 
+	case *recursiveCTENode:
+		setUnlimited(n.initial)
+
 	case *testingRelocateNode:
 		setUnlimited(n.rows)
 
 	case *valuesNode:
+	case *alterSequenceNode:
 	case *alterTableNode:
 	case *cancelQueryNode:
 	case *controlJobNode:
 	case *copyNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createPolicyNode:
+	case *createSequenceNode:
+	case *createStatsNode:
 	case *createUserNode:
 	case *createViewNode:
+	case *declareCursorNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
+	case *dropPolicyNode:
+	case *dropSequenceNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropUserNode:
+	case *fetchNode:
 	case *zeroNode:
 	case *unaryNode:
 	case *hookFnNode:
 	case *valueGenerator:
+	case *workTableScanNode:
 	case *setNode:
 	case *setClusterSettingNode:
 	case *showRangesNode:
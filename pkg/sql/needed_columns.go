@@ -79,6 +79,11 @@ func setNeededColumns(plan planNode, needed []bool) {
 	case *valuesNode:
 		markOmitted(n.columns, needed)
 
+	case *recursiveCTENode:
+		// Every row is added to the work table, so all the columns are
+		// needed.
+		setNeededColumns(n.initial, allColumns(n.initial))
+
 	case *delayedNode:
 		if n.plan != nil {
 			setNeededColumns(n.plan, needed)
@@ -183,23 +188,32 @@ func setNeededColumns(plan planNode, needed []bool) {
 	case *testingRelocateNode:
 		setNeededColumns(n.rows, allColumns(n.rows))
 
+	case *alterSequenceNode:
 	case *alterTableNode:
 	case *cancelQueryNode:
 	case *controlJobNode:
 	case *copyNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createPolicyNode:
+	case *createSequenceNode:
+	case *createStatsNode:
 	case *createUserNode:
 	case *createViewNode:
+	case *declareCursorNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
+	case *dropPolicyNode:
+	case *dropSequenceNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropUserNode:
+	case *fetchNode:
 	case *zeroNode:
 	case *unaryNode:
 	case *hookFnNode:
 	case *valueGenerator:
+	case *workTableScanNode:
 	case *setNode:
 	case *setClusterSettingNode:
 	case *showRangesNode:
@@ -151,7 +151,9 @@ var Aggregates = map[string][]Builtin{
 			ReturnType:    fixedReturnType(TypeInt),
 			AggregateFunc: newCountRowsAggregate,
 			WindowFunc: func(params []Type, evalCtx *EvalContext) WindowFunc {
-				return newAggregateWindow(newCountRowsAggregate(params, evalCtx))
+				return newAggregateWindow(func() AggregateFunc {
+					return newCountRowsAggregate(params, evalCtx)
+				})
 			},
 			Info: "Calculates the number of rows.",
 		},
@@ -232,7 +234,9 @@ func makeAggBuiltinWithReturnType(
 		ReturnType:    retType,
 		AggregateFunc: f,
 		WindowFunc: func(params []Type, evalCtx *EvalContext) WindowFunc {
-			return newAggregateWindow(f(params, evalCtx))
+			return newAggregateWindow(func() AggregateFunc {
+				return f(params, evalCtx)
+			})
 		},
 		Info: info,
 	}
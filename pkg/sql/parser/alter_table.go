@@ -60,19 +60,23 @@ type AlterTableCmd interface {
 
 func (*AlterTableAddColumn) alterTableCmd()          {}
 func (*AlterTableAddConstraint) alterTableCmd()      {}
+func (*AlterTableAlterColumnType) alterTableCmd()    {}
 func (*AlterTableDropColumn) alterTableCmd()         {}
 func (*AlterTableDropConstraint) alterTableCmd()     {}
 func (*AlterTableDropNotNull) alterTableCmd()        {}
 func (*AlterTableSetDefault) alterTableCmd()         {}
 func (*AlterTableValidateConstraint) alterTableCmd() {}
+func (*AlterTableRowLevelSecurity) alterTableCmd()   {}
 
 var _ AlterTableCmd = &AlterTableAddColumn{}
 var _ AlterTableCmd = &AlterTableAddConstraint{}
+var _ AlterTableCmd = &AlterTableAlterColumnType{}
 var _ AlterTableCmd = &AlterTableDropColumn{}
 var _ AlterTableCmd = &AlterTableDropConstraint{}
 var _ AlterTableCmd = &AlterTableDropNotNull{}
 var _ AlterTableCmd = &AlterTableSetDefault{}
 var _ AlterTableCmd = &AlterTableValidateConstraint{}
+var _ AlterTableCmd = &AlterTableRowLevelSecurity{}
 
 // ColumnMutationCmd is the subset of AlterTableCmds that modify an
 // existing column.
@@ -178,6 +182,21 @@ func (node *AlterTableValidateConstraint) Format(buf *bytes.Buffer, f FmtFlags)
 	FormatNode(buf, f, node.Constraint)
 }
 
+// AlterTableRowLevelSecurity represents an ENABLE ROW LEVEL SECURITY or
+// DISABLE ROW LEVEL SECURITY command.
+type AlterTableRowLevelSecurity struct {
+	Enable bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableRowLevelSecurity) Format(buf *bytes.Buffer, f FmtFlags) {
+	if node.Enable {
+		buf.WriteString("ENABLE ROW LEVEL SECURITY")
+	} else {
+		buf.WriteString("DISABLE ROW LEVEL SECURITY")
+	}
+}
+
 // AlterTableSetDefault represents an ALTER COLUMN SET DEFAULT
 // or DROP DEFAULT command.
 type AlterTableSetDefault struct {
@@ -206,6 +225,29 @@ func (node *AlterTableSetDefault) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// AlterTableAlterColumnType represents an ALTER COLUMN TYPE command.
+type AlterTableAlterColumnType struct {
+	columnKeyword bool
+	Column        Name
+	ToType        ColumnType
+}
+
+// GetColumn implements the ColumnMutationCmd interface.
+func (node *AlterTableAlterColumnType) GetColumn() Name {
+	return node.Column
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableAlterColumnType) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("ALTER ")
+	if node.columnKeyword {
+		buf.WriteString("COLUMN ")
+	}
+	FormatNode(buf, f, node.Column)
+	buf.WriteString(" TYPE ")
+	FormatNode(buf, f, node.ToType)
+}
+
 // AlterTableDropNotNull represents an ALTER COLUMN DROP NOT NULL
 // command.
 type AlterTableDropNotNull struct {
@@ -43,6 +43,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -86,6 +87,8 @@ const (
 	categoryString        = "String and Byte"
 	categoryArray         = "Array"
 	categorySystemInfo    = "System Info"
+	categorySequences     = "Sequence"
+	categoryJSON          = "JSONB"
 )
 
 // Builtin is a built-in function.
@@ -164,7 +167,7 @@ func (b Builtin) preferred() bool {
 
 func categorizeType(t Type) string {
 	switch t {
-	case TypeDate, TypeInterval, TypeTimestamp, TypeTimestampTZ:
+	case TypeDate, TypeInterval, TypeTimestamp, TypeTimestampTZ, TypeTime, TypeTimeTZ:
 		return categoryDateAndTime
 	case TypeInt, TypeDecimal, TypeFloat:
 		return categoryMath
@@ -306,7 +309,7 @@ var Builtins = map[string][]Builtin{
 	// NULL arguments are ignored.
 	"concat": {
 		Builtin{
-			Types:        VariadicType{TypeString},
+			Types:        VariadicType{Typ: TypeString},
 			ReturnType:   fixedReturnType(TypeString),
 			nullableArgs: true,
 			fn: func(evalCtx *EvalContext, args Datums) (Datum, error) {
@@ -329,7 +332,7 @@ var Builtins = map[string][]Builtin{
 
 	"concat_ws": {
 		Builtin{
-			Types:        VariadicType{TypeString},
+			Types:        VariadicType{Typ: TypeString},
 			ReturnType:   fixedReturnType(TypeString),
 			nullableArgs: true,
 			fn: func(evalCtx *EvalContext, args Datums) (Datum, error) {
@@ -910,6 +913,119 @@ CockroachDB supports the following flags:
 	"experimental_uuid_v4": {uuidV4Impl},
 	"uuid_v4":              {uuidV4Impl},
 
+	// Sequence functions.
+
+	"nextval": {
+		Builtin{
+			Types:            ArgTypes{{"sequence_name", TypeString}},
+			ReturnType:       fixedReturnType(TypeInt),
+			category:         categorySequences,
+			impure:           true,
+			distsqlBlacklist: true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				seqName, err := evalSequenceName(ctx, "nextval", args[0])
+				if err != nil {
+					return nil, err
+				}
+				res, err := ctx.Planner.IncrementSequence(ctx.Ctx(), seqName)
+				if err != nil {
+					return nil, err
+				}
+				return NewDInt(DInt(res)), nil
+			},
+			Info: "Advances the given sequence and returns its new value.",
+		},
+	},
+
+	"currval": {
+		Builtin{
+			Types:            ArgTypes{{"sequence_name", TypeString}},
+			ReturnType:       fixedReturnType(TypeInt),
+			category:         categorySequences,
+			impure:           true,
+			distsqlBlacklist: true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				seqName, err := evalSequenceName(ctx, "currval", args[0])
+				if err != nil {
+					return nil, err
+				}
+				res, err := ctx.Planner.GetLatestValueInSessionForSequence(ctx.Ctx(), seqName)
+				if err != nil {
+					return nil, err
+				}
+				return NewDInt(DInt(res)), nil
+			},
+			Info: "Returns the latest value obtained with nextval for this sequence in this session.",
+		},
+	},
+
+	"lastval": {
+		Builtin{
+			Types:            ArgTypes{},
+			ReturnType:       fixedReturnType(TypeInt),
+			category:         categorySequences,
+			impure:           true,
+			distsqlBlacklist: true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				if ctx.Planner == nil {
+					return nil, errSequenceFuncUnsupported("lastval")
+				}
+				res, err := ctx.Planner.GetLastSequenceValue(ctx.Ctx())
+				if err != nil {
+					return nil, err
+				}
+				return NewDInt(DInt(res)), nil
+			},
+			Info: "Return value most recently obtained with nextval in this session.",
+		},
+	},
+
+	"setval": {
+		Builtin{
+			Types:            ArgTypes{{"sequence_name", TypeString}, {"value", TypeInt}},
+			ReturnType:       fixedReturnType(TypeInt),
+			category:         categorySequences,
+			impure:           true,
+			distsqlBlacklist: true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				seqName, err := evalSequenceName(ctx, "setval", args[0])
+				if err != nil {
+					return nil, err
+				}
+				newVal := MustBeDInt(args[1])
+				if err := ctx.Planner.SetSequenceValue(ctx.Ctx(), seqName, int64(newVal), true); err != nil {
+					return nil, err
+				}
+				return args[1], nil
+			},
+			Info: "Set the given sequence's current value. The next call to nextval will return " +
+				"`value + Increment`",
+		},
+		Builtin{
+			Types: ArgTypes{
+				{"sequence_name", TypeString}, {"value", TypeInt}, {"is_called", TypeBool},
+			},
+			ReturnType:       fixedReturnType(TypeInt),
+			category:         categorySequences,
+			impure:           true,
+			distsqlBlacklist: true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				seqName, err := evalSequenceName(ctx, "setval", args[0])
+				if err != nil {
+					return nil, err
+				}
+				isCalled := bool(*args[2].(*DBool))
+				newVal := MustBeDInt(args[1])
+				if err := ctx.Planner.SetSequenceValue(ctx.Ctx(), seqName, int64(newVal), isCalled); err != nil {
+					return nil, err
+				}
+				return args[1], nil
+			},
+			Info: "Set the given sequence's current value. If is_called is false, the next call " +
+				"to nextval will return `value`; otherwise `value + Increment`.",
+		},
+	},
+
 	"greatest": {
 		Builtin{
 			Types:        HomogeneousType{},
@@ -1128,6 +1244,76 @@ CockroachDB supports the following flags:
 				"Compatible elements: year, quarter, month, week, dayofweek, dayofyear,\n" +
 				"hour, minute, second, millisecond, microsecond, epoch",
 		},
+		Builtin{
+			Types:      ArgTypes{{"element", TypeString}, {"input", TypeTime}},
+			ReturnType: fixedReturnType(TypeInt),
+			category:   categoryDateAndTime,
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				timeSpan := strings.ToLower(string(MustBeDString(args[0])))
+				fromTime := *args[1].(*DTime)
+				if timeSpan == "epoch" {
+					return NewDInt(DInt(int64(fromTime) / microsPerSecond)), nil
+				}
+				return extractStringFromTime(fromTime, timeSpan)
+			},
+			Info: "Extracts `element` from `input`.\n\n" +
+				"Compatible elements: hour, minute, second, millisecond, microsecond, epoch",
+		},
+		Builtin{
+			Types:      ArgTypes{{"element", TypeString}, {"input", TypeTimeTZ}},
+			ReturnType: fixedReturnType(TypeInt),
+			category:   categoryDateAndTime,
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				timeSpan := strings.ToLower(string(MustBeDString(args[0])))
+				fromTime := args[1].(*DTimeTZ)
+				switch timeSpan {
+				case "epoch":
+					return NewDInt(DInt(fromTime.UTCMicros() / microsPerSecond)), nil
+				case "timezone":
+					return NewDInt(DInt(fromTime.OffsetSecs)), nil
+				case "timezone_hour":
+					return NewDInt(DInt(fromTime.OffsetSecs / (60 * 60))), nil
+				case "timezone_minute":
+					return NewDInt(DInt(fromTime.OffsetSecs % (60 * 60) / 60)), nil
+				}
+				return extractStringFromTime(fromTime.Time, timeSpan)
+			},
+			Info: "Extracts `element` from `input`.\n\n" +
+				"Compatible elements: hour, minute, second, millisecond, microsecond, epoch,\n" +
+				"timezone, timezone_hour, timezone_minute",
+		},
+	},
+
+	"date_trunc": {
+		Builtin{
+			Types:      ArgTypes{{"element", TypeString}, {"input", TypeTime}},
+			ReturnType: fixedReturnType(TypeTime),
+			category:   categoryDateAndTime,
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				timeSpan := strings.ToLower(string(MustBeDString(args[0])))
+				return truncateTime(*args[1].(*DTime), timeSpan)
+			},
+			Info: "Truncates `input` to precision `element`. Sets all fields that are less\n" +
+				"significant than `element` to zero.\n\n" +
+				"Compatible elements: hour, minute, second, millisecond, microsecond.",
+		},
+		Builtin{
+			Types:      ArgTypes{{"element", TypeString}, {"input", TypeTimeTZ}},
+			ReturnType: fixedReturnType(TypeTimeTZ),
+			category:   categoryDateAndTime,
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				timeSpan := strings.ToLower(string(MustBeDString(args[0])))
+				fromTime := args[1].(*DTimeTZ)
+				t, err := truncateTime(fromTime.Time, timeSpan)
+				if err != nil {
+					return nil, err
+				}
+				return &DTimeTZ{Time: *t, OffsetSecs: fromTime.OffsetSecs}, nil
+			},
+			Info: "Truncates `input` to precision `element`. Sets all fields that are less\n" +
+				"significant than `element` to zero. The time zone is left unchanged.\n\n" +
+				"Compatible elements: hour, minute, second, millisecond, microsecond.",
+		},
 	},
 
 	"extract_duration": {
@@ -1725,6 +1911,110 @@ CockroachDB supports the following flags:
 		}
 	}),
 
+	// JSON functions.
+
+	"jsonb_typeof": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeJSON}},
+			ReturnType: fixedReturnType(TypeString),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				return NewDString(jsonTypeNames[MustBeDJSON(args[0]).Type()]), nil
+			},
+			category: categoryJSON,
+			Info:     "Returns the type of the outermost JSON value as a text string.",
+		},
+	},
+
+	"jsonb_array_length": {
+		Builtin{
+			Types:      ArgTypes{{"json", TypeJSON}},
+			ReturnType: fixedReturnType(TypeInt),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				n, ok := json.ArrayLength(MustBeDJSON(args[0]).JSON)
+				if !ok {
+					return nil, pgerror.NewError(
+						pgerror.CodeInvalidParameterValueError, "cannot get array length of a non-array")
+				}
+				return NewDInt(DInt(n)), nil
+			},
+			category: categoryJSON,
+			Info:     "Returns the number of elements in the outermost JSON or JSONB array.",
+		},
+	},
+
+	"jsonb_pretty": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeJSON}},
+			ReturnType: fixedReturnType(TypeString),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				s, err := json.Pretty(MustBeDJSON(args[0]).JSON)
+				if err != nil {
+					return nil, err
+				}
+				return NewDString(s), nil
+			},
+			category: categoryJSON,
+			Info:     "Returns the given JSON value as a STRING indented and with newlines.",
+		},
+	},
+
+	"jsonb_strip_nulls": {
+		Builtin{
+			Types:      ArgTypes{{"from_json", TypeJSON}},
+			ReturnType: fixedReturnType(TypeJSON),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				return NewDJSON(json.StripNulls(MustBeDJSON(args[0]).JSON)), nil
+			},
+			category: categoryJSON,
+			Info: "Returns from_json with all object fields that have null values omitted. " +
+				"Other null values are untouched.",
+		},
+	},
+
+	"jsonb_extract_path": {
+		Builtin{
+			Types:      VariadicType{FixedTypes: []Type{TypeJSON}, Typ: TypeString},
+			ReturnType: fixedReturnType(TypeJSON),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				j := jsonExtractPath(args)
+				if j == nil {
+					return DNull, nil
+				}
+				return NewDJSON(j), nil
+			},
+			category: categoryJSON,
+			Info:     "Returns the JSON value pointed to by the variadic arguments.",
+		},
+	},
+
+	"jsonb_extract_path_text": {
+		Builtin{
+			Types:      VariadicType{FixedTypes: []Type{TypeJSON}, Typ: TypeString},
+			ReturnType: fixedReturnType(TypeString),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				return jsonAsText(jsonExtractPath(args)), nil
+			},
+			category: categoryJSON,
+			Info:     "Returns the JSON value as text pointed to by the variadic arguments.",
+		},
+	},
+
+	"to_jsonb": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeAny}},
+			ReturnType: fixedReturnType(TypeJSON),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				j, err := asJSON(args[0])
+				if err != nil {
+					return nil, err
+				}
+				return NewDJSON(j), nil
+			},
+			category: categoryJSON,
+			Info:     "Returns the value as JSON or JSONB.",
+		},
+	},
+
 	// Metadata functions.
 
 	"version": {
@@ -1823,6 +2113,30 @@ CockroachDB supports the following flags:
 		},
 	},
 
+	"current_setting": {
+		Builtin{
+			Types:            ArgTypes{{"setting_name", TypeString}},
+			ReturnType:       fixedReturnType(TypeString),
+			category:         categorySystemInfo,
+			distsqlBlacklist: true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				return evalCurrentSetting(ctx, string(MustBeDString(args[0])), false /* missingOk */)
+			},
+			Info: "Returns the current value of the session variable `setting_name`.",
+		},
+		Builtin{
+			Types:            ArgTypes{{"setting_name", TypeString}, {"missing_ok", TypeBool}},
+			ReturnType:       fixedReturnType(TypeString),
+			category:         categorySystemInfo,
+			distsqlBlacklist: true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				return evalCurrentSetting(ctx, string(MustBeDString(args[0])), bool(*args[1].(*DBool)))
+			},
+			Info: "Returns the current value of the session variable `setting_name`, or NULL " +
+				"if `missing_ok` is true and the variable does not exist.",
+		},
+	},
+
 	"crdb_internal.cluster_id": {
 		Builtin{
 			Types:      ArgTypes{},
@@ -2065,6 +2379,45 @@ var uuidV4Impl = Builtin{
 	Info: "Returns a UUID.",
 }
 
+// evalSequenceName parses and qualifies the sequence name passed to the
+// sequence builtin fnName.
+func evalSequenceName(ctx *EvalContext, fnName string, arg Datum) (*TableName, error) {
+	if ctx.Planner == nil {
+		return nil, errSequenceFuncUnsupported(fnName)
+	}
+	tn, err := ParseTableName(string(MustBeDString(arg)))
+	if err != nil {
+		return nil, err
+	}
+	return ctx.Planner.QualifyWithDatabase(ctx.Ctx(), &NormalizableTableName{TableNameReference: tn})
+}
+
+// evalCurrentSetting returns the value of the session variable name for
+// current_setting().
+func evalCurrentSetting(ctx *EvalContext, name string, missingOk bool) (Datum, error) {
+	if ctx.Planner == nil {
+		return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"current_setting() cannot be used outside of a SQL session")
+	}
+	val, ok := ctx.Planner.GetSessionVar(ctx.Ctx(), name)
+	if !ok {
+		if missingOk {
+			return DNull, nil
+		}
+		return nil, pgerror.NewErrorf(pgerror.CodeUndefinedObjectError,
+			"unrecognized configuration parameter %q", name)
+	}
+	return NewDString(val), nil
+}
+
+// errSequenceFuncUnsupported is returned when a sequence builtin is
+// evaluated outside of a SQL session, for example while backfilling a
+// column added with a DEFAULT expression.
+func errSequenceFuncUnsupported(fnName string) error {
+	return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+		"%s() cannot be evaluated in this context", fnName)
+}
+
 var ceilImpl = []Builtin{
 	floatBuiltin1(func(x float64) (Datum, error) {
 		return NewDFloat(DFloat(math.Ceil(x))), nil
@@ -2271,7 +2624,7 @@ func feedHash(h hash.Hash, args Datums) {
 func hashBuiltin(newHash func() hash.Hash, info string) []Builtin {
 	return []Builtin{
 		{
-			Types:        VariadicType{TypeString},
+			Types:        VariadicType{Typ: TypeString},
 			ReturnType:   fixedReturnType(TypeString),
 			nullableArgs: true,
 			fn: func(_ *EvalContext, args Datums) (Datum, error) {
@@ -2282,7 +2635,7 @@ func hashBuiltin(newHash func() hash.Hash, info string) []Builtin {
 			Info: info,
 		},
 		{
-			Types:        VariadicType{TypeBytes},
+			Types:        VariadicType{Typ: TypeBytes},
 			ReturnType:   fixedReturnType(TypeString),
 			nullableArgs: true,
 			fn: func(_ *EvalContext, args Datums) (Datum, error) {
@@ -2298,7 +2651,7 @@ func hashBuiltin(newHash func() hash.Hash, info string) []Builtin {
 func hash32Builtin(newHash func() hash.Hash32, info string) []Builtin {
 	return []Builtin{
 		{
-			Types:        VariadicType{TypeString},
+			Types:        VariadicType{Typ: TypeString},
 			ReturnType:   fixedReturnType(TypeInt),
 			nullableArgs: true,
 			fn: func(_ *EvalContext, args Datums) (Datum, error) {
@@ -2309,7 +2662,7 @@ func hash32Builtin(newHash func() hash.Hash32, info string) []Builtin {
 			Info: info,
 		},
 		{
-			Types:        VariadicType{TypeBytes},
+			Types:        VariadicType{Typ: TypeBytes},
 			ReturnType:   fixedReturnType(TypeInt),
 			nullableArgs: true,
 			fn: func(_ *EvalContext, args Datums) (Datum, error) {
@@ -2324,7 +2677,7 @@ func hash32Builtin(newHash func() hash.Hash32, info string) []Builtin {
 func hash64Builtin(newHash func() hash.Hash64, info string) []Builtin {
 	return []Builtin{
 		{
-			Types:        VariadicType{TypeString},
+			Types:        VariadicType{Typ: TypeString},
 			ReturnType:   fixedReturnType(TypeInt),
 			nullableArgs: true,
 			fn: func(_ *EvalContext, args Datums) (Datum, error) {
@@ -2335,7 +2688,7 @@ func hash64Builtin(newHash func() hash.Hash64, info string) []Builtin {
 			Info: info,
 		},
 		{
-			Types:        VariadicType{TypeBytes},
+			Types:        VariadicType{Typ: TypeBytes},
 			ReturnType:   fixedReturnType(TypeInt),
 			nullableArgs: true,
 			fn: func(_ *EvalContext, args Datums) (Datum, error) {
@@ -2772,3 +3125,122 @@ func extractStringFromTimestamp(
 		return nil, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError, "unsupported timespan: %s", timeSpan)
 	}
 }
+
+// extractStringFromTime extracts timeSpan from a time of day.
+func extractStringFromTime(fromTime DTime, timeSpan string) (Datum, error) {
+	micros := int64(fromTime)
+	switch timeSpan {
+	case "hour", "hours":
+		return NewDInt(DInt(micros / microsPerHour)), nil
+
+	case "minute", "minutes":
+		return NewDInt(DInt(micros % microsPerHour / microsPerMinute)), nil
+
+	case "second", "seconds":
+		return NewDInt(DInt(micros % microsPerMinute / microsPerSecond)), nil
+
+	case "millisecond", "milliseconds":
+		return NewDInt(DInt(micros % microsPerSecond / 1000)), nil
+
+	case "microsecond", "microseconds":
+		return NewDInt(DInt(micros % microsPerSecond)), nil
+
+	default:
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError, "unsupported timespan: %s", timeSpan)
+	}
+}
+
+// truncateTime truncates a time of day to the precision timeSpan.
+func truncateTime(fromTime DTime, timeSpan string) (*DTime, error) {
+	var unit DTime
+	switch timeSpan {
+	case "hour", "hours":
+		unit = DTime(microsPerHour)
+	case "minute", "minutes":
+		unit = DTime(microsPerMinute)
+	case "second", "seconds":
+		unit = DTime(microsPerSecond)
+	case "millisecond", "milliseconds":
+		unit = 1000
+	case "microsecond", "microseconds":
+		unit = 1
+	default:
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError, "unsupported timespan: %s", timeSpan)
+	}
+	t := fromTime - fromTime%unit
+	return &t, nil
+}
+
+var jsonTypeNames = map[json.Type]string{
+	json.NullJSONType:   "null",
+	json.StringJSONType: "string",
+	json.NumberJSONType: "number",
+	json.FalseJSONType:  "boolean",
+	json.TrueJSONType:   "boolean",
+	json.ArrayJSONType:  "array",
+	json.ObjectJSONType: "object",
+}
+
+// jsonExtractPath follows the path given by args[1:] into the JSON document
+// args[0], treating each path element as an object key or, for arrays, as an
+// integer index. It returns nil if the path does not exist.
+func jsonExtractPath(args Datums) json.JSON {
+	j := MustBeDJSON(args[0]).JSON
+	for _, a := range args[1:] {
+		key := string(MustBeDString(a))
+		if j.Type() == json.ArrayJSONType {
+			idx, err := strconv.Atoi(key)
+			if err != nil {
+				return nil
+			}
+			j = j.FetchValIdx(idx)
+		} else {
+			j = j.FetchValKey(key)
+		}
+		if j == nil {
+			return nil
+		}
+	}
+	return j
+}
+
+// asJSON converts a datum to a JSON document. Booleans, numbers, strings and
+// arrays are converted to their JSON counterparts; other values are
+// converted to JSON strings of their text representation.
+func asJSON(d Datum) (json.JSON, error) {
+	switch t := d.(type) {
+	case *DBool:
+		return json.FromBool(bool(*t)), nil
+	case *DInt:
+		return json.FromInt(int64(*t)), nil
+	case *DFloat:
+		var dec apd.Decimal
+		if _, err := dec.SetFloat64(float64(*t)); err != nil {
+			return nil, err
+		}
+		return json.FromDecimal(dec), nil
+	case *DDecimal:
+		return json.FromDecimal(t.Decimal), nil
+	case *DString:
+		return json.FromString(string(*t)), nil
+	case *DCollatedString:
+		return json.FromString(t.Contents), nil
+	case *DJSON:
+		return t.JSON, nil
+	case *DArray:
+		elems := make([]json.JSON, len(t.Array))
+		for i, e := range t.Array {
+			if e == DNull {
+				elems[i] = json.NullJSONValue
+				continue
+			}
+			var err error
+			if elems[i], err = asJSON(e); err != nil {
+				return nil, err
+			}
+		}
+		return json.FromArray(elems), nil
+	default:
+		return json.FromString(AsStringWithFlags(d, FmtBareStrings)), nil
+	}
+}
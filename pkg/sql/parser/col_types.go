@@ -44,11 +44,14 @@ func (*IntColType) columnType()            {}
 func (*FloatColType) columnType()          {}
 func (*DecimalColType) columnType()        {}
 func (*DateColType) columnType()           {}
+func (*TimeColType) columnType()           {}
+func (*TimeTZColType) columnType()         {}
 func (*TimestampColType) columnType()      {}
 func (*TimestampTZColType) columnType()    {}
 func (*IntervalColType) columnType()       {}
 func (*UUIDColType) columnType()           {}
 func (*IPAddrColType) columnType()         {}
+func (*JSONColType) columnType()           {}
 func (*StringColType) columnType()         {}
 func (*NameColType) columnType()           {}
 func (*BytesColType) columnType()          {}
@@ -63,11 +66,14 @@ func (*IntColType) castTargetType()            {}
 func (*FloatColType) castTargetType()          {}
 func (*DecimalColType) castTargetType()        {}
 func (*DateColType) castTargetType()           {}
+func (*TimeColType) castTargetType()           {}
+func (*TimeTZColType) castTargetType()         {}
 func (*TimestampColType) castTargetType()      {}
 func (*TimestampTZColType) castTargetType()    {}
 func (*IntervalColType) castTargetType()       {}
 func (*UUIDColType) castTargetType()           {}
 func (*IPAddrColType) castTargetType()         {}
+func (*JSONColType) castTargetType()           {}
 func (*StringColType) castTargetType()         {}
 func (*NameColType) castTargetType()           {}
 func (*BytesColType) castTargetType()          {}
@@ -249,6 +255,30 @@ func (node *DateColType) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString("DATE")
 }
 
+// Pre-allocated immutable time column type.
+var timeColTypeTime = &TimeColType{}
+
+// TimeColType represents a TIME type.
+type TimeColType struct {
+}
+
+// Format implements the NodeFormatter interface.
+func (node *TimeColType) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("TIME")
+}
+
+// Pre-allocated immutable time with time zone column type.
+var timeTZColTypeTimeWithTZ = &TimeTZColType{}
+
+// TimeTZColType represents a TIME WITH TIME ZONE type.
+type TimeTZColType struct {
+}
+
+// Format implements the NodeFormatter interface.
+func (node *TimeTZColType) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("TIME WITH TIME ZONE")
+}
+
 // Pre-allocated immutable timestamp column type.
 var timestampColTypeTimestamp = &TimestampColType{}
 
@@ -312,6 +342,22 @@ func (node *IPAddrColType) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString(node.Name)
 }
 
+// Pre-allocated immutable JSON column types.
+var (
+	jsonColTypeJSON  = &JSONColType{Name: "JSON"}
+	jsonColTypeJSONB = &JSONColType{Name: "JSONB"}
+)
+
+// JSONColType represents the JSON column type.
+type JSONColType struct {
+	Name string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *JSONColType) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString(node.Name)
+}
+
 // Pre-allocated immutable string column types.
 var (
 	stringColTypeChar    = &StringColType{Name: "CHAR"}
@@ -489,11 +535,14 @@ func (node *IntColType) String() string            { return AsString(node) }
 func (node *FloatColType) String() string          { return AsString(node) }
 func (node *DecimalColType) String() string        { return AsString(node) }
 func (node *DateColType) String() string           { return AsString(node) }
+func (node *TimeColType) String() string           { return AsString(node) }
+func (node *TimeTZColType) String() string         { return AsString(node) }
 func (node *TimestampColType) String() string      { return AsString(node) }
 func (node *TimestampTZColType) String() string    { return AsString(node) }
 func (node *IntervalColType) String() string       { return AsString(node) }
 func (node *UUIDColType) String() string           { return AsString(node) }
 func (node *IPAddrColType) String() string         { return AsString(node) }
+func (node *JSONColType) String() string           { return AsString(node) }
 func (node *StringColType) String() string         { return AsString(node) }
 func (node *NameColType) String() string           { return AsString(node) }
 func (node *BytesColType) String() string          { return AsString(node) }
@@ -515,6 +564,10 @@ func DatumTypeToColumnType(t Type) (ColumnType, error) {
 		return floatColTypeFloat, nil
 	case TypeDecimal:
 		return decimalColTypeDecimal, nil
+	case TypeTime:
+		return timeColTypeTime, nil
+	case TypeTimeTZ:
+		return timeTZColTypeTimeWithTZ, nil
 	case TypeTimestamp:
 		return timestampColTypeTimestamp, nil
 	case TypeTimestampTZ:
@@ -525,6 +578,8 @@ func DatumTypeToColumnType(t Type) (ColumnType, error) {
 		return uuidColTypeUUID, nil
 	case TypeINet:
 		return ipnetColTypeINet, nil
+	case TypeJSON:
+		return jsonColTypeJSONB, nil
 	case TypeDate:
 		return dateColTypeDate, nil
 	case TypeString:
@@ -579,6 +634,10 @@ func CastTargetToDatumType(t CastTargetType) Type {
 		return TypeBytes
 	case *DateColType:
 		return TypeDate
+	case *TimeColType:
+		return TypeTime
+	case *TimeTZColType:
+		return TypeTimeTZ
 	case *TimestampColType:
 		return TypeTimestamp
 	case *TimestampTZColType:
@@ -589,6 +648,8 @@ func CastTargetToDatumType(t CastTargetType) Type {
 		return TypeUUID
 	case *IPAddrColType:
 		return TypeINet
+	case *JSONColType:
+		return TypeJSON
 	case *CollatedStringColType:
 		return TCollatedString{Locale: ct.Locale}
 	case *ArrayColType:
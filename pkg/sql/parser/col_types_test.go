@@ -51,6 +51,8 @@ func TestParseColumnType(t *testing.T) {
 		{"UUID", &UUIDColType{}},
 		{"INET", &IPAddrColType{Name: "INET"}},
 		{"DATE", &DateColType{}},
+		{"TIME", &TimeColType{}},
+		{"TIME WITH TIME ZONE", &TimeTZColType{}},
 		{"TIMESTAMP", &TimestampColType{}},
 		{"TIMESTAMP WITH TIME ZONE", &TimestampTZColType{}},
 		{"INTERVAL", &IntervalColType{}},
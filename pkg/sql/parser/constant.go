@@ -349,8 +349,11 @@ var (
 		TypeTimestamp,
 		TypeTimestampTZ,
 		TypeInterval,
+		TypeTime,
+		TypeTimeTZ,
 		TypeUUID,
 		TypeINet,
+		TypeJSON,
 	}
 	strValAvailBytesString = []Type{TypeBytes, TypeString, TypeUUID, TypeINet}
 	strValAvailBytes       = []Type{TypeBytes, TypeUUID}
@@ -427,12 +430,18 @@ func (expr *StrVal) ResolveAsType(ctx *SemaContext, typ Type) (Datum, error) {
 		return ParseDDate(expr.s, ctx.getLocation())
 	case TypeINet:
 		return ParseDIPAddrFromINetString(expr.s)
+	case TypeJSON:
+		return ParseDJSON(expr.s)
 	case TypeTimestamp:
 		return ParseDTimestamp(expr.s, time.Microsecond)
 	case TypeTimestampTZ:
 		return ParseDTimestampTZ(expr.s, ctx.getLocation(), time.Microsecond)
 	case TypeInterval:
 		return ParseDInterval(expr.s)
+	case TypeTime:
+		return ParseDTime(expr.s)
+	case TypeTimeTZ:
+		return ParseDTimeTZ(expr.s, ctx.getLocation())
 	case TypeUUID:
 		if expr.bytesEsc {
 			return ParseDUuidFromBytes([]byte(expr.s))
@@ -209,6 +209,20 @@ func mustParseDTimestampTZ(t *testing.T, s string) Datum {
 	}
 	return d
 }
+func mustParseDTime(t *testing.T, s string) Datum {
+	d, err := ParseDTime(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+func mustParseDTimeTZ(t *testing.T, s string) Datum {
+	d, err := ParseDTimeTZ(s, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
 func mustParseDInterval(t *testing.T, s string) Datum {
 	d, err := ParseDInterval(s)
 	if err != nil {
@@ -225,6 +239,8 @@ var parseFuncs = map[Type]func(*testing.T, string) Datum{
 	TypeTimestamp:   mustParseDTimestamp,
 	TypeTimestampTZ: mustParseDTimestampTZ,
 	TypeInterval:    mustParseDInterval,
+	TypeTime:        mustParseDTime,
+	TypeTimeTZ:      mustParseDTimeTZ,
 }
 
 func typeSet(types ...Type) map[Type]struct{} {
@@ -258,12 +274,18 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 			parseOptions: typeSet(TypeString, TypeBytes, TypeDate, TypeTimestamp, TypeTimestampTZ),
 		},
 		{
-			c:            &StrVal{s: "2010-09-28 12:00:00.1", bytesEsc: false},
-			parseOptions: typeSet(TypeString, TypeBytes, TypeTimestamp, TypeTimestampTZ, TypeDate),
+			c: &StrVal{s: "2010-09-28 12:00:00.1", bytesEsc: false},
+			parseOptions: typeSet(TypeString, TypeBytes, TypeTimestamp, TypeTimestampTZ, TypeDate,
+				TypeTime, TypeTimeTZ),
+		},
+		{
+			c: &StrVal{s: "2006-07-08T00:00:00.000000123Z", bytesEsc: false},
+			parseOptions: typeSet(TypeString, TypeBytes, TypeTimestamp, TypeTimestampTZ, TypeDate,
+				TypeTime, TypeTimeTZ),
 		},
 		{
-			c:            &StrVal{s: "2006-07-08T00:00:00.000000123Z", bytesEsc: false},
-			parseOptions: typeSet(TypeString, TypeBytes, TypeTimestamp, TypeTimestampTZ, TypeDate),
+			c:            &StrVal{s: "12:34:56.789", bytesEsc: false},
+			parseOptions: typeSet(TypeString, TypeBytes, TypeTime, TypeTimeTZ),
 		},
 		{
 			c:            &StrVal{s: "PT12H2M", bytesEsc: false},
@@ -66,15 +66,27 @@ func (node *CreateDatabase) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
-// IndexElem represents a column with a direction in a CREATE INDEX statement.
+// IndexElem represents a column or an expression with a direction in a
+// CREATE INDEX statement.
 type IndexElem struct {
-	Column    Name
+	Column Name
+	// Expr is the indexed expression, if the element is an expression instead
+	// of a column.
+	Expr      Expr
 	Direction Direction
 }
 
 // Format implements the NodeFormatter interface.
 func (node IndexElem) Format(buf *bytes.Buffer, f FmtFlags) {
-	FormatNode(buf, f, node.Column)
+	if node.Expr == nil {
+		FormatNode(buf, f, node.Column)
+	} else if _, ok := node.Expr.(*FuncExpr); ok {
+		FormatNode(buf, f, node.Expr)
+	} else {
+		buf.WriteByte('(')
+		FormatNode(buf, f, node.Expr)
+		buf.WriteByte(')')
+	}
 	if node.Direction != DefaultDirection {
 		buf.WriteByte(' ')
 		buf.WriteString(node.Direction.String())
@@ -100,12 +112,16 @@ type CreateIndex struct {
 	Name        Name
 	Table       NormalizableTableName
 	Unique      bool
+	Inverted    bool
 	IfNotExists bool
 	Columns     IndexElemList
 	// Extra columns to be stored together with the indexed ones as an optimization
 	// for improved reading performance.
 	Storing    NameList
 	Interleave *InterleaveDef
+	// Predicate restricts a partial index to the rows it holds for. It is nil
+	// for a full index.
+	Predicate Expr
 }
 
 // Format implements the NodeFormatter interface.
@@ -114,6 +130,9 @@ func (node *CreateIndex) Format(buf *bytes.Buffer, f FmtFlags) {
 	if node.Unique {
 		buf.WriteString("UNIQUE ")
 	}
+	if node.Inverted {
+		buf.WriteString("INVERTED ")
+	}
 	buf.WriteString("INDEX ")
 	if node.IfNotExists {
 		buf.WriteString("IF NOT EXISTS ")
@@ -135,6 +154,10 @@ func (node *CreateIndex) Format(buf *bytes.Buffer, f FmtFlags) {
 	if node.Interleave != nil {
 		FormatNode(buf, f, node.Interleave)
 	}
+	if node.Predicate != nil {
+		buf.WriteString(" WHERE ")
+		FormatNode(buf, f, node.Predicate)
+	}
 }
 
 // TableDef represents a column, index or constraint definition within a CREATE
@@ -196,12 +219,17 @@ type ColumnTableDef struct {
 		Table          NormalizableTableName
 		Col            Name
 		ConstraintName Name
+		Actions        ReferenceActions
 	}
 	Family struct {
 		Name        Name
 		Create      bool
 		IfNotExists bool
 	}
+	Computed struct {
+		Computed bool
+		Expr     Expr
+	}
 }
 
 // ColumnTableDefCheckExpr represents a check constraint on a column definition
@@ -292,6 +320,7 @@ func newColumnTableDef(
 			d.References.Table = t.Table
 			d.References.Col = t.Col
 			d.References.ConstraintName = c.Name
+			d.References.Actions = t.Actions
 		case *ColumnFamilyConstraint:
 			if d.HasColumnFamily() {
 				return nil, pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
@@ -300,6 +329,13 @@ func newColumnTableDef(
 			d.Family.Name = t.Family
 			d.Family.Create = t.Create
 			d.Family.IfNotExists = t.IfNotExists
+		case *ColumnComputedDef:
+			if d.IsComputed() {
+				return nil, pgerror.NewErrorf(pgerror.CodeSyntaxError,
+					"multiple computed expressions specified for column %q", name)
+			}
+			d.Computed.Computed = true
+			d.Computed.Expr = t.Expr
 		default:
 			panic(fmt.Sprintf("unexpected column qualification: %T", c))
 		}
@@ -326,6 +362,11 @@ func (node *ColumnTableDef) HasColumnFamily() bool {
 	return node.Family.Name != "" || node.Family.Create
 }
 
+// IsComputed returns if the ColumnTableDef is a computed column.
+func (node *ColumnTableDef) IsComputed() bool {
+	return node.Computed.Computed
+}
+
 // Format implements the NodeFormatter interface.
 func (node *ColumnTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 	FormatNode(buf, f, node.Name)
@@ -375,6 +416,7 @@ func (node *ColumnTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 			FormatNode(buf, f, node.References.Col)
 			buf.WriteByte(')')
 		}
+		FormatNode(buf, f, &node.References.Actions)
 	}
 	if node.HasColumnFamily() {
 		if node.Family.Create {
@@ -389,6 +431,11 @@ func (node *ColumnTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 			FormatNode(buf, f, node.Family.Name)
 		}
 	}
+	if node.IsComputed() {
+		buf.WriteString(" AS (")
+		FormatNode(buf, f, node.Computed.Expr)
+		buf.WriteString(") STORED")
+	}
 }
 
 // NamedColumnQualification wraps a NamedColumnQualification with a name.
@@ -411,6 +458,7 @@ func (UniqueConstraint) columnQualification()        {}
 func (*ColumnCheckConstraint) columnQualification()  {}
 func (*ColumnFKConstraint) columnQualification()     {}
 func (*ColumnFamilyConstraint) columnQualification() {}
+func (*ColumnComputedDef) columnQualification()      {}
 
 // ColumnCollation represents a COLLATE clause for a column.
 type ColumnCollation string
@@ -439,8 +487,9 @@ type ColumnCheckConstraint struct {
 
 // ColumnFKConstraint represents a FK-constaint on a column.
 type ColumnFKConstraint struct {
-	Table NormalizableTableName
-	Col   Name // empty-string means use PK
+	Table   NormalizableTableName
+	Col     Name // empty-string means use PK
+	Actions ReferenceActions
 }
 
 // ColumnFamilyConstraint represents FAMILY on a column.
@@ -450,6 +499,11 @@ type ColumnFamilyConstraint struct {
 	IfNotExists bool
 }
 
+// ColumnComputedDef represents the description of a computed column.
+type ColumnComputedDef struct {
+	Expr Expr
+}
+
 // IndexTableDef represents an index definition within a CREATE TABLE
 // statement.
 type IndexTableDef struct {
@@ -457,6 +511,7 @@ type IndexTableDef struct {
 	Columns    IndexElemList
 	Storing    NameList
 	Interleave *InterleaveDef
+	Inverted   bool
 }
 
 func (node *IndexTableDef) setName(name Name) {
@@ -465,6 +520,9 @@ func (node *IndexTableDef) setName(name Name) {
 
 // Format implements the NodeFormatter interface.
 func (node *IndexTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
+	if node.Inverted {
+		buf.WriteString("INVERTED ")
+	}
 	buf.WriteString("INDEX ")
 	if node.Name != "" {
 		FormatNode(buf, f, node.Name)
@@ -532,6 +590,7 @@ type ForeignKeyConstraintTableDef struct {
 	Table    NormalizableTableName
 	FromCols NameList
 	ToCols   NameList
+	Actions  ReferenceActions
 }
 
 // Format implements the NodeFormatter interface.
@@ -552,6 +611,51 @@ func (node *ForeignKeyConstraintTableDef) Format(buf *bytes.Buffer, f FmtFlags)
 		FormatNode(buf, f, node.ToCols)
 		buf.WriteByte(')')
 	}
+	FormatNode(buf, f, &node.Actions)
+}
+
+// ReferenceAction is the action taken on the referencing rows of a foreign
+// key when a referenced row is deleted or updated.
+type ReferenceAction int
+
+// The values for ReferenceAction.
+const (
+	NoAction ReferenceAction = iota
+	Restrict
+	SetNull
+	SetDefault
+	Cascade
+)
+
+var referenceActionName = [...]string{
+	NoAction:   "NO ACTION",
+	Restrict:   "RESTRICT",
+	SetNull:    "SET NULL",
+	SetDefault: "SET DEFAULT",
+	Cascade:    "CASCADE",
+}
+
+func (ra ReferenceAction) String() string {
+	return referenceActionName[ra]
+}
+
+// ReferenceActions contains the actions of a foreign key on delete and on
+// update of a referenced row.
+type ReferenceActions struct {
+	Delete ReferenceAction
+	Update ReferenceAction
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ReferenceActions) Format(buf *bytes.Buffer, f FmtFlags) {
+	if node.Delete != NoAction {
+		buf.WriteString(" ON DELETE ")
+		buf.WriteString(node.Delete.String())
+	}
+	if node.Update != NoAction {
+		buf.WriteString(" ON UPDATE ")
+		buf.WriteString(node.Update.String())
+	}
 }
 
 func (node *ForeignKeyConstraintTableDef) setName(name Name) {
@@ -702,6 +806,50 @@ func (node *CreateUser) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// CreateRole represents a CREATE ROLE statement.
+type CreateRole struct {
+	Name Name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateRole) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE ROLE ")
+	FormatNode(buf, f, node.Name)
+}
+
+// CreatePolicy represents a CREATE POLICY statement.
+type CreatePolicy struct {
+	Name  Name
+	Table NormalizableTableName
+	// Roles is empty if the policy applies to all users.
+	Roles NameList
+	// Using and Check are nil if omitted.
+	Using Expr
+	Check Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreatePolicy) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE POLICY ")
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" ON ")
+	FormatNode(buf, f, &node.Table)
+	if len(node.Roles) > 0 {
+		buf.WriteString(" TO ")
+		FormatNode(buf, f, node.Roles)
+	}
+	if node.Using != nil {
+		buf.WriteString(" USING (")
+		FormatNode(buf, f, node.Using)
+		buf.WriteByte(')')
+	}
+	if node.Check != nil {
+		buf.WriteString(" WITH CHECK (")
+		FormatNode(buf, f, node.Check)
+		buf.WriteByte(')')
+	}
+}
+
 // CreateView represents a CREATE VIEW statement.
 type CreateView struct {
 	Name        NormalizableTableName
@@ -724,3 +872,92 @@ func (node *CreateView) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString(" AS ")
 	FormatNode(buf, f, node.AsSource)
 }
+
+// CreateSequence represents a CREATE SEQUENCE statement.
+type CreateSequence struct {
+	IfNotExists bool
+	Name        NormalizableTableName
+	Options     SequenceOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateSequence) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE SEQUENCE ")
+	if node.IfNotExists {
+		buf.WriteString("IF NOT EXISTS ")
+	}
+	FormatNode(buf, f, &node.Name)
+	FormatNode(buf, f, node.Options)
+}
+
+// SequenceOptions represents a list of sequence options.
+type SequenceOptions []SequenceOption
+
+// Format implements the NodeFormatter interface.
+func (node SequenceOptions) Format(buf *bytes.Buffer, f FmtFlags) {
+	for _, option := range node {
+		buf.WriteByte(' ')
+		switch option.Name {
+		case SeqOptNoCycle:
+			buf.WriteString(option.Name)
+		default:
+			if option.IntVal == nil {
+				// NO MINVALUE, NO MAXVALUE.
+				buf.WriteString("NO ")
+				buf.WriteString(option.Name)
+				continue
+			}
+			buf.WriteString(option.Name)
+			if option.OptionalWord {
+				switch option.Name {
+				case SeqOptIncrement:
+					buf.WriteString(" BY")
+				case SeqOptStart:
+					buf.WriteString(" WITH")
+				}
+			}
+			fmt.Fprintf(buf, " %d", *option.IntVal)
+		}
+	}
+}
+
+// SequenceOption represents an option on a CREATE SEQUENCE or ALTER
+// SEQUENCE statement.
+type SequenceOption struct {
+	Name string
+
+	// IntVal is the value of the option. It is nil for NO MINVALUE and NO
+	// MAXVALUE, which reset the bound to its default.
+	IntVal *int64
+	// OptionalWord is set if the option was specified with its optional
+	// keyword, as in INCREMENT BY or START WITH. It is only used for
+	// formatting.
+	OptionalWord bool
+}
+
+// Names of options on CREATE SEQUENCE and ALTER SEQUENCE.
+const (
+	SeqOptCache     = "CACHE"
+	SeqOptNoCycle   = "NO CYCLE"
+	SeqOptIncrement = "INCREMENT"
+	SeqOptMinValue  = "MINVALUE"
+	SeqOptMaxValue  = "MAXVALUE"
+	SeqOptStart     = "START"
+)
+
+// CreateStats represents a CREATE STATISTICS statement.
+type CreateStats struct {
+	Name        Name
+	ColumnNames NameList
+	Table       NormalizableTableName
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateStats) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE STATISTICS ")
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" ON ")
+	FormatNode(buf, f, node.ColumnNames)
+	buf.WriteString(" FROM ")
+	FormatNode(buf, f, &node.Table)
+}
@@ -0,0 +1,92 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package parser
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// DeclareCursor represents a DECLARE statement.
+type DeclareCursor struct {
+	Name   Name
+	Select *Select
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DeclareCursor) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("DECLARE ")
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" CURSOR FOR ")
+	FormatNode(buf, f, node.Select)
+}
+
+// CursorStmt is the part common to the FETCH and MOVE statements: the cursor
+// and the number of rows to retrieve from it.
+type CursorStmt struct {
+	Name  Name
+	Count int64
+	// All is set when all the remaining rows are retrieved, in which case
+	// Count is ignored.
+	All bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CursorStmt) Format(buf *bytes.Buffer, f FmtFlags) {
+	if node.All {
+		buf.WriteString("ALL")
+	} else {
+		buf.WriteString(strconv.FormatInt(node.Count, 10))
+	}
+	buf.WriteString(" FROM ")
+	FormatNode(buf, f, node.Name)
+}
+
+// FetchCursor represents a FETCH statement.
+type FetchCursor struct {
+	CursorStmt
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FetchCursor) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("FETCH ")
+	FormatNode(buf, f, &node.CursorStmt)
+}
+
+// MoveCursor represents a MOVE statement.
+type MoveCursor struct {
+	CursorStmt
+}
+
+// Format implements the NodeFormatter interface.
+func (node *MoveCursor) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("MOVE ")
+	FormatNode(buf, f, &node.CursorStmt)
+}
+
+// CloseCursor represents a CLOSE statement.
+type CloseCursor struct {
+	Name Name // empty for ALL
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CloseCursor) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CLOSE ")
+	if node.Name == "" {
+		buf.WriteString("ALL")
+	} else {
+		FormatNode(buf, f, node.Name)
+	}
+}
@@ -39,6 +39,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
@@ -1247,6 +1249,103 @@ func (d *DIPAddr) Size() uintptr {
 	return unsafe.Sizeof(*d)
 }
 
+// DJSON is the JSON Datum.
+type DJSON struct {
+	json.JSON
+}
+
+// NewDJSON is a helper routine to create a DJSON initialized from its argument.
+func NewDJSON(j json.JSON) *DJSON {
+	return &DJSON{j}
+}
+
+// ParseDJSON takes a string of JSON and returns a DJSON value.
+func ParseDJSON(s string) (Datum, error) {
+	j, err := json.ParseJSON(s)
+	if err != nil {
+		return nil, makeParseError(s, TypeJSON, err)
+	}
+	return NewDJSON(j), nil
+}
+
+// MustBeDJSON attempts to retrieve a DJSON from an Expr, panicking if the
+// assertion fails.
+func MustBeDJSON(e Expr) DJSON {
+	i, ok := e.(*DJSON)
+	if !ok {
+		panic(pgerror.NewErrorf(pgerror.CodeInternalError, "expected *DJSON, found %T", e))
+	}
+	return *i
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DJSON) ResolvedType() Type {
+	return TypeJSON
+}
+
+// Compare implements the Datum interface.
+func (d *DJSON) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := other.(*DJSON)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return d.JSON.Compare(v.JSON)
+}
+
+// Prev implements the Datum interface.
+func (d *DJSON) Prev() (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DJSON) Next() (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DJSON) IsMax() bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DJSON) IsMin() bool {
+	return d.JSON.Type() == json.NullJSONType
+}
+
+var dMinJSON = NewDJSON(json.NullJSONValue)
+
+// min implements the Datum interface.
+func (d *DJSON) min() (Datum, bool) {
+	return dMinJSON, true
+}
+
+// max implements the Datum interface.
+func (d *DJSON) max() (Datum, bool) {
+	return nil, false
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DJSON) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DJSON) Format(buf *bytes.Buffer, f FmtFlags) {
+	s := d.JSON.String()
+	if f.withinArray {
+		encodeSQLStringInsideArray(buf, s)
+	} else {
+		encodeSQLStringWithFlags(buf, s, f)
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DJSON) Size() uintptr {
+	return unsafe.Sizeof(*d) + d.JSON.Size()
+}
+
 // DDate is the date Datum represented as the number of days after
 // the Unix epoch.
 type DDate int64
@@ -1356,6 +1455,377 @@ func (d *DDate) Size() uintptr {
 	return unsafe.Sizeof(*d)
 }
 
+// DTime is the time of day Datum, represented as the number of microseconds
+// after midnight.
+type DTime int64
+
+const (
+	microsPerSecond = int64(time.Second / time.Microsecond)
+	microsPerMinute = 60 * microsPerSecond
+	microsPerHour   = 60 * microsPerMinute
+	microsPerDay    = 24 * microsPerHour
+
+	// dTimeMax is the maximum value of a DTime, 24:00:00.
+	dTimeMax = DTime(microsPerDay)
+)
+
+// MakeDTime creates a DTime from the time of day of t.
+func MakeDTime(t time.Time) *DTime {
+	hour, min, sec := t.Clock()
+	micros := int64(hour)*microsPerHour + int64(min)*microsPerMinute + int64(sec)*microsPerSecond +
+		(int64(t.Nanosecond())+500)/1000
+	// Rounding the nanoseconds up may overflow into the next day.
+	d := DTime(micros % microsPerDay)
+	return &d
+}
+
+// MakeDTimeFromDuration creates a DTime from a duration after midnight,
+// wrapping around at midnight.
+func MakeDTimeFromDuration(d duration.Duration) *DTime {
+	micros := (d.Nanos / int64(time.Microsecond)) % microsPerDay
+	if micros < 0 {
+		micros += microsPerDay
+	}
+	t := DTime(micros)
+	return &t
+}
+
+// parseTimeOfDay parses a time of day of the form HH:MM[:SS[.ffffff]] at the
+// beginning of s, and returns it along with the rest of s. ok is false if s
+// doesn't begin with a time of day.
+func parseTimeOfDay(s string) (t DTime, rest string, ok bool) {
+	parseNum := func(s string, maxDigits int) (int64, string, bool) {
+		i := 0
+		var n int64
+		for i < len(s) && i < maxDigits && s[i] >= '0' && s[i] <= '9' {
+			n = n*10 + int64(s[i]-'0')
+			i++
+		}
+		return n, s[i:], i > 0
+	}
+	hour, s, ok := parseNum(s, 2)
+	if !ok || len(s) == 0 || s[0] != ':' {
+		return 0, "", false
+	}
+	min, s, ok := parseNum(s[1:], 2)
+	if !ok {
+		return 0, "", false
+	}
+	var sec, micros int64
+	if len(s) > 0 && s[0] == ':' {
+		if sec, s, ok = parseNum(s[1:], 2); !ok {
+			return 0, "", false
+		}
+		if len(s) > 0 && s[0] == '.' {
+			var frac int64
+			var rest string
+			if frac, rest, ok = parseNum(s[1:], 9); !ok {
+				return 0, "", false
+			}
+			for i := len(s[1:]) - len(rest); i < 9; i++ {
+				frac *= 10
+			}
+			micros = (frac + 500) / 1000
+			s = rest
+		}
+	}
+	if min > 59 || sec > 59 {
+		return 0, "", false
+	}
+	t = DTime(hour*microsPerHour + min*microsPerMinute + sec*microsPerSecond + micros)
+	if t > dTimeMax {
+		return 0, "", false
+	}
+	return t, s, true
+}
+
+// parseZoneOffset parses a time zone of the form Z, UTC, GMT or
+// +/-HH[[:]MM[[:]SS]] and returns its offset in seconds east of UTC.
+func parseZoneOffset(s string) (int32, bool) {
+	switch strings.ToUpper(s) {
+	case "Z", "UTC", "GMT":
+		return 0, true
+	}
+	if len(s) < 2 || (s[0] != '+' && s[0] != '-') {
+		return 0, false
+	}
+	sign := int32(1)
+	if s[0] == '-' {
+		sign = -1
+	}
+	digits := strings.Replace(s[1:], ":", "", -1)
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	if len(digits) > 6 {
+		return 0, false
+	}
+	var parts [3]int32
+	for i := 0; i < len(digits); i += 2 {
+		n, err := strconv.Atoi(digits[i : i+2])
+		if err != nil {
+			return 0, false
+		}
+		parts[i/2] = int32(n)
+	}
+	if parts[0] > 15 || parts[1] > 59 || parts[2] > 59 {
+		return 0, false
+	}
+	return sign * (parts[0]*60*60 + parts[1]*60 + parts[2]), true
+}
+
+// ParseDTime parses and returns the *DTime Datum value represented by the
+// provided string, or an error if parsing is unsuccessful. A time zone in the
+// string is ignored, as is a date.
+func ParseDTime(s string) (*DTime, error) {
+	s = strings.TrimSpace(s)
+	if t, rest, ok := parseTimeOfDay(s); ok {
+		rest = strings.TrimSpace(rest)
+		if _, ok := parseZoneOffset(rest); rest == "" || ok {
+			return &t, nil
+		}
+	}
+	if !strings.Contains(s, ":") {
+		// Unlike a timestamp, a time must have a time of day.
+		return nil, makeParseError(s, TypeTime, nil)
+	}
+	t, err := parseTimestampInLocation(s, time.UTC, TypeTime)
+	if err != nil {
+		return nil, err
+	}
+	return MakeDTime(t), nil
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DTime) ResolvedType() Type {
+	return TypeTime
+}
+
+// Compare implements the Datum interface.
+func (d *DTime) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := other.(*DTime)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	if *d < *v {
+		return -1
+	}
+	if *v < *d {
+		return 1
+	}
+	return 0
+}
+
+// Prev implements the Datum interface.
+func (d *DTime) Prev() (Datum, bool) {
+	prev := *d - 1
+	return &prev, true
+}
+
+// Next implements the Datum interface.
+func (d *DTime) Next() (Datum, bool) {
+	next := *d + 1
+	return &next, true
+}
+
+// IsMax implements the Datum interface.
+func (d *DTime) IsMax() bool {
+	return *d == dTimeMax
+}
+
+// IsMin implements the Datum interface.
+func (d *DTime) IsMin() bool {
+	return *d == 0
+}
+
+// max implements the Datum interface.
+func (d *DTime) max() (Datum, bool) {
+	max := dTimeMax
+	return &max, true
+}
+
+// min implements the Datum interface.
+func (d *DTime) min() (Datum, bool) {
+	min := DTime(0)
+	return &min, true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DTime) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DTime) Format(buf *bytes.Buffer, f FmtFlags) {
+	if !f.bareStrings {
+		buf.WriteByte('\'')
+	}
+	formatTimeOfDay(buf, *d)
+	if !f.bareStrings {
+		buf.WriteByte('\'')
+	}
+}
+
+// formatTimeOfDay writes t as HH:MM:SS, followed by the fractional seconds
+// without trailing zeros if there are any.
+func formatTimeOfDay(buf *bytes.Buffer, t DTime) {
+	micros := int64(t)
+	fmt.Fprintf(buf, "%02d:%02d:%02d",
+		micros/microsPerHour, micros%microsPerHour/microsPerMinute,
+		micros%microsPerMinute/microsPerSecond)
+	if frac := micros % microsPerSecond; frac != 0 {
+		buf.WriteString(strings.TrimRight(fmt.Sprintf(".%06d", frac), "0"))
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DTime) Size() uintptr {
+	return unsafe.Sizeof(*d)
+}
+
+// DTimeTZ is the time of day with time zone Datum.
+type DTimeTZ struct {
+	// Time is the time of day in the time zone.
+	Time DTime
+	// OffsetSecs is the offset of the time zone in seconds east of UTC.
+	OffsetSecs int32
+}
+
+// MakeDTimeTZ creates a DTimeTZ from the time of day and time zone of t.
+func MakeDTimeTZ(t time.Time) *DTimeTZ {
+	_, offset := t.Zone()
+	return &DTimeTZ{Time: *MakeDTime(t), OffsetSecs: int32(offset)}
+}
+
+// ParseDTimeTZ parses and returns the *DTimeTZ Datum value represented by the
+// provided string, or an error if parsing is unsuccessful. If the string has no
+// time zone, the current offset of the provided location is used.
+func ParseDTimeTZ(s string, loc *time.Location) (*DTimeTZ, error) {
+	s = strings.TrimSpace(s)
+	if t, rest, ok := parseTimeOfDay(s); ok {
+		rest = strings.TrimSpace(rest)
+		if rest == "" {
+			_, offset := timeutil.Now().In(loc).Zone()
+			return &DTimeTZ{Time: t, OffsetSecs: int32(offset)}, nil
+		}
+		if offset, ok := parseZoneOffset(rest); ok {
+			return &DTimeTZ{Time: t, OffsetSecs: offset}, nil
+		}
+	}
+	if !strings.Contains(s, ":") {
+		return nil, makeParseError(s, TypeTimeTZ, nil)
+	}
+	t, err := parseTimestampInLocation(s, loc, TypeTimeTZ)
+	if err != nil {
+		return nil, err
+	}
+	return MakeDTimeTZ(t), nil
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DTimeTZ) ResolvedType() Type {
+	return TypeTimeTZ
+}
+
+// UTCMicros returns the time of day in UTC, in microseconds after midnight. The
+// result is not normalized to a single day, so that all the values of a
+// DTimeTZ are ordered.
+func (d *DTimeTZ) UTCMicros() int64 {
+	return int64(d.Time) - int64(d.OffsetSecs)*microsPerSecond
+}
+
+// Compare implements the Datum interface. Like in Postgres, times are compared
+// by their UTC time first, then by their offset.
+func (d *DTimeTZ) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := other.(*DTimeTZ)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	if l, r := d.UTCMicros(), v.UTCMicros(); l != r {
+		if l < r {
+			return -1
+		}
+		return 1
+	}
+	// Postgres orders zones west of UTC after those east of it.
+	if d.OffsetSecs > v.OffsetSecs {
+		return -1
+	}
+	if v.OffsetSecs > d.OffsetSecs {
+		return 1
+	}
+	return 0
+}
+
+// Prev implements the Datum interface.
+func (d *DTimeTZ) Prev() (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DTimeTZ) Next() (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DTimeTZ) IsMax() bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DTimeTZ) IsMin() bool {
+	return false
+}
+
+// max implements the Datum interface.
+func (d *DTimeTZ) max() (Datum, bool) {
+	return nil, false
+}
+
+// min implements the Datum interface.
+func (d *DTimeTZ) min() (Datum, bool) {
+	return nil, false
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DTimeTZ) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DTimeTZ) Format(buf *bytes.Buffer, f FmtFlags) {
+	if !f.bareStrings {
+		buf.WriteByte('\'')
+	}
+	formatTimeOfDay(buf, d.Time)
+	offset := d.OffsetSecs
+	if offset < 0 {
+		buf.WriteByte('-')
+		offset = -offset
+	} else {
+		buf.WriteByte('+')
+	}
+	fmt.Fprintf(buf, "%02d", offset/3600)
+	if offset%3600 != 0 {
+		fmt.Fprintf(buf, ":%02d", offset%3600/60)
+		if offset%60 != 0 {
+			fmt.Fprintf(buf, ":%02d", offset%60)
+		}
+	}
+	if !f.bareStrings {
+		buf.WriteByte('\'')
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DTimeTZ) Size() uintptr {
+	return unsafe.Sizeof(*d)
+}
+
 // DTimestamp is the timestamp Datum.
 type DTimestamp struct {
 	time.Time
@@ -29,6 +29,9 @@ type DiscardMode int
 const (
 	// DiscardModeAll represents a DISCARD ALL statement.
 	DiscardModeAll DiscardMode = iota
+
+	// DiscardModeSequences represents a DISCARD SEQUENCES statement.
+	DiscardModeSequences
 )
 
 // Format implements the NodeFormatter interface.
@@ -36,6 +39,8 @@ func (node *Discard) Format(buf *bytes.Buffer, f FmtFlags) {
 	switch node.Mode {
 	case DiscardModeAll:
 		buf.WriteString("DISCARD ALL")
+	case DiscardModeSequences:
+		buf.WriteString("DISCARD SEQUENCES")
 	}
 }
 
@@ -122,6 +122,26 @@ func (node *DropView) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// DropSequence represents a DROP SEQUENCE statement.
+type DropSequence struct {
+	Names        TableNameReferences
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropSequence) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("DROP SEQUENCE ")
+	if node.IfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	FormatNode(buf, f, node.Names)
+	if node.DropBehavior != DropDefault {
+		buf.WriteByte(' ')
+		buf.WriteString(node.DropBehavior.String())
+	}
+}
+
 // DropUser represents a DROP USER statement
 type DropUser struct {
 	Names    NameList
@@ -136,3 +156,36 @@ func (node *DropUser) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 	FormatNode(buf, f, node.Names)
 }
+
+// DropRole represents a DROP ROLE statement
+type DropRole struct {
+	Names    NameList
+	IfExists bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropRole) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("DROP ROLE ")
+	if node.IfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	FormatNode(buf, f, node.Names)
+}
+
+// DropPolicy represents a DROP POLICY statement.
+type DropPolicy struct {
+	Name     Name
+	Table    NormalizableTableName
+	IfExists bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropPolicy) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("DROP POLICY ")
+	if node.IfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" ON ")
+	FormatNode(buf, f, &node.Table)
+}
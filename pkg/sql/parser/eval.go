@@ -34,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
@@ -330,6 +331,19 @@ func addWithOverflow(a, b int64) (r int64, ok bool) {
 	return a + b, true
 }
 
+// addTimeNanos returns the time of day the given number of nanoseconds after
+// t, wrapping around at midnight. Like in Postgres, only the time part of an
+// interval is added to a time.
+func addTimeNanos(t DTime, nanos int64) *DTime {
+	return MakeDTimeFromDuration(duration.Duration{Nanos: int64(t)*int64(time.Microsecond) + nanos})
+}
+
+// addDateAndTime returns the timestamp at time of day t on date d.
+func addDateAndTime(d *DDate, t *DTime) *DTimestamp {
+	midnight := MakeDTimestampTZFromDate(time.UTC, d).Time
+	return MakeDTimestamp(midnight.Add(time.Duration(*t)*time.Microsecond), time.Microsecond)
+}
+
 // BinOps contains the binary operations indexed by operation type.
 var BinOps = map[BinaryOperator]binOpOverload{
 	Bitand: {
@@ -503,6 +517,62 @@ var BinOps = map[BinaryOperator]binOpOverload{
 				return MakeDTimestampTZ(t, time.Microsecond), nil
 			},
 		},
+		BinOp{
+			LeftType:   TypeDate,
+			RightType:  TypeTime,
+			ReturnType: TypeTimestamp,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return addDateAndTime(left.(*DDate), right.(*DTime)), nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeTime,
+			RightType:  TypeDate,
+			ReturnType: TypeTimestamp,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return addDateAndTime(right.(*DDate), left.(*DTime)), nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeTime,
+			RightType:  TypeInterval,
+			ReturnType: TypeTime,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return addTimeNanos(*left.(*DTime), right.(*DInterval).Nanos), nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeInterval,
+			RightType:  TypeTime,
+			ReturnType: TypeTime,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return addTimeNanos(*right.(*DTime), left.(*DInterval).Nanos), nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeTimeTZ,
+			RightType:  TypeInterval,
+			ReturnType: TypeTimeTZ,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				t := left.(*DTimeTZ)
+				return &DTimeTZ{
+					Time:       *addTimeNanos(t.Time, right.(*DInterval).Nanos),
+					OffsetSecs: t.OffsetSecs,
+				}, nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeInterval,
+			RightType:  TypeTimeTZ,
+			ReturnType: TypeTimeTZ,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				t := right.(*DTimeTZ)
+				return &DTimeTZ{
+					Time:       *addTimeNanos(t.Time, left.(*DInterval).Nanos),
+					OffsetSecs: t.OffsetSecs,
+				}, nil
+			},
+		},
 	},
 
 	Minus: {
@@ -646,6 +716,35 @@ var BinOps = map[BinaryOperator]binOpOverload{
 				return MakeDTimestampTZ(t, time.Microsecond), nil
 			},
 		},
+		BinOp{
+			LeftType:   TypeTime,
+			RightType:  TypeTime,
+			ReturnType: TypeInterval,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				micros := int64(*left.(*DTime) - *right.(*DTime))
+				return &DInterval{Duration: duration.Duration{Nanos: micros * int64(time.Microsecond)}}, nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeTime,
+			RightType:  TypeInterval,
+			ReturnType: TypeTime,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return addTimeNanos(*left.(*DTime), -right.(*DInterval).Nanos), nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeTimeTZ,
+			RightType:  TypeInterval,
+			ReturnType: TypeTimeTZ,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				t := left.(*DTimeTZ)
+				return &DTimeTZ{
+					Time:       *addTimeNanos(t.Time, -right.(*DInterval).Nanos),
+					OffsetSecs: t.OffsetSecs,
+				}, nil
+			},
+		},
 		BinOp{
 			LeftType:   TypeInterval,
 			RightType:  TypeInterval,
@@ -1011,6 +1110,54 @@ var BinOps = map[BinaryOperator]binOpOverload{
 		},
 	},
 
+	JSONFetchVal: {
+		BinOp{
+			LeftType:   TypeJSON,
+			RightType:  TypeString,
+			ReturnType: TypeJSON,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				j := left.(*DJSON).JSON.FetchValKey(string(MustBeDString(right)))
+				if j == nil {
+					return DNull, nil
+				}
+				return NewDJSON(j), nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeJSON,
+			RightType:  TypeInt,
+			ReturnType: TypeJSON,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				j := left.(*DJSON).JSON.FetchValIdx(int(MustBeDInt(right)))
+				if j == nil {
+					return DNull, nil
+				}
+				return NewDJSON(j), nil
+			},
+		},
+	},
+
+	JSONFetchText: {
+		BinOp{
+			LeftType:   TypeJSON,
+			RightType:  TypeString,
+			ReturnType: TypeString,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				j := left.(*DJSON).JSON.FetchValKey(string(MustBeDString(right)))
+				return jsonAsText(j), nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeJSON,
+			RightType:  TypeInt,
+			ReturnType: TypeString,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				j := left.(*DJSON).JSON.FetchValIdx(int(MustBeDInt(right)))
+				return jsonAsText(j), nil
+			},
+		},
+	},
+
 	Pow: {
 		BinOp{
 			LeftType:   TypeInt,
@@ -1256,6 +1403,16 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			RightType: TypeInterval,
 			fn:        cmpOpScalarEQFn,
 		},
+		CmpOp{
+			LeftType:  TypeTime,
+			RightType: TypeTime,
+			fn:        cmpOpScalarEQFn,
+		},
+		CmpOp{
+			LeftType:  TypeTimeTZ,
+			RightType: TypeTimeTZ,
+			fn:        cmpOpScalarEQFn,
+		},
 		CmpOp{
 			LeftType:  TypeUUID,
 			RightType: TypeUUID,
@@ -1266,6 +1423,11 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			RightType: TypeINet,
 			fn:        cmpOpScalarEQFn,
 		},
+		CmpOp{
+			LeftType:  TypeJSON,
+			RightType: TypeJSON,
+			fn:        cmpOpScalarEQFn,
+		},
 		CmpOp{
 			LeftType:  TypeOid,
 			RightType: TypeOid,
@@ -1396,6 +1558,16 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			RightType: TypeInterval,
 			fn:        cmpOpScalarLTFn,
 		},
+		CmpOp{
+			LeftType:  TypeTime,
+			RightType: TypeTime,
+			fn:        cmpOpScalarLTFn,
+		},
+		CmpOp{
+			LeftType:  TypeTimeTZ,
+			RightType: TypeTimeTZ,
+			fn:        cmpOpScalarLTFn,
+		},
 		CmpOp{
 			LeftType:  TypeUUID,
 			RightType: TypeUUID,
@@ -1406,6 +1578,11 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			RightType: TypeINet,
 			fn:        cmpOpScalarLTFn,
 		},
+		CmpOp{
+			LeftType:  TypeJSON,
+			RightType: TypeJSON,
+			fn:        cmpOpScalarLTFn,
+		},
 		CmpOp{
 			LeftType:  TypeTuple,
 			RightType: TypeTuple,
@@ -1531,6 +1708,16 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			RightType: TypeInterval,
 			fn:        cmpOpScalarLEFn,
 		},
+		CmpOp{
+			LeftType:  TypeTime,
+			RightType: TypeTime,
+			fn:        cmpOpScalarLEFn,
+		},
+		CmpOp{
+			LeftType:  TypeTimeTZ,
+			RightType: TypeTimeTZ,
+			fn:        cmpOpScalarLEFn,
+		},
 		CmpOp{
 			LeftType:  TypeUUID,
 			RightType: TypeUUID,
@@ -1541,6 +1728,11 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			RightType: TypeINet,
 			fn:        cmpOpScalarLEFn,
 		},
+		CmpOp{
+			LeftType:  TypeJSON,
+			RightType: TypeJSON,
+			fn:        cmpOpScalarLEFn,
+		},
 		CmpOp{
 			LeftType:  TypeTuple,
 			RightType: TypeTuple,
@@ -1562,8 +1754,11 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeEvalTupleIn(TypeTimestamp),
 		makeEvalTupleIn(TypeTimestampTZ),
 		makeEvalTupleIn(TypeInterval),
+		makeEvalTupleIn(TypeTime),
+		makeEvalTupleIn(TypeTimeTZ),
 		makeEvalTupleIn(TypeUUID),
 		makeEvalTupleIn(TypeINet),
+		makeEvalTupleIn(TypeJSON),
 		makeEvalTupleIn(TypeTuple),
 		makeEvalTupleIn(TypeOid),
 	},
@@ -1620,6 +1815,39 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			},
 		},
 	},
+
+	Contains: {
+		CmpOp{
+			LeftType:  TypeJSON,
+			RightType: TypeJSON,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(json.Contains(left.(*DJSON).JSON, right.(*DJSON).JSON))), nil
+			},
+		},
+	},
+
+	JSONExists: {
+		CmpOp{
+			LeftType:  TypeJSON,
+			RightType: TypeString,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(left.(*DJSON).JSON.Exists(string(MustBeDString(right))))), nil
+			},
+		},
+	},
+}
+
+// jsonAsText returns the text of a JSON document as returned by the ->>
+// operator, or NULL if the document is missing or is a JSON null.
+func jsonAsText(j json.JSON) Datum {
+	if j == nil {
+		return DNull
+	}
+	text := j.AsText()
+	if text == nil {
+		return DNull
+	}
+	return NewDString(*text)
 }
 
 func isNaN(d Datum) bool {
@@ -1839,6 +2067,28 @@ type EvalPlanner interface {
 	// QualifyWithDatabase resolves a possibly unqualified table name into a
 	// normalized table name that is qualified by database.
 	QualifyWithDatabase(ctx context.Context, t *NormalizableTableName) (*TableName, error)
+
+	// IncrementSequence increments the given sequence and returns the result.
+	// It returns an error if the given name is not a sequence.
+	// The caller must ensure that seqName is fully qualified already.
+	IncrementSequence(ctx context.Context, seqName *TableName) (int64, error)
+
+	// GetLatestValueInSessionForSequence returns the value most recently
+	// obtained by nextval() for the given sequence in this session.
+	GetLatestValueInSessionForSequence(ctx context.Context, seqName *TableName) (int64, error)
+
+	// SetSequenceValue sets the sequence's value. If isCalled is false, the
+	// next call to nextval() returns newVal; otherwise it returns newVal
+	// plus the sequence's increment.
+	SetSequenceValue(ctx context.Context, seqName *TableName, newVal int64, isCalled bool) error
+
+	// GetLastSequenceValue returns the value most recently obtained by
+	// nextval() in this session, for any sequence.
+	GetLastSequenceValue(ctx context.Context) (int64, error)
+
+	// GetSessionVar returns the value of the session variable name, which
+	// can be user-defined, and whether it exists.
+	GetSessionVar(ctx context.Context, name string) (string, bool)
 }
 
 // contextHolder is a wrapper that returns a Context.
@@ -2402,7 +2652,7 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 		switch t := d.(type) {
 		case *DBool, *DInt, *DFloat, *DDecimal, dNull:
 			s = d.String()
-		case *DTimestamp, *DTimestampTZ, *DDate:
+		case *DTimestamp, *DTimestampTZ, *DDate, *DTime, *DTimeTZ:
 			s = AsStringWithFlags(d, FmtBareStrings)
 		case *DInterval:
 			// When converting an interval to string, we need a string representation
@@ -2413,6 +2663,8 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 			s = t.UUID.String()
 		case *DIPAddr:
 			s = t.String()
+		case *DJSON:
+			s = t.JSON.String()
 		case *DString:
 			s = string(*t)
 		case *DCollatedString:
@@ -2479,6 +2731,16 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 			return d, nil
 		}
 
+	case *JSONColType:
+		switch t := d.(type) {
+		case *DString:
+			return ParseDJSON(string(*t))
+		case *DCollatedString:
+			return ParseDJSON(t.Contents)
+		case *DJSON:
+			return d, nil
+		}
+
 	case *DateColType:
 		switch d := d.(type) {
 		case *DString:
@@ -2495,6 +2757,40 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 			return NewDDateFromTime(d.Time, time.UTC), nil
 		}
 
+	case *TimeColType:
+		switch d := d.(type) {
+		case *DString:
+			return ParseDTime(string(*d))
+		case *DCollatedString:
+			return ParseDTime(d.Contents)
+		case *DTime:
+			return d, nil
+		case *DTimeTZ:
+			t := d.Time
+			return &t, nil
+		case *DTimestamp:
+			return MakeDTime(d.Time), nil
+		case *DTimestampTZ:
+			return MakeDTime(d.Time.In(ctx.GetLocation())), nil
+		case *DInterval:
+			return MakeDTimeFromDuration(d.Duration), nil
+		}
+
+	case *TimeTZColType:
+		switch d := d.(type) {
+		case *DString:
+			return ParseDTimeTZ(string(*d), ctx.GetLocation())
+		case *DCollatedString:
+			return ParseDTimeTZ(d.Contents, ctx.GetLocation())
+		case *DTime:
+			_, offset := timeutil.Now().In(ctx.GetLocation()).Zone()
+			return &DTimeTZ{Time: *d, OffsetSecs: int32(offset)}, nil
+		case *DTimeTZ:
+			return d, nil
+		case *DTimestampTZ:
+			return MakeDTimeTZ(d.Time.In(ctx.GetLocation())), nil
+		}
+
 	case *TimestampColType:
 		// TODO(knz): Timestamp from float, decimal.
 		switch d := d.(type) {
@@ -2540,6 +2836,8 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 		case *DInt:
 			// An integer duration represents a duration in microseconds.
 			return &DInterval{Duration: duration.Duration{Nanos: int64(*v) * 1000}}, nil
+		case *DTime:
+			return &DInterval{Duration: duration.Duration{Nanos: int64(*v) * int64(time.Microsecond)}}, nil
 		case *DInterval:
 			return d, nil
 		}
@@ -3040,11 +3338,26 @@ func (t *DIPAddr) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DJSON) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t *DDate) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DTime) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DTimeTZ) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t *DFloat) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
@@ -3169,6 +3482,9 @@ func foldComparisonExpr(
 	case NotRegIMatch:
 		// NotRegIMatch(left, right) is implemented as !RegIMatch(left, right)
 		return RegIMatch, left, right, false, true
+	case ContainedBy:
+		// ContainedBy(left, right) is implemented as Contains(right, left)
+		return Contains, right, left, true, false
 	case IsDistinctFrom:
 		// IsDistinctFrom(left, right) is implemented as !EQ(left, right)
 		//
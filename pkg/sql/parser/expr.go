@@ -293,6 +293,9 @@ const (
 	IsNotDistinctFrom
 	Is
 	IsNot
+	Contains
+	ContainedBy
+	JSONExists
 
 	// The following operators will always be used with an associated SubOperator.
 	// If Go had algebraic data types they would be defined in a self-contained
@@ -335,6 +338,9 @@ var comparisonOpName = [...]string{
 	IsNotDistinctFrom: "IS NOT DISTINCT FROM",
 	Is:                "IS",
 	IsNot:             "IS NOT",
+	Contains:          "@>",
+	ContainedBy:       "<@",
+	JSONExists:        "?",
 	Any:               "ANY",
 	Some:              "SOME",
 	All:               "ALL",
@@ -748,22 +754,26 @@ const (
 	Concat
 	LShift
 	RShift
+	JSONFetchVal
+	JSONFetchText
 )
 
 var binaryOpName = [...]string{
-	Bitand:   "&",
-	Bitor:    "|",
-	Bitxor:   "#",
-	Plus:     "+",
-	Minus:    "-",
-	Mult:     "*",
-	Div:      "/",
-	FloorDiv: "//",
-	Mod:      "%",
-	Pow:      "^",
-	Concat:   "||",
-	LShift:   "<<",
-	RShift:   ">>",
+	Bitand:        "&",
+	Bitor:         "|",
+	Bitxor:        "#",
+	Plus:          "+",
+	Minus:         "-",
+	Mult:          "*",
+	Div:           "/",
+	FloorDiv:      "//",
+	Mod:           "%",
+	Pow:           "^",
+	Concat:        "||",
+	LShift:        "<<",
+	RShift:        ">>",
+	JSONFetchVal:  "->",
+	JSONFetchText: "->>",
 }
 
 func (i BinaryOperator) String() string {
@@ -1078,11 +1088,14 @@ var (
 	decimalCastTypes = []Type{TypeNull, TypeBool, TypeInt, TypeFloat, TypeDecimal, TypeString, TypeCollatedString,
 		TypeTimestamp, TypeTimestampTZ, TypeDate, TypeInterval}
 	stringCastTypes = []Type{TypeNull, TypeBool, TypeInt, TypeFloat, TypeDecimal, TypeString, TypeCollatedString,
-		TypeBytes, TypeTimestamp, TypeTimestampTZ, TypeInterval, TypeUUID, TypeDate, TypeOid, TypeINet}
+		TypeBytes, TypeTimestamp, TypeTimestampTZ, TypeInterval, TypeUUID, TypeDate, TypeTime, TypeTimeTZ,
+		TypeOid, TypeINet}
 	bytesCastTypes     = []Type{TypeNull, TypeString, TypeCollatedString, TypeBytes, TypeUUID}
 	dateCastTypes      = []Type{TypeNull, TypeString, TypeCollatedString, TypeDate, TypeTimestamp, TypeTimestampTZ, TypeInt}
 	timestampCastTypes = []Type{TypeNull, TypeString, TypeCollatedString, TypeDate, TypeTimestamp, TypeTimestampTZ, TypeInt}
-	intervalCastTypes  = []Type{TypeNull, TypeString, TypeCollatedString, TypeInt, TypeInterval}
+	timeCastTypes      = []Type{TypeNull, TypeString, TypeCollatedString, TypeTime, TypeTimeTZ, TypeTimestamp, TypeTimestampTZ, TypeInterval}
+	timeTZCastTypes    = []Type{TypeNull, TypeString, TypeCollatedString, TypeTime, TypeTimeTZ, TypeTimestampTZ}
+	intervalCastTypes  = []Type{TypeNull, TypeString, TypeCollatedString, TypeInt, TypeTime, TypeInterval}
 	oidCastTypes       = []Type{TypeNull, TypeString, TypeCollatedString, TypeInt, TypeOid}
 	uuidCastTypes      = []Type{TypeNull, TypeString, TypeCollatedString, TypeBytes, TypeUUID}
 	inetCastTypes      = []Type{TypeNull, TypeString, TypeCollatedString, TypeINet}
@@ -1105,6 +1118,10 @@ func validCastTypes(t Type) []Type {
 		return bytesCastTypes
 	case TypeDate:
 		return dateCastTypes
+	case TypeTime:
+		return timeCastTypes
+	case TypeTimeTZ:
+		return timeTZCastTypes
 	case TypeTimestamp, TypeTimestampTZ:
 		return timestampCastTypes
 	case TypeInterval:
@@ -1228,8 +1245,11 @@ func (node *DInt) String() string             { return AsString(node) }
 func (node *DInterval) String() string        { return AsString(node) }
 func (node *DUuid) String() string            { return AsString(node) }
 func (node *DIPAddr) String() string          { return AsString(node) }
+func (node *DJSON) String() string            { return AsString(node) }
 func (node *DString) String() string          { return AsString(node) }
 func (node *DCollatedString) String() string  { return AsString(node) }
+func (node *DTime) String() string            { return AsString(node) }
+func (node *DTimeTZ) String() string          { return AsString(node) }
 func (node *DTimestamp) String() string       { return AsString(node) }
 func (node *DTimestampTZ) String() string     { return AsString(node) }
 func (node *DTuple) String() string           { return AsString(node) }
@@ -77,6 +77,11 @@ func TestContextualHelp(t *testing.T) {
 		{`ALTER VIEW blah RENAME ?`, `ALTER VIEW`},
 		{`ALTER VIEW blah RENAME TO blih ?`, `ALTER VIEW`},
 
+		{`ALTER SEQUENCE IF ?`, `ALTER SEQUENCE`},
+		{`ALTER SEQUENCE blah ?`, `ALTER SEQUENCE`},
+		{`ALTER SEQUENCE blah RENAME ?`, `ALTER SEQUENCE`},
+		{`ALTER SEQUENCE blah INCREMENT ?`, `ALTER SEQUENCE`},
+
 		{`CANCEL ?`, `CANCEL`},
 		{`CANCEL JOB ?`, `CANCEL JOB`},
 		{`CANCEL QUERY ?`, `CANCEL QUERY`},
@@ -96,6 +101,15 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE USER blih ?`, `CREATE USER`},
 		{`CREATE USER blih WITH ?`, `CREATE USER`},
 
+		{`CREATE ROLE ?`, `CREATE ROLE`},
+
+		{`CREATE POLICY ?`, `CREATE POLICY`},
+		{`CREATE POLICY p ON ?`, `CREATE POLICY`},
+
+		{`CREATE SEQUENCE blah ?`, `CREATE SEQUENCE`},
+		{`CREATE SEQUENCE IF NOT ?`, `CREATE SEQUENCE`},
+		{`CREATE SEQUENCE blah START WITH ?`, `CREATE SEQUENCE`},
+
 		{`CREATE VIEW blah (?`, `CREATE VIEW`},
 		{`CREATE VIEW blah AS (SELECT c FROM x) ?`, `CREATE VIEW`},
 		{`CREATE VIEW blah AS SELECT c FROM x ?`, `SELECT`},
@@ -133,9 +147,17 @@ func TestContextualHelp(t *testing.T) {
 		{`DROP VIEW IF ?`, `DROP VIEW`},
 		{`DROP VIEW IF EXISTS blih, bloh ?`, `DROP VIEW`},
 
+		{`DROP SEQUENCE blah ?`, `DROP SEQUENCE`},
+		{`DROP SEQUENCE IF ?`, `DROP SEQUENCE`},
+		{`DROP SEQUENCE IF EXISTS blih, bloh ?`, `DROP SEQUENCE`},
+
 		{`DROP USER IF ?`, `DROP USER`},
 		{`DROP USER IF EXISTS bloh ?`, `DROP USER`},
 
+		{`DROP ROLE IF ?`, `DROP ROLE`},
+		{`DROP ROLE IF EXISTS bloh ?`, `DROP ROLE`},
+		{`DROP POLICY ?`, `DROP POLICY`},
+
 		{`EXPLAIN (?`, `EXPLAIN`},
 		{`EXPLAIN SELECT 1 ?`, `SELECT`},
 		{`EXPLAIN INSERT INTO xx (SELECT 1) ?`, `INSERT`},
@@ -160,6 +182,19 @@ func TestContextualHelp(t *testing.T) {
 		{`DEALLOCATE ALL ?`, `DEALLOCATE`},
 		{`DEALLOCATE PREPARE ?`, `DEALLOCATE`},
 
+		{`DECLARE ?`, `DECLARE`},
+		{`DECLARE foo ?`, `DECLARE`},
+		{`DECLARE foo CURSOR FOR SELECT 1 ?`, `SELECT`},
+
+		{`FETCH ?`, `FETCH`},
+		{`FETCH 10 FROM ?`, `FETCH`},
+
+		{`MOVE ?`, `MOVE`},
+		{`MOVE ALL IN ?`, `MOVE`},
+
+		{`CLOSE ?`, `CLOSE`},
+		{`CLOSE foo ?`, `CLOSE`},
+
 		{`INSERT INTO ?`, `INSERT`},
 		{`INSERT INTO blah (?`, `<SELECTCLAUSE>`},
 		{`INSERT INTO blah VALUES (1) RETURNING ?`, `INSERT`},
@@ -183,6 +218,7 @@ func TestContextualHelp(t *testing.T) {
 		{`GRANT ALL ?`, `GRANT`},
 		{`GRANT ALL ON foo TO ?`, `GRANT`},
 		{`GRANT ALL ON foo TO bar ?`, `GRANT`},
+		{`GRANT SELECT (a) ON foo TO ?`, `GRANT`},
 
 		{`PAUSE ?`, `PAUSE JOB`},
 
@@ -191,6 +227,7 @@ func TestContextualHelp(t *testing.T) {
 		{`REVOKE ALL ?`, `REVOKE`},
 		{`REVOKE ALL ON foo FROM ?`, `REVOKE`},
 		{`REVOKE ALL ON foo FROM bar ?`, `REVOKE`},
+		{`REVOKE SELECT (a) ON foo FROM ?`, `REVOKE`},
 
 		{`SELECT * FROM ?`, `<SOURCE>`},
 		{`SELECT * FROM (?`, `<SOURCE>`}, // not <selectclause>! joins are allowed.
@@ -280,6 +317,8 @@ func TestContextualHelp(t *testing.T) {
 		{`SET SESSION TIME ZONE 'UTC' ?`, `SET SESSION`},
 		{`SET SESSION blah TO ?`, `SET SESSION`},
 		{`SET SESSION blah TO 42 ?`, `SET SESSION`},
+		{`SET LOCAL ?`, `SET SESSION`},
+		{`SET LOCAL blah TO ?`, `SET SESSION`},
 
 		{`SET TRANSACTION ?`, `SET TRANSACTION`},
 		{`SET TRANSACTION ISOLATION LEVEL SNAPSHOT ?`, `SET TRANSACTION`},
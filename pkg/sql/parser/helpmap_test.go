@@ -8,6 +8,7 @@ var expectedHelpStrings = []string{
 	"<SOURCE>",
 	"ALTER DATABASE",
 	"ALTER INDEX",
+	"ALTER SEQUENCE",
 	"ALTER TABLE",
 	"ALTER VIEW",
 	"ALTER",
@@ -16,27 +17,38 @@ var expectedHelpStrings = []string{
 	"CANCEL JOB",
 	"CANCEL QUERY",
 	"CANCEL",
+	"CLOSE",
 	"COMMIT",
 	"CREATE DATABASE",
 	"CREATE INDEX",
+	"CREATE POLICY",
+	"CREATE ROLE",
+	"CREATE SEQUENCE",
+	"CREATE STATISTICS",
 	"CREATE TABLE",
 	"CREATE USER",
 	"CREATE VIEW",
 	"CREATE",
 	"DEALLOCATE",
+	"DECLARE",
 	"DELETE",
 	"DISCARD",
 	"DROP DATABASE",
 	"DROP INDEX",
+	"DROP POLICY",
+	"DROP ROLE",
+	"DROP SEQUENCE",
 	"DROP TABLE",
 	"DROP USER",
 	"DROP VIEW",
 	"DROP",
 	"EXECUTE",
 	"EXPLAIN",
+	"FETCH",
 	"GRANT",
 	"IMPORT",
 	"INSERT",
+	"MOVE",
 	"PAUSE JOB",
 	"PREPARE",
 	"RELEASE",
@@ -64,6 +76,7 @@ var expectedHelpStrings = []string{
 	"SHOW QUERIES",
 	"SHOW SESSION",
 	"SHOW SESSIONS",
+	"SHOW STATISTICS",
 	"SHOW TABLES",
 	"SHOW TRACE",
 	"SHOW TRANSACTION",
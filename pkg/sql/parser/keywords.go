@@ -6,6 +6,7 @@ package parser
 var keywords = map[string]int{
 	"ACTION":                    ACTION,
 	"ADD":                       ADD,
+	"ADMIN":                     ADMIN,
 	"ALL":                       ALL,
 	"ALTER":                     ALTER,
 	"ANALYSE":                   ANALYSE,
@@ -31,6 +32,7 @@ var keywords = map[string]int{
 	"BY":                        BY,
 	"BYTEA":                     BYTEA,
 	"BYTES":                     BYTES,
+	"CACHE":                     CACHE,
 	"CANCEL":                    CANCEL,
 	"CASCADE":                   CASCADE,
 	"CASE":                      CASE,
@@ -39,6 +41,7 @@ var keywords = map[string]int{
 	"CHARACTER":                 CHARACTER,
 	"CHARACTERISTICS":           CHARACTERISTICS,
 	"CHECK":                     CHECK,
+	"CLOSE":                     CLOSE,
 	"CLUSTER":                   CLUSTER,
 	"COALESCE":                  COALESCE,
 	"COLLATE":                   COLLATE,
@@ -64,6 +67,7 @@ var keywords = map[string]int{
 	"CURRENT_TIME":              CURRENT_TIME,
 	"CURRENT_TIMESTAMP":         CURRENT_TIMESTAMP,
 	"CURRENT_USER":              CURRENT_USER,
+	"CURSOR":                    CURSOR,
 	"CYCLE":                     CYCLE,
 	"DATA":                      DATA,
 	"DATABASE":                  DATABASE,
@@ -73,16 +77,19 @@ var keywords = map[string]int{
 	"DEALLOCATE":                DEALLOCATE,
 	"DEC":                       DEC,
 	"DECIMAL":                   DECIMAL,
+	"DECLARE":                   DECLARE,
 	"DEFAULT":                   DEFAULT,
 	"DEFERRABLE":                DEFERRABLE,
 	"DELETE":                    DELETE,
 	"DESC":                      DESC,
+	"DISABLE":                   DISABLE,
 	"DISCARD":                   DISCARD,
 	"DISTINCT":                  DISTINCT,
 	"DO":                        DO,
 	"DOUBLE":                    DOUBLE,
 	"DROP":                      DROP,
 	"ELSE":                      ELSE,
+	"ENABLE":                    ENABLE,
 	"ENCODING":                  ENCODING,
 	"END":                       END,
 	"EXCEPT":                    EXCEPT,
@@ -104,6 +111,7 @@ var keywords = map[string]int{
 	"FOR":                       FOR,
 	"FORCE_INDEX":               FORCE_INDEX,
 	"FOREIGN":                   FOREIGN,
+	"FORWARD":                   FORWARD,
 	"FROM":                      FROM,
 	"FULL":                      FULL,
 	"GRANT":                     GRANT,
@@ -113,12 +121,14 @@ var keywords = map[string]int{
 	"GROUPING":                  GROUPING,
 	"HAVING":                    HAVING,
 	"HIGH":                      HIGH,
+	"HOLD":                      HOLD,
 	"HOUR":                      HOUR,
 	"IF":                        IF,
 	"IFNULL":                    IFNULL,
 	"ILIKE":                     ILIKE,
 	"IMPORT":                    IMPORT,
 	"IN":                        IN,
+	"INCREMENT":                 INCREMENT,
 	"INCREMENTAL":               INCREMENTAL,
 	"INDEX":                     INDEX,
 	"INDEXES":                   INDEXES,
@@ -137,11 +147,14 @@ var keywords = map[string]int{
 	"INTERSECT":                 INTERSECT,
 	"INTERVAL":                  INTERVAL,
 	"INTO":                      INTO,
+	"INVERTED":                  INVERTED,
 	"IS":                        IS,
 	"ISOLATION":                 ISOLATION,
 	"JOB":                       JOB,
 	"JOBS":                      JOBS,
 	"JOIN":                      JOIN,
+	"JSON":                      JSON,
+	"JSONB":                     JSONB,
 	"KEY":                       KEY,
 	"KEYS":                      KEYS,
 	"KV":                        KV,
@@ -159,8 +172,11 @@ var keywords = map[string]int{
 	"LOCALTIMESTAMP":            LOCALTIMESTAMP,
 	"LOW":                       LOW,
 	"MATCH":                     MATCH,
+	"MAXVALUE":                  MAXVALUE,
 	"MINUTE":                    MINUTE,
+	"MINVALUE":                  MINVALUE,
 	"MONTH":                     MONTH,
+	"MOVE":                      MOVE,
 	"NAME":                      NAME,
 	"NAMES":                     NAMES,
 	"NAN":                       NAN,
@@ -181,6 +197,7 @@ var keywords = map[string]int{
 	"OID":                       OID,
 	"ON":                        ON,
 	"ONLY":                      ONLY,
+	"OPTION":                    OPTION,
 	"OPTIONS":                   OPTIONS,
 	"OR":                        OR,
 	"ORDER":                     ORDER,
@@ -197,6 +214,7 @@ var keywords = map[string]int{
 	"PAUSE":                     PAUSE,
 	"PLACING":                   PLACING,
 	"PLANS":                     PLANS,
+	"POLICY":                    POLICY,
 	"POSITION":                  POSITION,
 	"PRECEDING":                 PRECEDING,
 	"PRECISION":                 PRECISION,
@@ -226,15 +244,19 @@ var keywords = map[string]int{
 	"RETURNING":                 RETURNING,
 	"REVOKE":                    REVOKE,
 	"RIGHT":                     RIGHT,
+	"ROLE":                      ROLE,
 	"ROLLBACK":                  ROLLBACK,
 	"ROLLUP":                    ROLLUP,
 	"ROW":                       ROW,
 	"ROWS":                      ROWS,
 	"SAVEPOINT":                 SAVEPOINT,
 	"SCATTER":                   SCATTER,
+	"SCROLL":                    SCROLL,
 	"SEARCH":                    SEARCH,
 	"SECOND":                    SECOND,
+	"SECURITY":                  SECURITY,
 	"SELECT":                    SELECT,
+	"SEQUENCE":                  SEQUENCE,
 	"SEQUENCES":                 SEQUENCES,
 	"SERIAL":                    SERIAL,
 	"SERIALIZABLE":              SERIALIZABLE,
@@ -254,9 +276,11 @@ var keywords = map[string]int{
 	"SPLIT":                     SPLIT,
 	"SQL":                       SQL,
 	"START":                     START,
+	"STATISTICS":                STATISTICS,
 	"STATUS":                    STATUS,
 	"STDIN":                     STDIN,
 	"STORE":                     STORE,
+	"STORED":                    STORED,
 	"STORING":                   STORING,
 	"STRICT":                    STRICT,
 	"STRING":                    STRING,
@@ -275,6 +299,7 @@ var keywords = map[string]int{
 	"TIME":                      TIME,
 	"TIMESTAMP":                 TIMESTAMP,
 	"TIMESTAMPTZ":               TIMESTAMPTZ,
+	"TIMETZ":                    TIMETZ,
 	"TO":                        TO,
 	"TRACE":                     TRACE,
 	"TRAILING":                  TRAILING,
@@ -160,14 +160,18 @@ func (HomogeneousType) String() string {
 	return "anyelement..."
 }
 
-// VariadicType is a typeList implementation which accepts any number of
-// arguments and matches when each argument is either NULL or of the type
-// typ.
+// VariadicType is a typeList implementation which accepts a fixed number of
+// arguments of the types FixedTypes followed by any number of arguments that
+// are each either NULL or of the type Typ.
 type VariadicType struct {
-	Typ Type
+	FixedTypes []Type
+	Typ        Type
 }
 
 func (v VariadicType) match(types []Type) bool {
+	if !v.matchLen(len(types)) {
+		return false
+	}
 	for i := range types {
 		if !v.matchAt(types[i], i) {
 			return false
@@ -177,29 +181,37 @@ func (v VariadicType) match(types []Type) bool {
 }
 
 func (v VariadicType) matchAt(typ Type, i int) bool {
-	return typ == TypeNull || v.Typ.Equivalent(typ)
+	return typ == TypeNull || v.getAt(i).Equivalent(typ)
 }
 
 func (v VariadicType) matchLen(l int) bool {
-	return true
+	return l >= len(v.FixedTypes)
 }
 
 func (v VariadicType) getAt(i int) Type {
+	if i < len(v.FixedTypes) {
+		return v.FixedTypes[i]
+	}
 	return v.Typ
 }
 
 // Length implements the typeList interface.
 func (v VariadicType) Length() int {
-	return 1
+	return len(v.FixedTypes) + 1
 }
 
 // Types implements the typeList interface.
 func (v VariadicType) Types() []Type {
-	return []Type{v.Typ}
+	return append(append([]Type(nil), v.FixedTypes...), v.Typ)
 }
 
 func (v VariadicType) String() string {
-	return fmt.Sprintf("%s...", v.Typ)
+	var buf bytes.Buffer
+	for _, t := range v.FixedTypes {
+		fmt.Fprintf(&buf, "%s, ", t)
+	}
+	fmt.Fprintf(&buf, "%s...", v.Typ)
+	return buf.String()
 }
 
 // unknownReturnType is returned from returnTypers when the arguments provided are
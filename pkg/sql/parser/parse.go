@@ -230,6 +230,10 @@ func ParseStringAs(t Type, s string, location *time.Location) (Datum, error) {
 		d, err = ParseDInterval(s)
 	case TypeString:
 		d = NewDString(s)
+	case TypeTime:
+		d, err = ParseDTime(s)
+	case TypeTimeTZ:
+		d, err = ParseDTimeTZ(s, location)
 	case TypeTimestamp:
 		d, err = ParseDTimestamp(s, time.Microsecond)
 	case TypeTimestampTZ:
@@ -238,6 +242,8 @@ func ParseStringAs(t Type, s string, location *time.Location) (Datum, error) {
 		d, err = ParseDUuidFromString(s)
 	case TypeINet:
 		d, err = ParseDIPAddrFromINetString(s)
+	case TypeJSON:
+		d, err = ParseDJSON(s)
 	default:
 		return nil, pgerror.NewErrorf(pgerror.CodeInternalError, "unknown type %s", t)
 	}
@@ -79,11 +79,18 @@ func TestParse(t *testing.T) {
 		{`CREATE INDEX ON a (b) INTERLEAVE IN PARENT c (d)`},
 		{`CREATE INDEX ON a (b) INTERLEAVE IN PARENT c.d (e)`},
 		{`CREATE INDEX ON a (b ASC, c DESC)`},
+		{`CREATE INDEX ON a (lower(b))`},
+		{`CREATE INDEX ON a ((b + c) DESC, d)`},
 		{`CREATE UNIQUE INDEX a ON b (c)`},
 		{`CREATE UNIQUE INDEX a ON b (c) STORING (d)`},
 		{`CREATE UNIQUE INDEX a ON b (c) INTERLEAVE IN PARENT d (e, f)`},
 		{`CREATE UNIQUE INDEX a ON b (c) INTERLEAVE IN PARENT d.e (f, g)`},
 		{`CREATE UNIQUE INDEX a ON b.c (d)`},
+		{`CREATE INVERTED INDEX a ON b (c)`},
+		{`CREATE INVERTED INDEX IF NOT EXISTS a ON b (c)`},
+		{`CREATE INDEX a ON b (c) WHERE d > 0`},
+		{`CREATE UNIQUE INDEX IF NOT EXISTS a ON b (c) STORING (d) WHERE e IS NOT NULL AND f`},
+		{`CREATE INVERTED INDEX a ON b (c) WHERE d`},
 
 		{`CREATE TABLE a ()`},
 		{`CREATE TABLE a (b INT)`},
@@ -100,6 +107,10 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b BIGSERIAL)`},
 		{`CREATE TABLE a (b UUID)`},
 		{`CREATE TABLE a (b INET)`},
+		{`CREATE TABLE a (b JSONB)`},
+		{`CREATE TABLE a (b INT, c JSONB, INVERTED INDEX (c))`},
+		{`CREATE TABLE a (b INT, c JSONB, INVERTED INDEX d (c))`},
+		{`CREATE TABLE a (b JSON)`},
 		{`CREATE TABLE a (b INT NULL)`},
 		{`CREATE TABLE a (b INT CONSTRAINT maybe NULL)`},
 		{`CREATE TABLE a (b INT NOT NULL)`},
@@ -117,12 +128,17 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (a INT DEFAULT 1 CONSTRAINT positive CHECK (a > 0))`},
 		{`CREATE TABLE a (a INT CONSTRAINT one DEFAULT 1 CONSTRAINT positive CHECK (a > 0))`},
 		{`CREATE TABLE a (a INT CONSTRAINT one CHECK (a > 0) CONSTRAINT two CHECK (a < 10))`},
+		{`CREATE TABLE a (a STRING, b STRING AS (lower(a)) STORED)`},
+		{`CREATE TABLE a (a INT, b INT NOT NULL AS (a + 1) STORED, INDEX (b))`},
 		// "0" lost quotes previously.
 		{`CREATE TABLE a (b INT, c TEXT, PRIMARY KEY (b, c, "0"))`},
 		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b) REFERENCES other)`},
 		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b, c) REFERENCES other)`},
 		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b, c) REFERENCES other (x, y))`},
 		{`CREATE TABLE a (b INT, c TEXT, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y))`},
+		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b) REFERENCES other ON DELETE CASCADE)`},
+		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b) REFERENCES other ON UPDATE SET NULL)`},
+		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b, c) REFERENCES other (x, y) ON DELETE SET DEFAULT ON UPDATE RESTRICT)`},
 		{`CREATE TABLE a (b INT, c TEXT, INDEX (b, c))`},
 		{`CREATE TABLE a (b INT, c TEXT, INDEX d (b, c))`},
 		{`CREATE TABLE a (b INT, c TEXT, CONSTRAINT d UNIQUE (b, c))`},
@@ -133,6 +149,7 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT, c INT REFERENCES foo)`},
 		{`CREATE TABLE a (b INT, c INT CONSTRAINT ref REFERENCES foo)`},
 		{`CREATE TABLE a (b INT, c INT REFERENCES foo (bar))`},
+		{`CREATE TABLE a (b INT, c INT REFERENCES foo (bar) ON DELETE CASCADE ON UPDATE CASCADE)`},
 		{`CREATE TABLE a (b INT, INDEX (b) STORING (c))`},
 		{`CREATE TABLE a (b INT, c TEXT, INDEX (b ASC, c DESC) STORING (c))`},
 		{`CREATE TABLE a (b INT, INDEX (b) INTERLEAVE IN PARENT c (d, e))`},
@@ -160,6 +177,12 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b STRING COLLATE "DE")`},
 		{`CREATE TABLE a (b STRING[] COLLATE "DE")`},
 
+		{`CREATE ROLE a`},
+		{`CREATE POLICY p ON a`},
+		{`CREATE POLICY p ON db.a TO b, c USING (tenant = current_setting('app.tenant'))`},
+		{`CREATE POLICY p ON a USING (b > 0) WITH CHECK (b > 1)`},
+		{`CREATE POLICY p ON a WITH CHECK (b > 1)`},
+
 		{`CREATE VIEW a AS SELECT * FROM b`},
 		{`CREATE VIEW a AS SELECT b.* FROM b LIMIT 5`},
 		{`CREATE VIEW a AS (SELECT c, d FROM b WHERE c > 0 ORDER BY c)`},
@@ -168,6 +191,24 @@ func TestParse(t *testing.T) {
 		{`CREATE VIEW a (x, y) AS VALUES (1, 'one'), (2, 'two')`},
 		{`CREATE VIEW a AS TABLE b`},
 
+		{`CREATE SEQUENCE a`},
+		{`CREATE SEQUENCE a.b`},
+		{`CREATE SEQUENCE IF NOT EXISTS a`},
+		{`CREATE SEQUENCE a NO CYCLE`},
+		{`CREATE SEQUENCE a CACHE 10`},
+		{`CREATE SEQUENCE a INCREMENT 5`},
+		{`CREATE SEQUENCE a INCREMENT BY 5`},
+		{`CREATE SEQUENCE a NO MAXVALUE`},
+		{`CREATE SEQUENCE a MAXVALUE 1000`},
+		{`CREATE SEQUENCE a NO MINVALUE`},
+		{`CREATE SEQUENCE a MINVALUE 1000`},
+		{`CREATE SEQUENCE a START 1000`},
+		{`CREATE SEQUENCE a START WITH 1000`},
+		{`CREATE SEQUENCE a INCREMENT BY -1 MINVALUE -100 MAXVALUE -1 START WITH -1 CACHE 1`},
+
+		{`CREATE STATISTICS a ON col1 FROM t`},
+		{`CREATE STATISTICS a ON col1, col2 FROM d.t`},
+
 		{`DELETE FROM a`},
 		{`DELETE FROM a.b`},
 		{`DELETE FROM a WHERE a = b`},
@@ -178,6 +219,7 @@ func TestParse(t *testing.T) {
 		{`DELETE FROM a WHERE a = b RETURNING NOTHING`},
 
 		{`DISCARD ALL`},
+		{`DISCARD SEQUENCES`},
 
 		{`DROP DATABASE a`},
 		{`DROP DATABASE IF EXISTS a`},
@@ -212,9 +254,21 @@ func TestParse(t *testing.T) {
 		{`DROP VIEW a.b CASCADE`},
 		{`DROP VIEW a, b CASCADE`},
 
+		{`DROP SEQUENCE a`},
+		{`DROP SEQUENCE a.b`},
+		{`DROP SEQUENCE a, b`},
+		{`DROP SEQUENCE IF EXISTS a`},
+		{`DROP SEQUENCE a RESTRICT`},
+		{`DROP SEQUENCE IF EXISTS a, b CASCADE`},
+
 		{`DROP USER a`},
 		{`DROP USER a, b`},
 
+		{`DROP ROLE a`},
+		{`DROP ROLE IF EXISTS a, b`},
+		{`DROP POLICY p ON a`},
+		{`DROP POLICY IF EXISTS p ON db.a`},
+
 		{`CANCEL JOB a`},
 		{`CANCEL QUERY a`},
 		{`RESUME JOB a`},
@@ -261,6 +315,8 @@ func TestParse(t *testing.T) {
 		{`SHOW TESTING_RANGES FROM INDEX d.i`},
 		{`SHOW TESTING_RANGES FROM INDEX i`},
 		{`SHOW EXPERIMENTAL_FINGERPRINTS FROM TABLE d.t`},
+		{`SHOW STATISTICS FOR TABLE t`},
+		{`SHOW STATISTICS FOR TABLE d.t`},
 
 		// Tables are the default, but can also be specified with
 		// GRANT x ON TABLE y. However, the stringer does not output TABLE.
@@ -308,6 +364,16 @@ func TestParse(t *testing.T) {
 		{`DEALLOCATE a`},
 		{`DEALLOCATE ALL`},
 
+		{`DECLARE a CURSOR FOR SELECT * FROM t`},
+		{`DECLARE a CURSOR FOR SELECT $1 FROM t ORDER BY k LIMIT 10`},
+		{`FETCH 1 FROM a`},
+		{`FETCH 10 FROM a`},
+		{`FETCH ALL FROM a`},
+		{`MOVE 3 FROM a`},
+		{`MOVE ALL FROM a`},
+		{`CLOSE a`},
+		{`CLOSE ALL`},
+
 		// Tables are the default, but can also be specified with
 		// GRANT x ON TABLE y. However, the stringer does not output TABLE.
 		{`GRANT SELECT ON foo TO root`},
@@ -317,6 +383,11 @@ func TestParse(t *testing.T) {
 		{`GRANT SELECT, INSERT ON DATABASE bar TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO "test-user"`},
+		{`GRANT SELECT (a, b) ON foo TO bar`},
+		{`GRANT SELECT, UPDATE (a) ON foo, db.baz TO bar, "test-user"`},
+
+		{`GRANT foo TO bar`},
+		{`GRANT foo, "test-role" TO bar, baz WITH ADMIN OPTION`},
 
 		// Tables are the default, but can also be specified with
 		// REVOKE x ON TABLE y. However, the stringer does not output TABLE.
@@ -326,6 +397,11 @@ func TestParse(t *testing.T) {
 		{`REVOKE ALL ON DATABASE foo FROM root, test`},
 		{`REVOKE SELECT, INSERT ON DATABASE bar FROM foo, bar, baz`},
 		{`REVOKE SELECT, INSERT ON DATABASE db1, db2 FROM foo, bar, baz`},
+		{`REVOKE SELECT (a, b) ON foo FROM bar`},
+		{`REVOKE ALL (a) ON foo FROM bar`},
+
+		{`REVOKE foo FROM bar`},
+		{`REVOKE ADMIN OPTION FOR foo, "test-role" FROM bar, baz`},
 
 		{`INSERT INTO a VALUES (1)`},
 		{`INSERT INTO a.b VALUES (1)`},
@@ -434,10 +510,14 @@ func TestParse(t *testing.T) {
 		{`SELECT DATE 'foo'`},
 		{`SELECT TIMESTAMP 'foo'`},
 		{`SELECT TIMESTAMP WITH TIME ZONE 'foo'`},
+		{`SELECT TIME 'foo'`},
+		{`SELECT TIME WITH TIME ZONE 'foo'`},
 		{`SELECT CHAR 'foo'`},
 
 		{`SELECT '192.168.0.1':::INET`},
 		{`SELECT '192.168.0.1'::INET`},
+		{`SELECT '{"a": 1}':::JSONB`},
+		{`SELECT '{"a": 1}'::JSONB`},
 
 		{`SELECT 'a' AS "12345"`},
 		{`SELECT 'a' AS clnm`},
@@ -474,6 +554,18 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM generate_series(1, 32)`},
 		{`SELECT a FROM generate_series(1, 32) AS s (x)`},
 		{`SELECT a FROM generate_series(1, 32) WITH ORDINALITY AS s (x)`},
+		{`WITH a AS (SELECT 1) SELECT * FROM a`},
+		{`WITH a (x) AS (SELECT 1) SELECT x FROM a`},
+		{`WITH a AS (SELECT 1), b (x, y) AS (SELECT * FROM a, a) SELECT * FROM b`},
+		{`WITH a AS (SELECT 1) SELECT * FROM a ORDER BY 1 LIMIT 1`},
+		{`SELECT * FROM (WITH a AS (SELECT 1) SELECT * FROM a)`},
+		{`WITH a AS (SELECT 1) INSERT INTO t SELECT * FROM a`},
+		{`WITH a AS (SELECT 1) UPSERT INTO t SELECT * FROM a RETURNING NOTHING`},
+		{`WITH a AS (SELECT 1) UPDATE t SET b = 1 WHERE b IN (SELECT * FROM a)`},
+		{`WITH a AS (SELECT 1) DELETE FROM t WHERE b IN (SELECT * FROM a)`},
+		{`PREPARE a AS WITH b AS (SELECT $1) SELECT * FROM b`},
+		{`WITH RECURSIVE a (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM a WHERE n < 10) SELECT * FROM a`},
+		{`WITH RECURSIVE a AS (SELECT 1), b AS (SELECT * FROM a UNION SELECT * FROM b) SELECT * FROM b`},
 		{`SELECT a FROM t1, t2`},
 		{`SELECT a FROM t AS t1`},
 		{`SELECT a FROM t AS t1 (c1)`},
@@ -505,6 +597,12 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM t WHERE a !~ b`},
 		{`SELECT a FROM t WHERE a ~* c`},
 		{`SELECT a FROM t WHERE a !~* c`},
+		{`SELECT a FROM t WHERE a @> b`},
+		{`SELECT a FROM t WHERE a <@ b`},
+		{`SELECT a FROM t WHERE a ? b`},
+		{`SELECT a -> b FROM t`},
+		{`SELECT a ->> b FROM t`},
+		{`SELECT a -> b -> c FROM t`},
 		{`SELECT a FROM t WHERE a BETWEEN b AND c`},
 		{`SELECT a FROM t WHERE a NOT BETWEEN b AND c`},
 		{`SELECT a FROM t WHERE a IS NULL`},
@@ -567,6 +665,13 @@ func TestParse(t *testing.T) {
 		{`SELECT avg(1) OVER (ORDER BY c) FROM t`},
 		{`SELECT avg(1) OVER (PARTITION BY b ORDER BY c) FROM t`},
 		{`SELECT avg(1) OVER (w PARTITION BY b ORDER BY c) FROM t`},
+		{`SELECT avg(1) OVER (ROWS UNBOUNDED PRECEDING) FROM t`},
+		{`SELECT avg(1) OVER (ORDER BY c ROWS 1 PRECEDING) FROM t`},
+		{`SELECT avg(1) OVER (ORDER BY c ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING) FROM t`},
+		{`SELECT avg(1) OVER (ORDER BY c ROWS BETWEEN CURRENT ROW AND UNBOUNDED FOLLOWING) FROM t`},
+		{`SELECT avg(1) OVER (PARTITION BY b ORDER BY c RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) FROM t`},
+		{`SELECT avg(1) OVER (ORDER BY c RANGE BETWEEN 1 + 2 PRECEDING AND $1 FOLLOWING) FROM t`},
+		{`SELECT a FROM t WINDOW w AS (ORDER BY c ROWS BETWEEN 2 PRECEDING AND CURRENT ROW)`},
 
 		{`SELECT a FROM t UNION SELECT 1 FROM t`},
 		{`SELECT a FROM t UNION SELECT 1 FROM t UNION SELECT 1 FROM t`},
@@ -601,6 +706,8 @@ func TestParse(t *testing.T) {
 		{`SET a = 3.0`},
 		{`SET a = $1`},
 		{`SET a = off`},
+		{`SET LOCAL a = 3`},
+		{`SET LOCAL a = 3, 4`},
 		{`SET TRANSACTION READ ONLY`},
 		{`SET TRANSACTION READ WRITE`},
 		{`SET TRANSACTION ISOLATION LEVEL SNAPSHOT`},
@@ -658,6 +765,11 @@ func TestParse(t *testing.T) {
 		{`ALTER INDEX a@b RENAME TO b`},
 		{`ALTER INDEX b RENAME TO b`},
 		{`ALTER INDEX IF EXISTS a@b RENAME TO b`},
+
+		{`ALTER SEQUENCE a RENAME TO b`},
+		{`ALTER SEQUENCE IF EXISTS a RENAME TO b`},
+		{`ALTER SEQUENCE a INCREMENT BY 5 START 1000`},
+		{`ALTER SEQUENCE IF EXISTS a NO MINVALUE NO MAXVALUE CACHE 100`},
 		{`ALTER TABLE a RENAME COLUMN c1 TO c2`},
 		{`ALTER TABLE IF EXISTS a RENAME COLUMN c1 TO c2`},
 
@@ -673,6 +785,7 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE a ADD b INT CREATE FAMILY`},
 		{`ALTER TABLE a ADD b INT CREATE FAMILY fam_b`},
 		{`ALTER TABLE a ADD b INT CREATE IF NOT EXISTS FAMILY fam_b`},
+		{`ALTER TABLE a ADD b INT AS (a * 2) STORED`},
 
 		{`ALTER TABLE a DROP b, DROP CONSTRAINT a_idx`},
 		{`ALTER TABLE a DROP IF EXISTS b, DROP CONSTRAINT a_idx`},
@@ -687,12 +800,16 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE a DROP CONSTRAINT b CASCADE`},
 		{`ALTER TABLE a DROP CONSTRAINT IF EXISTS b RESTRICT`},
 		{`ALTER TABLE a VALIDATE CONSTRAINT a`},
+		{`ALTER TABLE a ENABLE ROW LEVEL SECURITY`},
+		{`ALTER TABLE a DISABLE ROW LEVEL SECURITY`},
 
 		{`ALTER TABLE a ALTER COLUMN b SET DEFAULT 42`},
 		{`ALTER TABLE a ALTER COLUMN b SET DEFAULT NULL`},
 		{`ALTER TABLE a ALTER COLUMN b DROP DEFAULT`},
 		{`ALTER TABLE a ALTER COLUMN b DROP NOT NULL`},
 		{`ALTER TABLE a ALTER b DROP NOT NULL`},
+		{`ALTER TABLE a ALTER COLUMN b TYPE STRING(50)`},
+		{`ALTER TABLE a ALTER b TYPE DECIMAL(10,2)`},
 
 		{`COPY t FROM STDIN`},
 		{`COPY t (a, b, c) FROM STDIN`},
@@ -767,14 +884,22 @@ func TestParse2(t *testing.T) {
 			`CREATE DATABASE a TEMPLATE = 'template0'`},
 		{`CREATE DATABASE a TEMPLATE = invalid`,
 			`CREATE DATABASE a TEMPLATE = 'invalid'`},
+		{`CREATE TABLE a (b INT REFERENCES other ON UPDATE NO ACTION ON DELETE NO ACTION)`,
+			`CREATE TABLE a (b INT REFERENCES other)`},
+		{`CREATE TABLE a (b INT REFERENCES other ON UPDATE CASCADE ON DELETE SET NULL)`,
+			`CREATE TABLE a (b INT REFERENCES other ON DELETE SET NULL ON UPDATE CASCADE)`},
 		{`CREATE TABLE a (b INT, UNIQUE INDEX foo (b))`,
 			`CREATE TABLE a (b INT, CONSTRAINT foo UNIQUE (b))`},
 		{`CREATE TABLE a (b INT, UNIQUE INDEX foo (b) INTERLEAVE IN PARENT c (d))`,
 			`CREATE TABLE a (b INT, CONSTRAINT foo UNIQUE (b) INTERLEAVE IN PARENT c (d))`},
 		{`CREATE INDEX ON a (b) COVERING (c)`, `CREATE INDEX ON a (b) STORING (c)`},
+		{`CREATE INDEX ON a ((lower(b)))`, `CREATE INDEX ON a (lower(b))`},
 
 		{`SELECT TIMESTAMP WITHOUT TIME ZONE 'foo'`, `SELECT TIMESTAMP 'foo'`},
 		{`SELECT CAST('foo' AS TIMESTAMP WITHOUT TIME ZONE)`, `SELECT CAST('foo' AS TIMESTAMP)`},
+		{`SELECT TIME WITHOUT TIME ZONE 'foo'`, `SELECT TIME 'foo'`},
+		{`SELECT TIMETZ 'foo'`, `SELECT TIME WITH TIME ZONE 'foo'`},
+		{`SELECT CAST('foo' AS TIMETZ)`, `SELECT CAST('foo' AS TIME WITH TIME ZONE)`},
 		{`SELECT CAST(1 AS "char")`, `SELECT CAST(1 AS CHAR)`},
 
 		{`SELECT 'a' FROM t@{FORCE_INDEX=bar}`, `SELECT 'a' FROM t@bar`},
@@ -872,6 +997,8 @@ func TestParse2(t *testing.T) {
 			`SELECT current_user()`},
 		{`SELECT SESSION_USER`,
 			`SELECT current_user()`},
+		{`SELECT CURRENT_ROLE`,
+			`SELECT current_user()`},
 		{`SELECT USER`,
 			`SELECT current_user()`},
 		// Offset has an optional ROW/ROWS keyword.
@@ -930,6 +1057,8 @@ func TestParse2(t *testing.T) {
 			`SET "time zone" = 'local'`},
 		{`SET TIME ZONE pst8pdt`,
 			`SET "time zone" = 'pst8pdt'`},
+		{`SET LOCAL TIME ZONE 'Europe/Rome'`,
+			`SET LOCAL "time zone" = 'Europe/Rome'`},
 		{`SET TIME ZONE "Europe/Rome"`,
 			`SET "time zone" = 'Europe/Rome'`},
 		{`SET TIME ZONE INTERVAL '-7h'`,
@@ -1026,10 +1155,24 @@ func TestParse2(t *testing.T) {
 		{"ROLLBACK TO SAVEPOINT foo", "ROLLBACK TRANSACTION TO SAVEPOINT foo"},
 		{"ROLLBACK TRANSACTION TO foo", "ROLLBACK TRANSACTION TO SAVEPOINT foo"},
 		{"ROLLBACK TRANSACTION TO SAVEPOINT foo", "ROLLBACK TRANSACTION TO SAVEPOINT foo"},
+		{`ALTER TABLE a ALTER COLUMN b SET DATA TYPE INT8`,
+			`ALTER TABLE a ALTER COLUMN b TYPE INT8`},
 		{`DEALLOCATE PREPARE a`,
 			`DEALLOCATE a`},
 		{`DEALLOCATE PREPARE ALL`,
 			`DEALLOCATE ALL`},
+		{`DECLARE a NO SCROLL CURSOR WITHOUT HOLD FOR SELECT 1`,
+			`DECLARE a CURSOR FOR SELECT 1`},
+		{`FETCH a`, `FETCH 1 FROM a`},
+		{`FETCH IN a`, `FETCH 1 FROM a`},
+		{`FETCH NEXT FROM a`, `FETCH 1 FROM a`},
+		{`FETCH FORWARD a`, `FETCH 1 FROM a`},
+		{`FETCH 5 IN a`, `FETCH 5 FROM a`},
+		{`FETCH FORWARD 5 FROM a`, `FETCH 5 FROM a`},
+		{`FETCH FORWARD ALL a`, `FETCH ALL FROM a`},
+		{`FETCH NEXT`, `FETCH 1 FROM "next"`},
+		{`MOVE a`, `MOVE 1 FROM a`},
+		{`MOVE FORWARD 2 IN a`, `MOVE 2 FROM a`},
 
 		{`BACKUP DATABASE foo TO bar`,
 			`BACKUP DATABASE foo TO 'bar'`},
@@ -1112,6 +1255,10 @@ SET TIME ZONE INTERVAL 'foobar'
 		{`SELECT INTERVAL 'foo'`, `could not parse "foo" as type interval: interval: missing unit at position 0: "foo" at or near "EOF"
 SELECT INTERVAL 'foo'
                      ^
+`},
+		{`GRANT foo ON t TO bar`, `not a valid privilege: "foo" at or near "on"
+GRANT foo ON t TO bar
+          ^
 `},
 		{`SELECT 1 /* hello`, `unterminated comment
 SELECT 1 /* hello
@@ -1621,6 +1768,11 @@ func TestParsePrecedence(t *testing.T) {
 		{`'a' || 'b' ~ 'c'`, regmatch(concat(a, b), c)},
 		{`'a' || 'b' ~* 'c'`, regimatch(concat(a, b), c)},
 
+		// JSON operators bind like || and tighter than comparisons.
+		{`'a' -> 'b' = 'c'`, cmp(EQ, binary(JSONFetchVal, a, b), c)},
+		{`'a' ->> 'b' || 'c'`, concat(binary(JSONFetchText, a, b), c)},
+		{`'a' @> 'b' AND 'c'`, and(cmp(Contains, a, b), c)},
+
 		// Unary ~ should have highest precedence.
 		{`~1+2`, binary(Plus, unary(UnaryComplement, one), two)},
 	}
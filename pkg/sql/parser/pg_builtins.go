@@ -41,6 +41,8 @@ var typeBuiltinsHaveUnderscore = map[oid.Oid]struct{}{
 	TypeUUID.Oid():        {},
 	TypeTimestamp.Oid():   {},
 	TypeTimestampTZ.Oid(): {},
+	TypeTime.Oid():        {},
+	TypeTimeTZ.Oid():      {},
 	TypeTuple.Oid():       {},
 }
 
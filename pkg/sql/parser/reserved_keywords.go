@@ -91,6 +91,8 @@ var reservedKeywords = map[string]struct{}{
 	"INTO":              {},
 	"IS":                {},
 	"JOIN":              {},
+	"JSON":              {},
+	"JSONB":             {},
 	"LATERAL":           {},
 	"LEADING":           {},
 	"LEAST":             {},
@@ -139,6 +141,7 @@ var reservedKeywords = map[string]struct{}{
 	"TIME":              {},
 	"TIMESTAMP":         {},
 	"TIMESTAMPTZ":       {},
+	"TIMETZ":            {},
 	"TO":                {},
 	"TRAILING":          {},
 	"TREAT":             {},
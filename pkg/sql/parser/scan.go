@@ -321,7 +321,34 @@ func (s *Scanner) scan(lval *sqlSymType) {
 		return
 
 	case '?':
-		lval.id = HELPTOKEN
+		// A question mark at the end of the input requests contextual
+		// help; anywhere else it is the JSON key existence operator.
+		if strings.TrimSpace(s.in[s.pos:]) == "" {
+			lval.id = HELPTOKEN
+		}
+		return
+
+	case '-':
+		switch s.peek() {
+		case '>': // ->
+			s.pos++
+			if s.peek() == '>' { // ->>
+				s.pos++
+				lval.id = FETCHTEXT
+				return
+			}
+			lval.id = FETCHVAL
+			return
+		}
+		return
+
+	case '@':
+		switch s.peek() {
+		case '>': // @>
+			s.pos++
+			lval.id = CONTAINS
+			return
+		}
 		return
 
 	case '<':
@@ -330,6 +357,10 @@ func (s *Scanner) scan(lval *sqlSymType) {
 			s.pos++
 			lval.id = LSHIFT
 			return
+		case '@': // <@
+			s.pos++
+			lval.id = CONTAINED_BY
+			return
 		case '>': // <>
 			s.pos++
 			lval.id = NOT_EQUALS
@@ -38,6 +38,7 @@ func (*ValuesClause) selectStatement() {}
 
 // Select represents a SelectStatement with an ORDER and/or LIMIT.
 type Select struct {
+	With    *With
 	Select  SelectStatement
 	OrderBy OrderBy
 	Limit   *Limit
@@ -45,6 +46,7 @@ type Select struct {
 
 // Format implements the NodeFormatter interface.
 func (node *Select) Format(buf *bytes.Buffer, f FmtFlags) {
+	FormatNode(buf, f, node.With)
 	FormatNode(buf, f, node.Select)
 	FormatNode(buf, f, node.OrderBy)
 	FormatNode(buf, f, node.Limit)
@@ -546,6 +548,7 @@ type WindowDef struct {
 	RefName    Name
 	Partitions Exprs
 	OrderBy    OrderBy
+	Frame      *WindowFrame
 }
 
 // Format implements the NodeFormatter interface.
@@ -574,9 +577,106 @@ func (node *WindowDef) Format(buf *bytes.Buffer, f FmtFlags) {
 			buf.WriteString(tmpBuf.String()[1:])
 		}
 		needSpaceSeparator = true
-		_ = needSpaceSeparator // avoid compiler warning until TODO below is addressed.
 	}
-	// TODO(nvanbenschoten): Support Window Frames.
-	// if node.Frame != nil {}
+	if node.Frame != nil {
+		if needSpaceSeparator {
+			buf.WriteRune(' ')
+		}
+		FormatNode(buf, f, node.Frame)
+	}
 	buf.WriteRune(')')
 }
+
+// WindowFrameMode indicates which mode of framing is used.
+type WindowFrameMode int
+
+const (
+	// RangeFrame is the mode of specifying frame in terms of logical range (e.g. 100 units cheaper).
+	RangeFrame WindowFrameMode = iota
+	// RowsFrame is the mode of specifying frame in terms of physical offsets (e.g. 1 row before etc).
+	RowsFrame
+)
+
+var windowFrameModeName = [...]string{
+	RangeFrame: "RANGE",
+	RowsFrame:  "ROWS",
+}
+
+func (m WindowFrameMode) String() string {
+	return windowFrameModeName[m]
+}
+
+// WindowFrameBoundType indicates which type of boundary is used.
+type WindowFrameBoundType int
+
+const (
+	// UnboundedPreceding represents UNBOUNDED PRECEDING type of boundary.
+	UnboundedPreceding WindowFrameBoundType = iota
+	// OffsetPreceding represents 'value' PRECEDING type of boundary.
+	OffsetPreceding
+	// CurrentRow represents CURRENT ROW type of boundary.
+	CurrentRow
+	// OffsetFollowing represents 'value' FOLLOWING type of boundary.
+	OffsetFollowing
+	// UnboundedFollowing represents UNBOUNDED FOLLOWING type of boundary.
+	UnboundedFollowing
+)
+
+// WindowFrameBound specifies the offset and the type of boundary.
+type WindowFrameBound struct {
+	BoundType  WindowFrameBoundType
+	OffsetExpr Expr
+}
+
+// HasOffset returns whether node contains an offset.
+func (node *WindowFrameBound) HasOffset() bool {
+	return node.BoundType == OffsetPreceding || node.BoundType == OffsetFollowing
+}
+
+// Format implements the NodeFormatter interface.
+func (node *WindowFrameBound) Format(buf *bytes.Buffer, f FmtFlags) {
+	switch node.BoundType {
+	case UnboundedPreceding:
+		buf.WriteString("UNBOUNDED PRECEDING")
+	case OffsetPreceding:
+		FormatNode(buf, f, node.OffsetExpr)
+		buf.WriteString(" PRECEDING")
+	case CurrentRow:
+		buf.WriteString("CURRENT ROW")
+	case OffsetFollowing:
+		FormatNode(buf, f, node.OffsetExpr)
+		buf.WriteString(" FOLLOWING")
+	case UnboundedFollowing:
+		buf.WriteString("UNBOUNDED FOLLOWING")
+	default:
+		panic(fmt.Sprintf("unhandled case: %d", node.BoundType))
+	}
+}
+
+// WindowFrameBounds specifies boundaries of the window frame. EndBound is nil
+// if only the start of the frame is specified, in which case the frame ends
+// at the current row.
+type WindowFrameBounds struct {
+	StartBound *WindowFrameBound
+	EndBound   *WindowFrameBound
+}
+
+// WindowFrame represents static state of window frame over which calculations are made.
+type WindowFrame struct {
+	Mode   WindowFrameMode   // the mode of framing being used
+	Bounds WindowFrameBounds // the bounds of the frame
+}
+
+// Format implements the NodeFormatter interface.
+func (node *WindowFrame) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString(node.Mode.String())
+	buf.WriteRune(' ')
+	if node.Bounds.EndBound != nil {
+		buf.WriteString("BETWEEN ")
+		FormatNode(buf, f, node.Bounds.StartBound)
+		buf.WriteString(" AND ")
+		FormatNode(buf, f, node.Bounds.EndBound)
+	} else {
+		FormatNode(buf, f, node.Bounds.StartBound)
+	}
+}
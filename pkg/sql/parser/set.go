@@ -26,11 +26,17 @@ import "bytes"
 type SetVar struct {
 	Name   VarName
 	Values Exprs
+	// Local is set for SET LOCAL, whose change is undone at the end of the
+	// transaction.
+	Local bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *SetVar) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString("SET ")
+	if node.Local {
+		buf.WriteString("LOCAL ")
+	}
 	if node.Name == nil {
 		buf.WriteString("ROW (")
 		FormatNode(buf, f, node.Values)
@@ -169,6 +169,27 @@ func (u *sqlSymUnion) slct() *Select {
 func (u *sqlSymUnion) selectStmt() SelectStatement {
     return u.val.(SelectStatement)
 }
+func (u *sqlSymUnion) with() *With {
+    if with, ok := u.val.(*With); ok {
+        return with
+    }
+    return nil
+}
+func (u *sqlSymUnion) cte() *CTE {
+    return u.val.(*CTE)
+}
+func (u *sqlSymUnion) ctes() []*CTE {
+    return u.val.([]*CTE)
+}
+func (u *sqlSymUnion) int64() int64 {
+    return u.val.(int64)
+}
+func (u *sqlSymUnion) seqOpt() SequenceOption {
+    return u.val.(SequenceOption)
+}
+func (u *sqlSymUnion) seqOpts() []SequenceOption {
+    return u.val.([]SequenceOption)
+}
 func (u *sqlSymUnion) colDef() *ColumnTableDef {
     return u.val.(*ColumnTableDef)
 }
@@ -265,9 +286,6 @@ func (u *sqlSymUnion) targetList() TargetList {
 func (u *sqlSymUnion) targetListPtr() *TargetList {
     return u.val.(*TargetList)
 }
-func (u *sqlSymUnion) privilegeType() privilege.Kind {
-    return u.val.(privilege.Kind)
-}
 func (u *sqlSymUnion) privilegeList() privilege.List {
     return u.val.(privilege.List)
 }
@@ -310,6 +328,12 @@ func (u *sqlSymUnion) idxElem() IndexElem {
 func (u *sqlSymUnion) idxElems() IndexElemList {
     return u.val.(IndexElemList)
 }
+func (u *sqlSymUnion) referenceAction() ReferenceAction {
+    return u.val.(ReferenceAction)
+}
+func (u *sqlSymUnion) referenceActions() ReferenceActions {
+    return u.val.(ReferenceActions)
+}
 func (u *sqlSymUnion) dropBehavior() DropBehavior {
     return u.val.(DropBehavior)
 }
@@ -325,6 +349,15 @@ func (u *sqlSymUnion) windowDef() *WindowDef {
 func (u *sqlSymUnion) window() Window {
     return u.val.(Window)
 }
+func (u *sqlSymUnion) windowFrame() *WindowFrame {
+    return u.val.(*WindowFrame)
+}
+func (u *sqlSymUnion) windowFrameBounds() WindowFrameBounds {
+    return u.val.(WindowFrameBounds)
+}
+func (u *sqlSymUnion) windowFrameBound() *WindowFrameBound {
+    return u.val.(*WindowFrameBound)
+}
 func (u *sqlSymUnion) op() operator {
     return u.val.(operator)
 }
@@ -346,6 +379,9 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 func (u *sqlSymUnion) transactionModes() TransactionModes {
     return u.val.(TransactionModes)
 }
+func (u *sqlSymUnion) cursorStmt() CursorStmt {
+    return u.val.(CursorStmt)
+}
 
 %}
 
@@ -359,6 +395,7 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %token <str>   TYPECAST TYPEANNOTATE DOT_DOT
 %token <str>   LESS_EQUALS GREATER_EQUALS NOT_EQUALS
 %token <str>   NOT_REGMATCH REGIMATCH NOT_REGIMATCH
+%token <str>   FETCHVAL FETCHTEXT CONTAINS CONTAINED_BY
 %token <str>   ERROR
 
 // If you want to make any keyword changes, add the new keyword here as well as
@@ -366,42 +403,42 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 // below; search this file for "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str>   ACTION ADD
+%token <str>   ACTION ADD ADMIN
 %token <str>   ALL ALTER ANALYSE ANALYZE AND ANY ANNOTATE_TYPE ARRAY AS ASC
 %token <str>   ASYMMETRIC AT
 
 %token <str>   BACKUP BEGIN BETWEEN BIGINT BIGSERIAL BIT
 %token <str>   BLOB BOOL BOOLEAN BOTH BY BYTEA BYTES
 
-%token <str>   CANCEL CASCADE CASE CAST CHAR
+%token <str>   CACHE CANCEL CASCADE CASE CAST CHAR
 %token <str>   CHARACTER CHARACTERISTICS CHECK
-%token <str>   CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMIT
+%token <str>   CLOSE CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMIT
 %token <str>   COMMITTED CONCAT CONFLICT CONSTRAINT CONSTRAINTS
 %token <str>   COPY COVERING CREATE
 %token <str>   CROSS CSV CUBE CURRENT CURRENT_CATALOG CURRENT_DATE CURRENT_SCHEMA
 %token <str>   CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP
-%token <str>   CURRENT_USER CYCLE
+%token <str>   CURRENT_USER CURSOR CYCLE
 
 %token <str>   DATA DATABASE DATABASES DATE DAY DEC DECIMAL DEFAULT
-%token <str>   DEALLOCATE DEFERRABLE DELETE DESC
-%token <str>   DISCARD DISTINCT DO DOUBLE DROP
+%token <str>   DEALLOCATE DECLARE DEFERRABLE DELETE DESC
+%token <str>   DISABLE DISCARD DISTINCT DO DOUBLE DROP
 
-%token <str>   ELSE ENCODING END ESCAPE EXCEPT
+%token <str>   ELSE ENABLE ENCODING END ESCAPE EXCEPT
 %token <str>   EXISTS EXECUTE EXPERIMENTAL_FINGERPRINTS EXPLAIN EXTRACT EXTRACT_DURATION
 
 %token <str>   FALSE FAMILY FETCH FILTER FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR
-%token <str>   FORCE_INDEX FOREIGN FROM FULL
+%token <str>   FORCE_INDEX FOREIGN FORWARD FROM FULL
 
 %token <str>   GRANT GRANTS GREATEST GROUP GROUPING
 
-%token <str>   HAVING HELP HIGH HOUR
+%token <str>   HAVING HELP HIGH HOLD HOUR
 
-%token <str>   IMPORT INCREMENTAL IF IFNULL ILIKE IN INET INTERLEAVE
+%token <str>   IMPORT INCREMENT INCREMENTAL IF IFNULL ILIKE IN INET INTERLEAVE
 %token <str>   INDEX INDEXES INITIALLY
 %token <str>   INNER INSERT INT INT2VECTOR INT2 INT4 INT8 INT64 INTEGER
-%token <str>   INTERSECT INTERVAL INTO IS ISOLATION
+%token <str>   INTERSECT INTERVAL INTO INVERTED IS ISOLATION
 
-%token <str>   JOB JOBS JOIN
+%token <str>   JOB JOBS JOIN JSON JSONB
 
 %token <str>   KEY KEYS KV
 
@@ -409,16 +446,16 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %token <str>   LEADING LEAST LEFT LEVEL LIKE LIMIT LOCAL
 %token <str>   LOCALTIME LOCALTIMESTAMP LOW LSHIFT
 
-%token <str>   MATCH MINUTE MONTH
+%token <str>   MATCH MAXVALUE MINUTE MINVALUE MONTH MOVE
 
 %token <str>   NAN NAME NAMES NATURAL NEXT NO NO_INDEX_JOIN NORMAL
 %token <str>   NOT NOTHING NULL NULLIF
 %token <str>   NULLS NUMERIC
 
-%token <str>   OF OFF OFFSET OID ON ONLY OPTIONS OR
+%token <str>   OF OFF OFFSET OID ON ONLY OPTION OPTIONS OR
 %token <str>   ORDER ORDINALITY OUT OUTER OVER OVERLAPS OVERLAY
 
-%token <str>   PARENT PARTIAL PARTITION PASSWORD PAUSE PLACING PLANS POSITION
+%token <str>   PARENT PARTIAL PARTITION PASSWORD PAUSE PLACING PLANS POLICY POSITION
 %token <str>   PRECEDING PRECISION PREPARE PRIMARY PRIORITY
 
 %token <str>   QUERIES QUERY
@@ -427,16 +464,16 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %token <str>   REGCLASS REGPROC REGPROCEDURE REGNAMESPACE REGTYPE
 %token <str>   RENAME REPEATABLE
 %token <str>   RELEASE RESET RESTORE RESTRICT RESUME RETURNING REVOKE RIGHT
-%token <str>   ROLLBACK ROLLUP ROW ROWS RSHIFT
+%token <str>   ROLE ROLLBACK ROLLUP ROW ROWS RSHIFT
 
-%token <str>   SAVEPOINT SCATTER SEARCH SECOND SELECT SEQUENCES
+%token <str>   SAVEPOINT SCATTER SCROLL SEARCH SECOND SECURITY SELECT SEQUENCE SEQUENCES
 %token <str>   SERIAL SERIALIZABLE SESSION SESSIONS SESSION_USER SET SETTING SETTINGS
 %token <str>   SHOW SIMILAR SIMPLE SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
-%token <str>   START STATUS STDIN STRICT STRING STORE STORING SUBSTRING
+%token <str>   START STATISTICS STATUS STDIN STRICT STRING STORE STORED STORING SUBSTRING
 %token <str>   SYMMETRIC SYSTEM
 
 %token <str>   TABLE TABLES TEMP TEMPLATE TEMPORARY TESTING_RANGES TESTING_RELOCATE TEXT THEN
-%token <str>   TIME TIMESTAMP TIMESTAMPTZ TO TRAILING TRACE TRANSACTION TREAT TRIM TRUE
+%token <str>   TIME TIMETZ TIMESTAMP TIMESTAMPTZ TO TRAILING TRACE TRANSACTION TREAT TRIM TRUE
 %token <str>   TRUNCATE TYPE
 
 %token <str>   UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN
@@ -476,6 +513,7 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %type <Statement> alter_table_stmt
 %type <Statement> alter_index_stmt
 %type <Statement> alter_view_stmt
+%type <Statement> alter_sequence_stmt
 %type <Statement> alter_database_stmt
 
 // ALTER TABLE
@@ -497,6 +535,10 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 // ALTER VIEW
 %type <Statement> alter_rename_view_stmt
 
+// ALTER SEQUENCE
+%type <Statement> alter_rename_sequence_stmt
+%type <Statement> alter_sequence_options_stmt
+
 %type <Statement> backup_stmt
 %type <Statement> begin_stmt
 
@@ -504,6 +546,7 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %type <Statement> cancel_job_stmt
 %type <Statement> cancel_query_stmt
 
+%type <Statement> close_cursor_stmt
 %type <Statement> commit_stmt
 %type <Statement> copy_from_stmt
 
@@ -513,7 +556,11 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %type <Statement> create_table_stmt
 %type <Statement> create_table_as_stmt
 %type <Statement> create_user_stmt
+%type <Statement> create_role_stmt
+%type <Statement> create_policy_stmt
 %type <Statement> create_view_stmt
+%type <Statement> create_sequence_stmt
+%type <Statement> create_stats_stmt
 %type <Statement> delete_stmt
 %type <Statement> discard_stmt
 
@@ -522,7 +569,10 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %type <Statement> drop_index_stmt
 %type <Statement> drop_table_stmt
 %type <Statement> drop_user_stmt
+%type <Statement> drop_role_stmt
+%type <Statement> drop_policy_stmt
 %type <Statement> drop_view_stmt
+%type <Statement> drop_sequence_stmt
 
 %type <Statement> explain_stmt
 %type <Statement> prepare_stmt
@@ -530,6 +580,9 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %type <Statement> explainable_stmt
 %type <Statement> execute_stmt
 %type <Statement> deallocate_stmt
+%type <Statement> declare_cursor_stmt
+%type <Statement> fetch_cursor_stmt
+%type <Statement> move_cursor_stmt
 %type <Statement> grant_stmt
 %type <Statement> insert_stmt
 %type <Statement> import_stmt
@@ -566,6 +619,7 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %type <Statement> show_queries_stmt
 %type <Statement> show_session_stmt
 %type <Statement> show_sessions_stmt
+%type <Statement> show_stats_stmt
 %type <Statement> show_tables_stmt
 %type <Statement> show_testing_stmt
 %type <Statement> show_trace_stmt
@@ -694,6 +748,10 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %type <*ColumnTableDef> column_def
 %type <TableDef> table_elem
 %type <Expr>  where_clause
+%type <Expr> opt_policy_using opt_policy_check
+%type <NameList> opt_policy_roles
+%type <CursorStmt> cursor_fetch_args
+%type <empty> opt_from_or_in opt_cursor_scroll opt_cursor_hold
 %type <NamePart> glob_indirection
 %type <NamePart> name_indirection
 %type <*ArraySubscript> array_subscript
@@ -745,6 +803,9 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %type <empty> opt_varying
 
 %type <*NumVal>  signed_iconst
+%type <int64>    signed_iconst64
+%type <[]SequenceOption> sequence_option_list opt_sequence_option_list
+%type <SequenceOption> sequence_option_elem
 %type <Expr>  var_value
 %type <Exprs> var_list
 %type <UnresolvedName> var_name
@@ -764,13 +825,16 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %type <[]NamedColumnQualification> col_qual_list
 %type <NamedColumnQualification> col_qualification
 %type <ColumnQualification> col_qualification_elem
-%type <empty> key_actions key_delete key_match key_update key_action
+%type <empty> key_match
+%type <ReferenceActions> key_actions
+%type <ReferenceAction> key_action key_delete key_update
 
 %type <Expr>  func_application func_expr_common_subexpr
 %type <Expr>  func_expr func_expr_windowless
-%type <empty> common_table_expr
-%type <empty> with_clause opt_with opt_with_clause
-%type <empty> cte_list
+%type <*CTE> common_table_expr
+%type <*With> with_clause opt_with_clause
+%type <[]*CTE> cte_list
+%type <empty> opt_with
 
 %type <empty> within_group_clause
 %type <Expr> filter_clause
@@ -778,15 +842,18 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %type <Window> window_clause window_definition_list
 %type <*WindowDef> window_definition over_clause window_specification
 %type <str> opt_existing_window_name
-%type <empty> opt_frame_clause frame_extent frame_bound
+%type <*WindowFrame> opt_frame_clause
+%type <WindowFrameBounds> frame_extent
+%type <*WindowFrameBound> frame_bound
 
 %type <[]ColumnID> opt_tableref_col_list tableref_col_list
 
 %type <TargetList>    targets
 %type <*TargetList> on_privilege_target_clause
 %type <NameList>       grantee_list for_grantee_clause
-%type <privilege.List> privileges privilege_list
-%type <privilege.Kind> privilege
+%type <privilege.List> privileges
+%type <NameList> privilege_list
+%type <str> privilege
 
 // Precedence: lowest to highest
 %nonassoc  VALUES              // see value_clause
@@ -827,7 +894,7 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 // funny behavior of UNBOUNDED on the SQL standard, though.
 %nonassoc  UNBOUNDED         // ideally should have same precedence as IDENT
 %nonassoc  IDENT NULL PARTITION RANGE ROWS PRECEDING FOLLOWING CUBE ROLLUP
-%left      CONCAT       // multi-character ops
+%left      CONCAT FETCHVAL FETCHTEXT CONTAINS CONTAINED_BY '?' // multi-character ops
 %left      '|'
 %left      '#'
 %left      '&'
@@ -881,17 +948,21 @@ stmt:
 | alter_stmt      // help texts in sub-rule
 | backup_stmt     // EXTEND WITH HELP: BACKUP
 | cancel_stmt     // help texts in sub-rule
+| close_cursor_stmt // EXTEND WITH HELP: CLOSE
 | copy_from_stmt
 | create_stmt     // help texts in sub-rule
 | deallocate_stmt // EXTEND WITH HELP: DEALLOCATE
+| declare_cursor_stmt // EXTEND WITH HELP: DECLARE
 | delete_stmt     // EXTEND WITH HELP: DELETE
 | discard_stmt    // EXTEND WITH HELP: DISCARD
 | drop_stmt       // help texts in sub-rule
 | execute_stmt    // EXTEND WITH HELP: EXECUTE
 | explain_stmt    // EXTEND WITH HELP: EXPLAIN
+| fetch_cursor_stmt // EXTEND WITH HELP: FETCH
 | grant_stmt      // EXTEND WITH HELP: GRANT
 | insert_stmt     // EXTEND WITH HELP: INSERT
 | import_stmt     // EXTEND WITH HELP: IMPORT
+| move_cursor_stmt // EXTEND WITH HELP: MOVE
 | pause_stmt      // EXTEND WITH HELP: PAUSE JOB
 | prepare_stmt    // EXTEND WITH HELP: PREPARE
 | restore_stmt    // EXTEND WITH HELP: RESTORE
@@ -917,11 +988,12 @@ stmt:
 
 // %Help: ALTER
 // %Category: Group
-// %Text: ALTER TABLE, ALTER INDEX, ALTER VIEW, ALTER DATABASE
+// %Text: ALTER TABLE, ALTER INDEX, ALTER VIEW, ALTER SEQUENCE, ALTER DATABASE
 alter_stmt:
   alter_table_stmt    // EXTEND WITH HELP: ALTER TABLE
 | alter_index_stmt    // EXTEND WITH HELP: ALTER INDEX
 | alter_view_stmt     // EXTEND WITH HELP: ALTER VIEW
+| alter_sequence_stmt // EXTEND WITH HELP: ALTER SEQUENCE
 | alter_database_stmt // EXTEND WITH HELP: ALTER DATABASE
 | ALTER error         // SHOW HELP: ALTER
 
@@ -937,9 +1009,11 @@ alter_stmt:
 //   ALTER TABLE ... DROP CONSTRAINT [IF EXISTS] <constraintname> [RESTRICT | CASCADE]
 //   ALTER TABLE ... ALTER [COLUMN] <colname> {SET DEFAULT <expr> | DROP DEFAULT}
 //   ALTER TABLE ... ALTER [COLUMN] <colname> DROP NOT NULL
+//   ALTER TABLE ... ALTER [COLUMN] <colname> [SET DATA] TYPE <type>
 //   ALTER TABLE ... RENAME TO <newname>
 //   ALTER TABLE ... RENAME [COLUMN] <colname> TO <newname>
 //   ALTER TABLE ... VALIDATE CONSTRAINT <constraintname>
+//   ALTER TABLE ... {ENABLE | DISABLE} ROW LEVEL SECURITY
 //   ALTER TABLE ... SPLIT AT <selectclause>
 //   ALTER TABLE ... SCATTER [ FROM ( <exprs...> ) TO ( <exprs...> ) ]
 //
@@ -971,6 +1045,35 @@ alter_view_stmt:
 // prefix is spread over multiple non-terminals.
 | ALTER VIEW error // SHOW HELP: ALTER VIEW
 
+// %Help: ALTER SEQUENCE - change the definition of a sequence
+// %Category: DDL
+// %Text:
+// ALTER SEQUENCE [IF EXISTS] <name>
+//   [INCREMENT <increment>]
+//   [MINVALUE <minvalue> | NO MINVALUE]
+//   [MAXVALUE <maxvalue> | NO MAXVALUE]
+//   [START <start>]
+//   [CACHE <cache>]
+//   [[NO] CYCLE]
+// ALTER SEQUENCE [IF EXISTS] <name> RENAME TO <newname>
+// %SeeAlso: CREATE SEQUENCE, DROP SEQUENCE
+alter_sequence_stmt:
+  alter_rename_sequence_stmt
+| alter_sequence_options_stmt
+// ALTER SEQUENCE has its error help token here because the ALTER
+// SEQUENCE prefix is spread over multiple non-terminals.
+| ALTER SEQUENCE error // SHOW HELP: ALTER SEQUENCE
+
+alter_sequence_options_stmt:
+  ALTER SEQUENCE relation_expr sequence_option_list
+  {
+    $$.val = &AlterSequence{Name: $3.normalizableTableName(), Options: $4.seqOpts(), IfExists: false}
+  }
+| ALTER SEQUENCE IF EXISTS relation_expr sequence_option_list
+  {
+    $$.val = &AlterSequence{Name: $5.normalizableTableName(), Options: $6.seqOpts(), IfExists: true}
+  }
+
 // %Help: ALTER DATABASE - change the definition of a database
 // %Category: DDL
 // %Text:
@@ -1123,7 +1226,14 @@ alter_table_cmd:
   }
   // ALTER TABLE <name> ALTER [COLUMN] <colname> [SET DATA] TYPE <typename>
   //     [ USING <expression> ]
-| ALTER opt_column name opt_set_data TYPE typename opt_collate_clause alter_using { return unimplemented(sqllex, "alter set type") }
+| ALTER opt_column name opt_set_data TYPE typename opt_collate_clause alter_using
+  {
+    $$.val = &AlterTableAlterColumnType{
+      columnKeyword: $2.bool(),
+      Column: Name($3),
+      ToType: $6.colType(),
+    }
+  }
   // ALTER TABLE <name> ADD CONSTRAINT ...
 | ADD table_constraint opt_validate_behavior
   {
@@ -1141,6 +1251,16 @@ alter_table_cmd:
       Constraint: Name($3),
     }
   }
+  // ALTER TABLE <name> ENABLE ROW LEVEL SECURITY
+| ENABLE ROW LEVEL SECURITY
+  {
+    $$.val = &AlterTableRowLevelSecurity{Enable: true}
+  }
+  // ALTER TABLE <name> DISABLE ROW LEVEL SECURITY
+| DISABLE ROW LEVEL SECURITY
+  {
+    $$.val = &AlterTableRowLevelSecurity{Enable: false}
+  }
   // ALTER TABLE <name> DROP CONSTRAINT IF EXISTS <name> [RESTRICT|CASCADE]
 | DROP CONSTRAINT IF EXISTS name opt_drop_behavior
   {
@@ -1410,7 +1530,7 @@ cancel_query_stmt:
 // %Category: Group
 // %Text:
 // CREATE DATABASE, CREATE TABLE, CREATE INDEX, CREATE TABLE AS,
-// CREATE USER, CREATE VIEW
+// CREATE USER, CREATE ROLE, CREATE VIEW, CREATE SEQUENCE, CREATE POLICY
 create_stmt:
   create_database_stmt // EXTEND WITH HELP: CREATE DATABASE
 | create_index_stmt    // EXTEND WITH HELP: CREATE INDEX
@@ -1419,7 +1539,11 @@ create_stmt:
 // Error case for both CREATE TABLE and CREATE TABLE ... AS in one
 | CREATE TABLE error   // SHOW HELP: CREATE TABLE
 | create_user_stmt     // EXTEND WITH HELP: CREATE USER
+| create_role_stmt     // EXTEND WITH HELP: CREATE ROLE
+| create_policy_stmt   // EXTEND WITH HELP: CREATE POLICY
 | create_view_stmt     // EXTEND WITH HELP: CREATE VIEW
+| create_sequence_stmt // EXTEND WITH HELP: CREATE SEQUENCE
+| create_stats_stmt    // EXTEND WITH HELP: CREATE STATISTICS
 | CREATE error         // SHOW HELP: CREATE
 
 // %Help: DELETE - delete rows from a table
@@ -1432,6 +1556,7 @@ delete_stmt:
   opt_with_clause DELETE FROM relation_expr_opt_alias where_clause opt_limit_clause returning_clause
   {
     $$.val = &Delete{
+      With: $1.with(),
       Table: $4.tblExpr(),
       Where: newWhere(astWhere, $5.expr()),
       Limit: $6.limit(),
@@ -1442,27 +1567,34 @@ delete_stmt:
 
 // %Help: DISCARD - reset the session to its initial state
 // %Category: Cfg
-// %Text: DISCARD ALL
+// %Text: DISCARD { ALL | SEQUENCES }
 discard_stmt:
   DISCARD ALL
   {
     $$.val = &Discard{Mode: DiscardModeAll}
   }
 | DISCARD PLANS { return unimplemented(sqllex, "discard plans") }
-| DISCARD SEQUENCES { return unimplemented(sqllex, "discard sequences") }
+| DISCARD SEQUENCES
+  {
+    $$.val = &Discard{Mode: DiscardModeSequences}
+  }
 | DISCARD TEMP { return unimplemented(sqllex, "discard temp") }
 | DISCARD TEMPORARY { return unimplemented(sqllex, "discard temporary") }
 | DISCARD error // SHOW HELP: DISCARD
 
 // %Help: DROP
 // %Category: Group
-// %Text: DROP DATABASE, DROP INDEX, DROP TABLE, DROP VIEW, DROP USER
+// %Text: DROP DATABASE, DROP INDEX, DROP TABLE, DROP VIEW, DROP SEQUENCE, DROP USER, DROP ROLE,
+// DROP POLICY
 drop_stmt:
   drop_database_stmt // EXTEND WITH HELP: DROP DATABASE
 | drop_index_stmt    // EXTEND WITH HELP: DROP INDEX
 | drop_table_stmt    // EXTEND WITH HELP: DROP TABLE
 | drop_view_stmt     // EXTEND WITH HELP: DROP VIEW
+| drop_sequence_stmt // EXTEND WITH HELP: DROP SEQUENCE
 | drop_user_stmt     // EXTEND WITH HELP: DROP USER
+| drop_role_stmt     // EXTEND WITH HELP: DROP ROLE
+| drop_policy_stmt   // EXTEND WITH HELP: DROP POLICY
 | DROP error         // SHOW HELP: DROP
 
 // %Help: DROP VIEW - remove a view
@@ -1480,6 +1612,21 @@ drop_view_stmt:
   }
 | DROP VIEW error // SHOW HELP: DROP VIEW
 
+// %Help: DROP SEQUENCE - remove a sequence
+// %Category: DDL
+// %Text: DROP SEQUENCE [IF EXISTS] <sequenceName> [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: DROP
+drop_sequence_stmt:
+  DROP SEQUENCE table_name_list opt_drop_behavior
+  {
+    $$.val = &DropSequence{Names: $3.tableNameReferences(), IfExists: false, DropBehavior: $4.dropBehavior()}
+  }
+| DROP SEQUENCE IF EXISTS table_name_list opt_drop_behavior
+  {
+    $$.val = &DropSequence{Names: $5.tableNameReferences(), IfExists: true, DropBehavior: $6.dropBehavior()}
+  }
+| DROP SEQUENCE error // SHOW HELP: DROP SEQUENCE
+
 // %Help: DROP TABLE - remove a table
 // %Category: DDL
 // %Text: DROP TABLE [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
@@ -1544,7 +1691,7 @@ drop_database_stmt:
 // %Help: DROP USER - remove a user
 // %Category: Priv
 // %Text: DROP USER [IF EXISTS] <user> [, ...]
-// %SeeAlso: CREATE USER, SHOW USERS
+// %SeeAlso: CREATE USER, SHOW USERS, DROP ROLE
 drop_user_stmt:
   DROP USER name_list
   {
@@ -1556,6 +1703,36 @@ drop_user_stmt:
   }
 | DROP USER error // SHOW HELP: DROP USER
 
+// %Help: DROP ROLE - remove a role
+// %Category: Priv
+// %Text: DROP ROLE [IF EXISTS] <role> [, ...]
+// %SeeAlso: CREATE ROLE, DROP USER
+drop_role_stmt:
+  DROP ROLE name_list
+  {
+    $$.val = &DropRole{Names: $3.nameList(), IfExists: false}
+  }
+| DROP ROLE IF EXISTS name_list
+  {
+    $$.val = &DropRole{Names: $5.nameList(), IfExists: true}
+  }
+| DROP ROLE error // SHOW HELP: DROP ROLE
+
+// %Help: DROP POLICY - remove a row-level security policy
+// %Category: Priv
+// %Text: DROP POLICY [IF EXISTS] <name> ON <tablename>
+// %SeeAlso: CREATE POLICY
+drop_policy_stmt:
+  DROP POLICY name ON qualified_name
+  {
+    $$.val = &DropPolicy{Name: Name($3), Table: $5.normalizableTableName(), IfExists: false}
+  }
+| DROP POLICY IF EXISTS name ON qualified_name
+  {
+    $$.val = &DropPolicy{Name: Name($5), Table: $7.normalizableTableName(), IfExists: true}
+  }
+| DROP POLICY error // SHOW HELP: DROP POLICY
+
 table_name_list:
   any_name
   {
@@ -1731,13 +1908,123 @@ deallocate_stmt:
   }
 | DEALLOCATE error // SHOW HELP: DEALLOCATE
 
-// %Help: GRANT - define access privileges
+// %Help: DECLARE - define a cursor
+// %Category: Misc
+// %Text: DECLARE <cursorname> [NO SCROLL] CURSOR [WITHOUT HOLD] FOR <selectclause>
+//
+// Cursors can only be declared in a transaction block and are closed when
+// the transaction ends.
+// %SeeAlso: FETCH, MOVE, CLOSE, SELECT
+declare_cursor_stmt:
+  DECLARE name opt_cursor_scroll CURSOR opt_cursor_hold FOR select_stmt
+  {
+    $$.val = &DeclareCursor{Name: Name($2), Select: $7.slct()}
+  }
+| DECLARE error // SHOW HELP: DECLARE
+
+opt_cursor_scroll:
+  NO SCROLL {}
+| SCROLL { return unimplemented(sqllex, "scroll cursor") }
+| /* EMPTY */ {}
+
+opt_cursor_hold:
+  WITHOUT HOLD {}
+| WITH HOLD { return unimplemented(sqllex, "cursor with hold") }
+| /* EMPTY */ {}
+
+// %Help: FETCH - retrieve rows from a cursor
+// %Category: Misc
+// %Text: FETCH [ NEXT | FORWARD [ <count> | ALL ] | <count> | ALL ] [ FROM | IN ] <cursorname>
+// %SeeAlso: DECLARE, MOVE, CLOSE
+fetch_cursor_stmt:
+  FETCH cursor_fetch_args
+  {
+    $$.val = &FetchCursor{CursorStmt: $2.cursorStmt()}
+  }
+| FETCH error // SHOW HELP: FETCH
+
+// %Help: MOVE - skip rows of a cursor
+// %Category: Misc
+// %Text: MOVE [ NEXT | FORWARD [ <count> | ALL ] | <count> | ALL ] [ FROM | IN ] <cursorname>
+// %SeeAlso: DECLARE, FETCH, CLOSE
+move_cursor_stmt:
+  MOVE cursor_fetch_args
+  {
+    $$.val = &MoveCursor{CursorStmt: $2.cursorStmt()}
+  }
+| MOVE error // SHOW HELP: MOVE
+
+cursor_fetch_args:
+  name
+  {
+    $$.val = CursorStmt{Name: Name($1), Count: 1}
+  }
+| FROM name
+  {
+    $$.val = CursorStmt{Name: Name($2), Count: 1}
+  }
+| IN name
+  {
+    $$.val = CursorStmt{Name: Name($2), Count: 1}
+  }
+| NEXT opt_from_or_in name
+  {
+    $$.val = CursorStmt{Name: Name($3), Count: 1}
+  }
+| signed_iconst64 opt_from_or_in name
+  {
+    $$.val = CursorStmt{Name: Name($3), Count: $1.int64()}
+  }
+| ALL opt_from_or_in name
+  {
+    $$.val = CursorStmt{Name: Name($3), All: true}
+  }
+| FORWARD opt_from_or_in name
+  {
+    $$.val = CursorStmt{Name: Name($3), Count: 1}
+  }
+| FORWARD signed_iconst64 opt_from_or_in name
+  {
+    $$.val = CursorStmt{Name: Name($4), Count: $2.int64()}
+  }
+| FORWARD ALL opt_from_or_in name
+  {
+    $$.val = CursorStmt{Name: Name($4), All: true}
+  }
+
+opt_from_or_in:
+  FROM {}
+| IN {}
+| /* EMPTY */ {}
+
+// %Help: CLOSE - close a cursor
+// %Category: Misc
+// %Text: CLOSE { <cursorname> | ALL }
+// %SeeAlso: DECLARE, FETCH, MOVE
+close_cursor_stmt:
+  CLOSE name
+  {
+    $$.val = &CloseCursor{Name: Name($2)}
+  }
+| CLOSE ALL
+  {
+    $$.val = &CloseCursor{}
+  }
+| CLOSE error // SHOW HELP: CLOSE
+
+// %Help: GRANT - define access privileges and role memberships
 // %Category: Priv
 // %Text:
-// GRANT {ALL | <privileges...> } ON <targets...> TO <grantees...>
+// Grant privileges:
+//   GRANT {ALL | <privileges...> } ON <targets...> TO <grantees...>
+// Grant privileges on columns:
+//   GRANT {ALL | <privileges...> } ( <colnames...> ) ON [TABLE] <tablenames...> TO <grantees...>
+// Grant role membership:
+//   GRANT <roles...> TO <grantees...> [WITH ADMIN OPTION]
 //
 // Privileges:
 //   CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE
+//   (only SELECT, INSERT and UPDATE on columns)
 //
 // Targets:
 //   DATABASE <databasename> [, ...]
@@ -1749,15 +2036,33 @@ grant_stmt:
   {
     $$.val = &Grant{Privileges: $2.privilegeList(), Grantees: $6.nameList(), Targets: $4.targetList()}
   }
+| GRANT privileges '(' name_list ')' ON targets TO grantee_list
+  {
+    $$.val = &Grant{Privileges: $2.privilegeList(), Columns: $4.nameList(), Grantees: $9.nameList(), Targets: $7.targetList()}
+  }
+| GRANT privilege_list TO grantee_list
+  {
+    $$.val = &GrantRole{Roles: $2.nameList(), Members: $4.nameList(), AdminOption: false}
+  }
+| GRANT privilege_list TO grantee_list WITH ADMIN OPTION
+  {
+    $$.val = &GrantRole{Roles: $2.nameList(), Members: $4.nameList(), AdminOption: true}
+  }
 | GRANT error // SHOW HELP: GRANT
 
-// %Help: REVOKE - remove access privileges
+// %Help: REVOKE - remove access privileges and role memberships
 // %Category: Priv
 // %Text:
-// REVOKE {ALL | <privileges...> } ON <targets...> FROM <grantees...>
+// Revoke privileges:
+//   REVOKE {ALL | <privileges...> } ON <targets...> FROM <grantees...>
+// Revoke privileges on columns:
+//   REVOKE {ALL | <privileges...> } ( <colnames...> ) ON [TABLE] <tablenames...> FROM <grantees...>
+// Revoke role membership:
+//   REVOKE [ADMIN OPTION FOR] <roles...> FROM <grantees...>
 //
 // Privileges:
 //   CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE
+//   (only SELECT, INSERT and UPDATE on columns)
 //
 // Targets:
 //   DATABASE <databasename> [, <databasename>]...
@@ -1769,6 +2074,18 @@ revoke_stmt:
   {
     $$.val = &Revoke{Privileges: $2.privilegeList(), Grantees: $6.nameList(), Targets: $4.targetList()}
   }
+| REVOKE privileges '(' name_list ')' ON targets FROM grantee_list
+  {
+    $$.val = &Revoke{Privileges: $2.privilegeList(), Columns: $4.nameList(), Grantees: $9.nameList(), Targets: $7.targetList()}
+  }
+| REVOKE privilege_list FROM grantee_list
+  {
+    $$.val = &RevokeRole{Roles: $2.nameList(), Members: $4.nameList(), AdminOption: false}
+  }
+| REVOKE ADMIN OPTION FOR privilege_list FROM grantee_list
+  {
+    $$.val = &RevokeRole{Roles: $5.nameList(), Members: $7.nameList(), AdminOption: true}
+  }
 | REVOKE error // SHOW HELP: REVOKE
 
 targets:
@@ -1791,48 +2108,36 @@ privileges:
   {
     $$.val = privilege.List{privilege.ALL}
   }
-  | privilege_list { }
+  | privilege_list
+  {
+    privList, err := privilege.ListFromStrings($1.nameList().ToStrings())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = privList
+  }
 
+// The same list is used for privileges and for role names: whether it names
+// privileges depends on whether it is followed by ON.
 privilege_list:
   privilege
   {
-    $$.val = privilege.List{$1.privilegeType()}
+    $$.val = NameList{Name($1)}
   }
   | privilege_list ',' privilege
   {
-    $$.val = append($1.privilegeList(), $3.privilegeType())
+    $$.val = append($1.nameList(), Name($3))
   }
 
-// This list must match the list of privileges in sql/privilege/privilege.go.
+// Privileges are parsed as names, and validated against the list of
+// privileges in sql/privilege/privilege.go when used as such. CREATE, GRANT and
+// SELECT are reserved keywords, so they are listed explicitly.
 privilege:
-  CREATE
-  {
-    $$.val = privilege.CREATE
-  }
-| DROP
-  {
-    $$.val = privilege.DROP
-  }
+  name
+| CREATE
 | GRANT
-  {
-    $$.val = privilege.GRANT
-  }
 | SELECT
-  {
-    $$.val = privilege.SELECT
-  }
-| INSERT
-  {
-    $$.val = privilege.INSERT
-  }
-| DELETE
-  {
-    $$.val = privilege.DELETE
-  }
-| UPDATE
-  {
-    $$.val = privilege.UPDATE
-  }
 
 // TODO(marc): this should not be 'name', but should instead be a
 // type just for usernames.
@@ -1892,7 +2197,6 @@ set_stmt:
 | set_transaction_stmt // EXTEND WITH HELP: SET TRANSACTION
 | set_exprs_internal   { /* SKIP DOC */ }
 | use_stmt             { /* SKIP DOC */ }
-| SET LOCAL error { return unimplemented(sqllex, "set local") }
 
 // %Help: SET CLUSTER SETTING - change a cluster setting
 // %Category: Cfg
@@ -1921,10 +2225,12 @@ set_exprs_internal:
 // %Help: SET SESSION - change a session variable
 // %Category: Cfg
 // %Text:
-// SET [SESSION] <var> { TO | = } <values...>
-// SET [SESSION] TIME ZONE <tz>
+// SET [SESSION | LOCAL] <var> { TO | = } <values...>
+// SET [SESSION | LOCAL] TIME ZONE <tz>
 // SET [SESSION] CHARACTERISTICS AS TRANSACTION ISOLATION LEVEL { SNAPSHOT | SERIALIZABLE }
 //
+// With LOCAL, the change only lasts until the end of the current transaction.
+//
 // %SeeAlso: SHOW SESSION, RESET, DISCARD, SHOW, SET CLUSTER SETTING, SET TRANSACTION,
 // WEBDOCS/set-vars.html
 set_session_stmt:
@@ -1936,6 +2242,12 @@ set_session_stmt:
   {
     $$.val = $2.stmt()
   }
+| SET LOCAL set_rest_more
+  {
+    setVar := $3.stmt().(*SetVar)
+    setVar.Local = true
+    $$.val = setVar
+  }
 // Special form for pg compatibility:
 | SET SESSION CHARACTERISTICS AS TRANSACTION transaction_iso_level
   {
@@ -2110,6 +2422,7 @@ show_stmt:
 | show_queries_stmt      // EXTEND WITH HELP: SHOW QUERIES
 | show_session_stmt      // EXTEND WITH HELP: SHOW SESSION
 | show_sessions_stmt     // EXTEND WITH HELP: SHOW SESSIONS
+| show_stats_stmt        // EXTEND WITH HELP: SHOW STATISTICS
 | show_tables_stmt       // EXTEND WITH HELP: SHOW TABLES
 | show_testing_stmt
 | show_trace_stmt        // EXTEND WITH HELP: SHOW TRACE
@@ -2318,6 +2631,17 @@ show_sessions_stmt:
     $$.val = &ShowSessions{Cluster: false}
   }
 
+// %Help: SHOW STATISTICS - display table statistics
+// %Category: Misc
+// %Text: SHOW STATISTICS FOR TABLE <table_name>
+// %SeeAlso: CREATE STATISTICS
+show_stats_stmt:
+  SHOW STATISTICS FOR TABLE qualified_name
+  {
+    $$.val = &ShowTableStats{Table: $5.normalizableTableName()}
+  }
+| SHOW STATISTICS error // SHOW HELP: SHOW STATISTICS
+
 // %Help: SHOW TABLES - list tables
 // %Category: DDL
 // %Text: SHOW TABLES [FROM <databasename>]
@@ -2447,6 +2771,7 @@ pause_stmt:
 //    <name> <type> [<qualifiers...>]
 //    [UNIQUE] INDEX [<name>] ( <colname> [ASC | DESC] [, ...] )
 //                            [STORING ( <colnames...> )] [<interleave>]
+//    INVERTED INDEX [<name>] ( <colname> )
 //    FAMILY [<name>] ( <colnames...> )
 //    [CONSTRAINT <name>] <constraint>
 //
@@ -2461,6 +2786,7 @@ pause_stmt:
 //   FAMILY <familyname>, CREATE [IF NOT EXISTS] FAMILY [<familyname>]
 //   REFERENCES <tablename> [( <colnames...> )]
 //   COLLATE <collationname>
+//   AS ( <expr> ) STORED
 //
 // Interleave clause:
 //    INTERLEAVE IN PARENT <tablename> ( <colnames...> ) [CASCADE | RESTRICT]
@@ -2637,8 +2963,13 @@ col_qualification_elem:
     $$.val = &ColumnFKConstraint{
       Table: $2.normalizableTableName(),
       Col: Name($3),
+      Actions: $5.referenceActions(),
     }
  }
+| AS '(' a_expr ')' STORED
+  {
+    $$.val = &ColumnComputedDef{Expr: $3.expr()}
+  }
 
 index_def:
   INDEX opt_name '(' index_params ')' opt_storing opt_interleave
@@ -2661,6 +2992,14 @@ index_def:
       },
     }
   }
+| INVERTED INDEX opt_name '(' index_params ')'
+  {
+    $$.val = &IndexTableDef{
+      Name:     Name($3),
+      Columns:  $5.idxElems(),
+      Inverted: true,
+    }
+  }
 
 family_def:
   FAMILY opt_name '(' name_list ')'
@@ -2718,6 +3057,7 @@ constraint_elem:
       Table: $7.normalizableTableName(),
       FromCols: $4.nameList(),
       ToCols: $8.nameList(),
+      Actions: $10.referenceActions(),
     }
   }
 
@@ -2764,26 +3104,60 @@ key_match:
 // simplicity of parsing, and then break them down again in the calling
 // production.
 key_actions:
-  key_update {}
-| key_delete {}
-| key_update key_delete {}
-| key_delete key_update {}
-| /* EMPTY */ {}
+  key_update
+  {
+    $$.val = ReferenceActions{Update: $1.referenceAction()}
+  }
+| key_delete
+  {
+    $$.val = ReferenceActions{Delete: $1.referenceAction()}
+  }
+| key_update key_delete
+  {
+    $$.val = ReferenceActions{Update: $1.referenceAction(), Delete: $2.referenceAction()}
+  }
+| key_delete key_update
+  {
+    $$.val = ReferenceActions{Delete: $1.referenceAction(), Update: $2.referenceAction()}
+  }
+| /* EMPTY */
+  {
+    $$.val = ReferenceActions{}
+  }
 
 key_update:
-  ON UPDATE key_action {}
+  ON UPDATE key_action
+  {
+    $$.val = $3.referenceAction()
+  }
 
 key_delete:
-  ON DELETE key_action {}
+  ON DELETE key_action
+  {
+    $$.val = $3.referenceAction()
+  }
 
 key_action:
-  NO ACTION { return unimplemented(sqllex, "no action") }
-// RESTRICT is currently the default and only supported ON DELETE/UPDATE
-// behavior and thus needs no special handling.
-| RESTRICT {}
-| CASCADE { return unimplemented(sqllex, "action cascade") }
-| SET NULL { return unimplemented(sqllex, "action set null") }
-| SET DEFAULT { return unimplemented(sqllex, "action set default") }
+  NO ACTION
+  {
+    $$.val = NoAction
+  }
+| RESTRICT
+  {
+    $$.val = Restrict
+  }
+| CASCADE
+  {
+    $$.val = Cascade
+  }
+| SET NULL
+  {
+    $$.val = SetNull
+  }
+| SET DEFAULT
+  {
+    $$.val = SetDefault
+  }
 
 numeric_only:
   FCONST
@@ -2821,6 +3195,74 @@ create_user_stmt:
   }
 | CREATE USER error // SHOW HELP: CREATE USER
 
+// %Help: CREATE ROLE - define a new role
+// %Category: Priv
+// %Text: CREATE ROLE <name>
+// %SeeAlso: DROP ROLE, GRANT, REVOKE
+create_role_stmt:
+  CREATE ROLE name
+  {
+    $$.val = &CreateRole{Name: Name($3)}
+  }
+| CREATE ROLE error // SHOW HELP: CREATE ROLE
+
+// %Help: CREATE POLICY - define a row-level security policy
+// %Category: Priv
+// %Text:
+// CREATE POLICY <name> ON <tablename>
+//   [TO <roles...>]
+//   [USING ( <expr> )]
+//   [WITH CHECK ( <expr> )]
+//
+// Once row-level security is enabled on the table, the users other than root
+// only see the rows satisfying the USING expression of one of the policies
+// applying to them, and can only write rows satisfying its WITH CHECK
+// expression, or its USING expression if it has none.
+//
+// %SeeAlso: DROP POLICY, ALTER TABLE
+create_policy_stmt:
+  CREATE POLICY name ON qualified_name opt_policy_roles opt_policy_using opt_policy_check
+  {
+    $$.val = &CreatePolicy{
+      Name: Name($3),
+      Table: $5.normalizableTableName(),
+      Roles: $6.nameList(),
+      Using: $7.expr(),
+      Check: $8.expr(),
+    }
+  }
+| CREATE POLICY error // SHOW HELP: CREATE POLICY
+
+opt_policy_roles:
+  TO grantee_list
+  {
+    $$.val = $2.nameList()
+  }
+| /* EMPTY */
+  {
+    $$.val = NameList(nil)
+  }
+
+opt_policy_using:
+  USING '(' a_expr ')'
+  {
+    $$.val = $3.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = Expr(nil)
+  }
+
+opt_policy_check:
+  WITH CHECK '(' a_expr ')'
+  {
+    $$.val = $4.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = Expr(nil)
+  }
+
 opt_password:
   opt_with PASSWORD SCONST
   {
@@ -2848,12 +3290,87 @@ create_view_stmt:
 
 // TODO(a-robinson): CREATE OR REPLACE VIEW support (#2971).
 
+// %Help: CREATE SEQUENCE - create a new sequence
+// %Category: DDL
+// %Text:
+// CREATE SEQUENCE [IF NOT EXISTS] <seqname>
+//   [INCREMENT <increment>]
+//   [MINVALUE <minvalue> | NO MINVALUE]
+//   [MAXVALUE <maxvalue> | NO MAXVALUE]
+//   [START [WITH] <start>]
+//   [CACHE <cache>]
+//   [NO CYCLE]
+// %SeeAlso: CREATE TABLE
+create_sequence_stmt:
+  CREATE SEQUENCE any_name opt_sequence_option_list
+  {
+    $$.val = &CreateSequence{Name: $3.normalizableTableName(), Options: $4.seqOpts()}
+  }
+| CREATE SEQUENCE IF NOT EXISTS any_name opt_sequence_option_list
+  {
+    $$.val = &CreateSequence{Name: $6.normalizableTableName(), Options: $7.seqOpts(), IfNotExists: true}
+  }
+| CREATE SEQUENCE error // SHOW HELP: CREATE SEQUENCE
+
+// %Help: CREATE STATISTICS - create a new table statistic
+// %Category: Misc
+// %Text:
+// CREATE STATISTICS <statisticname>
+//   ON <colname> [, ...]
+//   FROM <tablename>
+// %SeeAlso: SHOW STATISTICS
+create_stats_stmt:
+  CREATE STATISTICS name ON name_list FROM qualified_name
+  {
+    $$.val = &CreateStats{
+      Name: Name($3),
+      ColumnNames: $5.nameList(),
+      Table: $7.normalizableTableName(),
+    }
+  }
+| CREATE STATISTICS error // SHOW HELP: CREATE STATISTICS
+
+opt_sequence_option_list:
+  sequence_option_list
+| /* EMPTY */ { $$.val = []SequenceOption(nil) }
+
+sequence_option_list:
+  sequence_option_elem                       { $$.val = []SequenceOption{$1.seqOpt()} }
+| sequence_option_list sequence_option_elem  { $$.val = append($1.seqOpts(), $2.seqOpt()) }
+
+sequence_option_elem:
+  CYCLE                        { return unimplemented(sqllex, "sequence cycle") }
+| NO CYCLE                     { $$.val = SequenceOption{Name: SeqOptNoCycle} }
+| CACHE signed_iconst64        { x := $2.int64()
+                                 $$.val = SequenceOption{Name: SeqOptCache, IntVal: &x} }
+| INCREMENT signed_iconst64    { x := $2.int64()
+                                 $$.val = SequenceOption{Name: SeqOptIncrement, IntVal: &x} }
+| INCREMENT BY signed_iconst64 { x := $3.int64()
+                                 $$.val = SequenceOption{Name: SeqOptIncrement, IntVal: &x, OptionalWord: true} }
+| MINVALUE signed_iconst64     { x := $2.int64()
+                                 $$.val = SequenceOption{Name: SeqOptMinValue, IntVal: &x} }
+| NO MINVALUE                  { $$.val = SequenceOption{Name: SeqOptMinValue} }
+| MAXVALUE signed_iconst64     { x := $2.int64()
+                                 $$.val = SequenceOption{Name: SeqOptMaxValue, IntVal: &x} }
+| NO MAXVALUE                  { $$.val = SequenceOption{Name: SeqOptMaxValue} }
+| START signed_iconst64        { x := $2.int64()
+                                 $$.val = SequenceOption{Name: SeqOptStart, IntVal: &x} }
+| START WITH signed_iconst64   { x := $3.int64()
+                                 $$.val = SequenceOption{Name: SeqOptStart, IntVal: &x, OptionalWord: true} }
+
 // %Help: CREATE INDEX - create a new index
 // %Category: DDL
 // %Text:
 // CREATE [UNIQUE] INDEX [IF NOT EXISTS] [<idxname>]
-//        ON <tablename> ( <colname> [ASC | DESC] [, ...] )
-//        [STORING ( <colnames...> )] [<interleave>]
+//        ON <tablename> ( <elem> [ASC | DESC] [, ...] )
+//        [STORING ( <colnames...> )] [<interleave>] [WHERE <predicate>]
+// CREATE INVERTED INDEX [IF NOT EXISTS] [<idxname>]
+//        ON <tablename> ( <colname> ) [WHERE <predicate>]
+//
+// Index elements:
+//    <colname>
+//    <funcname> ( <args...> )
+//    ( <expr> )
 //
 // Interleave clause:
 //    INTERLEAVE IN PARENT <tablename> ( <colnames...> ) [CASCADE | RESTRICT]
@@ -2861,7 +3378,7 @@ create_view_stmt:
 // %SeeAlso: CREATE TABLE, SHOW INDEXES, SHOW CREATE INDEX,
 // WEBDOCS/create-index.html
 create_index_stmt:
-  CREATE opt_unique INDEX opt_name ON qualified_name '(' index_params ')' opt_storing opt_interleave
+  CREATE opt_unique INDEX opt_name ON qualified_name '(' index_params ')' opt_storing opt_interleave where_clause
   {
     $$.val = &CreateIndex{
       Name:    Name($4),
@@ -2870,9 +3387,10 @@ create_index_stmt:
       Columns: $8.idxElems(),
       Storing: $10.nameList(),
       Interleave: $11.interleave(),
+      Predicate: $12.expr(),
     }
   }
-| CREATE opt_unique INDEX IF NOT EXISTS name ON qualified_name '(' index_params ')' opt_storing opt_interleave
+| CREATE opt_unique INDEX IF NOT EXISTS name ON qualified_name '(' index_params ')' opt_storing opt_interleave where_clause
   {
     $$.val = &CreateIndex{
       Name:        Name($7),
@@ -2882,6 +3400,28 @@ create_index_stmt:
       Columns:     $11.idxElems(),
       Storing:     $13.nameList(),
       Interleave: $14.interleave(),
+      Predicate:   $15.expr(),
+    }
+  }
+| CREATE INVERTED INDEX opt_name ON qualified_name '(' index_params ')' where_clause
+  {
+    $$.val = &CreateIndex{
+      Name:      Name($4),
+      Table:     $6.normalizableTableName(),
+      Inverted:  true,
+      Columns:   $8.idxElems(),
+      Predicate: $10.expr(),
+    }
+  }
+| CREATE INVERTED INDEX IF NOT EXISTS name ON qualified_name '(' index_params ')' where_clause
+  {
+    $$.val = &CreateIndex{
+      Name:        Name($7),
+      Table:       $9.normalizableTableName(),
+      Inverted:    true,
+      IfNotExists: true,
+      Columns:     $11.idxElems(),
+      Predicate:   $13.expr(),
     }
   }
 | CREATE opt_unique INDEX error // SHOW HELP: CREATE INDEX
@@ -2914,8 +3454,14 @@ index_elem:
   {
     $$.val = IndexElem{Column: Name($1), Direction: $3.dir()}
   }
-| func_expr_windowless opt_collate opt_asc_desc { return unimplemented(sqllex, "index_elem func expr") }
-| '(' a_expr ')' opt_collate opt_asc_desc { return unimplemented(sqllex, "index_elem a_expr") }
+| func_expr_windowless opt_collate opt_asc_desc
+  {
+    $$.val = IndexElem{Expr: $1.expr(), Direction: $3.dir()}
+  }
+| '(' a_expr ')' opt_collate opt_asc_desc
+  {
+    $$.val = IndexElem{Expr: $2.expr(), Direction: $5.dir()}
+  }
 
 opt_collate:
   COLLATE unrestricted_name { return unimplementedWithIssue(sqllex, 16619) }
@@ -2973,6 +3519,16 @@ alter_rename_view_stmt:
     $$.val = &RenameTable{Name: $5.normalizableTableName(), NewName: $8.normalizableTableName(), IfExists: true, IsView: true}
   }
 
+alter_rename_sequence_stmt:
+  ALTER SEQUENCE relation_expr RENAME TO qualified_name
+  {
+    $$.val = &RenameTable{Name: $3.normalizableTableName(), NewName: $6.normalizableTableName(), IfExists: false, IsSequence: true}
+  }
+| ALTER SEQUENCE IF EXISTS relation_expr RENAME TO qualified_name
+  {
+    $$.val = &RenameTable{Name: $5.normalizableTableName(), NewName: $8.normalizableTableName(), IfExists: true, IsSequence: true}
+  }
+
 alter_rename_index_stmt:
   ALTER INDEX table_name_with_index RENAME TO name
   {
@@ -2997,9 +3553,11 @@ opt_set_data:
   SET DATA {}
 | /* EMPTY */ {}
 
-// %Help: RELEASE - complete a retryable block
+// %Help: RELEASE - destroy a savepoint
 // %Category: Txn
-// %Text: RELEASE [SAVEPOINT] cockroach_restart
+// %Text: RELEASE [SAVEPOINT] <savepoint name>
+//
+// Releasing the savepoint cockroach_restart completes a retryable block.
 // %SeeAlso: SAVEPOINT, WEBDOCS/savepoint.html
 release_stmt:
   RELEASE savepoint_name
@@ -3019,9 +3577,11 @@ resume_stmt:
   }
 | RESUME error // SHOW HELP: RESUME JOB
 
-// %Help: SAVEPOINT - start a retryable block
+// %Help: SAVEPOINT - define a new savepoint
 // %Category: Txn
-// %Text: SAVEPOINT cockroach_restart
+// %Text: SAVEPOINT <savepoint name>
+//
+// The savepoint cockroach_restart starts a retryable block.
 // %SeeAlso: RELEASE, WEBDOCS/savepoint.html
 savepoint_stmt:
   SAVEPOINT name
@@ -3079,7 +3639,7 @@ commit_stmt:
 
 // %Help: ROLLBACK - abort the current transaction
 // %Category: Txn
-// %Text: ROLLBACK [TRANSACTION] [TO [SAVEPOINT] cockroach_restart]
+// %Text: ROLLBACK [TRANSACTION] [TO [SAVEPOINT] <savepoint name>]
 // %SeeAlso: BEGIN, COMMIT, SAVEPOINT, WEBDOCS/rollback-transaction.html
 rollback_stmt:
   ROLLBACK opt_to_savepoint
@@ -3277,12 +3837,14 @@ insert_stmt:
   opt_with_clause INSERT INTO insert_target insert_rest returning_clause
   {
     $$.val = $5.stmt()
+    $$.val.(*Insert).With = $1.with()
     $$.val.(*Insert).Table = $4.tblExpr()
     $$.val.(*Insert).Returning = $6.retClause()
   }
 | opt_with_clause INSERT INTO insert_target insert_rest on_conflict returning_clause
   {
     $$.val = $5.stmt()
+    $$.val.(*Insert).With = $1.with()
     $$.val.(*Insert).Table = $4.tblExpr()
     $$.val.(*Insert).OnConflict = $6.onConflict()
     $$.val.(*Insert).Returning = $7.retClause()
@@ -3300,6 +3862,7 @@ upsert_stmt:
   opt_with_clause UPSERT INTO insert_target insert_rest returning_clause
   {
     $$.val = $5.stmt()
+    $$.val.(*Insert).With = $1.with()
     $$.val.(*Insert).Table = $4.tblExpr()
     $$.val.(*Insert).OnConflict = &OnConflict{}
     $$.val.(*Insert).Returning = $6.retClause()
@@ -3379,7 +3942,13 @@ update_stmt:
   opt_with_clause UPDATE relation_expr_opt_alias
     SET set_clause_list update_from_clause where_clause returning_clause
   {
-    $$.val = &Update{Table: $3.tblExpr(), Exprs: $5.updateExprs(), Where: newWhere(astWhere, $7.expr()), Returning: $8.retClause()}
+    $$.val = &Update{
+      With: $1.with(),
+      Table: $3.tblExpr(),
+      Exprs: $5.updateExprs(),
+      Where: newWhere(astWhere, $7.expr()),
+      Returning: $8.retClause(),
+    }
   }
 | opt_with_clause UPDATE error // SHOW HELP: UPDATE
 
@@ -3501,15 +4070,15 @@ select_no_parens:
   }
 | with_clause select_clause
   {
-    $$.val = &Select{Select: $2.selectStmt()}
+    $$.val = &Select{With: $1.with(), Select: $2.selectStmt()}
   }
 | with_clause select_clause sort_clause
   {
-    $$.val = &Select{Select: $2.selectStmt(), OrderBy: $3.orderBy()}
+    $$.val = &Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy()}
   }
 | with_clause select_clause opt_sort_clause select_limit
   {
-    $$.val = &Select{Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $4.limit()}
+    $$.val = &Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $4.limit()}
   }
 
 select_clause:
@@ -3653,24 +4222,51 @@ table_clause:
 //
 // Recognizing WITH_LA here allows a CTE to be named TIME or ORDINALITY.
 with_clause:
-  WITH cte_list { return unimplemented(sqllex, "with cte_list") }
-| WITH_LA cte_list { return unimplemented(sqllex, "with cte_list") }
-| WITH RECURSIVE cte_list { return unimplemented(sqllex, "with cte_list") }
+  WITH cte_list
+  {
+    $$.val = &With{CTEList: $2.ctes()}
+  }
+| WITH_LA cte_list
+  {
+    $$.val = &With{CTEList: $2.ctes()}
+  }
+| WITH RECURSIVE cte_list
+  {
+    $$.val = &With{Recursive: true, CTEList: $3.ctes()}
+  }
 
 cte_list:
-  common_table_expr { return unimplemented(sqllex, "cte_list") }
-| cte_list ',' common_table_expr { return unimplemented(sqllex, "cte_list") }
+  common_table_expr
+  {
+    $$.val = []*CTE{$1.cte()}
+  }
+| cte_list ',' common_table_expr
+  {
+    $$.val = append($1.ctes(), $3.cte())
+  }
 
 common_table_expr:
-  name opt_name_list AS '(' preparable_stmt ')' { return unimplemented(sqllex, "cte") }
+  name opt_name_list AS '(' preparable_stmt ')'
+  {
+    $$.val = &CTE{
+      Name: AliasClause{Alias: Name($1), Cols: $2.nameList()},
+      Stmt: $5.stmt(),
+    }
+  }
 
 opt_with:
   WITH {}
 | /* EMPTY */ {}
 
 opt_with_clause:
-  with_clause { return unimplemented(sqllex, "with_clause") }
-| /* EMPTY */ {}
+  with_clause
+  {
+    $$.val = $1.with()
+  }
+| /* EMPTY */
+  {
+    $$.val = nil
+  }
 
 opt_table:
   TABLE {}
@@ -4373,6 +4969,14 @@ simple_typename:
   {
     $$.val = ipnetColTypeINet
   }
+| JSON
+  {
+    $$.val = jsonColTypeJSON
+  }
+| JSONB
+  {
+    $$.val = jsonColTypeJSONB
+  }
 | BIGSERIAL
   {
     $$.val = intColTypeBigSerial
@@ -4683,6 +5287,22 @@ const_datetime:
   {
     $$.val = timestampTzColTypeTimestampWithTZ
   }
+| TIME
+  {
+    $$.val = timeColTypeTime
+  }
+| TIME WITHOUT TIME ZONE
+  {
+    $$.val = timeColTypeTime
+  }
+| TIMETZ
+  {
+    $$.val = timeTZColTypeTimeWithTZ
+  }
+| TIME WITH_LA TIME ZONE
+  {
+    $$.val = timeTZColTypeTimeWithTZ
+  }
 
 const_interval:
   INTERVAL {
@@ -4865,6 +5485,26 @@ a_expr:
   {
     $$.val = &BinaryExpr{Operator: Concat, Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr FETCHVAL a_expr
+  {
+    $$.val = &BinaryExpr{Operator: JSONFetchVal, Left: $1.expr(), Right: $3.expr()}
+  }
+| a_expr FETCHTEXT a_expr
+  {
+    $$.val = &BinaryExpr{Operator: JSONFetchText, Left: $1.expr(), Right: $3.expr()}
+  }
+| a_expr CONTAINS a_expr
+  {
+    $$.val = &ComparisonExpr{Operator: Contains, Left: $1.expr(), Right: $3.expr()}
+  }
+| a_expr CONTAINED_BY a_expr
+  {
+    $$.val = &ComparisonExpr{Operator: ContainedBy, Left: $1.expr(), Right: $3.expr()}
+  }
+| a_expr '?' a_expr
+  {
+    $$.val = &ComparisonExpr{Operator: JSONExists, Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr LSHIFT a_expr
   {
     $$.val = &BinaryExpr{Operator: LShift, Left: $1.expr(), Right: $3.expr()}
@@ -5126,6 +5766,26 @@ b_expr:
   {
     $$.val = &BinaryExpr{Operator: Concat, Left: $1.expr(), Right: $3.expr()}
   }
+| b_expr FETCHVAL b_expr
+  {
+    $$.val = &BinaryExpr{Operator: JSONFetchVal, Left: $1.expr(), Right: $3.expr()}
+  }
+| b_expr FETCHTEXT b_expr
+  {
+    $$.val = &BinaryExpr{Operator: JSONFetchText, Left: $1.expr(), Right: $3.expr()}
+  }
+| b_expr CONTAINS b_expr
+  {
+    $$.val = &ComparisonExpr{Operator: Contains, Left: $1.expr(), Right: $3.expr()}
+  }
+| b_expr CONTAINED_BY b_expr
+  {
+    $$.val = &ComparisonExpr{Operator: ContainedBy, Left: $1.expr(), Right: $3.expr()}
+  }
+| b_expr '?' b_expr
+  {
+    $$.val = &ComparisonExpr{Operator: JSONExists, Left: $1.expr(), Right: $3.expr()}
+  }
 | b_expr LSHIFT b_expr
   {
     $$.val = &BinaryExpr{Operator: LShift, Left: $1.expr(), Right: $3.expr()}
@@ -5323,7 +5983,10 @@ func_expr_common_subexpr:
     $$.val = &FuncExpr{Func: wrapFunction($1)}
   }
 | CURRENT_TIMESTAMP '(' error { return helpWithFunction(sqllex, ResolvableFunctionReference{UnresolvedName{Name($1)}}) }
-| CURRENT_ROLE { return unimplemented(sqllex, "current role") }
+| CURRENT_ROLE
+  {
+    $$.val = &FuncExpr{Func: wrapFunction("current_user")}
+  }
 | CURRENT_USER
   {
     $$.val = &FuncExpr{Func: wrapFunction($1)}
@@ -5483,6 +6146,7 @@ window_specification:
       RefName: Name($2),
       Partitions: $3.exprs(),
       OrderBy: $4.orderBy(),
+      Frame: $5.windowFrame(),
     }
   }
 
@@ -5511,29 +6175,96 @@ opt_partition_clause:
     $$.val = Exprs(nil)
   }
 
-// For frame clauses, we return a WindowDef, but only some fields are used:
-// frameOptions, startOffset, and endOffset.
-//
 // This is only a subset of the full SQL:2008 frame_clause grammar. We don't
 // support <window frame exclusion> yet.
 opt_frame_clause:
-  RANGE frame_extent { return unimplemented(sqllex, "frame range") }
-| ROWS frame_extent { return unimplemented(sqllex, "frame rows") }
-| /* EMPTY */ {}
+  RANGE frame_extent
+  {
+    $$.val = &WindowFrame{
+      Mode: RangeFrame,
+      Bounds: $2.windowFrameBounds(),
+    }
+  }
+| ROWS frame_extent
+  {
+    $$.val = &WindowFrame{
+      Mode: RowsFrame,
+      Bounds: $2.windowFrameBounds(),
+    }
+  }
+| /* EMPTY */
+  {
+    $$.val = (*WindowFrame)(nil)
+  }
 
 frame_extent:
-  frame_bound { return unimplemented(sqllex, "frame_extent") }
-| BETWEEN frame_bound AND frame_bound { return unimplemented(sqllex, "frame_extent") }
+  frame_bound
+  {
+    startBound := $1.windowFrameBound()
+    switch {
+    case startBound.BoundType == UnboundedFollowing:
+      sqllex.Error("frame start cannot be UNBOUNDED FOLLOWING")
+      return 1
+    case startBound.BoundType == OffsetFollowing:
+      sqllex.Error("frame starting from following row cannot end with current row")
+      return 1
+    }
+    $$.val = WindowFrameBounds{StartBound: startBound}
+  }
+| BETWEEN frame_bound AND frame_bound
+  {
+    startBound := $2.windowFrameBound()
+    endBound := $4.windowFrameBound()
+    switch {
+    case startBound.BoundType == UnboundedFollowing:
+      sqllex.Error("frame start cannot be UNBOUNDED FOLLOWING")
+      return 1
+    case endBound.BoundType == UnboundedPreceding:
+      sqllex.Error("frame end cannot be UNBOUNDED PRECEDING")
+      return 1
+    case startBound.BoundType == CurrentRow && endBound.BoundType == OffsetPreceding:
+      sqllex.Error("frame starting from current row cannot have preceding rows")
+      return 1
+    case startBound.BoundType == OffsetFollowing && endBound.BoundType == OffsetPreceding:
+      sqllex.Error("frame starting from following row cannot have preceding rows")
+      return 1
+    case startBound.BoundType == OffsetFollowing && endBound.BoundType == CurrentRow:
+      sqllex.Error("frame starting from following row cannot have preceding rows")
+      return 1
+    }
+    $$.val = WindowFrameBounds{StartBound: startBound, EndBound: endBound}
+  }
 
 // This is used for both frame start and frame end, with output set up on the
 // assumption it's frame start; the frame_extent productions must reject
 // invalid cases.
 frame_bound:
-  UNBOUNDED PRECEDING { return unimplemented(sqllex, "frame_bound") }
-| UNBOUNDED FOLLOWING { return unimplemented(sqllex, "frame_bound") }
-| CURRENT ROW { return unimplemented(sqllex, "frame_bound") }
-| a_expr PRECEDING { return unimplemented(sqllex, "frame_bound") }
-| a_expr FOLLOWING { return unimplemented(sqllex, "frame_bound") }
+  UNBOUNDED PRECEDING
+  {
+    $$.val = &WindowFrameBound{BoundType: UnboundedPreceding}
+  }
+| UNBOUNDED FOLLOWING
+  {
+    $$.val = &WindowFrameBound{BoundType: UnboundedFollowing}
+  }
+| CURRENT ROW
+  {
+    $$.val = &WindowFrameBound{BoundType: CurrentRow}
+  }
+| a_expr PRECEDING
+  {
+    $$.val = &WindowFrameBound{
+      OffsetExpr: $1.expr(),
+      BoundType: OffsetPreceding,
+    }
+  }
+| a_expr FOLLOWING
+  {
+    $$.val = &WindowFrameBound{
+      OffsetExpr: $1.expr(),
+      BoundType: OffsetFollowing,
+    }
+  }
 
 // Supporting nonterminals for expressions.
 
@@ -6130,6 +6861,15 @@ signed_iconst:
     $$.val = &NumVal{Value: constant.UnaryOp(token.SUB, $2.numVal().Value, 0)}
   }
 
+// signed_iconst64 is a signed_iconst that must fit in an int64.
+signed_iconst64:
+  signed_iconst
+  {
+    val, err := $1.numVal().AsInt64()
+    if err != nil { sqllex.Error(err.Error()); return 1 }
+    $$.val = val
+  }
+
 interval:
   const_interval SCONST opt_interval
   {
@@ -6214,14 +6954,17 @@ unrestricted_name:
 unreserved_keyword:
   ACTION
 | ADD
+| ADMIN
 | ALTER
 | AT
 | BACKUP
 | BEGIN
 | BLOB
 | BY
+| CACHE
 | CANCEL
 | CASCADE
+| CLOSE
 | CLUSTER
 | COLUMNS
 | COMMIT
@@ -6233,16 +6976,20 @@ unreserved_keyword:
 | CSV
 | CUBE
 | CURRENT
+| CURSOR
 | CYCLE
 | DATA
 | DATABASE
 | DATABASES
 | DAY
 | DEALLOCATE
+| DECLARE
 | DELETE
+| DISABLE
 | DISCARD
 | DOUBLE
 | DROP
+| ENABLE
 | ENCODING
 | EXECUTE
 | EXPERIMENTAL_FINGERPRINTS
@@ -6251,15 +6998,19 @@ unreserved_keyword:
 | FIRST
 | FOLLOWING
 | FORCE_INDEX
+| FORWARD
 | GRANTS
 | HIGH
+| HOLD
 | HOUR
 | IMPORT
+| INCREMENT
 | INCREMENTAL
 | INDEXES
 | INSERT
 | INT2VECTOR
 | INTERLEAVE
+| INVERTED
 | ISOLATION
 | JOB
 | JOBS
@@ -6272,8 +7023,11 @@ unreserved_keyword:
 | LOCAL
 | LOW
 | MATCH
+| MAXVALUE
 | MINUTE
+| MINVALUE
 | MONTH
+| MOVE
 | NAMES
 | NAN
 | NEXT
@@ -6284,6 +7038,7 @@ unreserved_keyword:
 | OF
 | OFF
 | OID
+| OPTION
 | OPTIONS
 | ORDINALITY
 | OVER
@@ -6293,6 +7048,7 @@ unreserved_keyword:
 | PASSWORD
 | PAUSE
 | PLANS
+| POLICY
 | PRECEDING
 | PREPARE
 | PRIORITY
@@ -6315,17 +7071,22 @@ unreserved_keyword:
 | RESTRICT
 | RESUME
 | REVOKE
+| ROLE
 | ROLLBACK
 | ROLLUP
 | ROWS
 | SETTING
 | SETTINGS
+| STATISTICS
 | STATUS
 | SAVEPOINT
 | SCATTER
+| SCROLL
 | SEARCH
 | SECOND
+| SECURITY
 | SERIALIZABLE
+| SEQUENCE
 | SEQUENCES
 | SESSION
 | SESSIONS
@@ -6337,6 +7098,7 @@ unreserved_keyword:
 | START
 | STDIN
 | STORE
+| STORED
 | STORING
 | STRICT
 | SPLIT
@@ -6413,6 +7175,8 @@ col_name_keyword:
 | INT64
 | INTEGER
 | INTERVAL
+| JSON
+| JSONB
 | LEAST
 | NAME
 | NULLIF
@@ -6429,6 +7193,7 @@ col_name_keyword:
 | STRING
 | SUBSTRING
 | TIME
+| TIMETZ
 | TIMESTAMP
 | TIMESTAMPTZ
 | TREAT
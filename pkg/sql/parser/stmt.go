@@ -101,6 +101,12 @@ func (*AlterTable) StatementTag() string { return "ALTER TABLE" }
 
 func (*AlterTable) hiddenFromShowQueries() {}
 
+// StatementType implements the Statement interface.
+func (*AlterSequence) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*AlterSequence) StatementTag() string { return "ALTER SEQUENCE" }
+
 // StatementType implements the Statement interface.
 func (*Backup) StatementType() StatementType { return Rows }
 
@@ -129,6 +135,18 @@ func (*CancelQuery) StatementType() StatementType { return Ack }
 // StatementTag returns a short string identifying the type of statement.
 func (*CancelQuery) StatementTag() string { return "CANCEL QUERY" }
 
+// StatementType implements the Statement interface.
+func (*CloseCursor) StatementType() StatementType { return Ack }
+
+// StatementTag returns a short string identifying the type of statement.
+func (n *CloseCursor) StatementTag() string {
+	// Postgres distinguishes the command tags for these two cases of Close statements.
+	if n.Name == "" {
+		return "CLOSE CURSOR ALL"
+	}
+	return "CLOSE CURSOR"
+}
+
 // StatementType implements the Statement interface.
 func (*CommitTransaction) StatementType() StatementType { return Ack }
 
@@ -166,6 +184,18 @@ func (n *CreateTable) StatementTag() string {
 	return "CREATE TABLE"
 }
 
+// StatementType implements the Statement interface.
+func (*CreatePolicy) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreatePolicy) StatementTag() string { return "CREATE POLICY" }
+
+// StatementType implements the Statement interface.
+func (*CreateRole) StatementType() StatementType { return Ack }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateRole) StatementTag() string { return "CREATE ROLE" }
+
 // StatementType implements the Statement interface.
 func (*CreateUser) StatementType() StatementType { return Ack }
 
@@ -178,6 +208,18 @@ func (*CreateView) StatementType() StatementType { return DDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*CreateView) StatementTag() string { return "CREATE VIEW" }
 
+// StatementType implements the Statement interface.
+func (*CreateSequence) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateSequence) StatementTag() string { return "CREATE SEQUENCE" }
+
+// StatementType implements the Statement interface.
+func (*CreateStats) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateStats) StatementTag() string { return "CREATE STATISTICS" }
+
 // StatementType implements the Statement interface.
 func (*Deallocate) StatementType() StatementType { return Ack }
 
@@ -192,6 +234,12 @@ func (n *Deallocate) StatementTag() string {
 
 func (*Deallocate) hiddenFromStats() {}
 
+// StatementType implements the Statement interface.
+func (*DeclareCursor) StatementType() StatementType { return Ack }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DeclareCursor) StatementTag() string { return "DECLARE CURSOR" }
+
 // StatementType implements the Statement interface.
 func (*Discard) StatementType() StatementType { return Ack }
 
@@ -228,6 +276,24 @@ func (*DropView) StatementType() StatementType { return DDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*DropView) StatementTag() string { return "DROP VIEW" }
 
+// StatementType implements the Statement interface.
+func (*DropSequence) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropSequence) StatementTag() string { return "DROP SEQUENCE" }
+
+// StatementType implements the Statement interface.
+func (*DropPolicy) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropPolicy) StatementTag() string { return "DROP POLICY" }
+
+// StatementType implements the Statement interface.
+func (*DropRole) StatementType() StatementType { return RowsAffected }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropRole) StatementTag() string { return "DROP ROLE" }
+
 // StatementType implements the Statement interface.
 func (*DropUser) StatementType() StatementType { return RowsAffected }
 
@@ -248,6 +314,12 @@ func (*Explain) StatementTag() string { return "EXPLAIN" }
 
 func (*Explain) hiddenFromStats() {}
 
+// StatementType implements the Statement interface.
+func (*FetchCursor) StatementType() StatementType { return Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*FetchCursor) StatementTag() string { return "FETCH" }
+
 // StatementType implements the Statement interface.
 func (*Grant) StatementType() StatementType { return DDL }
 
@@ -256,6 +328,14 @@ func (*Grant) StatementTag() string { return "GRANT" }
 
 func (*Grant) hiddenFromStats() {}
 
+// StatementType implements the Statement interface.
+func (*GrantRole) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*GrantRole) StatementTag() string { return "GRANT" }
+
+func (*GrantRole) hiddenFromStats() {}
+
 // StatementType implements the Statement interface.
 func (n *Insert) StatementType() StatementType { return n.Returning.statementType() }
 
@@ -268,6 +348,12 @@ func (n *Import) StatementType() StatementType { return Rows }
 // StatementTag returns a short string identifying the type of statement.
 func (*Import) StatementTag() string { return "IMPORT" }
 
+// StatementType implements the Statement interface.
+func (*MoveCursor) StatementType() StatementType { return RowsAffected }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*MoveCursor) StatementTag() string { return "MOVE" }
+
 // StatementType implements the Statement interface.
 func (*ParenSelect) StatementType() StatementType { return Rows }
 
@@ -353,6 +439,14 @@ func (*Revoke) StatementTag() string { return "REVOKE" }
 
 func (*Revoke) hiddenFromStats() {}
 
+// StatementType implements the Statement interface.
+func (*RevokeRole) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*RevokeRole) StatementTag() string { return "REVOKE" }
+
+func (*RevokeRole) hiddenFromStats() {}
+
 // StatementType implements the Statement interface.
 func (*RollbackToSavepoint) StatementType() StatementType { return Ack }
 
@@ -573,6 +667,15 @@ func (*ShowFingerprints) StatementTag() string { return "SHOW EXPERIMENTAL_FINGE
 
 func (*ShowFingerprints) independentFromParallelizedPriors() {}
 
+// StatementType implements the Statement interface.
+func (*ShowTableStats) StatementType() StatementType { return Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*ShowTableStats) StatementTag() string { return "SHOW STATISTICS" }
+
+func (*ShowTableStats) hiddenFromStats()                   {}
+func (*ShowTableStats) independentFromParallelizedPriors() {}
+
 // StatementType implements the Statement interface.
 func (*ShowConstraints) StatementType() StatementType { return Rows }
 
@@ -621,81 +724,97 @@ func (ValuesClause) StatementType() StatementType { return Rows }
 // StatementTag returns a short string identifying the type of statement.
 func (ValuesClause) StatementTag() string { return "VALUES" }
 
-func (n *AlterTable) String() string               { return AsString(n) }
-func (n AlterTableCmds) String() string            { return AsString(n) }
-func (n *AlterTableAddColumn) String() string      { return AsString(n) }
-func (n *AlterTableAddConstraint) String() string  { return AsString(n) }
-func (n *AlterTableDropColumn) String() string     { return AsString(n) }
-func (n *AlterTableDropConstraint) String() string { return AsString(n) }
-func (n *AlterTableDropNotNull) String() string    { return AsString(n) }
-func (n *AlterTableSetDefault) String() string     { return AsString(n) }
-func (n *Backup) String() string                   { return AsString(n) }
-func (n *BeginTransaction) String() string         { return AsString(n) }
-func (n *CancelJob) String() string                { return AsString(n) }
-func (n *CancelQuery) String() string              { return AsString(n) }
-func (n *CommitTransaction) String() string        { return AsString(n) }
-func (n *CopyFrom) String() string                 { return AsString(n) }
-func (n *CreateDatabase) String() string           { return AsString(n) }
-func (n *CreateIndex) String() string              { return AsString(n) }
-func (n *CreateTable) String() string              { return AsString(n) }
-func (n *CreateUser) String() string               { return AsString(n) }
-func (n *CreateView) String() string               { return AsString(n) }
-func (n *Deallocate) String() string               { return AsString(n) }
-func (n *Delete) String() string                   { return AsString(n) }
-func (n *DropDatabase) String() string             { return AsString(n) }
-func (n *DropIndex) String() string                { return AsString(n) }
-func (n *DropTable) String() string                { return AsString(n) }
-func (n *DropView) String() string                 { return AsString(n) }
-func (n *DropUser) String() string                 { return AsString(n) }
-func (n *Execute) String() string                  { return AsString(n) }
-func (n *Explain) String() string                  { return AsString(n) }
-func (n *Grant) String() string                    { return AsString(n) }
-func (n *Insert) String() string                   { return AsString(n) }
-func (n *Import) String() string                   { return AsString(n) }
-func (n *ParenSelect) String() string              { return AsString(n) }
-func (n *PauseJob) String() string                 { return AsString(n) }
-func (n *Prepare) String() string                  { return AsString(n) }
-func (n *ReleaseSavepoint) String() string         { return AsString(n) }
-func (n *TestingRelocate) String() string          { return AsString(n) }
-func (n *RenameColumn) String() string             { return AsString(n) }
-func (n *RenameDatabase) String() string           { return AsString(n) }
-func (n *RenameIndex) String() string              { return AsString(n) }
-func (n *RenameTable) String() string              { return AsString(n) }
-func (n *Restore) String() string                  { return AsString(n) }
-func (n *ResumeJob) String() string                { return AsString(n) }
-func (n *Revoke) String() string                   { return AsString(n) }
-func (n *RollbackToSavepoint) String() string      { return AsString(n) }
-func (n *RollbackTransaction) String() string      { return AsString(n) }
-func (n *Savepoint) String() string                { return AsString(n) }
-func (n *Scatter) String() string                  { return AsString(n) }
-func (n *Select) String() string                   { return AsString(n) }
-func (n *SelectClause) String() string             { return AsString(n) }
-func (n *SetClusterSetting) String() string        { return AsString(n) }
-func (n *SetDefaultIsolation) String() string      { return AsString(n) }
-func (n *SetTransaction) String() string           { return AsString(n) }
-func (n *SetVar) String() string                   { return AsString(n) }
-func (n *ShowBackup) String() string               { return AsString(n) }
-func (n *ShowClusterSetting) String() string       { return AsString(n) }
-func (n *ShowColumns) String() string              { return AsString(n) }
-func (n *ShowConstraints) String() string          { return AsString(n) }
-func (n *ShowCreateTable) String() string          { return AsString(n) }
-func (n *ShowCreateView) String() string           { return AsString(n) }
-func (n *ShowDatabases) String() string            { return AsString(n) }
-func (n *ShowGrants) String() string               { return AsString(n) }
-func (n *ShowIndex) String() string                { return AsString(n) }
-func (n *ShowJobs) String() string                 { return AsString(n) }
-func (n *ShowQueries) String() string              { return AsString(n) }
-func (n *ShowRanges) String() string               { return AsString(n) }
-func (n *ShowSessions) String() string             { return AsString(n) }
-func (n *ShowTables) String() string               { return AsString(n) }
-func (n *ShowTrace) String() string                { return AsString(n) }
-func (n *ShowTransactionStatus) String() string    { return AsString(n) }
-func (n *ShowUsers) String() string                { return AsString(n) }
-func (n *ShowVar) String() string                  { return AsString(n) }
-func (n *ShowFingerprints) String() string         { return AsString(n) }
-func (n *Split) String() string                    { return AsString(n) }
-func (l StatementList) String() string             { return AsString(l) }
-func (n *Truncate) String() string                 { return AsString(n) }
-func (n *UnionClause) String() string              { return AsString(n) }
-func (n *Update) String() string                   { return AsString(n) }
-func (n *ValuesClause) String() string             { return AsString(n) }
+func (n *AlterTable) String() string                { return AsString(n) }
+func (n AlterTableCmds) String() string             { return AsString(n) }
+func (n *AlterTableAddColumn) String() string       { return AsString(n) }
+func (n *AlterTableAddConstraint) String() string   { return AsString(n) }
+func (n *AlterTableAlterColumnType) String() string { return AsString(n) }
+func (n *AlterTableDropColumn) String() string      { return AsString(n) }
+func (n *AlterTableDropConstraint) String() string  { return AsString(n) }
+func (n *AlterTableDropNotNull) String() string     { return AsString(n) }
+func (n *AlterTableSetDefault) String() string      { return AsString(n) }
+func (n *AlterSequence) String() string             { return AsString(n) }
+func (n *Backup) String() string                    { return AsString(n) }
+func (n *BeginTransaction) String() string          { return AsString(n) }
+func (n *CancelJob) String() string                 { return AsString(n) }
+func (n *CancelQuery) String() string               { return AsString(n) }
+func (n *CloseCursor) String() string               { return AsString(n) }
+func (n *CommitTransaction) String() string         { return AsString(n) }
+func (n *CopyFrom) String() string                  { return AsString(n) }
+func (n *CreateDatabase) String() string            { return AsString(n) }
+func (n *CreateIndex) String() string               { return AsString(n) }
+func (n *CreateTable) String() string               { return AsString(n) }
+func (n *CreatePolicy) String() string              { return AsString(n) }
+func (n *CreateRole) String() string                { return AsString(n) }
+func (n *CreateUser) String() string                { return AsString(n) }
+func (n *CreateView) String() string                { return AsString(n) }
+func (n *CreateSequence) String() string            { return AsString(n) }
+func (n *CreateStats) String() string               { return AsString(n) }
+func (n *Deallocate) String() string                { return AsString(n) }
+func (n *DeclareCursor) String() string             { return AsString(n) }
+func (n *Delete) String() string                    { return AsString(n) }
+func (n *DropDatabase) String() string              { return AsString(n) }
+func (n *DropIndex) String() string                 { return AsString(n) }
+func (n *DropTable) String() string                 { return AsString(n) }
+func (n *DropView) String() string                  { return AsString(n) }
+func (n *DropSequence) String() string              { return AsString(n) }
+func (n *DropPolicy) String() string                { return AsString(n) }
+func (n *DropRole) String() string                  { return AsString(n) }
+func (n *DropUser) String() string                  { return AsString(n) }
+func (n *Execute) String() string                   { return AsString(n) }
+func (n *Explain) String() string                   { return AsString(n) }
+func (n *FetchCursor) String() string               { return AsString(n) }
+func (n *Grant) String() string                     { return AsString(n) }
+func (n *GrantRole) String() string                 { return AsString(n) }
+func (n *Insert) String() string                    { return AsString(n) }
+func (n *Import) String() string                    { return AsString(n) }
+func (n *MoveCursor) String() string                { return AsString(n) }
+func (n *ParenSelect) String() string               { return AsString(n) }
+func (n *PauseJob) String() string                  { return AsString(n) }
+func (n *Prepare) String() string                   { return AsString(n) }
+func (n *ReleaseSavepoint) String() string          { return AsString(n) }
+func (n *TestingRelocate) String() string           { return AsString(n) }
+func (n *RenameColumn) String() string              { return AsString(n) }
+func (n *RenameDatabase) String() string            { return AsString(n) }
+func (n *RenameIndex) String() string               { return AsString(n) }
+func (n *RenameTable) String() string               { return AsString(n) }
+func (n *Restore) String() string                   { return AsString(n) }
+func (n *ResumeJob) String() string                 { return AsString(n) }
+func (n *Revoke) String() string                    { return AsString(n) }
+func (n *RevokeRole) String() string                { return AsString(n) }
+func (n *RollbackToSavepoint) String() string       { return AsString(n) }
+func (n *RollbackTransaction) String() string       { return AsString(n) }
+func (n *Savepoint) String() string                 { return AsString(n) }
+func (n *Scatter) String() string                   { return AsString(n) }
+func (n *Select) String() string                    { return AsString(n) }
+func (n *SelectClause) String() string              { return AsString(n) }
+func (n *SetClusterSetting) String() string         { return AsString(n) }
+func (n *SetDefaultIsolation) String() string       { return AsString(n) }
+func (n *SetTransaction) String() string            { return AsString(n) }
+func (n *SetVar) String() string                    { return AsString(n) }
+func (n *ShowBackup) String() string                { return AsString(n) }
+func (n *ShowClusterSetting) String() string        { return AsString(n) }
+func (n *ShowColumns) String() string               { return AsString(n) }
+func (n *ShowConstraints) String() string           { return AsString(n) }
+func (n *ShowCreateTable) String() string           { return AsString(n) }
+func (n *ShowCreateView) String() string            { return AsString(n) }
+func (n *ShowDatabases) String() string             { return AsString(n) }
+func (n *ShowGrants) String() string                { return AsString(n) }
+func (n *ShowIndex) String() string                 { return AsString(n) }
+func (n *ShowJobs) String() string                  { return AsString(n) }
+func (n *ShowQueries) String() string               { return AsString(n) }
+func (n *ShowRanges) String() string                { return AsString(n) }
+func (n *ShowSessions) String() string              { return AsString(n) }
+func (n *ShowTables) String() string                { return AsString(n) }
+func (n *ShowTableStats) String() string            { return AsString(n) }
+func (n *ShowTrace) String() string                 { return AsString(n) }
+func (n *ShowTransactionStatus) String() string     { return AsString(n) }
+func (n *ShowUsers) String() string                 { return AsString(n) }
+func (n *ShowVar) String() string                   { return AsString(n) }
+func (n *ShowFingerprints) String() string          { return AsString(n) }
+func (n *Split) String() string                     { return AsString(n) }
+func (l StatementList) String() string              { return AsString(l) }
+func (n *Truncate) String() string                  { return AsString(n) }
+func (n *UnionClause) String() string               { return AsString(n) }
+func (n *Update) String() string                    { return AsString(n) }
+func (n *ValuesClause) String() string              { return AsString(n) }
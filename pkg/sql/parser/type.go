@@ -83,6 +83,10 @@ var (
 	TypeBytes Type = tBytes{}
 	// TypeDate is the type of a DDate. Can be compared with ==.
 	TypeDate Type = tDate{}
+	// TypeTime is the type of a DTime. Can be compared with ==.
+	TypeTime Type = tTime{}
+	// TypeTimeTZ is the type of a DTimeTZ. Can be compared with ==.
+	TypeTimeTZ Type = tTimeTZ{}
 	// TypeTimestamp is the type of a DTimestamp. Can be compared with ==.
 	TypeTimestamp Type = tTimestamp{}
 	// TypeTimestampTZ is the type of a DTimestampTZ. Can be compared with ==.
@@ -93,6 +97,8 @@ var (
 	TypeUUID Type = tUUID{}
 	// TypeINet is the type of a DIPAddr. Can be compared with ==.
 	TypeINet Type = tINet{}
+	// TypeJSON is the type of a DJSON. Can be compared with ==.
+	TypeJSON Type = tJSON{}
 	// TypeTuple is the type family of a DTuple. CANNOT be compared with ==.
 	TypeTuple Type = TTuple(nil)
 	// TypeArray is the type family of a DArray. CANNOT be compared with ==.
@@ -140,6 +146,8 @@ var (
 		TypeString,
 		TypeBytes,
 		TypeDate,
+		TypeTime,
+		TypeTimeTZ,
 		TypeTimestamp,
 		TypeTimestampTZ,
 		TypeInterval,
@@ -186,10 +194,13 @@ var OidToType = map[oid.Oid]Type{
 	oid.T__int8:        TArray{TypeInt},
 	oid.T_record:       TypeTuple,
 	oid.T_text:         TypeString,
+	oid.T_time:         TypeTime,
+	oid.T_timetz:       TypeTimeTZ,
 	oid.T_timestamp:    TypeTimestamp,
 	oid.T_timestamptz:  TypeTimestampTZ,
 	oid.T_uuid:         TypeUUID,
 	oid.T_inet:         TypeINet,
+	oid.T_jsonb:        TypeJSON,
 	oid.T_varchar:      typeVarChar,
 }
 
@@ -351,6 +362,26 @@ func (tDate) Oid() oid.Oid                { return oid.T_date }
 func (tDate) SQLName() string             { return "date" }
 func (tDate) IsAmbiguous() bool           { return false }
 
+type tTime struct{}
+
+func (tTime) String() string              { return "time" }
+func (tTime) Equivalent(other Type) bool  { return UnwrapType(other) == TypeTime || other == TypeAny }
+func (tTime) FamilyEqual(other Type) bool { return UnwrapType(other) == TypeTime }
+func (tTime) Size() (uintptr, bool)       { return unsafe.Sizeof(DTime(0)), fixedSize }
+func (tTime) Oid() oid.Oid                { return oid.T_time }
+func (tTime) SQLName() string             { return "time without time zone" }
+func (tTime) IsAmbiguous() bool           { return false }
+
+type tTimeTZ struct{}
+
+func (tTimeTZ) String() string              { return "timetz" }
+func (tTimeTZ) Equivalent(other Type) bool  { return UnwrapType(other) == TypeTimeTZ || other == TypeAny }
+func (tTimeTZ) FamilyEqual(other Type) bool { return UnwrapType(other) == TypeTimeTZ }
+func (tTimeTZ) Size() (uintptr, bool)       { return unsafe.Sizeof(DTimeTZ{}), fixedSize }
+func (tTimeTZ) Oid() oid.Oid                { return oid.T_timetz }
+func (tTimeTZ) SQLName() string             { return "time with time zone" }
+func (tTimeTZ) IsAmbiguous() bool           { return false }
+
 type tTimestamp struct{}
 
 func (tTimestamp) String() string { return "timestamp" }
@@ -407,6 +438,16 @@ func (tINet) Oid() oid.Oid                { return oid.T_inet }
 func (tINet) SQLName() string             { return "inet" }
 func (tINet) IsAmbiguous() bool           { return false }
 
+type tJSON struct{}
+
+func (tJSON) String() string              { return "jsonb" }
+func (tJSON) Equivalent(other Type) bool  { return UnwrapType(other) == TypeJSON || other == TypeAny }
+func (tJSON) FamilyEqual(other Type) bool { return UnwrapType(other) == TypeJSON }
+func (tJSON) Size() (uintptr, bool)       { return unsafe.Sizeof(DJSON{}), variableSize }
+func (tJSON) Oid() oid.Oid                { return oid.T_jsonb }
+func (tJSON) SQLName() string             { return "jsonb" }
+func (tJSON) IsAmbiguous() bool           { return false }
+
 // TTuple is the type of a DTuple.
 type TTuple []Type
 
@@ -238,8 +238,8 @@ func (expr *CastExpr) TypeCheck(ctx *SemaContext, _ Type) (TypedExpr, error) {
 			// If the type doesn't have any possible parameters (like length,
 			// precision), the CastExpr becomes a no-op and can be elided.
 			switch expr.Type.(type) {
-			case *BoolColType, *DateColType, *TimestampColType, *TimestampTZColType,
-				*IntervalColType, *BytesColType:
+			case *BoolColType, *DateColType, *TimeColType, *TimeTZColType, *TimestampColType,
+				*TimestampTZColType, *IntervalColType, *BytesColType:
 				return expr.Expr.TypeCheck(ctx, returnType)
 			}
 		}
@@ -869,10 +869,22 @@ func (d *DUuid) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) { return d,
 // identity function for Datum.
 func (d *DIPAddr) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) { return d, nil }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DJSON) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) { return d, nil }
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DDate) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) { return d, nil }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DTime) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) { return d, nil }
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DTimeTZ) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) { return d, nil }
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DTimestamp) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) { return d, nil }
@@ -146,6 +146,10 @@ type ColumnItem struct {
 	// Selector defines which sub-part of the variable is being
 	// accessed.
 	Selector NameParts
+	// ForUpdateOrDelete indicates that the column is fetched by an UPDATE
+	// or DELETE statement to rewrite or remove the row, and not read by
+	// the query itself; such references need no SELECT privilege.
+	ForUpdateOrDelete bool
 }
 
 // Format implements the NodeFormatter interface.
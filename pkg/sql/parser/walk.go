@@ -211,6 +211,18 @@ func (expr *FuncExpr) CopyNode() *FuncExpr {
 			}
 			windowDef.OrderBy = newOrderBy
 		}
+		if windowDef.Frame != nil {
+			frameCopy := *windowDef.Frame
+			windowDef.Frame = &frameCopy
+			if startBound := frameCopy.Bounds.StartBound; startBound != nil {
+				startBoundCopy := *startBound
+				frameCopy.Bounds.StartBound = &startBoundCopy
+			}
+			if endBound := frameCopy.Bounds.EndBound; endBound != nil {
+				endBoundCopy := *endBound
+				frameCopy.Bounds.EndBound = &endBoundCopy
+			}
+		}
 	}
 	return &exprCopy
 }
@@ -249,6 +261,26 @@ func (expr *FuncExpr) Walk(v Visitor) Expr {
 				ret.WindowDef.OrderBy[i].Expr = e
 			}
 		}
+		if frame := expr.WindowDef.Frame; frame != nil {
+			if startBound := frame.Bounds.StartBound; startBound.HasOffset() {
+				e, changed := WalkExpr(v, startBound.OffsetExpr)
+				if changed {
+					if ret == expr {
+						ret = expr.CopyNode()
+					}
+					ret.WindowDef.Frame.Bounds.StartBound.OffsetExpr = e
+				}
+			}
+			if endBound := frame.Bounds.EndBound; endBound != nil && endBound.HasOffset() {
+				e, changed := WalkExpr(v, endBound.OffsetExpr)
+				if changed {
+					if ret == expr {
+						ret = expr.CopyNode()
+					}
+					ret.WindowDef.Frame.Bounds.EndBound.OffsetExpr = e
+				}
+			}
+		}
 	}
 	if expr.Filter != nil {
 		e, changed := WalkExpr(v, expr.Filter)
@@ -519,6 +551,12 @@ func (expr *DBytes) Walk(_ Visitor) Expr { return expr }
 // Walk implements the Expr interface.
 func (expr *DDate) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *DTime) Walk(_ Visitor) Expr { return expr }
+
+// Walk implements the Expr interface.
+func (expr *DTimeTZ) Walk(_ Visitor) Expr { return expr }
+
 // Walk implements the Expr interface.
 func (expr *DFloat) Walk(_ Visitor) Expr { return expr }
 
@@ -537,6 +575,9 @@ func (expr *DUuid) Walk(_ Visitor) Expr { return expr }
 // Walk implements the Expr interface.
 func (expr *DIPAddr) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *DJSON) Walk(_ Visitor) Expr { return expr }
+
 // Walk implements the Expr interface.
 func (expr dNull) Walk(_ Visitor) Expr { return expr }
 
@@ -622,6 +663,25 @@ func walkReturningClause(v Visitor, clause ReturningClause) (ReturningClause, bo
 	}
 }
 
+func walkWith(v Visitor, with *With) (*With, bool) {
+	if with == nil {
+		return nil, false
+	}
+	ret := with
+	for i, cte := range with.CTEList {
+		s, changed := WalkStmt(v, cte.Stmt)
+		if changed {
+			if ret == with {
+				ret = &With{CTEList: append([]*CTE(nil), with.CTEList...)}
+			}
+			cteCopy := *cte
+			cteCopy.Stmt = s
+			ret.CTEList[i] = &cteCopy
+		}
+	}
+	return ret, (ret != with)
+}
+
 // CopyNode makes a copy of this Statement without recursing in any child Statements.
 func (stmt *Backup) CopyNode() *Backup {
 	stmtCopy := *stmt
@@ -692,6 +752,13 @@ func (stmt *Delete) WalkStmt(v Visitor) Statement {
 			ret.Where.Expr = e
 		}
 	}
+	with, changed := walkWith(v, stmt.With)
+	if changed {
+		if ret == stmt {
+			ret = stmt.CopyNode()
+		}
+		ret.With = with
+	}
 	returning, changed := walkReturningClause(v, stmt.Returning)
 	if changed {
 		if ret == stmt {
@@ -735,6 +802,13 @@ func (stmt *Insert) WalkStmt(v Visitor) Statement {
 			ret.Rows = rows.(*Select)
 		}
 	}
+	with, changed := walkWith(v, stmt.With)
+	if changed {
+		if ret == stmt {
+			ret = stmt.CopyNode()
+		}
+		ret.With = with
+	}
 	returning, changed := walkReturningClause(v, stmt.Returning)
 	if changed {
 		if ret == stmt {
@@ -880,6 +954,13 @@ func (stmt *Select) WalkStmt(v Visitor) Statement {
 		ret = stmt.CopyNode()
 		ret.Select = sel.(SelectStatement)
 	}
+	with, changed := walkWith(v, stmt.With)
+	if changed {
+		if ret == stmt {
+			ret = stmt.CopyNode()
+		}
+		ret.With = with
+	}
 	order, changed := walkOrderBy(v, stmt.OrderBy)
 	if changed {
 		if ret == stmt {
@@ -1086,6 +1167,14 @@ func (stmt *Update) WalkStmt(v Visitor) Statement {
 		}
 	}
 
+	with, changed := walkWith(v, stmt.With)
+	if changed {
+		if ret == stmt {
+			ret = stmt.CopyNode()
+		}
+		ret.With = with
+	}
+
 	returning, changed := walkReturningClause(v, stmt.Returning)
 	if changed {
 		if ret == stmt {
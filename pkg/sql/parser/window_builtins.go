@@ -16,6 +16,7 @@ package parser
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 
@@ -48,14 +49,30 @@ type IndexedRow struct {
 	Row Datums
 }
 
-// WindowFrame is a view into a subset of data over which calculations are made.
-type WindowFrame struct {
-	// constant for all calls to WindowFunc.Add
+// WindowFrameRun is a view into a subset of data over which calculations are made.
+type WindowFrameRun struct {
+	// constant for all calls to WindowFunc.Compute
 	Rows        []IndexedRow
 	ArgIdxStart int // the index which arguments to the window function begin
 	ArgCount    int // the number of window function arguments
 
-	// changes for each row (each call to WindowFunc.Add)
+	// Frame is the frame of the window definition, or nil if none is
+	// specified. StartBoundOffset and EndBoundOffset are the values of the
+	// offsets of its bounds, if any.
+	Frame            *WindowFrame
+	StartBoundOffset Datum
+	EndBoundOffset   Datum
+
+	// OrdVals are the values of the ORDER BY column of the Rows and
+	// OrdDescending its direction. They, as well as PlusOp and MinusOp, are
+	// only set for RANGE frames with offsets, which require a single ORDER BY
+	// column.
+	OrdVals       Datums
+	OrdDescending bool
+	PlusOp        BinOp
+	MinusOp       BinOp
+
+	// changes for each row (each call to WindowFunc.Compute)
 	RowIdx int // the current row index
 
 	// changes for each peer group
@@ -63,34 +80,164 @@ type WindowFrame struct {
 	PeerRowCount int // the number of rows in the current peer group
 }
 
-func (wf WindowFrame) rank() int {
+func (wf WindowFrameRun) rank() int {
 	return wf.RowIdx + 1
 }
 
-func (wf WindowFrame) rowCount() int {
+func (wf WindowFrameRun) rowCount() int {
 	return len(wf.Rows)
 }
 
-// TODO(nvanbenschoten): This definition only holds while we don't support
-// frame specification (RANGE or ROWS) in the OVER clause.
-func (wf WindowFrame) frameSize() int {
+// peerGroupEndIdx returns one past the index of the last row in the current
+// peer group.
+func (wf WindowFrameRun) peerGroupEndIdx() int {
 	return wf.FirstPeerIdx + wf.PeerRowCount
 }
 
 // firstInPeerGroup returns if the current row is the first in its peer group.
-func (wf WindowFrame) firstInPeerGroup() bool {
+func (wf WindowFrameRun) firstInPeerGroup() bool {
 	return wf.RowIdx == wf.FirstPeerIdx
 }
 
-func (wf WindowFrame) args() Datums {
+// frameStartsAtPartitionStart returns whether the frame of every row starts
+// at the first row of the partition.
+func (wf WindowFrameRun) frameStartsAtPartitionStart() bool {
+	return wf.Frame == nil || wf.Frame.Bounds.StartBound.BoundType == UnboundedPreceding
+}
+
+// frameBounds returns the index of the first row in the current row's window
+// frame and one past the index of its last row. The frame is empty if they
+// are equal.
+func (wf WindowFrameRun) frameBounds(evalCtx *EvalContext) (start, end int, err error) {
+	if wf.Frame == nil {
+		// The default frame, RANGE UNBOUNDED PRECEDING, contains the rows from
+		// the start of the partition through the last peer of the current row.
+		return 0, wf.peerGroupEndIdx(), nil
+	}
+	start, err = wf.frameBoundIdx(evalCtx, wf.Frame.Bounds.StartBound, wf.StartBoundOffset, false /* end */)
+	if err != nil {
+		return 0, 0, err
+	}
+	endBound := wf.Frame.Bounds.EndBound
+	if endBound == nil {
+		endBound = &WindowFrameBound{BoundType: CurrentRow}
+	}
+	end, err = wf.frameBoundIdx(evalCtx, endBound, wf.EndBoundOffset, true /* end */)
+	if err != nil {
+		return 0, 0, err
+	}
+	if end < start {
+		end = start
+	}
+	return start, end, nil
+}
+
+// frameBoundIdx returns the index of the row at which the current row's frame
+// starts or, if end is set, one past the index of the row at which it ends.
+func (wf WindowFrameRun) frameBoundIdx(
+	evalCtx *EvalContext, bound *WindowFrameBound, offset Datum, end bool,
+) (int, error) {
+	switch bound.BoundType {
+	case UnboundedPreceding:
+		return 0, nil
+	case UnboundedFollowing:
+		return wf.rowCount(), nil
+	}
+
+	if wf.Frame.Mode == RowsFrame {
+		idx := wf.RowIdx
+		switch bound.BoundType {
+		case OffsetPreceding:
+			if n := MustBeDInt(offset); n < DInt(idx) {
+				idx -= int(n)
+			} else {
+				idx = 0
+				if end {
+					// The frame ends before the first row.
+					return 0, nil
+				}
+			}
+		case OffsetFollowing:
+			if n := MustBeDInt(offset); n < DInt(wf.rowCount()-idx) {
+				idx += int(n)
+			} else {
+				return wf.rowCount(), nil
+			}
+		}
+		if end {
+			idx++
+		}
+		return idx, nil
+	}
+
+	// In RANGE mode, the bounds are peer group boundaries for the current row
+	// and for NULLs, which are only within an offset of their peers.
+	if bound.BoundType == CurrentRow || wf.OrdVals[wf.RowIdx] == DNull {
+		if end {
+			return wf.peerGroupEndIdx(), nil
+		}
+		return wf.FirstPeerIdx, nil
+	}
+	// The value at the bound is the current value moved by the offset in the
+	// direction of the bound, taking the direction of the ordering into
+	// account.
+	op := wf.PlusOp
+	if (bound.BoundType == OffsetPreceding) != wf.OrdDescending {
+		op = wf.MinusOp
+	}
+	boundVal, err := op.fn(evalCtx, wf.OrdVals[wf.RowIdx], offset)
+	if err != nil {
+		return 0, err
+	}
+	// NULLs sort first and are outside of the range of any non-NULL value.
+	lo, hi := 0, wf.rowCount()
+	if wf.OrdDescending {
+		hi = sort.Search(hi, func(i int) bool { return wf.OrdVals[i] == DNull })
+	} else {
+		lo = sort.Search(hi, func(i int) bool { return wf.OrdVals[i] != DNull })
+	}
+	return lo + sort.Search(hi-lo, func(i int) bool {
+		c := wf.OrdVals[lo+i].Compare(evalCtx, boundVal)
+		if wf.OrdDescending {
+			c = -c
+		}
+		if end {
+			// The end is one past the last row within the bound.
+			return c > 0
+		}
+		// The start is the first row within the bound.
+		return c >= 0
+	}), nil
+}
+
+func (wf WindowFrameRun) args() Datums {
 	return wf.argsWithRowOffset(0)
 }
 
-func (wf WindowFrame) argsWithRowOffset(offset int) Datums {
-	return wf.Rows[wf.RowIdx+offset].Row[wf.ArgIdxStart : wf.ArgIdxStart+wf.ArgCount]
+func (wf WindowFrameRun) argsWithRowOffset(offset int) Datums {
+	return wf.argsAt(wf.RowIdx + offset)
+}
+
+func (wf WindowFrameRun) argsAt(idx int) Datums {
+	return wf.Rows[idx].Row[wf.ArgIdxStart : wf.ArgIdxStart+wf.ArgCount]
+}
+
+// WindowFrameRangeOps returns the operators computing the bounds of RANGE
+// frames with offsets of type offsetType over an ORDER BY column of type
+// ordType, or false if such frames are not supported for these types.
+func WindowFrameRangeOps(ordType, offsetType Type) (plusOp, minusOp BinOp, ok bool) {
+	plusOp, ok = BinOps[Plus].lookupImpl(ordType, offsetType)
+	if !ok || !plusOp.ReturnType.Equivalent(ordType) {
+		return BinOp{}, BinOp{}, false
+	}
+	minusOp, ok = BinOps[Minus].lookupImpl(ordType, offsetType)
+	if !ok || !minusOp.ReturnType.Equivalent(ordType) {
+		return BinOp{}, BinOp{}, false
+	}
+	return plusOp, minusOp, true
 }
 
-// WindowFunc performs a computation on each row using data from a provided WindowFrame.
+// WindowFunc performs a computation on each row using data from a provided WindowFrameRun.
 type WindowFunc interface {
 	// Compute computes the window function for the provided window frame, given the
 	// current state of WindowFunc. The method should be called sequentially for every
@@ -98,7 +245,7 @@ type WindowFunc interface {
 	// because there is an implicit carried dependency between each row and all those
 	// that have come before it (like in an AggregateFunc). As such, this approach does
 	// not present any exploitable associativity/commutativity for optimization.
-	Compute(context.Context, *EvalContext, WindowFrame) (Datum, error)
+	Compute(context.Context, *EvalContext, WindowFrameRun) (Datum, error)
 
 	// Close allows the window function to free any memory it requested during execution,
 	// such as during the execution of an aggregation like CONCAT_AGG or ARRAY_AGG.
@@ -205,45 +352,75 @@ var _ WindowFunc = &nthValueWindow{}
 // aggregateWindowFunc aggregates over the the current row's window frame, using
 // the internal AggregateFunc to perform the aggregation.
 type aggregateWindowFunc struct {
-	agg     AggregateFunc
-	peerRes Datum
+	agg    AggregateFunc
+	newAgg func() AggregateFunc
+	// added is the number of rows of the partition added to agg.
+	added int
+
+	// peerRes is the result for the frame of the previous row, which is shared
+	// by all peers with the default frame.
+	peerRes              Datum
+	frameStart, frameEnd int
 }
 
-func newAggregateWindow(agg AggregateFunc) WindowFunc {
-	return &aggregateWindowFunc{agg: agg}
+func newAggregateWindow(newAgg func() AggregateFunc) WindowFunc {
+	return &aggregateWindowFunc{agg: newAgg(), newAgg: newAgg}
 }
 
 func (w *aggregateWindowFunc) Compute(
-	ctx context.Context, evalCtx *EvalContext, wf WindowFrame,
+	ctx context.Context, evalCtx *EvalContext, wf WindowFrameRun,
 ) (Datum, error) {
-	if !wf.firstInPeerGroup() {
+	start, end, err := wf.frameBounds(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	if w.peerRes != nil && start == w.frameStart && end == w.frameEnd {
 		return w.peerRes, nil
 	}
 
-	// Accumulate all values in the peer group at the same time, as these
-	// must return the same value.
-	for i := 0; i < wf.PeerRowCount; i++ {
-		args := wf.argsWithRowOffset(i)
+	add := func(agg AggregateFunc, idx int) error {
+		args := wf.argsAt(idx)
 		var value Datum
 		// COUNT_ROWS takes no arguments.
 		if len(args) > 0 {
 			value = args[0]
 		}
-		if err := w.agg.Add(ctx, value); err != nil {
+		return agg.Add(ctx, value)
+	}
+
+	var res Datum
+	if wf.frameStartsAtPartitionStart() {
+		// The frame only grows from one row to the next, so the values can be
+		// accumulated across rows.
+		for ; w.added < end; w.added++ {
+			if err := add(w.agg, w.added); err != nil {
+				return nil, err
+			}
+		}
+		if res, err = w.agg.Result(); err != nil {
+			return nil, err
+		}
+	} else {
+		// Rows leave the frame from one row to the next, so each frame is
+		// aggregated from scratch.
+		agg := w.newAgg()
+		defer agg.Close(ctx)
+		for i := start; i < end; i++ {
+			if err := add(agg, i); err != nil {
+				return nil, err
+			}
+		}
+		if res, err = agg.Result(); err != nil {
 			return nil, err
 		}
 	}
 
-	// Retrieve the value for the entire peer group, save it, and return it.
-
-	peerRes, err := w.agg.Result()
-	if err != nil {
-		return nil, err
-	}
-	w.peerRes = peerRes
+	// Save the value for the frame, which may be the frame of the next rows.
+	w.peerRes, w.frameStart, w.frameEnd = res, start, end
 	return w.peerRes, nil
 }
 
+
 func (w *aggregateWindowFunc) Close(ctx context.Context, evalCtx *EvalContext) {
 	w.agg.Close(ctx)
 }
@@ -256,7 +433,7 @@ func newRowNumberWindow([]Type, *EvalContext) WindowFunc {
 	return &rowNumberWindow{}
 }
 
-func (rowNumberWindow) Compute(_ context.Context, _ *EvalContext, wf WindowFrame) (Datum, error) {
+func (rowNumberWindow) Compute(_ context.Context, _ *EvalContext, wf WindowFrameRun) (Datum, error) {
 	return NewDInt(DInt(wf.RowIdx + 1 /* one-indexed */)), nil
 }
 
@@ -271,7 +448,7 @@ func newRankWindow([]Type, *EvalContext) WindowFunc {
 	return &rankWindow{}
 }
 
-func (w *rankWindow) Compute(_ context.Context, _ *EvalContext, wf WindowFrame) (Datum, error) {
+func (w *rankWindow) Compute(_ context.Context, _ *EvalContext, wf WindowFrameRun) (Datum, error) {
 	if wf.firstInPeerGroup() {
 		w.peerRes = NewDInt(DInt(wf.rank()))
 	}
@@ -291,7 +468,7 @@ func newDenseRankWindow([]Type, *EvalContext) WindowFunc {
 }
 
 func (w *denseRankWindow) Compute(
-	_ context.Context, _ *EvalContext, wf WindowFrame,
+	_ context.Context, _ *EvalContext, wf WindowFrameRun,
 ) (Datum, error) {
 	if wf.firstInPeerGroup() {
 		w.denseRank++
@@ -315,7 +492,7 @@ func newPercentRankWindow([]Type, *EvalContext) WindowFunc {
 var dfloatZero = NewDFloat(0)
 
 func (w *percentRankWindow) Compute(
-	_ context.Context, _ *EvalContext, wf WindowFrame,
+	_ context.Context, _ *EvalContext, wf WindowFrameRun,
 ) (Datum, error) {
 	// Return zero if there's only one row, per spec.
 	if wf.rowCount() <= 1 {
@@ -342,11 +519,11 @@ func newCumulativeDistWindow([]Type, *EvalContext) WindowFunc {
 }
 
 func (w *cumulativeDistWindow) Compute(
-	_ context.Context, _ *EvalContext, wf WindowFrame,
+	_ context.Context, _ *EvalContext, wf WindowFrameRun,
 ) (Datum, error) {
 	if wf.firstInPeerGroup() {
 		// (number of rows preceding or peer with current row) / (total rows)
-		w.peerRes = NewDFloat(DFloat(wf.frameSize()) / DFloat(wf.rowCount()))
+		w.peerRes = NewDFloat(DFloat(wf.peerGroupEndIdx()) / DFloat(wf.rowCount()))
 	}
 	return w.peerRes, nil
 }
@@ -368,7 +545,7 @@ func newNtileWindow([]Type, *EvalContext) WindowFunc {
 
 var errInvalidArgumentForNtile = pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError, "argument of ntile() must be greater than zero")
 
-func (w *ntileWindow) Compute(_ context.Context, _ *EvalContext, wf WindowFrame) (Datum, error) {
+func (w *ntileWindow) Compute(_ context.Context, _ *EvalContext, wf WindowFrameRun) (Datum, error) {
 	if w.ntile == nil {
 		// If this is the first call to ntileWindow.Compute, set up the buckets.
 		total := wf.rowCount()
@@ -436,7 +613,7 @@ func makeLeadLagWindowConstructor(
 	}
 }
 
-func (w *leadLagWindow) Compute(_ context.Context, _ *EvalContext, wf WindowFrame) (Datum, error) {
+func (w *leadLagWindow) Compute(_ context.Context, _ *EvalContext, wf WindowFrameRun) (Datum, error) {
 	offset := 1
 	if w.withOffset {
 		offsetArg := wf.args()[1]
@@ -470,8 +647,17 @@ func newFirstValueWindow([]Type, *EvalContext) WindowFunc {
 	return &firstValueWindow{}
 }
 
-func (firstValueWindow) Compute(_ context.Context, _ *EvalContext, wf WindowFrame) (Datum, error) {
-	return wf.Rows[0].Row[wf.ArgIdxStart], nil
+func (firstValueWindow) Compute(
+	_ context.Context, evalCtx *EvalContext, wf WindowFrameRun,
+) (Datum, error) {
+	start, end, err := wf.frameBounds(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	if start == end {
+		return DNull, nil
+	}
+	return wf.Rows[start].Row[wf.ArgIdxStart], nil
 }
 
 func (firstValueWindow) Close(context.Context, *EvalContext) {}
@@ -483,8 +669,17 @@ func newLastValueWindow([]Type, *EvalContext) WindowFunc {
 	return &lastValueWindow{}
 }
 
-func (lastValueWindow) Compute(_ context.Context, _ *EvalContext, wf WindowFrame) (Datum, error) {
-	return wf.Rows[wf.frameSize()-1].Row[wf.ArgIdxStart], nil
+func (lastValueWindow) Compute(
+	_ context.Context, evalCtx *EvalContext, wf WindowFrameRun,
+) (Datum, error) {
+	start, end, err := wf.frameBounds(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	if start == end {
+		return DNull, nil
+	}
+	return wf.Rows[end-1].Row[wf.ArgIdxStart], nil
 }
 
 func (lastValueWindow) Close(context.Context, *EvalContext) {}
@@ -499,7 +694,9 @@ func newNthValueWindow([]Type, *EvalContext) WindowFunc {
 
 var errInvalidArgumentForNthValue = pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError, "argument of nth_value() must be greater than zero")
 
-func (nthValueWindow) Compute(_ context.Context, _ *EvalContext, wf WindowFrame) (Datum, error) {
+func (nthValueWindow) Compute(
+	_ context.Context, evalCtx *EvalContext, wf WindowFrameRun,
+) (Datum, error) {
 	arg := wf.args()[1]
 	if arg == DNull {
 		return DNull, nil
@@ -512,10 +709,14 @@ func (nthValueWindow) Compute(_ context.Context, _ *EvalContext, wf WindowFrame)
 
 	// per spec: Only consider the rows within the "window frame", which by default contains
 	// the rows from the start of the partition through the last peer of the current row.
-	if nth > wf.frameSize() {
+	start, end, err := wf.frameBounds(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	if nth > end-start {
 		return DNull, nil
 	}
-	return wf.Rows[nth-1].Row[wf.ArgIdxStart], nil
+	return wf.Rows[start+nth-1].Row[wf.ArgIdxStart], nil
 }
 
 func (nthValueWindow) Close(context.Context, *EvalContext) {}
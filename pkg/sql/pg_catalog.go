@@ -219,9 +219,10 @@ CREATE TABLE pg_catalog.pg_attribute (
 }
 
 var (
-	relKindTable = parser.NewDString("r")
-	relKindIndex = parser.NewDString("i")
-	relKindView  = parser.NewDString("v")
+	relKindTable    = parser.NewDString("r")
+	relKindIndex    = parser.NewDString("i")
+	relKindView     = parser.NewDString("v")
+	relKindSequence = parser.NewDString("S")
 
 	relPersistencePermanent = parser.NewDString("p")
 )
@@ -267,6 +268,8 @@ CREATE TABLE pg_catalog.pg_class (
 			if table.IsView() {
 				// The only difference between tables and views is the relkind column.
 				relKind = relKindView
+			} else if table.IsSequence() {
+				relKind = relKindSequence
 			}
 			if err := addRow(
 				h.TableOid(db, table),       // oid
@@ -942,11 +945,25 @@ func indexDefFromDescriptor(
 		if index.ColumnDirections[i] == sqlbase.IndexDescriptor_DESC {
 			elem.Direction = parser.Descending
 		}
+		if index.IsExprColumn(i) {
+			expr, err := parser.ParseExpr(index.ColumnExprs[i])
+			if err != nil {
+				return "", err
+			}
+			elem.Expr = expr
+		}
 		indexDef.Columns[i] = elem
 	}
 	for i, name := range index.StoreColumnNames {
 		indexDef.Storing[i] = parser.Name(name)
 	}
+	if index.IsPartial() {
+		pred, err := parser.ParseExpr(index.Predicate)
+		if err != nil {
+			return "", err
+		}
+		indexDef.Predicate = pred
+	}
 	if len(index.Interleave.Ancestors) > 0 {
 		intl := index.Interleave
 		parentTable, err := sqlbase.GetTableDescFromID(ctx, p.txn, intl.Ancestors[len(intl.Ancestors)-1].TableID)
@@ -1319,7 +1336,7 @@ CREATE TABLE pg_catalog.pg_tables (
 `,
 	populate: func(ctx context.Context, p *planner, prefix string, addRow func(...parser.Datum) error) error {
 		return forEachTableDesc(ctx, p, prefix, func(db *sqlbase.DatabaseDescriptor, table *sqlbase.TableDescriptor) error {
-			if table.IsView() {
+			if table.IsView() || table.IsSequence() {
 				return nil
 			}
 			return addRow(
@@ -1554,11 +1571,14 @@ var datumToTypeCategory = map[reflect.Type]*parser.DString{
 	reflect.TypeOf(parser.TypeString):      typCategoryString,
 	reflect.TypeOf(parser.TypeTimestamp):   typCategoryDateTime,
 	reflect.TypeOf(parser.TypeTimestampTZ): typCategoryDateTime,
+	reflect.TypeOf(parser.TypeTime):        typCategoryDateTime,
+	reflect.TypeOf(parser.TypeTimeTZ):      typCategoryDateTime,
 	reflect.TypeOf(parser.TypeTuple):       typCategoryPseudo,
 	reflect.TypeOf(parser.TypeTable):       typCategoryPseudo,
 	reflect.TypeOf(parser.TypeOid):         typCategoryNumeric,
 	reflect.TypeOf(parser.TypeUUID):        typCategoryUserDefined,
 	reflect.TypeOf(parser.TypeINet):        typCategoryNetworkAddr,
+	reflect.TypeOf(parser.TypeJSON):        typCategoryUserDefined,
 }
 
 func typCategory(typ parser.Type) parser.Datum {
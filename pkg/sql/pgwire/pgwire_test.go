@@ -15,10 +15,14 @@
 package pgwire_test
 
 import (
+	"bufio"
+	"bytes"
 	gosql "database/sql"
 	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/url"
@@ -1771,3 +1775,121 @@ func TestPGWireTooManyArguments(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+// rawPGConn speaks the pgwire protocol at the message level, for testing
+// behaviors lib/pq doesn't exercise.
+type rawPGConn struct {
+	t    *testing.T
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+func newRawPGConn(t *testing.T, addr string) *rawPGConn {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &rawPGConn{t: t, conn: conn, rd: bufio.NewReader(conn)}
+	// The startup message has no type.
+	c.send(0, int32(196608) /* version 3.0 */, "user", security.RootUser, "")
+	for {
+		if typ, _ := c.receive(); typ == 'Z' {
+			return c
+		}
+	}
+}
+
+// send sends a message of the given type, the arguments of which are strings
+// or fixed-size integers.
+func (c *rawPGConn) send(typ byte, args ...interface{}) {
+	var buf bytes.Buffer
+	for _, arg := range args {
+		if s, ok := arg.(string); ok {
+			buf.WriteString(s)
+			buf.WriteByte(0)
+		} else if err := binary.Write(&buf, binary.BigEndian, arg); err != nil {
+			c.t.Fatal(err)
+		}
+	}
+	var msg []byte
+	if typ != 0 {
+		msg = append(msg, typ)
+	}
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(4+buf.Len()))
+	msg = append(msg, length[:]...)
+	msg = append(msg, buf.Bytes()...)
+	if _, err := c.conn.Write(msg); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *rawPGConn) receive() (byte, []byte) {
+	typ, err := c.rd.ReadByte()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	var length uint32
+	if err := binary.Read(c.rd, binary.BigEndian, &length); err != nil {
+		c.t.Fatal(err)
+	}
+	payload := make([]byte, length-4)
+	if _, err := io.ReadFull(c.rd, payload); err != nil {
+		c.t.Fatal(err)
+	}
+	return typ, payload
+}
+
+// expect checks the types of the next messages received.
+func (c *rawPGConn) expect(types ...byte) {
+	for _, expected := range types {
+		if typ, payload := c.receive(); typ != expected {
+			c.t.Fatalf("expected message %q, got %q: %q", expected, typ, payload)
+		}
+	}
+}
+
+// TestPGWirePortalSuspension checks that in a transaction block, executing a
+// portal with a row limit returns the rows of the query in batches, the
+// portal being suspended in between.
+func TestPGWirePortalSuspension(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{Insecure: true})
+	defer s.Stopper().Stop(context.TODO())
+
+	if _, err := db.Exec(`
+CREATE DATABASE d;
+CREATE TABLE d.t (k INT PRIMARY KEY);
+INSERT INTO d.t VALUES (1), (2), (3), (4), (5);
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	c := newRawPGConn(t, s.ServingAddr())
+	defer c.conn.Close()
+
+	c.send('Q', "BEGIN")
+	c.expect('C', 'Z')
+
+	c.send('P', "", "SELECT k FROM d.t ORDER BY k", int16(0))
+	c.send('B', "", "", int16(0), int16(0), int16(0))
+	for i := 0; i < 3; i++ {
+		c.send('E', "", int32(2))
+	}
+	c.send('S')
+	c.expect('1', '2', 'D', 'D', 's', 'D', 'D', 's', 'D', 'C', 'Z')
+
+	c.send('Q', "COMMIT")
+	c.expect('C', 'Z')
+
+	// The portal can't be resumed after the end of the transaction.
+	c.send('E', "", int32(2))
+	c.send('S')
+	c.expect('E', 'Z')
+
+	// Outside of a transaction block, the portal must return all its rows.
+	c.send('B', "", "", int16(0), int16(0), int16(0))
+	c.send('E', "", int32(5))
+	c.send('S')
+	c.expect('2', 'D', 'D', 'D', 'D', 'D', 'C', 'Z')
+}
@@ -12,7 +12,7 @@ const (
 	_serverMessageType_name_4 = "serverMsgAuthserverMsgParameterStatusserverMsgRowDescription"
 	_serverMessageType_name_5 = "serverMsgReady"
 	_serverMessageType_name_6 = "serverMsgNoData"
-	_serverMessageType_name_7 = "serverMsgParameterDescription"
+	_serverMessageType_name_7 = "serverMsgPortalSuspendedserverMsgParameterDescription"
 )
 
 var (
@@ -23,7 +23,7 @@ var (
 	_serverMessageType_index_4 = [...]uint8{0, 13, 37, 60}
 	_serverMessageType_index_5 = [...]uint8{0, 14}
 	_serverMessageType_index_6 = [...]uint8{0, 15}
-	_serverMessageType_index_7 = [...]uint8{0, 29}
+	_serverMessageType_index_7 = [...]uint8{0, 24, 53}
 )
 
 func (i serverMessageType) String() string {
@@ -45,8 +45,9 @@ func (i serverMessageType) String() string {
 		return _serverMessageType_name_5
 	case i == 110:
 		return _serverMessageType_name_6
-	case i == 116:
-		return _serverMessageType_name_7
+	case 115 <= i && i <= 116:
+		i -= 115
+		return _serverMessageType_name_7[_serverMessageType_index_7[i]:_serverMessageType_index_7[i+1]]
 	default:
 		return fmt.Sprintf("serverMessageType(%d)", i)
 	}
@@ -144,6 +144,9 @@ func (b *writeBuffer) writeTextDatum(
 	case *parser.DIPAddr:
 		b.writeLengthPrefixedString(v.IPAddr.String())
 
+	case *parser.DJSON:
+		b.writeLengthPrefixedString(v.JSON.String())
+
 	case *parser.DString:
 		b.writeLengthPrefixedString(string(*v))
 
@@ -169,6 +172,9 @@ func (b *writeBuffer) writeTextDatum(
 		b.putInt32(int32(len(s)))
 		b.write(s)
 
+	case *parser.DTime, *parser.DTimeTZ:
+		b.writeLengthPrefixedString(parser.AsStringWithFlags(v, parser.FmtBareStrings))
+
 	case *parser.DInterval:
 		b.writeLengthPrefixedString(v.ValueAsString())
 
@@ -317,6 +323,14 @@ func (b *writeBuffer) writeBinaryDatum(
 		b.putInt32(16)
 		b.write(v.GetBytes())
 
+	case *parser.DJSON:
+		// The binary format of jsonb is a version byte followed by the text
+		// representation.
+		s := v.JSON.String()
+		b.putInt32(int32(len(s) + 1))
+		b.writeByte(pgBinaryJSONBVersion)
+		b.writeString(s)
+
 	case *parser.DIPAddr:
 		// We calculate the Postgres binary format for an IPAddr. For the spec see,
 		// https://github.com/postgres/postgres/blob/81c5e46c490e2426db243eada186995da5bb0ba7/src/backend/utils/adt/network.c#L144
@@ -370,6 +384,16 @@ func (b *writeBuffer) writeBinaryDatum(
 		b.putInt32(4)
 		b.putInt32(dateToPgBinary(v))
 
+	case *parser.DTime:
+		b.putInt32(8)
+		b.putInt64(int64(*v))
+
+	case *parser.DTimeTZ:
+		// Postgres sends the time zone offset in seconds west of UTC.
+		b.putInt32(12)
+		b.putInt64(int64(v.Time))
+		b.putInt32(-v.OffsetSecs)
+
 	case *parser.DArray:
 		if v.ParamTyp.FamilyEqual(parser.TypeAnyArray) {
 			b.setError(errors.New("unsupported binary serialization of multidimensional arrays"))
@@ -482,6 +506,10 @@ const (
 	pgBinaryIPv6family byte = 3
 )
 
+// pgBinaryJSONBVersion is the version of the jsonb binary format, which is
+// the only one defined by Postgres.
+const pgBinaryJSONBVersion byte = 1
+
 // pgBinaryToIPAddr takes an IPAddr and interprets it as the Postgres binary
 // format. See https://github.com/postgres/postgres/blob/81c5e46c490e2426db243eada186995da5bb0ba7/src/backend/utils/adt/network.c#L144
 // for the binary spec.
@@ -591,6 +619,18 @@ func decodeOidDatum(id oid.Oid, code formatCode, b []byte) (parser.Datum, error)
 			}
 			daysSinceEpoch := ts.Unix() / secondsInDay
 			return parser.NewDDate(parser.DDate(daysSinceEpoch)), nil
+		case oid.T_time:
+			d, err := parser.ParseDTime(string(b))
+			if err != nil {
+				return nil, errors.Errorf("could not parse string %q as time", b)
+			}
+			return d, nil
+		case oid.T_timetz:
+			d, err := parser.ParseDTimeTZ(string(b), time.UTC)
+			if err != nil {
+				return nil, errors.Errorf("could not parse string %q as timetz", b)
+			}
+			return d, nil
 		case oid.T_interval:
 			d, err := parser.ParseDInterval(string(b))
 			if err != nil {
@@ -609,6 +649,12 @@ func decodeOidDatum(id oid.Oid, code formatCode, b []byte) (parser.Datum, error)
 				return nil, errors.Errorf("could not parse string %q as inet", b)
 			}
 			return d, nil
+		case oid.T_jsonb:
+			d, err := parser.ParseDJSON(string(b))
+			if err != nil {
+				return nil, errors.Errorf("could not parse string %q as jsonb", b)
+			}
+			return d, nil
 		case oid.T__int2, oid.T__int4, oid.T__int8:
 			var arr pq.Int64Array
 			if err := (&arr).Scan(b); err != nil {
@@ -783,6 +829,20 @@ func decodeOidDatum(id oid.Oid, code formatCode, b []byte) (parser.Datum, error)
 			}
 			i := int32(binary.BigEndian.Uint32(b))
 			return pgBinaryToDate(i), nil
+		case oid.T_time:
+			if len(b) < 8 {
+				return nil, errors.Errorf("time requires 8 bytes for binary format")
+			}
+			t := parser.DTime(int64(binary.BigEndian.Uint64(b)))
+			return &t, nil
+		case oid.T_timetz:
+			if len(b) < 12 {
+				return nil, errors.Errorf("timetz requires 12 bytes for binary format")
+			}
+			return &parser.DTimeTZ{
+				Time:       parser.DTime(int64(binary.BigEndian.Uint64(b))),
+				OffsetSecs: -int32(binary.BigEndian.Uint32(b[8:])),
+			}, nil
 		case oid.T_uuid:
 			u, err := parser.ParseDUuidFromBytes(b)
 			if err != nil {
@@ -795,6 +855,15 @@ func decodeOidDatum(id oid.Oid, code formatCode, b []byte) (parser.Datum, error)
 				return nil, err
 			}
 			return parser.NewDIPAddr(parser.DIPAddr{IPAddr: ipAddr}), nil
+		case oid.T_jsonb:
+			if len(b) < 1 || b[0] != pgBinaryJSONBVersion {
+				return nil, errors.Errorf("unsupported jsonb binary format version")
+			}
+			d, err := parser.ParseDJSON(string(b[1:]))
+			if err != nil {
+				return nil, errors.Errorf("could not parse string %q as jsonb", b[1:])
+			}
+			return d, nil
 		case oid.T__int2, oid.T__int4, oid.T__int8, oid.T__text, oid.T__name:
 			return decodeBinaryArray(b, code)
 		}
@@ -76,6 +76,7 @@ const (
 	serverMsgParameterDescription serverMessageType = 't'
 	serverMsgParameterStatus      serverMessageType = 'S'
 	serverMsgParseComplete        serverMessageType = '1'
+	serverMsgPortalSuspended      serverMessageType = 's'
 	serverMsgReady                serverMessageType = 'Z'
 	serverMsgRowDescription       serverMessageType = 'T'
 )
@@ -207,8 +208,11 @@ type streamingState struct {
 	formatCodes     []formatCode
 	sendDescription bool
 	limit           int
-	emptyQuery      bool
-	err             error
+	// suspendable is set when the execution of the current portal is
+	// suspended, rather than completed, once limit rows are returned.
+	suspendable bool
+	emptyQuery  bool
+	err         error
 
 	// hasSentResults is set if any results have been sent on the client
 	// connection since the last time Close() or Flush() were called. This is used
@@ -243,6 +247,7 @@ func (s *streamingState) reset(formatCodes []formatCode, sendDescription bool, l
 	s.formatCodes = formatCodes
 	s.sendDescription = sendDescription
 	s.limit = limit
+	s.suspendable = false
 	s.emptyQuery = false
 	s.hasSentResults = false
 	s.txnStartIdx = 0
@@ -955,7 +960,12 @@ func (c *v3Conn) handleExecute(buf *readBuffer) error {
 	tracing.AnnotateTrace()
 	c.streamingState.reset(portalMeta.outFormats, false /* sendDescription */, int(limit))
 	c.session.ResultsWriter = c
-	err = c.executor.ExecutePreparedStatement(c.session, stmt, pinfo)
+	if limit != 0 && c.session.CanSuspendPortal(portal) {
+		c.streamingState.suspendable = true
+		err = c.executor.ExecutePortal(c.session, portal, portalName, pinfo, int(limit))
+	} else {
+		err = c.executor.ExecutePreparedStatement(c.session, stmt, pinfo)
+	}
 	if err != nil {
 		if err := c.setError(err); err != nil {
 			return err
@@ -1271,6 +1281,13 @@ func (c *v3Conn) CloseResult() error {
 			}
 		}
 
+		if state.suspendable && state.rowsAffected == limit {
+			// There may be more rows, returned by the next execution of the
+			// portal.
+			c.writeBuf.initMsg(serverMsgPortalSuspended)
+			return c.writeBuf.finishMsg(&state.buf)
+		}
+
 		tag = append(tag, ' ')
 		tag = strconv.AppendUint(tag, uint64(state.rowsAffected), 10)
 		return c.sendCommandComplete(tag, &state.buf)
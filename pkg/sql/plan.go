@@ -137,17 +137,24 @@ type planNodeFastPath interface {
 	FastPathResults() (int, bool)
 }
 
+var _ planNode = &alterSequenceNode{}
 var _ planNode = &alterTableNode{}
 var _ planNode = &copyNode{}
 var _ planNode = &createDatabaseNode{}
 var _ planNode = &createIndexNode{}
+var _ planNode = &createPolicyNode{}
+var _ planNode = &createSequenceNode{}
+var _ planNode = &createStatsNode{}
 var _ planNode = &createTableNode{}
 var _ planNode = &createViewNode{}
+var _ planNode = &declareCursorNode{}
 var _ planNode = &delayedNode{}
 var _ planNode = &deleteNode{}
 var _ planNode = &distinctNode{}
 var _ planNode = &dropDatabaseNode{}
 var _ planNode = &dropIndexNode{}
+var _ planNode = &dropPolicyNode{}
+var _ planNode = &dropSequenceNode{}
 var _ planNode = &dropTableNode{}
 var _ planNode = &dropViewNode{}
 var _ planNode = &zeroNode{}
@@ -155,6 +162,7 @@ var _ planNode = &unaryNode{}
 var _ planNode = &explainDistSQLNode{}
 var _ planNode = &explainPlanNode{}
 var _ planNode = &traceNode{}
+var _ planNode = &fetchNode{}
 var _ planNode = &filterNode{}
 var _ planNode = &groupNode{}
 var _ planNode = &hookFnNode{}
@@ -163,6 +171,7 @@ var _ planNode = &insertNode{}
 var _ planNode = &joinNode{}
 var _ planNode = &limitNode{}
 var _ planNode = &ordinalityNode{}
+var _ planNode = &recursiveCTENode{}
 var _ planNode = &testingRelocateNode{}
 var _ planNode = &renderNode{}
 var _ planNode = &scanNode{}
@@ -176,6 +185,7 @@ var _ planNode = &updateNode{}
 var _ planNode = &valueGenerator{}
 var _ planNode = &valuesNode{}
 var _ planNode = &windowNode{}
+var _ planNode = &workTableScanNode{}
 var _ planNode = &createUserNode{}
 var _ planNode = &dropUserNode{}
 
@@ -324,6 +334,8 @@ func (p *planner) newPlan(
 	}
 
 	switch n := stmt.(type) {
+	case *parser.AlterSequence:
+		return p.AlterSequence(ctx, n)
 	case *parser.AlterTable:
 		return p.AlterTable(ctx, n)
 	case *parser.BeginTransaction:
@@ -332,6 +344,8 @@ func (p *planner) newPlan(
 		return p.CancelQuery(ctx, n)
 	case *parser.CancelJob:
 		return p.CancelJob(ctx, n)
+	case *parser.CloseCursor:
+		return p.CloseCursor(ctx, n)
 	case CopyDataBlock:
 		return p.CopyData(ctx, n)
 	case *parser.CopyFrom:
@@ -340,14 +354,24 @@ func (p *planner) newPlan(
 		return p.CreateDatabase(n)
 	case *parser.CreateIndex:
 		return p.CreateIndex(ctx, n)
+	case *parser.CreatePolicy:
+		return p.CreatePolicy(ctx, n)
+	case *parser.CreateSequence:
+		return p.CreateSequence(ctx, n)
+	case *parser.CreateStats:
+		return p.CreateStatistics(ctx, n)
 	case *parser.CreateTable:
 		return p.CreateTable(ctx, n)
+	case *parser.CreateRole:
+		return p.CreateRole(ctx, n)
 	case *parser.CreateUser:
 		return p.CreateUser(ctx, n)
 	case *parser.CreateView:
 		return p.CreateView(ctx, n)
 	case *parser.Deallocate:
 		return p.Deallocate(ctx, n)
+	case *parser.DeclareCursor:
+		return p.DeclareCursor(ctx, n)
 	case *parser.Delete:
 		return p.Delete(ctx, n, desiredTypes)
 	case *parser.Discard:
@@ -356,24 +380,38 @@ func (p *planner) newPlan(
 		return p.DropDatabase(ctx, n)
 	case *parser.DropIndex:
 		return p.DropIndex(ctx, n)
+	case *parser.DropPolicy:
+		return p.DropPolicy(ctx, n)
+	case *parser.DropSequence:
+		return p.DropSequence(ctx, n)
 	case *parser.DropTable:
 		return p.DropTable(ctx, n)
 	case *parser.DropView:
 		return p.DropView(ctx, n)
+	case *parser.DropRole:
+		return p.DropRole(ctx, n)
 	case *parser.DropUser:
 		return p.DropUser(ctx, n)
 	case *parser.Execute:
 		return p.Execute(ctx, n)
 	case *parser.Explain:
 		return p.Explain(ctx, n)
+	case *parser.FetchCursor:
+		return p.FetchCursor(ctx, n)
 	case *parser.Grant:
 		return p.Grant(ctx, n)
+	case *parser.GrantRole:
+		return p.GrantRole(ctx, n)
 	case *parser.Insert:
 		return p.Insert(ctx, n, desiredTypes)
+	case *parser.MoveCursor:
+		return p.MoveCursor(ctx, n)
 	case *parser.ParenSelect:
 		return p.newPlan(ctx, n.Select, desiredTypes)
 	case *parser.PauseJob:
 		return p.PauseJob(ctx, n)
+	case *portalFetch:
+		return p.portalFetch(ctx, n)
 	case *parser.TestingRelocate:
 		return p.TestingRelocate(ctx, n)
 	case *parser.RenameColumn:
@@ -388,6 +426,8 @@ func (p *planner) newPlan(
 		return p.ResumeJob(ctx, n)
 	case *parser.Revoke:
 		return p.Revoke(ctx, n)
+	case *parser.RevokeRole:
+		return p.RevokeRole(ctx, n)
 	case *parser.Scatter:
 		return p.Scatter(ctx, n)
 	case *parser.Select:
@@ -428,6 +468,8 @@ func (p *planner) newPlan(
 		return p.ShowSessions(ctx, n)
 	case *parser.ShowTables:
 		return p.ShowTables(ctx, n)
+	case *parser.ShowTableStats:
+		return p.ShowTableStats(ctx, n)
 	case *parser.ShowTrace:
 		return p.ShowTrace(ctx, n)
 	case *parser.ShowTransactionStatus:
@@ -514,6 +556,8 @@ func (p *planner) prepare(ctx context.Context, stmt parser.Statement) (planNode,
 		return p.ShowSessions(ctx, n)
 	case *parser.ShowTables:
 		return p.ShowTables(ctx, n)
+	case *parser.ShowTableStats:
+		return p.ShowTableStats(ctx, n)
 	case *parser.ShowTrace:
 		return p.ShowTrace(ctx, n)
 	case *parser.ShowUsers:
@@ -53,6 +53,8 @@ func getPlanColumns(plan planNode, mut bool) sqlbase.ResultColumns {
 		return n.resultColumns
 	case *delayedNode:
 		return n.columns
+	case *fetchNode:
+		return n.columns
 	case *groupNode:
 		return n.columns
 	case *hookFnNode:
@@ -75,6 +77,10 @@ func getPlanColumns(plan planNode, mut bool) sqlbase.ResultColumns {
 		return n.results.columns
 	case *windowNode:
 		return n.values.columns
+	case *recursiveCTENode:
+		return n.columns
+	case *workTableScanNode:
+		return n.columns
 	case *traceNode:
 		return n.columns
 
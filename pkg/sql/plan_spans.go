@@ -35,6 +35,7 @@ func collectSpans(params runParams, plan planNode) (reads, writes roachpb.Spans,
 	case
 		*valueGenerator,
 		*valuesNode,
+		*workTableScanNode,
 		*zeroNode,
 		*unaryNode:
 		return nil, nil, nil
@@ -87,6 +88,16 @@ func collectSpans(params runParams, plan planNode) (reads, writes roachpb.Spans,
 		return concatSpans(params, n.left.plan, n.right.plan)
 	case *unionNode:
 		return concatSpans(params, n.left, n.right)
+
+	case *recursiveCTENode:
+		// The recursive term is only planned during execution, so we
+		// conservatively assume that it may read anything.
+		reads, writes, err := collectSpans(params, n.initial)
+		if err != nil {
+			return nil, nil, err
+		}
+		reads = append(reads, roachpb.Span{Key: roachpb.KeyMin, EndKey: roachpb.KeyMax})
+		return reads, writes, nil
 	}
 
 	panic(fmt.Sprintf("don't know how to collect spans for node %T", plan))
@@ -125,7 +136,9 @@ func tableWriterSpans(params runParams, tw tableWriter) (reads, writes roachpb.S
 	// conservative and assume anything in the table might change.
 	tableSpans := tw.tableDesc().AllIndexSpans()
 	fkReads := tw.fkSpanCollector().CollectSpans()
-	return fkReads, tableSpans
+	// Cascading foreign key actions may modify the other tables too.
+	cascadeWrites := tw.fkSpanCollector().CollectCascadeSpans()
+	return fkReads, append(tableSpans, cascadeWrites...)
 }
 
 // insertNodeWithValuesSpans is a special case of editNodeSpans. It tightens the
@@ -68,6 +68,10 @@ type planner struct {
 	// initializing plans to read from a table. This should be used with care.
 	skipSelectPrivilegeChecks bool
 
+	// sessionUserRoles caches the roles the session user is a member of for
+	// the duration of a statement. See sessionUserMemberOf.
+	sessionUserRoles map[string]bool
+
 	// autoCommit indicates whether we're planning for a spontaneous transaction.
 	// If autoCommit is true, the plan is allowed (but not required) to
 	// commit the transaction along with other KV operations.
@@ -90,6 +94,10 @@ type planner struct {
 	// TODO(knz): Remove this in favor of a better encapsulated mechanism.
 	planDeps planDependencies
 
+	// ctes contains the common table expressions (WITH clauses) that are
+	// visible to the statement currently being planned.
+	ctes cteNameEnvironment
+
 	// hasStar collects whether any star expansion has occurred during
 	// logical plan construction. This is used by CREATE VIEW until
 	// #10028 is addressed.
@@ -299,7 +307,10 @@ func (p *planner) exec(ctx context.Context, sql string, args ...interface{}) (in
 }
 
 func (p *planner) fillFKTableMap(ctx context.Context, m sqlbase.TableLookupsByID) error {
-	for tableID := range m {
+	for tableID, lookup := range m {
+		if lookup.Table != nil || lookup.IsAdding {
+			continue
+		}
 		table, err := p.session.tables.getTableVersionByID(ctx, p.txn, tableID)
 		if err == errTableAdding {
 			m[tableID] = sqlbase.TableLookup{IsAdding: true}
@@ -313,6 +324,24 @@ func (p *planner) fillFKTableMap(ctx context.Context, m sqlbase.TableLookupsByID
 	return nil
 }
 
+// lookupFKTables returns the tables needed to check the foreign keys of the
+// rows of table changed as per usage, including the tables reached through
+// the cascading actions of these foreign keys.
+func (p *planner) lookupFKTables(
+	ctx context.Context, table *sqlbase.TableDescriptor, usage sqlbase.FKCheck,
+) (sqlbase.TableLookupsByID, error) {
+	fkTables := sqlbase.TablesNeededForFKs(*table, usage)
+	for {
+		if err := p.fillFKTableMap(ctx, fkTables); err != nil {
+			return nil, err
+		}
+		added, err := sqlbase.TablesNeededForCascades(table, usage, fkTables)
+		if err != nil || !added {
+			return fkTables, err
+		}
+	}
+}
+
 // isDatabaseVisible returns true if the given database is visible
 // given the provided prefix.
 // An empty prefix makes all databases visible.
@@ -0,0 +1,313 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+type createPolicyNode struct {
+	n         *parser.CreatePolicy
+	tableDesc *sqlbase.TableDescriptor
+	policy    sqlbase.TableDescriptor_Policy
+}
+
+// CreatePolicy adds a row-level security policy to a table.
+// Privileges: CREATE on table.
+//   notes: postgres requires being the owner of the table.
+func (p *planner) CreatePolicy(ctx context.Context, n *parser.CreatePolicy) (planNode, error) {
+	tableDesc, err := p.getTableDescForPolicy(ctx, &n.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	name := n.Name.Normalize()
+	if name == "" {
+		return nil, errors.New("no policy name specified")
+	}
+	if tableDesc.FindPolicyByName(name) != -1 {
+		return nil, pgerror.NewErrorf(pgerror.CodeDuplicateObjectError,
+			"policy %q for table %q already exists", name, tableDesc.Name)
+	}
+
+	policy := sqlbase.TableDescriptor_Policy{Name: name}
+	if policy.Roles, err = p.checkPolicyRoles(ctx, n.Roles); err != nil {
+		return nil, err
+	}
+	if n.Using != nil {
+		if err := validatePolicyExpr(tableDesc, n.Using, "USING", p.session.SearchPath); err != nil {
+			return nil, err
+		}
+		policy.UsingExpr = parser.Serialize(n.Using)
+	}
+	if n.Check != nil {
+		if err := validatePolicyExpr(tableDesc, n.Check, "WITH CHECK", p.session.SearchPath); err != nil {
+			return nil, err
+		}
+		policy.CheckExpr = parser.Serialize(n.Check)
+	}
+	return &createPolicyNode{n: n, tableDesc: tableDesc, policy: policy}, nil
+}
+
+func (n *createPolicyNode) Start(params runParams) error {
+	n.tableDesc.Policies = append(n.tableDesc.Policies, n.policy)
+	return params.p.writePolicyChange(params.ctx, n.tableDesc, n.n)
+}
+
+func (*createPolicyNode) Next(runParams) (bool, error) { return false, nil }
+func (*createPolicyNode) Close(context.Context)        {}
+func (*createPolicyNode) Values() parser.Datums        { return parser.Datums{} }
+
+type dropPolicyNode struct {
+	n         *parser.DropPolicy
+	tableDesc *sqlbase.TableDescriptor
+	idx       int
+}
+
+// DropPolicy removes a row-level security policy from a table.
+// Privileges: CREATE on table.
+//   notes: postgres requires being the owner of the table.
+func (p *planner) DropPolicy(ctx context.Context, n *parser.DropPolicy) (planNode, error) {
+	tableDesc, err := p.getTableDescForPolicy(ctx, &n.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	name := n.Name.Normalize()
+	idx := tableDesc.FindPolicyByName(name)
+	if idx == -1 {
+		if n.IfExists {
+			return &zeroNode{}, nil
+		}
+		return nil, pgerror.NewErrorf(pgerror.CodeUndefinedObjectError,
+			"policy %q for table %q does not exist", name, tableDesc.Name)
+	}
+	return &dropPolicyNode{n: n, tableDesc: tableDesc, idx: idx}, nil
+}
+
+func (n *dropPolicyNode) Start(params runParams) error {
+	policies := n.tableDesc.Policies
+	n.tableDesc.Policies = append(policies[:n.idx:n.idx], policies[n.idx+1:]...)
+	return params.p.writePolicyChange(params.ctx, n.tableDesc, n.n)
+}
+
+func (*dropPolicyNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropPolicyNode) Close(context.Context)        {}
+func (*dropPolicyNode) Values() parser.Datums        { return parser.Datums{} }
+
+// getTableDescForPolicy resolves the table a policy is created on or dropped
+// from, and checks that the session user can change its policies.
+func (p *planner) getTableDescForPolicy(
+	ctx context.Context, table *parser.NormalizableTableName,
+) (*sqlbase.TableDescriptor, error) {
+	tn, err := table.NormalizeWithDatabaseName(p.session.Database)
+	if err != nil {
+		return nil, err
+	}
+	tableDesc, err := getTableDesc(ctx, p.txn, p.getVirtualTabler(), tn)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc == nil {
+		return nil, sqlbase.NewUndefinedRelationError(tn)
+	}
+	if err := p.CheckPrivilege(tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	return tableDesc, nil
+}
+
+// writePolicyChange writes the descriptor of a table whose policies were
+// changed by stmt.
+func (p *planner) writePolicyChange(
+	ctx context.Context, tableDesc *sqlbase.TableDescriptor, stmt parser.Statement,
+) error {
+	if err := tableDesc.SetUpVersion(); err != nil {
+		return err
+	}
+	if err := p.writeTableDesc(ctx, tableDesc); err != nil {
+		return err
+	}
+
+	// Record this policy change in the event log. This is an auditable log
+	// event and is recorded in the same transaction as the table descriptor
+	// update.
+	if err := MakeEventLogger(p.LeaseMgr()).InsertEventRecord(
+		ctx,
+		p.txn,
+		EventLogAlterTable,
+		int32(tableDesc.ID),
+		int32(p.evalCtx.NodeID),
+		struct {
+			TableName string
+			Statement string
+			User      string
+		}{tableDesc.Name, stmt.String(), p.session.User},
+	); err != nil {
+		return err
+	}
+
+	p.notifySchemaChange(tableDesc, sqlbase.InvalidMutationID)
+	return nil
+}
+
+// checkPolicyRoles verifies that the roles a policy applies to are existing
+// users or roles, or the public pseudo-role, and returns their normalized
+// names.
+func (p *planner) checkPolicyRoles(ctx context.Context, roles parser.NameList) ([]string, error) {
+	const getUser = `SELECT username FROM system.users WHERE username = $1`
+	internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+	var names []string
+	for _, role := range roles {
+		name := role.Normalize()
+		if name != sqlbase.PublicRole && name != security.RootUser {
+			row, err := internalExecutor.QueryRowInTransaction(ctx, "check-role", p.txn, getUser, name)
+			if err != nil {
+				return nil, err
+			}
+			if row == nil {
+				return nil, errors.Errorf("user or role %s does not exist", name)
+			}
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// validatePolicyExpr checks that expr, the expression of the given clause of
+// a policy on tableDesc, is a boolean expression over the columns of the
+// table, without subqueries, aggregates or window functions.
+func validatePolicyExpr(
+	tableDesc *sqlbase.TableDescriptor, expr parser.Expr, clause string, searchPath parser.SearchPath,
+) error {
+	preFn := func(expr parser.Expr) (err error, recurse bool, newExpr parser.Expr) {
+		switch t := expr.(type) {
+		case *parser.Subquery:
+			return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"%s expression of policy cannot contain subqueries", clause), false, nil
+		case parser.VarName:
+			v, err := t.NormalizeVarName()
+			if err != nil {
+				return err, false, nil
+			}
+			c, ok := v.(*parser.ColumnItem)
+			if !ok {
+				return nil, true, expr
+			}
+			col, err := tableDesc.FindActiveColumnByName(string(c.ColumnName))
+			if err != nil {
+				return pgerror.NewErrorf(pgerror.CodeUndefinedColumnError,
+					"column %q not found for policy", c.ColumnName), false, nil
+			}
+			// Convert to a dummy node of the correct type.
+			return nil, false, dummyColumnItem{col.Type.ToDatumType()}
+		}
+		return nil, true, expr
+	}
+	expr, err := parser.SimpleVisit(expr, preFn)
+	if err != nil {
+		return err
+	}
+
+	var p parser.Parser
+	if err := p.AssertNoAggregationOrWindowing(expr, "policy expressions", searchPath); err != nil {
+		return err
+	}
+	_, err = sqlbase.SanitizeVarFreeExpr(expr, parser.TypeBool, clause, searchPath)
+	return err
+}
+
+// policyPredicate returns the predicate restricting the rows of tableDesc the
+// session user can access, or nil when row-level security doesn't apply to
+// the user. The predicate lets through the rows accepted by one of the
+// policies applying to the user, using the USING expressions of the policies
+// for the rows read and their WITH CHECK expressions, or USING by default,
+// for the rows written. Without such policies, no row is accessible.
+func (p *planner) policyPredicate(
+	ctx context.Context, tableDesc *sqlbase.TableDescriptor, forWrite bool,
+) (parser.Expr, error) {
+	if !tableDesc.RowLevelSecurityApplies(p.session.User) {
+		return nil, nil
+	}
+	memberOf, err := p.sessionUserMemberOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pred parser.Expr
+	for i := range tableDesc.Policies {
+		policy := &tableDesc.Policies[i]
+		if !policy.AppliesTo(p.session.User, memberOf) {
+			continue
+		}
+		exprStr := policy.UsingExpr
+		if forWrite && policy.CheckExpr != "" {
+			exprStr = policy.CheckExpr
+		}
+		if exprStr == "" {
+			continue
+		}
+		expr, err := parser.ParseExpr(exprStr)
+		if err != nil {
+			return nil, err
+		}
+		if pred == nil {
+			pred = &parser.ParenExpr{Expr: expr}
+		} else {
+			pred = &parser.OrExpr{Left: pred, Right: &parser.ParenExpr{Expr: expr}}
+		}
+	}
+	if pred == nil {
+		return parser.DBoolFalse, nil
+	}
+	return pred, nil
+}
+
+// restrictScanToPolicies restricts the rows read by scan, for the table
+// named tn, to those the session user can access according to the policies
+// of the table.
+func (p *planner) restrictScanToPolicies(
+	ctx context.Context, scan *scanNode, tn *parser.TableName,
+) error {
+	pred, err := p.policyPredicate(ctx, scan.desc, false /* forWrite */)
+	if err != nil || pred == nil {
+		return err
+	}
+	// The policies can refer to columns the user has no SELECT privilege on.
+	cols := make(sqlbase.ResultColumns, len(scan.resultColumns))
+	copy(cols, scan.resultColumns)
+	for i := range cols {
+		cols[i].Unreadable = false
+	}
+	filter, err := p.analyzeExpr(ctx, pred,
+		multiSourceInfo{newSourceInfoForSingleTable(*tn, cols)},
+		scan.filterVars, parser.TypeBool, true /* requireType */, "row-level security policy")
+	if err != nil {
+		return err
+	}
+	scan.filter = mergeConj(scan.filter, filter)
+	return nil
+}
+
+func newPolicyViolationError(tableName string) error {
+	return pgerror.NewErrorf(pgerror.CodeInsufficientPrivilegeError,
+		"new row violates row-level security policy for table %q", tableName)
+}
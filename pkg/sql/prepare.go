@@ -196,7 +196,7 @@ func (ps PreparedStatements) Delete(ctx context.Context, name string) bool {
 			for portalName := range stmt.portalNames {
 				if portal, ok := ps.session.PreparedPortals.Get(name); ok {
 					delete(ps.session.PreparedPortals.portals, portalName)
-					portal.memAcc.Wsession(ps.session).Close(ctx)
+					portal.close(ctx, ps.session)
 				}
 			}
 		}
@@ -213,7 +213,7 @@ func (ps PreparedStatements) closeAll(ctx context.Context, s *Session) {
 		stmt.close(ctx, s)
 	}
 	for _, portal := range s.PreparedPortals.portals {
-		portal.memAcc.Wsession(s).Close(ctx)
+		portal.close(ctx, s)
 	}
 }
 
@@ -242,9 +242,20 @@ type PreparedPortal struct {
 
 	ProtocolMeta interface{} // a field for protocol implementations to hang metadata off of.
 
+	// cursor runs the statement when the portal is executed with a row limit;
+	// see Executor.ExecutePortal.
+	cursor *sqlCursor
+
 	memAcc WrappableMemoryAccount
 }
 
+func (p *PreparedPortal) close(ctx context.Context, s *Session) {
+	if p.cursor != nil {
+		s.TxnState.closeCursor(ctx, p.cursor)
+	}
+	p.memAcc.Wsession(s).Close(ctx)
+}
+
 // PreparedPortals is a mapping of PreparedPortal names to their corresponding
 // PreparedPortals.
 type PreparedPortals struct {
@@ -288,7 +299,7 @@ func (pp PreparedPortals) New(
 	stmt.portalNames[name] = struct{}{}
 
 	if prevPortal, ok := pp.Get(name); ok {
-		prevPortal.memAcc.Wsession(pp.session).Close(ctx)
+		prevPortal.close(ctx, pp.session)
 	}
 
 	pp.portals[name] = portal
@@ -300,7 +311,7 @@ func (pp PreparedPortals) New(
 func (pp PreparedPortals) Delete(ctx context.Context, name string) bool {
 	if portal, ok := pp.Get(name); ok {
 		delete(portal.Stmt.portalNames, name)
-		portal.memAcc.Wsession(pp.session).Close(ctx)
+		portal.close(ctx, pp.session)
 		delete(pp.portals, name)
 		return true
 	}
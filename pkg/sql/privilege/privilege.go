@@ -45,6 +45,8 @@ const (
 var (
 	ReadData      = List{GRANT, SELECT}
 	ReadWriteData = List{GRANT, SELECT, INSERT, DELETE, UPDATE}
+	// ColumnData is the set of privileges that can be granted on columns.
+	ColumnData = List{SELECT, INSERT, UPDATE}
 )
 
 // Mask returns the bitmask for a given privilege.
@@ -57,6 +59,18 @@ var ByValue = [...]Kind{
 	ALL, CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE,
 }
 
+// ByName is a map of privilege names to privilege kinds.
+var ByName = map[string]Kind{
+	"ALL":    ALL,
+	"CREATE": CREATE,
+	"DROP":   DROP,
+	"GRANT":  GRANT,
+	"SELECT": SELECT,
+	"INSERT": INSERT,
+	"DELETE": DELETE,
+	"UPDATE": UPDATE,
+}
+
 // List is a list of privileges.
 type List []Kind
 
@@ -125,6 +139,21 @@ func (pl List) ToBitField() uint32 {
 	return ret
 }
 
+// ListFromStrings takes a list of privilege names, in any case, and returns
+// the corresponding list of privileges. It errors if a name is not a
+// privilege.
+func ListFromStrings(strs []string) (List, error) {
+	ret := make(List, len(strs))
+	for i, s := range strs {
+		k, ok := ByName[strings.ToUpper(s)]
+		if !ok {
+			return nil, fmt.Errorf("not a valid privilege: %q", s)
+		}
+		ret[i] = k
+	}
+	return ret, nil
+}
+
 // ListFromBitField takes a bitfield of privileges and
 // returns a list. It is ordered in increasing
 // value of privilege.Kind.
@@ -0,0 +1,348 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// recursiveCTENode evaluates a recursive common table expression of
+// the form:
+//
+//   WITH RECURSIVE name AS (initial UNION [ALL] recursive) ...
+//
+// The rows of the initial (non-recursive) term are emitted first and
+// collected into a "work table". The recursive term is then planned
+// and run repeatedly: in each iteration, references to the CTE from the
+// recursive term read the rows of the work table, that is the rows
+// produced by the previous iteration, and the rows produced become the
+// work table for the next iteration. Evaluation stops when an iteration
+// produces no rows.
+//
+// For UNION (as opposed to UNION ALL), rows that were already emitted
+// are discarded and not added to the work table.
+//
+// The work tables are disk-backed row containers, and the rows emitted
+// for UNION are tracked in a disk-backed key set, so that neither the
+// amount of rows produced by a single iteration nor the amount of
+// distinct rows produced overall is bounded by the memory budget.
+type recursiveCTENode struct {
+	p    *planner
+	name string
+
+	columns sqlbase.ResultColumns
+
+	// initial is the plan for the non-recursive term.
+	initial planNode
+	// recursive is the recursive term. It is re-planned for every
+	// iteration in the name environment env.
+	recursive *parser.Select
+	env       cteNameEnvironment
+	unionAll  bool
+
+	// workTableRefs is the number of references to the CTE from the
+	// recursive term, counted while planning it.
+	workTableRefs int
+
+	run recursiveCTERun
+}
+
+// recursiveCTERun contains the run-time state of recursiveCTENode
+// during local execution.
+type recursiveCTERun struct {
+	// plan is the plan currently producing rows: either the initial plan
+	// or the plan for the current iteration of the recursive term. It is
+	// nil in between iterations.
+	plan planNode
+
+	// workTable holds the rows produced by the previous iteration, which
+	// are read by the current iteration. nextWorkTable collects the rows
+	// produced by the current iteration.
+	workTable     *distsqlrun.DiskBackedRowContainer
+	nextWorkTable *distsqlrun.DiskBackedRowContainer
+
+	colTypes []sqlbase.ColumnType
+	scratch  sqlbase.EncDatumRow
+
+	// seen contains the encoding of every row emitted so far, for UNION.
+	seen   *distsqlrun.DiskBackedKeySet
+	keyBuf []byte
+}
+
+// desiredTypes returns the types of the columns of the non-recursive term,
+// used as desired types when planning the recursive term.
+func (n *recursiveCTENode) desiredTypes() []parser.Type {
+	types := make([]parser.Type, len(n.columns))
+	for i, col := range n.columns {
+		types[i] = col.Typ
+	}
+	return types
+}
+
+// mergeColumnTypes checks that the columns produced by the recursive term
+// are compatible with those of the non-recursive term. Columns of the
+// non-recursive term that only contain NULL take the type of the
+// recursive term.
+func (n *recursiveCTENode) mergeColumnTypes(recColumns sqlbase.ResultColumns) error {
+	if len(recColumns) != len(n.columns) {
+		return pgerror.NewErrorf(pgerror.CodeSyntaxError,
+			"each UNION query must have the same number of columns: %d vs %d",
+			len(n.columns), len(recColumns))
+	}
+	for i := range n.columns {
+		l, r := n.columns[i].Typ, recColumns[i].Typ
+		if l == parser.TypeNull {
+			n.columns[i].Typ = r
+			continue
+		}
+		if !(l.Equivalent(r) || r == parser.TypeNull) {
+			return pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+				"recursive query %q column %d has type %s in non-recursive term but type %s in recursive term",
+				n.name, i+1, l, r)
+		}
+	}
+	return nil
+}
+
+// newWorkTableScan returns a planNode that reads the work table of the
+// recursive CTE. It is called when planning a reference to the CTE from
+// its recursive term.
+func (n *recursiveCTENode) newWorkTableScan() (planNode, error) {
+	n.workTableRefs++
+	if n.workTableRefs > 1 {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+			"recursive reference to query %q must not appear more than once", n.name)
+	}
+	return &workTableScanNode{cte: n, columns: n.columns}, nil
+}
+
+func (n *recursiveCTENode) Start(params runParams) error {
+	if err := n.initial.Start(params); err != nil {
+		return err
+	}
+	n.run.plan = n.initial
+
+	n.run.colTypes = make([]sqlbase.ColumnType, len(n.columns))
+	for i, col := range n.columns {
+		colTyp, err := sqlbase.DatumTypeToColumnType(col.Typ)
+		if err != nil {
+			return err
+		}
+		n.run.colTypes[i] = colTyp
+	}
+	n.run.scratch = make(sqlbase.EncDatumRow, len(n.columns))
+	n.run.nextWorkTable = params.p.newDiskBackedRowContainer(params.ctx, n.run.colTypes)
+
+	if !n.unionAll {
+		n.run.seen = params.p.newDiskBackedKeySet(params.ctx)
+	}
+	return nil
+}
+
+func (n *recursiveCTENode) Next(params runParams) (bool, error) {
+	for {
+		if err := params.p.cancelChecker.Check(); err != nil {
+			return false, err
+		}
+
+		if n.run.plan == nil {
+			if n.run.nextWorkTable.Len() == 0 {
+				// The last iteration did not produce any rows: we're done.
+				return false, nil
+			}
+			if err := n.startIteration(params); err != nil {
+				return false, err
+			}
+		}
+
+		next, err := n.run.plan.Next(params)
+		if err != nil {
+			return false, err
+		}
+		if !next {
+			if n.run.plan != n.initial {
+				n.run.plan.Close(params.ctx)
+			}
+			n.run.plan = nil
+			continue
+		}
+
+		values := n.run.plan.Values()
+		if !n.unionAll {
+			n.run.keyBuf, err = sqlbase.EncodeDatums(n.run.keyBuf[:0], values)
+			if err != nil {
+				return false, err
+			}
+			added, err := n.run.seen.Add(params.ctx, n.run.keyBuf)
+			if err != nil {
+				return false, err
+			}
+			if !added {
+				continue
+			}
+		}
+
+		for i, v := range values {
+			n.run.scratch[i] = sqlbase.DatumToEncDatum(n.run.colTypes[i], v)
+		}
+		if err := n.run.nextWorkTable.AddRow(params.ctx, n.run.scratch); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+}
+
+// startIteration makes the rows produced by the previous iteration the
+// current work table, and plans and starts the recursive term for a new
+// iteration.
+func (n *recursiveCTENode) startIteration(params runParams) error {
+	if n.run.workTable != nil {
+		n.run.workTable.Close(params.ctx)
+	}
+	n.run.workTable = n.run.nextWorkTable
+	n.run.nextWorkTable = params.p.newDiskBackedRowContainer(params.ctx, n.run.colTypes)
+
+	p := params.p
+	defer func(prev cteNameEnvironment) { p.ctes = prev }(p.ctes)
+	p.ctes = n.env
+	n.workTableRefs = 0
+
+	plan, err := p.newPlan(params.ctx, n.recursive, n.desiredTypes())
+	if err != nil {
+		return err
+	}
+	plan, err = p.optimizePlan(params.ctx, plan, allColumns(plan))
+	if err != nil {
+		plan.Close(params.ctx)
+		return err
+	}
+	if err := p.startPlan(params.ctx, plan); err != nil {
+		plan.Close(params.ctx)
+		return err
+	}
+	n.run.plan = plan
+	return nil
+}
+
+func (n *recursiveCTENode) Values() parser.Datums {
+	return n.run.plan.Values()
+}
+
+func (n *recursiveCTENode) Close(ctx context.Context) {
+	if n.run.plan != nil && n.run.plan != n.initial {
+		n.run.plan.Close(ctx)
+	}
+	n.run.plan = nil
+	n.initial.Close(ctx)
+	if n.run.workTable != nil {
+		n.run.workTable.Close(ctx)
+		n.run.workTable = nil
+	}
+	if n.run.nextWorkTable != nil {
+		n.run.nextWorkTable.Close(ctx)
+		n.run.nextWorkTable = nil
+	}
+	if n.run.seen != nil {
+		n.run.seen.Close(ctx)
+		n.run.seen = nil
+	}
+}
+
+// workTableScanNode reads the work table of a recursiveCTENode, that is
+// the rows produced by the previous iteration of the recursive CTE.
+type workTableScanNode struct {
+	cte     *recursiveCTENode
+	columns sqlbase.ResultColumns
+
+	run struct {
+		iter   distsqlrun.DiskBackedRowIterator
+		active bool
+		values parser.Datums
+		alloc  sqlbase.DatumAlloc
+	}
+}
+
+func (n *workTableScanNode) Start(params runParams) error {
+	n.run.iter = n.cte.run.workTable.NewIterator(params.ctx)
+	n.run.active = true
+	n.run.iter.Rewind()
+	n.run.values = make(parser.Datums, len(n.columns))
+	return nil
+}
+
+func (n *workTableScanNode) Next(params runParams) (bool, error) {
+	if !n.run.active {
+		return false, nil
+	}
+	if ok, err := n.run.iter.Valid(); err != nil || !ok {
+		return false, err
+	}
+	row, err := n.run.iter.Row()
+	if err != nil {
+		return false, err
+	}
+	for i := range row {
+		if err := row[i].EnsureDecoded(&n.run.alloc); err != nil {
+			return false, err
+		}
+		n.run.values[i] = row[i].Datum
+	}
+	n.run.iter.Next()
+	return true, nil
+}
+
+func (n *workTableScanNode) Values() parser.Datums {
+	return n.run.values
+}
+
+func (n *workTableScanNode) Close(context.Context) {
+	if n.run.active {
+		n.run.iter.Close()
+		n.run.active = false
+	}
+}
+
+// newDiskBackedRowContainer creates a row container for rows of the given
+// types that uses the node's temporary storage when the rows do not fit in
+// memory. Planners that are not associated with a server, such as those
+// used in tests, get a container that only uses memory.
+func (p *planner) newDiskBackedRowContainer(
+	ctx context.Context, types []sqlbase.ColumnType,
+) *distsqlrun.DiskBackedRowContainer {
+	if cfg := p.ExecCfg(); cfg != nil && cfg.DistSQLSrv != nil {
+		return cfg.DistSQLSrv.NewDiskBackedRowContainer(ctx, types, &p.evalCtx)
+	}
+	return distsqlrun.MakeDiskBackedRowContainer(
+		ctx, types, &p.evalCtx, 0 /* memLimit */, nil /* diskMonitor */, nil, /* engine */
+	)
+}
+
+// newDiskBackedKeySet creates a key set that uses the node's temporary
+// storage when the keys do not fit in memory. Like for
+// newDiskBackedRowContainer, planners that are not associated with a server
+// get a set that only uses memory.
+func (p *planner) newDiskBackedKeySet(ctx context.Context) *distsqlrun.DiskBackedKeySet {
+	if cfg := p.ExecCfg(); cfg != nil && cfg.DistSQLSrv != nil {
+		return cfg.DistSQLSrv.NewDiskBackedKeySet(ctx, &p.evalCtx)
+	}
+	return distsqlrun.MakeDiskBackedKeySet(
+		ctx, &p.evalCtx, 0 /* memLimit */, nil /* diskMonitor */, nil, /* engine */
+	)
+}
@@ -125,44 +125,32 @@ func (p *planner) RenameTable(ctx context.Context, n *parser.RenameTable) (planN
 		return nil, err
 	}
 
-	// Check if source table or view exists.
+	// Check if source table, view or sequence exists.
 	// Note that Postgres's behavior here is a little lenient - it'll let you
 	// modify views by running ALTER TABLE, but won't let you modify tables
 	// by running ALTER VIEW. Our behavior is strict for now, but can be
 	// made more lenient down the road if needed.
-	var tableDesc *sqlbase.TableDescriptor
-	if n.IsView {
-		tableDesc, err = getViewDesc(ctx, p.txn, p.getVirtualTabler(), oldTn)
-		if err != nil {
-			return nil, err
-		}
-		if tableDesc == nil {
-			if n.IfExists {
-				// Noop.
-				return &zeroNode{}, nil
-			}
-			// Key does not exist, but we want it to: error out.
-			return nil, sqlbase.NewUndefinedRelationError(oldTn)
-		}
-		if tableDesc.State != sqlbase.TableDescriptor_PUBLIC {
-			return nil, sqlbase.NewUndefinedRelationError(oldTn)
-		}
-	} else {
-		tableDesc, err = getTableDesc(ctx, p.txn, p.getVirtualTabler(), oldTn)
-		if err != nil {
-			return nil, err
-		}
-		if tableDesc == nil {
-			if n.IfExists {
-				// Noop.
-				return &zeroNode{}, nil
-			}
-			// Key does not exist, but we want it to: error out.
-			return nil, sqlbase.NewUndefinedRelationError(oldTn)
-		}
-		if tableDesc.State != sqlbase.TableDescriptor_PUBLIC {
-			return nil, sqlbase.NewUndefinedRelationError(oldTn)
+	getDesc := getTableDesc
+	switch {
+	case n.IsView:
+		getDesc = getViewDesc
+	case n.IsSequence:
+		getDesc = getSequenceDesc
+	}
+	tableDesc, err := getDesc(ctx, p.txn, p.getVirtualTabler(), oldTn)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc == nil {
+		if n.IfExists {
+			// Noop.
+			return &zeroNode{}, nil
 		}
+		// Key does not exist, but we want it to: error out.
+		return nil, sqlbase.NewUndefinedRelationError(oldTn)
+	}
+	if tableDesc.State != sqlbase.TableDescriptor_PUBLIC {
+		return nil, sqlbase.NewUndefinedRelationError(oldTn)
 	}
 
 	if err := p.CheckPrivilege(tableDesc, privilege.DROP); err != nil {
@@ -409,6 +397,59 @@ func (p *planner) RenameColumn(ctx context.Context, n *parser.RenameColumn) (pla
 			tableDesc.Checks[i].Expr = after
 		}
 	}
+	// Rename the column in the expressions of computed columns.
+	renameInComputedExpr := func(c *sqlbase.ColumnDescriptor) error {
+		if !c.IsComputed() {
+			return nil
+		}
+		expr, err := parser.ParseExpr(*c.ComputedExpr)
+		if err != nil {
+			return err
+		}
+		if expr, err = parser.SimpleVisit(expr, preFn); err != nil {
+			return err
+		}
+		if after := expr.String(); after != *c.ComputedExpr {
+			c.ComputedExpr = &after
+		}
+		return nil
+	}
+	for i := range tableDesc.Columns {
+		if err := renameInComputedExpr(&tableDesc.Columns[i]); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range tableDesc.Mutations {
+		if c := m.GetColumn(); c != nil {
+			if err := renameInComputedExpr(c); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Rename the column in the expressions of policies.
+	renameInPolicyExpr := func(exprStr *string) error {
+		if *exprStr == "" {
+			return nil
+		}
+		expr, err := parser.ParseExpr(*exprStr)
+		if err != nil {
+			return err
+		}
+		if expr, err = parser.SimpleVisit(expr, preFn); err != nil {
+			return err
+		}
+		*exprStr = expr.String()
+		return nil
+	}
+	for i := range tableDesc.Policies {
+		policy := &tableDesc.Policies[i]
+		if err := renameInPolicyExpr(&policy.UsingExpr); err != nil {
+			return nil, err
+		}
+		if err := renameInPolicyExpr(&policy.CheckExpr); err != nil {
+			return nil, err
+		}
+	}
 	// Rename the column in the indexes.
 	tableDesc.RenameColumnDescriptor(col, string(n.NewName))
 
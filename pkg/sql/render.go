@@ -136,12 +136,25 @@ func (r *renderNode) IndexedVarFormat(buf *bytes.Buffer, f parser.FmtFlags, idx
 func (p *planner) Select(
 	ctx context.Context, n *parser.Select, desiredTypes []parser.Type,
 ) (planNode, error) {
+	resetWith, err := p.initWith(ctx, n.With)
+	if err != nil {
+		return nil, err
+	}
+	defer resetWith()
+
 	wrapped := n.Select
 	limit := n.Limit
 	orderBy := n.OrderBy
 
 	for s, ok := wrapped.(*parser.ParenSelect); ok; s, ok = wrapped.(*parser.ParenSelect) {
 		wrapped = s.Select.Select
+		if s.Select.With != nil {
+			resetInnerWith, err := p.initWith(ctx, s.Select.With)
+			if err != nil {
+				return nil, err
+			}
+			defer resetInnerWith()
+		}
 		if s.Select.OrderBy != nil {
 			if orderBy != nil {
 				return nil, fmt.Errorf("multiple ORDER BY clauses not allowed")
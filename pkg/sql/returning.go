@@ -20,6 +20,7 @@ import (
 	"golang.org/x/net/context"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/pkg/errors"
@@ -52,7 +53,7 @@ func (p *planner) newReturningHelper(
 	r parser.ReturningClause,
 	desiredTypes []parser.Type,
 	tn *parser.TableName,
-	tablecols []sqlbase.ColumnDescriptor,
+	desc *sqlbase.TableDescriptor,
 ) (*returningHelper, error) {
 	rh := &returningHelper{
 		p: p,
@@ -75,10 +76,24 @@ func (p *planner) newReturningHelper(
 		}
 	}
 
+	tablecols := desc.Columns
+	resultCols := sqlbase.ResultColumnsFromColDescs(tablecols)
+	// Without the SELECT privilege on the table, only the columns the user
+	// has the SELECT privilege on can be returned.
+	if ok, err := p.hasPrivilege(desc.Privileges, privilege.SELECT); err != nil {
+		return nil, err
+	} else if !ok {
+		for i := range tablecols {
+			readable, err := p.hasColumnPrivilege(desc, &tablecols[i], privilege.SELECT)
+			if err != nil {
+				return nil, err
+			}
+			resultCols[i].Unreadable = !readable
+		}
+	}
+
 	rh.columns = make(sqlbase.ResultColumns, 0, len(rExprs))
-	rh.source = newSourceInfoForSingleTable(
-		*tn, sqlbase.ResultColumnsFromColDescs(tablecols),
-	)
+	rh.source = newSourceInfoForSingleTable(*tn, resultCols)
 	rh.exprs = make([]parser.TypedExpr, 0, len(rExprs))
 	ivarHelper := parser.MakeIndexedVarHelper(rh, len(tablecols))
 	for _, target := range rExprs {
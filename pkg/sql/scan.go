@@ -197,9 +197,20 @@ func (n *scanNode) initTable(
 ) error {
 	n.desc = desc
 
+	// Without the SELECT privilege on the table, the SELECT privilege on
+	// some of its columns allows the query to read these columns. The
+	// others are marked unreadable, and name resolution refuses them.
+	checkColumns := false
 	if !p.skipSelectPrivilegeChecks {
 		if err := p.CheckPrivilege(n.desc, privilege.SELECT); err != nil {
-			return err
+			ok, colErr := p.anyColumnPrivilege(n.desc, privilege.SELECT)
+			if colErr != nil {
+				return colErr
+			}
+			if !ok {
+				return err
+			}
+			checkColumns = true
 		}
 	}
 
@@ -210,7 +221,19 @@ func (n *scanNode) initTable(
 	}
 
 	n.noIndexJoin = (indexHints != nil && indexHints.NoIndexJoin)
-	return n.initDescDefaults(scanVisibility, wantedColumns)
+	if err := n.initDescDefaults(scanVisibility, wantedColumns); err != nil {
+		return err
+	}
+	if checkColumns {
+		for i := range n.cols {
+			ok, err := p.hasColumnPrivilege(n.desc, &n.cols[i], privilege.SELECT)
+			if err != nil {
+				return err
+			}
+			n.resultColumns[i].Unreadable = !ok
+		}
+	}
+	return nil
 }
 
 func (n *scanNode) lookupSpecifiedIndex(indexHints *parser.IndexHints) error {
@@ -384,6 +407,11 @@ func (n *scanNode) computeOrdering(
 	index *sqlbase.IndexDescriptor, exactPrefix int, reverse bool,
 ) orderingInfo {
 	var ordering orderingInfo
+	if index.Type == sqlbase.IndexDescriptor_INVERTED {
+		// The entries of an inverted index are ordered by paths into the
+		// indexed document, which don't order the rows by any column.
+		return ordering
+	}
 
 	columnIDs, dirs := index.FullColumnIDs()
 
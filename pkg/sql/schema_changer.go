@@ -281,7 +281,12 @@ func (sc *SchemaChanger) maybeAddDropRename(
 		}
 
 		// Do all the hard work of deleting the table data and the table ID.
-		if err := truncateTableInChunks(ctx, table, &sc.db, false /* traceKV */); err != nil {
+		if table.IsSequence() {
+			// The only data of a sequence is its value.
+			if err := sc.db.Del(ctx, keys.MakeSequenceKey(uint32(table.ID))); err != nil {
+				return false, err
+			}
+		} else if err := truncateTableInChunks(ctx, table, &sc.db, false /* traceKV */); err != nil {
 			return false, err
 		}
 
@@ -575,7 +580,9 @@ func (sc *SchemaChanger) waitToUpdateLeases(ctx context.Context, tableID sqlbase
 // schema.
 // Returns the updated of the descriptor.
 func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.Descriptor, error) {
+	var dropConvertedSpans []jobs.ResumeSpanList
 	return sc.leaseMgr.Publish(ctx, sc.tableID, func(desc *sqlbase.TableDescriptor) error {
+		dropConvertedSpans = nil
 		i := 0
 		for _, mutation := range desc.Mutations {
 			if mutation.MutationID != sc.mutationID {
@@ -583,7 +590,9 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.Descriptor, error)
 				// mutations if they have the mutation ID we're looking for.
 				break
 			}
-			desc.MakeMutationComplete(mutation)
+			if err := desc.MakeMutationComplete(mutation); err != nil {
+				return err
+			}
 			i++
 		}
 		if i == 0 {
@@ -594,8 +603,29 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.Descriptor, error)
 		// Trim the executed mutations from the descriptor.
 		desc.Mutations = desc.Mutations[i:]
 
+		// Completing a column type conversion enqueued mutations dropping the
+		// columns that were converted. They are carried out by the same job.
+		dropConvertedMutationID := sqlbase.InvalidMutationID
+		for _, m := range desc.Mutations {
+			if m.MutationID == desc.NextMutationID {
+				dropConvertedSpans = append(dropConvertedSpans, jobs.ResumeSpanList{
+					ResumeSpans: []roachpb.Span{desc.PrimaryIndexSpan()},
+				})
+			}
+		}
+		if len(dropConvertedSpans) > 0 {
+			var err error
+			if dropConvertedMutationID, err = desc.FinalizeMutation(); err != nil {
+				return err
+			}
+		}
+
 		for i, g := range desc.MutationJobs {
 			if g.MutationID == sc.mutationID {
+				if dropConvertedMutationID != sqlbase.InvalidMutationID {
+					desc.MutationJobs[i].MutationID = dropConvertedMutationID
+					break
+				}
 				// Trim the executed mutation group from the descriptor.
 				desc.MutationJobs = append(desc.MutationJobs[:i], desc.MutationJobs[i+1:]...)
 				break
@@ -603,7 +633,12 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.Descriptor, error)
 		}
 		return nil
 	}, func(txn *client.Txn) error {
-		if err := sc.job.WithTxn(txn).Succeeded(ctx); err != nil {
+		if len(dropConvertedSpans) > 0 {
+			details := jobs.SchemaChangeDetails{ResumeSpanList: dropConvertedSpans}
+			if err := sc.job.WithTxn(txn).SetDetails(ctx, details); err != nil {
+				return err
+			}
+		} else if err := sc.job.WithTxn(txn).Succeeded(ctx); err != nil {
 			log.Warningf(ctx, "schema change ignoring error while marking job %d as successful: %+v",
 				sc.job.ID(), err)
 		}
@@ -668,8 +703,20 @@ func (sc *SchemaChanger) runStateMachineAndBackfill(
 	}
 
 	// Mark the mutations as completed.
-	_, err := sc.done(ctx)
-	return err
+	desc, err := sc.done(ctx)
+	if err != nil {
+		return err
+	}
+
+	// If the job was handed over to the mutations dropping converted columns,
+	// run them as well.
+	for _, g := range desc.GetTable().MutationJobs {
+		if g.JobID == *sc.job.ID() {
+			sc.mutationID = g.MutationID
+			return sc.runStateMachineAndBackfill(ctx, lease, evalCtx)
+		}
+	}
+	return nil
 }
 
 // reverseMutations reverses the direction of all the mutations with the
@@ -103,6 +103,14 @@ func (v *nameResolutionVisitor) VisitPre(expr parser.Expr) (recurse bool, newNod
 		if v.err != nil {
 			return false, expr
 		}
+		// Ordinal references can't bypass column privileges.
+		for srcIdx, src := range v.sources {
+			colIdx := t.Idx - v.colOffsets[srcIdx]
+			if colIdx >= 0 && colIdx < len(src.sourceColumns) && src.sourceColumns[colIdx].Unreadable {
+				v.err = newUnreadableColumnError(src.sourceColumns[colIdx].Name)
+				return false, expr
+			}
+		}
 
 		v.foundDependentVars = true
 		return false, t
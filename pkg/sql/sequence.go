@@ -0,0 +1,424 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"math"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// sequenceState contains the per-session state related to sequences.
+// It is protected by a mutex because statements of a session may be
+// executed in parallel.
+type sequenceState struct {
+	mu syncutil.Mutex
+
+	// latestValues stores the last value obtained by nextval() in this
+	// session, by sequence ID. It is used by currval().
+	latestValues map[sqlbase.ID]int64
+
+	// lastSequenceIncremented is the ID of the sequence nextval() was last
+	// called on in this session. It is used by lastval().
+	lastSequenceIncremented sqlbase.ID
+
+	// cache stores the values reserved by this session for sequences
+	// with a CACHE size greater than 1, which have not been handed out
+	// yet.
+	cache map[sqlbase.ID]*sequenceCacheEntry
+}
+
+// sequenceCacheEntry is a range of values of a sequence reserved by a
+// session with a single increment of the sequence's value.
+type sequenceCacheEntry struct {
+	// version is the version of the sequence descriptor the values were
+	// reserved with. The entry is discarded if the sequence is altered.
+	version sqlbase.DescriptorVersion
+	// next is the next value to hand out, and remaining the number of
+	// values left including next.
+	next      int64
+	remaining int64
+}
+
+// nextCachedValue returns the next value reserved by this session for
+// the given sequence, if any.
+func (ss *sequenceState) nextCachedValue(desc *sqlbase.TableDescriptor) (int64, bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	entry, ok := ss.cache[desc.ID]
+	if !ok {
+		return 0, false
+	}
+	if entry.version != desc.Version {
+		delete(ss.cache, desc.ID)
+		return 0, false
+	}
+	val := entry.next
+	entry.next += desc.SequenceOpts.Increment
+	entry.remaining--
+	if entry.remaining == 0 {
+		delete(ss.cache, desc.ID)
+	}
+	return val, true
+}
+
+// cacheValues records that count values of the given sequence, starting
+// at next, were reserved by this session.
+func (ss *sequenceState) cacheValues(desc *sqlbase.TableDescriptor, next int64, count int64) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.cache == nil {
+		ss.cache = make(map[sqlbase.ID]*sequenceCacheEntry)
+	}
+	ss.cache[desc.ID] = &sequenceCacheEntry{version: desc.Version, next: next, remaining: count}
+}
+
+// discardCachedValues forgets the values of the given sequence reserved
+// by this session.
+func (ss *sequenceState) discardCachedValues(id sqlbase.ID) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	delete(ss.cache, id)
+}
+
+// recordValue records the value most recently obtained for the given
+// sequence.
+func (ss *sequenceState) recordValue(id sqlbase.ID, val int64) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.latestValues == nil {
+		ss.latestValues = make(map[sqlbase.ID]int64)
+	}
+	ss.latestValues[id] = val
+	ss.lastSequenceIncremented = id
+}
+
+// getLastValueByID returns the value most recently obtained for the
+// given sequence in this session, if any.
+func (ss *sequenceState) getLastValueByID(id sqlbase.ID) (int64, bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	val, ok := ss.latestValues[id]
+	return val, ok
+}
+
+// getLastValue returns the value most recently obtained for any
+// sequence in this session, if any.
+func (ss *sequenceState) getLastValue() (int64, bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	val, ok := ss.latestValues[ss.lastSequenceIncremented]
+	return val, ok
+}
+
+// reset forgets all the sequence values obtained or reserved by the
+// session. It implements DISCARD SEQUENCES.
+func (ss *sequenceState) reset() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.latestValues = nil
+	ss.lastSequenceIncremented = 0
+	ss.cache = nil
+}
+
+// getSequenceDescForUse returns the leased descriptor of the sequence
+// with the given name, for use by the sequence builtins.
+func (p *planner) getSequenceDescForUse(
+	ctx context.Context, seqName *parser.TableName,
+) (*sqlbase.TableDescriptor, error) {
+	desc, err := p.getTableDesc(ctx, seqName)
+	if err != nil {
+		return nil, err
+	}
+	if !desc.IsSequence() {
+		return nil, sqlbase.NewWrongObjectTypeError(seqName, "sequence")
+	}
+	return desc, nil
+}
+
+// IncrementSequence implements the parser.EvalPlanner interface.
+//
+// The sequence's value is incremented outside of the current
+// transaction, so that concurrent transactions using the same sequence
+// do not conflict with each other. As a consequence, values obtained
+// by transactions that are later aborted are not reused. When the
+// sequence's CACHE size is greater than 1, the session reserves that
+// many values with a single increment and hands them out on subsequent
+// calls.
+//
+// If the sequence's value was written by the current transaction, the
+// value is incremented in the transaction instead, and no values are
+// reserved: they would be handed out again if the transaction aborted.
+func (p *planner) IncrementSequence(ctx context.Context, seqName *parser.TableName) (int64, error) {
+	desc, err := p.getSequenceDescForUse(ctx, seqName)
+	if err != nil {
+		return 0, err
+	}
+	if err := p.CheckPrivilege(desc, privilege.UPDATE); err != nil {
+		return 0, err
+	}
+
+	seqOpts := desc.SequenceOpts
+	ss := &p.session.sequenceState
+	val, ok := ss.nextCachedValue(desc)
+	if !ok {
+		seqValueKey := keys.MakeSequenceKey(uint32(desc.ID))
+		if p.session.tables.isUncommittedSequence(desc.ID) {
+			res, err := p.txn.Inc(ctx, seqValueKey, seqOpts.Increment)
+			if err != nil {
+				return 0, err
+			}
+			val = res.ValueInt()
+		} else {
+			cacheSize := seqOpts.CacheSize
+			if cacheSize < 1 {
+				cacheSize = 1
+			}
+			incrementBy, ok := cacheIncrement(seqOpts.Increment, cacheSize)
+			if !ok {
+				return 0, cacheTooLargeError(seqOpts.Increment, cacheSize)
+			}
+			endVal, err := client.IncrementValRetryable(ctx, p.ExecCfg().DB, seqValueKey, incrementBy)
+			if err != nil {
+				return 0, err
+			}
+			val = endVal - (incrementBy - seqOpts.Increment)
+			if cacheSize > 1 {
+				ss.cacheValues(desc, val+seqOpts.Increment, cacheSize-1)
+			}
+		}
+	}
+
+	if val > seqOpts.MaxValue || val < seqOpts.MinValue {
+		ss.discardCachedValues(desc.ID)
+		return 0, boundsExceededError(desc)
+	}
+
+	ss.recordValue(desc.ID, val)
+	return val, nil
+}
+
+// cacheIncrement returns the amount by which the value of a sequence is
+// incremented to reserve cacheSize values, and false if it overflows.
+func cacheIncrement(increment, cacheSize int64) (int64, bool) {
+	incrementBy := increment * cacheSize
+	if cacheSize != 1 && incrementBy/cacheSize != increment {
+		return 0, false
+	}
+	return incrementBy, true
+}
+
+// cacheTooLargeError returns the error reported when the values reserved
+// by a session for a sequence would not fit in an int64.
+func cacheTooLargeError(increment, cacheSize int64) error {
+	return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+		"CACHE (%d) is too large for INCREMENT (%d)", cacheSize, increment)
+}
+
+// boundsExceededError returns the error reported when a sequence has
+// reached the end of its range of values.
+func boundsExceededError(desc *sqlbase.TableDescriptor) error {
+	seqOpts := desc.SequenceOpts
+	word := "maximum"
+	value := seqOpts.MaxValue
+	if seqOpts.Increment < 0 {
+		word = "minimum"
+		value = seqOpts.MinValue
+	}
+	return pgerror.NewErrorf(pgerror.CodeSequenceGeneratorLimitExceededError,
+		"reached %s value of sequence %q (%d)", word, desc.Name, value)
+}
+
+// GetLatestValueInSessionForSequence implements the parser.EvalPlanner
+// interface.
+func (p *planner) GetLatestValueInSessionForSequence(
+	ctx context.Context, seqName *parser.TableName,
+) (int64, error) {
+	desc, err := p.getSequenceDescForUse(ctx, seqName)
+	if err != nil {
+		return 0, err
+	}
+	if err := p.CheckPrivilege(desc, privilege.SELECT); err != nil {
+		return 0, err
+	}
+
+	val, ok := p.session.sequenceState.getLastValueByID(desc.ID)
+	if !ok {
+		return 0, pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			"currval of sequence %q is not yet defined in this session", desc.Name)
+	}
+	return val, nil
+}
+
+// SetSequenceValue implements the parser.EvalPlanner interface.
+//
+// Unlike nextval(), setval() writes the sequence's value in the current
+// transaction.
+func (p *planner) SetSequenceValue(
+	ctx context.Context, seqName *parser.TableName, newVal int64, isCalled bool,
+) error {
+	desc, err := p.getSequenceDescForUse(ctx, seqName)
+	if err != nil {
+		return err
+	}
+	if err := p.CheckPrivilege(desc, privilege.UPDATE); err != nil {
+		return err
+	}
+
+	seqOpts := desc.SequenceOpts
+	if newVal > seqOpts.MaxValue || newVal < seqOpts.MinValue {
+		return pgerror.NewErrorf(pgerror.CodeNumericValueOutOfRangeError,
+			"setval: value %d is out of bounds for sequence %q (%d..%d)",
+			newVal, desc.Name, seqOpts.MinValue, seqOpts.MaxValue)
+	}
+
+	// The stored value is the one last handed out: the next call to
+	// nextval() adds the increment to it.
+	storedVal := newVal
+	if !isCalled {
+		storedVal = newVal - seqOpts.Increment
+	}
+	seqValueKey := keys.MakeSequenceKey(uint32(desc.ID))
+	if err := p.txn.Put(ctx, seqValueKey, storedVal); err != nil {
+		return err
+	}
+	p.session.tables.addUncommittedSequence(desc.ID)
+
+	ss := &p.session.sequenceState
+	ss.discardCachedValues(desc.ID)
+	if isCalled {
+		ss.recordValue(desc.ID, newVal)
+	}
+	return nil
+}
+
+// GetLastSequenceValue implements the parser.EvalPlanner interface.
+func (p *planner) GetLastSequenceValue(ctx context.Context) (int64, error) {
+	val, ok := p.session.sequenceState.getLastValue()
+	if !ok {
+		return 0, pgerror.NewError(pgerror.CodeObjectNotInPrerequisiteStateError,
+			"lastval is not yet defined in this session")
+	}
+	return val, nil
+}
+
+// assignSequenceOptions sets the options of a sequence descriptor from
+// the options of a CREATE SEQUENCE or ALTER SEQUENCE statement. When
+// setDefaults is true, the options that are not specified take their
+// default values, which depend on the direction of the sequence.
+func assignSequenceOptions(
+	opts *sqlbase.TableDescriptor_SequenceOpts, optsNode parser.SequenceOptions, setDefaults bool,
+) error {
+	seen := make(map[string]bool, len(optsNode))
+	for _, option := range optsNode {
+		if seen[option.Name] {
+			return pgerror.NewError(pgerror.CodeSyntaxError, "conflicting or redundant options")
+		}
+		seen[option.Name] = true
+	}
+
+	// The defaults of the other options depend on the sign of the
+	// increment, so it is determined first.
+	if setDefaults {
+		opts.Increment = 1
+		opts.CacheSize = 1
+	}
+	for _, option := range optsNode {
+		if option.Name == parser.SeqOptIncrement {
+			opts.Increment = *option.IntVal
+		}
+	}
+	if opts.Increment == 0 {
+		return pgerror.NewError(pgerror.CodeInvalidParameterValueError,
+			"INCREMENT must not be zero")
+	}
+	isAscending := opts.Increment > 0
+
+	defaultMinValue, defaultMaxValue := int64(1), int64(math.MaxInt64)
+	if !isAscending {
+		defaultMinValue, defaultMaxValue = math.MinInt64, -1
+	}
+	if setDefaults {
+		opts.MinValue = defaultMinValue
+		opts.MaxValue = defaultMaxValue
+	}
+
+	for _, option := range optsNode {
+		switch option.Name {
+		case parser.SeqOptIncrement:
+			// Handled above.
+		case parser.SeqOptNoCycle:
+			// Sequences never cycle.
+		case parser.SeqOptCache:
+			if *option.IntVal < 1 {
+				return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+					"CACHE (%d) must be greater than zero", *option.IntVal)
+			}
+			opts.CacheSize = *option.IntVal
+		case parser.SeqOptMinValue:
+			// A nil value indicates NO MINVALUE.
+			if option.IntVal == nil {
+				opts.MinValue = defaultMinValue
+			} else {
+				opts.MinValue = *option.IntVal
+			}
+		case parser.SeqOptMaxValue:
+			// A nil value indicates NO MAXVALUE.
+			if option.IntVal == nil {
+				opts.MaxValue = defaultMaxValue
+			} else {
+				opts.MaxValue = *option.IntVal
+			}
+		case parser.SeqOptStart:
+			opts.Start = *option.IntVal
+		default:
+			return pgerror.NewErrorf(pgerror.CodeInternalError,
+				"unknown sequence option: %s", option.Name)
+		}
+	}
+
+	if setDefaults && !seen[parser.SeqOptStart] {
+		if isAscending {
+			opts.Start = opts.MinValue
+		} else {
+			opts.Start = opts.MaxValue
+		}
+	}
+
+	if opts.MinValue >= opts.MaxValue {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"MINVALUE (%d) must be less than MAXVALUE (%d)", opts.MinValue, opts.MaxValue)
+	}
+	if opts.Start < opts.MinValue {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"START value (%d) cannot be less than MINVALUE (%d)", opts.Start, opts.MinValue)
+	}
+	if opts.Start > opts.MaxValue {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"START value (%d) cannot be greater than MAXVALUE (%d)", opts.Start, opts.MaxValue)
+	}
+	if _, ok := cacheIncrement(opts.Increment, opts.CacheSize); !ok {
+		return cacheTooLargeError(opts.Increment, opts.CacheSize)
+	}
+	return nil
+}
@@ -210,6 +210,11 @@ type Session struct {
 	// SafeUpdates causes errors when the client
 	// sends syntax that may have unwanted side effects.
 	SafeUpdates bool
+	// CustomVars contains the values of the user-defined session variables,
+	// whose names contain a dot, e.g. app.tenant_id. Applications use them
+	// to pass settings to their queries, typically to the predicates of
+	// row-level security policies through current_setting().
+	CustomVars map[string]string
 
 	//
 	// Session parameters, non-user-configurable.
@@ -299,6 +304,10 @@ type Session struct {
 
 	tables TableCollection
 
+	// sequenceState contains the values obtained from sequences by this
+	// session.
+	sequenceState sequenceState
+
 	// If set, contains the in progress COPY FROM columns.
 	copyFrom *copyNode
 
@@ -537,6 +546,9 @@ func (s *Session) Finish(e *Executor) {
 	// If we're inside a txn, roll it back.
 	if s.TxnState.State().kvTxnIsOpen() {
 		_ = s.TxnState.updateStateAndCleanupOnErr(fmt.Errorf("session closing"), e)
+	} else if s.TxnState.kvTxnKeptAfterErr() {
+		s.TxnState.mu.txn.CleanupOnError(s.context, fmt.Errorf("session closing"))
+		s.TxnState.resetStateAndTxn(Aborted)
 	}
 	if s.TxnState.State() != NoTxn {
 		s.TxnState.finishSQLTxn(s)
@@ -624,6 +636,7 @@ func (s *Session) resetPlanner(p *planner, e *Executor, txn *client.Txn) {
 	p.phaseTimes = s.phaseTimes
 	p.stmt = nil
 	p.cancelChecker = sqlbase.NewCancelChecker(s.Ctx())
+	p.sessionUserRoles = nil
 
 	p.semaCtx = parser.MakeSemaContext(s.User == security.RootUser)
 	p.semaCtx.Location = &s.Location
@@ -970,6 +983,22 @@ type txnState struct {
 	// errors. The txn will enter a RestartWait state in case of such errors.
 	retryIntent bool
 
+	// The savepoints created by the user with SAVEPOINT, other than the restart
+	// savepoint, from the oldest to the most recent.
+	savepoints []sqlSavepoint
+
+	// The functions undoing the changes made to session variables by SET
+	// LOCAL, in the order of the changes. They are called in reverse order
+	// when the transaction finishes, or for the changes made after a
+	// savepoint, when rolling back to it.
+	localVars []localVarUndo
+
+	// The cursors open in the transaction, by name, and the number of cursors
+	// declared in the transaction so far. The cursors are closed when the
+	// transaction finishes.
+	cursors    map[string]*sqlCursor
+	numCursors int
+
 	// A COMMIT statement has been processed. Useful for allowing the txn to
 	// survive retriable errors if it will be auto-retried (BEGIN; ... COMMIT; in
 	// the same batch), but not if the error needs to be reported to the user.
@@ -997,6 +1026,65 @@ type txnState struct {
 	mon mon.BytesMonitor
 }
 
+// sqlSavepoint is a savepoint created with SAVEPOINT.
+type sqlSavepoint struct {
+	name string
+	// token identifies the savepoint in the KV transaction; see
+	// client.Txn.CreateSavepoint.
+	token int32
+	// numLocalVars is the length of txnState.localVars when the savepoint was
+	// created.
+	numLocalVars int
+	// numCursors is txnState.numCursors when the savepoint was created.
+	numCursors int
+}
+
+// localVarUndo undoes the change of a session variable made by SET LOCAL.
+type localVarUndo struct {
+	name string
+	// restore restores the value of the variable before the change. It is
+	// nil if the variable was later changed for the whole session.
+	restore func()
+}
+
+// saveLocalVar records restore, the function undoing the change of the
+// session variable name about to be made by SET LOCAL.
+func (ts *txnState) saveLocalVar(name string, restore func()) {
+	ts.localVars = append(ts.localVars, localVarUndo{name: name, restore: restore})
+}
+
+// forgetLocalVar keeps the session variable name from being restored at the
+// end of the transaction, because it is being changed for the whole session.
+func (ts *txnState) forgetLocalVar(name string) {
+	for i := range ts.localVars {
+		if ts.localVars[i].name == name {
+			ts.localVars[i].restore = nil
+		}
+	}
+}
+
+// unwindLocalVars undoes the changes made by SET LOCAL to the session
+// variables, except for the first n.
+func (ts *txnState) unwindLocalVars(n int) {
+	for i := len(ts.localVars) - 1; i >= n; i-- {
+		if restore := ts.localVars[i].restore; restore != nil {
+			restore()
+		}
+	}
+	ts.localVars = ts.localVars[:n]
+}
+
+// findSavepoint returns the index of the most recent savepoint with the given
+// name, or -1 if there is none.
+func (ts *txnState) findSavepoint(name string) int {
+	for i := len(ts.savepoints) - 1; i >= 0; i-- {
+		if ts.savepoints[i].name == name {
+			return i
+		}
+	}
+	return -1
+}
+
 // State returns the current state of the session.
 func (ts *txnState) State() TxnStateEnum {
 	return TxnStateEnum(atomic.LoadInt64((*int64)(&ts.state)))
@@ -1041,6 +1129,10 @@ func (ts *txnState) resetForNewSQLTxn(
 
 	ts.retryIntent = retryIntent
 	// Reset state vars to defaults.
+	ts.savepoints = nil
+	ts.localVars = nil
+	ts.cursors = nil
+	ts.numCursors = 0
 	ts.commitSeen = false
 	ts.sqlTimestamp = sqlTimestamp
 	ts.implicitTxn = implicitTxn
@@ -1144,10 +1236,16 @@ func (ts *txnState) resetStateAndTxn(state TxnStateEnum) {
 	ts.mu.Unlock()
 }
 
-// finishSQLTxn finalizes a transaction's results and closes the root span for
-// the current SQL txn. This needs to be called before resetForNewSQLTxn() is
-// called for starting another SQL txn.
+// finishSQLTxn finalizes a transaction's results, undoes its SET LOCAL
+// statements, closes its cursors and closes the root span for the current SQL
+// txn. This needs to be called before resetForNewSQLTxn() is called for
+// starting another SQL txn.
 func (ts *txnState) finishSQLTxn(s *Session) {
+	// The session variables set with SET LOCAL get their values back.
+	ts.unwindLocalVars(0)
+	// The cursors hold memory accounted for by the txn monitor.
+	ts.closeCursors(ts.Ctx, 0)
+
 	ts.mon.Stop(ts.Ctx)
 	if ts.cancel != nil {
 		ts.cancel()
@@ -1247,6 +1345,25 @@ func (ts *txnState) updateStateAndCleanupOnErr(err error, e *Executor) error {
 	return err
 }
 
+// canRollbackToSavepointAfterErr returns whether the KV txn can be kept open
+// when a statement fails with err, so that a subsequent ROLLBACK TO SAVEPOINT
+// can discard the writes of the failed statement and resume the transaction.
+// This is the case for non-retryable errors in explicit transactions which
+// have savepoints other than the restart savepoint.
+func (ts *txnState) canRollbackToSavepointAfterErr(err error) bool {
+	if _, retryable := err.(*roachpb.HandledRetryableTxnError); retryable {
+		return false
+	}
+	return len(ts.savepoints) > 0 && !ts.implicitTxn && !ts.commitSeen &&
+		ts.mu.txn != nil && !ts.mu.txn.IsFinalized()
+}
+
+// kvTxnKeptAfterErr returns whether the SQL txn is in the Aborted state but
+// its KV txn was kept open, waiting for a ROLLBACK TO SAVEPOINT.
+func (ts *txnState) kvTxnKeptAfterErr() bool {
+	return ts.State() == Aborted && ts.mu.txn != nil
+}
+
 func (ts *txnState) setIsolationLevel(isolation enginepb.IsolationType) error {
 	if err := ts.mu.txn.SetIsolation(isolation); err != nil {
 		return err
@@ -32,11 +32,14 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
-// setNode represents a SET SESSION statement.
+// setNode represents a SET SESSION or SET LOCAL statement.
 type setNode struct {
-	v sessionVar
+	name string
+	v    sessionVar
 	// typedValues == nil means RESET.
 	typedValues []parser.TypedExpr
+	// local is set for SET LOCAL.
+	local bool
 }
 
 // SetVar sets session variables.
@@ -83,7 +86,7 @@ func (p *planner) SetVar(ctx context.Context, n *parser.SetVar) (planNode, error
 		}
 	}
 
-	v, ok := varGen[name]
+	v, ok := lookupSessionVar(name)
 	if !ok {
 		return nil, fmt.Errorf("unknown variable: %q", name)
 	}
@@ -97,15 +100,26 @@ func (p *planner) SetVar(ctx context.Context, n *parser.SetVar) (planNode, error
 			return nil, fmt.Errorf("variable \"%s\" cannot be reset", name)
 		}
 	}
+	if n.Local && v.Save == nil {
+		return nil, fmt.Errorf("variable \"%s\" cannot be set locally", name)
+	}
 
-	return &setNode{v: v, typedValues: typedValues}, nil
+	return &setNode{name: name, v: v, typedValues: typedValues, local: n.Local}, nil
 }
 
 func (n *setNode) Start(params runParams) error {
+	session := params.p.session
+	if n.local {
+		session.TxnState.saveLocalVar(n.name, n.v.Save(session))
+	} else {
+		// A change to the whole session outlasts the transaction, even if
+		// the variable was previously set locally.
+		session.TxnState.forgetLocalVar(n.name)
+	}
 	if n.typedValues != nil {
-		return n.v.Set(params.ctx, params.p.session, n.typedValues)
+		return n.v.Set(params.ctx, session, n.typedValues)
 	}
-	return n.v.Reset(params.p.session)
+	return n.v.Reset(session)
 }
 
 func (n *setNode) Next(_ runParams) (bool, error) { return false, nil }
@@ -153,7 +153,10 @@ func (p *planner) ShowVar(ctx context.Context, n *parser.ShowVar) (planNode, err
 	}
 
 	if _, ok := varGen[name]; !ok {
-		return nil, fmt.Errorf("unknown variable: %q", origName)
+		// User-defined variables only exist once set.
+		if _, ok := p.session.CustomVars[name]; !ok {
+			return nil, fmt.Errorf("unknown variable: %q", origName)
+		}
 	}
 
 	varName := parser.EscapeSQLString(name)
@@ -650,5 +653,5 @@ func (p *planner) ShowTransactionStatus(ctx context.Context) (planNode, error) {
 // Privileges: SELECT on system.users.
 func (p *planner) ShowUsers(ctx context.Context, n *parser.ShowUsers) (planNode, error) {
 	return p.delegateQuery(ctx, "SHOW USERS",
-		`SELECT username FROM system.users ORDER BY 1`, nil, nil)
+		`SELECT username FROM system.users WHERE "isRole" = false ORDER BY 1`, nil, nil)
 }
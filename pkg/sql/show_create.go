@@ -43,6 +43,25 @@ func (p *planner) showCreateView(
 	return buf.String(), nil
 }
 
+// showCreateSequence returns a valid SQL representation of the
+// CREATE SEQUENCE statement used to create the given sequence.
+func (p *planner) showCreateSequence(
+	ctx context.Context, tn parser.Name, desc *sqlbase.TableDescriptor,
+) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString("CREATE SEQUENCE ")
+	tn.Format(&buf, parser.FmtSimple)
+	opts := desc.SequenceOpts
+	fmt.Fprintf(&buf, " MINVALUE %d", opts.MinValue)
+	fmt.Fprintf(&buf, " MAXVALUE %d", opts.MaxValue)
+	fmt.Fprintf(&buf, " INCREMENT %d", opts.Increment)
+	fmt.Fprintf(&buf, " START %d", opts.Start)
+	if opts.CacheSize > 1 {
+		fmt.Fprintf(&buf, " CACHE %d", opts.CacheSize)
+	}
+	return buf.String(), nil
+}
+
 // showCreateTable returns a valid SQL representation of the CREATE
 // TABLE statement used to create the given table.
 //
@@ -97,6 +116,8 @@ func (p *planner) showCreateTable(
 				&fkTableName,
 				quoteNames(fkIdx.ColumnNames...),
 			)
+			actions := fk.ReferenceActions()
+			buf.WriteString(parser.AsString(&actions))
 		}
 		if idx.ID != desc.PrimaryIndex.ID {
 			// Showing the primary index is handled above.
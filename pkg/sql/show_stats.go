@@ -0,0 +1,103 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+var showTableStatsColumns = sqlbase.ResultColumns{
+	{Name: "statistics_name", Typ: parser.TypeString},
+	{Name: "column_names", Typ: parser.TypeString},
+	{Name: "created", Typ: parser.TypeTimestamp},
+	{Name: "row_count", Typ: parser.TypeInt},
+	{Name: "distinct_count", Typ: parser.TypeInt},
+	{Name: "null_count", Typ: parser.TypeInt},
+}
+
+// ShowTableStats returns the statistics of a table which were created with
+// CREATE STATISTICS, in the order in which they were created.
+// Privileges: Any privilege on table.
+func (p *planner) ShowTableStats(ctx context.Context, n *parser.ShowTableStats) (planNode, error) {
+	tn, err := n.Table.NormalizeWithDatabaseName(p.session.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, err := MustGetTableDesc(ctx, p.txn, p.getVirtualTabler(), tn, true /*allowAdding*/)
+	if err != nil {
+		return nil, sqlbase.NewUndefinedRelationError(tn)
+	}
+	if err := p.anyPrivilege(desc); err != nil {
+		return nil, err
+	}
+
+	return &delayedNode{
+		name:    "SHOW STATISTICS FOR TABLE " + tn.String(),
+		columns: showTableStatsColumns,
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			// The statistics are read as root, since the user only needs a
+			// privilege on the table itself.
+			internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+			rows, err := internalExecutor.QueryRowsInTransaction(
+				ctx,
+				"show-statistics",
+				p.txn,
+				`SELECT name, "columnIDs", "createdAt", "rowCount", "distinctCount", "nullCount"
+				FROM system.table_statistics
+				WHERE "tableID" = $1
+				ORDER BY "createdAt", "statisticID"`,
+				desc.ID,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			v := p.newContainerValuesNode(showTableStatsColumns, len(rows))
+			for _, r := range rows {
+				columnIDs := r[1].(*parser.DArray).Array
+				columnNames := make([]string, len(columnIDs))
+				for i, d := range columnIDs {
+					colID := sqlbase.ColumnID(*d.(*parser.DInt))
+					if col, err := desc.FindColumnByID(colID); err == nil {
+						columnNames[i] = col.Name
+					} else {
+						// The column was dropped after the statistic was created.
+						columnNames[i] = fmt.Sprintf("[%d]", colID)
+					}
+				}
+				newRow := parser.Datums{
+					r[0],
+					parser.NewDString(strings.Join(columnNames, ", ")),
+					r[2],
+					r[3],
+					r[4],
+					r[5],
+				}
+				if _, err := v.rows.AddRow(ctx, newRow); err != nil {
+					v.Close(ctx)
+					return nil, err
+				}
+			}
+			return v, nil
+		},
+	}, nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"bytes"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+)
+
+// ColumnTypeConversionRequiresBackfill returns false if the values stored for
+// a column of type from are valid, identically encoded values of type to,
+// such that converting the column only requires changing its descriptor.
+// This is the case for widenings within a semantic type, e.g. STRING(10) to
+// STRING(50) or INT4 to INT.
+func ColumnTypeConversionRequiresBackfill(from, to ColumnType) bool {
+	if from.SemanticType != to.SemanticType {
+		return true
+	}
+	if (from.Locale == nil) != (to.Locale == nil) ||
+		(from.Locale != nil && *from.Locale != *to.Locale) {
+		return true
+	}
+	if len(from.ArrayDimensions) > 0 || len(to.ArrayDimensions) > 0 ||
+		from.ArrayContents != nil || to.ArrayContents != nil {
+		return !from.Equal(to)
+	}
+
+	// widens returns whether a width or precision of to is at least as
+	// permissive as the one of from, where 0 means unbounded.
+	widens := func(from, to int32) bool {
+		return to == 0 || (from != 0 && to >= from)
+	}
+
+	switch from.SemanticType {
+	case ColumnType_STRING, ColumnType_COLLATEDSTRING:
+		return !widens(from.Width, to.Width)
+
+	case ColumnType_INT:
+		if from.VisibleType == ColumnType_BIT || to.VisibleType == ColumnType_BIT {
+			return from.VisibleType != to.VisibleType || from.Width != to.Width
+		}
+		return !widens(from.Width, to.Width)
+
+	case ColumnType_FLOAT:
+		// All floats are stored with 64 bits of precision.
+		return !widens(from.Precision, to.Precision)
+
+	case ColumnType_DECIMAL:
+		if to.Precision == 0 {
+			return false
+		}
+		return to.Width != from.Width || !widens(from.Precision, to.Precision)
+
+	default:
+		return false
+	}
+}
+
+// ColumnConverter computes the values of a column being added by ALTER COLUMN
+// TYPE from the values of the column it is converted from.
+type ColumnConverter struct {
+	col        ColumnDescriptor
+	sourceType parser.Type
+	expr       parser.TypedExpr
+	evalCtx    parser.EvalContext
+
+	// sourceVal is the value being converted by the current call to Convert.
+	sourceVal parser.Datum
+}
+
+var _ parser.IndexedVarContainer = &ColumnConverter{}
+
+// MakeColumnConverter creates a ColumnConverter from the values of the source
+// column to the type of col. It returns an error if there is no cast between
+// the two types.
+func MakeColumnConverter(source, col ColumnDescriptor) (*ColumnConverter, error) {
+	toType, err := parser.ParseType(col.Type.SQLString())
+	if err != nil {
+		return nil, err
+	}
+	cc := &ColumnConverter{col: col, sourceType: source.Type.ToDatumType()}
+	// The converted column only has a temporary name; errors refer to the
+	// column being converted.
+	cc.col.Name = source.Name
+	ivarHelper := parser.MakeIndexedVarHelper(cc, 1)
+	cast := &parser.CastExpr{Expr: ivarHelper.IndexedVar(0), Type: toType}
+	cc.expr, err = cast.TypeCheck(&parser.SemaContext{}, parser.TypeAny)
+	if err != nil {
+		return nil, err
+	}
+	return cc, nil
+}
+
+// Convert returns the value of the converted column for a source value,
+// checking that it fits the column's type.
+func (cc *ColumnConverter) Convert(d parser.Datum) (parser.Datum, error) {
+	cc.sourceVal = d
+	res, err := cc.expr.Eval(&cc.evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckValueWidth(cc.col, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// IndexedVarEval implements the parser.IndexedVarContainer interface.
+func (cc *ColumnConverter) IndexedVarEval(idx int, ctx *parser.EvalContext) (parser.Datum, error) {
+	return cc.sourceVal, nil
+}
+
+// IndexedVarResolvedType implements the parser.IndexedVarContainer interface.
+func (cc *ColumnConverter) IndexedVarResolvedType(idx int) parser.Type {
+	return cc.sourceType
+}
+
+// IndexedVarFormat implements the parser.IndexedVarContainer interface.
+func (cc *ColumnConverter) IndexedVarFormat(buf *bytes.Buffer, f parser.FmtFlags, idx int) {
+	buf.WriteString("@1")
+}
+
+// columnConversion is a column in the DELETE_AND_WRITE_ONLY state being
+// converted from another column. Writers compute its value from the value of
+// the source column in the same row.
+type columnConversion struct {
+	converter *ColumnConverter
+	// sourceIdx and targetIdx are the positions of the source and converted
+	// columns in the row being written.
+	sourceIdx, targetIdx int
+}
+
+// forEachColumnConversion calls fn for every column in the
+// DELETE_AND_WRITE_ONLY state that is being converted from another column.
+func forEachColumnConversion(
+	desc *TableDescriptor, fn func(source, col ColumnDescriptor) error,
+) error {
+	for _, m := range desc.Mutations {
+		col := m.GetColumn()
+		if col == nil || m.ConvertFromColumnID == 0 ||
+			m.Direction != DescriptorMutation_ADD || m.State != DescriptorMutation_DELETE_AND_WRITE_ONLY {
+			continue
+		}
+		source, err := desc.FindActiveColumnByID(m.ConvertFromColumnID)
+		if err != nil {
+			return err
+		}
+		if err := fn(*source, *col); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,332 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// ComputedColumns computes the values of the computed columns of a table from
+// the values of the other columns in the same row.
+//
+// Usage:
+//   cc.LoadRow(colIDtoRowIndex, row, false)
+//   err := cc.ComputeInto(evalCtx, colIDtoRowIndex, row)
+type ComputedColumns struct {
+	// Cols are the computed columns.
+	Cols  []ColumnDescriptor
+	exprs []parser.TypedExpr
+
+	// sourceCols are the public columns of the table. The IndexedVars in
+	// exprs refer to them by ordinal.
+	sourceCols   []ColumnDescriptor
+	curSourceRow parser.Datums
+}
+
+var _ parser.IndexedVarContainer = &ComputedColumns{}
+
+// MakeComputedColumns prepares the evaluation of the computed columns among
+// cols, which can include columns of tableDesc that are being added by a
+// mutation. It returns nil if none of the columns are computed.
+func MakeComputedColumns(tableDesc *TableDescriptor, cols []ColumnDescriptor) (*ComputedColumns, error) {
+	var computed []ColumnDescriptor
+	for _, col := range cols {
+		if col.IsComputed() {
+			computed = append(computed, col)
+		}
+	}
+	if len(computed) == 0 {
+		return nil, nil
+	}
+	cc := newComputedColumns(tableDesc)
+	for i := range computed {
+		if err := cc.add(&computed[i], &parser.SemaContext{}); err != nil {
+			return nil, err
+		}
+	}
+	return cc, nil
+}
+
+// ValidateComputedColumn checks that the expression of the computed column
+// col only refers to columns of tableDesc that are not computed, and that it
+// is a pure, scalar expression of the type of the column. The search path is
+// used for name resolution of functions.
+func ValidateComputedColumn(
+	tableDesc *TableDescriptor, col *ColumnDescriptor, searchPath parser.SearchPath,
+) error {
+	return newComputedColumns(tableDesc).add(col, &parser.SemaContext{SearchPath: searchPath})
+}
+
+// ComputedColumnsToUpdate returns the computed columns of tableDesc whose
+// values change along with the columns updateCols: the public ones and the
+// ones being added in the DELETE_AND_WRITE_ONLY state whose expressions refer
+// to one of updateCols.
+func ComputedColumnsToUpdate(
+	tableDesc *TableDescriptor, updateCols []ColumnDescriptor,
+) ([]ColumnDescriptor, error) {
+	names := make(map[string]struct{}, len(updateCols))
+	for _, col := range updateCols {
+		names[col.Name] = struct{}{}
+	}
+	cols := append([]ColumnDescriptor(nil), tableDesc.Columns...)
+	for _, m := range tableDesc.Mutations {
+		if col := m.GetColumn(); col != nil &&
+			m.Direction == DescriptorMutation_ADD && m.State == DescriptorMutation_DELETE_AND_WRITE_ONLY {
+			cols = append(cols, *col)
+		}
+	}
+	var res []ColumnDescriptor
+	for i := range cols {
+		if !cols[i].IsComputed() {
+			continue
+		}
+		if refers, err := computedColumnRefersTo(&cols[i], names); err != nil {
+			return nil, err
+		} else if refers {
+			res = append(res, cols[i])
+		}
+	}
+	return res, nil
+}
+
+// ComputedColumnReferencing returns a computed column of tableDesc whose
+// expression refers to the column col, or nil if there is none.
+func ComputedColumnReferencing(
+	tableDesc *TableDescriptor, col ColumnDescriptor,
+) (*ColumnDescriptor, error) {
+	cols := append([]ColumnDescriptor(nil), tableDesc.Columns...)
+	for _, m := range tableDesc.Mutations {
+		if c := m.GetColumn(); c != nil && m.Direction == DescriptorMutation_ADD {
+			cols = append(cols, *c)
+		}
+	}
+	names := map[string]struct{}{col.Name: {}}
+	for i := range cols {
+		if !cols[i].IsComputed() || cols[i].ID == col.ID {
+			continue
+		}
+		if refers, err := computedColumnRefersTo(&cols[i], names); err != nil {
+			return nil, err
+		} else if refers {
+			return &cols[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// computedColumnRefersTo returns whether the expression of the computed
+// column col refers to one of the columns named in names.
+func computedColumnRefersTo(col *ColumnDescriptor, names map[string]struct{}) (bool, error) {
+	return exprRefersTo(*col.ComputedExpr, names)
+}
+
+// exprRefersTo returns whether the serialized expression exprStr refers to
+// one of the columns names.
+func exprRefersTo(exprStr string, names map[string]struct{}) (bool, error) {
+	expr, err := parser.ParseExpr(exprStr)
+	if err != nil {
+		return false, err
+	}
+	found := false
+	_, err = parser.SimpleVisit(expr, func(expr parser.Expr) (error, bool, parser.Expr) {
+		vBase, ok := expr.(parser.VarName)
+		if !ok {
+			return nil, !found, expr
+		}
+		v, err := vBase.NormalizeVarName()
+		if err != nil {
+			return err, false, nil
+		}
+		if c, ok := v.(*parser.ColumnItem); ok {
+			if _, ok := names[string(c.ColumnName)]; ok {
+				found = true
+			}
+		}
+		return nil, false, expr
+	})
+	return found, err
+}
+
+func newComputedColumns(tableDesc *TableDescriptor) *ComputedColumns {
+	return &ComputedColumns{
+		sourceCols:   tableDesc.Columns,
+		curSourceRow: make(parser.Datums, len(tableDesc.Columns)),
+	}
+}
+
+// add resolves the expression of the computed column col and adds it to cc.
+func (cc *ComputedColumns) add(col *ColumnDescriptor, semaCtx *parser.SemaContext) error {
+	typ := col.Type.ToDatumType()
+	typedExpr, err := cc.resolve(
+		*col.ComputedExpr, fmt.Sprintf("computed column %q", col.Name), semaCtx, typ,
+	)
+	if err != nil {
+		return err
+	}
+	if actual := typedExpr.ResolvedType(); !typ.Equivalent(actual) && typedExpr != parser.DNull {
+		return incompatibleExprTypeError("computed column", typ, actual)
+	}
+
+	cc.Cols = append(cc.Cols, *col)
+	cc.exprs = append(cc.exprs, typedExpr)
+	return nil
+}
+
+// resolve parses the expression s, resolves its column references to the
+// columns of the table and type checks it with the desired type. The
+// expression must be pure and cannot refer to computed columns; errors name
+// the expression with context.
+func (cc *ComputedColumns) resolve(
+	s string, context string, semaCtx *parser.SemaContext, desired parser.Type,
+) (parser.TypedExpr, error) {
+	raw, err := parser.ParseExpr(s)
+	if err != nil {
+		return nil, err
+	}
+	ivarHelper := parser.MakeIndexedVarHelper(cc, len(cc.sourceCols))
+	expr, err := parser.SimpleVisit(raw, func(expr parser.Expr) (error, bool, parser.Expr) {
+		switch t := expr.(type) {
+		case *parser.Subquery:
+			return pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
+				"%s cannot contain subqueries", context), false, nil
+		case parser.VarName:
+			v, err := t.NormalizeVarName()
+			if err != nil {
+				return err, false, nil
+			}
+			c, ok := v.(*parser.ColumnItem)
+			if !ok {
+				return nil, true, expr
+			}
+			for i := range cc.sourceCols {
+				if cc.sourceCols[i].Name != string(c.ColumnName) {
+					continue
+				}
+				if cc.sourceCols[i].IsComputed() {
+					return pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
+						"%s cannot reference computed column %q",
+						context, c.ColumnName), false, nil
+				}
+				return nil, false, ivarHelper.IndexedVar(i)
+			}
+			return pgerror.NewErrorf(pgerror.CodeUndefinedColumnError,
+				"column %q not found for %s", c.ColumnName, context), false, nil
+		}
+		return nil, true, expr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var p parser.Parser
+	if err := p.AssertNoAggregationOrWindowing(
+		expr, "computed column expressions", semaCtx.SearchPath,
+	); err != nil {
+		return nil, err
+	}
+	typedExpr, err := parser.TypeCheck(expr, semaCtx, desired)
+	if err != nil {
+		return nil, err
+	}
+	var impure impureFuncFinder
+	parser.WalkExprConst(&impure, typedExpr)
+	if impure.fn != nil {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
+			"%s cannot use impure function %s", context, impure.fn)
+	}
+	return typedExpr, nil
+}
+
+// impureFuncFinder finds an application of an impure function in an
+// expression.
+type impureFuncFinder struct {
+	fn *parser.FuncExpr
+}
+
+var _ parser.Visitor = &impureFuncFinder{}
+
+func (v *impureFuncFinder) VisitPre(expr parser.Expr) (recurse bool, newExpr parser.Expr) {
+	if f, ok := expr.(*parser.FuncExpr); ok && f.IsImpure() {
+		v.fn = f
+	}
+	return v.fn == nil, expr
+}
+
+func (*impureFuncFinder) VisitPost(expr parser.Expr) parser.Expr { return expr }
+
+// LoadRow sets the values the computed columns are computed from. Any value
+// not passed is set to NULL, unless merge is true, in which case it is left
+// unchanged (allowing updating a subset of a row's values).
+func (cc *ComputedColumns) LoadRow(colIdx map[ColumnID]int, row parser.Datums, merge bool) {
+	for i, col := range cc.sourceCols {
+		if ri, ok := colIdx[col.ID]; ok {
+			cc.curSourceRow[i] = row[ri]
+		} else if !merge {
+			cc.curSourceRow[i] = parser.DNull
+		}
+	}
+}
+
+// Compute returns the value of the computed column Cols[i] for the loaded row,
+// checking that it fits the column's type.
+func (cc *ComputedColumns) Compute(evalCtx *parser.EvalContext, i int) (parser.Datum, error) {
+	d, err := cc.exprs[i].Eval(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckValueWidth(cc.Cols[i], d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// ComputeInto computes the values of the computed columns for the loaded row
+// and stores them in row, at the positions given by colIdx. Computed columns
+// not in colIdx are skipped.
+func (cc *ComputedColumns) ComputeInto(
+	evalCtx *parser.EvalContext, colIdx map[ColumnID]int, row parser.Datums,
+) error {
+	for i, col := range cc.Cols {
+		ri, ok := colIdx[col.ID]
+		if !ok {
+			continue
+		}
+		d, err := cc.Compute(evalCtx, i)
+		if err != nil {
+			return err
+		}
+		row[ri] = d
+	}
+	return nil
+}
+
+// IndexedVarEval implements the parser.IndexedVarContainer interface.
+func (cc *ComputedColumns) IndexedVarEval(idx int, ctx *parser.EvalContext) (parser.Datum, error) {
+	return cc.curSourceRow[idx].Eval(ctx)
+}
+
+// IndexedVarResolvedType implements the parser.IndexedVarContainer interface.
+func (cc *ComputedColumns) IndexedVarResolvedType(idx int) parser.Type {
+	return cc.sourceCols[idx].Type.ToDatumType()
+}
+
+// IndexedVarFormat implements the parser.IndexedVarContainer interface.
+func (cc *ComputedColumns) IndexedVarFormat(buf *bytes.Buffer, f parser.FmtFlags, idx int) {
+	parser.FormatNode(buf, f, parser.Name(cc.sourceCols[idx].Name))
+}
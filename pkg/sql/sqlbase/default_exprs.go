@@ -71,8 +71,10 @@ func MakeDefaultExprs(
 	return defaultExprs, nil
 }
 
-// ProcessDefaultColumns adds columns with DEFAULT to cols if not present
-// and returns the defaultExprs for cols.
+// ProcessDefaultColumns adds columns with DEFAULT and computed columns to
+// cols if not present and returns the defaultExprs for cols. The default
+// expression of a computed column is NULL; its value is computed from the
+// other columns of the row with ComputedColumns.
 func ProcessDefaultColumns(
 	cols []ColumnDescriptor,
 	tableDesc *TableDescriptor,
@@ -84,9 +86,9 @@ func ProcessDefaultColumns(
 		colIDSet[col.ID] = struct{}{}
 	}
 
-	// Add the column if it has a DEFAULT expression.
+	// Add the column if it has a DEFAULT expression or is computed.
 	addIfDefault := func(col ColumnDescriptor) {
-		if col.DefaultExpr != nil {
+		if col.DefaultExpr != nil || col.IsComputed() {
 			if _, ok := colIDSet[col.ID]; !ok {
 				colIDSet[col.ID] = struct{}{}
 				cols = append(cols, col)
@@ -94,15 +96,17 @@ func ProcessDefaultColumns(
 		}
 	}
 
-	// Add any column that has a DEFAULT expression.
+	// Add any column that has a DEFAULT expression or is computed.
 	for _, col := range tableDesc.Columns {
 		addIfDefault(col)
 	}
 	// Also add any column in a mutation that is DELETE_AND_WRITE_ONLY and has
-	// a DEFAULT expression.
+	// a DEFAULT expression or is computed. Columns being converted from
+	// another column are skipped: their values are computed from the source
+	// column instead.
 	for _, m := range tableDesc.Mutations {
 		if col := m.GetColumn(); col != nil &&
-			m.State == DescriptorMutation_DELETE_AND_WRITE_ONLY {
+			m.State == DescriptorMutation_DELETE_AND_WRITE_ONLY && m.ConvertFromColumnID == 0 {
 			addIfDefault(*col)
 		}
 	}
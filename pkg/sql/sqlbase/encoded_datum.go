@@ -95,7 +95,15 @@ func EncDatumFromEncoded(typ ColumnType, enc DatumEncoding, encoded []byte) EncD
 func EncDatumFromBuffer(typ ColumnType, enc DatumEncoding, buf []byte) (EncDatum, []byte, error) {
 	switch enc {
 	case DatumEncoding_ASCENDING_KEY, DatumEncoding_DESCENDING_KEY:
-		encLen, err := encoding.PeekLength(buf)
+		var encLen int
+		var err error
+		if typ.SemanticType == ColumnType_JSON {
+			// JSON columns only appear in the keys of inverted indexes, which
+			// hold a path into the document rather than the document itself.
+			encLen, err = encoding.PeekJSONInvertedIndexKeyLength(buf)
+		} else {
+			encLen, err = encoding.PeekLength(buf)
+		}
 		if err != nil {
 			return EncDatum{}, nil, err
 		}
@@ -191,7 +191,8 @@ func TestEncDatumCompare(t *testing.T) {
 
 	for kind := range ColumnType_SemanticType_name {
 		kind := ColumnType_SemanticType(kind)
-		if kind == ColumnType_NULL || kind == ColumnType_ARRAY || kind == ColumnType_INT2VECTOR {
+		if kind == ColumnType_NULL || kind == ColumnType_ARRAY || kind == ColumnType_INT2VECTOR ||
+			kind == ColumnType_JSON {
 			continue
 		}
 		typ := ColumnType{SemanticType: kind}
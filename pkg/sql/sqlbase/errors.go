@@ -72,6 +72,13 @@ func NewNonNullViolationError(columnName string) error {
 	return pgerror.NewErrorf(pgerror.CodeNotNullViolationError, "null value in column %q violates not-null constraint", columnName)
 }
 
+// NewComputedColumnWriteError creates an error for an attempt to write a
+// value to a computed column.
+func NewComputedColumnWriteError(columnName string) error {
+	return pgerror.NewErrorf(pgerror.CodeInvalidColumnReferenceError,
+		"cannot write directly to computed column %q", columnName)
+}
+
 // NewUniquenessConstraintViolationError creates an error that represents a
 // violation of a UNIQUE constraint.
 func NewUniquenessConstraintViolationError(index *IndexDescriptor, vals []parser.Datum) error {
@@ -16,6 +16,7 @@ package sqlbase
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
@@ -80,6 +81,88 @@ func TablesNeededForFKs(table TableDescriptor, usage FKCheck) TableLookupsByID {
 	return ret
 }
 
+// TablesNeededForCascades adds to tables the IDs of the additional tables that
+// will be needed to apply the cascading actions of the foreign keys referencing
+// `table` when its rows are deleted or updated (as per usage), and to check the
+// foreign keys of the rows these actions change in turn.
+//
+// Cascading actions are only followed through the tables whose descriptors are
+// already set in tables, so callers need to fill in the values of the map and
+// call this again until it returns false.
+func TablesNeededForCascades(
+	table *TableDescriptor, usage FKCheck, tables TableLookupsByID,
+) (bool, error) {
+	if usage == CheckInserts {
+		return false, nil
+	}
+	type change struct {
+		table *TableDescriptor
+		usage FKCheck
+	}
+	type changeKey struct {
+		id    ID
+		usage FKCheck
+	}
+	added := false
+	queue := []change{{table: table, usage: usage}}
+	// Cascading actions can form cycles, e.g. through a self-referencing
+	// table, so every kind of change to a table is only visited once.
+	visited := map[changeKey]struct{}{{id: table.ID, usage: usage}: {}}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		for _, idx := range c.table.AllNonDropIndexes() {
+			for _, ref := range idx.ReferencedBy {
+				referencing := tables[ref.Table].Table
+				if referencing == nil {
+					// Not looked up yet, or being added and thus empty.
+					continue
+				}
+				refIdx, err := referencing.FindIndexByID(ref.Index)
+				if err != nil {
+					return false, err
+				}
+				action := referencingAction(refIdx.ForeignKey, c.usage)
+				if restrictsChanges(action) {
+					continue
+				}
+				next := change{table: referencing, usage: CheckUpdates}
+				if action == ForeignKeyReference_CASCADE {
+					next.usage = c.usage
+				}
+				key := changeKey{id: referencing.ID, usage: next.usage}
+				if _, ok := visited[key]; ok {
+					continue
+				}
+				visited[key] = struct{}{}
+				queue = append(queue, next)
+				for id := range TablesNeededForFKs(*referencing, next.usage) {
+					if _, ok := tables[id]; !ok {
+						tables[id] = TableLookup{}
+						added = true
+					}
+				}
+			}
+		}
+	}
+	return added, nil
+}
+
+// referencingAction returns the action of the foreign key ref that applies to
+// the given kind of change to the referenced rows.
+func referencingAction(ref ForeignKeyReference, usage FKCheck) ForeignKeyReference_Action {
+	if usage == CheckDeletes {
+		return ref.OnDelete
+	}
+	return ref.OnUpdate
+}
+
+// restrictsChanges returns whether the action rejects changes to referenced
+// rows instead of propagating them to the referencing rows.
+func restrictsChanges(action ForeignKeyReference_Action) bool {
+	return action == ForeignKeyReference_NO_ACTION || action == ForeignKeyReference_RESTRICT
+}
+
 type fkInsertHelper map[IndexID][]baseFKHelper
 
 var errSkipUnusedFK = errors.New("no columns involved in FK included in writer")
@@ -162,13 +245,32 @@ func (fks fkInsertHelper) CollectSpansForValues(values parser.Datums) (roachpb.S
 	return collectSpansForValuesWithFKMap(fks, values)
 }
 
-type fkDeleteHelper map[IndexID][]baseFKHelper
+// CollectCascadeSpans implements the FkSpanCollector interface.
+func (fks fkInsertHelper) CollectCascadeSpans() roachpb.Spans {
+	return nil
+}
+
+// fkDeleteHelper handles the foreign keys referencing the rows of a table that
+// are deleted or, as the inbound part of an fkUpdateHelper, updated.
+type fkDeleteHelper struct {
+	// checks are the foreign keys that reject the change of a referenced row,
+	// keyed by the referenced index.
+	checks map[IndexID][]baseFKHelper
+	// cascades are the foreign keys that propagate the change of a referenced
+	// row to the referencing rows, keyed by the referenced index.
+	cascades map[IndexID][]*fkCascader
+	// otherTables is only set if there are cascades, which may write to any of
+	// these tables.
+	otherTables TableLookupsByID
+}
 
 func makeFKDeleteHelper(
 	txn *client.Txn,
 	table TableDescriptor,
 	otherTables TableLookupsByID,
 	colMap map[ColumnID]int,
+	usage FKCheck,
+	evalCtx *parser.EvalContext,
 	alloc *DatumAlloc,
 ) (fkDeleteHelper, error) {
 	var fks fkDeleteHelper
@@ -186,17 +288,36 @@ func makeFKDeleteHelper(
 			if err != nil {
 				return fks, err
 			}
-			if fks == nil {
-				fks = make(fkDeleteHelper)
+			if action := referencingAction(fk.searchIdx.ForeignKey, usage); !restrictsChanges(action) {
+				c, err := makeFKCascader(fk, action, usage, otherTables, evalCtx, alloc)
+				if err != nil {
+					return fks, err
+				}
+				if fks.cascades == nil {
+					fks.cascades = make(map[IndexID][]*fkCascader)
+				}
+				fks.cascades[idx.ID] = append(fks.cascades[idx.ID], c)
+				fks.otherTables = otherTables
+				continue
 			}
-			fks[idx.ID] = append(fks[idx.ID], fk)
+			if fks.checks == nil {
+				fks.checks = make(map[IndexID][]baseFKHelper)
+			}
+			fks.checks[idx.ID] = append(fks.checks[idx.ID], fk)
 		}
 	}
 	return fks, nil
 }
 
+// checkAll applies the cascading actions for the deletion of row and then
+// checks that it is no longer referenced.
 func (fks fkDeleteHelper) checkAll(ctx context.Context, row parser.Datums, traceKV bool) error {
-	for idx := range fks {
+	for idx := range fks.cascades {
+		if err := fks.cascadeIdx(ctx, idx, row, nil /* newRow */, traceKV); err != nil {
+			return err
+		}
+	}
+	for idx := range fks.checks {
 		if err := fks.checkIdx(ctx, idx, row, traceKV); err != nil {
 			return err
 		}
@@ -204,10 +325,35 @@ func (fks fkDeleteHelper) checkAll(ctx context.Context, row parser.Datums, trace
 	return nil
 }
 
+// cascadeIdx applies the cascading actions of the foreign keys referencing
+// index idx to the rows referencing oldRow, which is being deleted if newRow
+// is nil and updated to newRow otherwise.
+func (fks fkDeleteHelper) cascadeIdx(
+	ctx context.Context, idx IndexID, oldRow, newRow parser.Datums, traceKV bool,
+) error {
+	for _, c := range fks.cascades[idx] {
+		if err := c.cascade(ctx, oldRow, newRow, traceKV); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inheritCascadeState sets up the cascades of fks, which are applied for the
+// changes made by parent, to track the rows changed by the same statement.
+func (fks fkDeleteHelper) inheritCascadeState(parent *fkCascader) {
+	for _, cascades := range fks.cascades {
+		for _, c := range cascades {
+			c.depth = parent.depth + 1
+			c.changed = parent.changed
+		}
+	}
+}
+
 func (fks fkDeleteHelper) checkIdx(
 	ctx context.Context, idx IndexID, row parser.Datums, traceKV bool,
 ) error {
-	for _, fk := range fks[idx] {
+	for _, fk := range fks.checks[idx] {
 		found, err := fk.check(ctx, row, traceKV)
 		if err != nil {
 			return err
@@ -233,12 +379,28 @@ func (fks fkDeleteHelper) checkIdx(
 
 // CollectSpans implements the FkSpanCollector interface.
 func (fks fkDeleteHelper) CollectSpans() roachpb.Spans {
-	return collectSpansWithFKMap(fks)
+	return collectSpansWithFKMap(fks.checks)
 }
 
 // CollectSpansForValues implements the FkSpanCollector interface.
 func (fks fkDeleteHelper) CollectSpansForValues(values parser.Datums) (roachpb.Spans, error) {
-	return collectSpansForValuesWithFKMap(fks, values)
+	return collectSpansForValuesWithFKMap(fks.checks, values)
+}
+
+// CollectCascadeSpans implements the FkSpanCollector interface.
+func (fks fkDeleteHelper) CollectCascadeSpans() roachpb.Spans {
+	if len(fks.cascades) == 0 {
+		return nil
+	}
+	// The rows changed by cascading actions can be anywhere in the tables
+	// reached through them.
+	var writes roachpb.Spans
+	for _, lookup := range fks.otherTables {
+		if lookup.Table != nil {
+			writes = append(writes, lookup.Table.AllIndexSpans()...)
+		}
+	}
+	return writes
 }
 
 type fkUpdateHelper struct {
@@ -251,11 +413,14 @@ func makeFKUpdateHelper(
 	table TableDescriptor,
 	otherTables TableLookupsByID,
 	colMap map[ColumnID]int,
+	evalCtx *parser.EvalContext,
 	alloc *DatumAlloc,
 ) (fkUpdateHelper, error) {
 	ret := fkUpdateHelper{}
 	var err error
-	if ret.inbound, err = makeFKDeleteHelper(txn, table, otherTables, colMap, alloc); err != nil {
+	if ret.inbound, err = makeFKDeleteHelper(
+		txn, table, otherTables, colMap, CheckUpdates, evalCtx, alloc,
+	); err != nil {
 		return ret, err
 	}
 	ret.outbound, err = makeFKInsertHelper(txn, table, otherTables, colMap, alloc)
@@ -265,6 +430,9 @@ func makeFKUpdateHelper(
 func (fks fkUpdateHelper) checkIdx(
 	ctx context.Context, idx IndexID, oldValues, newValues parser.Datums, traceKV bool,
 ) error {
+	if err := fks.inbound.cascadeIdx(ctx, idx, oldValues, newValues, traceKV); err != nil {
+		return err
+	}
 	if err := fks.inbound.checkIdx(ctx, idx, oldValues, traceKV); err != nil {
 		return err
 	}
@@ -291,6 +459,329 @@ func (fks fkUpdateHelper) CollectSpansForValues(values parser.Datums) (roachpb.S
 	return append(inboundReads, outboundReads...), nil
 }
 
+// CollectCascadeSpans implements the FkSpanCollector interface.
+func (fks fkUpdateHelper) CollectCascadeSpans() roachpb.Spans {
+	return fks.inbound.CollectCascadeSpans()
+}
+
+// maxFKCascadeDepth is the maximum number of cascading actions that a change
+// to a row can trigger in a chain, e.g. through a long hierarchy of rows in a
+// self-referencing table.
+const maxFKCascadeDepth = 64
+
+// fkCascadeKey identifies a row changed by a cascading action: the encoded
+// primary key of the row and, for updates, the index holding the foreign key
+// whose action changed it.
+type fkCascadeKey struct {
+	idx IndexID
+	key string
+}
+
+// fkCascader applies the cascading action of a foreign key to the rows
+// referencing a row that is deleted or updated.
+type fkCascader struct {
+	// search finds the referencing rows. Its searchTable is the referencing
+	// table and its writeIdx the referenced index.
+	search      baseFKHelper
+	searchCols  map[ColumnID]int
+	action      ForeignKeyReference_Action
+	usage       FKCheck
+	otherTables TableLookupsByID
+	evalCtx     *parser.EvalContext
+	alloc       *DatumAlloc
+
+	// depth is the number of cascading actions leading to the changes this
+	// cascader is applied for.
+	depth int
+	// changed holds the rows already changed by the cascading actions of a
+	// statement, and is shared by all the cascaders applied for its changes.
+	// Cascading actions can lead back to a row they already changed, e.g. in a
+	// self-referencing table, which would otherwise cascade forever.
+	changed map[fkCascadeKey]struct{}
+
+	// The writers of the referencing rows are only set up when the cascader is
+	// first applied, since setting them up sets up the cascaders for the rows
+	// they change in turn, which can lead back to this foreign key.
+	initialized bool
+	rd          RowDeleter
+	ru          RowUpdater
+	rowFetcher  RowFetcher
+	updateCols  []ColumnDescriptor
+	defaults    []parser.TypedExpr
+	// computedCols computes the values of the computed columns at the end of
+	// updateCols, after the referencing columns.
+	computedCols *ComputedColumns
+}
+
+func makeFKCascader(
+	search baseFKHelper,
+	action ForeignKeyReference_Action,
+	usage FKCheck,
+	otherTables TableLookupsByID,
+	evalCtx *parser.EvalContext,
+	alloc *DatumAlloc,
+) (*fkCascader, error) {
+	c := &fkCascader{
+		search:      search,
+		action:      action,
+		usage:       usage,
+		otherTables: otherTables,
+		evalCtx:     evalCtx,
+		alloc:       alloc,
+		changed:     make(map[fkCascadeKey]struct{}),
+	}
+	// The search only needs the primary key of the referencing rows, which
+	// are then fetched in full from the primary index.
+	table := c.search.searchTable
+	c.searchCols = ColIDtoRowIndexFromCols(table.Columns)
+	valNeededForCol := make([]bool, len(table.Columns))
+	for _, colID := range table.PrimaryIndex.ColumnIDs {
+		valNeededForCol[c.searchCols[colID]] = true
+	}
+	isSecondary := table.PrimaryIndex.ID != c.search.searchIdx.ID
+	if err := c.search.rf.Init(table, c.searchCols, c.search.searchIdx, false, /* reverse */
+		isSecondary, table.Columns, valNeededForCol,
+		false /* returnRangeInfo */, alloc); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// deletes returns whether the cascader deletes the referencing rows, as
+// opposed to updating their referencing columns.
+func (c *fkCascader) deletes() bool {
+	return c.usage == CheckDeletes && c.action == ForeignKeyReference_CASCADE
+}
+
+// init sets up the writer of the referencing rows and the fetcher of their
+// current values.
+func (c *fkCascader) init() error {
+	table := c.search.searchTable
+	var fetchCols []ColumnDescriptor
+	var fetchColIDtoRowIndex map[ColumnID]int
+	if c.deletes() {
+		rd, err := MakeRowDeleter(c.search.txn, table, c.otherTables, nil, /* requestedCols */
+			CheckFKs, c.evalCtx, c.alloc)
+		if err != nil {
+			return err
+		}
+		c.rd = rd
+		c.rd.Fks.inheritCascadeState(c)
+		fetchCols, fetchColIDtoRowIndex = c.rd.FetchCols, c.rd.FetchColIDtoRowIndex
+	} else {
+		c.updateCols = make([]ColumnDescriptor, c.search.prefixLen)
+		for i, colID := range c.search.searchIdx.ColumnIDs[:c.search.prefixLen] {
+			col, err := table.FindColumnByID(colID)
+			if err != nil {
+				return err
+			}
+			c.updateCols[i] = *col
+		}
+		// The computed columns depending on the referencing columns are
+		// updated too, from all the values of the row.
+		computedUpdateCols, err := ComputedColumnsToUpdate(table, c.updateCols)
+		if err != nil {
+			return err
+		}
+		if c.computedCols, err = MakeComputedColumns(table, computedUpdateCols); err != nil {
+			return err
+		}
+		c.updateCols = append(c.updateCols, computedUpdateCols...)
+		var requestedCols []ColumnDescriptor
+		if c.computedCols != nil {
+			requestedCols = table.Columns
+		}
+		ru, err := MakeRowUpdater(c.search.txn, table, c.otherTables, c.updateCols,
+			requestedCols, RowUpdaterDefault, c.evalCtx, c.alloc)
+		if err != nil {
+			return err
+		}
+		c.ru = ru
+		if c.action == ForeignKeyReference_CASCADE {
+			// The new values of the referenced row are only written after the
+			// referencing rows are updated to them.
+			delete(c.ru.Fks.outbound, c.search.searchIdx.ID)
+		}
+		c.ru.Fks.inbound.inheritCascadeState(c)
+		if c.action == ForeignKeyReference_SET_DEFAULT {
+			if c.defaults, err = MakeDefaultExprs(c.updateCols, &parser.Parser{}, c.evalCtx); err != nil {
+				return err
+			}
+		}
+		fetchCols, fetchColIDtoRowIndex = c.ru.FetchCols, c.ru.FetchColIDtoRowIndex
+	}
+
+	valNeededForCol := make([]bool, len(fetchCols))
+	for i := range valNeededForCol {
+		valNeededForCol[i] = true
+	}
+	if err := c.rowFetcher.Init(table, fetchColIDtoRowIndex, &table.PrimaryIndex,
+		false /* reverse */, false /* isSecondaryIndex */, fetchCols, valNeededForCol,
+		false /* returnRangeInfo */, c.alloc); err != nil {
+		return err
+	}
+	c.initialized = true
+	return nil
+}
+
+// cascade applies the action to the rows referencing oldRow, which is being
+// deleted if newRow is nil and updated to newRow otherwise. The changes are
+// written before returning, in the same transaction as the change to the
+// referenced row.
+func (c *fkCascader) cascade(
+	ctx context.Context, oldRow, newRow parser.Datums, traceKV bool,
+) error {
+	for _, idx := range c.search.ids {
+		// NULLs don't reference any row.
+		if oldRow[idx] == parser.DNull {
+			return nil
+		}
+	}
+	if newRow != nil {
+		unchanged := true
+		for _, idx := range c.search.ids {
+			if oldRow[idx].Compare(c.evalCtx, newRow[idx]) != 0 {
+				unchanged = false
+				break
+			}
+		}
+		if unchanged {
+			return nil
+		}
+	}
+
+	// Find the primary keys of the referencing rows.
+	span, err := c.search.spanForValues(oldRow)
+	if err != nil {
+		return err
+	}
+	if err := c.search.rf.StartScan(
+		ctx, c.search.txn, roachpb.Spans{span}, false /* limitBatches */, 0, traceKV,
+	); err != nil {
+		return err
+	}
+	table := c.search.searchTable
+	primaryPrefix := MakeIndexKeyPrefix(table, table.PrimaryIndex.ID)
+	var spans roachpb.Spans
+	for {
+		row, err := c.search.rf.NextRowDecoded(ctx, traceKV)
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			break
+		}
+		keyBytes, _, err := EncodeIndexKey(table, &table.PrimaryIndex, c.searchCols, row, primaryPrefix)
+		if err != nil {
+			return err
+		}
+		changedKey := fkCascadeKey{key: string(keyBytes)}
+		if !c.deletes() {
+			changedKey.idx = c.search.searchIdx.ID
+		}
+		if _, ok := c.changed[changedKey]; ok {
+			continue
+		}
+		c.changed[changedKey] = struct{}{}
+		key := roachpb.Key(keyBytes)
+		spans = append(spans, roachpb.Span{Key: key, EndKey: key.PrefixEnd()})
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+
+	if c.depth >= maxFKCascadeDepth {
+		return pgerror.NewErrorf(pgerror.CodeTriggeredActionExceptionError,
+			"foreign key cascades on table %q exceed the maximum depth of %d",
+			table.Name, maxFKCascadeDepth)
+	}
+	if !c.initialized {
+		if err := c.init(); err != nil {
+			return err
+		}
+	}
+
+	// Fetch the referencing rows. Their values must be copied since they are
+	// only valid until the next row is fetched, and changing a row can lead to
+	// uses of this cascader for other rows.
+	sort.Sort(spans)
+	if err := c.rowFetcher.StartScan(
+		ctx, c.search.txn, spans, false /* limitBatches */, 0, traceKV,
+	); err != nil {
+		return err
+	}
+	var rows []parser.Datums
+	for {
+		row, err := c.rowFetcher.NextRowDecoded(ctx, traceKV)
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			break
+		}
+		rows = append(rows, append(parser.Datums(nil), row...))
+	}
+
+	var updateValues parser.Datums
+	if !c.deletes() {
+		updateValues = make(parser.Datums, len(c.updateCols))
+		for i := range updateValues[:c.search.prefixLen] {
+			switch c.action {
+			case ForeignKeyReference_CASCADE:
+				updateValues[i] = newRow[c.search.ids[c.search.searchIdx.ColumnIDs[i]]]
+			case ForeignKeyReference_SET_DEFAULT:
+				if c.defaults != nil {
+					if updateValues[i], err = c.defaults[i].Eval(c.evalCtx); err != nil {
+						return err
+					}
+					break
+				}
+				fallthrough
+			default:
+				updateValues[i] = parser.DNull
+			}
+		}
+		if c.action == ForeignKeyReference_SET_DEFAULT {
+			// Rows whose default values reference the changed row can't be
+			// changed to them.
+			fkValues := make(parser.Datums, c.search.prefixLen)
+			stillReferenced := true
+			for i, colID := range c.search.searchIdx.ColumnIDs[:c.search.prefixLen] {
+				fkValues[i] = oldRow[c.search.ids[colID]]
+				if updateValues[i] == parser.DNull || fkValues[i].Compare(c.evalCtx, updateValues[i]) != 0 {
+					stillReferenced = false
+				}
+			}
+			if stillReferenced {
+				return pgerror.NewErrorf(pgerror.CodeForeignKeyViolationError,
+					"foreign key violation: values %v in columns %s referenced in table %q",
+					fkValues, c.search.writeIdx.ColumnNames[:c.search.prefixLen], table.Name)
+			}
+		}
+	}
+
+	b := c.search.txn.NewBatch()
+	for _, row := range rows {
+		if c.deletes() {
+			err = c.rd.DeleteRow(ctx, b, row, traceKV)
+		} else {
+			if c.computedCols != nil {
+				c.computedCols.LoadRow(c.ru.FetchColIDtoRowIndex, row, false)
+				c.computedCols.LoadRow(c.ru.updateColIDtoRowIndex, updateValues, true)
+				err = c.computedCols.ComputeInto(c.evalCtx, c.ru.updateColIDtoRowIndex, updateValues)
+				if err != nil {
+					return err
+				}
+			}
+			_, err = c.ru.UpdateRow(ctx, b, row, updateValues, traceKV)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return c.search.txn.Run(ctx, b)
+}
+
 type baseFKHelper struct {
 	txn          *client.Txn
 	rf           RowFetcher
@@ -389,6 +880,9 @@ func (f baseFKHelper) span() roachpb.Span {
 type FkSpanCollector interface {
 	CollectSpans() roachpb.Spans
 	CollectSpansForValues(values parser.Datums) (roachpb.Spans, error)
+	// CollectCascadeSpans returns the spans that cascading foreign key actions
+	// may write to.
+	CollectCascadeSpans() roachpb.Spans
 }
 
 var _ FkSpanCollector = fkInsertHelper{}
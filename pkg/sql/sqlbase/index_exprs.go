@@ -0,0 +1,183 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// indexExprColumnPrefix is the prefix of the names of the hidden computed
+// columns storing the values of the expressions of expression indexes.
+const indexExprColumnPrefix = "crdb_idx_"
+
+// ResolveIndexExprs replaces each expression among elems, the elements of a
+// new index of tableDesc, with a new hidden computed column storing the value
+// of the expression. It returns the new columns, which the caller must add to
+// tableDesc along with the index, and the elements naming them. The returned
+// elements keep their expressions, in a normalized form, for FillColumns.
+func ResolveIndexExprs(
+	tableDesc *TableDescriptor, elems parser.IndexElemList, searchPath parser.SearchPath,
+) (parser.IndexElemList, []ColumnDescriptor, error) {
+	var res parser.IndexElemList
+	var cols []ColumnDescriptor
+	for i, elem := range elems {
+		if elem.Expr == nil {
+			continue
+		}
+		if res == nil {
+			res = append(parser.IndexElemList(nil), elems...)
+		}
+		col, expr, err := makeIndexExprColumn(tableDesc, elem.Expr, cols, searchPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		res[i].Column = parser.Name(col.Name)
+		res[i].Expr = expr
+		cols = append(cols, col)
+	}
+	if res == nil {
+		return elems, nil, nil
+	}
+	return res, cols, nil
+}
+
+// makeIndexExprColumn creates the hidden computed column storing the value of
+// the index expression expr, whose name must differ from the columns of
+// tableDesc and from added. It also returns the normalized expression.
+func makeIndexExprColumn(
+	tableDesc *TableDescriptor,
+	expr parser.Expr,
+	added []ColumnDescriptor,
+	searchPath parser.SearchPath,
+) (ColumnDescriptor, parser.Expr, error) {
+	typedExpr, err := newComputedColumns(tableDesc).resolve(
+		parser.Serialize(expr), "index expression", &parser.SemaContext{SearchPath: searchPath},
+		parser.TypeAny,
+	)
+	if err != nil {
+		return ColumnDescriptor{}, nil, err
+	}
+	colType, err := DatumTypeToColumnType(typedExpr.ResolvedType())
+	if err != nil {
+		return ColumnDescriptor{}, nil, pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
+			"cannot index expression %s of type %s", expr, typedExpr.ResolvedType())
+	}
+	// The column references of the typed expression are formatted as the
+	// names of the columns, so it can be parsed back.
+	s := parser.Serialize(typedExpr)
+	normalized, err := parser.ParseExpr(s)
+	if err != nil {
+		return ColumnDescriptor{}, nil, err
+	}
+
+	return ColumnDescriptor{
+		Name:         hiddenColumnName(tableDesc, added, indexExprColumnPrefix+indexExprName(normalized)),
+		Type:         colType,
+		Nullable:     true,
+		Hidden:       true,
+		ComputedExpr: &s,
+	}, normalized, nil
+}
+
+// hiddenColumnName returns a name for a new hidden column of tableDesc derived
+// from baseName, which differs from the names of the columns of tableDesc and
+// of added.
+func hiddenColumnName(tableDesc *TableDescriptor, added []ColumnDescriptor, baseName string) string {
+	taken := func(name string) bool {
+		if _, _, err := tableDesc.FindColumnByName(parser.Name(name)); err == nil {
+			return true
+		}
+		for _, col := range added {
+			if col.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+	name := baseName
+	for i := 1; taken(name); i++ {
+		name = fmt.Sprintf("%s%d", baseName, i)
+	}
+	return name
+}
+
+// indexExprName returns a short name describing the index expression expr,
+// used in the names of the index and of the column storing its values: the
+// name of the function for function applications, like PostgreSQL, and
+// "expr" otherwise.
+func indexExprName(expr parser.Expr) string {
+	f, ok := expr.(*parser.FuncExpr)
+	if !ok {
+		return "expr"
+	}
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, parser.AsString(f.Func))
+	if name == "" {
+		return "expr"
+	}
+	return name
+}
+
+// formatIndexExpr formats the index expression s for an index definition,
+// surrounding it with parentheses unless it is a function application.
+func formatIndexExpr(s string) string {
+	if expr, err := parser.ParseExpr(s); err == nil {
+		if _, ok := expr.(*parser.FuncExpr); ok {
+			return s
+		}
+	}
+	return "(" + s + ")"
+}
+
+// IsExprColumn returns whether the i-th column of the index stores the value
+// of an expression instead of a column of the table.
+func (desc *IndexDescriptor) IsExprColumn(i int) bool {
+	return len(desc.ColumnExprs) > 0 && desc.ColumnExprs[i] != ""
+}
+
+// HasExprs returns whether some of the columns of the index store the values
+// of expressions.
+func (desc *IndexDescriptor) HasExprs() bool {
+	for i := range desc.ColumnExprs {
+		if desc.IsExprColumn(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// IndexExprRefersTo returns whether the expression of the i-th column of the
+// index idx of tableDesc refers to the column col.
+func IndexExprRefersTo(
+	tableDesc *TableDescriptor, idx *IndexDescriptor, i int, col ColumnDescriptor,
+) (bool, error) {
+	if !idx.IsExprColumn(i) {
+		return false, nil
+	}
+	exprCol, err := tableDesc.FindColumnByID(idx.ColumnIDs[i])
+	if err != nil {
+		return false, err
+	}
+	return computedColumnRefersTo(exprCol, map[string]struct{}{col.Name: {}})
+}
@@ -0,0 +1,75 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+)
+
+// indexPredicateColumnName is the base name of the hidden computed columns
+// storing the values of the predicates of partial indexes.
+const indexPredicateColumnName = indexExprColumnPrefix + "pred"
+
+// MakeIndexPredicateColumn creates the hidden computed column storing the
+// value of pred, the predicate of a new partial index of tableDesc, whose name
+// must differ from the columns of tableDesc and from added. It also returns
+// the normalized predicate, for the Predicate of the index. The caller must
+// add the column to tableDesc along with the index.
+func MakeIndexPredicateColumn(
+	tableDesc *TableDescriptor,
+	pred parser.Expr,
+	added []ColumnDescriptor,
+	searchPath parser.SearchPath,
+) (ColumnDescriptor, string, error) {
+	typedExpr, err := newComputedColumns(tableDesc).resolve(
+		parser.Serialize(pred), "index predicate", &parser.SemaContext{SearchPath: searchPath},
+		parser.TypeBool,
+	)
+	if err != nil {
+		return ColumnDescriptor{}, "", err
+	}
+	if typ := typedExpr.ResolvedType(); !parser.TypeBool.Equivalent(typ) && typedExpr != parser.DNull {
+		return ColumnDescriptor{}, "", incompatibleExprTypeError("index predicate", parser.TypeBool, typ)
+	}
+	s := parser.Serialize(typedExpr)
+	return ColumnDescriptor{
+		Name:         hiddenColumnName(tableDesc, added, indexPredicateColumnName),
+		Type:         ColumnType{SemanticType: ColumnType_BOOL},
+		Nullable:     true,
+		Hidden:       true,
+		ComputedExpr: &s,
+	}, s, nil
+}
+
+// IsPartial returns whether the index only contains the rows its predicate
+// holds for.
+func (desc *IndexDescriptor) IsPartial() bool {
+	return desc.PredicateColumnID != 0
+}
+
+// IndexPredicateRefersTo returns whether the predicate of the index idx of
+// tableDesc refers to the column col.
+func IndexPredicateRefersTo(
+	tableDesc *TableDescriptor, idx *IndexDescriptor, col ColumnDescriptor,
+) (bool, error) {
+	if !idx.IsPartial() {
+		return false, nil
+	}
+	predCol, err := tableDesc.FindColumnByID(idx.PredicateColumnID)
+	if err != nil {
+		return false, err
+	}
+	return computedColumnRefersTo(predCol, map[string]struct{}{col.Name: {}})
+}
@@ -0,0 +1,79 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/security"
+)
+
+// PublicRole is the pseudo-role naming all the users in the roles of a
+// policy.
+const PublicRole = "public"
+
+// FindPolicyByName returns the index of the row-level security policy of the
+// table named name, or -1 if there is none.
+func (desc *TableDescriptor) FindPolicyByName(name string) int {
+	for i := range desc.Policies {
+		if desc.Policies[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// RowLevelSecurityApplies returns whether the access of user to the rows of
+// the table is restricted by its policies.
+func (desc *TableDescriptor) RowLevelSecurityApplies(user string) bool {
+	return desc.RowLevelSecurity && user != security.RootUser
+}
+
+// AppliesTo returns whether the policy applies to user, whose roles are given
+// by memberOf.
+func (p *TableDescriptor_Policy) AppliesTo(user string, memberOf map[string]bool) bool {
+	if len(p.Roles) == 0 {
+		return true
+	}
+	for _, role := range p.Roles {
+		if role == PublicRole || role == user {
+			return true
+		}
+		if _, ok := memberOf[role]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyReferencing returns a row-level security policy of tableDesc whose
+// predicates refer to the column col, or nil if there is none.
+func PolicyReferencing(
+	tableDesc *TableDescriptor, col ColumnDescriptor,
+) (*TableDescriptor_Policy, error) {
+	names := map[string]struct{}{col.Name: {}}
+	for i := range tableDesc.Policies {
+		policy := &tableDesc.Policies[i]
+		for _, expr := range []string{policy.UsingExpr, policy.CheckExpr} {
+			if expr == "" {
+				continue
+			}
+			if refers, err := exprRefersTo(expr, names); err != nil {
+				return nil, err
+			} else if refers {
+				return policy, nil
+			}
+		}
+	}
+	return nil, nil
+}
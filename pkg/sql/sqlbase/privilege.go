@@ -200,6 +200,20 @@ func (p PrivilegeDescriptor) Validate(id ID) error {
 	return nil
 }
 
+// ValidateColumn is called when writing a table descriptor for the
+// privileges of each of its columns, which can only grant the privileges
+// in privilege.ColumnData, and need not grant anything to the root user.
+func (p PrivilegeDescriptor) ValidateColumn() error {
+	allowed := privilege.ColumnData.ToBitField()
+	for _, u := range p.Users {
+		if remaining := u.Privileges &^ allowed; remaining != 0 {
+			return fmt.Errorf("user %s must not have %s privileges on a column",
+				u.User, privilege.ListFromBitField(remaining))
+		}
+	}
+	return nil
+}
+
 // UserPrivilegeString is a pair of strings describing the
 // privileges for a given user.
 type UserPrivilegeString struct {
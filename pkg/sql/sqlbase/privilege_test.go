@@ -191,6 +191,31 @@ func TestPrivilegeValidate(t *testing.T) {
 	}
 }
 
+// TestPrivilegeValidateColumn exercises validation for column privileges.
+func TestPrivilegeValidateColumn(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	descriptor := &PrivilegeDescriptor{}
+	if err := descriptor.ValidateColumn(); err != nil {
+		t.Fatal(err)
+	}
+	descriptor.Grant("foo", privilege.List{privilege.SELECT, privilege.UPDATE})
+	descriptor.Grant("bar", privilege.ColumnData)
+	if err := descriptor.ValidateColumn(); err != nil {
+		t.Fatal(err)
+	}
+	descriptor.Grant("foo", privilege.List{privilege.DELETE})
+	if err := descriptor.ValidateColumn(); !testutils.IsError(
+		err, "user foo must not have DELETE privileges on a column",
+	) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	descriptor.Revoke("foo", privilege.List{privilege.DELETE})
+	descriptor.Grant("bar", privilege.List{privilege.ALL})
+	if err := descriptor.ValidateColumn(); err == nil {
+		t.Fatal("unexpected success")
+	}
+}
+
 // TestSystemPrivilegeValidate exercises validation for system config
 // descriptors. We use a dummy system table installed for testing
 // purposes.
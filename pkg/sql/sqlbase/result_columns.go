@@ -30,6 +30,10 @@ type ResultColumn struct {
 
 	// If set, a value won't be produced for this column; used internally.
 	Omitted bool
+
+	// If set, the session user doesn't have the privilege to read this
+	// column, which queries can't refer to; used for column privileges.
+	Unreadable bool
 }
 
 // ResultColumns is the type used throughout the sql module to
@@ -166,12 +166,22 @@ func (rf *RowFetcher) Init(
 	for i, id := range indexColumnIDs {
 		rf.indexColIdx[i] = rf.colIdxMap[id]
 	}
+	if index.Type == IndexDescriptor_INVERTED {
+		// The key of an inverted index only holds a path into the indexed
+		// document, which can't be used as the value of the column.
+		rf.indexColIdx[0] = -1
+	}
 
 	if isSecondaryIndex {
 		for i := range rf.cols {
 			if rf.neededCols.Contains(uint32(rf.cols[i].ID)) && !index.ContainsColumnID(rf.cols[i].ID) {
 				return fmt.Errorf("requested column %s not in index", rf.cols[i].Name)
 			}
+			if rf.neededCols.Contains(uint32(rf.cols[i].ID)) &&
+				index.Type == IndexDescriptor_INVERTED && index.ColumnIDs[0] == rf.cols[i].ID {
+				return fmt.Errorf("column %s can't be fetched from inverted index %s",
+					rf.cols[i].Name, index.Name)
+			}
 		}
 	}
 
@@ -329,7 +339,9 @@ func (rf *RowFetcher) processKV(
 
 		// Fill in the column values that are part of the index key.
 		for i, v := range rf.keyVals {
-			rf.row[rf.indexColIdx[i]] = v
+			if idx := rf.indexColIdx[i]; idx >= 0 {
+				rf.row[idx] = v
+			}
 		}
 	}
 
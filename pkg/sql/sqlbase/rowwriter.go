@@ -55,12 +55,7 @@ type rowHelper struct {
 func (rh *rowHelper) encodeIndexes(
 	colIDtoRowIndex map[ColumnID]int, values []parser.Datum,
 ) (primaryIndexKey []byte, secondaryIndexEntries []IndexEntry, err error) {
-	if rh.primaryIndexKeyPrefix == nil {
-		rh.primaryIndexKeyPrefix = MakeIndexKeyPrefix(rh.TableDesc,
-			rh.TableDesc.PrimaryIndex.ID)
-	}
-	primaryIndexKey, _, err = EncodeIndexKey(
-		rh.TableDesc, &rh.TableDesc.PrimaryIndex, colIDtoRowIndex, values, rh.primaryIndexKeyPrefix)
+	primaryIndexKey, err = rh.encodePrimaryIndex(colIDtoRowIndex, values)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -71,17 +66,27 @@ func (rh *rowHelper) encodeIndexes(
 	return primaryIndexKey, secondaryIndexEntries, nil
 }
 
+// encodePrimaryIndex encodes the primary index key.
+func (rh *rowHelper) encodePrimaryIndex(
+	colIDtoRowIndex map[ColumnID]int, values []parser.Datum,
+) (primaryIndexKey []byte, err error) {
+	if rh.primaryIndexKeyPrefix == nil {
+		rh.primaryIndexKeyPrefix = MakeIndexKeyPrefix(rh.TableDesc,
+			rh.TableDesc.PrimaryIndex.ID)
+	}
+	primaryIndexKey, _, err = EncodeIndexKey(
+		rh.TableDesc, &rh.TableDesc.PrimaryIndex, colIDtoRowIndex, values, rh.primaryIndexKeyPrefix)
+	return primaryIndexKey, err
+}
+
 // encodeSecondaryIndexes encodes the secondary index keys. The
 // secondaryIndexEntries are only valid until the next call to encodeIndexes or
 // encodeSecondaryIndexes.
 func (rh *rowHelper) encodeSecondaryIndexes(
 	colIDtoRowIndex map[ColumnID]int, values []parser.Datum,
 ) (secondaryIndexEntries []IndexEntry, err error) {
-	if len(rh.indexEntries) != len(rh.Indexes) {
-		rh.indexEntries = make([]IndexEntry, len(rh.Indexes))
-	}
-	err = EncodeSecondaryIndexes(
-		rh.TableDesc, rh.Indexes, colIDtoRowIndex, values, rh.indexEntries)
+	rh.indexEntries, err = EncodeSecondaryIndexes(
+		rh.TableDesc, rh.Indexes, colIDtoRowIndex, values, rh.indexEntries[:0])
 	if err != nil {
 		return nil, err
 	}
@@ -144,8 +149,17 @@ type RowInserter struct {
 	InsertColIDtoRowIndex map[ColumnID]int
 	Fks                   fkInsertHelper
 
+	// conversions are the columns being converted to a new type from one of
+	// the InsertCols. Their values are appended to the inserted row, after the
+	// values of InsertCols; writeCols and writeColIDtoRowIndex describe that
+	// extended row.
+	conversions          []columnConversion
+	writeCols            []ColumnDescriptor
+	writeColIDtoRowIndex map[ColumnID]int
+
 	// For allocation avoidance.
 	marshalled []roachpb.Value
+	values     []parser.Datum
 	key        roachpb.Key
 	valueBuf   []byte
 	scratch    []byte
@@ -178,8 +192,36 @@ func MakeRowInserter(
 		Helper:                rowHelper{TableDesc: tableDesc, Indexes: indexes},
 		InsertCols:            insertCols,
 		InsertColIDtoRowIndex: ColIDtoRowIndexFromCols(insertCols),
-		marshalled:            make([]roachpb.Value, len(insertCols)),
 	}
+	ri.writeCols, ri.writeColIDtoRowIndex = ri.InsertCols, ri.InsertColIDtoRowIndex
+
+	if err := forEachColumnConversion(tableDesc, func(source, col ColumnDescriptor) error {
+		sourceIdx, ok := ri.InsertColIDtoRowIndex[source.ID]
+		if !ok {
+			return nil
+		}
+		if _, ok := ri.InsertColIDtoRowIndex[col.ID]; ok {
+			// The caller is already providing the converted value.
+			return nil
+		}
+		converter, err := MakeColumnConverter(source, col)
+		if err != nil {
+			return err
+		}
+		if len(ri.conversions) == 0 {
+			ri.writeCols = append([]ColumnDescriptor(nil), insertCols...)
+			ri.writeColIDtoRowIndex = ColIDtoRowIndexFromCols(insertCols)
+		}
+		ri.conversions = append(ri.conversions, columnConversion{
+			converter: converter, sourceIdx: sourceIdx, targetIdx: len(ri.writeCols),
+		})
+		ri.writeColIDtoRowIndex[col.ID] = len(ri.writeCols)
+		ri.writeCols = append(ri.writeCols, col)
+		return nil
+	}); err != nil {
+		return RowInserter{}, err
+	}
+	ri.marshalled = make([]roachpb.Value, len(ri.writeCols))
 
 	for i, col := range tableDesc.PrimaryIndex.ColumnIDs {
 		if _, ok := ri.InsertColIDtoRowIndex[col]; !ok {
@@ -239,13 +281,26 @@ func (ri *RowInserter) InsertRow(
 		putFn = insertPutFn
 	}
 
+	if len(ri.conversions) > 0 {
+		// Extend the row with the values of the columns being converted.
+		ri.values = append(ri.values[:0], values...)
+		for _, c := range ri.conversions {
+			val, err := c.converter.Convert(values[c.sourceIdx])
+			if err != nil {
+				return err
+			}
+			ri.values = append(ri.values, val)
+		}
+		values = ri.values
+	}
+
 	// Encode the values to the expected column type. This needs to
 	// happen before index encoding because certain datum types (i.e. tuple)
 	// cannot be used as index values.
 	for i, val := range values {
 		// Make sure the value can be written to the column before proceeding.
 		var err error
-		if ri.marshalled[i], err = MarshalColumnValue(ri.InsertCols[i], val); err != nil {
+		if ri.marshalled[i], err = MarshalColumnValue(ri.writeCols[i], val); err != nil {
 			return err
 		}
 	}
@@ -275,7 +330,7 @@ func (ri *RowInserter) InsertRow(
 			// Storage optimization to store DefaultColumnID directly as a value. Also
 			// backwards compatible with the original BaseFormatVersion.
 
-			idx, ok := ri.InsertColIDtoRowIndex[family.DefaultColumnID]
+			idx, ok := ri.writeColIDtoRowIndex[family.DefaultColumnID]
 			if !ok {
 				continue
 			}
@@ -302,7 +357,7 @@ func (ri *RowInserter) InsertRow(
 			panic("invalid family sorted column id map")
 		}
 		for _, colID := range familySortedColumnIDs {
-			idx, ok := ri.InsertColIDtoRowIndex[colID]
+			idx, ok := ri.writeColIDtoRowIndex[colID]
 			if !ok || values[idx] == parser.DNull {
 				// Column not being inserted.
 				continue
@@ -314,7 +369,7 @@ func (ri *RowInserter) InsertRow(
 				continue
 			}
 
-			col := ri.InsertCols[idx]
+			col := ri.writeCols[idx]
 
 			if lastColID > col.ID {
 				panic(fmt.Errorf("cannot write column id %d after %d", col.ID, lastColID))
@@ -362,6 +417,11 @@ type RowUpdater struct {
 	deleteOnlyIndex       map[int]struct{}
 	primaryKeyColChange   bool
 
+	// conversions are the columns being converted to a new type from one of
+	// the UpdateCols. Their new values are computed from the new values of
+	// their source columns.
+	conversions []columnConversion
+
 	// rd and ri are used when the update this RowUpdater is created for modifies
 	// the primary key of the table. In that case, rows must be deleted and
 	// re-added instead of merely updated, since the keys are changing.
@@ -374,7 +434,8 @@ type RowUpdater struct {
 	marshalled      []roachpb.Value
 	newValues       []parser.Datum
 	key             roachpb.Key
-	indexEntriesBuf []IndexEntry
+	oldIndexEntries [][]IndexEntry
+	newIndexEntries [][]IndexEntry
 	valueBuf        []byte
 	scratch         []byte
 	value           roachpb.Value
@@ -406,6 +467,7 @@ func MakeRowUpdater(
 	updateCols []ColumnDescriptor,
 	requestedCols []ColumnDescriptor,
 	updateType rowUpdaterType,
+	evalCtx *parser.EvalContext,
 	alloc *DatumAlloc,
 ) (RowUpdater, error) {
 	updateColIDtoRowIndex := ColIDtoRowIndexFromCols(updateCols)
@@ -433,6 +495,12 @@ func MakeRowUpdater(
 		if primaryKeyColChange {
 			return true
 		}
+		// The rows entering or leaving a partial index change its entries.
+		if index.IsPartial() {
+			if _, ok := updateColIDtoRowIndex[index.PredicateColumnID]; ok {
+				return true
+			}
+		}
 		return index.RunOverAllColumns(func(id ColumnID) error {
 			if _, ok := updateColIDtoRowIndex[id]; ok {
 				return returnTruePseudoError
@@ -493,7 +561,7 @@ func MakeRowUpdater(
 		// them, so request them all.
 		var err error
 		if ru.rd, err = MakeRowDeleter(txn, tableDesc, fkTables,
-			tableCols, SkipFKs, alloc); err != nil {
+			tableCols, SkipFKs, evalCtx, alloc); err != nil {
 			return RowUpdater{}, err
 		}
 		ru.FetchCols = ru.rd.FetchCols
@@ -542,12 +610,41 @@ func MakeRowUpdater(
 			if err := index.RunOverAllColumns(maybeAddCol); err != nil {
 				return RowUpdater{}, err
 			}
+			if index.IsPartial() {
+				if err := maybeAddCol(index.PredicateColumnID); err != nil {
+					return RowUpdater{}, err
+				}
+			}
 		}
 	}
 
+	if err := forEachColumnConversion(tableDesc, func(source, col ColumnDescriptor) error {
+		if _, ok := ru.updateColIDtoRowIndex[source.ID]; !ok {
+			return nil
+		}
+		// The converted column is in the same family as its source, so it is
+		// fetched whenever the source column is updated.
+		targetIdx, ok := ru.FetchColIDtoRowIndex[col.ID]
+		if !ok {
+			return errors.Errorf("column %d was expected to be fetched, but wasn't", col.ID)
+		}
+		converter, err := MakeColumnConverter(source, col)
+		if err != nil {
+			return err
+		}
+		ru.conversions = append(ru.conversions, columnConversion{
+			converter: converter,
+			sourceIdx: ru.FetchColIDtoRowIndex[source.ID],
+			targetIdx: targetIdx,
+		})
+		return nil
+	}); err != nil {
+		return RowUpdater{}, err
+	}
+
 	var err error
 	if ru.Fks, err = makeFKUpdateHelper(txn, *tableDesc, fkTables,
-		ru.FetchColIDtoRowIndex, alloc); err != nil {
+		ru.FetchColIDtoRowIndex, evalCtx, alloc); err != nil {
 		return RowUpdater{}, err
 	}
 	return ru, nil
@@ -574,16 +671,25 @@ func (ru *RowUpdater) UpdateRow(
 		return nil, errors.Errorf("got %d values but expected %d", len(updateValues), len(ru.UpdateCols))
 	}
 
-	primaryIndexKey, secondaryIndexEntries, err := ru.Helper.encodeIndexes(ru.FetchColIDtoRowIndex, oldValues)
+	primaryIndexKey, err := ru.Helper.encodePrimaryIndex(ru.FetchColIDtoRowIndex, oldValues)
 	if err != nil {
 		return nil, err
 	}
 
-	// The secondary index entries returned by rowHelper.encodeIndexes are only
-	// valid until the next call to encodeIndexes. We need to copy them so that
-	// we can compare against the new secondary index entries.
-	secondaryIndexEntries = append(ru.indexEntriesBuf[:0], secondaryIndexEntries...)
-	ru.indexEntriesBuf = secondaryIndexEntries
+	// The entries are kept per index: an inverted index has a variable number
+	// of entries, so the old and new entries can't be matched up by position
+	// in a flat slice.
+	if len(ru.oldIndexEntries) != len(ru.Helper.Indexes) {
+		ru.oldIndexEntries = make([][]IndexEntry, len(ru.Helper.Indexes))
+		ru.newIndexEntries = make([][]IndexEntry, len(ru.Helper.Indexes))
+	}
+	for i := range ru.Helper.Indexes {
+		ru.oldIndexEntries[i], err = EncodeSecondaryIndex(
+			ru.Helper.TableDesc, &ru.Helper.Indexes[i], ru.FetchColIDtoRowIndex, oldValues)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Check that the new value types match the column types. This needs to
 	// happen before index encoding because certain datum types (i.e. tuple)
@@ -599,20 +705,23 @@ func (ru *RowUpdater) UpdateRow(
 	for i, updateCol := range ru.UpdateCols {
 		ru.newValues[ru.FetchColIDtoRowIndex[updateCol.ID]] = updateValues[i]
 	}
+	for _, c := range ru.conversions {
+		if ru.newValues[c.targetIdx], err = c.converter.Convert(ru.newValues[c.sourceIdx]); err != nil {
+			return nil, err
+		}
+	}
 
 	rowPrimaryKeyChanged := false
-	var newSecondaryIndexEntries []IndexEntry
 	if ru.primaryKeyColChange {
-		var newPrimaryIndexKey []byte
-		newPrimaryIndexKey, newSecondaryIndexEntries, err =
-			ru.Helper.encodeIndexes(ru.FetchColIDtoRowIndex, ru.newValues)
+		newPrimaryIndexKey, err := ru.Helper.encodePrimaryIndex(ru.FetchColIDtoRowIndex, ru.newValues)
 		if err != nil {
 			return nil, err
 		}
 		rowPrimaryKeyChanged = !bytes.Equal(primaryIndexKey, newPrimaryIndexKey)
-	} else {
-		newSecondaryIndexEntries, err =
-			ru.Helper.encodeSecondaryIndexes(ru.FetchColIDtoRowIndex, ru.newValues)
+	}
+	for i := range ru.Helper.Indexes {
+		ru.newIndexEntries[i], err = EncodeSecondaryIndex(
+			ru.Helper.TableDesc, &ru.Helper.Indexes[i], ru.FetchColIDtoRowIndex, ru.newValues)
 		if err != nil {
 			return nil, err
 		}
@@ -622,8 +731,8 @@ func (ru *RowUpdater) UpdateRow(
 		if err := ru.Fks.checkIdx(ctx, ru.Helper.TableDesc.PrimaryIndex.ID, oldValues, ru.newValues, traceKV); err != nil {
 			return nil, err
 		}
-		for i := range newSecondaryIndexEntries {
-			if !bytes.Equal(newSecondaryIndexEntries[i].Key, secondaryIndexEntries[i].Key) {
+		for i := range ru.Helper.Indexes {
+			if !indexEntriesEqual(ru.newIndexEntries[i], ru.oldIndexEntries[i]) {
 				if err := ru.Fks.checkIdx(ctx, ru.Helper.Indexes[i].ID, oldValues, ru.newValues, traceKV); err != nil {
 					return nil, err
 				}
@@ -737,8 +846,18 @@ func (ru *RowUpdater) UpdateRow(
 	}
 
 	// Update secondary indexes.
-	for i, newSecondaryIndexEntry := range newSecondaryIndexEntries {
-		secondaryIndexEntry := secondaryIndexEntries[i]
+	for i := range ru.Helper.Indexes {
+		if ru.Helper.Indexes[i].Type == IndexDescriptor_INVERTED {
+			ru.updateInvertedIndex(ctx, b, i, traceKV)
+			continue
+		}
+		if ru.Helper.Indexes[i].IsPartial() &&
+			(len(ru.oldIndexEntries[i]) == 0 || len(ru.newIndexEntries[i]) == 0) {
+			ru.updatePartialIndex(ctx, b, i, traceKV)
+			continue
+		}
+		secondaryIndexEntry := ru.oldIndexEntries[i][0]
+		newSecondaryIndexEntry := ru.newIndexEntries[i][0]
 		var expValue interface{}
 		if !bytes.Equal(newSecondaryIndexEntry.Key, secondaryIndexEntry.Key) {
 			if err := ru.Fks.checkIdx(ctx, ru.Helper.Indexes[i].ID, oldValues, ru.newValues, traceKV); err != nil {
@@ -766,6 +885,81 @@ func (ru *RowUpdater) UpdateRow(
 	return ru.newValues, nil
 }
 
+// updateInvertedIndex deletes the entries of the i-th index that are no
+// longer present in the new row and adds the ones that are new. Entries of an
+// inverted index have no value, so entries present in both rows are left
+// alone.
+func (ru *RowUpdater) updateInvertedIndex(
+	ctx context.Context, b *client.Batch, i int, traceKV bool,
+) {
+	oldEntries, newEntries := ru.oldIndexEntries[i], ru.newIndexEntries[i]
+	for j := range oldEntries {
+		if !containsIndexEntryKey(newEntries, oldEntries[j].Key) {
+			if traceKV {
+				log.VEventf(ctx, 2, "Del %s", oldEntries[j].Key)
+			}
+			b.Del(oldEntries[j].Key)
+		}
+	}
+	// Do not update Indexes in the DELETE_ONLY state.
+	if _, ok := ru.deleteOnlyIndex[i]; ok {
+		return
+	}
+	for j := range newEntries {
+		if !containsIndexEntryKey(oldEntries, newEntries[j].Key) {
+			if traceKV {
+				log.VEventf(ctx, 2, "Put %s -> %v", newEntries[j].Key, newEntries[j].Value.PrettyPrint())
+			}
+			b.Put(newEntries[j].Key, &newEntries[j].Value)
+		}
+	}
+}
+
+// updatePartialIndex deletes the entry of the i-th index, a partial index,
+// if the row leaves it and adds one if the row enters it.
+func (ru *RowUpdater) updatePartialIndex(
+	ctx context.Context, b *client.Batch, i int, traceKV bool,
+) {
+	oldEntries, newEntries := ru.oldIndexEntries[i], ru.newIndexEntries[i]
+	for j := range oldEntries {
+		if traceKV {
+			log.VEventf(ctx, 2, "Del %s", oldEntries[j].Key)
+		}
+		b.Del(oldEntries[j].Key)
+	}
+	// Do not update Indexes in the DELETE_ONLY state.
+	if _, ok := ru.deleteOnlyIndex[i]; ok {
+		return
+	}
+	for j := range newEntries {
+		if traceKV {
+			log.VEventf(ctx, 2, "CPut %s -> %v", newEntries[j].Key, newEntries[j].Value.PrettyPrint())
+		}
+		b.CPut(newEntries[j].Key, &newEntries[j].Value, nil)
+	}
+}
+
+func containsIndexEntryKey(entries []IndexEntry, key roachpb.Key) bool {
+	for i := range entries {
+		if bytes.Equal(entries[i].Key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func indexEntriesEqual(a, b []IndexEntry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i].Key, b[i].Key) {
+			return false
+		}
+	}
+	return true
+}
+
 // IsColumnOnlyUpdate returns true if this RowUpdater is only updating column
 // data (in contrast to updating the primary key or other indexes).
 func (ru *RowUpdater) IsColumnOnlyUpdate() bool {
@@ -800,6 +994,7 @@ func MakeRowDeleter(
 	fkTables TableLookupsByID,
 	requestedCols []ColumnDescriptor,
 	checkFKs bool,
+	evalCtx *parser.EvalContext,
 	alloc *DatumAlloc,
 ) (RowDeleter, error) {
 	indexes := tableDesc.Indexes
@@ -840,6 +1035,11 @@ func MakeRowDeleter(
 				return RowDeleter{}, err
 			}
 		}
+		if index.IsPartial() {
+			if err := maybeAddCol(index.PredicateColumnID); err != nil {
+				return RowDeleter{}, err
+			}
+		}
 	}
 
 	rd := RowDeleter{
@@ -850,7 +1050,7 @@ func MakeRowDeleter(
 	if checkFKs {
 		var err error
 		if rd.Fks, err = makeFKDeleteHelper(txn, *tableDesc, fkTables,
-			fetchColIDtoRowIndex, alloc); err != nil {
+			fetchColIDtoRowIndex, CheckDeletes, evalCtx, alloc); err != nil {
 			return RowDeleter{}, err
 		}
 	}
@@ -899,15 +1099,17 @@ func (rd *RowDeleter) DeleteIndexRow(
 	if err := rd.Fks.checkAll(ctx, values, traceKV); err != nil {
 		return err
 	}
-	secondaryIndexEntry, err := EncodeSecondaryIndex(
+	secondaryIndexEntries, err := EncodeSecondaryIndex(
 		rd.Helper.TableDesc, idx, rd.FetchColIDtoRowIndex, values)
 	if err != nil {
 		return err
 	}
-	if traceKV {
-		log.VEventf(ctx, 2, "Del %s", secondaryIndexEntry.Key)
+	for _, secondaryIndexEntry := range secondaryIndexEntries {
+		if traceKV {
+			log.VEventf(ctx, 2, "Del %s", secondaryIndexEntry.Key)
+		}
+		b.Del(secondaryIndexEntry.Key)
 	}
-	b.Del(secondaryIndexEntry.Key)
 	return nil
 }
 
@@ -16,6 +16,7 @@ package sqlbase
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"strings"
 	"unicode/utf8"
@@ -189,7 +190,17 @@ func (desc *IndexDescriptor) RunOverAllColumns(fn func(id ColumnID) error) error
 func (desc *IndexDescriptor) allocateName(tableDesc *TableDescriptor) {
 	segments := make([]string, 0, len(desc.ColumnNames)+2)
 	segments = append(segments, tableDesc.Name)
-	segments = append(segments, desc.ColumnNames...)
+	for i, name := range desc.ColumnNames {
+		if desc.IsExprColumn(i) {
+			// Name the index after the expression rather than after the
+			// hidden column storing its values.
+			name = "expr"
+			if expr, err := parser.ParseExpr(desc.ColumnExprs[i]); err == nil {
+				name = indexExprName(expr)
+			}
+		}
+		segments = append(segments, name)
+	}
 	if desc.Unique {
 		segments = append(segments, "key")
 	} else {
@@ -210,11 +221,22 @@ func (desc *IndexDescriptor) allocateName(tableDesc *TableDescriptor) {
 	desc.Name = name
 }
 
-// FillColumns sets the column names and directions in desc.
+// FillColumns sets the column names and directions in desc, as well as the
+// expressions of the elements that ResolveIndexExprs replaced with columns.
 func (desc *IndexDescriptor) FillColumns(elems parser.IndexElemList) error {
 	desc.ColumnNames = make([]string, 0, len(elems))
 	desc.ColumnDirections = make([]IndexDescriptor_Direction, 0, len(elems))
-	for _, c := range elems {
+	desc.ColumnExprs = nil
+	for i, c := range elems {
+		if c.Expr != nil {
+			if c.Column == "" {
+				return fmt.Errorf("unresolved index expression %s", c.Expr)
+			}
+			if desc.ColumnExprs == nil {
+				desc.ColumnExprs = make([]string, len(elems))
+			}
+			desc.ColumnExprs[i] = parser.Serialize(c.Expr)
+		}
 		desc.ColumnNames = append(desc.ColumnNames, string(c.Column))
 		switch c.Direction {
 		case parser.Ascending, parser.DefaultDirection:
@@ -273,19 +295,25 @@ func (desc *IndexDescriptor) FullColumnIDs() ([]ColumnID, []encoding.Direction)
 }
 
 // ColNamesString returns a string describing the column names and directions
-// in this index.
+// in this index. The expressions of an expression index are described instead
+// of the columns storing their values.
 func (desc *IndexDescriptor) ColNamesString() string {
 	var buf bytes.Buffer
 	for i, name := range desc.ColumnNames {
 		if i > 0 {
 			buf.WriteString(", ")
 		}
+		if desc.IsExprColumn(i) {
+			fmt.Fprintf(&buf, "%s %s", formatIndexExpr(desc.ColumnExprs[i]), desc.ColumnDirections[i])
+			continue
+		}
 		fmt.Fprintf(&buf, "%s %s", parser.Name(name), desc.ColumnDirections[i])
 	}
 	return buf.String()
 }
 
 var isUnique = map[bool]string{true: "UNIQUE "}
+var isInverted = map[bool]string{true: "INVERTED "}
 
 // SQLString returns the SQL string describing this index. If non-empty,
 // "ON tableName" is included in the output in the correct place.
@@ -302,12 +330,18 @@ func (desc *IndexDescriptor) SQLString(tableName string) string {
 	if tableName != "" {
 		onTable = fmt.Sprintf("ON %s ", tableName)
 	}
-	return fmt.Sprintf("%sINDEX %s%s (%s)%s",
+	var where string
+	if desc.IsPartial() {
+		where = fmt.Sprintf(" WHERE %s", desc.Predicate)
+	}
+	return fmt.Sprintf("%s%sINDEX %s%s (%s)%s%s",
 		isUnique[desc.Unique],
+		isInverted[desc.Type == IndexDescriptor_INVERTED],
 		onTable,
 		parser.AsString(parser.Name(desc.Name)),
 		desc.ColNamesString(),
 		storing,
+		where,
 	)
 }
 
@@ -333,9 +367,10 @@ func (desc *TableDescriptor) IsEmpty() bool {
 }
 
 // IsTable returns true if the TableDescriptor actually describes a
-// Table resource, as opposed to a different resource (like a View).
+// Table resource, as opposed to a different resource (like a View or a
+// Sequence).
 func (desc *TableDescriptor) IsTable() bool {
-	return !desc.IsView()
+	return !desc.IsView() && !desc.IsSequence()
 }
 
 // IsView returns true if the TableDescriptor actually describes a
@@ -344,6 +379,12 @@ func (desc *TableDescriptor) IsView() bool {
 	return desc.ViewQuery != ""
 }
 
+// IsSequence returns true if the TableDescriptor actually describes a
+// Sequence resource rather than a Table.
+func (desc *TableDescriptor) IsSequence() bool {
+	return desc.SequenceOpts != nil
+}
+
 // IsVirtualTable returns true if the TableDescriptor describes a
 // virtual Table (like the information_schema tables) and thus doesn't
 // need to be physically stored.
@@ -596,7 +637,7 @@ func HasCompositeKeyEncoding(semanticType ColumnType_SemanticType) bool {
 // MustBeValueEncoded returns true if columns of the given kind can only be value
 // encoded.
 func MustBeValueEncoded(semanticType ColumnType_SemanticType) bool {
-	return semanticType == ColumnType_ARRAY
+	return semanticType == ColumnType_ARRAY || semanticType == ColumnType_JSON
 }
 
 // HasOldStoredColumns returns whether the index has stored columns in the old
@@ -1003,6 +1044,11 @@ func (desc *TableDescriptor) ValidateTable() error {
 			desc.Name, desc.GetFormatVersion(), FamilyFormatVersion, InterleavedFormatVersion)
 	}
 
+	// Sequences have no columns, indexes or column families.
+	if desc.IsSequence() {
+		return desc.Privileges.Validate(desc.GetID())
+	}
+
 	if len(desc.Columns) == 0 {
 		return ErrMissingColumns
 	}
@@ -1041,6 +1087,12 @@ func (desc *TableDescriptor) ValidateTable() error {
 			return fmt.Errorf("column %q invalid ID (%d) > next column ID (%d)",
 				column.Name, column.ID, desc.NextColumnID)
 		}
+
+		if column.Privileges != nil {
+			if err := column.Privileges.ValidateColumn(); err != nil {
+				return err
+			}
+		}
 	}
 
 	for _, m := range desc.Mutations {
@@ -1076,6 +1128,17 @@ func (desc *TableDescriptor) ValidateTable() error {
 		}
 	}
 
+	policyNames := make(map[string]struct{}, len(desc.Policies))
+	for _, policy := range desc.Policies {
+		if policy.Name == "" {
+			return fmt.Errorf("empty policy name")
+		}
+		if _, ok := policyNames[policy.Name]; ok {
+			return fmt.Errorf("duplicate policy name: %q", policy.Name)
+		}
+		policyNames[policy.Name] = struct{}{}
+	}
+
 	// Validate the privilege descriptor.
 	return desc.Privileges.Validate(desc.GetID())
 }
@@ -1193,6 +1256,20 @@ func (desc *TableDescriptor) validateTableIndexes(
 			return fmt.Errorf("mismatched column IDs (%d) and directions (%d)",
 				len(index.ColumnIDs), len(index.ColumnDirections))
 		}
+		if len(index.ColumnExprs) > 0 && len(index.ColumnIDs) != len(index.ColumnExprs) {
+			return fmt.Errorf("mismatched column IDs (%d) and expressions (%d)",
+				len(index.ColumnIDs), len(index.ColumnExprs))
+		}
+		if index.IsPartial() != (index.Predicate != "") {
+			return fmt.Errorf("index %q has mismatched predicate %q and predicate column %d",
+				index.Name, index.Predicate, index.PredicateColumnID)
+		}
+		if index.IsPartial() {
+			if _, ok := colIDToFamilyID[index.PredicateColumnID]; !ok {
+				return fmt.Errorf("index %q has unknown predicate column %d",
+					index.Name, index.PredicateColumnID)
+			}
+		}
 
 		if len(index.ColumnIDs) == 0 {
 			return fmt.Errorf("index %q must contain at least 1 column", index.Name)
@@ -1233,13 +1310,17 @@ func upperBoundColumnValueEncodedSize(col ColumnDescriptor) (int, bool) {
 	case ColumnType_BOOL:
 		typ = encoding.True
 	case ColumnType_INT, ColumnType_DATE, ColumnType_TIMESTAMP,
-		ColumnType_TIMESTAMPTZ, ColumnType_OID:
+		ColumnType_TIMESTAMPTZ, ColumnType_OID, ColumnType_TIME:
 		typ, size = encoding.Int, int(col.Type.Width)
+	case ColumnType_TIMETZ:
+		// The time of day and the offset are encoded as two varints.
+		typ, size = encoding.Bytes, 2*binary.MaxVarintLen64
 	case ColumnType_FLOAT:
 		typ = encoding.Float
 	case ColumnType_INTERVAL:
 		typ = encoding.Duration
-	case ColumnType_STRING, ColumnType_BYTES, ColumnType_COLLATEDSTRING, ColumnType_NAME, ColumnType_UUID, ColumnType_INET:
+	case ColumnType_STRING, ColumnType_BYTES, ColumnType_COLLATEDSTRING, ColumnType_NAME, ColumnType_UUID, ColumnType_INET,
+		ColumnType_JSON:
 		// STRINGs are counted as runes, so this isn't totally correct, but this
 		// seems better than always assuming the maximum rune width.
 		typ, size = encoding.Bytes, int(col.Type.Width)
@@ -1292,6 +1373,12 @@ func columnTypeIsIndexable(t ColumnType) bool {
 	return !MustBeValueEncoded(t.SemanticType)
 }
 
+// columnTypeIsInvertedIndexable returns whether the type t is valid as the
+// column of an inverted index.
+func columnTypeIsInvertedIndexable(t ColumnType) bool {
+	return t.SemanticType == ColumnType_JSON
+}
+
 func notIndexableError(cols []ColumnDescriptor) error {
 	if len(cols) == 0 {
 		return nil
@@ -1332,6 +1419,28 @@ func checkColumnsValidForIndex(tableDesc *TableDescriptor, indexColNames []strin
 	return nil
 }
 
+func checkColumnsValidForInvertedIndex(tableDesc *TableDescriptor, indexColNames []string) error {
+	if len(indexColNames) != 1 {
+		return errors.New("inverted indexes can't be multi-column")
+	}
+	for _, col := range tableDesc.Columns {
+		if col.Name == indexColNames[0] && !columnTypeIsInvertedIndexable(col.Type) {
+			return fmt.Errorf("column %s of type %s is not allowed as the column of an inverted index",
+				col.Name, col.Type.SemanticType)
+		}
+	}
+	return nil
+}
+
+// checkIndexColumns checks that the columns of idx are valid for an index of
+// its type.
+func checkIndexColumns(tableDesc *TableDescriptor, idx IndexDescriptor) error {
+	if idx.Type == IndexDescriptor_INVERTED {
+		return checkColumnsValidForInvertedIndex(tableDesc, idx.ColumnNames)
+	}
+	return checkColumnsValidForIndex(tableDesc, idx.ColumnNames)
+}
+
 // AddColumn adds a column to the table.
 func (desc *TableDescriptor) AddColumn(col ColumnDescriptor) {
 	desc.Columns = append(desc.Columns, col)
@@ -1344,7 +1453,7 @@ func (desc *TableDescriptor) AddFamily(fam ColumnFamilyDescriptor) {
 
 // AddIndex adds an index to the table.
 func (desc *TableDescriptor) AddIndex(idx IndexDescriptor, primary bool) error {
-	if err := checkColumnsValidForIndex(desc, idx.ColumnNames); err != nil {
+	if err := checkIndexColumns(desc, idx); err != nil {
 		return err
 	}
 	if primary {
@@ -1635,16 +1744,21 @@ func (desc *TableDescriptor) IsInterleaved() bool {
 }
 
 // MakeMutationComplete updates the descriptor upon completion of a mutation.
-func (desc *TableDescriptor) MakeMutationComplete(m DescriptorMutation) {
+// Completing the conversion of a column to a new type enqueues a mutation
+// dropping the column it replaces, using the next mutation ID.
+func (desc *TableDescriptor) MakeMutationComplete(m DescriptorMutation) error {
 	switch m.Direction {
 	case DescriptorMutation_ADD:
 		switch t := m.Descriptor_.(type) {
 		case *DescriptorMutation_Column:
+			if m.ConvertFromColumnID != 0 {
+				return desc.completeColumnConversion(*t.Column, m.ConvertFromColumnID)
+			}
 			desc.AddColumn(*t.Column)
 
 		case *DescriptorMutation_Index:
 			if err := desc.AddIndex(*t.Index, false); err != nil {
-				panic(err)
+				return err
 			}
 		}
 
@@ -1656,6 +1770,52 @@ func (desc *TableDescriptor) MakeMutationComplete(m DescriptorMutation) {
 		// Nothing else to be done. The column/index was already removed from the
 		// set of column/index descriptors at mutation creation time.
 	}
+	return nil
+}
+
+// completeColumnConversion makes col public in place of the column it was
+// converted from, taking over its name and position, and enqueues the
+// mutation dropping the source column.
+func (desc *TableDescriptor) completeColumnConversion(
+	col ColumnDescriptor, sourceID ColumnID,
+) error {
+	for i := range desc.Columns {
+		if desc.Columns[i].ID != sourceID {
+			continue
+		}
+		source := desc.Columns[i]
+		desc.Columns[i] = col
+		desc.RenameColumnDescriptor(col, source.Name)
+		desc.AddColumnMutation(source, DescriptorMutation_DROP)
+		return nil
+	}
+	return errors.Errorf("column %d converted to column %q does not exist", sourceID, col.Name)
+}
+
+// AddColumnConversionMutation adds a mutation to desc.Mutations adding col,
+// whose values are converted from those of the column source. col is placed
+// in the column family of source, so that both are always written together.
+func (desc *TableDescriptor) AddColumnConversionMutation(
+	col ColumnDescriptor, source ColumnDescriptor,
+) error {
+	var family string
+	for _, fam := range desc.Families {
+		for _, id := range fam.ColumnIDs {
+			if id == source.ID {
+				family = fam.Name
+			}
+		}
+	}
+	if family == "" {
+		return fmt.Errorf("column %q is not in any column family", source.Name)
+	}
+	m := DescriptorMutation{
+		Descriptor_:         &DescriptorMutation_Column{Column: &col},
+		Direction:           DescriptorMutation_ADD,
+		ConvertFromColumnID: source.ID,
+	}
+	desc.addMutation(m)
+	return desc.AddColumnToFamilyMaybeCreate(col.Name, family, false, false)
 }
 
 // AddColumnMutation adds a column mutation to desc.Mutations.
@@ -1670,7 +1830,7 @@ func (desc *TableDescriptor) AddColumnMutation(
 func (desc *TableDescriptor) AddIndexMutation(
 	idx IndexDescriptor, direction DescriptorMutation_Direction,
 ) error {
-	if err := checkColumnsValidForIndex(desc, idx.ColumnNames); err != nil {
+	if err := checkIndexColumns(desc, idx); err != nil {
 		return err
 	}
 	m := DescriptorMutation{Descriptor_: &DescriptorMutation_Index{Index: &idx}, Direction: direction}
@@ -1741,12 +1901,14 @@ func (desc *TableDescriptor) VisibleColumns() []ColumnDescriptor {
 	return cols
 }
 
-// ColumnsSelectors generates Select expressions for cols.
-func ColumnsSelectors(cols []ColumnDescriptor) parser.SelectExprs {
+// ColumnsSelectors generates Select expressions for cols. forUpdateOrDelete
+// marks the columns as fetched by an UPDATE or DELETE statement.
+func ColumnsSelectors(cols []ColumnDescriptor, forUpdateOrDelete bool) parser.SelectExprs {
 	exprs := make(parser.SelectExprs, len(cols))
 	colItems := make([]parser.ColumnItem, len(cols))
 	for i, col := range cols {
 		colItems[i].ColumnName = parser.Name(col.Name)
+		colItems[i].ForUpdateOrDelete = forUpdateOrDelete
 		exprs[i].Expr = &colItems[i]
 	}
 	return exprs
@@ -1781,6 +1943,8 @@ func (c *ColumnType) SQLString() string {
 		}
 	case ColumnType_TIMESTAMPTZ:
 		return "TIMESTAMP WITH TIME ZONE"
+	case ColumnType_TIMETZ:
+		return "TIME WITH TIME ZONE"
 	case ColumnType_COLLATEDSTRING:
 		if c.Locale == nil {
 			panic("locale is required for COLLATEDSTRING")
@@ -1791,6 +1955,8 @@ func (c *ColumnType) SQLString() string {
 		return fmt.Sprintf("%s COLLATE %s", ColumnType_STRING.String(), *c.Locale)
 	case ColumnType_ARRAY:
 		return c.ArrayContents.String() + "[]"
+	case ColumnType_JSON:
+		return "JSONB"
 	}
 	if c.VisibleType != ColumnType_NONE {
 		return c.VisibleType.String()
@@ -1882,12 +2048,18 @@ func DatumTypeToColumnSemanticType(ptyp parser.Type) (ColumnType_SemanticType, e
 		return ColumnType_TIMESTAMP, nil
 	case parser.TypeTimestampTZ:
 		return ColumnType_TIMESTAMPTZ, nil
+	case parser.TypeTime:
+		return ColumnType_TIME, nil
+	case parser.TypeTimeTZ:
+		return ColumnType_TIMETZ, nil
 	case parser.TypeInterval:
 		return ColumnType_INTERVAL, nil
 	case parser.TypeUUID:
 		return ColumnType_UUID, nil
 	case parser.TypeINet:
 		return ColumnType_INET, nil
+	case parser.TypeJSON:
+		return ColumnType_JSON, nil
 	case parser.TypeOid:
 		return ColumnType_OID, nil
 	case parser.TypeNull:
@@ -1950,12 +2122,18 @@ func columnSemanticTypeToDatumType(c *ColumnType, k ColumnType_SemanticType) par
 		return parser.TypeTimestamp
 	case ColumnType_TIMESTAMPTZ:
 		return parser.TypeTimestampTZ
+	case ColumnType_TIME:
+		return parser.TypeTime
+	case ColumnType_TIMETZ:
+		return parser.TypeTimeTZ
 	case ColumnType_INTERVAL:
 		return parser.TypeInterval
 	case ColumnType_UUID:
 		return parser.TypeUUID
 	case ColumnType_INET:
 		return parser.TypeINet
+	case ColumnType_JSON:
+		return parser.TypeJSON
 	case ColumnType_COLLATEDSTRING:
 		if c.Locale == nil {
 			panic("locale is required for COLLATEDSTRING")
@@ -2042,6 +2220,31 @@ func (f ForeignKeyReference) IsSet() bool {
 	return f.Table != 0
 }
 
+// ForeignKeyReferenceActionValue maps the actions of a foreign key definition
+// to their descriptor representation.
+var ForeignKeyReferenceActionValue = [...]ForeignKeyReference_Action{
+	parser.NoAction:   ForeignKeyReference_NO_ACTION,
+	parser.Restrict:   ForeignKeyReference_RESTRICT,
+	parser.SetNull:    ForeignKeyReference_SET_NULL,
+	parser.SetDefault: ForeignKeyReference_SET_DEFAULT,
+	parser.Cascade:    ForeignKeyReference_CASCADE,
+}
+
+// ReferenceActions returns the ON DELETE and ON UPDATE actions of the foreign
+// key as they are written in its definition.
+func (f ForeignKeyReference) ReferenceActions() parser.ReferenceActions {
+	var actions parser.ReferenceActions
+	for a, v := range ForeignKeyReferenceActionValue {
+		if v == f.OnDelete {
+			actions.Delete = parser.ReferenceAction(a)
+		}
+		if v == f.OnUpdate {
+			actions.Update = parser.ReferenceAction(a)
+		}
+	}
+	return actions
+}
+
 // InvalidateFKConstraints sets all FK constraints to un-validated.
 func (desc *TableDescriptor) InvalidateFKConstraints() {
 	// We don't use GetConstraintInfo because we want to edit the passed desc.
@@ -2110,5 +2313,13 @@ func (desc *ColumnDescriptor) SQLString() string {
 	if desc.DefaultExpr != nil {
 		fmt.Fprintf(&buf, " DEFAULT %s", *desc.DefaultExpr)
 	}
+	if desc.IsComputed() {
+		fmt.Fprintf(&buf, " AS (%s) STORED", *desc.ComputedExpr)
+	}
 	return buf.String()
 }
+
+// IsComputed returns whether the column is a computed column.
+func (desc *ColumnDescriptor) IsComputed() bool {
+	return desc.ComputedExpr != nil
+}
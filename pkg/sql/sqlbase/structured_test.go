@@ -848,6 +848,8 @@ func TestColumnTypeSQLString(t *testing.T) {
 		{ColumnType{SemanticType: ColumnType_DECIMAL, Precision: 7, Width: 8}, "DECIMAL(7,8)"},
 		{ColumnType{SemanticType: ColumnType_DATE}, "DATE"},
 		{ColumnType{SemanticType: ColumnType_TIMESTAMP}, "TIMESTAMP"},
+		{ColumnType{SemanticType: ColumnType_TIME}, "TIME"},
+		{ColumnType{SemanticType: ColumnType_TIMETZ}, "TIME WITH TIME ZONE"},
 		{ColumnType{SemanticType: ColumnType_INTERVAL}, "INTERVAL"},
 		{ColumnType{SemanticType: ColumnType_STRING}, "STRING"},
 		{ColumnType{SemanticType: ColumnType_STRING, Width: 10}, "STRING(10)"},
@@ -876,6 +878,7 @@ func TestColumnValueEncodedSize(t *testing.T) {
 		{ColumnType{SemanticType: ColumnType_DECIMAL, Precision: 100, Width: 100}, 69},
 		{ColumnType{SemanticType: ColumnType_DATE}, 10},
 		{ColumnType{SemanticType: ColumnType_TIMESTAMP}, 10},
+		{ColumnType{SemanticType: ColumnType_TIME}, 10},
 		{ColumnType{SemanticType: ColumnType_INTERVAL}, 28},
 		{ColumnType{SemanticType: ColumnType_STRING}, -1},
 		{ColumnType{SemanticType: ColumnType_STRING, Width: 100}, 110},
@@ -28,9 +28,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
@@ -138,11 +140,14 @@ func MakeColumnDefDescs(
 				col.Type.Width, col.Type.Precision)
 		}
 	case *parser.DateColType:
+	case *parser.TimeColType:
+	case *parser.TimeTZColType:
 	case *parser.TimestampColType:
 	case *parser.TimestampTZColType:
 	case *parser.IntervalColType:
 	case *parser.UUIDColType:
 	case *parser.IPAddrColType:
+	case *parser.JSONColType:
 	case *parser.StringColType:
 		col.Type.Width = int32(t.N)
 	case *parser.NameColType:
@@ -216,6 +221,17 @@ func MakeColumnDefDescs(
 		col.DefaultExpr = &s
 	}
 
+	if d.IsComputed() {
+		if col.DefaultExpr != nil {
+			return nil, nil, pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
+				"computed column %q cannot also have a DEFAULT expression", col.Name)
+		}
+		// The expression is validated against the other columns of the table
+		// by ValidateComputedColumn once they are known.
+		s := parser.Serialize(d.Computed.Expr)
+		col.ComputedExpr = &s
+	}
+
 	var idx *IndexDescriptor
 	if d.PrimaryKey || d.Unique {
 		idx = &IndexDescriptor{
@@ -536,6 +552,20 @@ func EncodeTableKey(b []byte, val parser.Datum, dir encoding.Direction) ([]byte,
 			return encoding.EncodeTimeAscending(b, t.Time), nil
 		}
 		return encoding.EncodeTimeDescending(b, t.Time), nil
+	case *parser.DTime:
+		if dir == encoding.Ascending {
+			return encoding.EncodeVarintAscending(b, int64(*t)), nil
+		}
+		return encoding.EncodeVarintDescending(b, int64(*t)), nil
+	case *parser.DTimeTZ:
+		// Times with time zones are ordered by their UTC time first, then by
+		// their offset west of UTC. See DTimeTZ.Compare.
+		if dir == encoding.Ascending {
+			b = encoding.EncodeVarintAscending(b, t.UTCMicros())
+			return encoding.EncodeVarintAscending(b, -int64(t.OffsetSecs)), nil
+		}
+		b = encoding.EncodeVarintDescending(b, t.UTCMicros())
+		return encoding.EncodeVarintDescending(b, -int64(t.OffsetSecs)), nil
 	case *parser.DInterval:
 		if dir == encoding.Ascending {
 			return encoding.EncodeDurationAscending(b, t.Duration)
@@ -614,12 +644,18 @@ func EncodeTableValue(
 		return encoding.EncodeTimeValue(appendTo, uint32(colID), t.Time), nil
 	case *parser.DTimestampTZ:
 		return encoding.EncodeTimeValue(appendTo, uint32(colID), t.Time), nil
+	case *parser.DTime:
+		return encoding.EncodeIntValue(appendTo, uint32(colID), int64(*t)), nil
+	case *parser.DTimeTZ:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), encodeTimeTZ(scratch[:0], t)), nil
 	case *parser.DInterval:
 		return encoding.EncodeDurationValue(appendTo, uint32(colID), t.Duration), nil
 	case *parser.DUuid:
 		return encoding.EncodeUUIDValue(appendTo, uint32(colID), t.UUID), nil
 	case *parser.DIPAddr:
 		return encoding.EncodeIPAddrValue(appendTo, uint32(colID), t.IPAddr), nil
+	case *parser.DJSON:
+		return encoding.EncodeJSONValue(appendTo, uint32(colID), json.EncodeJSON(scratch[:0], t.JSON)), nil
 	case *parser.DArray:
 		a, err := encodeArray(t, scratch)
 		if err != nil {
@@ -925,9 +961,12 @@ type DatumAlloc struct {
 	ddateAlloc        []parser.DDate
 	dtimestampAlloc   []parser.DTimestamp
 	dtimestampTzAlloc []parser.DTimestampTZ
+	dtimeAlloc        []parser.DTime
+	dtimeTzAlloc      []parser.DTimeTZ
 	dintervalAlloc    []parser.DInterval
 	duuidAlloc        []parser.DUuid
 	dipnetAlloc       []parser.DIPAddr
+	djsonAlloc        []parser.DJSON
 	doidAlloc         []parser.DOid
 	scratch           []byte
 	env               parser.CollationEnvironment
@@ -1034,6 +1073,30 @@ func (a *DatumAlloc) NewDTimestampTZ(v parser.DTimestampTZ) *parser.DTimestampTZ
 	return r
 }
 
+// NewDTime allocates a DTime.
+func (a *DatumAlloc) NewDTime(v parser.DTime) *parser.DTime {
+	buf := &a.dtimeAlloc
+	if len(*buf) == 0 {
+		*buf = make([]parser.DTime, datumAllocSize)
+	}
+	r := &(*buf)[0]
+	*r = v
+	*buf = (*buf)[1:]
+	return r
+}
+
+// NewDTimeTZ allocates a DTimeTZ.
+func (a *DatumAlloc) NewDTimeTZ(v parser.DTimeTZ) *parser.DTimeTZ {
+	buf := &a.dtimeTzAlloc
+	if len(*buf) == 0 {
+		*buf = make([]parser.DTimeTZ, datumAllocSize)
+	}
+	r := &(*buf)[0]
+	*r = v
+	*buf = (*buf)[1:]
+	return r
+}
+
 // NewDInterval allocates a DInterval.
 func (a *DatumAlloc) NewDInterval(v parser.DInterval) *parser.DInterval {
 	buf := &a.dintervalAlloc
@@ -1070,6 +1133,18 @@ func (a *DatumAlloc) NewDIPAddr(v parser.DIPAddr) *parser.DIPAddr {
 	return r
 }
 
+// NewDJSON allocates a DJSON.
+func (a *DatumAlloc) NewDJSON(v parser.DJSON) *parser.DJSON {
+	buf := &a.djsonAlloc
+	if len(*buf) == 0 {
+		*buf = make([]parser.DJSON, datumAllocSize)
+	}
+	r := &(*buf)[0]
+	*r = v
+	*buf = (*buf)[1:]
+	return r
+}
+
 // NewDOid allocates a DOid.
 func (a *DatumAlloc) NewDOid(v parser.DOid) parser.Datum {
 	buf := &a.doidAlloc
@@ -1179,6 +1254,31 @@ func DecodeTableKey(
 			rkey, t, err = encoding.DecodeTimeDescending(key)
 		}
 		return a.NewDTimestampTZ(parser.DTimestampTZ{Time: t}), rkey, err
+	case parser.TypeTime:
+		var t int64
+		if dir == encoding.Ascending {
+			rkey, t, err = encoding.DecodeVarintAscending(key)
+		} else {
+			rkey, t, err = encoding.DecodeVarintDescending(key)
+		}
+		return a.NewDTime(parser.DTime(t)), rkey, err
+	case parser.TypeTimeTZ:
+		var utc, offsetWest int64
+		if dir == encoding.Ascending {
+			if rkey, utc, err = encoding.DecodeVarintAscending(key); err != nil {
+				return nil, nil, err
+			}
+			rkey, offsetWest, err = encoding.DecodeVarintAscending(rkey)
+		} else {
+			if rkey, utc, err = encoding.DecodeVarintDescending(key); err != nil {
+				return nil, nil, err
+			}
+			rkey, offsetWest, err = encoding.DecodeVarintDescending(rkey)
+		}
+		return a.NewDTimeTZ(parser.DTimeTZ{
+			Time:       parser.DTime(utc - offsetWest*int64(time.Second/time.Microsecond)),
+			OffsetSecs: int32(-offsetWest),
+		}), rkey, err
 	case parser.TypeInterval:
 		var d duration.Duration
 		if dir == encoding.Ascending {
@@ -1390,6 +1490,22 @@ func decodeUntaggedDatum(a *DatumAlloc, t parser.Type, buf []byte) (parser.Datum
 			return nil, b, err
 		}
 		return a.NewDTimestampTZ(parser.DTimestampTZ{Time: data}), b, nil
+	case parser.TypeTime:
+		b, data, err := encoding.DecodeUntaggedIntValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		return a.NewDTime(parser.DTime(data)), b, nil
+	case parser.TypeTimeTZ:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		t, err := decodeTimeTZ(data)
+		if err != nil {
+			return nil, b, err
+		}
+		return a.NewDTimeTZ(t), b, nil
 	case parser.TypeInterval:
 		b, data, err := encoding.DecodeUntaggedDurationValue(buf)
 		return a.NewDInterval(parser.DInterval{Duration: data}), b, err
@@ -1399,6 +1515,16 @@ func decodeUntaggedDatum(a *DatumAlloc, t parser.Type, buf []byte) (parser.Datum
 	case parser.TypeINet:
 		b, data, err := encoding.DecodeUntaggedIPAddrValue(buf)
 		return a.NewDIPAddr(parser.DIPAddr{IPAddr: data}), b, err
+	case parser.TypeJSON:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		j, err := decodeJSON(data)
+		if err != nil {
+			return nil, b, err
+		}
+		return a.NewDJSON(parser.DJSON{JSON: j}), b, nil
 	case parser.TypeOid:
 		b, data, err := encoding.DecodeUntaggedIntValue(buf)
 		return a.NewDOid(parser.MakeDOid(parser.DInt(data))), b, err
@@ -1436,26 +1562,45 @@ func (a byID) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a byID) Less(i, j int) bool { return a[i].id < a[j].id }
 
 // EncodeSecondaryIndex encodes key/values for a secondary index. colMap maps
-// ColumnIDs to indices in `values`.
+// ColumnIDs to indices in `values`. A forward index produces exactly one
+// entry; an inverted index produces one entry per path in the indexed JSON
+// document and none when it is NULL. A partial index produces no entries for
+// the rows its predicate doesn't hold for.
 func EncodeSecondaryIndex(
 	tableDesc *TableDescriptor,
 	secondaryIndex *IndexDescriptor,
 	colMap map[ColumnID]int,
 	values []parser.Datum,
-) (IndexEntry, error) {
-	secondaryIndexKeyPrefix := MakeIndexKeyPrefix(tableDesc, secondaryIndex.ID)
-	secondaryIndexKey, containsNull, err := EncodeIndexKey(
-		tableDesc, secondaryIndex, colMap, values, secondaryIndexKeyPrefix)
-	if err != nil {
-		return IndexEntry{}, err
+) ([]IndexEntry, error) {
+	if secondaryIndex.IsPartial() {
+		i, ok := colMap[secondaryIndex.PredicateColumnID]
+		if !ok {
+			return nil, errors.Errorf("missing predicate column %d of index %q",
+				secondaryIndex.PredicateColumnID, secondaryIndex.Name)
+		}
+		if b, ok := values[i].(*parser.DBool); !ok || !bool(*b) {
+			return nil, nil
+		}
 	}
 
+	secondaryIndexKeyPrefix := MakeIndexKeyPrefix(tableDesc, secondaryIndex.ID)
+
 	// Add the extra columns - they are encoded ascendingly which is done by
 	// passing nil for the encoding directions.
 	extraKey, _, err := EncodeColumns(secondaryIndex.ExtraColumnIDs, nil,
 		colMap, values, nil)
 	if err != nil {
-		return IndexEntry{}, err
+		return nil, err
+	}
+
+	if secondaryIndex.Type == IndexDescriptor_INVERTED {
+		return encodeInvertedIndexKeys(secondaryIndex, colMap, values, secondaryIndexKeyPrefix, extraKey)
+	}
+
+	secondaryIndexKey, containsNull, err := EncodeIndexKey(
+		tableDesc, secondaryIndex, colMap, values, secondaryIndexKeyPrefix)
+	if err != nil {
+		return nil, err
 	}
 
 	entry := IndexEntry{Key: secondaryIndexKey}
@@ -1507,33 +1652,64 @@ func EncodeSecondaryIndex(
 		lastColID = col.id
 		entryValue, err = EncodeTableValue(entryValue, colIDDiff, val, nil)
 		if err != nil {
-			return IndexEntry{}, err
+			return nil, err
 		}
 	}
 	entry.Value.SetBytes(entryValue)
 
-	return entry, nil
+	return []IndexEntry{entry}, nil
+}
+
+// encodeInvertedIndexKeys returns the entries of an inverted index for the
+// indexed JSON column. Every entry has an empty value; the primary key is
+// recovered from the suffix of the key.
+func encodeInvertedIndexKeys(
+	index *IndexDescriptor,
+	colMap map[ColumnID]int,
+	values []parser.Datum,
+	keyPrefix []byte,
+	extraKey []byte,
+) ([]IndexEntry, error) {
+	if len(index.ColumnIDs) != 1 {
+		return nil, errors.Errorf("inverted index %q must have exactly one column", index.Name)
+	}
+	i, ok := colMap[index.ColumnIDs[0]]
+	if !ok || values[i] == parser.DNull {
+		return nil, nil
+	}
+	d, ok := values[i].(*parser.DJSON)
+	if !ok {
+		return nil, errors.Errorf("inverted index %q: unexpected value %s", index.Name, values[i])
+	}
+	invKeys := json.EncodeInvertedIndexKeys(keyPrefix, d.JSON)
+	entries := make([]IndexEntry, len(invKeys))
+	for j, key := range invKeys {
+		key = append(key, extraKey...)
+		entries[j].Key = keys.MakeFamilyKey(key, 0)
+		entries[j].Value.SetBytes([]byte{})
+	}
+	return entries, nil
 }
 
 // EncodeSecondaryIndexes encodes key/values for the secondary indexes. colMap
-// maps ColumnIDs to indices in `values`. secondaryIndexEntries is the return
-// value (passed as a parameter so the caller can reuse between rows) and is
-// expected to be the same length as indexes.
+// maps ColumnIDs to indices in `values`. The entries are appended to
+// secondaryIndexEntries (passed as a parameter so the caller can reuse the
+// buffer between rows) and the resulting slice is returned.
 func EncodeSecondaryIndexes(
 	tableDesc *TableDescriptor,
 	indexes []IndexDescriptor,
 	colMap map[ColumnID]int,
 	values []parser.Datum,
 	secondaryIndexEntries []IndexEntry,
-) error {
+) ([]IndexEntry, error) {
 	for i := range indexes {
-		var err error
-		secondaryIndexEntries[i], err = EncodeSecondaryIndex(tableDesc, &indexes[i], colMap, values)
+		entries, err := EncodeSecondaryIndex(tableDesc, &indexes[i], colMap, values)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		secondaryIndexEntries = append(secondaryIndexEntries, entries...)
 	}
-	return nil
+	return secondaryIndexEntries, nil
 }
 
 // CheckColumnType verifies that a given value is compatible
@@ -1634,6 +1810,16 @@ func MarshalColumnValue(col ColumnDescriptor, val parser.Datum) (roachpb.Value,
 			r.SetTime(v.Time)
 			return r, nil
 		}
+	case ColumnType_TIME:
+		if v, ok := val.(*parser.DTime); ok {
+			r.SetInt(int64(*v))
+			return r, nil
+		}
+	case ColumnType_TIMETZ:
+		if v, ok := val.(*parser.DTimeTZ); ok {
+			r.SetBytes(encodeTimeTZ(nil, v))
+			return r, nil
+		}
 	case ColumnType_INTERVAL:
 		if v, ok := val.(*parser.DInterval); ok {
 			err := r.SetDuration(v.Duration)
@@ -1650,6 +1836,11 @@ func MarshalColumnValue(col ColumnDescriptor, val parser.Datum) (roachpb.Value,
 			r.SetBytes(data)
 			return r, nil
 		}
+	case ColumnType_JSON:
+		if v, ok := val.(*parser.DJSON); ok {
+			r.SetBytes(json.EncodeJSON(nil, v.JSON))
+			return r, nil
+		}
 	case ColumnType_ARRAY:
 		if v, ok := val.(*parser.DArray); ok {
 			if err := checkElementType(v.ParamTyp, col.Type); err != nil {
@@ -1751,6 +1942,26 @@ func encodeArray(d *parser.DArray, scratch []byte) ([]byte, error) {
 	return scratch, nil
 }
 
+// encodeTimeTZ appends the value encoding of a DTimeTZ, its time of day
+// followed by its offset, to b.
+func encodeTimeTZ(b []byte, t *parser.DTimeTZ) []byte {
+	b = encoding.EncodeVarintAscending(b, int64(t.Time))
+	return encoding.EncodeVarintAscending(b, int64(t.OffsetSecs))
+}
+
+// decodeTimeTZ decodes a DTimeTZ encoded by encodeTimeTZ.
+func decodeTimeTZ(b []byte) (parser.DTimeTZ, error) {
+	b, t, err := encoding.DecodeVarintAscending(b)
+	if err != nil {
+		return parser.DTimeTZ{}, err
+	}
+	_, offset, err := encoding.DecodeVarintAscending(b)
+	if err != nil {
+		return parser.DTimeTZ{}, err
+	}
+	return parser.DTimeTZ{Time: parser.DTime(t), OffsetSecs: int32(offset)}, nil
+}
+
 func parserTypeToEncodingType(t parser.Type) (encoding.Type, error) {
 	switch t {
 	case parser.TypeInt:
@@ -1769,6 +1980,10 @@ func parserTypeToEncodingType(t parser.Type) (encoding.Type, error) {
 		return encoding.Bytes, nil
 	case parser.TypeTimestamp, parser.TypeTimestampTZ, parser.TypeDate:
 		return encoding.Time, nil
+	case parser.TypeTime:
+		return encoding.Int, nil
+	case parser.TypeTimeTZ:
+		return encoding.Bytes, nil
 	case parser.TypeInterval:
 		return encoding.Duration, nil
 	case parser.TypeBool:
@@ -1809,6 +2024,10 @@ func encodeArrayElement(b []byte, d parser.Datum) ([]byte, error) {
 		return encoding.EncodeUntaggedTimeValue(b, t.Time), nil
 	case *parser.DTimestampTZ:
 		return encoding.EncodeUntaggedTimeValue(b, t.Time), nil
+	case *parser.DTime:
+		return encoding.EncodeUntaggedIntValue(b, int64(*t)), nil
+	case *parser.DTimeTZ:
+		return encoding.EncodeUntaggedBytesValue(b, encodeTimeTZ(nil, t)), nil
 	case *parser.DInterval:
 		return encoding.EncodeUntaggedDurationValue(b, t.Duration), nil
 	case *parser.DUuid:
@@ -1889,6 +2108,22 @@ func UnmarshalColumnValue(
 			return nil, err
 		}
 		return a.NewDTimestampTZ(parser.DTimestampTZ{Time: v}), nil
+	case ColumnType_TIME:
+		v, err := value.GetInt()
+		if err != nil {
+			return nil, err
+		}
+		return a.NewDTime(parser.DTime(v)), nil
+	case ColumnType_TIMETZ:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		t, err := decodeTimeTZ(v)
+		if err != nil {
+			return nil, err
+		}
+		return a.NewDTimeTZ(t), nil
 	case ColumnType_INTERVAL:
 		d, err := value.GetDuration()
 		if err != nil {
@@ -1922,6 +2157,16 @@ func UnmarshalColumnValue(
 			return nil, err
 		}
 		return a.NewDIPAddr(parser.DIPAddr{IPAddr: ipAddr}), nil
+	case ColumnType_JSON:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		j, err := decodeJSON(v)
+		if err != nil {
+			return nil, err
+		}
+		return a.NewDJSON(parser.DJSON{JSON: j}), nil
 	case ColumnType_NAME:
 		v, err := value.GetBytes()
 		if err != nil {
@@ -1939,6 +2184,19 @@ func UnmarshalColumnValue(
 	}
 }
 
+// decodeJSON decodes a JSON document encoded by json.EncodeJSON, checking
+// that the whole buffer is consumed.
+func decodeJSON(b []byte) (json.JSON, error) {
+	rem, j, err := json.DecodeJSON(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(rem) != 0 {
+		return nil, errors.Errorf("%d trailing bytes in encoded JSON value", len(rem))
+	}
+	return j, nil
+}
+
 // CheckValueWidth checks that the width (for strings, byte arrays, and
 // bit string) and scale (for decimals) of the value fits the specified
 // column type. Used by INSERT and UPDATE.
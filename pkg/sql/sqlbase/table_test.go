@@ -200,11 +200,15 @@ func TestIndexKey(t *testing.T) {
 		primaryValue := roachpb.MakeValueFromBytes(nil)
 		primaryIndexKV := client.KeyValue{Key: primaryKey, Value: &primaryValue}
 
-		secondaryIndexEntry, err := EncodeSecondaryIndex(
+		secondaryIndexEntries, err := EncodeSecondaryIndex(
 			&tableDesc, &tableDesc.Indexes[0], colMap, testValues)
 		if err != nil {
 			t.Fatal(err)
 		}
+		if len(secondaryIndexEntries) != 1 {
+			t.Fatalf("expected 1 index entry, got %d", len(secondaryIndexEntries))
+		}
+		secondaryIndexEntry := secondaryIndexEntries[0]
 		secondaryIndexKV := client.KeyValue{
 			Key:   secondaryIndexEntry.Key,
 			Value: &secondaryIndexEntry.Value,
@@ -23,11 +23,13 @@ import (
 
 	"golang.org/x/net/context"
 
+	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
@@ -87,6 +89,14 @@ func RandDatum(rng *rand.Rand, typ ColumnType, null bool) parser.Datum {
 		return parser.NewDDate(parser.DDate(rng.Intn(10000)))
 	case ColumnType_TIMESTAMP:
 		return &parser.DTimestamp{Time: time.Unix(rng.Int63n(1000000), rng.Int63n(1000000))}
+	case ColumnType_TIME:
+		t := parser.DTime(rng.Int63n(24 * 60 * 60 * 1000000))
+		return &t
+	case ColumnType_TIMETZ:
+		return &parser.DTimeTZ{
+			Time:       parser.DTime(rng.Int63n(24 * 60 * 60 * 1000000)),
+			OffsetSecs: int32(rng.Intn(2*14*60*60+1) - 14*60*60),
+		}
 	case ColumnType_INTERVAL:
 		sign := 1 - rng.Int63n(2)*2
 		return &parser.DInterval{Duration: duration.Duration{
@@ -99,6 +109,8 @@ func RandDatum(rng *rand.Rand, typ ColumnType, null bool) parser.Datum {
 	case ColumnType_INET:
 		ipAddr := ipaddr.RandIPAddr(rng)
 		return parser.NewDIPAddr(parser.DIPAddr{IPAddr: ipAddr})
+	case ColumnType_JSON:
+		return parser.NewDJSON(randJSON(rng, 3))
 	case ColumnType_STRING:
 		// Generate a random ASCII string.
 		p := make([]byte, rng.Intn(10))
@@ -151,6 +163,46 @@ func RandDatum(rng *rand.Rand, typ ColumnType, null bool) parser.Datum {
 	}
 }
 
+// randJSON generates a random JSON document nested at most depth levels
+// deep.
+func randJSON(rng *rand.Rand, depth int) json.JSON {
+	n := rng.Intn(7)
+	if depth == 0 {
+		n = rng.Intn(5)
+	}
+	switch n {
+	case 0:
+		return json.NullJSONValue
+	case 1:
+		return json.FromBool(rng.Intn(2) == 0)
+	case 2:
+		return json.FromInt(rng.Int63n(1000) - 500)
+	case 3:
+		p := make([]byte, rng.Intn(5))
+		for i := range p {
+			p[i] = byte('a' + rng.Intn(26))
+		}
+		return json.FromString(string(p))
+	case 4:
+		var d apd.Decimal
+		d.SetExponent(-int32(rng.Intn(5)))
+		d.SetCoefficient(rng.Int63n(100000))
+		return json.FromDecimal(d)
+	case 5:
+		elems := make([]json.JSON, rng.Intn(4))
+		for i := range elems {
+			elems[i] = randJSON(rng, depth-1)
+		}
+		return json.FromArray(elems)
+	default:
+		b := json.NewObjectBuilder()
+		for i, l := 0, rng.Intn(4); i < l; i++ {
+			b.Add(string('a'+rune(rng.Intn(5))), randJSON(rng, depth-1))
+		}
+		return b.Build()
+	}
+}
+
 var (
 	columnSemanticTypes []ColumnType_SemanticType
 	collationLocales    = [...]string{"da", "de", "en"}
@@ -158,6 +210,11 @@ var (
 
 func init() {
 	for k := range ColumnType_SemanticType_name {
+		// JSON values have no key encoding, which users of random column
+		// types expect to be able to use.
+		if ColumnType_SemanticType(k) == ColumnType_JSON {
+			continue
+		}
 		columnSemanticTypes = append(columnSemanticTypes, ColumnType_SemanticType(k))
 	}
 }
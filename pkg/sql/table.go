@@ -161,6 +161,24 @@ func getViewDesc(
 	return desc, nil
 }
 
+// getSequenceDesc returns a table descriptor for a sequence, or nil if the
+// descriptor is not found.
+//
+// Returns an error if the underlying table descriptor actually
+// represents a table or a view rather than a sequence.
+func getSequenceDesc(
+	ctx context.Context, txn *client.Txn, vt VirtualTabler, tn *parser.TableName,
+) (*sqlbase.TableDescriptor, error) {
+	desc, err := getTableOrViewDesc(ctx, txn, vt, tn)
+	if err != nil {
+		return desc, err
+	}
+	if desc != nil && !desc.IsSequence() {
+		return nil, sqlbase.NewWrongObjectTypeError(tn, "sequence")
+	}
+	return desc, nil
+}
+
 // MustGetTableOrViewDesc returns a table descriptor for either a table or
 // view, or an error if the descriptor is not found. allowAdding when set allows
 // a table descriptor in the ADD state to also be returned.
@@ -256,6 +274,12 @@ type TableCollection struct {
 	// an uncommitted transaction.
 	uncommittedDatabases []uncommittedDatabase
 
+	// Sequences whose value was written by the uncommitted transaction,
+	// by CREATE SEQUENCE or setval(). Their value cannot be incremented
+	// outside of the transaction, which would hang waiting for the
+	// uncommitted write.
+	uncommittedSequences []sqlbase.ID
+
 	// leaseMgr manages acquiring and releasing per-table leases.
 	leaseMgr *LeaseManager
 	// databaseCache is used as a cache for database names.
@@ -449,6 +473,7 @@ func (tc *TableCollection) releaseTables(ctx context.Context) {
 	}
 	tc.uncommittedTables = nil
 	tc.uncommittedDatabases = nil
+	tc.uncommittedSequences = nil
 }
 
 func (tc *TableCollection) addUncommittedTable(desc sqlbase.TableDescriptor) {
@@ -461,6 +486,24 @@ func (tc *TableCollection) addUncommittedTable(desc sqlbase.TableDescriptor) {
 	tc.uncommittedTables = append(tc.uncommittedTables, &desc)
 }
 
+func (tc *TableCollection) addUncommittedSequence(id sqlbase.ID) {
+	if !tc.isUncommittedSequence(id) {
+		tc.uncommittedSequences = append(tc.uncommittedSequences, id)
+	}
+}
+
+// isUncommittedSequence returns whether the value of the sequence with
+// the given ID was written by the transaction affiliated with the
+// TableCollection.
+func (tc *TableCollection) isUncommittedSequence(id sqlbase.ID) bool {
+	for _, seqID := range tc.uncommittedSequences {
+		if seqID == id {
+			return true
+		}
+	}
+	return false
+}
+
 func (tc *TableCollection) addUncommittedDatabase(name string, id sqlbase.ID, dropped bool) {
 	db := uncommittedDatabase{name: name, id: id, dropped: dropped}
 	tc.uncommittedDatabases = append(tc.uncommittedDatabases, db)
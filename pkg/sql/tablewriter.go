@@ -194,6 +194,30 @@ func (tu *tableUpdater) fkSpanCollector() sqlbase.FkSpanCollector {
 
 func (tu *tableUpdater) close(_ context.Context) {}
 
+// computeUpdateValues extends the values assigned to the first columns of an
+// update to numUpdateCols values, with the values of the computed columns
+// that follow them. These are computed from the updated row, i.e. oldValues
+// merged with updateValues.
+func computeUpdateValues(
+	evalCtx *parser.EvalContext,
+	computedCols *sqlbase.ComputedColumns,
+	fetchColIDtoRowIndex map[sqlbase.ColumnID]int,
+	oldValues parser.Datums,
+	updateColIDtoRowIndex map[sqlbase.ColumnID]int,
+	updateValues parser.Datums,
+	numUpdateCols int,
+) (parser.Datums, error) {
+	for len(updateValues) < numUpdateCols {
+		updateValues = append(updateValues, parser.DNull)
+	}
+	computedCols.LoadRow(fetchColIDtoRowIndex, oldValues, false)
+	computedCols.LoadRow(updateColIDtoRowIndex, updateValues, true)
+	if err := computedCols.ComputeInto(evalCtx, updateColIDtoRowIndex, updateValues); err != nil {
+		return nil, err
+	}
+	return updateValues, nil
+}
+
 type tableUpsertEvaler interface {
 	expressionCarrier
 
@@ -237,9 +261,16 @@ type tableUpserter struct {
 	mon           *mon.BytesMonitor
 	collectRows   bool
 
+	// evalCtx is used by the cascading actions of foreign keys in the update
+	// case.
+	evalCtx *parser.EvalContext
+
 	// These are set for ON CONFLICT DO UPDATE, but not for DO NOTHING
 	updateCols []sqlbase.ColumnDescriptor
 	evaler     tableUpsertEvaler
+	// computedCols computes the values of the computed columns at the end of
+	// updateCols, which are not assigned to by the evaler.
+	computedCols *sqlbase.ComputedColumns
 
 	// Set by init.
 	txn                   *client.Txn
@@ -315,7 +346,7 @@ func (tu *tableUpserter) init(txn *client.Txn) error {
 		var err error
 		tu.ru, err = sqlbase.MakeRowUpdater(
 			txn, tableDesc, tu.fkTables, tu.updateCols, requestedCols,
-			sqlbase.RowUpdaterDefault, tu.alloc,
+			sqlbase.RowUpdaterDefault, tu.evalCtx, tu.alloc,
 		)
 		if err != nil {
 			return err
@@ -454,6 +485,15 @@ func (tu *tableUpserter) flush(
 				if err != nil {
 					return nil, err
 				}
+				if tu.computedCols != nil {
+					updateValues, err = computeUpdateValues(
+						tu.evalCtx, tu.computedCols, tu.fetchColIDtoRowIndex, existingValues,
+						tu.updateColIDtoRowIndex, updateValues, len(tu.updateCols),
+					)
+					if err != nil {
+						return nil, err
+					}
+				}
 				updatedRow, err := tu.ru.UpdateRow(ctx, b, existingValues, updateValues, traceKV)
 				if err != nil {
 					return nil, err
@@ -511,11 +551,14 @@ func (tu *tableUpserter) upsertRowPKs(ctx context.Context, traceKV bool) ([]roac
 	b := tu.txn.NewBatch()
 	for i := 0; i < tu.insertRows.Len(); i++ {
 		insertRow := tu.insertRows.At(i)
-		entry, err := sqlbase.EncodeSecondaryIndex(
+		entries, err := sqlbase.EncodeSecondaryIndex(
 			tableDesc, &tu.conflictIndex, tu.ri.InsertColIDtoRowIndex, insertRow)
 		if err != nil {
 			return nil, err
 		}
+		// The conflict index is always a unique forward index, which has
+		// exactly one entry per row.
+		entry := entries[0]
 		if traceKV {
 			log.VEventf(ctx, 2, "Get %s", entry.Key)
 		}
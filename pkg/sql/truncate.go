@@ -57,7 +57,10 @@ func (p *planner) Truncate(ctx context.Context, n *parser.Truncate) (planNode, e
 		if err != nil {
 			return nil, err
 		}
-		// We don't support truncation on views, only real tables.
+		// We don't support truncation on views or sequences, only real tables.
+		if tableDesc.IsSequence() {
+			return nil, errors.Errorf("cannot run TRUNCATE on sequence %q - sequences are not updateable", tn)
+		}
 		if !tableDesc.IsTable() {
 			return nil, errors.Errorf("cannot run TRUNCATE on view %q - views are not updateable", tn)
 		}
@@ -338,7 +341,7 @@ func truncateTableInChunks(
 			log.VEventf(ctx, 2, "table %s truncate at row: %d, span: %s", tableDesc.Name, row, resume)
 		}
 		if err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
-			rd, err := sqlbase.MakeRowDeleter(txn, tableDesc, nil, nil, false, alloc)
+			rd, err := sqlbase.MakeRowDeleter(txn, tableDesc, nil, nil, false, nil, alloc)
 			if err != nil {
 				return err
 			}
@@ -52,14 +52,28 @@ func (p *planner) makeEditNode(
 	if err != nil {
 		return editNodeBase{}, err
 	}
-	// We don't support update on views, only real tables.
+	// We don't support update on views or sequences, only real tables.
+	if tableDesc.IsSequence() {
+		return editNodeBase{},
+			errors.Errorf("cannot run %s on sequence %q - sequences are not updateable", priv, tn)
+	}
 	if !tableDesc.IsTable() {
 		return editNodeBase{},
 			errors.Errorf("cannot run %s on view %q - views are not updateable", priv, tn)
 	}
 
 	if err := p.CheckPrivilege(tableDesc, priv); err != nil {
-		return editNodeBase{}, err
+		// The INSERT and UPDATE privileges can also be granted on columns
+		// alone, in which case the caller must check the privilege on the
+		// columns it writes with checkColumnPrivileges.
+		if priv != privilege.INSERT && priv != privilege.UPDATE {
+			return editNodeBase{}, err
+		}
+		if ok, colErr := p.anyColumnPrivilege(tableDesc, priv); colErr != nil {
+			return editNodeBase{}, colErr
+		} else if !ok {
+			return editNodeBase{}, err
+		}
 	}
 
 	return editNodeBase{
@@ -88,7 +102,7 @@ func (r *editNodeRun) initEditNode(
 	r.rows = rows
 	r.tw = tw
 
-	rh, err := en.p.newReturningHelper(ctx, re, desiredTypes, tn, en.tableDesc.Columns)
+	rh, err := en.p.newReturningHelper(ctx, re, desiredTypes, tn, en.tableDesc)
 	if err != nil {
 		return err
 	}
@@ -117,6 +131,9 @@ type updateNode struct {
 	tw            tableUpdater
 	checkHelper   checkHelper
 	sourceSlots   []sourceSlot
+	// computedCols computes the values of the computed columns at the end of
+	// updateCols, which are not assigned to by sourceSlots.
+	computedCols *sqlbase.ComputedColumns
 
 	run struct {
 		// The following fields are populated during Start().
@@ -211,10 +228,19 @@ func (p *planner) Update(
 
 	tracing.AnnotateTrace()
 
+	resetWith, err := p.initWith(ctx, n.With)
+	if err != nil {
+		return nil, err
+	}
+	defer resetWith()
+
 	tn, err := p.getAliasedTableName(n.Table)
 	if err != nil {
 		return nil, err
 	}
+	if err := p.checkEditTargetNotShadowed(tn); err != nil {
+		return nil, err
+	}
 
 	en, err := p.makeEditNode(ctx, tn, privilege.UPDATE)
 	if err != nil {
@@ -241,25 +267,42 @@ func (p *planner) Update(
 	if err != nil {
 		return nil, err
 	}
+	if err := p.checkColumnPrivileges(en.tableDesc, updateCols, privilege.UPDATE); err != nil {
+		return nil, err
+	}
 
 	defaultExprs, err := sqlbase.MakeDefaultExprs(updateCols, &p.parser, &p.evalCtx)
 	if err != nil {
 		return nil, err
 	}
 
+	// The computed columns depending on the updated columns are updated too.
+	// Their values follow the ones of the SET expressions.
+	computedUpdateCols, err := sqlbase.ComputedColumnsToUpdate(en.tableDesc, updateCols)
+	if err != nil {
+		return nil, err
+	}
+	computedCols, err := sqlbase.MakeComputedColumns(en.tableDesc, computedUpdateCols)
+	if err != nil {
+		return nil, err
+	}
+	updateCols = append(updateCols, computedUpdateCols...)
+
 	var requestedCols []sqlbase.ColumnDescriptor
-	if _, retExprs := n.Returning.(*parser.ReturningExprs); retExprs || len(en.tableDesc.Checks) > 0 {
+	if _, retExprs := n.Returning.(*parser.ReturningExprs); retExprs ||
+		len(en.tableDesc.Checks) > 0 || computedCols != nil ||
+		en.tableDesc.RowLevelSecurityApplies(p.session.User) {
 		// TODO(dan): This could be made tighter, just the rows needed for RETURNING
 		// exprs.
 		requestedCols = en.tableDesc.Columns
 	}
 
-	fkTables := sqlbase.TablesNeededForFKs(*en.tableDesc, sqlbase.CheckUpdates)
-	if err := p.fillFKTableMap(ctx, fkTables); err != nil {
+	fkTables, err := p.lookupFKTables(ctx, en.tableDesc, sqlbase.CheckUpdates)
+	if err != nil {
 		return nil, err
 	}
 	ru, err := sqlbase.MakeRowUpdater(p.txn, en.tableDesc, fkTables, updateCols,
-		requestedCols, sqlbase.RowUpdaterDefault, &p.alloc)
+		requestedCols, sqlbase.RowUpdaterDefault, &p.evalCtx, &p.alloc)
 	if err != nil {
 		return nil, err
 	}
@@ -270,7 +313,7 @@ func (p *planner) Update(
 	// We construct a query containing the columns being updated, and then later merge the values
 	// they are being updated with into that renderNode to ideally reuse some of the queries.
 	rows, err := p.SelectClause(ctx, &parser.SelectClause{
-		Exprs: sqlbase.ColumnsSelectors(ru.FetchCols),
+		Exprs: sqlbase.ColumnsSelectors(ru.FetchCols, true),
 		From:  &parser.From{Tables: []parser.TableExpr{n.Table}},
 		Where: n.Where,
 	}, nil, nil, nil, publicAndNonPublicColumns)
@@ -369,6 +412,7 @@ func (p *planner) Update(
 		updateColsIdx: updateColsIdx,
 		tw:            tw,
 		sourceSlots:   sourceSlots,
+		computedCols:  computedCols,
 	}
 	if err := un.checkHelper.init(ctx, p, tn, en.tableDesc); err != nil {
 		return nil, err
@@ -424,6 +468,16 @@ func (u *updateNode) Next(params runParams) (bool, error) {
 			valueIdx++
 		}
 	}
+	if u.computedCols != nil {
+		var err error
+		updateValues, err = computeUpdateValues(
+			&params.p.evalCtx, u.computedCols, u.tw.ru.FetchColIDtoRowIndex, oldValues,
+			u.updateColsIdx, updateValues[:valueIdx], len(updateValues),
+		)
+		if err != nil {
+			return false, err
+		}
+	}
 
 	if err := u.checkHelper.loadRow(u.tw.ru.FetchColIDtoRowIndex, oldValues, false); err != nil {
 		return false, err
@@ -215,6 +215,10 @@ func upsertExprsAndIndex(
 		}
 		updateExprs := make(parser.UpdateExprs, 0, len(insertCols))
 		for _, c := range insertCols {
+			if c.IsComputed() {
+				// Computed columns are recomputed from the updated row instead.
+				continue
+			}
 			if _, ok := indexColSet[c.ID]; !ok {
 				names := parser.UnresolvedNames{
 					parser.UnresolvedName{parser.Name(c.Name)},
@@ -230,7 +234,8 @@ func upsertExprsAndIndex(
 	}
 
 	indexMatch := func(index sqlbase.IndexDescriptor) bool {
-		if !index.Unique {
+		// A partial index only guarantees uniqueness among some of the rows.
+		if !index.Unique || index.IsPartial() {
 			return false
 		}
 		if len(index.ColumnNames) != len(onConflict.Columns) {
@@ -49,8 +49,17 @@ type sessionVar struct {
 	// Reset performs mutations (usually on session) to effect the change
 	// desired by RESET commands.
 	Reset func(*Session) error
+
+	// Save returns a function restoring the current value of the variable.
+	// It is used to undo SET LOCAL at the end of the transaction; variables
+	// without it cannot be set locally.
+	Save func(*Session) func()
 }
 
+// saveNothing is the Save function of the variables whose value SET
+// doesn't change.
+func saveNothing(*Session) func() { return func() {} }
+
 // nopVar is a placeholder for a number of settings sent by various client
 // drivers which we do not support, but should simply ignore rather than
 // throwing an error when trying to SET or SHOW them.
@@ -58,6 +67,7 @@ var nopVar = sessionVar{
 	Set:   func(context.Context, *Session, []parser.TypedExpr) error { return nil },
 	Get:   func(*Session) string { return "" },
 	Reset: func(*Session) error { return nil },
+	Save:  saveNothing,
 }
 
 // varGen is the main definition array for all session variables.
@@ -84,6 +94,12 @@ var varGen = map[string]sessionVar{
 			session.resetApplicationName(session.defaults.applicationName)
 			return nil
 		},
+		Save: func(session *Session) func() {
+			session.mu.RLock()
+			defer session.mu.RUnlock()
+			appName := session.mu.ApplicationName
+			return func() { session.resetApplicationName(appName) }
+		},
 	},
 
 	// Supported for PG compatibility only.
@@ -110,6 +126,7 @@ var varGen = map[string]sessionVar{
 			return nil
 		},
 		Reset: func(*Session) error { return nil },
+		Save:  saveNothing,
 	},
 
 	`database`: {
@@ -136,6 +153,10 @@ var varGen = map[string]sessionVar{
 			session.Database = session.defaults.database
 			return nil
 		},
+		Save: func(session *Session) func() {
+			dbName := session.Database
+			return func() { session.Database = dbName }
+		},
 	},
 
 	`datestyle`: {
@@ -154,6 +175,7 @@ var varGen = map[string]sessionVar{
 			return nil
 		},
 		Reset: func(*Session) error { return nil },
+		Save:  saveNothing,
 	},
 
 	`default_transaction_isolation`: {
@@ -184,6 +206,10 @@ var varGen = map[string]sessionVar{
 			session.DefaultIsolationLevel = enginepb.IsolationType(0)
 			return nil
 		},
+		Save: func(session *Session) func() {
+			isolation := session.DefaultIsolationLevel
+			return func() { session.DefaultIsolationLevel = isolation }
+		},
 	},
 
 	`distsql`: {
@@ -214,6 +240,10 @@ var varGen = map[string]sessionVar{
 			session.DistSQLMode = DistSQLExecMode(DistSQLClusterExecMode.Get(&session.execCfg.Settings.SV))
 			return nil
 		},
+		Save: func(session *Session) func() {
+			mode := session.DistSQLMode
+			return func() { session.DistSQLMode = mode }
+		},
 	},
 
 	// Supported for PG compatibility only.
@@ -239,6 +269,10 @@ var varGen = map[string]sessionVar{
 			session.SafeUpdates = (b == parser.DBoolTrue)
 			return nil
 		},
+		Save: func(session *Session) func() {
+			safeUpdates := session.SafeUpdates
+			return func() { session.SafeUpdates = safeUpdates }
+		},
 	},
 
 	`search_path`: {
@@ -260,6 +294,10 @@ var varGen = map[string]sessionVar{
 			session.SearchPath = sqlbase.DefaultSearchPath
 			return nil
 		},
+		Save: func(session *Session) func() {
+			searchPath := session.SearchPath
+			return func() { session.SearchPath = searchPath }
+		},
 	},
 
 	`server_version`: {
@@ -287,6 +325,7 @@ var varGen = map[string]sessionVar{
 		},
 		Get:   func(*Session) string { return "on" },
 		Reset: func(*Session) error { return nil },
+		Save:  saveNothing,
 	},
 
 	`time zone`: {
@@ -306,6 +345,10 @@ var varGen = map[string]sessionVar{
 			session.Location = time.UTC
 			return nil
 		},
+		Save: func(session *Session) func() {
+			location := session.Location
+			return func() { session.Location = location }
+		},
 	},
 
 	`transaction isolation level`: {
@@ -394,6 +437,73 @@ func stopTracing(s *Session) error {
 	return nil
 }
 
+// isCustomVarName returns whether name is the name of a user-defined session
+// variable. As in PostgreSQL, these are the names containing a dot.
+func isCustomVarName(name string) bool {
+	return strings.Contains(name, ".")
+}
+
+// customVar returns the sessionVar for the user-defined session variable
+// name. Any value can be assigned to it; it is stored as a string.
+func customVar(name string) sessionVar {
+	return sessionVar{
+		Set: func(_ context.Context, session *Session, values []parser.TypedExpr) error {
+			if len(values) != 1 {
+				return fmt.Errorf("set %s: requires a single value", name)
+			}
+			evalCtx := session.evalCtx()
+			val, err := values[0].Eval(&evalCtx)
+			if err != nil {
+				return err
+			}
+			if session.CustomVars == nil {
+				session.CustomVars = make(map[string]string)
+			}
+			session.CustomVars[name] = parser.AsStringWithFlags(val, parser.FmtBareStrings)
+			return nil
+		},
+		Get: func(session *Session) string {
+			return session.CustomVars[name]
+		},
+		Reset: func(session *Session) error {
+			delete(session.CustomVars, name)
+			return nil
+		},
+		Save: func(session *Session) func() {
+			val, ok := session.CustomVars[name]
+			return func() {
+				if ok {
+					session.CustomVars[name] = val
+				} else {
+					delete(session.CustomVars, name)
+				}
+			}
+		},
+	}
+}
+
+// lookupSessionVar returns the session variable name, which can be
+// user-defined.
+func lookupSessionVar(name string) (sessionVar, bool) {
+	if v, ok := varGen[name]; ok {
+		return v, true
+	}
+	if isCustomVarName(name) {
+		return customVar(name), true
+	}
+	return sessionVar{}, false
+}
+
+// GetSessionVar implements the parser.EvalPlanner interface.
+func (p *planner) GetSessionVar(_ context.Context, name string) (string, bool) {
+	name = strings.ToLower(name)
+	if v, ok := varGen[name]; ok {
+		return v.Get(p.session), true
+	}
+	val, ok := p.session.CustomVars[name]
+	return val, ok
+}
+
 var varNames = func() []string {
 	res := make([]string, 0, len(varGen))
 	for vName := range varGen {
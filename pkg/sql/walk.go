@@ -210,6 +210,9 @@ func (v *planVisitor) visit(plan planNode) {
 				}
 				v.observer.attr(name, "mergeJoinOrder", order.AsString(eqCols))
 			}
+			if n.lookupJoin {
+				v.observer.attr(name, "algorithm", "lookup")
+			}
 		}
 		subplans := v.expr(name, "pred", -1, n.pred.onCond, nil)
 		v.subqueries(name, subplans)
@@ -287,6 +290,18 @@ func (v *planVisitor) visit(plan planNode) {
 		v.visit(n.left)
 		v.visit(n.right)
 
+	case *recursiveCTENode:
+		if v.observer.attr != nil {
+			v.observer.attr(name, "name", n.name)
+			v.observer.attr(name, "recursive", parser.AsStringWithFlags(n.recursive, parser.FmtParsable))
+		}
+		v.visit(n.initial)
+
+	case *workTableScanNode:
+		if v.observer.attr != nil {
+			v.observer.attr(name, "name", n.cte.name)
+		}
+
 	case *splitNode:
 		v.visit(n.rows)
 
@@ -518,25 +533,33 @@ func nodeName(plan planNode) string {
 // strings are constant and not precomputed so that the type names can
 // be changed without changing the output of "EXPLAIN".
 var planNodeNames = map[reflect.Type]string{
+	reflect.TypeOf(&alterSequenceNode{}):     "alter sequence",
 	reflect.TypeOf(&alterTableNode{}):        "alter table",
 	reflect.TypeOf(&cancelQueryNode{}):       "cancel query",
 	reflect.TypeOf(&controlJobNode{}):        "control job",
 	reflect.TypeOf(&copyNode{}):              "copy",
 	reflect.TypeOf(&createDatabaseNode{}):    "create database",
 	reflect.TypeOf(&createIndexNode{}):       "create index",
+	reflect.TypeOf(&createPolicyNode{}):      "create policy",
+	reflect.TypeOf(&createSequenceNode{}):    "create sequence",
+	reflect.TypeOf(&createStatsNode{}):       "create statistics",
 	reflect.TypeOf(&createTableNode{}):       "create table",
 	reflect.TypeOf(&createUserNode{}):        "create user",
 	reflect.TypeOf(&createViewNode{}):        "create view",
+	reflect.TypeOf(&declareCursorNode{}):     "declare cursor",
 	reflect.TypeOf(&delayedNode{}):           "virtual table",
 	reflect.TypeOf(&deleteNode{}):            "delete",
 	reflect.TypeOf(&distinctNode{}):          "distinct",
 	reflect.TypeOf(&dropDatabaseNode{}):      "drop database",
 	reflect.TypeOf(&dropIndexNode{}):         "drop index",
+	reflect.TypeOf(&dropPolicyNode{}):        "drop policy",
+	reflect.TypeOf(&dropSequenceNode{}):      "drop sequence",
 	reflect.TypeOf(&dropTableNode{}):         "drop table",
 	reflect.TypeOf(&dropViewNode{}):          "drop view",
 	reflect.TypeOf(&dropUserNode{}):          "drop user",
 	reflect.TypeOf(&explainDistSQLNode{}):    "explain dist_sql",
 	reflect.TypeOf(&explainPlanNode{}):       "explain plan",
+	reflect.TypeOf(&fetchNode{}):             "fetch",
 	reflect.TypeOf(&traceNode{}):             "show trace for",
 	reflect.TypeOf(&filterNode{}):            "filter",
 	reflect.TypeOf(&groupNode{}):             "group",
@@ -547,6 +570,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&joinNode{}):              "join",
 	reflect.TypeOf(&limitNode{}):             "limit",
 	reflect.TypeOf(&ordinalityNode{}):        "ordinality",
+	reflect.TypeOf(&recursiveCTENode{}):      "recursive cte",
 	reflect.TypeOf(&testingRelocateNode{}):   "testingRelocate",
 	reflect.TypeOf(&renderNode{}):            "render",
 	reflect.TypeOf(&scanNode{}):              "scan",
@@ -562,5 +586,6 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&valueGenerator{}):        "generator",
 	reflect.TypeOf(&valuesNode{}):            "values",
 	reflect.TypeOf(&windowNode{}):            "window",
+	reflect.TypeOf(&workTableScanNode{}):     "work table",
 	reflect.TypeOf(&zeroNode{}):              "norows",
 }
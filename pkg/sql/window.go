@@ -32,7 +32,7 @@ import (
 // adjust the render targets in the renderNode as necessary. The use of window functions
 // will run with a space complexity of O(NW) (N = number of rows, W = number of windows)
 // and a time complexity of O(NW) (no ordering), O(W*NlogN) (with ordering), and
-// O(W*N^2) (with constant or variable sized window-frames, which are not yet supported).
+// O(W*N^2) (with window frames that don't start at the first row of the partition).
 //
 // This code uses the following terminology throughout:
 // - window:
@@ -208,11 +208,123 @@ func (n *windowNode) constructWindowDefinitions(
 			}
 		}
 
+		// Validate the frame clause.
+		if windowDef.Frame != nil {
+			if err := n.constructWindowFrame(ctx, windowFn, windowDef.Frame, s); err != nil {
+				return err
+			}
+		}
+
 		windowFn.windowDef = windowDef
 	}
 	return nil
 }
 
+// constructWindowFrame type checks the offsets of the bounds of a window
+// function application's frame. For RANGE frames with offsets, it also looks up
+// the operators used to compute the bounds from the values of the single ORDER
+// BY column.
+func (n *windowNode) constructWindowFrame(
+	ctx context.Context, windowFn *windowFuncHolder, frame *parser.WindowFrame, s *renderNode,
+) error {
+	startBound, endBound := frame.Bounds.StartBound, frame.Bounds.EndBound
+	if !startBound.HasOffset() && (endBound == nil || !endBound.HasOffset()) {
+		return nil
+	}
+
+	offsetType := parser.Type(parser.TypeInt)
+	if frame.Mode == parser.RangeFrame {
+		if len(windowFn.columnOrdering) != 1 {
+			return errors.Errorf(
+				"RANGE with offset PRECEDING/FOLLOWING requires exactly one ORDER BY column")
+		}
+		ordType := s.columns[windowFn.columnOrdering[0].ColIdx].Typ
+		offsetType = ordType
+		if ordType.Equivalent(parser.TypeTimestamp) || ordType.Equivalent(parser.TypeTimestampTZ) {
+			offsetType = parser.TypeInterval
+		}
+		plusOp, minusOp, ok := parser.WindowFrameRangeOps(ordType, offsetType)
+		if !ok {
+			return errors.Errorf(
+				"RANGE with offset PRECEDING/FOLLOWING is not supported for column type %s", ordType)
+		}
+		windowFn.framePlusOp, windowFn.frameMinusOp = plusOp, minusOp
+	}
+
+	name := frame.Mode.String()
+	bounds := []struct {
+		bound *parser.WindowFrameBound
+		dst   *parser.TypedExpr
+	}{
+		{startBound, &windowFn.frameStartOffset},
+		{endBound, &windowFn.frameEndOffset},
+	}
+	for _, b := range bounds {
+		if b.bound == nil || !b.bound.HasOffset() {
+			continue
+		}
+		if err := n.planner.parser.AssertNoAggregationOrWindowing(
+			b.bound.OffsetExpr, name, n.planner.session.SearchPath,
+		); err != nil {
+			return err
+		}
+		typedOffset, err := n.planner.analyzeExpr(
+			ctx, b.bound.OffsetExpr, nil, parser.IndexedVarHelper{}, offsetType, true, name,
+		)
+		if err != nil {
+			return err
+		}
+		if parser.ContainsVars(typedOffset) {
+			return errors.Errorf("argument of %s must not contain variables", name)
+		}
+		*b.dst = typedOffset
+	}
+	return nil
+}
+
+// evalFrameOffsets evaluates the offsets of the bounds of a window function
+// application's frame, if any.
+func (w *windowFuncHolder) evalFrameOffsets(
+	evalCtx *parser.EvalContext,
+) (startOffset, endOffset parser.Datum, err error) {
+	offsets := []struct {
+		name string
+		expr parser.TypedExpr
+		dst  *parser.Datum
+	}{
+		{"starting", w.frameStartOffset, &startOffset},
+		{"ending", w.frameEndOffset, &endOffset},
+	}
+	for _, o := range offsets {
+		if o.expr == nil {
+			continue
+		}
+		d, err := o.expr.Eval(evalCtx)
+		if err != nil {
+			return nil, nil, err
+		}
+		if d == parser.DNull {
+			return nil, nil, errors.Errorf("frame %s offset must not be null", o.name)
+		}
+		var zero parser.Datum
+		switch d.(type) {
+		case *parser.DInt:
+			zero = parser.NewDInt(0)
+		case *parser.DFloat:
+			zero = parser.NewDFloat(0)
+		case *parser.DDecimal:
+			zero = &parser.DDecimal{}
+		case *parser.DInterval:
+			zero = &parser.DInterval{}
+		}
+		if zero != nil && d.Compare(evalCtx, zero) < 0 {
+			return nil, nil, errors.Errorf("frame %s offset must not be negative", o.name)
+		}
+		*o.dst = d
+	}
+	return startOffset, endOffset, nil
+}
+
 // constructWindowDef constructs a WindowDef using the provided WindowDef value and the
 // set of named window specifications on the current SELECT clause. If the provided
 // WindowDef does not reference a named window spec, then it will simply be returned without
@@ -246,6 +358,12 @@ func constructWindowDef(
 		return *referencedSpec, nil
 	}
 
+	// referencedSpec.Frame is never used: the frame of a window can only be
+	// specified when it is not copied.
+	if referencedSpec.Frame != nil {
+		return def, errors.Errorf("cannot copy window %q because it has a frame clause", refName)
+	}
+
 	// referencedSpec.Partitions is always used.
 	if len(def.Partitions) > 0 {
 		return def, errors.Errorf("cannot override PARTITION BY clause of window %q", refName)
@@ -643,19 +761,29 @@ func (n *windowNode) computeWindows(ctx context.Context) error {
 		//   * Removable Cumulative
 		//   * Segment Tree
 		// See Leis et al. [http://www.vldb.org/pvldb/vol8/p1058-leis.pdf]
+		startOffset, endOffset, err := windowFn.evalFrameOffsets(&n.planner.evalCtx)
+		if err != nil {
+			return err
+		}
+		// RANGE frames with offsets need the values of the ORDER BY column to
+		// find the bounds of each row's frame.
+		frame := windowFn.windowDef.Frame
+		needOrdVals := frame != nil && frame.Mode == parser.RangeFrame &&
+			(startOffset != nil || endOffset != nil)
+
 		for _, partition := range partitions {
-			// TODO(nvanbenschoten): Handle framing here. Right now we only handle the default
-			// framing option of RANGE UNBOUNDED PRECEDING. With ORDER BY, this sets the frame
-			// to be all rows from the partition start up through the current row's last ORDER BY
-			// peer. Without ORDER BY, all rows of the partition are included in the window frame,
-			// since all rows become peers of the current row. Once we add better framing support,
-			// we should flesh this logic out more.
+			// Without a frame clause, the frame is the default RANGE UNBOUNDED
+			// PRECEDING. With ORDER BY, this sets the frame to be all rows from the
+			// partition start up through the current row's last ORDER BY peer. Without
+			// ORDER BY, all rows of the partition are included in the window frame,
+			// since all rows become peers of the current row. The frames of the other
+			// rows are computed by the WindowFrameRun from the peer groups and, for
+			// RANGE frames with offsets, the values of the ORDER BY column.
 			builtin := windowFn.expr.GetWindowConstructor()(&n.planner.evalCtx)
 			defer builtin.Close(ctx, &n.planner.evalCtx)
 
-			// Since we only support two types of window frames (see TODO above), we only
-			// need two possible types of peerGroupChecker's to help determine peer groups
-			// for given tuples.
+			// Peer groups are either determined by the ORDER BY clause or, without
+			// one, consist of the whole partition.
 			var peerGrouper peerGroupChecker
 			if windowFn.columnOrdering != nil {
 				// If an ORDER BY clause is provided, order the partition and use the
@@ -680,26 +808,43 @@ func (n *windowNode) computeWindows(ctx context.Context) error {
 			}
 
 			// Iterate over peer groups within partition using a window frame.
-			frame := parser.WindowFrame{
-				Rows:        partition,
-				ArgIdxStart: windowFn.argIdxStart,
-				ArgCount:    windowFn.argCount,
-				RowIdx:      0,
+			frameRun := parser.WindowFrameRun{
+				Rows:             partition,
+				ArgIdxStart:      windowFn.argIdxStart,
+				ArgCount:         windowFn.argCount,
+				Frame:            frame,
+				StartBoundOffset: startOffset,
+				EndBoundOffset:   endOffset,
+				PlusOp:           windowFn.framePlusOp,
+				MinusOp:          windowFn.frameMinusOp,
+				RowIdx:           0,
 			}
-			for frame.RowIdx < len(partition) {
+			if needOrdVals {
+				sz := int64(uintptr(len(partition)) * unsafe.Sizeof(parser.Datum(nil)))
+				if err := acc.Grow(ctx, sz); err != nil {
+					return err
+				}
+				ordering := windowFn.columnOrdering[0]
+				frameRun.OrdVals = make(parser.Datums, len(partition))
+				for i := range partition {
+					frameRun.OrdVals[i] = n.wrappedRenderVals.At(partition[i].Idx)[ordering.ColIdx]
+				}
+				frameRun.OrdDescending = ordering.Direction == encoding.Descending
+			}
+			for frameRun.RowIdx < len(partition) {
 				// Compute the size of the current peer group.
-				frame.FirstPeerIdx = frame.RowIdx
-				frame.PeerRowCount = 1
-				for ; frame.FirstPeerIdx+frame.PeerRowCount < len(partition); frame.PeerRowCount++ {
-					cur := frame.FirstPeerIdx + frame.PeerRowCount
+				frameRun.FirstPeerIdx = frameRun.RowIdx
+				frameRun.PeerRowCount = 1
+				for ; frameRun.FirstPeerIdx+frameRun.PeerRowCount < len(partition); frameRun.PeerRowCount++ {
+					cur := frameRun.FirstPeerIdx + frameRun.PeerRowCount
 					if !peerGrouper.InSameGroup(cur, cur-1) {
 						break
 					}
 				}
 
 				// Perform calculations on each row in the current peer group.
-				for ; frame.RowIdx < frame.FirstPeerIdx+frame.PeerRowCount; frame.RowIdx++ {
-					res, err := builtin.Compute(ctx, &n.planner.evalCtx, frame)
+				for ; frameRun.RowIdx < frameRun.FirstPeerIdx+frameRun.PeerRowCount; frameRun.RowIdx++ {
+					res, err := builtin.Compute(ctx, &n.planner.evalCtx, frameRun)
 					if err != nil {
 						return err
 					}
@@ -711,7 +856,7 @@ func (n *windowNode) computeWindows(ctx context.Context) error {
 					}
 
 					// Save result into n.windowValues, indexed by original row index.
-					valRowIdx := partition[frame.RowIdx].Idx
+					valRowIdx := partition[frameRun.RowIdx].Idx
 					n.windowValues[valRowIdx][windowIdx] = res
 				}
 			}
@@ -903,6 +1048,14 @@ type windowFuncHolder struct {
 	windowDef      parser.WindowDef
 	partitionIdxs  []int
 	columnOrdering sqlbase.ColumnOrdering
+
+	// frameStartOffset and frameEndOffset are the offsets of the bounds of the
+	// window definition's frame, if any. framePlusOp and frameMinusOp compute
+	// the bounds of RANGE frames with offsets.
+	frameStartOffset parser.TypedExpr
+	frameEndOffset   parser.TypedExpr
+	framePlusOp      parser.BinOp
+	frameMinusOp     parser.BinOp
 }
 
 func (*windowFuncHolder) Variable() {}
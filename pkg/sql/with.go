@@ -0,0 +1,248 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// cteSource is a common table expression (CTE) that is visible by
+// name to the statement being planned.
+type cteSource struct {
+	cte *parser.CTE
+
+	// envIdx is the number of CTEs that were already in scope when
+	// this CTE was defined. Only those are visible from the CTE's own
+	// query: a CTE cannot refer to CTEs defined after it, and only a
+	// CTE defined by WITH RECURSIVE can refer to itself.
+	envIdx int
+
+	// recursive is set if the CTE was defined by WITH RECURSIVE.
+	recursive bool
+
+	// The following fields are only set on the entries that a recursive
+	// CTE's own query sees for its name. When workTable is set, references
+	// resolve to the rows produced by the previous iteration of the
+	// recursive CTE. When selfRefErr is set, references are invalid and
+	// report that error.
+	workTable  *recursiveCTENode
+	selfRefErr error
+}
+
+// cteNameEnvironment is the list of CTEs currently in scope, from
+// outermost to innermost. Lookups start from the end so that inner
+// definitions shadow outer ones with the same name.
+type cteNameEnvironment []cteSource
+
+// lookup returns the innermost CTE with the given name, if any.
+func (e cteNameEnvironment) lookup(name parser.Name) (cteSource, bool) {
+	normalized := name.Normalize()
+	for i := len(e) - 1; i >= 0; i-- {
+		if e[i].cte.Name.Alias.Normalize() == normalized {
+			return e[i], true
+		}
+	}
+	return cteSource{}, false
+}
+
+// initWith adds the CTEs defined by the given WITH clause to the
+// planner's name environment, so that they can be referred to like
+// tables in the statement that follows. The returned function restores
+// the previous environment; it must be called once the statement the
+// WITH clause belongs to has been planned.
+func (p *planner) initWith(ctx context.Context, with *parser.With) (func(), error) {
+	prev := p.ctes
+	if with == nil {
+		return func() {}, nil
+	}
+
+	// Ensure that appending below does not clobber the environment of an
+	// enclosing statement which may share the same backing array.
+	env := prev[:len(prev):len(prev)]
+	seen := make(map[parser.Name]struct{}, len(with.CTEList))
+	for _, cte := range with.CTEList {
+		name := parser.Name(cte.Name.Alias.Normalize())
+		if _, ok := seen[name]; ok {
+			return nil, pgerror.NewErrorf(pgerror.CodeDuplicateAliasError,
+				"WITH query name %q specified more than once", parser.ErrString(cte.Name.Alias))
+		}
+		seen[name] = struct{}{}
+
+		if _, ok := cte.Stmt.(*parser.Select); !ok {
+			return nil, pgerror.Unimplemented("with_dml",
+				"data-modifying statements in WITH are not supported")
+		}
+		env = append(env, cteSource{cte: cte, envIdx: len(env), recursive: with.Recursive})
+	}
+	p.ctes = env
+	return func() { p.ctes = prev }, nil
+}
+
+// getCTEDataSource attempts to find a CTE with the given name among
+// the CTEs currently in scope, and if found plans the CTE's query as
+// a data source.
+func (p *planner) getCTEDataSource(
+	ctx context.Context, t *parser.NormalizableTableName,
+) (planDataSource, bool, error) {
+	if len(p.ctes) == 0 {
+		return planDataSource{}, false, nil
+	}
+	tn, err := t.Normalize()
+	if err != nil {
+		return planDataSource{}, false, err
+	}
+	if !tn.DBNameOriginallyOmitted {
+		// CTE names are never qualified.
+		return planDataSource{}, false, nil
+	}
+	src, ok := p.ctes.lookup(tn.TableName)
+	if !ok {
+		return planDataSource{}, false, nil
+	}
+
+	var plan planNode
+	switch {
+	case src.selfRefErr != nil:
+		return planDataSource{}, false, src.selfRefErr
+
+	case src.workTable != nil:
+		plan, err = src.workTable.newWorkTableScan()
+
+	case src.recursive:
+		plan, err = p.getRecursiveCTEPlan(ctx, src)
+
+	default:
+		// The CTE's query is planned in the environment where it was
+		// defined.
+		defer func(prev cteNameEnvironment) { p.ctes = prev }(p.ctes)
+		p.ctes = p.ctes[:src.envIdx]
+		plan, err = p.newPlan(ctx, src.cte.Stmt, nil)
+	}
+	if err != nil {
+		return planDataSource{}, false, err
+	}
+	ds := planDataSource{
+		info: newSourceInfoForSingleTable(anonymousTable, planColumns(plan)),
+		plan: plan,
+	}
+	ds, err = renameSource(ds, src.cte.Name, false)
+	return ds, true, err
+}
+
+// getRecursiveCTEPlan plans the query of a CTE defined by WITH
+// RECURSIVE. If the query has the form
+//
+//   non-recursive-term UNION [ALL] recursive-term
+//
+// and the recursive term refers to the CTE, the result is a
+// recursiveCTENode. Otherwise the CTE is not actually recursive and its
+// query is planned like that of a regular CTE.
+func (p *planner) getRecursiveCTEPlan(ctx context.Context, src cteSource) (planNode, error) {
+	defer func(prev cteNameEnvironment) { p.ctes = prev }(p.ctes)
+	env := p.ctes[:src.envIdx:src.envIdx]
+	name := parser.ErrString(src.cte.Name.Alias)
+
+	union := recursiveUnion(src.cte.Stmt)
+	if union == nil {
+		p.ctes = append(env, cteSource{
+			cte:    src.cte,
+			envIdx: src.envIdx,
+			selfRefErr: pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+				"recursive query %q does not have the form "+
+					"non-recursive-term UNION [ALL] recursive-term", name),
+		})
+		return p.newPlan(ctx, src.cte.Stmt, nil)
+	}
+
+	p.ctes = append(env, cteSource{
+		cte:    src.cte,
+		envIdx: src.envIdx,
+		selfRefErr: pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+			"recursive reference to query %q must not appear within its non-recursive term", name),
+	})
+	initial, err := p.newPlan(ctx, union.Left, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &recursiveCTENode{
+		p:         p,
+		name:      name,
+		columns:   append(sqlbase.ResultColumns(nil), planColumns(initial)...),
+		initial:   initial,
+		recursive: union.Right,
+		unionAll:  union.All,
+	}
+	n.env = append(env, cteSource{cte: src.cte, envIdx: src.envIdx, workTable: n})
+
+	// Plan the recursive term once to check that it refers to the CTE and
+	// that its columns are compatible with those of the non-recursive
+	// term. It is planned again for every iteration during execution.
+	p.ctes = n.env
+	rec, err := p.newPlan(ctx, union.Right, n.desiredTypes())
+	if err != nil {
+		initial.Close(ctx)
+		return nil, err
+	}
+	recColumns := planColumns(rec)
+	rec.Close(ctx)
+	if n.workTableRefs == 0 {
+		// The CTE does not refer to itself after all.
+		initial.Close(ctx)
+		p.ctes = env
+		return p.newPlan(ctx, src.cte.Stmt, nil)
+	}
+	if err := n.mergeColumnTypes(recColumns); err != nil {
+		initial.Close(ctx)
+		return nil, err
+	}
+	return n, nil
+}
+
+// recursiveUnion returns the UNION clause of the query of a recursive CTE,
+// or nil if the query does not have the form
+// non-recursive-term UNION [ALL] recursive-term.
+func recursiveUnion(stmt parser.Statement) *parser.UnionClause {
+	sel, ok := stmt.(*parser.Select)
+	if !ok || sel.With != nil || sel.OrderBy != nil || sel.Limit != nil {
+		return nil
+	}
+	union, ok := sel.Select.(*parser.UnionClause)
+	if !ok || union.Type != parser.UnionOp {
+		return nil
+	}
+	return union
+}
+
+// checkEditTargetNotShadowed verifies that the target table of an
+// UPDATE or DELETE statement is not shadowed by a CTE in scope. The
+// rows to modify are read through the regular data source resolution,
+// which would otherwise read them from the CTE instead of the table.
+func (p *planner) checkEditTargetNotShadowed(tn *parser.TableName) error {
+	if !tn.DBNameOriginallyOmitted {
+		return nil
+	}
+	if _, ok := p.ctes.lookup(tn.TableName); ok {
+		return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"WITH query name %q cannot be used as the target of a data-modifying statement; "+
+				"qualify the table name with its database instead",
+			parser.ErrString(tn.TableName))
+	}
+	return nil
+}
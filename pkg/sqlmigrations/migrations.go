@@ -89,6 +89,18 @@ var backwardCompatibleMigrations = []migrationDescriptor{
 		name:   "persist trace.debug.enable = 'false'",
 		workFn: disableNetTrace,
 	},
+	{
+		name:           "create system.table_statistics table",
+		workFn:         createTableStatisticsTable,
+		newDescriptors: 1,
+		newRanges:      1,
+	},
+	{
+		name:           "add system.users isRole column and create system.role_members",
+		workFn:         addRoles,
+		newDescriptors: 1,
+		newRanges:      1,
+	},
 }
 
 // migrationDescriptor describes a single migration hook that's used to modify
@@ -366,6 +378,21 @@ func createWebSessionsTable(ctx context.Context, r runner) error {
 	return createSystemTable(ctx, r, sqlbase.WebSessionsTable)
 }
 
+func createTableStatisticsTable(ctx context.Context, r runner) error {
+	return createSystemTable(ctx, r, sqlbase.TableStatisticsTable)
+}
+
+func addRoles(ctx context.Context, r runner) error {
+	// The users table is already present in fresh clusters, so its new column
+	// is added through a schema change rather than at the KV layer.
+	const alterStmt = `ALTER TABLE system.users ADD COLUMN IF NOT EXISTS "isRole" BOOL NOT NULL ` +
+		`DEFAULT false CREATE IF NOT EXISTS FAMILY "fam_3_isRole"`
+	if err := runStmtAsRootWithRetry(ctx, r, alterStmt); err != nil {
+		return err
+	}
+	return createSystemTable(ctx, r, sqlbase.RoleMembersTable)
+}
+
 func createSystemTable(ctx context.Context, r runner, desc sqlbase.TableDescriptor) error {
 	// We install the table at the KV layer so that we can choose a known ID in
 	// the reserved ID space. (The SQL layer doesn't allow this.)
@@ -181,15 +181,22 @@ type RangeInfo struct {
 	Desc            *roachpb.RangeDescriptor
 	LogicalBytes    int64
 	WritesPerSecond float64
+	// QueriesPerSecond is the QPS served by the leaseholder of the range, or 0
+	// if it isn't known.
+	QueriesPerSecond float64
 }
 
 func rangeInfoForRepl(repl *Replica, desc *roachpb.RangeDescriptor) RangeInfo {
 	writesPerSecond, _ := repl.writeStats.avgQPS()
-	return RangeInfo{
+	info := RangeInfo{
 		Desc:            desc,
 		LogicalBytes:    repl.GetMVCCStats().Total(),
 		WritesPerSecond: writesPerSecond,
 	}
+	if qps, ok := repl.loadBasedQPS(); ok {
+		info.QueriesPerSecond = qps
+	}
+	return info
 }
 
 // Allocator tries to spread replicas as evenly as possible across the stores
@@ -506,9 +513,11 @@ func (a Allocator) RebalanceTarget(
 // TransferLeaseTarget returns a suitable replica to transfer the range lease
 // to from the provided list. It excludes the current lease holder replica
 // unless asked to do otherwise by the checkTransferLeaseSource parameter.
+// If the zone has lease preferences, only the replicas satisfying the most
+// preferred of them are considered.
 func (a *Allocator) TransferLeaseTarget(
 	ctx context.Context,
-	constraints config.Constraints,
+	zone config.ZoneConfig,
 	existing []roachpb.ReplicaDescriptor,
 	leaseStoreID roachpb.StoreID,
 	rangeID roachpb.RangeID,
@@ -518,7 +527,7 @@ func (a *Allocator) TransferLeaseTarget(
 	alwaysAllowDecisionWithoutStats bool,
 ) roachpb.ReplicaDescriptor {
 	sl, _, _ := a.storePool.getStoreList(rangeID, storeFilterNone)
-	sl = sl.filter(constraints)
+	sl = sl.filter(zone.Constraints)
 
 	// Filter stores that are on nodes containing existing replicas, but leave
 	// the stores containing the existing replicas in place. This excludes stores
@@ -548,6 +557,31 @@ func (a *Allocator) TransferLeaseTarget(
 		return roachpb.ReplicaDescriptor{}
 	}
 
+	// If the lease holder doesn't satisfy the lease preferences while other
+	// replicas do, the lease is transferred to one of them no matter how many
+	// leases their stores hold.
+	var mustTransfer bool
+	if preferred := a.preferredLeaseholders(zone, sl, existing); len(preferred) > 0 {
+		mustTransfer = !storeHasReplica(leaseStoreID, preferred)
+		if len(preferred) == 1 {
+			if mustTransfer {
+				return preferred[0]
+			}
+			return roachpb.ReplicaDescriptor{}
+		}
+		existing = preferred
+		if mustTransfer {
+			checkTransferLeaseSource = false
+		}
+	}
+
+	// If the lease holder's store is overfull on QPS, transfer the lease to
+	// even out the QPS of the stores.
+	rangeQPS := a.rangeQPS(stats)
+	if repl := a.qpsLeaseTarget(ctx, sl, source, existing, rangeQPS); repl != (roachpb.ReplicaDescriptor{}) {
+		return repl
+	}
+
 	// Try to pick a replica to transfer the lease to while also determining
 	// whether we actually should be transferring the lease. The transfer
 	// decision is only needed if we've been asked to check the source.
@@ -571,13 +605,20 @@ func (a *Allocator) TransferLeaseTarget(
 		}
 	}
 
+	// Leases are never transferred to stores which they would make overfull on
+	// QPS, or they could be moved straight back by qpsLeaseTarget.
 	if repl != (roachpb.ReplicaDescriptor{}) {
-		return repl
+		if storeDesc, ok := a.storePool.getStoreDescriptor(repl.StoreID); ok &&
+			!qpsOverfullAfterTransfer(a.storePool.st, sl, storeDesc.Capacity, rangeQPS) {
+			return repl
+		}
 	}
 
 	// Fall back to logic that doesn't take request counts and latency into
 	// account if the counts/latency-based logic couldn't pick a best replica.
 	candidates := make([]roachpb.ReplicaDescriptor, 0, len(existing))
+	var bestOption roachpb.ReplicaDescriptor
+	bestOptionLeaseCount := int32(math.MaxInt32)
 	for _, repl := range existing {
 		if leaseStoreID == repl.StoreID {
 			continue
@@ -586,11 +627,18 @@ func (a *Allocator) TransferLeaseTarget(
 		if !ok {
 			continue
 		}
-		if !checkCandidateFullness || float64(storeDesc.Capacity.LeaseCount) < sl.candidateLeases.mean-0.5 {
+		if !qpsOverfullAfterTransfer(a.storePool.st, sl, storeDesc.Capacity, rangeQPS) &&
+			(!checkCandidateFullness || float64(storeDesc.Capacity.LeaseCount) < sl.candidateLeases.mean-0.5) {
 			candidates = append(candidates, repl)
+		} else if storeDesc.Capacity.LeaseCount < bestOptionLeaseCount {
+			bestOption = repl
+			bestOptionLeaseCount = storeDesc.Capacity.LeaseCount
 		}
 	}
 	if len(candidates) == 0 {
+		if mustTransfer {
+			return bestOption
+		}
 		return roachpb.ReplicaDescriptor{}
 	}
 	a.randGen.Lock()
@@ -598,12 +646,13 @@ func (a *Allocator) TransferLeaseTarget(
 	return candidates[a.randGen.Intn(len(candidates))]
 }
 
-// ShouldTransferLease returns true if the specified store is overfull in terms
-// of leases with respect to the other stores matching the specified
-// attributes.
+// ShouldTransferLease returns true if the specified store doesn't satisfy the
+// lease preferences of the zone while another replica does, or if it is
+// overfull in terms of QPS or leases with respect to the other stores
+// matching the specified attributes.
 func (a *Allocator) ShouldTransferLease(
 	ctx context.Context,
-	constraints config.Constraints,
+	zone config.ZoneConfig,
 	existing []roachpb.ReplicaDescriptor,
 	leaseStoreID roachpb.StoreID,
 	rangeID roachpb.RangeID,
@@ -614,9 +663,31 @@ func (a *Allocator) ShouldTransferLease(
 		return false
 	}
 	sl, _, _ := a.storePool.getStoreList(rangeID, storeFilterNone)
-	sl = sl.filter(constraints)
+	sl = sl.filter(zone.Constraints)
 	log.VEventf(ctx, 3, "ShouldTransferLease (lease-holder=%d):\n%s", leaseStoreID, sl)
 
+	// Only the replicas satisfying the most preferred lease preference are
+	// considered. If only one replica does, it's where the lease should be.
+	if preferred := a.preferredLeaseholders(zone, sl, existing); len(preferred) > 0 {
+		if !storeHasReplica(leaseStoreID, preferred) {
+			log.VEventf(ctx, 3, "ShouldTransferLease decision (lease-holder=%d): "+
+				"lease-holder does not satisfy the lease preferences", leaseStoreID)
+			return true
+		}
+		if len(preferred) == 1 {
+			return false
+		}
+		existing = preferred
+	}
+
+	if repl := a.qpsLeaseTarget(
+		ctx, sl, source, existing, a.rangeQPS(stats),
+	); repl != (roachpb.ReplicaDescriptor{}) {
+		log.VEventf(ctx, 3, "ShouldTransferLease decision (lease-holder=%d): "+
+			"lease-holder is overfull on QPS", leaseStoreID)
+		return true
+	}
+
 	transferDec, _ := a.shouldTransferLeaseUsingStats(ctx, sl, source, existing, stats)
 	var result bool
 	switch transferDec {
@@ -634,6 +705,124 @@ func (a *Allocator) ShouldTransferLease(
 	return result
 }
 
+// preferredLeaseholders returns the replicas on live stores which satisfy the
+// first lease preference of the zone satisfied by any such replica. Lease
+// preferences are ordered from most to least preferred, so the later ones are
+// only considered if no replica satisfies the earlier ones. It returns nil if
+// the zone has no lease preferences or if no replica satisfies any of them.
+func (a Allocator) preferredLeaseholders(
+	zone config.ZoneConfig, sl StoreList, existing []roachpb.ReplicaDescriptor,
+) []roachpb.ReplicaDescriptor {
+	for _, preference := range zone.LeasePreferences {
+		constraints := config.Constraints{Constraints: preference.Constraints}
+		var preferred []roachpb.ReplicaDescriptor
+		for _, repl := range existing {
+			for _, store := range sl.stores {
+				if store.StoreID != repl.StoreID {
+					continue
+				}
+				if ok, _ := constraintCheck(store, constraints); ok {
+					preferred = append(preferred, repl)
+				}
+				break
+			}
+		}
+		if len(preferred) > 0 {
+			return preferred
+		}
+	}
+	return nil
+}
+
+// rangeQPS returns the QPS of the range with the given stats as used for
+// QPS-based rebalancing, or 0 if QPS-based rebalancing is disabled or the QPS
+// hasn't been measured for long enough.
+func (a Allocator) rangeQPS(stats *replicaStats) float64 {
+	if stats == nil || !qpsBasedRebalancingEnabled(a.storePool.st) {
+		return 0
+	}
+	qps, dur := stats.avgQPS()
+	if dur < MinStatsDuration {
+		return 0
+	}
+	return qps
+}
+
+// qpsLeaseTarget returns the replica to transfer the lease of a range with the
+// given QPS to if the lease holder's store is overfull on QPS. The target is
+// the replica on the store with the lowest QPS, as long as the transfer
+// wouldn't make that store overfull on QPS, or busier than the lease holder's
+// store would be after the transfer. It returns an empty descriptor if no
+// transfer is needed or if no replica is suitable.
+func (a Allocator) qpsLeaseTarget(
+	ctx context.Context,
+	sl StoreList,
+	source roachpb.StoreDescriptor,
+	existing []roachpb.ReplicaDescriptor,
+	rangeQPS float64,
+) roachpb.ReplicaDescriptor {
+	if rangeQPS == 0 {
+		return roachpb.ReplicaDescriptor{}
+	}
+	st := a.storePool.st
+	if source.Capacity.QueriesPerSecond <= overfullQPSThreshold(st, sl.candidateQueriesPerSecond.mean) {
+		return roachpb.ReplicaDescriptor{}
+	}
+	var target roachpb.ReplicaDescriptor
+	targetQPS := math.MaxFloat64
+	for _, repl := range existing {
+		if repl.StoreID == source.StoreID {
+			continue
+		}
+		storeDesc, ok := a.storePool.getStoreDescriptor(repl.StoreID)
+		if !ok || qpsOverfullAfterTransfer(st, sl, storeDesc.Capacity, rangeQPS) {
+			continue
+		}
+		if storeDesc.Capacity.QueriesPerSecond+rangeQPS >= source.Capacity.QueriesPerSecond-rangeQPS {
+			continue
+		}
+		if storeDesc.Capacity.QueriesPerSecond < targetQPS {
+			target = repl
+			targetQPS = storeDesc.Capacity.QueriesPerSecond
+		}
+	}
+	if target != (roachpb.ReplicaDescriptor{}) {
+		log.VEventf(ctx, 3, "QPS-based lease transfer from s%d (qps=%.2f) to s%d (qps=%.2f), "+
+			"range-qps=%.2f, mean=%.2f", source.StoreID, source.Capacity.QueriesPerSecond,
+			target.StoreID, targetQPS, rangeQPS, sl.candidateQueriesPerSecond.mean)
+	}
+	return target
+}
+
+// storeOverfullOnQPS returns whether QPS-based rebalancing is enabled and the
+// given store is overfull on QPS with respect to the other stores matching
+// the constraints.
+func (a Allocator) storeOverfullOnQPS(
+	constraints config.Constraints, storeID roachpb.StoreID, rangeID roachpb.RangeID,
+) bool {
+	if !qpsBasedRebalancingEnabled(a.storePool.st) {
+		return false
+	}
+	store, ok := a.storePool.getStoreDescriptor(storeID)
+	if !ok {
+		return false
+	}
+	sl, _, _ := a.storePool.getStoreList(rangeID, storeFilterNone)
+	sl = sl.filter(constraints)
+	return store.Capacity.QueriesPerSecond >
+		overfullQPSThreshold(a.storePool.st, sl.candidateQueriesPerSecond.mean)
+}
+
+// storeHasReplica returns whether one of the replicas is on the given store.
+func storeHasReplica(storeID roachpb.StoreID, replicas []roachpb.ReplicaDescriptor) bool {
+	for _, repl := range replicas {
+		if repl.StoreID == storeID {
+			return true
+		}
+	}
+	return false
+}
+
 func (a Allocator) shouldTransferLeaseUsingStats(
 	ctx context.Context,
 	sl StoreList,
@@ -77,23 +77,48 @@ var statRebalanceThreshold = settings.RegisterNonNegativeFloatSetting(
 	0.20,
 )
 
+// EnableQPSBasedRebalancing controls whether range leases and replicas are
+// rebalanced to even out the number of requests served per second by each
+// store, in addition to the other factors.
+var EnableQPSBasedRebalancing = settings.RegisterBoolSetting(
+	"kv.allocator.qps_based_rebalancing.enabled",
+	"set to enable rebalancing of range leases and replicas based on the QPS of stores",
+	false,
+)
+
+// qpsRebalanceThreshold is the same as statRebalanceThreshold, but for the
+// QPS of stores. Leases and replicas are only moved off stores whose QPS is
+// above the mean by more than this fraction, and never onto stores which
+// they would push above it, which keeps hot ranges from thrashing between
+// stores as their load fluctuates.
+var qpsRebalanceThreshold = settings.RegisterNonNegativeFloatSetting(
+	"kv.allocator.qps_rebalance_threshold",
+	"minimum fraction away from the mean a store's QPS can be before it is considered overfull or underfull",
+	0.25,
+)
+
 func statsBasedRebalancingEnabled(st *cluster.Settings) bool {
 	return EnableStatsBasedRebalancing.Get(&st.SV) && st.Version.IsActive(cluster.VersionStatsBasedRebalancing)
 }
 
+func qpsBasedRebalancingEnabled(st *cluster.Settings) bool {
+	return EnableQPSBasedRebalancing.Get(&st.SV) && st.Version.IsActive(cluster.VersionQPSBasedRebalancing)
+}
+
 type balanceDimensions struct {
-	ranges rangeCountStatus
-	bytes  float64
-	writes float64
+	ranges  rangeCountStatus
+	bytes   float64
+	writes  float64
+	queries float64
 }
 
 func (bd *balanceDimensions) totalScore() float64 {
-	return float64(bd.ranges) + bd.bytes + bd.writes
+	return float64(bd.ranges) + bd.bytes + bd.writes + bd.queries
 }
 
 func (bd balanceDimensions) String() string {
-	return fmt.Sprintf("%.2f(ranges=%d, bytes=%.2f, writes=%.2f)",
-		bd.totalScore(), int(bd.ranges), bd.bytes, bd.writes)
+	return fmt.Sprintf("%.2f(ranges=%d, bytes=%.2f, writes=%.2f, queries=%.2f)",
+		bd.totalScore(), int(bd.ranges), bd.bytes, bd.writes, bd.queries)
 }
 
 // candidate store for allocation.
@@ -548,6 +573,10 @@ func shouldRebalance(
 		return true
 	}
 
+	if qpsBasedRebalancingEnabled(st) && shouldRebalanceQPS(ctx, st, store, sl, rangeInfo) {
+		return true
+	}
+
 	if !statsBasedRebalancingEnabled(st) {
 		return shouldRebalanceNoStats(ctx, st, store, sl)
 	}
@@ -633,6 +662,42 @@ func shouldRebalanceNoStats(
 	return false
 }
 
+// shouldRebalanceQPS implements the decision of whether to rebalance based on
+// the QPS of the stores. The range is rebalanced away from a store which is
+// overfull on QPS if another store can take it without becoming overfull
+// itself.
+func shouldRebalanceQPS(
+	ctx context.Context,
+	st *cluster.Settings,
+	store roachpb.StoreDescriptor,
+	sl StoreList,
+	rangeInfo RangeInfo,
+) bool {
+	if rangeInfo.QueriesPerSecond == 0 {
+		return false
+	}
+	mean := sl.candidateQueriesPerSecond.mean
+	overfullThreshold := overfullQPSThreshold(st, mean)
+	if store.Capacity.QueriesPerSecond <= overfullThreshold {
+		return false
+	}
+	for _, desc := range sl.stores {
+		if !preexistingReplicaCheck(desc.Node.NodeID, rangeInfo.Desc.Replicas) || !maxCapacityCheck(desc) {
+			continue
+		}
+		if qpsOverfullAfterTransfer(st, sl, desc.Capacity, rangeInfo.QueriesPerSecond) {
+			continue
+		}
+		log.VEventf(ctx, 2,
+			"s%d: should-rebalance(qps-overfull, better-fit=s%d): qps=%.2f, otherQPS=%.2f, rangeQPS=%.2f, "+
+				"mean=%.2f, overfull-threshold=%.2f",
+			store.StoreID, desc.StoreID, store.Capacity.QueriesPerSecond, desc.Capacity.QueriesPerSecond,
+			rangeInfo.QueriesPerSecond, mean, overfullThreshold)
+		return true
+	}
+	return false
+}
+
 // preexistingReplicaCheck returns true if no existing replica is present on
 // the candidate's node.
 func preexistingReplicaCheck(nodeID roachpb.NodeID, existing []roachpb.ReplicaDescriptor) bool {
@@ -788,6 +853,16 @@ func balanceScore(
 			sc.WritesPerReplica,
 			rangeInfo.WritesPerSecond)
 	}
+	if qpsBasedRebalancingEnabled(st) && rangeInfo.QueriesPerSecond > 0 {
+		// Unlike writes, which are applied by all the replicas of a range,
+		// queries are only served by the leaseholder, so only stores which are
+		// far from the mean take the QPS of the range into account.
+		if sc.QueriesPerSecond > overfullQPSThreshold(st, sl.candidateQueriesPerSecond.mean) {
+			dimensions.queries = -1
+		} else if sc.QueriesPerSecond < underfullQPSThreshold(st, sl.candidateQueriesPerSecond.mean) {
+			dimensions.queries = 1
+		}
+	}
 	return dimensions
 }
 
@@ -924,6 +999,23 @@ func underfullStatThreshold(st *cluster.Settings, mean float64) float64 {
 	return mean * (1 - statRebalanceThreshold.Get(&st.SV))
 }
 
+func overfullQPSThreshold(st *cluster.Settings, mean float64) float64 {
+	return mean * (1 + qpsRebalanceThreshold.Get(&st.SV))
+}
+
+func underfullQPSThreshold(st *cluster.Settings, mean float64) float64 {
+	return mean * (1 - qpsRebalanceThreshold.Get(&st.SV))
+}
+
+// qpsOverfullAfterTransfer returns whether the store would be overfull on QPS
+// after receiving the lease of a range with the given QPS.
+func qpsOverfullAfterTransfer(
+	st *cluster.Settings, sl StoreList, sc roachpb.StoreCapacity, rangeQPS float64,
+) bool {
+	return rangeQPS > 0 &&
+		sc.QueriesPerSecond+rangeQPS > overfullQPSThreshold(st, sl.candidateQueriesPerSecond.mean)
+}
+
 func rebalanceFromConvergesOnMean(
 	st *cluster.Settings, sl StoreList, sc roachpb.StoreCapacity, rangeInfo RangeInfo,
 ) bool {
@@ -933,7 +1025,8 @@ func rebalanceFromConvergesOnMean(
 		sc,
 		sc.RangeCount-1,
 		sc.LogicalBytes-rangeInfo.LogicalBytes,
-		sc.WritesPerSecond-rangeInfo.WritesPerSecond)
+		sc.WritesPerSecond-rangeInfo.WritesPerSecond,
+		sc.QueriesPerSecond-rangeInfo.QueriesPerSecond)
 }
 
 func rebalanceToConvergesOnMean(
@@ -945,7 +1038,8 @@ func rebalanceToConvergesOnMean(
 		sc,
 		sc.RangeCount+1,
 		sc.LogicalBytes+rangeInfo.LogicalBytes,
-		sc.WritesPerSecond+rangeInfo.WritesPerSecond)
+		sc.WritesPerSecond+rangeInfo.WritesPerSecond,
+		sc.QueriesPerSecond+rangeInfo.QueriesPerSecond)
 }
 
 func rebalanceConvergesOnMean(
@@ -955,7 +1049,25 @@ func rebalanceConvergesOnMean(
 	newRangeCount int32,
 	newLogicalBytes int64,
 	newWritesPerSecond float64,
+	newQueriesPerSecond float64,
 ) bool {
+	if qpsBasedRebalancingEnabled(st) && newQueriesPerSecond != sc.QueriesPerSecond {
+		mean := sl.candidateQueriesPerSecond.mean
+		// A range is never moved onto a store which it would make overfull on
+		// QPS, while moving it off a store which is overfull on QPS, or onto
+		// one which is underfull, always converges.
+		if newQueriesPerSecond > sc.QueriesPerSecond {
+			if newQueriesPerSecond > overfullQPSThreshold(st, mean) {
+				return false
+			}
+			if sc.QueriesPerSecond < underfullQPSThreshold(st, mean) {
+				return true
+			}
+		} else if sc.QueriesPerSecond > overfullQPSThreshold(st, mean) {
+			return true
+		}
+	}
+
 	if !statsBasedRebalancingEnabled(st) {
 		return convergesOnMean(float64(sc.RangeCount), float64(newRangeCount), sl.candidateRanges.mean)
 	}
@@ -1016,6 +1016,51 @@ func TestRebalanceConvergesOnMean(t *testing.T) {
 	}
 }
 
+func TestRebalanceConvergesOnMeanQPS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	st := cluster.MakeTestingClusterSettings()
+	EnableQPSBasedRebalancing.Override(&st.SV, true)
+
+	storeList := StoreList{
+		candidateRanges:           stat{mean: 1000},
+		candidateQueriesPerSecond: stat{mean: 1000},
+	}
+	emptyRange := RangeInfo{}
+	hotRange := RangeInfo{
+		QueriesPerSecond: 100,
+	}
+
+	testCases := []struct {
+		rangeCount       int32
+		queriesPerSecond float64
+		ri               RangeInfo
+		toConverges      bool
+		fromConverges    bool
+	}{
+		{999, 1000, emptyRange, true, false},
+		{1001, 2000, emptyRange, false, true},
+		{1000, 500, hotRange, true, false},
+		{1001, 500, hotRange, true, true},
+		{999, 1000, hotRange, true, false},
+		{999, 1200, hotRange, false, false},
+		{1000, 1500, hotRange, false, true},
+		{1001, 1500, hotRange, false, true},
+	}
+	for i, tc := range testCases {
+		sc := roachpb.StoreCapacity{
+			RangeCount:       tc.rangeCount,
+			QueriesPerSecond: tc.queriesPerSecond,
+		}
+		if a, e := rebalanceToConvergesOnMean(st, storeList, sc, tc.ri), tc.toConverges; a != e {
+			t.Errorf("%d: rebalanceToConvergesOnMean(storeList, %+v, %+v) got %t; want %t", i, sc, tc.ri, a, e)
+		}
+		if a, e := rebalanceFromConvergesOnMean(st, storeList, sc, tc.ri), tc.fromConverges; a != e {
+			t.Errorf("%d: rebalanceFromConvergesOnMean(storeList, %+v, %+v) got %t; want %t", i, sc, tc.ri, a, e)
+		}
+	}
+}
+
 func TestMaxCapacity(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
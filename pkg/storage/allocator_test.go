@@ -1018,7 +1018,7 @@ func TestAllocatorTransferLeaseTarget(t *testing.T) {
 		t.Run("", func(t *testing.T) {
 			target := a.TransferLeaseTarget(
 				context.Background(),
-				config.Constraints{},
+				config.ZoneConfig{},
 				c.existing,
 				c.leaseholder,
 				0,
@@ -1072,7 +1072,7 @@ func TestAllocatorTransferLeaseTargetMultiStore(t *testing.T) {
 		t.Run("", func(t *testing.T) {
 			target := a.TransferLeaseTarget(
 				context.Background(),
-				config.Constraints{},
+				config.ZoneConfig{},
 				existing,
 				c.leaseholder,
 				0,
@@ -1135,7 +1135,7 @@ func TestAllocatorShouldTransferLease(t *testing.T) {
 		t.Run("", func(t *testing.T) {
 			result := a.ShouldTransferLease(
 				context.Background(),
-				config.Constraints{},
+				config.ZoneConfig{},
 				c.existing,
 				c.leaseholder,
 				0,
@@ -1148,6 +1148,106 @@ func TestAllocatorShouldTransferLease(t *testing.T) {
 	}
 }
 
+func TestAllocatorLeasePreferences(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper, g, _, a, _ := createTestAllocator( /* deterministic */ true)
+	defer stopper.Stop(context.Background())
+
+	// 5 stores, 2 in the east and 3 in the west, with varying lease counts.
+	attrs := []string{"east", "east", "west", "west", "west"}
+	leaseCounts := []int32{10, 50, 30, 40, 60}
+	var stores []*roachpb.StoreDescriptor
+	for i := range attrs {
+		stores = append(stores, &roachpb.StoreDescriptor{
+			StoreID:  roachpb.StoreID(i + 1),
+			Node:     roachpb.NodeDescriptor{NodeID: roachpb.NodeID(i + 1)},
+			Attrs:    roachpb.Attributes{Attrs: []string{attrs[i]}},
+			Capacity: roachpb.StoreCapacity{LeaseCount: leaseCounts[i]},
+		})
+	}
+	sg := gossiputil.NewStoreGossiper(g)
+	sg.GossipStores(stores, t)
+
+	replicas := func(storeIDs ...roachpb.StoreID) []roachpb.ReplicaDescriptor {
+		var r []roachpb.ReplicaDescriptor
+		for _, storeID := range storeIDs {
+			r = append(r, roachpb.ReplicaDescriptor{
+				NodeID:  roachpb.NodeID(storeID),
+				StoreID: storeID,
+			})
+		}
+		return r
+	}
+	preferences := func(values ...string) []config.LeasePreference {
+		var p []config.LeasePreference
+		for _, value := range values {
+			p = append(p, config.LeasePreference{
+				Constraints: []config.Constraint{{Type: config.Constraint_REQUIRED, Value: value}},
+			})
+		}
+		return p
+	}
+
+	testCases := []struct {
+		leaseholder    roachpb.StoreID
+		existing       []roachpb.ReplicaDescriptor
+		preferences    []config.LeasePreference
+		expectedShould bool
+		expectedTarget roachpb.StoreID
+	}{
+		// The lease holder satisfies the preferences and isn't overfull.
+		{1, replicas(1, 2, 3), preferences("east"), false, 0},
+		// The lease holder doesn't satisfy the preferences: the lease is moved to
+		// the least loaded of the preferred replicas.
+		{3, replicas(1, 2, 3), preferences("east"), true, 1},
+		// The only preferred replica gets the lease, even if its store is
+		// overfull.
+		{3, replicas(2, 3), preferences("east"), true, 2},
+		{2, replicas(2, 3), preferences("east"), false, 0},
+		// Later preferences only apply if no replica satisfies earlier ones.
+		{1, replicas(1, 2, 3), preferences("west", "east"), true, 3},
+		{2, replicas(1, 2), preferences("west", "east"), true, 1},
+		{1, replicas(1, 3, 4), preferences("north", "west"), true, 3},
+		{1, replicas(1, 4), preferences("north", "west"), true, 4},
+		// No replica satisfies the preferences, which are ignored.
+		{1, replicas(1, 2, 3), preferences("north"), false, 0},
+		// All of the preferred replicas are on overfull stores: the lease is
+		// moved to the least loaded of them anyway.
+		{1, replicas(1, 4, 5), preferences("west"), true, 4},
+		{4, replicas(1, 4, 5), preferences("west"), false, 0},
+	}
+	for _, c := range testCases {
+		t.Run("", func(t *testing.T) {
+			zone := config.ZoneConfig{LeasePreferences: c.preferences}
+			result := a.ShouldTransferLease(
+				context.Background(),
+				zone,
+				c.existing,
+				c.leaseholder,
+				0,
+				nil, /* replicaStats */
+			)
+			if c.expectedShould != result {
+				t.Errorf("expected should transfer %v, but found %v", c.expectedShould, result)
+			}
+			target := a.TransferLeaseTarget(
+				context.Background(),
+				zone,
+				c.existing,
+				c.leaseholder,
+				0,
+				nil,   /* replicaStats */
+				true,  /* checkTransferLeaseSource */
+				true,  /* checkCandidateFullness */
+				false, /* alwaysAllowDecisionWithoutStats */
+			)
+			if c.expectedTarget != target.StoreID {
+				t.Errorf("expected target %d, but found %d", c.expectedTarget, target.StoreID)
+			}
+		})
+	}
+}
+
 // Test out the load-based lease transfer algorithm against a variety of
 // request distributions and inter-node latencies.
 func TestAllocatorTransferLeaseTargetLoadBased(t *testing.T) {
@@ -1301,7 +1401,7 @@ func TestAllocatorTransferLeaseTargetLoadBased(t *testing.T) {
 			})
 			target := a.TransferLeaseTarget(
 				context.Background(),
-				config.Constraints{},
+				config.ZoneConfig{},
 				existing,
 				c.leaseholder,
 				0,
@@ -1317,6 +1417,98 @@ func TestAllocatorTransferLeaseTargetLoadBased(t *testing.T) {
 	}
 }
 
+// TestAllocatorTransferLeaseTargetQPS verifies that the lease of a range is
+// transferred away from a store which is overfull on QPS, but never onto a
+// store which the transfer would make overfull on QPS.
+func TestAllocatorTransferLeaseTargetQPS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper, g, storePool, a, _ := createTestAllocator( /* deterministic */ true)
+	defer stopper.Stop(context.Background())
+
+	// 3 stores with the same number of leases, whose mean QPS is 600.
+	qps := []float64{1000, 500, 300}
+	var stores []*roachpb.StoreDescriptor
+	for i := 1; i <= 3; i++ {
+		stores = append(stores, &roachpb.StoreDescriptor{
+			StoreID: roachpb.StoreID(i),
+			Node:    roachpb.NodeDescriptor{NodeID: roachpb.NodeID(i)},
+			Capacity: roachpb.StoreCapacity{
+				LeaseCount:       10,
+				QueriesPerSecond: qps[i-1],
+			},
+		})
+	}
+	sg := gossiputil.NewStoreGossiper(g)
+	sg.GossipStores(stores, t)
+
+	existing := []roachpb.ReplicaDescriptor{
+		{NodeID: 1, StoreID: 1},
+		{NodeID: 2, StoreID: 2},
+		{NodeID: 3, StoreID: 3},
+	}
+
+	manual := hlc.NewManualClock(123)
+	clock := hlc.NewClock(manual.UnixNano, time.Nanosecond)
+	localityFn := func(roachpb.NodeID) string { return "" }
+	newStats := func(qps int) *replicaStats {
+		stats := newReplicaStats(clock, localityFn)
+		stats.recordCount(float64(qps*int(MinStatsDuration.Seconds())), 99)
+		return stats
+	}
+	coldRange := newStats(100)
+	hotRange := newStats(400)
+	manual.Increment(int64(MinStatsDuration))
+
+	testCases := []struct {
+		enabled     bool
+		leaseholder roachpb.StoreID
+		stats       *replicaStats
+		expected    roachpb.StoreID
+	}{
+		// Store 1 is overfull on QPS, and its lease moves to the store with the
+		// lowest QPS.
+		{enabled: true, leaseholder: 1, stats: coldRange, expected: 3},
+		// Moving the lease would make the target store overfull on QPS or
+		// busier than the lease holder's store.
+		{enabled: true, leaseholder: 1, stats: hotRange, expected: 0},
+		// Stores 2 and 3 aren't overfull on QPS.
+		{enabled: true, leaseholder: 2, stats: coldRange, expected: 0},
+		{enabled: true, leaseholder: 3, stats: coldRange, expected: 0},
+		// QPS is ignored when QPS-based rebalancing is disabled.
+		{enabled: false, leaseholder: 1, stats: coldRange, expected: 0},
+	}
+	for _, c := range testCases {
+		t.Run("", func(t *testing.T) {
+			EnableQPSBasedRebalancing.Override(&storePool.st.SV, c.enabled)
+			target := a.TransferLeaseTarget(
+				context.Background(),
+				config.ZoneConfig{},
+				existing,
+				c.leaseholder,
+				0,
+				c.stats,
+				true,  /* checkTransferLeaseSource */
+				true,  /* checkCandidateFullness */
+				false, /* !alwaysAllowDecisionWithoutStats */
+			)
+			if c.expected != target.StoreID {
+				t.Errorf("expected %d, got %d", c.expected, target.StoreID)
+			}
+			should := a.ShouldTransferLease(
+				context.Background(),
+				config.ZoneConfig{},
+				existing,
+				c.leaseholder,
+				0,
+				c.stats,
+			)
+			if e := c.expected != 0; e != should {
+				t.Errorf("expected ShouldTransferLease to return %t, got %t", e, should)
+			}
+		})
+	}
+}
+
 func TestLoadBasedLeaseRebalanceScore(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -19,16 +19,20 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 
+	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
@@ -367,6 +371,193 @@ func TestStoreRangeMergeNonCollocated(t *testing.T) {
 	}
 }
 
+// TestStoreRangeMergeQueue verifies that the merge queue merges an empty
+// range into the range that follows it, but leaves alone the ranges holding
+// system data.
+func TestStoreRangeMergeQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	storeCfg := storage.TestStoreConfig(nil)
+	storeCfg.TestingKnobs.DisableSplitQueue = true
+	storage.MergeQueueEnabled.Override(&storeCfg.Settings.SV, true)
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	store := createTestStoreWithConfig(t, stopper, storeCfg)
+
+	// Split off two empty ranges in the user key space. The splits are carried
+	// out from right to left, so that both split keys are in the first range.
+	const tableID = keys.MaxReservedDescID + 10
+	lhsKey := roachpb.Key(keys.MakeTablePrefix(tableID))
+	rhsKey := roachpb.Key(append(keys.MakeTablePrefix(tableID), 'b'))
+	for _, key := range []roachpb.Key{rhsKey, lhsKey} {
+		if _, pErr := client.SendWrapped(context.Background(), rg1(store), adminSplitArgs(key)); pErr != nil {
+			t.Fatal(pErr)
+		}
+	}
+	systemRepl := store.LookupReplica(roachpb.RKeyMin, nil)
+	if lhsRepl, rhsRepl := store.LookupReplica(roachpb.RKey(lhsKey), nil),
+		store.LookupReplica(roachpb.RKey(rhsKey), nil); lhsRepl == rhsRepl {
+		t.Fatalf("expected %s and %s to be split", lhsKey, rhsKey)
+	}
+
+	store.ForceMergeScanAndProcess()
+
+	testutils.SucceedsSoon(t, func() error {
+		lhsRepl := store.LookupReplica(roachpb.RKey(lhsKey), nil)
+		if rhsRepl := store.LookupReplica(roachpb.RKey(rhsKey), nil); lhsRepl != rhsRepl {
+			return errors.Errorf("%s and %s are not merged yet", lhsRepl, rhsRepl)
+		}
+		if !lhsRepl.Desc().StartKey.Equal(lhsKey) {
+			return errors.Errorf("expected %s to start at %s", lhsRepl, lhsKey)
+		}
+		return nil
+	})
+	if repl := store.LookupReplica(roachpb.RKeyMin, nil); repl != systemRepl {
+		t.Fatalf("expected %s to be left alone, but found %s", systemRepl, repl)
+	}
+}
+
+// TestStoreRangeMergeAfterLoadDecays verifies that, with load-based splitting
+// enabled, the merge queue merges two ranges which are above the minimum range
+// size and were split because of their load once their combined QPS has
+// decayed below the load-based splitting threshold, and only once the merge
+// queue is enabled.
+func TestStoreRangeMergeAfterLoadDecays(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	manual := hlc.NewManualClock(123)
+	storeCfg := storage.TestStoreConfig(hlc.NewClock(manual.UnixNano, time.Nanosecond))
+	storeCfg.TestingKnobs.DisableSplitQueue = true
+	storage.SplitByLoadEnabled.Override(&storeCfg.Settings.SV, true)
+	storage.SplitByLoadQPSThreshold.Override(&storeCfg.Settings.SV, 100)
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	store := createTestStoreWithConfig(t, stopper, storeCfg)
+
+	// Split off two ranges in the user key space, and fill both above the
+	// minimum range size of the default zone.
+	const tableID = keys.MaxReservedDescID + 10
+	lhsKey := roachpb.Key(keys.MakeTablePrefix(tableID))
+	rhsKey := roachpb.Key(append(keys.MakeTablePrefix(tableID), 'b'))
+	for _, key := range []roachpb.Key{rhsKey, lhsKey} {
+		if _, pErr := client.SendWrapped(context.Background(), rg1(store), adminSplitArgs(key)); pErr != nil {
+			t.Fatal(pErr)
+		}
+	}
+	lhsRepl := store.LookupReplica(roachpb.RKey(lhsKey), nil)
+	rhsRepl := store.LookupReplica(roachpb.RKey(rhsKey), nil)
+	minBytes := config.DefaultZoneConfig().RangeMinBytes
+	fillRange(store, lhsRepl.RangeID, append(lhsKey, 'a'), minBytes, t)
+	fillRange(store, rhsRepl.RangeID, rhsKey, minBytes, t)
+
+	// Only the boundaries created by load-based splits are merged back because
+	// of load. Pretend the split at rhsKey was one.
+	lhsRepl.SetLoadSplitEndKey(roachpb.RKey(rhsKey))
+
+	// Send 600 requests to each range and let 10 seconds pass, so that each
+	// range is below the threshold but their combined QPS is above it.
+	for _, repl := range []*storage.Replica{lhsRepl, rhsRepl} {
+		for i := 0; i < 600; i++ {
+			if _, pErr := client.SendWrappedWith(context.Background(), store, roachpb.Header{
+				RangeID:       repl.RangeID,
+				GatewayNodeID: store.Ident.NodeID,
+			}, getArgs(repl.Desc().StartKey.AsRawKey())); pErr != nil {
+				t.Fatal(pErr)
+			}
+		}
+	}
+	manual.Increment((10 * time.Second).Nanoseconds())
+	store.ForceMergeScanAndProcess()
+	if repl := store.LookupReplica(roachpb.RKey(rhsKey), nil); repl == lhsRepl {
+		t.Fatalf("expected %s not to be merged while the load is above the threshold", repl)
+	}
+
+	// Once the QPS has decayed, the ranges are merged, but only if the merge
+	// queue is enabled.
+	manual.Increment((50 * time.Second).Nanoseconds())
+	store.ForceMergeScanAndProcess()
+	if repl := store.LookupReplica(roachpb.RKey(rhsKey), nil); repl == lhsRepl {
+		t.Fatalf("expected %s not to be merged while the merge queue is disabled", repl)
+	}
+	storage.MergeQueueEnabled.Override(&store.ClusterSettings().SV, true)
+	store.ForceMergeScanAndProcess()
+	testutils.SucceedsSoon(t, func() error {
+		lhsRepl := store.LookupReplica(roachpb.RKey(lhsKey), nil)
+		if rhsRepl := store.LookupReplica(roachpb.RKey(rhsKey), nil); lhsRepl != rhsRepl {
+			return errors.Errorf("%s and %s are not merged yet", lhsRepl, rhsRepl)
+		}
+		return nil
+	})
+}
+
+// TestStoreRangeMergeConcurrentSplit verifies that a merge initiated by the
+// merge queue fails if the right-hand side range is split after the queue
+// decided to merge it, instead of merging a stale view of that range.
+func TestStoreRangeMergeConcurrentSplit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	const tableID = keys.MaxReservedDescID + 10
+	lhsKey := roachpb.Key(keys.MakeTablePrefix(tableID))
+	rhsKey := roachpb.Key(append(keys.MakeTablePrefix(tableID), 'b'))
+	splitKey := roachpb.Key(append(keys.MakeTablePrefix(tableID), 'c'))
+
+	// Pause the merge transaction once it has written the descriptor of the
+	// left-hand side range, which it does after the merge queue has looked up
+	// the descriptor of the right-hand side range.
+	var paused int32
+	mergeStarted := make(chan struct{})
+	splitDone := make(chan struct{})
+	storeCfg := storage.TestStoreConfig(nil)
+	storeCfg.TestingKnobs.DisableSplitQueue = true
+	storage.MergeQueueEnabled.Override(&storeCfg.Settings.SV, true)
+	storeCfg.TestingKnobs.TestingEvalFilter = func(filterArgs storagebase.FilterArgs) *roachpb.Error {
+		if filterArgs.Hdr.Txn == nil || filterArgs.Hdr.Txn.Name != "merge" ||
+			filterArgs.Req.Method() != roachpb.ConditionalPut ||
+			!filterArgs.Req.Header().Key.Equal(keys.RangeDescriptorKey(roachpb.RKey(lhsKey))) {
+			return nil
+		}
+		if atomic.CompareAndSwapInt32(&paused, 0, 1) {
+			close(mergeStarted)
+			<-splitDone
+		}
+		return nil
+	}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	store := createTestStoreWithConfig(t, stopper, storeCfg)
+
+	for _, key := range []roachpb.Key{rhsKey, lhsKey} {
+		if _, pErr := client.SendWrapped(context.Background(), rg1(store), adminSplitArgs(key)); pErr != nil {
+			t.Fatal(pErr)
+		}
+	}
+	lhsRepl := store.LookupReplica(roachpb.RKey(lhsKey), nil)
+	rhsRepl := store.LookupReplica(roachpb.RKey(rhsKey), nil)
+
+	mergeErr := make(chan error, 1)
+	go func() {
+		mergeErr <- store.MergeQueueProcess(context.Background(), lhsRepl)
+	}()
+
+	// Split the right-hand side range while the merge is in progress.
+	<-mergeStarted
+	if _, pErr := client.SendWrappedWith(context.Background(), store, roachpb.Header{
+		RangeID: rhsRepl.RangeID,
+	}, adminSplitArgs(splitKey)); pErr != nil {
+		t.Fatal(pErr)
+	}
+	close(splitDone)
+
+	if err := <-mergeErr; !testutils.IsError(err, "range changed during merge") {
+		t.Fatalf("expected the merge to fail on the changed descriptor, got %v", err)
+	}
+	for _, key := range []roachpb.Key{lhsKey, rhsKey, splitKey} {
+		if desc := store.LookupReplica(roachpb.RKey(key), nil).Desc(); !desc.StartKey.Equal(key) {
+			t.Fatalf("expected a range starting at %s, found %s", key, desc)
+		}
+	}
+	if desc := lhsRepl.Desc(); !desc.EndKey.Equal(rhsKey) {
+		t.Fatalf("expected %s to end at %s", desc, rhsKey)
+	}
+}
+
 // TestStoreRangeMergeStats starts by splitting a range, then writing random data
 // to both sides of the split. It then merges the ranges and verifies the merged
 // range has stats consistent with recomputations.
@@ -2280,6 +2280,9 @@ func TestStoreCapacityAfterSplit(t *testing.T) {
 	if minExpected, a := 1/float64(storage.MinStatsDuration/time.Second), cap.WritesPerSecond; minExpected > a {
 		t.Errorf("expected cap.WritesPerSecond >= %f, got %f", minExpected, a)
 	}
+	if minExpected, a := 1/float64(storage.MinStatsDuration/time.Second), cap.QueriesPerSecond; minExpected > a {
+		t.Errorf("expected cap.QueriesPerSecond >= %f, got %f", minExpected, a)
+	}
 	bpr2 := cap.BytesPerReplica
 	if bpr2.P10 <= bpr1.P10 {
 		t.Errorf("expected BytesPerReplica to have increased from %+v, but got %+v", bpr1, bpr2)
@@ -19,6 +19,17 @@ func (t TxnMeta) Short() string {
 	return t.ID.Short()
 }
 
+// IsIgnoredSeqNum returns true if the writes of the batch with the given
+// sequence number were rolled back to a savepoint.
+func (t *TxnMeta) IsIgnoredSeqNum(seq int32) bool {
+	for _, r := range t.IgnoredSeqNums {
+		if r.Start <= seq && seq <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
 // Total returns the range size as the sum of the key and value
 // bytes. This includes all non-live keys and all versioned values.
 func (ms MVCCStats) Total() int64 {
@@ -123,3 +134,15 @@ func (ms *MVCCStats) Subtract(oms MVCCStats) {
 func (meta MVCCMetadata) IsInline() bool {
 	return meta.RawBytes != nil
 }
+
+// LatestVisibleIntentHistory returns the index of the latest value in the
+// intent history which wasn't rolled back by the transaction, or -1 if there
+// is none.
+func (meta *MVCCMetadata) LatestVisibleIntentHistory(txn *TxnMeta) int {
+	for i := len(meta.IntentHistory) - 1; i >= 0; i-- {
+		if !txn.IsIgnoredSeqNum(meta.IntentHistory[i].Sequence) {
+			return i
+		}
+	}
+	return -1
+}
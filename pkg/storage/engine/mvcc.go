@@ -737,6 +737,24 @@ func mvccGetInternal(
 					txn.Epoch, meta.Txn.Epoch)
 			}
 			seekKey = seekKey.Next()
+		} else if ownIntent && txn.IsIgnoredSeqNum(meta.Txn.Sequence) {
+			// The intent was written after a savepoint which the transaction
+			// rolled back to. We read the latest value written before the
+			// savepoint instead, which is either in the intent history or an
+			// earlier version.
+			if i := meta.LatestVisibleIntentHistory(&txn.TxnMeta); i >= 0 {
+				value := &buf.value
+				value.RawBytes = meta.IntentHistory[i].Value
+				if allowedSafety == safeValue {
+					value.RawBytes = append([]byte(nil), value.RawBytes...)
+				}
+				value.Timestamp = meta.Timestamp
+				if err := value.Verify(metaKey.Key); err != nil {
+					return nil, nil, safeValue, err
+				}
+				return value, ignoredIntents, allowedSafety, nil
+			}
+			seekKey = seekKey.Next()
 		}
 	} else if txn != nil && timestamp.Less(txn.MaxTimestamp) {
 		// In this branch, the latest timestamp is ahead, and so the read of an
@@ -1000,6 +1018,38 @@ func maybeGetValue(
 	return valueFn(exVal)
 }
 
+// overwrittenIntentHistory returns the intent history of a key whose intent is
+// being overwritten by the same transaction in the same epoch: the history of
+// the intent, followed by the value of the intent. The values written by
+// batches which were rolled back are dropped.
+func overwrittenIntentHistory(
+	iter Iterator, metaKey MVCCKey, meta *enginepb.MVCCMetadata, txn *roachpb.Transaction,
+) ([]enginepb.MVCCMetadata_SequencedIntent, error) {
+	history := make([]enginepb.MVCCMetadata_SequencedIntent, 0, len(meta.IntentHistory)+1)
+	for _, h := range meta.IntentHistory {
+		if !txn.IsIgnoredSeqNum(h.Sequence) {
+			history = append(history, h)
+		}
+	}
+	// The writes of a batch are rolled back together, so there is no need to
+	// remember a value written by the same batch.
+	if meta.Txn.Sequence != txn.Sequence && !txn.IsIgnoredSeqNum(meta.Txn.Sequence) {
+		versionKey := metaKey
+		versionKey.Timestamp = meta.Timestamp
+		iter.Seek(versionKey)
+		if ok, err := iter.Valid(); err != nil {
+			return nil, err
+		} else if !ok || !iter.UnsafeKey().Equal(versionKey) {
+			return nil, errors.Errorf("intent value missing for key %s", versionKey)
+		}
+		history = append(history, enginepb.MVCCMetadata_SequencedIntent{
+			Sequence: meta.Txn.Sequence,
+			Value:    iter.Value(),
+		})
+	}
+	return history, nil
+}
+
 // mvccPutInternal adds a new timestamped value to the specified key.
 // If value is nil, creates a deletion tombstone value. valueFn is
 // an optional alternative to supplying value directly. It is passed
@@ -1059,6 +1109,7 @@ func mvccPutInternal(
 
 	var meta *enginepb.MVCCMetadata
 	var maybeTooOldErr error
+	var intentHistory []enginepb.MVCCMetadata_SequencedIntent
 	if ok {
 		// There is existing metadata for this key; ensure our write is permitted.
 		meta = &buf.meta
@@ -1086,6 +1137,11 @@ func mvccPutInternal(
 				ctx, iter, metaKey, value, ok, timestamp, txn, buf, valueFn); err != nil {
 				return err
 			}
+			if txn.Epoch == meta.Txn.Epoch {
+				if intentHistory, err = overwrittenIntentHistory(iter, metaKey, meta, txn); err != nil {
+					return err
+				}
+			}
 			// We are replacing our own older write intent. If we are
 			// writing at the same timestamp we can simply overwrite it;
 			// otherwise we must explicitly delete the obsolete intent.
@@ -1140,9 +1196,17 @@ func mvccPutInternal(
 	{
 		var txnMeta *enginepb.TxnMeta
 		if txn != nil {
-			txnMeta = &txn.TxnMeta
+			// The ignored sequence numbers are only needed by the requests of
+			// the transaction, not by its intents.
+			buf.newTxn = txn.TxnMeta
+			buf.newTxn.IgnoredSeqNums = nil
+			txnMeta = &buf.newTxn
+		}
+		buf.newMeta = enginepb.MVCCMetadata{
+			Txn:           txnMeta,
+			Timestamp:     timestamp,
+			IntentHistory: intentHistory,
 		}
-		buf.newMeta = enginepb.MVCCMetadata{Txn: txnMeta, Timestamp: timestamp}
 	}
 	newMeta := &buf.newMeta
 
@@ -1943,6 +2007,38 @@ func mvccResolveWriteIntent(
 		meta.Timestamp.Less(intent.Txn.Timestamp) &&
 		meta.Txn.Epoch >= intent.Txn.Epoch
 
+	// If the intent was written after a savepoint which the transaction rolled
+	// back to, we commit the latest value written before the savepoint
+	// instead. If there is none, the key wasn't written by the transaction
+	// and the intent is removed as if the transaction had aborted.
+	if commit && intent.Txn.IsIgnoredSeqNum(meta.Txn.Sequence) {
+		if i := meta.LatestVisibleIntentHistory(&intent.Txn); i < 0 {
+			commit = false
+		} else {
+			restored := *meta
+			restoredTxn := *meta.Txn
+			restoredTxn.Sequence = meta.IntentHistory[i].Sequence
+			restored.Txn = &restoredTxn
+			restored.IntentHistory = meta.IntentHistory[:i]
+			value := meta.IntentHistory[i].Value
+			restored.ValBytes = int64(len(value))
+			restored.Deleted = len(value) == 0
+			if err := engine.Put(MVCCKey{Key: intent.Key, Timestamp: meta.Timestamp}, value); err != nil {
+				return err
+			}
+			metaKeySize, metaValSize, err := buf.putMeta(engine, metaKey, &restored)
+			if err != nil {
+				return err
+			}
+			if ms != nil {
+				ms.Add(updateStatsOnPut(intent.Key, origMetaKeySize, origMetaValSize,
+					metaKeySize, metaValSize, meta, &restored))
+			}
+			*meta = restored
+			origMetaKeySize, origMetaValSize = metaKeySize, metaValSize
+		}
+	}
+
 	// If we're committing, or if the commit timestamp of the intent has
 	// been moved forward, and if the proposed epoch matches the existing
 	// epoch: update the meta.Txn. For commit, it's set to nil;
@@ -1957,8 +2053,12 @@ func mvccResolveWriteIntent(
 		var metaKeySize, metaValSize int64
 		var err error
 		if pushed {
-			// Keep intent if we're pushing timestamp.
+			// Keep intent if we're pushing timestamp. The intent keeps its
+			// own sequence number, which its intent history is relative to.
 			buf.newTxn = intent.Txn
+			buf.newTxn.Sequence = meta.Txn.Sequence
+			buf.newTxn.BatchIndex = meta.Txn.BatchIndex
+			buf.newTxn.IgnoredSeqNums = nil
 			buf.newMeta.Txn = &buf.newTxn
 			metaKeySize, metaValSize, err = buf.putMeta(engine, metaKey, &buf.newMeta)
 		} else {
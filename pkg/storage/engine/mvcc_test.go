@@ -2382,6 +2382,78 @@ func TestMVCCResolveTxn(t *testing.T) {
 	}
 }
 
+// TestMVCCIgnoredSeqNums verifies that the writes of a transaction which were
+// rolled back to a savepoint are invisible to the transaction and are not
+// committed.
+func TestMVCCIgnoredSeqNums(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	engine := createTestEngine()
+	defer engine.Close()
+	ctx := context.Background()
+
+	ms := &enginepb.MVCCStats{}
+	txn := makeTxn(*txn1, hlc.Timestamp{Logical: 1})
+	put := func(seq int32, key roachpb.Key, value roachpb.Value) {
+		txn.Sequence = seq
+		if err := MVCCPut(ctx, engine, ms, key, txn.Timestamp, value, txn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectValue := func(key roachpb.Key, txn *roachpb.Transaction, expValue *roachpb.Value) {
+		value, _, err := MVCCGet(ctx, engine, key, hlc.Timestamp{Logical: 1}, true, txn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expValue == nil {
+			if value != nil {
+				t.Fatalf("%s: expected no value, got %s", key, value.RawBytes)
+			}
+		} else if value == nil || !bytes.Equal(expValue.RawBytes, value.RawBytes) {
+			t.Fatalf("%s: expected value %s, got %v", key, expValue.RawBytes, value)
+		}
+	}
+
+	put(1, testKey1, value1)
+	put(2, testKey1, value2)
+	put(3, testKey2, value3)
+	put(4, testKey3, value4)
+	expectValue(testKey1, txn, &value2)
+	expectValue(testKey2, txn, &value3)
+
+	// Roll back the writes of sequences 2 and 3.
+	txn.IgnoredSeqNums = []enginepb.IgnoredSeqNumRange{{Start: 2, End: 3}}
+	expectValue(testKey1, txn, &value1)
+	expectValue(testKey2, txn, nil)
+	expectValue(testKey3, txn, &value4)
+
+	// Overwriting an intent drops the rolled back values from its history.
+	put(5, testKey3, value1)
+	put(6, testKey3, value2)
+	txn.IgnoredSeqNums = append(txn.IgnoredSeqNums, enginepb.IgnoredSeqNumRange{Start: 6, End: 6})
+	expectValue(testKey3, txn, &value1)
+
+	commit := txn.Clone()
+	commit.Status = roachpb.COMMITTED
+	for _, key := range []roachpb.Key{testKey1, testKey2, testKey3} {
+		if err := MVCCResolveWriteIntent(ctx, engine, ms, roachpb.Intent{
+			Span: roachpb.Span{Key: key}, Txn: commit.TxnMeta, Status: commit.Status,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectValue(testKey1, nil, &value1)
+	expectValue(testKey2, nil, nil)
+	expectValue(testKey3, nil, &value1)
+
+	iter := engine.NewIterator(false)
+	defer iter.Close()
+	expMS, err := ComputeStatsGo(iter, mvccKey(roachpb.KeyMin), mvccKey(roachpb.KeyMax), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyStats("after commit", ms, &expMS, t)
+}
+
 // TestMVCCResolveNewerIntent verifies that resolving a newer intent
 // than the committing transaction aborts the intent.
 func TestMVCCResolveNewerIntent(t *testing.T) {
@@ -97,6 +97,19 @@ func (s *Store) ForceSplitScanAndProcess() {
 	forceScanAndProcess(s, s.splitQueue.baseQueue)
 }
 
+// ForceMergeScanAndProcess iterates over all ranges and enqueues any that
+// may need to be merged.
+func (s *Store) ForceMergeScanAndProcess() {
+	forceScanAndProcess(s, s.mergeQueue.baseQueue)
+}
+
+// MergeQueueProcess runs the merge queue on the given replica, regardless of
+// whether it would be queued, and returns the error it encountered.
+func (s *Store) MergeQueueProcess(ctx context.Context, repl *Replica) error {
+	sysCfg, _ := s.cfg.Gossip.GetSystemConfig()
+	return s.mergeQueue.process(ctx, repl, sysCfg)
+}
+
 // ForceRaftLogScanAndProcess iterates over all ranges and enqueues any that
 // need their raft logs truncated and then process each of them.
 func (s *Store) ForceRaftLogScanAndProcess() {
@@ -165,6 +178,11 @@ func (s *Store) SetSplitQueueActive(active bool) {
 	s.setSplitQueueActive(active)
 }
 
+// SetMergeQueueActive enables or disables the merge queue.
+func (s *Store) SetMergeQueueActive(active bool) {
+	s.setMergeQueueActive(active)
+}
+
 // SetRaftSnapshotQueueActive enables or disables the raft snapshot queue.
 func (s *Store) SetRaftSnapshotQueueActive(active bool) {
 	s.setRaftSnapshotQueueActive(active)
@@ -395,6 +413,12 @@ const (
 	sideloadBogusTerm  = 67890
 )
 
+// SetLoadSplitEndKey records that the range was split at the given key
+// because of its load, which lets the merge queue undo the split.
+func (r *Replica) SetLoadSplitEndKey(key roachpb.RKey) {
+	r.setLoadSplitEndKey(key)
+}
+
 func (r *Replica) PutBogusSideloadedData() {
 	r.raftMu.Lock()
 	defer r.raftMu.Unlock()
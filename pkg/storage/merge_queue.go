@@ -0,0 +1,292 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+const (
+	// mergeQueueTimerDuration is the duration between merges of queued ranges.
+	mergeQueueTimerDuration = 0 // zero duration to process merges greedily.
+)
+
+// MergeQueueEnabled controls whether ranges are automatically merged with
+// their right-hand neighbor when they are below the minimum size of their
+// zone.
+//
+// The setting is off by default because merges don't yet freeze the
+// right-hand side range through raft: they only hold off new requests on the
+// local replica of the right-hand side range (see blockRequestsForMerge), and
+// neither wait for the commands already in flight on it nor pin its lease.
+// The merge trigger also applies the right-hand side data known to each
+// replica of the left-hand side range, which may lag behind.
+//
+// This setting also controls whether load-based splits are undone: no range
+// is merged automatically while it is off.
+var MergeQueueEnabled = settings.RegisterBoolSetting(
+	"kv.range_merge.queue_enabled",
+	"whether the automatic merge queue is enabled",
+	false,
+)
+
+// mergeQueue manages a queue of ranges slated to be merged with the range
+// that follows them in the key space because they are below the minimum size
+// of their zone. This happens when tables are dropped or when their data ages
+// out, and leaves behind ranges that cost gossip, range cache and raft
+// heartbeat resources for little or no data.
+//
+// If load-based splitting is enabled, the queue also undoes load-based
+// splits: a range whose end key was created by a load-based split (see
+// endKeySplitByLoad) is merged with the range that follows it, regardless of
+// their sizes, once their combined QPS is below the load-based splitting
+// threshold and the merged range wouldn't be split again because of its size.
+//
+// The merge is carried out by the leaseholder of the left-hand side range.
+// AdminMerge requires the two ranges to be collocated on the same set of
+// stores, so the queue first relocates the replicas of the right-hand side
+// range onto the stores of the left-hand side range, using the same
+// AdminChangeReplicas and AdminTransferLease commands as the replicate queue.
+type mergeQueue struct {
+	*baseQueue
+	db *client.DB
+}
+
+// newMergeQueue returns a new instance of mergeQueue.
+func newMergeQueue(store *Store, db *client.DB, gossip *gossip.Gossip) *mergeQueue {
+	mq := &mergeQueue{
+		db: db,
+	}
+	mq.baseQueue = newBaseQueue(
+		"merge", mq, store, gossip,
+		queueConfig{
+			maxSize:              defaultQueueMaxSize,
+			needsLease:           true,
+			needsSystemConfig:    true,
+			acceptsUnsplitRanges: false,
+			successes:            store.metrics.MergeQueueSuccesses,
+			failures:             store.metrics.MergeQueueFailures,
+			pending:              store.metrics.MergeQueuePending,
+			processingNanos:      store.metrics.MergeQueueProcessingNanos,
+		},
+	)
+	return mq
+}
+
+// shouldQueue determines whether a range should be queued for merging. This
+// is true if the range can be merged with the range that follows it, that is,
+// if the boundary between the two ranges isn't required by a zone config or a
+// table, if the range isn't itself a candidate for a load-based split, and if
+// its size in bytes allows for a merge (see mergeableSize). The smaller the
+// range, the higher the priority.
+func (mq *mergeQueue) shouldQueue(
+	ctx context.Context, now hlc.Timestamp, repl *Replica, sysCfg config.SystemConfig,
+) (shouldQ bool, priority float64) {
+	st := &repl.store.ClusterSettings().SV
+	if !MergeQueueEnabled.Get(st) {
+		return false, 0
+	}
+	desc := repl.Desc()
+	if !mergeableRange(desc, sysCfg) {
+		return false, 0
+	}
+	zone, err := sysCfg.GetZoneConfigForKey(desc.StartKey)
+	if err != nil {
+		log.Errorf(ctx, "could not find zone config for %s: %s", repl, err)
+		return false, 0
+	}
+	if SplitByLoadEnabled.Get(st) {
+		if qps, ok := repl.loadBasedQPS(); !ok || qps >= float64(SplitByLoadQPSThreshold.Get(st)) {
+			return false, 0
+		}
+	}
+	size := repl.GetMVCCStats().Total()
+	if !mergeableSize(st, zone, size, repl.endKeySplitByLoad()) {
+		return false, 0
+	}
+	if size < zone.RangeMinBytes {
+		return true, 1 - float64(size)/float64(zone.RangeMinBytes)
+	}
+	// Ranges that are only merged to undo load-based splits get a lower
+	// priority.
+	return true, 0.1 * (1 - float64(size)/float64(zone.RangeMaxBytes))
+}
+
+// mergeableSize returns whether a range of the given size can be merged with
+// the range that follows it. This is the case for ranges below the minimum
+// size of their zone. With load-based splitting enabled, this is also the case
+// for ranges below the maximum size of their zone whose end key was created by
+// a load-based split, so that such splits are undone once the load is gone;
+// their load is checked separately.
+func mergeableSize(
+	st *settings.Values, zone config.ZoneConfig, size int64, endKeySplitByLoad bool,
+) bool {
+	return size < zone.RangeMinBytes ||
+		(SplitByLoadEnabled.Get(st) && endKeySplitByLoad && size < zone.RangeMaxBytes)
+}
+
+// mergeableRange returns whether the range with the given descriptor can be
+// merged with the range that follows it.
+func mergeableRange(desc *roachpb.RangeDescriptor, sysCfg config.SystemConfig) bool {
+	// Merging the final range doesn't make sense, and merging the ranges
+	// holding system data isn't worth the risk: only the user key space is
+	// expected to accumulate small ranges.
+	if desc.EndKey.Equal(roachpb.RKeyMax) ||
+		desc.StartKey.Less(roachpb.RKey(keys.UserTableDataMin)) {
+		return false
+	}
+	// A merge across a split point would be undone by the split queue.
+	return !sysCfg.NeedsSplit(desc.StartKey, desc.EndKey.Next())
+}
+
+// process collocates the range with the range that follows it and merges
+// them, unless the merged range would be too large.
+func (mq *mergeQueue) process(
+	ctx context.Context, lhsRepl *Replica, sysCfg config.SystemConfig,
+) error {
+	st := &mq.store.ClusterSettings().SV
+	if !MergeQueueEnabled.Get(st) {
+		return nil
+	}
+	lhsDesc := lhsRepl.Desc()
+	if !mergeableRange(lhsDesc, sysCfg) {
+		return nil
+	}
+	zone, err := sysCfg.GetZoneConfigForKey(lhsDesc.StartKey)
+	if err != nil {
+		return err
+	}
+	lhsSize := lhsRepl.GetMVCCStats().Total()
+	if !mergeableSize(st, zone, lhsSize, lhsRepl.endKeySplitByLoad()) {
+		// The range has grown since it was queued.
+		return nil
+	}
+
+	rhsDesc, err := mq.lookupRightNeighbor(ctx, lhsDesc)
+	if err != nil {
+		return err
+	}
+	if sysCfg.NeedsSplit(lhsDesc.StartKey, rhsDesc.EndKey) {
+		return nil
+	}
+
+	if !replicaSetsEqual(lhsDesc.Replicas, rhsDesc.Replicas) {
+		// Move the replicas of the right-hand side range onto the stores of the
+		// left-hand side range. The lease of the right-hand side range is moved
+		// to this store, which holds the lease of the left-hand side range.
+		targets := []roachpb.ReplicationTarget{{
+			NodeID:  mq.store.Ident.NodeID,
+			StoreID: mq.store.StoreID(),
+		}}
+		for _, repl := range lhsDesc.Replicas {
+			if repl.StoreID != mq.store.StoreID() {
+				targets = append(targets, roachpb.ReplicationTarget{
+					NodeID:  repl.NodeID,
+					StoreID: repl.StoreID,
+				})
+			}
+		}
+		log.VEventf(ctx, 1, "collocating r%d with %s", rhsDesc.RangeID, lhsRepl)
+		if err := relocateRange(ctx, mq.db, rhsDesc, targets); err != nil {
+			return errors.Wrapf(err, "unable to collocate r%d with %s", rhsDesc.RangeID, lhsRepl)
+		}
+		if rhsDesc, err = mq.lookupRightNeighbor(ctx, lhsDesc); err != nil {
+			return err
+		}
+	}
+
+	// The size of the right-hand side range is only known once it has a
+	// replica on this store. Don't merge if the merged range would need to be
+	// split again.
+	rhsRepl, err := mq.store.GetReplica(rhsDesc.RangeID)
+	if err != nil || !rhsRepl.IsInitialized() {
+		return errors.Errorf("r%d is not yet collocated with %s", rhsDesc.RangeID, lhsRepl)
+	}
+	if lhsSize+rhsRepl.GetMVCCStats().Total() >= zone.RangeMaxBytes {
+		return nil
+	}
+
+	// The merge blocks requests to the right-hand side range through its local
+	// replica, which only holds them off if that replica holds the lease. The
+	// lease of a range which doesn't receive any requests may have expired.
+	if _, pErr := rhsRepl.redirectOnOrAcquireLease(ctx); pErr != nil {
+		return errors.Wrapf(pErr.GoError(), "unable to acquire the lease of r%d", rhsDesc.RangeID)
+	}
+
+	// Don't undo a load-based split while the merged range would be split
+	// again because of its load. The load of the right-hand side range is
+	// only known once its lease has been on this store for long enough.
+	if SplitByLoadEnabled.Get(st) {
+		lhsQPS, lhsOK := lhsRepl.loadBasedQPS()
+		rhsQPS, rhsOK := rhsRepl.loadBasedQPS()
+		if !lhsOK || !rhsOK || lhsQPS+rhsQPS >= float64(SplitByLoadQPSThreshold.Get(st)) {
+			return nil
+		}
+	}
+
+	// Make sure neither lease moved away in the meantime.
+	now := mq.store.Clock().Now()
+	if !lhsRepl.OwnsValidLease(now) || !rhsRepl.OwnsValidLease(now) {
+		return errors.Errorf("lease of %s or r%d moved during merge", lhsRepl, rhsDesc.RangeID)
+	}
+
+	// The merge transaction conditionally updates and deletes the descriptors
+	// of the two ranges, expecting the ones the decision to merge was based
+	// on, so that the merge fails if either range was split or had its
+	// replicas changed since it was looked up.
+	log.VEventf(ctx, 1, "merging r%d into %s", rhsDesc.RangeID, lhsRepl)
+	if _, pErr := lhsRepl.adminMerge(ctx, lhsDesc, &rhsDesc); pErr != nil {
+		return errors.Wrapf(pErr.GoError(), "unable to merge r%d into %s", rhsDesc.RangeID, lhsRepl)
+	}
+	return nil
+}
+
+// lookupRightNeighbor returns the descriptor of the range that follows the
+// range with the given descriptor, using a consistent read.
+func (mq *mergeQueue) lookupRightNeighbor(
+	ctx context.Context, desc *roachpb.RangeDescriptor,
+) (roachpb.RangeDescriptor, error) {
+	var rhsDesc roachpb.RangeDescriptor
+	if err := mq.db.GetProto(ctx, keys.RangeDescriptorKey(desc.EndKey), &rhsDesc); err != nil {
+		return roachpb.RangeDescriptor{}, err
+	}
+	if !rhsDesc.StartKey.Equal(desc.EndKey) {
+		return roachpb.RangeDescriptor{}, errors.Errorf(
+			"could not find the range following %s", desc)
+	}
+	return rhsDesc, nil
+}
+
+// timer returns interval between processing successive queued merges.
+func (*mergeQueue) timer(_ time.Duration) time.Duration {
+	return mergeQueueTimerDuration
+}
+
+// purgatoryChan returns nil.
+func (*mergeQueue) purgatoryChan() <-chan struct{} {
+	return nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+)
+
+// TestMergeQueueShouldQueue verifies shouldQueue method correctly
+// combines the size of the range with the split points around it.
+func TestMergeQueueShouldQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	tc.Start(t, stopper)
+
+	// Set zone configs.
+	config.TestingSetZoneConfig(2000, config.ZoneConfig{RangeMinBytes: 1 << 20, RangeMaxBytes: 32 << 20})
+	config.TestingSetZoneConfig(2002, config.ZoneConfig{RangeMinBytes: 1 << 20, RangeMaxBytes: 32 << 20})
+
+	tableKey := func(id uint32, suffix string) roachpb.RKey {
+		return roachpb.RKey(append(keys.MakeTablePrefix(id), suffix...))
+	}
+
+	testCases := []struct {
+		start, end roachpb.RKey
+		bytes      int64
+		shouldQ    bool
+		priority   float64
+	}{
+		// Empty range.
+		{tableKey(2002, ""), tableKey(2002, "b"), 0, true, 1},
+		// Half of min bytes.
+		{tableKey(2002, ""), tableKey(2002, "b"), 1 << 19, true, 0.5},
+		// Min bytes.
+		{tableKey(2002, ""), tableKey(2002, "b"), 1 << 20, false, 0},
+		// Empty range, but its end key is a split point.
+		{tableKey(2000, ""), tableKey(2001, ""), 0, false, 0},
+		// Empty range, but it needs to be split.
+		{tableKey(2000, ""), tableKey(2002, "b"), 0, false, 0},
+		// Empty range holding system data.
+		{roachpb.RKeyMin, roachpb.RKey(keys.MetaMax), 0, false, 0},
+		// Empty final range.
+		{tableKey(2002, "b"), roachpb.RKeyMax, 0, false, 0},
+	}
+
+	mergeQ := newMergeQueue(tc.store, nil, tc.gossip)
+
+	cfg, ok := tc.gossip.GetSystemConfig()
+	if !ok {
+		t.Fatal("config not set")
+	}
+
+	for _, enabled := range []bool{false, true} {
+		MergeQueueEnabled.Override(&tc.store.cfg.Settings.SV, enabled)
+
+		for i, test := range testCases {
+			// Create a replica for testing that is not hooked up to the store. This
+			// ensures that the store won't be mucking with our replica concurrently
+			// during testing (e.g. via the system config gossip update).
+			copy := *tc.repl.Desc()
+			copy.StartKey = test.start
+			copy.EndKey = test.end
+			repl, err := NewReplica(&copy, tc.store, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			repl.mu.Lock()
+			repl.mu.state.Stats = enginepb.MVCCStats{KeyBytes: test.bytes}
+			repl.mu.Unlock()
+
+			expectedShouldQ, expectedPriority := test.shouldQ, test.priority
+			if !enabled {
+				expectedShouldQ, expectedPriority = false, 0
+			}
+			shouldQ, priority := mergeQ.shouldQueue(context.TODO(), hlc.Timestamp{}, repl, cfg)
+			if shouldQ != expectedShouldQ {
+				t.Errorf("%t/%d: should queue expected %t; got %t", enabled, i, expectedShouldQ, shouldQ)
+			}
+			if math.Abs(priority-expectedPriority) > 0.00001 {
+				t.Errorf("%t/%d: priority expected %f; got %f", enabled, i, expectedPriority, priority)
+			}
+		}
+	}
+}
+
+// TestMergeQueueShouldQueueByLoad verifies that, with load-based splitting
+// enabled, the shouldQueue method queues ranges regardless of their size once
+// their QPS is below the load-based splitting threshold.
+func TestMergeQueueShouldQueueByLoad(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	tc.Start(t, stopper)
+
+	config.TestingSetZoneConfig(2002, config.ZoneConfig{RangeMinBytes: 1 << 20, RangeMaxBytes: 32 << 20})
+	st := &tc.store.cfg.Settings.SV
+	MergeQueueEnabled.Override(st, true)
+	SplitByLoadEnabled.Override(st, true)
+	SplitByLoadQPSThreshold.Override(st, 100)
+
+	tableKey := func(suffix string) roachpb.RKey {
+		return roachpb.RKey(append(keys.MakeTablePrefix(2002), suffix...))
+	}
+
+	testCases := []struct {
+		bytes       int64
+		qps         float64
+		splitByLoad bool
+		shouldQ     bool
+		priority    float64
+	}{
+		// Empty range without load.
+		{0, 0, false, true, 1},
+		// Range above min bytes with little load, split because of its load.
+		{1 << 24, 10, true, true, 0.05},
+		// Range above min bytes with little load, split for another reason.
+		{1 << 24, 10, false, false, 0},
+		// Range above min bytes with load above the threshold.
+		{1 << 24, 200, true, false, 0},
+		// Range at max bytes with little load.
+		{32 << 20, 10, true, false, 0},
+	}
+
+	mergeQ := newMergeQueue(tc.store, nil, tc.gossip)
+
+	cfg, ok := tc.gossip.GetSystemConfig()
+	if !ok {
+		t.Fatal("config not set")
+	}
+
+	for i, test := range testCases {
+		copy := *tc.repl.Desc()
+		copy.StartKey = tableKey("")
+		copy.EndKey = tableKey("b")
+		repl, err := NewReplica(&copy, tc.store, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.splitByLoad {
+			repl.setLoadSplitEndKey(copy.EndKey)
+		}
+
+		repl.mu.Lock()
+		repl.mu.state.Stats = enginepb.MVCCStats{KeyBytes: test.bytes}
+		repl.mu.Unlock()
+
+		// Record the QPS over 10 seconds.
+		repl.leaseholderStats.resetRequestCounts()
+		tc.manualClock.Increment((10 * time.Second).Nanoseconds())
+		repl.leaseholderStats.recordCount(test.qps*10, 1)
+
+		shouldQ, priority := mergeQ.shouldQueue(context.TODO(), hlc.Timestamp{}, repl, cfg)
+		if shouldQ != test.shouldQ {
+			t.Errorf("%d: should queue expected %t; got %t", i, test.shouldQ, shouldQ)
+		}
+		if math.Abs(priority-test.priority) > 0.00001 {
+			t.Errorf("%d: priority expected %f; got %f", i, test.priority, priority)
+		}
+
+		// Load-based splits aren't undone while the merge queue is disabled.
+		MergeQueueEnabled.Override(st, false)
+		if shouldQ, _ := mergeQ.shouldQueue(context.TODO(), hlc.Timestamp{}, repl, cfg); shouldQ {
+			t.Errorf("%d: expected not to queue with the merge queue disabled", i)
+		}
+		MergeQueueEnabled.Override(st, true)
+	}
+}
+
+////
+// NOTE: tests which actually verify processing of the merge queue are
+// in client_merge_test.go, which is in a different test package in
+// order to allow for distributed transactions with a proper client.
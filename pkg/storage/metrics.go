@@ -104,6 +104,11 @@ var (
 		Name: "leases.epoch",
 		Help: "Number of replica leaseholders using epoch-based leases"}
 
+	// Follower read metrics.
+	metaFollowerReadsCount = metric.Metadata{
+		Name: "follower_reads.success_count",
+		Help: "Number of reads served by a replica which does not hold the lease"}
+
 	// Storage metrics.
 	metaLiveBytes = metric.Metadata{
 		Name: "livebytes",
@@ -164,6 +169,9 @@ var (
 	metaAverageWritesPerSecond = metric.Metadata{
 		Name: "rebalancing.writespersecond",
 		Help: "Number of keys written (i.e. applied by raft) per second to the store, averaged over a large time period as used in rebalancing decisions"}
+	metaAverageQueriesPerSecond = metric.Metadata{
+		Name: "rebalancing.queriespersecond",
+		Help: "Number of kv-level requests received per second by the store, averaged over a large time period as used in rebalancing decisions"}
 
 	// RocksDB metrics.
 	metaRdbBlockCacheHits = metric.Metadata{
@@ -386,6 +394,18 @@ var (
 	metaReplicateQueuePurgatory = metric.Metadata{
 		Name: "queue.replicate.purgatory",
 		Help: "Number of replicas in the replicate queue's purgatory, awaiting allocation options"}
+	metaMergeQueueSuccesses = metric.Metadata{
+		Name: "queue.merge.process.success",
+		Help: "Number of replicas successfully processed by the merge queue"}
+	metaMergeQueueFailures = metric.Metadata{
+		Name: "queue.merge.process.failure",
+		Help: "Number of replicas which failed processing in the merge queue"}
+	metaMergeQueuePending = metric.Metadata{
+		Name: "queue.merge.pending",
+		Help: "Number of pending replicas in the merge queue"}
+	metaMergeQueueProcessingNanos = metric.Metadata{
+		Name: "queue.merge.processingnanos",
+		Help: "Nanoseconds spent processing replicas in the merge queue"}
 	metaSplitQueueSuccesses = metric.Metadata{
 		Name: "queue.split.process.success",
 		Help: "Number of replicas successfully processed by the split queue"}
@@ -509,6 +529,9 @@ type StoreMetrics struct {
 	LeaseExpirationCount      *metric.Gauge
 	LeaseEpochCount           *metric.Gauge
 
+	// Follower read metrics.
+	FollowerReadsCount *metric.Counter
+
 	// Storage metrics.
 	LiveBytes       *metric.Gauge
 	KeyBytes        *metric.Gauge
@@ -530,7 +553,8 @@ type StoreMetrics struct {
 	SysCount        *metric.Gauge
 
 	// Rebalancing metrics.
-	AverageWritesPerSecond *metric.GaugeFloat64
+	AverageWritesPerSecond  *metric.GaugeFloat64
+	AverageQueriesPerSecond *metric.GaugeFloat64
 
 	// RocksDB metrics.
 	RdbBlockCacheHits           *metric.Gauge
@@ -625,6 +649,10 @@ type StoreMetrics struct {
 	ReplicateQueuePending                     *metric.Gauge
 	ReplicateQueueProcessingNanos             *metric.Counter
 	ReplicateQueuePurgatory                   *metric.Gauge
+	MergeQueueSuccesses                       *metric.Counter
+	MergeQueueFailures                        *metric.Counter
+	MergeQueuePending                         *metric.Gauge
+	MergeQueueProcessingNanos                 *metric.Counter
 	SplitQueueSuccesses                       *metric.Counter
 	SplitQueueFailures                        *metric.Counter
 	SplitQueuePending                         *metric.Gauge
@@ -702,6 +730,9 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		LeaseExpirationCount:      metric.NewGauge(metaLeaseExpirationCount),
 		LeaseEpochCount:           metric.NewGauge(metaLeaseEpochCount),
 
+		// Follower read metrics.
+		FollowerReadsCount: metric.NewCounter(metaFollowerReadsCount),
+
 		// Storage metrics.
 		LiveBytes:       metric.NewGauge(metaLiveBytes),
 		KeyBytes:        metric.NewGauge(metaKeyBytes),
@@ -723,7 +754,8 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		SysCount:        metric.NewGauge(metaSysCount),
 
 		// Rebalancing metrics.
-		AverageWritesPerSecond: metric.NewGaugeFloat64(metaAverageWritesPerSecond),
+		AverageWritesPerSecond:  metric.NewGaugeFloat64(metaAverageWritesPerSecond),
+		AverageQueriesPerSecond: metric.NewGaugeFloat64(metaAverageQueriesPerSecond),
 
 		// RocksDB metrics.
 		RdbBlockCacheHits:           metric.NewGauge(metaRdbBlockCacheHits),
@@ -811,6 +843,10 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		ReplicateQueuePending:                     metric.NewGauge(metaReplicateQueuePending),
 		ReplicateQueueProcessingNanos:             metric.NewCounter(metaReplicateQueueProcessingNanos),
 		ReplicateQueuePurgatory:                   metric.NewGauge(metaReplicateQueuePurgatory),
+		MergeQueueSuccesses:                       metric.NewCounter(metaMergeQueueSuccesses),
+		MergeQueueFailures:                        metric.NewCounter(metaMergeQueueFailures),
+		MergeQueuePending:                         metric.NewGauge(metaMergeQueuePending),
+		MergeQueueProcessingNanos:                 metric.NewCounter(metaMergeQueueProcessingNanos),
 		SplitQueueSuccesses:                       metric.NewCounter(metaSplitQueueSuccesses),
 		SplitQueueFailures:                        metric.NewCounter(metaSplitQueueFailures),
 		SplitQueuePending:                         metric.NewGauge(metaSplitQueuePending),
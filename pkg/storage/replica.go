@@ -282,6 +282,39 @@ type Replica struct {
 		queues [numSpanScope]*CommandQueue
 	}
 
+	// closedTSMu tracks the timestamps closed by this replica while it holds
+	// the lease, and the writes which prevent it from closing more recent
+	// timestamps. See replica_closed_timestamp.go.
+	closedTSMu struct {
+		// Protects all fields in the closedTSMu struct.
+		//
+		// Locking notes: Replica.mu < Replica.closedTSMu < Store.tsCacheMu
+		syncutil.Mutex
+		// The number of writes being evaluated or proposed, by the timestamp
+		// they were registered at.
+		inFlight map[hlc.Timestamp]int
+		// The latest timestamp closed under lease.
+		closed hlc.Timestamp
+		lease  roachpb.Lease
+	}
+
+	// loadSplitMu tracks the keys of the requests received by this replica
+	// while its QPS is above the load-based splitting threshold. See
+	// replica_load_split.go.
+	loadSplitMu struct {
+		syncutil.Mutex
+		// The last time the QPS of the replica was checked against the
+		// threshold.
+		lastQPSCheck time.Time
+		// The keys sampled since the QPS went above the threshold, if it is
+		// still above it.
+		finder *splitKeyFinder
+		// The end key of the range, if the range was split at it because of
+		// its load by this replica or by the replica it was split from or
+		// merged with. See endKeySplitByLoad.
+		splitEndKey roachpb.RKey
+	}
+
 	mu struct {
 		// Protects all fields in the mu struct.
 		syncutil.RWMutex
@@ -343,6 +376,17 @@ type Replica struct {
 		minLeaseProposedTS hlc.Timestamp
 		// Max bytes before split.
 		maxBytes int64
+		// The most recent closed timestamp carried by an applied command, and
+		// the lease under which that command was proposed. Reads at or below
+		// the closed timestamp can be served without the lease for as long as
+		// that lease remains in effect.
+		closedTimestamp      hlc.Timestamp
+		closedTimestampLease roachpb.Lease
+		// mergesInProgress contains a channel for each in-progress merge that
+		// subsumes this range, which is closed once that merge has completed.
+		// Requests for the user data of the range wait for all of them. See
+		// blockRequestsForMerge.
+		mergesInProgress map[chan struct{}]struct{}
 		// proposals stores the Raft in-flight commands which
 		// originated at this Replica, i.e. all commands for which
 		// propose has been called, but which have not yet
@@ -1764,6 +1808,7 @@ func (r *Replica) Send(
 
 	if r.leaseholderStats != nil && ba.Header.GatewayNodeID != 0 {
 		r.leaseholderStats.record(ba.Header.GatewayNodeID)
+		r.recordLoadSplitSample(ba)
 	}
 
 	if err := r.checkBatchRequest(ba); err != nil {
@@ -2404,9 +2449,17 @@ func (r *Replica) executeAdminBatch(
 func (r *Replica) executeReadOnlyBatch(
 	ctx context.Context, ba roachpb.BatchRequest,
 ) (br *roachpb.BatchResponse, pErr *roachpb.Error) {
-	// If the read is consistent, the read requires the range lease.
+	if pErr := r.maybeWaitForMerges(ctx, ba); pErr != nil {
+		return nil, pErr
+	}
+
+	// If the read is consistent, the read requires the range lease, unless it
+	// is below the timestamp closed by the leaseholder.
 	if ba.ReadConsistency != roachpb.INCONSISTENT {
-		if _, pErr = r.redirectOnOrAcquireLease(ctx); pErr != nil {
+		if r.canServeFollowerRead(ba) {
+			log.Event(ctx, "serving follower read")
+			r.store.metrics.FollowerReadsCount.Inc(1)
+		} else if _, pErr = r.redirectOnOrAcquireLease(ctx); pErr != nil {
 			return nil, pErr
 		}
 	}
@@ -2489,6 +2542,9 @@ func (r *Replica) executeReadOnlyBatch(
 func (r *Replica) executeWriteBatch(
 	ctx context.Context, ba roachpb.BatchRequest,
 ) (*roachpb.BatchResponse, *roachpb.Error) {
+	if pErr := r.maybeWaitForMerges(ctx, ba); pErr != nil {
+		return nil, pErr
+	}
 	var ambiguousResult bool
 	for count := 0; ; count++ {
 		br, pErr, retry := r.tryExecuteWriteBatch(ctx, ba)
@@ -2527,6 +2583,73 @@ func (r *Replica) executeWriteBatch(
 	}
 }
 
+// blockRequestsForMerge makes requests for the user data of the range wait
+// until the returned function is called. It is used while the range is merged
+// into its left hand side neighbor, so that the data subsumed by the merge
+// can't change between the decision to merge and the commit of the merge.
+// Each merge gets its own channel, so concurrent merge attempts of the range
+// don't release each other's waiters.
+//
+// Requests that were already evaluating when the merge started are not
+// waited for, which is one of the reasons the merge queue is disabled by
+// default. See MergeQueueEnabled.
+func (r *Replica) blockRequestsForMerge() func() {
+	mergeComplete := make(chan struct{})
+	r.mu.Lock()
+	if r.mu.mergesInProgress == nil {
+		r.mu.mergesInProgress = make(map[chan struct{}]struct{})
+	}
+	r.mu.mergesInProgress[mergeComplete] = struct{}{}
+	r.mu.Unlock()
+	return func() {
+		r.mu.Lock()
+		delete(r.mu.mergesInProgress, mergeComplete)
+		r.mu.Unlock()
+		close(mergeComplete)
+	}
+}
+
+// maybeWaitForMerges waits for all the in-progress merges of the range to
+// complete if the batch accesses the user data of the range. Lease requests
+// and requests for range-local keys, like those of the merge transaction
+// itself and of the splits and replica changes it may have to push, are let
+// through.
+func (r *Replica) maybeWaitForMerges(ctx context.Context, ba roachpb.BatchRequest) *roachpb.Error {
+	if ba.IsLeaseRequest() {
+		return nil
+	}
+	global := false
+	for _, union := range ba.Requests {
+		if !keys.IsLocal(union.GetInner().Header().Key) {
+			global = true
+			break
+		}
+	}
+	if !global {
+		return nil
+	}
+	for {
+		var mergeComplete chan struct{}
+		r.mu.RLock()
+		for ch := range r.mu.mergesInProgress {
+			mergeComplete = ch
+			break
+		}
+		r.mu.RUnlock()
+		if mergeComplete == nil {
+			return nil
+		}
+		log.Event(ctx, "waiting for in-progress merge")
+		select {
+		case <-mergeComplete:
+		case <-ctx.Done():
+			return roachpb.NewError(ctx.Err())
+		case <-r.store.stopper.ShouldQuiesce():
+			return roachpb.NewError(&roachpb.NodeUnavailableError{})
+		}
+	}
+}
+
 // tryExecuteWriteBatch is invoked by executeWriteBatch, which will
 // call this method until it returns a non-retryable result. Retries
 // may happen if either the proposal was submitted to Raft but did not
@@ -2610,6 +2733,19 @@ func (r *Replica) tryExecuteWriteBatch(
 		lease = status.lease
 	}
 
+	// Prevent the leaseholder from closing timestamps at or above the
+	// timestamp of the write until it is done. This must precede the
+	// timestamp cache check, which forces the write above any timestamp
+	// closed before.
+	if !ba.IsLeaseRequest() {
+		writeTS := ba.Timestamp
+		if ba.Txn != nil {
+			writeTS = ba.Txn.Timestamp
+		}
+		r.beginInFlightWrite(writeTS)
+		defer r.endInFlightWrite(writeTS)
+	}
+
 	// Examine the read and write timestamp caches for preceding
 	// commands which require this command to move its timestamp
 	// forward. Or, in the case of a transactional write, the txn
@@ -2831,6 +2967,9 @@ func (r *Replica) insertProposalLocked(
 	proposal.command.MaxLeaseIndex = r.mu.lastAssignedLeaseIndex
 	proposal.command.ProposerReplica = proposerReplica
 	proposal.command.ProposerLease = proposerLease
+	if !proposal.Request.IsLeaseRequest() {
+		proposal.command.ClosedTimestamp = r.closeTimestampLocked(proposerLease)
+	}
 	if log.V(4) {
 		log.Infof(proposal.ctx, "submitting proposal %x: maxLeaseIndex=%d",
 			proposal.idKey, proposal.command.MaxLeaseIndex)
@@ -4386,6 +4525,9 @@ func (r *Replica) processRaftCommand(
 		}
 
 		pErr = r.maybeSetCorrupt(ctx, pErr)
+		if pErr == nil && forcedErr == nil {
+			r.applyClosedTimestamp(raftCmd.ClosedTimestamp, raftCmd.ProposerLease)
+		}
 		if pErr == nil {
 			pErr = forcedErr
 		}
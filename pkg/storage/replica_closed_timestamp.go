@@ -0,0 +1,159 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// A closed timestamp is a timestamp below which the leaseholder of a range
+// promises not to accept any more writes. The leaseholder keeps the promise
+// by bumping the read timestamp cache over the whole range to the closed
+// timestamp, which forces later writes above it, and by never closing a
+// timestamp above a write which is already past the timestamp cache but not
+// yet proposed. The promise is attached to the commands proposed by the
+// leaseholder (RaftCommand.ClosedTimestamp), so that a follower which has
+// applied such a command has all the writes at or below the closed timestamp
+// and can serve reads at those timestamps without the lease.
+//
+// Timestamps are only closed when the leaseholder proposes commands, which
+// means that followers of ranges which don't receive writes can't serve
+// reads; these are redirected to the leaseholder as usual.
+
+// closedTimestampIncrements is the number of increments the target duration
+// is divided in. The timestamp cache is only bumped when the closed timestamp
+// can advance by at least one increment, so that it isn't bumped on every
+// proposal.
+const closedTimestampIncrements = 10
+
+// beginInFlightWrite registers a write at the given timestamp, which must be
+// a lower bound of the timestamp the write will be proposed at. No timestamp
+// at or above it is closed until the write is unregistered with
+// endInFlightWrite.
+func (r *Replica) beginInFlightWrite(ts hlc.Timestamp) {
+	r.closedTSMu.Lock()
+	defer r.closedTSMu.Unlock()
+	if r.closedTSMu.inFlight == nil {
+		r.closedTSMu.inFlight = make(map[hlc.Timestamp]int)
+	}
+	r.closedTSMu.inFlight[ts]++
+}
+
+// endInFlightWrite unregisters a write registered with beginInFlightWrite.
+func (r *Replica) endInFlightWrite(ts hlc.Timestamp) {
+	r.closedTSMu.Lock()
+	defer r.closedTSMu.Unlock()
+	if r.closedTSMu.inFlight[ts]--; r.closedTSMu.inFlight[ts] <= 0 {
+		delete(r.closedTSMu.inFlight, ts)
+	}
+}
+
+// closeTimestampLocked advances the timestamp closed by this replica under
+// the given lease, if possible, and returns the closed timestamp to attach
+// to a command proposed under that lease. Replica.mu must be held.
+func (r *Replica) closeTimestampLocked(lease roachpb.Lease) hlc.Timestamp {
+	target := storagebase.ClosedTimestampTarget(r.store.cfg.Settings)
+
+	r.closedTSMu.Lock()
+	defer r.closedTSMu.Unlock()
+	if !r.closedTSMu.lease.Equivalent(lease) {
+		// The timestamps closed under a previous lease aren't carried over:
+		// the timestamp cache was reset when the lease was acquired.
+		r.closedTSMu.lease = lease
+		r.closedTSMu.closed = hlc.Timestamp{}
+	}
+	if target == 0 {
+		return r.closedTSMu.closed
+	}
+
+	candidate := r.store.Clock().Now()
+	candidate.WallTime -= target.Nanoseconds()
+	candidate.Logical = 0
+	for ts := range r.closedTSMu.inFlight {
+		if !candidate.Less(ts) {
+			candidate = ts.Prev()
+		}
+	}
+	if candidate.WallTime-r.closedTSMu.closed.WallTime < target.Nanoseconds()/closedTimestampIncrements {
+		return r.closedTSMu.closed
+	}
+
+	r.store.tsCacheMu.Lock()
+	for _, keyRange := range makeReplicatedKeyRanges(r.mu.state.Desc) {
+		r.store.tsCacheMu.cache.add(
+			keyRange.start.Key, keyRange.end.Key, candidate, lowWaterTxnIDMarker, true /* readTSCache */)
+	}
+	r.store.tsCacheMu.Unlock()
+	r.closedTSMu.closed = candidate
+	return candidate
+}
+
+// applyClosedTimestamp records the closed timestamp carried by a command
+// which was just applied, along with the lease under which it was proposed.
+func (r *Replica) applyClosedTimestamp(closed hlc.Timestamp, lease roachpb.Lease) {
+	if closed == (hlc.Timestamp{}) {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.mu.closedTimestampLease.Equivalent(lease) {
+		r.mu.closedTimestampLease = lease
+		r.mu.closedTimestamp = closed
+		return
+	}
+	r.mu.closedTimestamp.Forward(closed)
+}
+
+// resetClosedTimestamp forgets the timestamps closed on this replica. This
+// is called when the replica subsumes another range in a merge, since the
+// timestamps closed for the replica's previous key span don't apply to the
+// keys of the subsumed range. Note that commands proposed before the merge
+// but applied after it still carry a timestamp closed over the previous key
+// span only.
+func (r *Replica) resetClosedTimestamp() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.closedTimestamp = hlc.Timestamp{}
+	r.mu.closedTimestampLease = roachpb.Lease{}
+	r.closedTSMu.Lock()
+	defer r.closedTSMu.Unlock()
+	r.closedTSMu.closed = hlc.Timestamp{}
+	r.closedTSMu.lease = roachpb.Lease{}
+}
+
+// canServeFollowerRead returns whether this replica, which doesn't hold the
+// lease, can serve the given consistent read-only batch because the batch
+// reads at or below the timestamp closed under the current lease.
+func (r *Replica) canServeFollowerRead(ba roachpb.BatchRequest) bool {
+	if storagebase.ClosedTimestampTarget(r.store.cfg.Settings) == 0 {
+		return false
+	}
+	// A transactional read may observe values up to its maximum timestamp.
+	ts := ba.Timestamp
+	if ba.Txn != nil {
+		ts.Forward(ba.Txn.Timestamp)
+		ts.Forward(ba.Txn.MaxTimestamp)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	lease := *r.mu.state.Lease
+	if lease.OwnedBy(r.store.StoreID()) {
+		return false
+	}
+	return r.mu.closedTimestampLease.Equivalent(lease) && !r.mu.closedTimestamp.Less(ts)
+}
@@ -0,0 +1,216 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+)
+
+// TestReplicaCloseTimestamp verifies that the leaseholder closes timestamps
+// trailing the present by the target duration, that it doesn't close
+// timestamps above in-flight writes, and that it forces later writes above
+// the closed timestamp using the timestamp cache.
+func TestReplicaCloseTimestamp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	tc.Start(t, stopper)
+
+	const target = 10 * time.Second
+	storagebase.ClosedTimestampTargetDuration.Override(&tc.store.cfg.Settings.SV, target)
+	lease, _ := tc.repl.getLease()
+	tc.manualClock.Set((100 * time.Second).Nanoseconds())
+
+	closeTimestamp := func() hlc.Timestamp {
+		tc.repl.mu.Lock()
+		defer tc.repl.mu.Unlock()
+		return tc.repl.closeTimestampLocked(lease)
+	}
+
+	closed := closeTimestamp()
+	if expected := (hlc.Timestamp{WallTime: (90 * time.Second).Nanoseconds()}); closed != expected {
+		t.Fatalf("expected closed timestamp %s, got %s", expected, closed)
+	}
+	key := roachpb.Key("a")
+	tc.store.tsCacheMu.Lock()
+	rTS, _, _ := tc.store.tsCacheMu.cache.GetMaxRead(key, nil)
+	tc.store.tsCacheMu.Unlock()
+	if rTS.Less(closed) {
+		t.Fatalf("expected read timestamp cache at or above %s, got %s", closed, rTS)
+	}
+
+	// The closed timestamp only advances by increments of the target.
+	tc.manualClock.Increment((target / closedTimestampIncrements).Nanoseconds() - 1)
+	if ts := closeTimestamp(); ts != closed {
+		t.Fatalf("expected closed timestamp to remain %s, got %s", closed, ts)
+	}
+
+	// An in-flight write holds the closed timestamp back.
+	writeTS := hlc.Timestamp{WallTime: (95 * time.Second).Nanoseconds()}
+	tc.repl.beginInFlightWrite(writeTS)
+	tc.manualClock.Set((110 * time.Second).Nanoseconds())
+	closed = closeTimestamp()
+	if expected := writeTS.Prev(); closed != expected {
+		t.Fatalf("expected closed timestamp %s, got %s", expected, closed)
+	}
+	tc.repl.endInFlightWrite(writeTS)
+	closed = closeTimestamp()
+	if expected := (hlc.Timestamp{WallTime: (100 * time.Second).Nanoseconds()}); closed != expected {
+		t.Fatalf("expected closed timestamp %s, got %s", expected, closed)
+	}
+
+	// Timestamps aren't closed once disabled.
+	storagebase.ClosedTimestampTargetDuration.Override(&tc.store.cfg.Settings.SV, 0)
+	tc.manualClock.Set((200 * time.Second).Nanoseconds())
+	if ts := closeTimestamp(); ts != closed {
+		t.Fatalf("expected closed timestamp to remain %s, got %s", closed, ts)
+	}
+}
+
+// TestReplicaCloseTimestampVersionGate verifies that timestamps aren't closed
+// and follower reads aren't served until the cluster version allows it.
+func TestReplicaCloseTimestampVersionGate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	tc.manualClock = hlc.NewManualClock(123)
+	cfg := TestStoreConfig(hlc.NewClock(tc.manualClock.UnixNano, time.Nanosecond))
+	cfg.Settings = cluster.MakeClusterSettings(cluster.VersionBase, cluster.BinaryServerVersion)
+	if err := cfg.Settings.InitializeVersion(cluster.ClusterVersion{
+		MinimumVersion: cluster.VersionBase,
+		UseVersion:     cluster.VersionSavepoints,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	tc.StartWithStoreConfig(t, stopper, cfg)
+
+	storagebase.ClosedTimestampTargetDuration.Override(&tc.store.cfg.Settings.SV, 10*time.Second)
+	lease, _ := tc.repl.getLease()
+	tc.manualClock.Set((100 * time.Second).Nanoseconds())
+
+	tc.repl.mu.Lock()
+	closed := tc.repl.closeTimestampLocked(lease)
+	tc.repl.mu.Unlock()
+	if closed != (hlc.Timestamp{}) {
+		t.Fatalf("expected no timestamp to be closed, got %s", closed)
+	}
+	var ba roachpb.BatchRequest
+	ba.Timestamp = hlc.Timestamp{WallTime: 1}
+	if tc.repl.canServeFollowerRead(ba) {
+		t.Fatal("expected follower read to be rejected")
+	}
+}
+
+// TestReplicaCanServeFollowerRead verifies that a replica which doesn't hold
+// the lease serves reads at or below the closed timestamp it applied, for as
+// long as the lease under which it was closed remains in effect.
+func TestReplicaCanServeFollowerRead(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	tc.Start(t, stopper)
+
+	storagebase.ClosedTimestampTargetDuration.Override(&tc.store.cfg.Settings.SV, 10*time.Second)
+
+	// Create a replica for testing that is not hooked up to the store, and
+	// give its range a lease held by another store.
+	copy := *tc.repl.Desc()
+	repl, err := NewReplica(&copy, tc.store, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	epoch := int64(1)
+	lease := roachpb.Lease{
+		Start:   hlc.Timestamp{WallTime: 1},
+		Replica: roachpb.ReplicaDescriptor{NodeID: 2, StoreID: 2, ReplicaID: 2},
+		Epoch:   &epoch,
+	}
+	repl.mu.Lock()
+	repl.mu.state.Lease = &lease
+	repl.mu.Unlock()
+
+	closed := hlc.Timestamp{WallTime: 100}
+	readAt := func(ts hlc.Timestamp) roachpb.BatchRequest {
+		var ba roachpb.BatchRequest
+		ba.Timestamp = ts
+		return ba
+	}
+
+	if repl.canServeFollowerRead(readAt(closed)) {
+		t.Fatal("expected follower read to be rejected before any timestamp is closed")
+	}
+	repl.applyClosedTimestamp(closed, lease)
+
+	testCases := []struct {
+		ba       roachpb.BatchRequest
+		expected bool
+	}{
+		{readAt(closed.Prev()), true},
+		{readAt(closed), true},
+		{readAt(closed.Next()), false},
+	}
+	for i, test := range testCases {
+		if can := repl.canServeFollowerRead(test.ba); can != test.expected {
+			t.Errorf("%d: expected %t, got %t", i, test.expected, can)
+		}
+	}
+
+	// A transactional read is rejected if it may observe values above the
+	// closed timestamp.
+	txn := roachpb.MakeTransaction("test", roachpb.Key("a"), 0, 0, closed.Prev(), 0)
+	txn.MaxTimestamp = closed.Next()
+	ba := readAt(closed.Prev())
+	ba.Txn = &txn
+	if repl.canServeFollowerRead(ba) {
+		t.Error("expected transactional read with uncertainty above the closed timestamp to be rejected")
+	}
+
+	// Once the lease changes, the closed timestamp no longer applies.
+	newEpoch := int64(2)
+	newLease := lease
+	newLease.Epoch = &newEpoch
+	repl.mu.Lock()
+	repl.mu.state.Lease = &newLease
+	repl.mu.Unlock()
+	if repl.canServeFollowerRead(readAt(closed.Prev())) {
+		t.Error("expected follower read to be rejected after a lease change")
+	}
+
+	// The leaseholder itself never serves follower reads.
+	ownLease := lease
+	ownLease.Replica = roachpb.ReplicaDescriptor{
+		NodeID: tc.store.Ident.NodeID, StoreID: tc.store.StoreID(), ReplicaID: 1,
+	}
+	repl.mu.Lock()
+	repl.mu.state.Lease = &ownLease
+	repl.mu.Unlock()
+	repl.applyClosedTimestamp(closed, ownLease)
+	if repl.canServeFollowerRead(readAt(closed.Prev())) {
+		t.Error("expected leaseholder not to serve follower reads")
+	}
+}
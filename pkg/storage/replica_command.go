@@ -759,6 +759,9 @@ func evalEndTransaction(
 	if reply.Txn.Priority < h.Txn.Priority {
 		reply.Txn.Priority = h.Txn.Priority
 	}
+	// The requester knows about all the savepoints it rolled back to, so its
+	// ignored sequence numbers are used to resolve the intents.
+	reply.Txn.IgnoredSeqNums = h.Txn.IgnoredSeqNums
 
 	// Take max of supplied txn's timestamp and persisted txn's
 	// timestamp. It may have been pushed by another transaction.
@@ -3216,10 +3219,22 @@ func splitTrigger(
 // comment of "AdminSplit" for more information on this pattern.
 func (r *Replica) AdminMerge(
 	ctx context.Context, args roachpb.AdminMergeRequest,
+) (roachpb.AdminMergeResponse, *roachpb.Error) {
+	return r.adminMerge(ctx, r.Desc(), nil /* expRightDesc */)
+}
+
+// adminMerge implements AdminMerge. The merge only succeeds if the descriptor
+// of this range is still origLeftDesc and, if expRightDesc is not nil, if the
+// descriptor of the right hand side range is still expRightDesc when read by
+// the merge transaction. This lets callers that decided to merge based on a
+// particular view of the two ranges (like the merge queue) make sure that the
+// merge isn't carried out against ranges that were split or otherwise
+// changed in the meantime.
+func (r *Replica) adminMerge(
+	ctx context.Context, origLeftDesc, expRightDesc *roachpb.RangeDescriptor,
 ) (roachpb.AdminMergeResponse, *roachpb.Error) {
 	var reply roachpb.AdminMergeResponse
 
-	origLeftDesc := r.Desc()
 	if origLeftDesc.EndKey.Equal(roachpb.RKeyMax) {
 		// Merging the final range doesn't make sense.
 		return reply, roachpb.NewErrorf("cannot merge final range")
@@ -3234,15 +3249,22 @@ func (r *Replica) AdminMerge(
 	// descriptor end key. We look up the descriptor here only to get
 	// the new end key and then repeat the lookup inside the
 	// transaction.
-	{
-		rightRng := r.store.LookupReplica(origLeftDesc.EndKey, nil)
-		if rightRng == nil {
-			return reply, roachpb.NewErrorf("ranges not collocated")
-		}
-
-		updatedLeftDesc.EndKey = rightRng.Desc().EndKey
-		log.Infof(ctx, "initiating a merge of %s into this range", rightRng)
+	rightRng := r.store.LookupReplica(origLeftDesc.EndKey, nil)
+	if rightRng == nil {
+		return reply, roachpb.NewErrorf("ranges not collocated")
+	}
+	updatedLeftDesc.EndKey = rightRng.Desc().EndKey
+	if expRightDesc != nil {
+		updatedLeftDesc.EndKey = expRightDesc.EndKey
 	}
+	log.Infof(ctx, "initiating a merge of %s into this range", rightRng)
+
+	// Block requests for the user data of the right hand side range for the
+	// duration of the merge, so that the data subsumed by the merge trigger is
+	// the data the merge was decided on. This only has an effect if the local
+	// replica of the right hand side range holds its lease, which the merge
+	// queue makes sure of.
+	defer rightRng.blockRequestsForMerge()()
 
 	if err := r.store.DB().Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
 		log.Event(ctx, "merge closure begins")
@@ -3276,17 +3298,27 @@ func (r *Replica) AdminMerge(
 		}
 		if !bytes.Equal(rightDesc.EndKey, updatedLeftDesc.EndKey) {
 			// This merge raced with a split of the right-hand range.
-			// TODO(bdarnell): needs a test.
 			return errors.Errorf("range changed during merge; %s != %s", rightDesc.EndKey, updatedLeftDesc.EndKey)
 		}
+		if expRightDesc != nil && !rightDesc.Equal(expRightDesc) {
+			return errors.Errorf("range changed during merge; %s != %s", &rightDesc, expRightDesc)
+		}
 		if !replicaSetsEqual(origLeftDesc.Replicas, rightDesc.Replicas) {
 			return errors.Errorf("ranges not collocated")
 		}
 
 		b := txn.NewBatch()
 
-		// Remove the range descriptor for the deleted range.
-		b.Del(rightDescKey)
+		// Remove the range descriptor for the deleted range. Like the update of
+		// the left hand side's descriptor, the deletion is conditional on the
+		// descriptor that was read above, which lays down an intent that makes
+		// concurrent splits and replica changes of the right hand side range
+		// wait for (or abort) the merge.
+		rightDescBytes, err := protoutil.Marshal(&rightDesc)
+		if err != nil {
+			return err
+		}
+		b.CPut(rightDescKey, nil, rightDescBytes)
 
 		if err := mergeRangeAddressing(b, origLeftDesc, &updatedLeftDesc); err != nil {
 			return err
@@ -3896,6 +3928,17 @@ func TestingRelocateRange(
 	db *client.DB,
 	rangeDesc roachpb.RangeDescriptor,
 	targets []roachpb.ReplicationTarget,
+) error {
+	return relocateRange(ctx, db, rangeDesc, targets)
+}
+
+// relocateRange relocates a given range to a given set of stores. The first
+// store in the slice becomes the new leaseholder. See TestingRelocateRange.
+func relocateRange(
+	ctx context.Context,
+	db *client.DB,
+	rangeDesc roachpb.RangeDescriptor,
+	targets []roachpb.ReplicationTarget,
 ) error {
 	// Step 1: Add any stores that don't already have a replica in of the range.
 	//
@@ -0,0 +1,261 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+)
+
+// Ranges are split based on load when the QPS of their leaseholder stays
+// above the kv.range_split.load_qps_threshold setting. Once the QPS goes
+// above the threshold, the leaseholder samples the keys of the requests it
+// receives, and after loadSplitSampleDuration it picks the sampled key which
+// best balances the requests between the two sides of a split and queues the
+// range in the split queue. The QPS is checked at most once per
+// loadSplitQPSCheckInterval, and the samples are discarded as soon as it goes
+// back below the threshold.
+//
+// The leaseholder which carries out a load-based split remembers the split
+// key (see endKeySplitByLoad), and the merge queue merges the two ranges back
+// across that key once their combined QPS is below the threshold, as long as
+// the merged range wouldn't be split again because of its size. Boundaries
+// created in any other way, like manual splits, are never merged back because
+// of load.
+
+const (
+	// loadSplitSampleDuration is how long the keys of the requests received by
+	// a replica are sampled before a split key is picked.
+	loadSplitSampleDuration = 10 * time.Second
+	// loadSplitQPSCheckInterval is the interval at which the QPS of a replica
+	// is checked against the threshold.
+	loadSplitQPSCheckInterval = time.Second
+
+	// splitKeySampleSize is the number of request keys sampled.
+	splitKeySampleSize = 20
+	// splitKeyMinCounter is the minimum number of requests that must have been
+	// counted against a sampled key for it to be picked.
+	splitKeyMinCounter = 100
+	// splitKeyThreshold is the maximum imbalance between the requests to the
+	// left and to the right of a sampled key for it to be picked.
+	splitKeyThreshold = 0.25
+	// splitKeyContainedThreshold is the maximum fraction of the requests
+	// spanning a sampled key for it to be picked.
+	splitKeyContainedThreshold = 0.5
+)
+
+// splitKeySample is a key sampled from the requests received by a replica,
+// along with the number of requests received since then which would fall to
+// its left, to its right, or on both sides of a split at that key.
+type splitKeySample struct {
+	key                    roachpb.RKey
+	left, right, contained int
+}
+
+// splitKeyFinder picks a split key which balances the requests received by a
+// replica. It keeps a uniform sample of the start keys of the requests using
+// reservoir sampling, and counts where each request which isn't sampled
+// falls relative to the sampled keys.
+type splitKeyFinder struct {
+	startTime time.Time
+	count     int
+	samples   [splitKeySampleSize]splitKeySample
+}
+
+func newSplitKeyFinder(startTime time.Time) *splitKeyFinder {
+	return &splitKeyFinder{startTime: startTime}
+}
+
+// record records a request spanning the given keys. intn returns a random
+// integer in [0, n) and is used to pick the sampled keys.
+func (f *splitKeyFinder) record(span roachpb.RSpan, intn func(n int) int) {
+	idx := f.count
+	f.count++
+	if idx >= splitKeySampleSize {
+		if idx = intn(f.count); idx >= splitKeySampleSize {
+			for i := range f.samples {
+				f.samples[i].count(span)
+			}
+			return
+		}
+	}
+	f.samples[idx] = splitKeySample{key: span.Key}
+}
+
+// count counts a request spanning the given keys against the sampled key.
+func (s *splitKeySample) count(span roachpb.RSpan) {
+	switch {
+	case !s.key.Less(span.EndKey):
+		s.left++
+	case !span.Key.Less(s.key):
+		s.right++
+	default:
+		s.contained++
+	}
+}
+
+// key returns the sampled key which best balances the requests between the
+// two sides of a split, or nil if no sampled key balances them well enough.
+func (f *splitKeyFinder) key() roachpb.RKey {
+	var best roachpb.RKey
+	bestScore := math.Inf(1)
+	for _, s := range f.samples {
+		total := s.left + s.right + s.contained
+		if total < splitKeyMinCounter {
+			continue
+		}
+		containedScore := float64(s.contained) / float64(total)
+		if containedScore >= splitKeyContainedThreshold {
+			continue
+		}
+		balanceScore := math.Abs(float64(s.left-s.right)) / float64(s.left+s.right)
+		if balanceScore >= splitKeyThreshold {
+			continue
+		}
+		if score := balanceScore + containedScore; score < bestScore {
+			best = s.key
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// loadBasedQPS returns the QPS of the replica as used for load-based
+// splitting, merging and rebalancing, and whether it was measured for long
+// enough to be used.
+func (r *Replica) loadBasedQPS() (float64, bool) {
+	if r.leaseholderStats == nil {
+		return 0, false
+	}
+	qps, dur := r.leaseholderStats.avgQPS()
+	return qps, dur >= MinStatsDuration
+}
+
+// recordLoadSplitSample records the keys of a batch received by the replica
+// if its QPS is above the load-based splitting threshold, and adds the
+// replica to the split queue once a split key can be picked.
+func (r *Replica) recordLoadSplitSample(ba roachpb.BatchRequest) {
+	st := &r.store.ClusterSettings().SV
+	if !SplitByLoadEnabled.Get(st) {
+		return
+	}
+	now := time.Unix(0, r.store.Clock().PhysicalNow())
+
+	var shouldQueue bool
+	r.loadSplitMu.Lock()
+	if now.Sub(r.loadSplitMu.lastQPSCheck) >= loadSplitQPSCheckInterval {
+		r.loadSplitMu.lastQPSCheck = now
+		qps, ok := r.loadBasedQPS()
+		if f := r.loadSplitMu.finder; !ok || qps < float64(SplitByLoadQPSThreshold.Get(st)) {
+			r.loadSplitMu.finder = nil
+		} else if f == nil {
+			r.loadSplitMu.finder = newSplitKeyFinder(now)
+		} else {
+			shouldQueue = now.Sub(f.startTime) >= loadSplitSampleDuration && f.key() != nil
+		}
+	}
+	if f := r.loadSplitMu.finder; f != nil {
+		if span, err := keys.Range(ba); err == nil {
+			f.record(span, rand.Intn)
+		}
+	}
+	r.loadSplitMu.Unlock()
+
+	if shouldQueue {
+		r.store.splitQueue.MaybeAdd(r, r.store.Clock().Now())
+	}
+}
+
+// loadSplitKey returns the key at which the range should be split to balance
+// its load, or nil if it shouldn't be split based on load.
+func (r *Replica) loadSplitKey(now time.Time) roachpb.Key {
+	desc := r.Desc()
+
+	r.loadSplitMu.Lock()
+	defer r.loadSplitMu.Unlock()
+	f := r.loadSplitMu.finder
+	if f == nil || now.Sub(f.startTime) < loadSplitSampleDuration {
+		return nil
+	}
+	rKey := f.key()
+	if rKey == nil {
+		return nil
+	}
+	// The sampled keys may be in the middle of a SQL row, whose column
+	// families can't be split apart.
+	key, err := keys.EnsureSafeSplitKey(rKey.AsRawKey())
+	if err != nil {
+		return nil
+	}
+	if !containsKey(*desc, key) || desc.StartKey.Equal(key) || !engine.IsValidSplitKey(key) {
+		return nil
+	}
+	return key
+}
+
+// setLoadSplitEndKey records that the range was split at its end key because
+// of its load. Called by the split queue once it has split the range.
+func (r *Replica) setLoadSplitEndKey(key roachpb.RKey) {
+	r.loadSplitMu.Lock()
+	defer r.loadSplitMu.Unlock()
+	r.loadSplitMu.splitEndKey = key
+}
+
+// endKeySplitByLoad returns whether the current end key of the range was
+// created by a load-based split, which the merge queue may then undo. This is
+// only known to the replica which carried out the split, and is carried over
+// to the ranges that end at that key when the range is split or merged on
+// this store. It isn't persisted, so that a load-based split is only undone
+// as long as the leaseholder remains on the same store; otherwise the ranges
+// stay split, like they would after a manual split.
+func (r *Replica) endKeySplitByLoad() bool {
+	desc := r.Desc()
+	r.loadSplitMu.Lock()
+	defer r.loadSplitMu.Unlock()
+	return r.loadSplitMu.splitEndKey != nil && r.loadSplitMu.splitEndKey.Equal(desc.EndKey)
+}
+
+// inheritLoadSplitEndKey carries over the load-based split key recorded by
+// src to r, if it is the end key of r, and clears it from src. It is called
+// when src is split, with the new right hand side range r ending at the end
+// key of src, and when r is merged with src, which it then ends at.
+func (r *Replica) inheritLoadSplitEndKey(src *Replica, endKey roachpb.RKey) {
+	src.loadSplitMu.Lock()
+	key := src.loadSplitMu.splitEndKey
+	src.loadSplitMu.splitEndKey = nil
+	src.loadSplitMu.Unlock()
+
+	r.loadSplitMu.Lock()
+	defer r.loadSplitMu.Unlock()
+	if key != nil && key.Equal(endKey) {
+		r.loadSplitMu.splitEndKey = key
+	} else {
+		r.loadSplitMu.splitEndKey = nil
+	}
+}
+
+// resetLoadSplitSamples discards the keys sampled by the replica. This is
+// called when the range is split or merged, since the samples were taken for
+// its previous bounds.
+func (r *Replica) resetLoadSplitSamples() {
+	r.loadSplitMu.Lock()
+	defer r.loadSplitMu.Unlock()
+	r.loadSplitMu.finder = nil
+}
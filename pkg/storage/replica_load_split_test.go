@@ -0,0 +1,121 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+)
+
+// TestSplitKeyFinder verifies that the split key finder picks a key which
+// balances the sampled requests, and that it doesn't pick a key when no split
+// would balance them.
+func TestSplitKeyFinder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	key := func(i int) roachpb.RKey {
+		return roachpb.RKey(fmt.Sprintf("k%03d", i))
+	}
+	testCases := []struct {
+		name string
+		// keyFn returns the key of the i-th request.
+		keyFn    func(i int, rng *rand.Rand) int
+		min, max int
+	}{
+		{"uniform", func(_ int, rng *rand.Rand) int { return rng.Intn(100) }, 30, 70},
+		{"two keys", func(i int, _ *rand.Rand) int { return 10 + 80*(i%2) }, 90, 90},
+		{"single key", func(int, *rand.Rand) int { return 42 }, -1, -1},
+		{"sequential", func(i int, _ *rand.Rand) int { return i }, -1, -1},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			f := newSplitKeyFinder(time.Time{})
+			for i := 0; i < 1000; i++ {
+				k := key(c.keyFn(i, rng))
+				f.record(roachpb.RSpan{Key: k, EndKey: k.Next()}, rng.Intn)
+			}
+			splitKey := f.key()
+			if c.min < 0 {
+				if splitKey != nil {
+					t.Fatalf("expected no split key, got %s", splitKey)
+				}
+				return
+			}
+			if splitKey == nil || splitKey.Less(key(c.min)) || key(c.max).Less(splitKey) {
+				t.Fatalf("expected split key in [%s, %s], got %s", key(c.min), key(c.max), splitKey)
+			}
+		})
+	}
+}
+
+// TestReplicaLoadSplitKey verifies that a replica picks a load-based split key
+// once its QPS has been above the threshold for long enough, and discards its
+// samples when its QPS goes back below the threshold.
+func TestReplicaLoadSplitKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	tc.Start(t, stopper)
+
+	st := &tc.store.cfg.Settings.SV
+	SplitByLoadEnabled.Override(st, true)
+	SplitByLoadQPSThreshold.Override(st, 10)
+
+	now := func() time.Time {
+		return time.Unix(0, tc.store.Clock().PhysicalNow())
+	}
+	// Send requests alternating between two keys, which are best balanced by
+	// a split at the second key.
+	sendRequests := func(n int) {
+		for i := 0; i < n; i++ {
+			var ba roachpb.BatchRequest
+			ba.Add(&roachpb.GetRequest{Span: roachpb.Span{
+				Key: roachpb.Key(fmt.Sprintf("k%03d", 10+80*(i%2))),
+			}})
+			tc.repl.recordLoadSplitSample(ba)
+		}
+	}
+
+	// Record 100 QPS over 10 seconds.
+	tc.repl.leaseholderStats.resetRequestCounts()
+	tc.manualClock.Increment((10 * time.Second).Nanoseconds())
+	tc.repl.leaseholderStats.recordCount(1000, 1)
+
+	sendRequests(2000)
+	if key := tc.repl.loadSplitKey(now()); key != nil {
+		t.Fatalf("expected no split key before sampling for %s, got %s", loadSplitSampleDuration, key)
+	}
+	tc.manualClock.Increment(loadSplitSampleDuration.Nanoseconds())
+	if key, expected := tc.repl.loadSplitKey(now()), roachpb.Key("k090"); !key.Equal(expected) {
+		t.Fatalf("expected split key %s, got %s", expected, key)
+	}
+
+	// Once the QPS is below the threshold, the samples are discarded.
+	SplitByLoadQPSThreshold.Override(st, 1000)
+	sendRequests(1)
+	if key := tc.repl.loadSplitKey(now()); key != nil {
+		t.Fatalf("expected no split key once below the threshold, got %s", key)
+	}
+}
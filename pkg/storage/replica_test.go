@@ -8794,3 +8794,44 @@ func TestShouldReplicaQuiesce(t *testing.T) {
 		return q
 	})
 }
+
+// TestReplicaBlockRequestsForMerge verifies that requests for the user data
+// of a range wait for all the in-progress merges of the range, and that
+// requests for range-local keys don't.
+func TestReplicaBlockRequestsForMerge(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	tc.Start(t, stopper)
+
+	key := roachpb.Key("a")
+	var localBA, ba roachpb.BatchRequest
+	localBA.Add(&roachpb.GetRequest{Span: roachpb.Span{Key: keys.RangeDescriptorKey(roachpb.RKey(key))}})
+	ba.Add(&roachpb.GetRequest{Span: roachpb.Span{Key: key}})
+
+	releaseFirst := tc.repl.blockRequestsForMerge()
+	releaseSecond := tc.repl.blockRequestsForMerge()
+
+	if pErr := tc.repl.maybeWaitForMerges(context.Background(), localBA); pErr != nil {
+		t.Fatal(pErr)
+	}
+
+	errCh := make(chan *roachpb.Error, 1)
+	go func() {
+		errCh <- tc.repl.maybeWaitForMerges(context.Background(), ba)
+	}()
+
+	// Completing one of the merges must not release the request.
+	releaseFirst()
+	select {
+	case pErr := <-errCh:
+		t.Fatalf("request not blocked by in-progress merge: %v", pErr)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	releaseSecond()
+	if pErr := <-errCh; pErr != nil {
+		t.Fatal(pErr)
+	}
+}
@@ -69,6 +69,12 @@ var (
 	metaReplicateQueueTransferLeaseCount = metric.Metadata{
 		Name: "queue.replicate.transferlease",
 		Help: "Number of range lease transfers attempted by the replicate queue"}
+	metaReplicateQueueQPSRebalanceReplicaCount = metric.Metadata{
+		Name: "queue.replicate.qps.rebalancereplica",
+		Help: "Number of replica rebalancer-initiated additions attempted by the replicate queue while the store was overfull on QPS"}
+	metaReplicateQueueQPSTransferLeaseCount = metric.Metadata{
+		Name: "queue.replicate.qps.transferlease",
+		Help: "Number of range lease transfers attempted by the replicate queue while the store was overfull on QPS"}
 )
 
 // ReplicateQueueMetrics is the set of metrics for the replicate queue.
@@ -78,6 +84,9 @@ type ReplicateQueueMetrics struct {
 	RemoveDeadReplicaCount *metric.Counter
 	RebalanceReplicaCount  *metric.Counter
 	TransferLeaseCount     *metric.Counter
+
+	QPSRebalanceReplicaCount *metric.Counter
+	QPSTransferLeaseCount    *metric.Counter
 }
 
 func makeReplicateQueueMetrics() ReplicateQueueMetrics {
@@ -87,6 +96,9 @@ func makeReplicateQueueMetrics() ReplicateQueueMetrics {
 		RemoveDeadReplicaCount: metric.NewCounter(metaReplicateQueueRemoveDeadReplicaCount),
 		RebalanceReplicaCount:  metric.NewCounter(metaReplicateQueueRebalanceReplicaCount),
 		TransferLeaseCount:     metric.NewCounter(metaReplicateQueueTransferLeaseCount),
+
+		QPSRebalanceReplicaCount: metric.NewCounter(metaReplicateQueueQPSRebalanceReplicaCount),
+		QPSTransferLeaseCount:    metric.NewCounter(metaReplicateQueueQPSTransferLeaseCount),
 	}
 }
 
@@ -187,7 +199,7 @@ func (rq *replicateQueue) shouldQueue(
 	if lease, _ := repl.getLease(); repl.IsLeaseValid(lease, now) {
 		if rq.canTransferLease() &&
 			rq.allocator.ShouldTransferLease(
-				ctx, zone.Constraints, desc.Replicas, lease.Replica.StoreID, desc.RangeID, repl.leaseholderStats) {
+				ctx, zone, desc.Replicas, lease.Replica.StoreID, desc.RangeID, repl.leaseholderStats) {
 			log.VEventf(ctx, 2, "lease transfer needed, enqueuing")
 			return true, 0
 		}
@@ -494,6 +506,9 @@ func (rq *replicateQueue) processOneChange(
 					StoreID: rebalanceStore.StoreID,
 				}
 				rq.metrics.RebalanceReplicaCount.Inc(1)
+				if rq.allocator.storeOverfullOnQPS(zone.Constraints, rq.store.StoreID(), desc.RangeID) {
+					rq.metrics.QPSRebalanceReplicaCount.Inc(1)
+				}
 				log.VEventf(ctx, 1, "rebalancing to %+v: %s",
 					rebalanceReplica, rangeRaftProgress(repl.RaftStatus(), desc.Replicas))
 				if err := rq.addReplica(
@@ -536,7 +551,7 @@ func (rq *replicateQueue) transferLease(
 	candidates := filterBehindReplicas(repl.RaftStatus(), desc.Replicas, 0 /* brandNewReplicaID */)
 	if target := rq.allocator.TransferLeaseTarget(
 		ctx,
-		zone.Constraints,
+		zone,
 		candidates,
 		repl.store.StoreID(),
 		desc.RangeID,
@@ -546,6 +561,9 @@ func (rq *replicateQueue) transferLease(
 		false, /* !alwaysAllowDecisionWithoutStats */
 	); target != (roachpb.ReplicaDescriptor{}) {
 		rq.metrics.TransferLeaseCount.Inc(1)
+		if rq.allocator.storeOverfullOnQPS(zone.Constraints, repl.store.StoreID(), desc.RangeID) {
+			rq.metrics.QPSTransferLeaseCount.Inc(1)
+		}
 		log.VEventf(ctx, 1, "transferring lease to s%d", target.StoreID)
 		if opts.dryRun {
 			return false, nil
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 )
 
@@ -32,8 +33,30 @@ const (
 	splitQueueTimerDuration = 0 // zero duration to process splits greedily.
 )
 
-// splitQueue manages a queue of ranges slated to be split due to size
-// or along intersecting zone config boundaries.
+// SplitByLoadEnabled controls whether ranges are split based on the requests
+// they receive, in addition to their size.
+var SplitByLoadEnabled = settings.RegisterBoolSetting(
+	"kv.range_split.by_load_enabled",
+	"allow automatic splits of ranges based on where load is concentrated",
+	false,
+)
+
+// SplitByLoadQPSThreshold is the QPS above which ranges are split based on
+// load, if SplitByLoadEnabled is set.
+var SplitByLoadQPSThreshold = settings.RegisterValidatedIntSetting(
+	"kv.range_split.load_qps_threshold",
+	"the QPS over which a range becomes a candidate for load based splitting",
+	250,
+	func(v int64) error {
+		if v <= 0 {
+			return errors.Errorf("cannot set kv.range_split.load_qps_threshold to a non-positive value: %d", v)
+		}
+		return nil
+	},
+)
+
+// splitQueue manages a queue of ranges slated to be split due to size,
+// load or along intersecting zone config boundaries.
 type splitQueue struct {
 	*baseQueue
 	db *client.DB
@@ -62,7 +85,9 @@ func newSplitQueue(store *Store, db *client.DB, gossip *gossip.Gossip) *splitQue
 
 // shouldQueue determines whether a range should be queued for
 // splitting. This is true if the range is intersected by a zone config
-// prefix or if the range's size in bytes exceeds the limit for the zone.
+// prefix, if the range's size in bytes exceeds the limit for the zone or if
+// the range's QPS has been above the load-based splitting threshold for long
+// enough to pick a split key.
 func (sq *splitQueue) shouldQueue(
 	ctx context.Context, now hlc.Timestamp, repl *Replica, sysCfg config.SystemConfig,
 ) (shouldQ bool, priority float64) {
@@ -79,6 +104,12 @@ func (sq *splitQueue) shouldQueue(
 		priority += ratio
 		shouldQ = true
 	}
+
+	// Add priority for ranges which should be split based on load.
+	if repl.loadSplitKey(now.GoTime()) != nil {
+		priority++
+		shouldQ = true
+	}
 	return
 }
 
@@ -102,6 +133,25 @@ func (sq *splitQueue) process(ctx context.Context, r *Replica, sysCfg config.Sys
 		return nil
 	}
 
+	// Next handle case of splitting due to load.
+	if splitKey := r.loadSplitKey(sq.store.Clock().Now().GoTime()); splitKey != nil {
+		if _, _, pErr := r.adminSplitWithDescriptor(
+			ctx,
+			roachpb.AdminSplitRequest{
+				Span: roachpb.Span{
+					Key: splitKey,
+				},
+				SplitKey: splitKey,
+			},
+			desc,
+		); pErr != nil {
+			return errors.Wrapf(pErr.GoError(), "unable to split %s at key %q", r, splitKey)
+		}
+		// Let the merge queue undo the split once the load goes away.
+		r.setLoadSplitEndKey(roachpb.RKey(splitKey))
+		return nil
+	}
+
 	// Next handle case of splitting due to size. Note that we don't perform
 	// size-based splitting if maxBytes is 0 (happens in certain test
 	// situations).
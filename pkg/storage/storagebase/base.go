@@ -15,10 +15,41 @@
 package storagebase
 
 import (
+	"time"
+
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"golang.org/x/net/context"
 )
 
+// ClosedTimestampTargetDuration is how far behind the present leaseholders
+// try to close timestamps, that is, promise not to accept writes under. Reads
+// below a closed timestamp can be served by any replica of the range. It is
+// read by both the leaseholders, which close timestamps, and the DistSender,
+// which routes sufficiently old reads to the nearest replica.
+//
+// Closing timestamps bumps the timestamp cache over the whole range, which
+// forces long-running transactions writing to it to restart, so it is
+// disabled by default.
+var ClosedTimestampTargetDuration = settings.RegisterNonNegativeDurationSetting(
+	"kv.closed_timestamp.target_duration",
+	"if nonzero, attempt to close timestamps trailing the present by approximately this "+
+		"duration, which allows follower replicas to serve reads below them",
+	0,
+)
+
+// ClosedTimestampTarget returns the duration by which closed timestamps trail
+// the present, or zero if timestamps aren't closed, either because of
+// ClosedTimestampTargetDuration or because the cluster version doesn't allow
+// it yet.
+func ClosedTimestampTarget(st *cluster.Settings) time.Duration {
+	if !st.Version.IsActive(cluster.VersionClosedTimestamps) {
+		return 0
+	}
+	return ClosedTimestampTargetDuration.Get(&st.SV)
+}
+
 // CmdIDKey is a Raft command id.
 type CmdIDKey string
 
@@ -352,6 +352,7 @@ type Store struct {
 	rangeIDAlloc       *idAllocator                // Range ID allocator
 	gcQueue            *gcQueue                    // Garbage collection queue
 	splitQueue         *splitQueue                 // Range splitting queue
+	mergeQueue         *mergeQueue                 // Range merging queue
 	replicateQueue     *replicateQueue             // Replication queue
 	replicaGCQueue     *replicaGCQueue             // Replica GC queue
 	raftLogQueue       *raftLogQueue               // Raft log truncation queue
@@ -369,10 +370,12 @@ type Store struct {
 	// gossip interval. Updated atomically.
 	gossipRangeCountdown int32
 	gossipLeaseCountdown int32
-	// gossipWritesPerSecondVal serves a similar purpose, but simply records
-	// the most recently gossiped value so that we can tell if a newly measured
-	// value differs by enough to justify re-gossiping the store.
-	gossipWritesPerSecondVal syncutil.AtomicFloat64
+	// gossipWritesPerSecondVal and gossipQueriesPerSecondVal serve a similar
+	// purpose, but simply record the most recently gossiped values so that we
+	// can tell if newly measured values differ by enough to justify
+	// re-gossiping the store.
+	gossipWritesPerSecondVal  syncutil.AtomicFloat64
+	gossipQueriesPerSecondVal syncutil.AtomicFloat64
 
 	coalescedMu struct {
 		syncutil.Mutex
@@ -725,6 +728,8 @@ type StoreTestingKnobs struct {
 	DisableReplicaRebalancing bool
 	// DisableSplitQueue disables the split queue.
 	DisableSplitQueue bool
+	// DisableMergeQueue disables the merge queue.
+	DisableMergeQueue bool
 	// DisableTimeSeriesMaintenanceQueue disables the time series maintenance
 	// queue.
 	DisableTimeSeriesMaintenanceQueue bool
@@ -880,13 +885,14 @@ func NewStore(cfg StoreConfig, eng engine.Engine, nodeDesc *roachpb.NodeDescript
 		)
 		s.gcQueue = newGCQueue(s, s.cfg.Gossip)
 		s.splitQueue = newSplitQueue(s, s.db, s.cfg.Gossip)
+		s.mergeQueue = newMergeQueue(s, s.db, s.cfg.Gossip)
 		s.replicateQueue = newReplicateQueue(s, s.cfg.Gossip, s.allocator, s.cfg.Clock)
 		s.replicaGCQueue = newReplicaGCQueue(s, s.db, s.cfg.Gossip)
 		s.raftLogQueue = newRaftLogQueue(s, s.db, s.cfg.Gossip)
 		s.raftSnapshotQueue = newRaftSnapshotQueue(s, s.cfg.Gossip, s.cfg.Clock)
 		s.consistencyQueue = newConsistencyQueue(s, s.cfg.Gossip)
 		s.scanner.AddQueues(
-			s.gcQueue, s.splitQueue, s.replicateQueue, s.replicaGCQueue,
+			s.gcQueue, s.splitQueue, s.mergeQueue, s.replicateQueue, s.replicaGCQueue,
 			s.raftLogQueue, s.raftSnapshotQueue, s.consistencyQueue)
 
 		if s.cfg.TimeSeriesDataStore != nil {
@@ -906,6 +912,9 @@ func NewStore(cfg StoreConfig, eng engine.Engine, nodeDesc *roachpb.NodeDescript
 	if cfg.TestingKnobs.DisableSplitQueue {
 		s.setSplitQueueActive(false)
 	}
+	if cfg.TestingKnobs.DisableMergeQueue {
+		s.setMergeQueueActive(false)
+	}
 	if cfg.TestingKnobs.DisableTimeSeriesMaintenanceQueue {
 		s.setTimeSeriesMaintenanceQueueActive(false)
 	}
@@ -1354,6 +1363,7 @@ func (s *Store) GossipStore(ctx context.Context) error {
 	leaseCountdown := float64(storeDesc.Capacity.LeaseCount) * s.cfg.GossipWhenCapacityDeltaExceedsFraction
 	atomic.StoreInt32(&s.gossipLeaseCountdown, int32(math.Ceil(math.Max(leaseCountdown, 1))))
 	syncutil.StoreFloat64(&s.gossipWritesPerSecondVal, storeDesc.Capacity.WritesPerSecond)
+	syncutil.StoreFloat64(&s.gossipQueriesPerSecondVal, storeDesc.Capacity.QueriesPerSecond)
 
 	// Unique gossip key per store.
 	gossipStoreKey := gossip.MakeStoreKey(storeDesc.StoreID)
@@ -1423,25 +1433,29 @@ func (s *Store) maybeGossipOnCapacityChange(ctx context.Context, cce capacityCha
 	}
 }
 
-// recordNewWritesPerSecond takes a recently calculated value for the number
-// of key writes the store is handling and decides whether it has changed enough
-// to justify re-gossiping the store's capacity.
-func (s *Store) recordNewWritesPerSecond(newVal float64) {
-	oldVal := syncutil.LoadFloat64(&s.gossipWritesPerSecondVal)
-	if oldVal == -1 {
+// recordNewPerSecondStats takes recently calculated values for the number of
+// key writes and of queries the store is handling and decides whether either
+// has changed enough to justify re-gossiping the store's capacity.
+func (s *Store) recordNewPerSecondStats(newWPS, newQPS float64) {
+	oldWPS := syncutil.LoadFloat64(&s.gossipWritesPerSecondVal)
+	if oldWPS == -1 {
 		// Gossiping of store capacity is already ongoing.
 		return
 	}
-	if newVal < oldVal*.5 || newVal > oldVal*1.5 {
+	oldQPS := syncutil.LoadFloat64(&s.gossipQueriesPerSecondVal)
+	changed := func(oldVal, newVal float64) bool {
+		return newVal < oldVal*.5 || newVal > oldVal*1.5
+	}
+	if changed(oldWPS, newWPS) || changed(oldQPS, newQPS) {
 		ctx := s.AnnotateCtx(context.TODO())
 		if err := s.stopper.RunAsyncTask(
-			ctx, "storage.Store: gossip on writes-per-second change",
+			ctx, "storage.Store: gossip on per-second stats change",
 			func(ctx context.Context) {
 				if err := s.GossipStore(ctx); err != nil {
-					log.Warningf(ctx, "error gossiping on writes-per-second change: %s", err)
+					log.Warningf(ctx, "error gossiping on per-second stats change: %s", err)
 				}
 			}); err != nil {
-			log.Warningf(ctx, "unable to gossip on writes-per-second change: %s", err)
+			log.Warningf(ctx, "unable to gossip on per-second stats change: %s", err)
 		}
 	}
 }
@@ -1940,6 +1954,8 @@ func (s *Store) SplitRange(ctx context.Context, origRng, newRng *Replica) error
 	// spans that are now owned by the new range.
 	origRng.leaseholderStats.resetRequestCounts()
 	origRng.writeStats.splitRequestCounts(newRng.writeStats)
+	origRng.resetLoadSplitSamples()
+	newRng.inheritLoadSplitEndKey(origRng, newDesc.EndKey)
 
 	if kr := s.mu.replicasByKey.ReplaceOrInsert(origRng); kr != nil {
 		return errors.Errorf("replicasByKey unexpectedly contains %s when inserting replica %s", kr, origRng)
@@ -2001,6 +2017,9 @@ func (s *Store) MergeRange(
 		// logic that depends on them.
 		subsumingRng.writeStats.resetRequestCounts()
 	}
+	subsumingRng.resetClosedTimestamp()
+	subsumingRng.resetLoadSplitSamples()
+	subsumingRng.inheritLoadSplitEndKey(subsumedRng, updatedEndKey)
 
 	if err := s.maybeMergeTimestampCaches(ctx, subsumingRng, subsumedRng); err != nil {
 		return err
@@ -2300,11 +2319,15 @@ func (s *Store) Capacity() (roachpb.StoreCapacity, error) {
 	var leaseCount int32
 	var logicalBytes int64
 	var totalWritesPerSecond float64
+	var totalQueriesPerSecond float64
 	bytesPerReplica := make([]float64, 0, capacity.RangeCount)
 	writesPerReplica := make([]float64, 0, capacity.RangeCount)
 	newStoreReplicaVisitor(s).Visit(func(r *Replica) bool {
 		if r.OwnsValidLease(now) {
 			leaseCount++
+			if qps, ok := r.loadBasedQPS(); ok {
+				totalQueriesPerSecond += qps
+			}
 		}
 		mvccStats := r.GetMVCCStats()
 		logicalBytes += mvccStats.Total()
@@ -2321,9 +2344,10 @@ func (s *Store) Capacity() (roachpb.StoreCapacity, error) {
 	capacity.LeaseCount = leaseCount
 	capacity.LogicalBytes = logicalBytes
 	capacity.WritesPerSecond = totalWritesPerSecond
+	capacity.QueriesPerSecond = totalQueriesPerSecond
 	capacity.BytesPerReplica = roachpb.PercentilesFromData(bytesPerReplica)
 	capacity.WritesPerReplica = roachpb.PercentilesFromData(writesPerReplica)
-	s.recordNewWritesPerSecond(totalWritesPerSecond)
+	s.recordNewPerSecondStats(totalWritesPerSecond, totalQueriesPerSecond)
 
 	return capacity, nil
 }
@@ -4007,6 +4031,7 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 		raftLeaderNotLeaseHolderCount int64
 		quiescentCount                int64
 		averageWritesPerSecond        float64
+		averageQueriesPerSecond       float64
 
 		rangeCount                int64
 		unavailableRangeCount     int64
@@ -4031,6 +4056,9 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 		}
 		if metrics.Leaseholder {
 			leaseHolderCount++
+			if qps, ok := rep.loadBasedQPS(); ok {
+				averageQueriesPerSecond += qps
+			}
 			switch metrics.LeaseType {
 			case roachpb.LeaseNone:
 			case roachpb.LeaseExpiration:
@@ -4066,7 +4094,8 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 	s.metrics.LeaseEpochCount.Update(leaseEpochCount)
 	s.metrics.QuiescentCount.Update(quiescentCount)
 	s.metrics.AverageWritesPerSecond.Update(averageWritesPerSecond)
-	s.recordNewWritesPerSecond(averageWritesPerSecond)
+	s.metrics.AverageQueriesPerSecond.Update(averageQueriesPerSecond)
+	s.recordNewPerSecondStats(averageWritesPerSecond, averageQueriesPerSecond)
 
 	s.metrics.RangeCount.Update(rangeCount)
 	s.metrics.UnavailableRangeCount.Update(unavailableRangeCount)
@@ -4258,6 +4287,9 @@ func (s *Store) setReplicateQueueActive(active bool) {
 func (s *Store) setSplitQueueActive(active bool) {
 	s.splitQueue.SetDisabled(!active)
 }
+func (s *Store) setMergeQueueActive(active bool) {
+	s.mergeQueue.SetDisabled(!active)
+}
 func (s *Store) setTimeSeriesMaintenanceQueueActive(active bool) {
 	s.tsMaintenanceQueue.SetDisabled(!active)
 }
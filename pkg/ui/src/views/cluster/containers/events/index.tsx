@@ -56,6 +56,7 @@ export function getEventInfo(e: Event$Properties): SimplifiedEvent {
     TableName: string,
     User: string,
     ViewName: string,
+    SequenceName: string,
     SettingName: string,
     Value: string,
   } = protobuf.util.isset(e, "info") ? JSON.parse(e.info) : {};
@@ -97,6 +98,15 @@ export function getEventInfo(e: Event$Properties): SimplifiedEvent {
     case eventTypes.DROP_VIEW:
       content = <span>View Dropped: User {info.User} dropped view {info.ViewName}</span>;
       break;
+    case eventTypes.CREATE_SEQUENCE:
+      content = <span>Sequence Created: User {info.User} created sequence {info.SequenceName}</span>;
+      break;
+    case eventTypes.ALTER_SEQUENCE:
+      content = <span>Sequence Altered: User {info.User} altered sequence {info.SequenceName}</span>;
+      break;
+    case eventTypes.DROP_SEQUENCE:
+      content = <span>Sequence Dropped: User {info.User} dropped sequence {info.SequenceName}</span>;
+      break;
     case eventTypes.REVERSE_SCHEMA_CHANGE:
       content = <span>Schema Change Reversed: Schema change with ID {info.MutationID} was reversed.</span>;
       break;
@@ -69,6 +69,17 @@ const (
 	decimalNaNDesc          = decimalInfinity + 1 // NaN encoded descendingly
 	decimalTerminator       = 0x00
 
+	// Markers used in the keys of JSON inverted indexes. Object keys and
+	// array elements are the components of the path to a value; the
+	// remaining markers are used for the values themselves, along with
+	// the regular encodings of NULL, strings and decimals.
+	jsonObjectKeyMarker  = decimalNaNDesc + 1
+	jsonArrayMarker      = jsonObjectKeyMarker + 1
+	jsonFalseMarker      = jsonArrayMarker + 1
+	jsonTrueMarker       = jsonFalseMarker + 1
+	jsonEmptyArrayMarker = jsonTrueMarker + 1
+	jsonEmptyObjMarker   = jsonEmptyArrayMarker + 1
+
 	// IntMin is chosen such that the range of int tags does not overlap the
 	// ascii character set that is frequently used in testing.
 	IntMin      = 0x80
@@ -694,6 +705,68 @@ func DecodeIfNotNull(b []byte) ([]byte, bool) {
 	return b, false
 }
 
+// EncodeJSONObjectKeyAscending encodes the key of a JSON object as a
+// component of the path to a value in a JSON inverted index key. The
+// key uses the same escaping as EncodeStringAscending but a distinct
+// marker, so that it cannot be confused with a string value.
+func EncodeJSONObjectKeyAscending(b []byte, key string) []byte {
+	n := len(b)
+	b = EncodeStringAscending(b, key)
+	b[n] = jsonObjectKeyMarker
+	return b
+}
+
+// EncodeJSONArrayAscending encodes an array step as a component of the path
+// to a value in a JSON inverted index key.
+func EncodeJSONArrayAscending(b []byte) []byte {
+	return append(b, jsonArrayMarker)
+}
+
+// EncodeJSONTrueAscending encodes a JSON true value in a JSON inverted index
+// key.
+func EncodeJSONTrueAscending(b []byte) []byte {
+	return append(b, jsonTrueMarker)
+}
+
+// EncodeJSONFalseAscending encodes a JSON false value in a JSON inverted
+// index key.
+func EncodeJSONFalseAscending(b []byte) []byte {
+	return append(b, jsonFalseMarker)
+}
+
+// EncodeJSONEmptyArrayAscending encodes an empty JSON array in a JSON
+// inverted index key.
+func EncodeJSONEmptyArrayAscending(b []byte) []byte {
+	return append(b, jsonEmptyArrayMarker)
+}
+
+// EncodeJSONEmptyObjectAscending encodes an empty JSON object in a JSON
+// inverted index key.
+func EncodeJSONEmptyObjectAscending(b []byte) []byte {
+	return append(b, jsonEmptyObjMarker)
+}
+
+// PeekJSONInvertedIndexKeyLength returns the length of the JSON inverted
+// index key at the start of b, that is of the path components followed by
+// the encoded value they lead to.
+func PeekJSONInvertedIndexKeyLength(b []byte) (int, error) {
+	n := 0
+	for {
+		if n >= len(b) {
+			return 0, errors.Errorf("malformed JSON inverted index key %#x", b)
+		}
+		m := b[n]
+		l, err := PeekLength(b[n:])
+		if err != nil {
+			return 0, err
+		}
+		n += l
+		if m != jsonObjectKeyMarker && m != jsonArrayMarker {
+			return n, nil
+		}
+	}
+}
+
 // EncodeTimeAscending encodes a time value, appends it to the supplied buffer,
 // and returns the final buffer. The encoding is guaranteed to be ordered
 // Such that if t1.Before(t2) then after EncodeTime(b1, t1), and
@@ -864,6 +937,7 @@ const (
 	// Do not change SentinelType from 15. This value is specifically used for bit
 	// manipulation in EncodeValueTag.
 	SentinelType Type = 15 // Used in the Value encoding.
+	JSON         Type = 16
 )
 
 // PeekType peeks at the type of the value encoded at the start of b.
@@ -941,6 +1015,10 @@ func PeekLength(b []byte) (int, error) {
 		return GetMultiVarintLen(b, 2)
 	case durationBigNegMarker, durationMarker, durationBigPosMarker:
 		return GetMultiVarintLen(b, 3)
+	case jsonObjectKeyMarker:
+		return getBytesLength(b, ascendingEscapes)
+	case jsonArrayMarker, jsonFalseMarker, jsonTrueMarker, jsonEmptyArrayMarker, jsonEmptyObjMarker:
+		return 1, nil
 	case floatNeg, floatPos:
 		// the marker is followed by 8 bytes
 		if len(b) < 9 {
@@ -1234,6 +1312,13 @@ func EncodeArrayValue(appendTo []byte, colID uint32, data []byte) []byte {
 	return EncodeUntaggedBytesValue(appendTo, data)
 }
 
+// EncodeJSONValue encodes an already-encoded JSON value with its value tag,
+// appends it to the supplied buffer, and returns the final buffer.
+func EncodeJSONValue(appendTo []byte, colID uint32, data []byte) []byte {
+	appendTo = EncodeValueTag(appendTo, colID, JSON)
+	return EncodeUntaggedBytesValue(appendTo, data)
+}
+
 // EncodeTimeValue encodes a time.Time value with its value tag, appends it to
 // the supplied buffer, and returns the final buffer.
 func EncodeTimeValue(appendTo []byte, colID uint32, t time.Time) []byte {
@@ -1430,6 +1515,15 @@ func DecodeUntaggedBytesValue(b []byte) (remaining, data []byte, err error) {
 	return b[int(i):], b[:int(i)], nil
 }
 
+// DecodeJSONValue decodes a value encoded by EncodeJSONValue.
+func DecodeJSONValue(b []byte) (remaining []byte, data []byte, err error) {
+	b, err = decodeValueTypeAssert(b, JSON)
+	if err != nil {
+		return b, nil, err
+	}
+	return DecodeUntaggedBytesValue(b)
+}
+
 // DecodeTimeValue decodes a value encoded by EncodeTimeValue.
 func DecodeTimeValue(b []byte) (remaining []byte, t time.Time, err error) {
 	b, err = decodeValueTypeAssert(b, Time)
@@ -1580,7 +1674,7 @@ func PeekValueLength(b []byte) (typeOffset int, length int, err error) {
 		return typeOffset, dataOffset + n, err
 	case Float:
 		return typeOffset, dataOffset + floatValueEncodedLength, nil
-	case Bytes, Array:
+	case Bytes, Array, JSON:
 		_, n, i, err := DecodeNonsortingUvarint(b)
 		return typeOffset, dataOffset + n + int(i), err
 	case Decimal:
@@ -1717,6 +1811,13 @@ func PrettyPrintValueEncoded(b []byte) ([]byte, string, error) {
 			return b, "", err
 		}
 		return b, ipAddr.String(), nil
+	case JSON:
+		var data []byte
+		b, data, err = DecodeJSONValue(b)
+		if err != nil {
+			return b, "", err
+		}
+		return b, hex.EncodeToString(data), nil
 	default:
 		return b, "", errors.Errorf("unknown type %s", typ)
 	}
@@ -0,0 +1,179 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package json
+
+import (
+	"github.com/cockroachdb/apd"
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// EncodeJSON appends the encoding of a JSON document to appendTo. Each
+// node of the document is encoded as its Type followed by its contents:
+// the length-prefixed bytes of a string, the non-sorting encoding of a
+// number, or the number of elements of an array or pairs of an object
+// followed by the elements or the length-prefixed keys and values.
+func EncodeJSON(appendTo []byte, j JSON) []byte {
+	return j.encode(appendTo)
+}
+
+// DecodeJSON decodes a JSON document encoded with EncodeJSON.
+func DecodeJSON(b []byte) (remaining []byte, j JSON, err error) {
+	if len(b) == 0 {
+		return nil, nil, errors.New("insufficient bytes to decode JSON value")
+	}
+	typ := Type(b[0])
+	b = b[1:]
+	switch typ {
+	case NullJSONType:
+		return b, NullJSONValue, nil
+	case FalseJSONType:
+		return b, FalseJSONValue, nil
+	case TrueJSONType:
+		return b, TrueJSONValue, nil
+	case StringJSONType:
+		var data []byte
+		b, data, err = encoding.DecodeUntaggedBytesValue(b)
+		if err != nil {
+			return b, nil, err
+		}
+		return b, jsonString(data), nil
+	case NumberJSONType:
+		var d apd.Decimal
+		b, d, err = encoding.DecodeUntaggedDecimalValue(b)
+		if err != nil {
+			return b, nil, err
+		}
+		return b, FromDecimal(d), nil
+	case ArrayJSONType:
+		var n uint64
+		b, _, n, err = encoding.DecodeNonsortingUvarint(b)
+		if err != nil {
+			return b, nil, err
+		}
+		elems := make(jsonArray, n)
+		for i := range elems {
+			b, elems[i], err = DecodeJSON(b)
+			if err != nil {
+				return b, nil, err
+			}
+		}
+		return b, elems, nil
+	case ObjectJSONType:
+		var n uint64
+		b, _, n, err = encoding.DecodeNonsortingUvarint(b)
+		if err != nil {
+			return b, nil, err
+		}
+		obj := make(jsonObject, n)
+		for i := range obj {
+			var k []byte
+			b, k, err = encoding.DecodeUntaggedBytesValue(b)
+			if err != nil {
+				return b, nil, err
+			}
+			obj[i].k = jsonString(k)
+			b, obj[i].v, err = DecodeJSON(b)
+			if err != nil {
+				return b, nil, err
+			}
+		}
+		return b, obj, nil
+	}
+	return b, nil, errors.Errorf("unknown JSON type %d", typ)
+}
+
+func (j jsonNull) encode(appendTo []byte) []byte  { return append(appendTo, byte(NullJSONType)) }
+func (j jsonFalse) encode(appendTo []byte) []byte { return append(appendTo, byte(FalseJSONType)) }
+func (j jsonTrue) encode(appendTo []byte) []byte  { return append(appendTo, byte(TrueJSONType)) }
+
+func (j jsonString) encode(appendTo []byte) []byte {
+	appendTo = append(appendTo, byte(StringJSONType))
+	return encoding.EncodeUntaggedBytesValue(appendTo, []byte(j))
+}
+
+func (j *jsonNumber) encode(appendTo []byte) []byte {
+	appendTo = append(appendTo, byte(NumberJSONType))
+	d := apd.Decimal(*j)
+	return encoding.EncodeUntaggedDecimalValue(appendTo, &d)
+}
+
+func (j jsonArray) encode(appendTo []byte) []byte {
+	appendTo = append(appendTo, byte(ArrayJSONType))
+	appendTo = encoding.EncodeNonsortingUvarint(appendTo, uint64(len(j)))
+	for i := range j {
+		appendTo = j[i].encode(appendTo)
+	}
+	return appendTo
+}
+
+func (j jsonObject) encode(appendTo []byte) []byte {
+	appendTo = append(appendTo, byte(ObjectJSONType))
+	appendTo = encoding.EncodeNonsortingUvarint(appendTo, uint64(len(j)))
+	for i := range j {
+		appendTo = encoding.EncodeUntaggedBytesValue(appendTo, []byte(j[i].k))
+		appendTo = j[i].v.encode(appendTo)
+	}
+	return appendTo
+}
+
+// The prefix passed to encodeInvertedIndexKeys is the encoded path to the
+// document; it always has its capacity limited to its length so that
+// appending to it makes a copy.
+
+func (j jsonNull) encodeInvertedIndexKeys(prefix []byte, keys [][]byte) [][]byte {
+	return append(keys, encoding.EncodeNullAscending(prefix))
+}
+
+func (j jsonFalse) encodeInvertedIndexKeys(prefix []byte, keys [][]byte) [][]byte {
+	return append(keys, encoding.EncodeJSONFalseAscending(prefix))
+}
+
+func (j jsonTrue) encodeInvertedIndexKeys(prefix []byte, keys [][]byte) [][]byte {
+	return append(keys, encoding.EncodeJSONTrueAscending(prefix))
+}
+
+func (j jsonString) encodeInvertedIndexKeys(prefix []byte, keys [][]byte) [][]byte {
+	return append(keys, encoding.EncodeStringAscending(prefix, string(j)))
+}
+
+func (j *jsonNumber) encodeInvertedIndexKeys(prefix []byte, keys [][]byte) [][]byte {
+	d := apd.Decimal(*j)
+	return append(keys, encoding.EncodeDecimalAscending(prefix, &d))
+}
+
+func (j jsonArray) encodeInvertedIndexKeys(prefix []byte, keys [][]byte) [][]byte {
+	if len(j) == 0 {
+		return append(keys, encoding.EncodeJSONEmptyArrayAscending(prefix))
+	}
+	path := encoding.EncodeJSONArrayAscending(prefix)
+	path = path[:len(path):len(path)]
+	for i := range j {
+		keys = j[i].encodeInvertedIndexKeys(path, keys)
+	}
+	return keys
+}
+
+func (j jsonObject) encodeInvertedIndexKeys(prefix []byte, keys [][]byte) [][]byte {
+	if len(j) == 0 {
+		return append(keys, encoding.EncodeJSONEmptyObjectAscending(prefix))
+	}
+	for i := range j {
+		path := encoding.EncodeJSONObjectKeyAscending(prefix, string(j[i].k))
+		keys = j[i].v.encodeInvertedIndexKeys(path[:len(path):len(path)], keys)
+	}
+	return keys
+}
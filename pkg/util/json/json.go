@@ -0,0 +1,654 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package json implements the JSON documents stored in JSONB columns.
+package json
+
+import (
+	"bytes"
+	gojson "encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"unsafe"
+
+	"github.com/cockroachdb/apd"
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// Type represents a JSON type.
+type Type int
+
+// The values of Type are ordered the way JSON values of different types
+// compare to each other.
+const (
+	NullJSONType Type = iota
+	StringJSONType
+	NumberJSONType
+	FalseJSONType
+	TrueJSONType
+	ArrayJSONType
+	ObjectJSONType
+)
+
+// JSON is a JSON document.
+type JSON interface {
+	fmt.Stringer
+
+	// Type returns the type of the document.
+	Type() Type
+
+	// Compare compares two JSON documents. Documents of different types
+	// are ordered by type. Arrays are compared element by element, then
+	// by length; objects are compared by number of pairs, then key by key
+	// in sorted key order, then value by value.
+	Compare(JSON) int
+
+	// Format writes the document to buf.
+	Format(buf *bytes.Buffer)
+
+	// FetchValKey returns the value of the given key of an object, or nil
+	// if the document is not an object or does not have the key.
+	FetchValKey(key string) JSON
+
+	// FetchValIdx returns the element of an array at the given index, or
+	// nil if the document is not an array or the index is out of bounds.
+	// Negative indexes count from the end of the array.
+	FetchValIdx(idx int) JSON
+
+	// AsText returns the unquoted contents of a string, or the textual
+	// representation of any other document. It returns nil for a JSON
+	// null.
+	AsText() *string
+
+	// Exists returns whether the given string is a key of an object, an
+	// element of an array, or equal to a string.
+	Exists(s string) bool
+
+	// Size returns an estimate of the memory used by the document.
+	Size() uintptr
+
+	encode(appendTo []byte) []byte
+	encodeInvertedIndexKeys(prefix []byte, keys [][]byte) [][]byte
+	contains(other JSON) bool
+}
+
+type jsonNull struct{}
+type jsonTrue struct{}
+type jsonFalse struct{}
+type jsonString string
+type jsonNumber apd.Decimal
+type jsonArray []JSON
+
+type jsonKeyValuePair struct {
+	k jsonString
+	v JSON
+}
+
+// jsonObject is a JSON object. The pairs are sorted by key and keys are
+// unique.
+type jsonObject []jsonKeyValuePair
+
+var _ JSON = jsonNull{}
+var _ JSON = jsonTrue{}
+var _ JSON = jsonFalse{}
+var _ JSON = jsonString("")
+var _ JSON = &jsonNumber{}
+var _ JSON = jsonArray{}
+var _ JSON = jsonObject{}
+
+// NullJSONValue is JSON `null`.
+var NullJSONValue = JSON(jsonNull{})
+
+// TrueJSONValue is JSON `true`.
+var TrueJSONValue = JSON(jsonTrue{})
+
+// FalseJSONValue is JSON `false`.
+var FalseJSONValue = JSON(jsonFalse{})
+
+// FromString returns a JSON string.
+func FromString(s string) JSON {
+	return jsonString(s)
+}
+
+// FromDecimal returns a JSON number.
+func FromDecimal(d apd.Decimal) JSON {
+	n := jsonNumber(d)
+	return &n
+}
+
+// FromInt returns a JSON number.
+func FromInt(i int64) JSON {
+	var d apd.Decimal
+	d.SetCoefficient(i)
+	return FromDecimal(d)
+}
+
+// FromBool returns JSON `true` or `false`.
+func FromBool(b bool) JSON {
+	if b {
+		return TrueJSONValue
+	}
+	return FalseJSONValue
+}
+
+// FromArray returns a JSON array with the given elements.
+func FromArray(elems []JSON) JSON {
+	return jsonArray(elems)
+}
+
+// ObjectBuilder builds a JSON object. Keys that are added more than once
+// keep the last value.
+type ObjectBuilder struct {
+	m map[string]JSON
+}
+
+// NewObjectBuilder returns an ObjectBuilder.
+func NewObjectBuilder() *ObjectBuilder {
+	return &ObjectBuilder{m: make(map[string]JSON)}
+}
+
+// Add adds a key/value pair to the object.
+func (b *ObjectBuilder) Add(k string, v JSON) {
+	b.m[k] = v
+}
+
+// Build returns the object.
+func (b *ObjectBuilder) Build() JSON {
+	obj := make(jsonObject, 0, len(b.m))
+	for k, v := range b.m {
+		obj = append(obj, jsonKeyValuePair{k: jsonString(k), v: v})
+	}
+	sort.Slice(obj, func(i, j int) bool { return obj[i].k < obj[j].k })
+	return obj
+}
+
+// ParseJSON parses the textual representation of a JSON document.
+func ParseJSON(s string) (JSON, error) {
+	decoder := gojson.NewDecoder(bytes.NewReader([]byte(s)))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.Errorf("trailing characters after JSON document")
+	}
+	return MakeJSON(v)
+}
+
+// MakeJSON returns the JSON document for a value produced by
+// encoding/json's decoder with UseNumber set.
+func MakeJSON(d interface{}) (JSON, error) {
+	switch v := d.(type) {
+	case nil:
+		return NullJSONValue, nil
+	case bool:
+		return FromBool(v), nil
+	case string:
+		return FromString(v), nil
+	case gojson.Number:
+		var dec apd.Decimal
+		if _, _, err := dec.SetString(string(v)); err != nil {
+			return nil, err
+		}
+		return FromDecimal(dec), nil
+	case []interface{}:
+		elems := make([]JSON, len(v))
+		for i := range v {
+			var err error
+			if elems[i], err = MakeJSON(v[i]); err != nil {
+				return nil, err
+			}
+		}
+		return FromArray(elems), nil
+	case map[string]interface{}:
+		b := NewObjectBuilder()
+		for k, elem := range v {
+			j, err := MakeJSON(elem)
+			if err != nil {
+				return nil, err
+			}
+			b.Add(k, j)
+		}
+		return b.Build(), nil
+	}
+	return nil, errors.Errorf("unknown value type %T", d)
+}
+
+// Contains returns whether a contains b, following the rules of the `@>`
+// operator: an object contains another if it contains each of its pairs,
+// and an array contains another if each of its elements is contained by
+// an element of the first. As an exception, an array at the top level
+// contains a scalar if it has an element equal to it.
+func Contains(a, b JSON) bool {
+	if a.Type() == ArrayJSONType && b.Type() != ArrayJSONType && b.Type() != ObjectJSONType {
+		return a.contains(jsonArray{b})
+	}
+	return a.contains(b)
+}
+
+// EncodeInvertedIndexKeys returns the keys of the inverted index entries
+// for the given document, each prefixed with prefix. There is one key per
+// path from the root of the document to a scalar, empty array or empty
+// object; the key encodes the object keys and array steps along the path
+// followed by the value at its end.
+func EncodeInvertedIndexKeys(prefix []byte, j JSON) [][]byte {
+	keys := j.encodeInvertedIndexKeys(prefix[:len(prefix):len(prefix)], nil)
+	// Arrays can have duplicate elements, which have identical keys.
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+	out := keys[:0]
+	for i := range keys {
+		if i == 0 || !bytes.Equal(keys[i], keys[i-1]) {
+			out = append(out, keys[i])
+		}
+	}
+	return out
+}
+
+// EncodeContainedInvertedIndexKey returns an inverted index key, prefixed
+// with prefix, that every document containing j has among its inverted
+// index keys. It returns false if there is no such key, which is the case
+// when j is a scalar or only has empty arrays and objects as leaves.
+func EncodeContainedInvertedIndexKey(prefix []byte, j JSON) ([]byte, bool) {
+	switch j.Type() {
+	case ArrayJSONType, ObjectJSONType:
+		return firstScalarKey(prefix[:len(prefix):len(prefix)], j)
+	}
+	// A scalar is contained by an array that has it as an element, which
+	// does not have the same key.
+	return nil, false
+}
+
+func firstScalarKey(prefix []byte, j JSON) ([]byte, bool) {
+	switch t := j.(type) {
+	case jsonArray:
+		path := encoding.EncodeJSONArrayAscending(prefix)
+		for i := range t {
+			if key, ok := firstScalarKey(path[:len(path):len(path)], t[i]); ok {
+				return key, true
+			}
+		}
+		return nil, false
+	case jsonObject:
+		for i := range t {
+			path := encoding.EncodeJSONObjectKeyAscending(prefix, string(t[i].k))
+			if key, ok := firstScalarKey(path[:len(path):len(path)], t[i].v); ok {
+				return key, true
+			}
+		}
+		return nil, false
+	}
+	return j.encodeInvertedIndexKeys(prefix, nil)[0], true
+}
+
+// Pretty returns an indented representation of the document.
+func Pretty(j JSON) (string, error) {
+	var out bytes.Buffer
+	if err := gojson.Indent(&out, []byte(j.String()), "", "    "); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// StripNulls returns the document with all the object fields that have
+// null values removed, recursively.
+func StripNulls(j JSON) JSON {
+	switch t := j.(type) {
+	case jsonArray:
+		elems := make([]JSON, len(t))
+		for i := range t {
+			elems[i] = StripNulls(t[i])
+		}
+		return jsonArray(elems)
+	case jsonObject:
+		obj := make(jsonObject, 0, len(t))
+		for _, p := range t {
+			if p.v.Type() != NullJSONType {
+				obj = append(obj, jsonKeyValuePair{k: p.k, v: StripNulls(p.v)})
+			}
+		}
+		return obj
+	}
+	return j
+}
+
+// ArrayLength returns the number of elements of an array.
+func ArrayLength(j JSON) (int, bool) {
+	a, ok := j.(jsonArray)
+	return len(a), ok
+}
+
+// ArrayElements returns the elements of an array.
+func ArrayElements(j JSON) ([]JSON, bool) {
+	a, ok := j.(jsonArray)
+	return a, ok
+}
+
+// ObjectKeys returns the keys of an object, in sorted order.
+func ObjectKeys(j JSON) ([]string, bool) {
+	obj, ok := j.(jsonObject)
+	if !ok {
+		return nil, false
+	}
+	keys := make([]string, len(obj))
+	for i := range obj {
+		keys[i] = string(obj[i].k)
+	}
+	return keys, true
+}
+
+func (jsonNull) Type() Type    { return NullJSONType }
+func (jsonFalse) Type() Type   { return FalseJSONType }
+func (jsonTrue) Type() Type    { return TrueJSONType }
+func (jsonString) Type() Type  { return StringJSONType }
+func (*jsonNumber) Type() Type { return NumberJSONType }
+func (jsonArray) Type() Type   { return ArrayJSONType }
+func (jsonObject) Type() Type  { return ObjectJSONType }
+
+func cmpType(a, b JSON) int {
+	if a.Type() < b.Type() {
+		return -1
+	}
+	if a.Type() > b.Type() {
+		return 1
+	}
+	return 0
+}
+
+func (j jsonNull) Compare(other JSON) int  { return cmpType(j, other) }
+func (j jsonFalse) Compare(other JSON) int { return cmpType(j, other) }
+func (j jsonTrue) Compare(other JSON) int  { return cmpType(j, other) }
+
+func (j jsonString) Compare(other JSON) int {
+	if c := cmpType(j, other); c != 0 {
+		return c
+	}
+	o := other.(jsonString)
+	if j < o {
+		return -1
+	}
+	if j > o {
+		return 1
+	}
+	return 0
+}
+
+func (j *jsonNumber) Compare(other JSON) int {
+	if c := cmpType(j, other); c != 0 {
+		return c
+	}
+	a, b := apd.Decimal(*j), apd.Decimal(*other.(*jsonNumber))
+	return a.Cmp(&b)
+}
+
+func (j jsonArray) Compare(other JSON) int {
+	if c := cmpType(j, other); c != 0 {
+		return c
+	}
+	o := other.(jsonArray)
+	for i := 0; i < len(j) && i < len(o); i++ {
+		if c := j[i].Compare(o[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(j), len(o))
+}
+
+func (j jsonObject) Compare(other JSON) int {
+	if c := cmpType(j, other); c != 0 {
+		return c
+	}
+	o := other.(jsonObject)
+	if c := compareInts(len(j), len(o)); c != 0 {
+		return c
+	}
+	for i := range j {
+		if c := j[i].k.Compare(o[i].k); c != 0 {
+			return c
+		}
+	}
+	for i := range j {
+		if c := j[i].v.Compare(o[i].v); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func compareInts(a, b int) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+func (jsonNull) Format(buf *bytes.Buffer)  { buf.WriteString("null") }
+func (jsonFalse) Format(buf *bytes.Buffer) { buf.WriteString("false") }
+func (jsonTrue) Format(buf *bytes.Buffer)  { buf.WriteString("true") }
+
+func (j jsonString) Format(buf *bytes.Buffer) {
+	enc := gojson.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	// Encoding a string cannot fail.
+	_ = enc.Encode(string(j))
+	// Encode terminates the value with a newline.
+	buf.Truncate(buf.Len() - 1)
+}
+
+func (j *jsonNumber) Format(buf *bytes.Buffer) {
+	d := apd.Decimal(*j)
+	buf.WriteString(d.String())
+}
+
+func (j jsonArray) Format(buf *bytes.Buffer) {
+	buf.WriteByte('[')
+	for i := range j {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		j[i].Format(buf)
+	}
+	buf.WriteByte(']')
+}
+
+func (j jsonObject) Format(buf *bytes.Buffer) {
+	buf.WriteByte('{')
+	for i := range j {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		j[i].k.Format(buf)
+		buf.WriteString(": ")
+		j[i].v.Format(buf)
+	}
+	buf.WriteByte('}')
+}
+
+func formatToString(j JSON) string {
+	var buf bytes.Buffer
+	j.Format(&buf)
+	return buf.String()
+}
+
+func (j jsonNull) String() string    { return formatToString(j) }
+func (j jsonFalse) String() string   { return formatToString(j) }
+func (j jsonTrue) String() string    { return formatToString(j) }
+func (j jsonString) String() string  { return formatToString(j) }
+func (j *jsonNumber) String() string { return formatToString(j) }
+func (j jsonArray) String() string   { return formatToString(j) }
+func (j jsonObject) String() string  { return formatToString(j) }
+
+func (jsonNull) FetchValKey(string) JSON    { return nil }
+func (jsonFalse) FetchValKey(string) JSON   { return nil }
+func (jsonTrue) FetchValKey(string) JSON    { return nil }
+func (jsonString) FetchValKey(string) JSON  { return nil }
+func (*jsonNumber) FetchValKey(string) JSON { return nil }
+func (jsonArray) FetchValKey(string) JSON   { return nil }
+
+func (j jsonObject) FetchValKey(key string) JSON {
+	i := sort.Search(len(j), func(i int) bool { return string(j[i].k) >= key })
+	if i < len(j) && string(j[i].k) == key {
+		return j[i].v
+	}
+	return nil
+}
+
+func (jsonNull) FetchValIdx(int) JSON    { return nil }
+func (jsonFalse) FetchValIdx(int) JSON   { return nil }
+func (jsonTrue) FetchValIdx(int) JSON    { return nil }
+func (jsonString) FetchValIdx(int) JSON  { return nil }
+func (*jsonNumber) FetchValIdx(int) JSON { return nil }
+func (jsonObject) FetchValIdx(int) JSON  { return nil }
+
+func (j jsonArray) FetchValIdx(idx int) JSON {
+	if idx < 0 {
+		idx += len(j)
+	}
+	if idx < 0 || idx >= len(j) {
+		return nil
+	}
+	return j[idx]
+}
+
+func (jsonNull) AsText() *string { return nil }
+
+func (j jsonFalse) AsText() *string {
+	s := j.String()
+	return &s
+}
+
+func (j jsonTrue) AsText() *string {
+	s := j.String()
+	return &s
+}
+
+func (j jsonString) AsText() *string {
+	s := string(j)
+	return &s
+}
+
+func (j *jsonNumber) AsText() *string {
+	s := j.String()
+	return &s
+}
+
+func (j jsonArray) AsText() *string {
+	s := j.String()
+	return &s
+}
+
+func (j jsonObject) AsText() *string {
+	s := j.String()
+	return &s
+}
+
+func (jsonNull) Exists(string) bool    { return false }
+func (jsonFalse) Exists(string) bool   { return false }
+func (jsonTrue) Exists(string) bool    { return false }
+func (*jsonNumber) Exists(string) bool { return false }
+
+func (j jsonString) Exists(s string) bool {
+	return string(j) == s
+}
+
+func (j jsonArray) Exists(s string) bool {
+	for i := range j {
+		if e, ok := j[i].(jsonString); ok && string(e) == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (j jsonObject) Exists(s string) bool {
+	return j.FetchValKey(s) != nil
+}
+
+func (jsonNull) Size() uintptr  { return 0 }
+func (jsonFalse) Size() uintptr { return 0 }
+func (jsonTrue) Size() uintptr  { return 0 }
+
+func (j jsonString) Size() uintptr {
+	return unsafe.Sizeof(j) + uintptr(len(j))
+}
+
+func (j *jsonNumber) Size() uintptr {
+	intVal := j.Coeff
+	return unsafe.Sizeof(*j) + uintptr(cap(intVal.Bits()))*unsafe.Sizeof(big.Word(0))
+}
+
+func (j jsonArray) Size() uintptr {
+	valSize := uintptr(0)
+	for i := range j {
+		valSize += unsafe.Sizeof(j[i]) + j[i].Size()
+	}
+	return unsafe.Sizeof(j) + valSize
+}
+
+func (j jsonObject) Size() uintptr {
+	valSize := uintptr(0)
+	for i := range j {
+		valSize += unsafe.Sizeof(j[i]) + j[i].k.Size() + j[i].v.Size()
+	}
+	return unsafe.Sizeof(j) + valSize
+}
+
+func (j jsonNull) contains(other JSON) bool   { return j.Compare(other) == 0 }
+func (j jsonFalse) contains(other JSON) bool  { return j.Compare(other) == 0 }
+func (j jsonTrue) contains(other JSON) bool   { return j.Compare(other) == 0 }
+func (j jsonString) contains(other JSON) bool { return j.Compare(other) == 0 }
+func (j *jsonNumber) contains(other JSON) bool {
+	return j.Compare(other) == 0
+}
+
+func (j jsonArray) contains(other JSON) bool {
+	o, ok := other.(jsonArray)
+	if !ok {
+		return false
+	}
+	for i := range o {
+		found := false
+		for k := range j {
+			if j[k].contains(o[i]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (j jsonObject) contains(other JSON) bool {
+	o, ok := other.(jsonObject)
+	if !ok {
+		return false
+	}
+	for i := range o {
+		v := j.FetchValKey(string(o[i].k))
+		if v == nil || !v.contains(o[i].v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,223 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestJSONParseFormat(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		err      bool
+	}{
+		{`null`, `null`, false},
+		{`true`, `true`, false},
+		{` false `, `false`, false},
+		{`1.50`, `1.50`, false},
+		{`-1e3`, `-1E+3`, false},
+		{`"a<b"`, `"a<b"`, false},
+		{`"é\n"`, `"é\n"`, false},
+		{`[1, "a", [], {}]`, `[1, "a", [], {}]`, false},
+		{`{"b": 1, "a": 2}`, `{"a": 2, "b": 1}`, false},
+		{`{"a": 1, "a": 2}`, `{"a": 2}`, false},
+		{``, ``, true},
+		{`{`, ``, true},
+		{`1 2`, ``, true},
+		{`nul`, ``, true},
+	}
+	for _, tc := range testCases {
+		j, err := ParseJSON(tc.input)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: expected error, got %s", tc.input, j)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tc.input, err)
+			continue
+		}
+		if s := j.String(); s != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.input, tc.expected, s)
+		}
+	}
+}
+
+func TestJSONCompare(t *testing.T) {
+	// Documents in increasing order.
+	ordered := []string{
+		`null`,
+		`""`,
+		`"a"`,
+		`"b"`,
+		`-1`,
+		`1`,
+		`1.5`,
+		`false`,
+		`true`,
+		`[]`,
+		`[1]`,
+		`[1, 2]`,
+		`[2]`,
+		`{}`,
+		`{"a": 2}`,
+		`{"b": 1}`,
+		`{"a": 1, "b": 1}`,
+	}
+	for i := range ordered {
+		a, err := ParseJSON(ordered[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k := range ordered {
+			b, err := ParseJSON(ordered[k])
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := compareInts(i, k)
+			if c := a.Compare(b); c != expected {
+				t.Errorf("%s vs %s: expected %d, got %d", a, b, expected, c)
+			}
+		}
+	}
+	a, _ := ParseJSON(`1.0`)
+	b, _ := ParseJSON(`1`)
+	if c := a.Compare(b); c != 0 {
+		t.Errorf("expected 1.0 = 1, got %d", c)
+	}
+}
+
+func TestJSONContains(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected bool
+	}{
+		{`{"a": 1, "b": 2}`, `{"a": 1}`, true},
+		{`{"a": 1, "b": 2}`, `{}`, true},
+		{`{"a": 1}`, `{"a": 1, "b": 2}`, false},
+		{`{"a": {"b": [1, 2]}}`, `{"a": {"b": [2]}}`, true},
+		{`{"a": [1, 2]}`, `{"a": 1}`, false},
+		{`[1, 2, [3]]`, `[[3], 1]`, true},
+		{`[1, 2, [3]]`, `[3]`, false},
+		{`[1, 1]`, `[1, 1, 1]`, true},
+		{`["a", "b"]`, `"a"`, true},
+		{`"a"`, `["a"]`, false},
+		{`1`, `1.0`, true},
+		{`[]`, `{}`, false},
+		{`null`, `null`, true},
+	}
+	for _, tc := range testCases {
+		a, err := ParseJSON(tc.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ParseJSON(tc.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c := Contains(a, b); c != tc.expected {
+			t.Errorf("%s @> %s: expected %t, got %t", a, b, tc.expected, c)
+		}
+	}
+}
+
+func TestJSONEncodeDecode(t *testing.T) {
+	for _, s := range []string{
+		`null`, `true`, `false`, `"abc"`, `""`, `1.500`, `-3E-12`,
+		`[]`, `{}`, `[1, [2, [3]], {"a": null}]`, `{"a": {"b": "c"}, "d": [true, false]}`,
+	} {
+		j, err := ParseJSON(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoded := EncodeJSON([]byte("prefix"), j)
+		remaining, decoded, err := DecodeJSON(encoded[len("prefix"):])
+		if err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+		if len(remaining) != 0 {
+			t.Errorf("%s: %d trailing bytes", s, len(remaining))
+		}
+		if decoded.String() != j.String() {
+			t.Errorf("expected %s, got %s", j, decoded)
+		}
+	}
+}
+
+func TestJSONInvertedIndexKeys(t *testing.T) {
+	prefix := []byte("prefix")
+	keys := func(s string) [][]byte {
+		j, err := ParseJSON(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return EncodeInvertedIndexKeys(prefix, j)
+	}
+	contains := func(keys [][]byte, key []byte) bool {
+		for _, k := range keys {
+			if bytes.Equal(k, key) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if n := len(keys(`{"a": [1, 1, 2], "b": {"c": "d"}, "e": {}}`)); n != 4 {
+		t.Errorf("expected 4 keys, got %d", n)
+	}
+	if n := len(keys(`"a"`)); n != 1 {
+		t.Errorf("expected 1 key, got %d", n)
+	}
+
+	for _, tc := range []struct {
+		doc, contained string
+	}{
+		{`{"a": [1, 2], "b": {"c": "d"}}`, `{"a": [2]}`},
+		{`{"a": [1, 2], "b": {"c": "d"}}`, `{"b": {"c": "d"}}`},
+		{`[{"a": 1}, {"b": true}]`, `[{"b": true}]`},
+		{`[{"a": 1}, {"b": null}]`, `[{"b": null}]`},
+	} {
+		j, err := ParseJSON(tc.contained)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, ok := EncodeContainedInvertedIndexKey(prefix, j)
+		if !ok {
+			t.Errorf("%s: expected a key", tc.contained)
+			continue
+		}
+		if !contains(keys(tc.doc), key) {
+			t.Errorf("%s: key for %s not found", tc.doc, tc.contained)
+		}
+	}
+
+	for _, s := range []string{`1`, `"a"`, `{}`, `[]`, `{"a": [{}]}`} {
+		j, err := ParseJSON(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := EncodeContainedInvertedIndexKey(prefix, j); ok {
+			t.Errorf("%s: expected no key", s)
+		}
+	}
+
+	// Object keys and string values are distinguished.
+	if bytes.Equal(keys(`{"a": "b"}`)[0], keys(`["a", "b"]`)[0]) {
+		t.Error("expected different keys")
+	}
+}